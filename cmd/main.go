@@ -2,23 +2,53 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/iammrsea/social-app/cmd/server/graphql"
 	"github.com/iammrsea/social-app/internal"
+	contentService "github.com/iammrsea/social-app/internal/content/app"
+	interactionService "github.com/iammrsea/social-app/internal/interaction/app"
+	moderationService "github.com/iammrsea/social-app/internal/moderation/app"
+	"github.com/iammrsea/social-app/internal/shared/audit"
 	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/apikeys"
+	"github.com/iammrsea/social-app/internal/shared/auth/mfa"
+	"github.com/iammrsea/social-app/internal/shared/auth/onetime"
+	"github.com/iammrsea/social-app/internal/shared/auth/sessions"
 	"github.com/iammrsea/social-app/internal/shared/config"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
 	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/bans"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/shared/mail"
+	"github.com/iammrsea/social-app/internal/shared/outbox"
 	"github.com/iammrsea/social-app/internal/shared/storage"
 	userService "github.com/iammrsea/social-app/internal/user/app"
+	"github.com/iammrsea/social-app/internal/user/infra/accountdata"
+	"github.com/iammrsea/social-app/internal/user/infra/accountmail"
+	"github.com/iammrsea/social-app/internal/user/infra/apikeyauth"
+	"github.com/iammrsea/social-app/internal/user/infra/bancheck"
+	userEvents "github.com/iammrsea/social-app/internal/user/infra/eventbus"
+	userScheduler "github.com/iammrsea/social-app/internal/user/infra/scheduler"
+	"github.com/iammrsea/social-app/internal/user/infra/signout"
+	userRest "github.com/iammrsea/social-app/internal/user/ports/rest"
 )
 
 func main() {
-	port := config.NewEnv().Port()
+	env := config.NewEnv()
+
+	// social-app migrate manages the schema of the database and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(context.Background(), env.StorageEngine(), os.Args[2:]))
+	}
+
+	port := env.Port()
 
 	router := chi.NewRouter()
 
@@ -27,8 +57,6 @@ func main() {
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
 
-	router.Use(auth.AuthMiddleware)
-
 	// Set a timeout value on the request context (ctx), that will signal
 	// through ctx.Done() that the request has timed out and further
 	// processing should be stopped.
@@ -36,10 +64,8 @@ func main() {
 
 	ctx := context.Background()
 
-	// // Use any database of your choice
-	// mongoDB, closeConnection := db.SetupMongoDB(ctx)
-
-	storage, closeStorage, err := storage.NewStorage(ctx, config.StorageEngines.PostgreSQL)
+	// The database is picked with STORAGE_ENGINE, PostgreSQL unless told otherwise
+	storage, closeStorage, err := storage.NewStorage(ctx, env.StorageEngine())
 
 	if err != nil {
 		log.Fatalf("failed to initialize storage: %v", err)
@@ -47,18 +73,135 @@ func main() {
 
 	defer closeStorage()
 
+	// The pending migrations are applied unless MIGRATE_ON_STARTUP turns it off
+	if env.MigrateOnStartup() && storage.Migrations != nil {
+		applied, err := storage.Migrations.Up(ctx)
+		if err != nil {
+			log.Fatalf("failed to migrate the database: %v", err)
+		}
+		for _, migration := range applied {
+			log.Printf("applied migration %d %s", migration.Version, migration.Name)
+		}
+	}
+
+	// Access tokens are signed with the current key and verified against every key still in rotation
+	signingKeys, err := auth.DefaultKeySet()
+	if err != nil {
+		log.Fatalf("failed to load signing keys: %v", err)
+	}
+
 	// Repositories
 	userRepo := storage.Repos.UserRepo
 	userReadModelRepo := storage.Repos.UserReadModelRepo
+	postRepo := storage.Repos.PostRepo
+	postReadModelRepo := storage.Repos.PostReadModelRepo
+	commentRepo := storage.Repos.CommentRepo
+	commentReadModelRepo := storage.Repos.CommentReadModelRepo
+	voteRepo := storage.Repos.VoteRepo
+	voteReadModelRepo := storage.Repos.VoteReadModelRepo
+	reportRepo := storage.Repos.ReportRepo
+	reportReadModelRepo := storage.Repos.ReportReadModelRepo
+
+	// Access tokens are checked against the denylist of revoked tokens. API keys act for their owner,
+	// within the scopes of the key.
+	apiKeys := apikeys.NewManager(storage.APIKeys)
+	router.Use(auth.Middleware(storage.RevokedTokens, apikeyauth.NewAuthenticator(apiKeys, userReadModelRepo)))
+
+	// Guards. Admins and moderators can be made to log in with two-factor authentication before
+	// they get the permissions regular users lack.
+	var policyOptions []rbac.PolicyOption
+	if env.MFARequiredForPrivilegedRoles() {
+		policyOptions = append(policyOptions, rbac.RequireMFA(rbac.Admin, rbac.Moderator))
+	}
+	roleGuard := rbac.New(policyOptions...)
+	// Every decision of the guards is recorded to the audit log, allowed or denied
+	auditLog := audit.NewLog(storage.Audit)
+	guard := guards.WithAudit(guards.NewWithRoleBasedGuard(roleGuard), auditLog)
+
+	// The role-based policy is built in, or loaded from a file or the database and reloaded as it
+	// changes. Admins can define roles when it is kept in the database.
+	var policySource rbac.PolicySource
+	var policyStore rbac.PolicyStore
+	switch env.RBACPolicySource() {
+	case config.BuiltinPolicy:
+	case config.FilePolicy:
+		policySource = rbac.NewFileSource(env.RBACPolicyFile())
+	case config.DatabasePolicy:
+		policySource = storage.Policy
+		policyStore = storage.Policy
+	default:
+		log.Fatalf("unsupported rbac policy source: %s", env.RBACPolicySource())
+	}
+	if policySource != nil {
+		if err := roleGuard.Reload(ctx, policySource); errors.Is(err, rbac.ErrPolicyNotFound) {
+			log.Printf("%v, using the built-in policy", err)
+		} else if err != nil {
+			log.Fatalf("failed to load rbac policy: %v", err)
+		}
+	}
+
+	// Domain events are saved to the outbox by the repositories and published to the bus by the relay
+	bus := eventbus.New()
+	registry := outbox.NewRegistry()
+	userEvents.RegisterEvents(registry)
+
+	// Background jobs stop when the server exits
+	backgroundCtx, stopBackground := context.WithCancel(ctx)
+	defer stopBackground()
+	go outbox.NewRelay(storage.Outbox, registry, bus).Run(backgroundCtx)
+	if policySource != nil {
+		go rbac.NewPolicyReloader(roleGuard, policySource, env.RBACPolicyReloadInterval()).Run(backgroundCtx)
+	}
 
-	// Guards
-	guard := guards.New()
+	// Banned users are refused every write
+	banChecker := bans.NewCachedChecker(bancheck.NewLookup(userReadModelRepo))
+	bancheck.InvalidateOnBanChanges(bus, banChecker)
+
+	// Logins open sessions with rotating refresh tokens. Sessions end when the user's access changes.
+	sessionManager := sessions.NewManager(storage.Sessions, storage.RevokedTokens, auth.NewTokenIssuerFromEnv(), sessions.WithRefreshTTL(env.RefreshTokenTTL()))
+	signout.OnAccessChanges(bus, sessionManager)
+
+	// Emails carry single-use links, signed like access tokens, that verify the email of new users
+	// and reset passwords. Resetting a password also ends the user's sessions.
+	oneTimeTokens := onetime.NewTokens(signingKeys, storage.UsedTokens, env.AuthIssuer())
+	accountMail := accountmail.NewFromEnv(oneTimeTokens, mail.NewMailerFromEnv())
+	accountmail.OnRegistration(bus, accountMail)
+
+	// Users with two-factor authentication log in with a password, then a code
+	secondFactor := mfa.NewManager(storage.MFA, oneTimeTokens, mfa.WithLoginTTL(env.MFALoginTTL()))
+
+	roleManager := rbac.NewRoleManager(roleGuard, policyStore)
+
+	// Deleted accounts are erased once their grace period is over. Their posts, comments and reports
+	// are kept under a placeholder user; their votes are retracted.
+	accountData := accountdata.New(accountdata.Content{
+		Posts:            postRepo,
+		PostReadModels:   postReadModelRepo,
+		Comments:         commentRepo,
+		Votes:            voteRepo,
+		VoteReadModels:   voteReadModelRepo,
+		Reports:          reportRepo,
+		ReportReadModels: reportReadModelRepo,
+	})
+
+	users := userService.New(userRepo, userReadModelRepo, banChecker, sessionManager, apiKeys, accountMail, secondFactor, roleManager, accountData, env.AccountDeletionGracePeriod(), auditLog, guard)
+	go userScheduler.NewBanExpiryScheduler(users.LiftExpiredBans, env.BanExpiryInterval()).Run(backgroundCtx)
+	go userScheduler.NewAccountDeletionScheduler(users.EraseDueAccounts, env.AccountDeletionInterval()).Run(backgroundCtx)
+	content := contentService.New(postRepo, postReadModelRepo, commentRepo, commentReadModelRepo, banChecker, bus, guard, env.MaxCommentDepth())
 
 	services := &internal.Services{
-		UserService: userService.New(userRepo, userReadModelRepo, guard),
+		UserService:        users,
+		ContentService:     content,
+		InteractionService: interactionService.New(voteRepo, voteReadModelRepo, postReadModelRepo, banChecker, bus, guard),
+		ModerationService: moderationService.New(
+			reportRepo, reportReadModelRepo, postReadModelRepo, commentReadModelRepo, userReadModelRepo,
+			content.DeletePost, content.DeleteComment, users.BanUser, banChecker, bus, guard,
+		),
 	}
 
 	graphql.SetupHttGraphQLServer(router, services)
+	router.Mount("/auth", userRest.NewAuthHandler(users).Routes())
+	router.Method(http.MethodGet, "/.well-known/jwks.json", auth.JWKSHandler(signingKeys))
 
 	log.Printf("connect to http://localhost:%s/playground for GraphQL playground", port)
 	log.Fatal(http.ListenAndServe(":"+port, router))
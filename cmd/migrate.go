@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/config"
+	"github.com/iammrsea/social-app/internal/shared/storage"
+	"github.com/iammrsea/social-app/internal/shared/storage/migrate"
+)
+
+const migrateUsage = "usage: social-app migrate up | down [steps] | status"
+
+// runMigrate runs the migrate command against the database picked by STORAGE_ENGINE and returns
+// the exit code. up applies the pending migrations, down reverts the last steps applied ones, one
+// unless told otherwise, and status lists them all.
+func runMigrate(ctx context.Context, engine config.StorageEngine, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	steps := 1
+	switch {
+	case args[0] == "down" && len(args) == 2:
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		steps = n
+	case len(args) > 1:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	store, closeStorage, err := storage.NewStorage(ctx, engine)
+	if err != nil {
+		log.Printf("failed to initialize storage: %v", err)
+		return 1
+	}
+	defer closeStorage()
+	if store.Migrations == nil {
+		log.Printf("the %s storage engine has no schema to migrate", engine)
+		return 0
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := store.Migrations.Up(ctx)
+		printMigrations("applied", applied)
+		if err != nil {
+			log.Printf("failed to migrate: %v", err)
+			return 1
+		}
+	case "down":
+		reverted, err := store.Migrations.Down(ctx, steps)
+		printMigrations("reverted", reverted)
+		if err != nil {
+			log.Printf("failed to revert migrations: %v", err)
+			return 1
+		}
+	case "status":
+		statuses, err := store.Migrations.Status(ctx)
+		if err != nil {
+			log.Printf("failed to read the migration status: %v", err)
+			return 1
+		}
+		printStatus(statuses)
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
+
+func printMigrations(done string, migrations []migrate.Migration) {
+	if len(migrations) == 0 {
+		fmt.Printf("no migration %s\n", done)
+	}
+	for _, migration := range migrations {
+		fmt.Printf("%s %d %s\n", done, migration.Version, migration.Name)
+	}
+}
+
+func printStatus(statuses []migrate.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		name, appliedAt := status.Name, "pending"
+		if status.Unknown {
+			name = "(unknown to this build)"
+		}
+		if status.Applied() {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, name, appliedAt)
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,131 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.70
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/cmd/server/graphql/graph/model"
+	"github.com/iammrsea/social-app/internal/content/app/command"
+	"github.com/iammrsea/social-app/internal/content/app/query"
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/shared/pagination"
+	"github.com/lucsky/cuid"
+)
+
+// CreateComment is the resolver for the createComment field.
+func (r *mutationResolver) CreateComment(ctx context.Context, input model.CreateComment) (*domain.CommentReadModel, error) {
+	id := cuid.New()
+	cmd := command.CreateComment{
+		Id:     id,
+		PostId: input.PostID,
+		Body:   input.Body,
+	}
+	if input.ParentID != nil {
+		cmd.ParentId = *input.ParentID
+	}
+	if err := r.Services.ContentService.CommandHandler.CreateComment.Handle(ctx, cmd); err != nil {
+		return nil, err
+	}
+	return r.Services.ContentService.QueryHandler.GetCommentById.Handle(ctx, query.GetCommentById{
+		Id: id,
+	})
+}
+
+// EditComment is the resolver for the editComment field.
+func (r *mutationResolver) EditComment(ctx context.Context, input model.EditComment) (*domain.CommentReadModel, error) {
+	err := r.Services.ContentService.CommandHandler.EditComment.Handle(ctx, command.EditComment{
+		Id:   input.ID,
+		Body: input.Body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.Services.ContentService.QueryHandler.GetCommentById.Handle(ctx, query.GetCommentById{
+		Id: input.ID,
+	})
+}
+
+// DeleteComment is the resolver for the deleteComment field.
+func (r *mutationResolver) DeleteComment(ctx context.Context, id string) (bool, error) {
+	err := r.Services.ContentService.CommandHandler.DeleteComment.Handle(ctx, command.DeleteComment{
+		Id: id,
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Comments is the resolver for the comments field.
+func (r *queryResolver) Comments(ctx context.Context, postID string, first *int32, after *string, layout *query.CommentLayout) (*model.CommentConnection, error) {
+	var limit int32 = 10
+	if first != nil {
+		limit = *first
+	}
+	var afterCursor string
+
+	if after != nil {
+		decoded, err := pagination.DecodeCursor(*after)
+		if err == nil {
+			afterCursor = decoded
+		}
+	}
+
+	opts := query.GetComments{
+		GetCommentsOptions: domain.GetCommentsOptions{PostId: postID, After: afterCursor, First: limit},
+		Layout:             query.TreeLayout,
+	}
+	if layout != nil {
+		opts.Layout = *layout
+	}
+
+	result, err := r.Services.ContentService.GetComments.Handle(ctx, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Data) == 0 {
+		return &model.CommentConnection{
+			Edges:    []*model.CommentEdge{},
+			PageInfo: &pagination.PageInfo{},
+		}, nil
+	}
+
+	edges := make([]*model.CommentEdge, len(result.Data))
+
+	// Pages are made of threads, so every comment carries the cursor of the top-level comment it belongs to
+	var cursor string
+	for i, comment := range result.Data {
+		if comment.ParentId == nil {
+			cursor = pagination.EncodeCursor(comment.CreatedAt.UTC().Format(time.RFC3339Nano))
+		}
+		edges[i] = &model.CommentEdge{
+			Cursor: cursor,
+			Node:   comment,
+		}
+	}
+
+	return &model.CommentConnection{
+		Edges: edges,
+		PageInfo: &pagination.PageInfo{
+			HasNextPage:     result.PaginationInfo.HasNext,
+			HasPreviousPage: afterCursor != "",
+			StartCursor:     edges[0].Cursor,
+			EndCursor:       edges[len(edges)-1].Cursor,
+		},
+	}, nil
+}
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
@@ -3,10 +3,27 @@
 package model
 
 import (
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	domain3 "github.com/iammrsea/social-app/internal/interaction/domain"
+	domain1 "github.com/iammrsea/social-app/internal/moderation/domain"
+	"github.com/iammrsea/social-app/internal/shared/audit"
+	"github.com/iammrsea/social-app/internal/shared/auth/apikeys"
 	"github.com/iammrsea/social-app/internal/shared/pagination"
-	"github.com/iammrsea/social-app/internal/user/domain"
+	domain2 "github.com/iammrsea/social-app/internal/user/domain"
 )
 
+type AuditEntryConnection struct {
+	Edges    []*AuditEntryEdge    `json:"edges"`
+	PageInfo *pagination.PageInfo `json:"pageInfo"`
+}
+
+type AuditEntryEdge struct {
+	Node   *audit.Entry `json:"node"`
+	Cursor string       `json:"cursor"`
+}
+
 type AwardBadge struct {
 	ID    string `json:"id"`
 	Badge string `json:"badge"`
@@ -17,15 +34,136 @@ type ChangeUsername struct {
 	Username string `json:"username"`
 }
 
+type CommentConnection struct {
+	Edges    []*CommentEdge       `json:"edges"`
+	PageInfo *pagination.PageInfo `json:"pageInfo"`
+}
+
+type CommentEdge struct {
+	Node   *domain.CommentReadModel `json:"node"`
+	Cursor string                   `json:"cursor"`
+}
+
+type CompleteLogin struct {
+	MfaToken string  `json:"mfaToken"`
+	Code     string  `json:"code"`
+	Device   *string `json:"device,omitempty"`
+}
+
+type CreateAPIKey struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type CreateComment struct {
+	PostID   string  `json:"postId"`
+	ParentID *string `json:"parentId,omitempty"`
+	Body     string  `json:"body"`
+}
+
+type CreatePost struct {
+	Title  string             `json:"title"`
+	Body   string             `json:"body"`
+	Status *domain.PostStatus `json:"status,omitempty"`
+}
+
+type CreateReport struct {
+	TargetType domain1.ReportTargetType `json:"targetType"`
+	TargetID   string                   `json:"targetId"`
+	Reason     domain1.ReportReason     `json:"reason"`
+	Details    *string                  `json:"details,omitempty"`
+}
+
+type CreatedAPIKey struct {
+	APIKey *apikeys.APIKey `json:"apiKey"`
+	Key    string          `json:"key"`
+}
+
+// Creates a role or replaces its definition. Roles can be defined when the access policy is kept in
+// the database.
+type DefineRole struct {
+	Role           string   `json:"role"`
+	Inherits       []string `json:"inherits,omitempty"`
+	Permissions    []string `json:"permissions,omitempty"`
+	AllPermissions *bool    `json:"allPermissions,omitempty"`
+}
+
+type EditComment struct {
+	ID   string `json:"id"`
+	Body string `json:"body"`
+}
+
+type Login struct {
+	Email    string  `json:"email"`
+	Password string  `json:"password"`
+	Device   *string `json:"device,omitempty"`
+}
+
+type MfaSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
 type Mutation struct {
 }
 
+type PostConnection struct {
+	Edges    []*PostEdge          `json:"edges"`
+	PageInfo *pagination.PageInfo `json:"pageInfo"`
+}
+
+type PostEdge struct {
+	Node   *domain.PostReadModel `json:"node"`
+	Cursor string                `json:"cursor"`
+}
+
 type Query struct {
 }
 
+type RefreshToken struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
 type RegisterUser struct {
 	Email    string `json:"email"`
 	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type ReportConnection struct {
+	Edges    []*ReportEdge        `json:"edges"`
+	PageInfo *pagination.PageInfo `json:"pageInfo"`
+}
+
+type ReportEdge struct {
+	Node   *domain1.ReportReadModel `json:"node"`
+	Cursor string                   `json:"cursor"`
+}
+
+type ResetPassword struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"newPassword"`
+}
+
+type ResolveReport struct {
+	ID              string               `json:"id"`
+	Action          domain1.ReportAction `json:"action"`
+	Note            string               `json:"note"`
+	BanIndefinitely *bool                `json:"banIndefinitely,omitempty"`
+	BanEndDate      *time.Time           `json:"banEndDate,omitempty"`
+}
+
+type RoleAssignment struct {
+	ID   string `json:"id"`
+	Role string `json:"role"`
+}
+
+type UpdatePost struct {
+	ID      string  `json:"id"`
+	Title   *string `json:"title,omitempty"`
+	Body    *string `json:"body,omitempty"`
+	Publish *bool   `json:"publish,omitempty"`
 }
 
 type UserConnection struct {
@@ -34,12 +172,21 @@ type UserConnection struct {
 }
 
 type UserEdge struct {
-	Node   *domain.UserReadModel `json:"node"`
-	Cursor string                `json:"cursor"`
+	Node   *domain2.UserReadModel `json:"node"`
+	Cursor string                 `json:"cursor"`
+}
+
+type VoteConnection struct {
+	Edges    []*VoteEdge          `json:"edges"`
+	PageInfo *pagination.PageInfo `json:"pageInfo"`
+}
+
+type VoteEdge struct {
+	Node   *domain3.VoteReadModel `json:"node"`
+	Cursor string                 `json:"cursor"`
 }
 
 type VoteInput struct {
-	UserID string `json:"userId"`
-	PostID string `json:"postId"`
-	Type   string `json:"type"`
+	PostID string           `json:"postId"`
+	Type   domain3.VoteType `json:"type"`
 }
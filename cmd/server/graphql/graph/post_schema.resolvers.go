@@ -0,0 +1,129 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.70
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/cmd/server/graphql/graph/model"
+	"github.com/iammrsea/social-app/internal/content/app/command"
+	"github.com/iammrsea/social-app/internal/content/app/query"
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/shared/pagination"
+	"github.com/lucsky/cuid"
+)
+
+// CreatePost is the resolver for the createPost field.
+func (r *mutationResolver) CreatePost(ctx context.Context, input model.CreatePost) (*domain.PostReadModel, error) {
+	id := cuid.New()
+	cmd := command.CreatePost{
+		Id:    id,
+		Title: input.Title,
+		Body:  input.Body,
+	}
+	if input.Status != nil {
+		cmd.Status = *input.Status
+	}
+	if err := r.Services.ContentService.CommandHandler.CreatePost.Handle(ctx, cmd); err != nil {
+		return nil, err
+	}
+	return r.Services.ContentService.QueryHandler.GetPostById.Handle(ctx, query.GetPostById{
+		Id: id,
+	})
+}
+
+// UpdatePost is the resolver for the updatePost field.
+func (r *mutationResolver) UpdatePost(ctx context.Context, input model.UpdatePost) (*domain.PostReadModel, error) {
+	err := r.Services.ContentService.CommandHandler.UpdatePost.Handle(ctx, command.UpdatePost{
+		Id:      input.ID,
+		Title:   input.Title,
+		Body:    input.Body,
+		Publish: input.Publish != nil && *input.Publish,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.Services.ContentService.QueryHandler.GetPostById.Handle(ctx, query.GetPostById{
+		Id: input.ID,
+	})
+}
+
+// DeletePost is the resolver for the deletePost field.
+func (r *mutationResolver) DeletePost(ctx context.Context, id string) (bool, error) {
+	err := r.Services.ContentService.CommandHandler.DeletePost.Handle(ctx, command.DeletePost{
+		Id: id,
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetPost is the resolver for the getPost field.
+func (r *queryResolver) GetPost(ctx context.Context, id string) (*domain.PostReadModel, error) {
+	return r.Services.ContentService.QueryHandler.GetPostById.Handle(ctx, query.GetPostById{
+		Id: id,
+	})
+}
+
+// GetPosts is the resolver for the getPosts field.
+func (r *queryResolver) GetPosts(ctx context.Context, first *int32, after *string, authorID *string) (*model.PostConnection, error) {
+	var limit int32 = 10
+	if first != nil {
+		limit = *first
+	}
+	var afterCursor string
+
+	if after != nil {
+		decoded, err := pagination.DecodeCursor(*after)
+		if err == nil {
+			afterCursor = decoded
+		}
+	}
+
+	opts := query.GetPosts{After: afterCursor, First: limit}
+	if authorID != nil {
+		opts.AuthorId = *authorID
+	}
+
+	result, err := r.Services.ContentService.GetPosts.Handle(ctx, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Data) == 0 {
+		return &model.PostConnection{
+			Edges:    []*model.PostEdge{},
+			PageInfo: &pagination.PageInfo{},
+		}, nil
+	}
+
+	edges := make([]*model.PostEdge, len(result.Data))
+
+	for i, post := range result.Data {
+		cursor := post.CreatedAt.UTC().Format(time.RFC3339Nano)
+		edges[i] = &model.PostEdge{
+			Cursor: pagination.EncodeCursor(cursor),
+			Node:   post,
+		}
+	}
+
+	return &model.PostConnection{
+		Edges: edges,
+		PageInfo: &pagination.PageInfo{
+			HasNextPage:     result.PaginationInfo.HasNext,
+			HasPreviousPage: afterCursor != "",
+			StartCursor:     edges[0].Cursor,
+			EndCursor:       edges[len(edges)-1].Cursor,
+		},
+	}, nil
+}
+
+// Post returns PostResolver implementation.
+func (r *Resolver) Post() PostResolver { return &postResolver{r} }
+
+type postResolver struct{ *Resolver }
@@ -0,0 +1,120 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.70
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/cmd/server/graphql/graph/model"
+	"github.com/iammrsea/social-app/internal/moderation/app/command"
+	"github.com/iammrsea/social-app/internal/moderation/app/query"
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+	"github.com/iammrsea/social-app/internal/shared/pagination"
+	"github.com/lucsky/cuid"
+)
+
+// CreateReport is the resolver for the createReport field.
+func (r *mutationResolver) CreateReport(ctx context.Context, input model.CreateReport) (*domain.ReportReadModel, error) {
+	id := cuid.New()
+	cmd := command.CreateReport{
+		Id:         id,
+		TargetType: input.TargetType,
+		TargetId:   input.TargetID,
+		Reason:     input.Reason,
+	}
+	if input.Details != nil {
+		cmd.Details = *input.Details
+	}
+	if err := r.Services.ModerationService.CommandHandler.CreateReport.Handle(ctx, cmd); err != nil {
+		return nil, err
+	}
+	return r.Services.ModerationService.QueryHandler.GetReportById.Handle(ctx, query.GetReportById{
+		Id: id,
+	})
+}
+
+// ResolveReport is the resolver for the resolveReport field.
+func (r *mutationResolver) ResolveReport(ctx context.Context, input model.ResolveReport) (*domain.ReportReadModel, error) {
+	cmd := command.ResolveReport{
+		Id:         input.ID,
+		Action:     input.Action,
+		Note:       input.Note,
+		BanEndDate: input.BanEndDate,
+	}
+	if input.BanIndefinitely != nil {
+		cmd.BanIndefinitely = *input.BanIndefinitely
+	}
+	if err := r.Services.ModerationService.CommandHandler.ResolveReport.Handle(ctx, cmd); err != nil {
+		return nil, err
+	}
+	return r.Services.ModerationService.QueryHandler.GetReportById.Handle(ctx, query.GetReportById{
+		Id: input.ID,
+	})
+}
+
+// Reports is the resolver for the reports field.
+func (r *queryResolver) Reports(ctx context.Context, first *int32, after *string, status *domain.ReportStatus, targetType *domain.ReportTargetType) (*model.ReportConnection, error) {
+	var limit int32 = 10
+	if first != nil {
+		limit = *first
+	}
+	var afterCursor string
+
+	if after != nil {
+		decoded, err := pagination.DecodeCursor(*after)
+		if err == nil {
+			afterCursor = decoded
+		}
+	}
+
+	opts := query.GetReports{After: afterCursor, First: limit}
+	if status != nil {
+		opts.Status = *status
+	}
+	if targetType != nil {
+		opts.TargetType = *targetType
+	}
+
+	result, err := r.Services.ModerationService.GetReports.Handle(ctx, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Data) == 0 {
+		return &model.ReportConnection{
+			Edges:    []*model.ReportEdge{},
+			PageInfo: &pagination.PageInfo{},
+		}, nil
+	}
+
+	edges := make([]*model.ReportEdge, len(result.Data))
+
+	for i, report := range result.Data {
+		cursor := report.CreatedAt.UTC().Format(time.RFC3339Nano)
+		edges[i] = &model.ReportEdge{
+			Cursor: pagination.EncodeCursor(cursor),
+			Node:   report,
+		}
+	}
+
+	return &model.ReportConnection{
+		Edges: edges,
+		PageInfo: &pagination.PageInfo{
+			HasNextPage:     result.PaginationInfo.HasNext,
+			HasPreviousPage: afterCursor != "",
+			StartCursor:     edges[0].Cursor,
+			EndCursor:       edges[len(edges)-1].Cursor,
+		},
+	}, nil
+}
+
+// Report is the resolver for the report field.
+func (r *queryResolver) Report(ctx context.Context, id string) (*domain.ReportReadModel, error) {
+	return r.Services.ModerationService.QueryHandler.GetReportById.Handle(ctx, query.GetReportById{
+		Id: id,
+	})
+}
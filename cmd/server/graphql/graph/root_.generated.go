@@ -11,6 +11,9 @@ import (
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/introspection"
 	"github.com/iammrsea/social-app/cmd/server/graphql/graph/model"
+	"github.com/iammrsea/social-app/internal/content/app/query"
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+	"github.com/iammrsea/social-app/internal/shared/audit"
 	gqlparser "github.com/vektah/gqlparser/v2"
 	"github.com/vektah/gqlparser/v2/ast"
 )
@@ -33,24 +36,132 @@ type Config struct {
 }
 
 type ResolverRoot interface {
+	ApiKey() ApiKeyResolver
 	Mutation() MutationResolver
+	Post() PostResolver
 	Query() QueryResolver
+	RoleDefinition() RoleDefinitionResolver
+	User() UserResolver
 	UserReputation() UserReputationResolver
-	Vote() VoteResolver
 }
 
 type DirectiveRoot struct {
 }
 
 type ComplexityRoot struct {
+	ApiKey struct {
+		CreatedAt func(childComplexity int) int
+		ExpiresAt func(childComplexity int) int
+		Id        func(childComplexity int) int
+		Name      func(childComplexity int) int
+		Prefix    func(childComplexity int) int
+		RevokedAt func(childComplexity int) int
+		Scopes    func(childComplexity int) int
+	}
+
+	AuditEntry struct {
+		ActorId    func(childComplexity int) int
+		At         func(childComplexity int) int
+		Details    func(childComplexity int) int
+		Id         func(childComplexity int) int
+		Kind       func(childComplexity int) int
+		Outcome    func(childComplexity int) int
+		Permission func(childComplexity int) int
+		Reason     func(childComplexity int) int
+		RequestId  func(childComplexity int) int
+		Target     func(childComplexity int) int
+	}
+
+	AuditEntryConnection struct {
+		Edges    func(childComplexity int) int
+		PageInfo func(childComplexity int) int
+	}
+
+	AuditEntryEdge struct {
+		Cursor func(childComplexity int) int
+		Node   func(childComplexity int) int
+	}
+
+	AuthToken struct {
+		AccessToken           func(childComplexity int) int
+		ExpiresAt             func(childComplexity int) int
+		MFAToken              func(childComplexity int) int
+		RefreshToken          func(childComplexity int) int
+		RefreshTokenExpiresAt func(childComplexity int) int
+		TokenType             func(childComplexity int) int
+	}
+
+	Comment struct {
+		AuthorId  func(childComplexity int) int
+		Body      func(childComplexity int) int
+		CreatedAt func(childComplexity int) int
+		Depth     func(childComplexity int) int
+		Id        func(childComplexity int) int
+		IsDeleted func(childComplexity int) int
+		ParentId  func(childComplexity int) int
+		PostId    func(childComplexity int) int
+		Replies   func(childComplexity int) int
+		UpdatedAt func(childComplexity int) int
+	}
+
+	CommentConnection struct {
+		Edges    func(childComplexity int) int
+		PageInfo func(childComplexity int) int
+	}
+
+	CommentEdge struct {
+		Cursor func(childComplexity int) int
+		Node   func(childComplexity int) int
+	}
+
+	CreatedApiKey struct {
+		APIKey func(childComplexity int) int
+		Key    func(childComplexity int) int
+	}
+
+	MfaSetup struct {
+		ProvisioningURI func(childComplexity int) int
+		Secret          func(childComplexity int) int
+	}
+
 	Mutation struct {
-		AwardBadge         func(childComplexity int, input model.AwardBadge) int
-		BanUser            func(childComplexity int, id string) int
-		ChangeUsername     func(childComplexity int, input model.ChangeUsername) int
-		MakeModerator      func(childComplexity int, id string) int
-		RegisterUser       func(childComplexity int, input model.RegisterUser) int
-		RevokeAwardedBadge func(childComplexity int, input model.AwardBadge) int
-		Vote               func(childComplexity int, input *model.VoteInput) int
+		AssignRole              func(childComplexity int, input model.RoleAssignment) int
+		AwardBadge              func(childComplexity int, input model.AwardBadge) int
+		BanUser                 func(childComplexity int, id string) int
+		CancelAccountDeletion   func(childComplexity int, id string) int
+		ChangeUsername          func(childComplexity int, input model.ChangeUsername) int
+		CompleteLogin           func(childComplexity int, input model.CompleteLogin) int
+		CreateAPIKey            func(childComplexity int, input model.CreateAPIKey) int
+		CreateComment           func(childComplexity int, input model.CreateComment) int
+		CreatePost              func(childComplexity int, input model.CreatePost) int
+		CreateReport            func(childComplexity int, input model.CreateReport) int
+		DefineRole              func(childComplexity int, input model.DefineRole) int
+		DeleteComment           func(childComplexity int, id string) int
+		DeletePost              func(childComplexity int, id string) int
+		DeleteRole              func(childComplexity int, name string) int
+		DisableMfa              func(childComplexity int, code string) int
+		EditComment             func(childComplexity int, input model.EditComment) int
+		EnableMfa               func(childComplexity int, code string) int
+		FlipVote                func(childComplexity int, postID string) int
+		Login                   func(childComplexity int, input model.Login) int
+		Logout                  func(childComplexity int) int
+		LogoutAllSessions       func(childComplexity int) int
+		RefreshToken            func(childComplexity int, input model.RefreshToken) int
+		RegisterUser            func(childComplexity int, input model.RegisterUser) int
+		RemoveRole              func(childComplexity int, input model.RoleAssignment) int
+		RequestAccountDeletion  func(childComplexity int, id string) int
+		RequestDataExport       func(childComplexity int, id string) int
+		RequestPasswordReset    func(childComplexity int, email string) int
+		ResendVerificationEmail func(childComplexity int) int
+		ResetPassword           func(childComplexity int, input model.ResetPassword) int
+		ResolveReport           func(childComplexity int, input model.ResolveReport) int
+		RetractVote             func(childComplexity int, postID string) int
+		RevokeAPIKey            func(childComplexity int, id string) int
+		RevokeAwardedBadge      func(childComplexity int, input model.AwardBadge) int
+		SetupMfa                func(childComplexity int) int
+		UpdatePost              func(childComplexity int, input model.UpdatePost) int
+		VerifyEmail             func(childComplexity int, token string) int
+		Vote                    func(childComplexity int, input model.VoteInput) int
 	}
 
 	PageInfo struct {
@@ -60,22 +171,101 @@ type ComplexityRoot struct {
 		StartCursor     func(childComplexity int) int
 	}
 
+	Post struct {
+		AuthorId  func(childComplexity int) int
+		Body      func(childComplexity int) int
+		CreatedAt func(childComplexity int) int
+		Id        func(childComplexity int) int
+		Score     func(childComplexity int) int
+		Status    func(childComplexity int) int
+		Title     func(childComplexity int) int
+		UpdatedAt func(childComplexity int) int
+	}
+
+	PostConnection struct {
+		Edges    func(childComplexity int) int
+		PageInfo func(childComplexity int) int
+	}
+
+	PostEdge struct {
+		Cursor func(childComplexity int) int
+		Node   func(childComplexity int) int
+	}
+
+	PostScore struct {
+		Downvotes func(childComplexity int) int
+		PostId    func(childComplexity int) int
+		Score     func(childComplexity int) int
+		Upvotes   func(childComplexity int) int
+	}
+
 	Query struct {
+		APIKeys        func(childComplexity int) int
+		AuditLog       func(childComplexity int, first *int32, after *string, actorID *string, target *string, permission *string, kind *audit.Kind, outcome *audit.Outcome) int
+		Comments       func(childComplexity int, postID string, first *int32, after *string, layout *query.CommentLayout) int
+		GetPost        func(childComplexity int, id string) int
+		GetPostScore   func(childComplexity int, postID string) int
+		GetPosts       func(childComplexity int, first *int32, after *string, authorID *string) int
 		GetUserByEmail func(childComplexity int, email string) int
 		GetUserByID    func(childComplexity int, id string) int
 		GetUsers       func(childComplexity int, first *int32, after *string) int
-		GetVotes       func(childComplexity int) int
+		GetVotes       func(childComplexity int, first *int32, after *string, postID *string, userID *string) int
+		MfaEnabled     func(childComplexity int) int
+		Report         func(childComplexity int, id string) int
+		Reports        func(childComplexity int, first *int32, after *string, status *domain.ReportStatus, targetType *domain.ReportTargetType) int
+		Role           func(childComplexity int, name string) int
+		Roles          func(childComplexity int) int
+	}
+
+	Report struct {
+		CreatedAt      func(childComplexity int) int
+		Details        func(childComplexity int) int
+		Id             func(childComplexity int) int
+		Reason         func(childComplexity int) int
+		ReporterId     func(childComplexity int) int
+		Resolution     func(childComplexity int) int
+		Status         func(childComplexity int) int
+		TargetAuthorId func(childComplexity int) int
+		TargetId       func(childComplexity int) int
+		TargetType     func(childComplexity int) int
+		UpdatedAt      func(childComplexity int) int
+	}
+
+	ReportConnection struct {
+		Edges    func(childComplexity int) int
+		PageInfo func(childComplexity int) int
+	}
+
+	ReportEdge struct {
+		Cursor func(childComplexity int) int
+		Node   func(childComplexity int) int
+	}
+
+	ReportResolution struct {
+		Action      func(childComplexity int) int
+		ModeratorId func(childComplexity int) int
+		Note        func(childComplexity int) int
+		ResolvedAt  func(childComplexity int) int
+	}
+
+	RoleDefinition struct {
+		AllPermissions func(childComplexity int) int
+		Inherits       func(childComplexity int) int
+		Permissions    func(childComplexity int) int
+		Role           func(childComplexity int) int
 	}
 
 	User struct {
-		BanStatus  func(childComplexity int) int
-		CreatedAt  func(childComplexity int) int
-		Email      func(childComplexity int) int
-		Id         func(childComplexity int) int
-		Reputation func(childComplexity int) int
-		Role       func(childComplexity int) int
-		UpdatedAt  func(childComplexity int) int
-		Username   func(childComplexity int) int
+		BanStatus     func(childComplexity int) int
+		CreatedAt     func(childComplexity int) int
+		DeletionDueAt func(childComplexity int) int
+		Email         func(childComplexity int) int
+		EmailVerified func(childComplexity int) int
+		Id            func(childComplexity int) int
+		Reputation    func(childComplexity int) int
+		Roles         func(childComplexity int) int
+		UpdatedAt     func(childComplexity int) int
+		Username      func(childComplexity int) int
 	}
 
 	UserBanStatus struct {
@@ -103,9 +293,21 @@ type ComplexityRoot struct {
 	}
 
 	Vote struct {
-		PostID func(childComplexity int) int
-		Type   func(childComplexity int) int
-		UserID func(childComplexity int) int
+		CreatedAt func(childComplexity int) int
+		PostID    func(childComplexity int) int
+		Type      func(childComplexity int) int
+		UpdatedAt func(childComplexity int) int
+		UserID    func(childComplexity int) int
+	}
+
+	VoteConnection struct {
+		Edges    func(childComplexity int) int
+		PageInfo func(childComplexity int) int
+	}
+
+	VoteEdge struct {
+		Cursor func(childComplexity int) int
+		Node   func(childComplexity int) int
 	}
 }
 
@@ -128,6 +330,333 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 	_ = ec
 	switch typeName + "." + field {
 
+	case "ApiKey.createdAt":
+		if e.complexity.ApiKey.CreatedAt == nil {
+			break
+		}
+
+		return e.complexity.ApiKey.CreatedAt(childComplexity), true
+
+	case "ApiKey.expiresAt":
+		if e.complexity.ApiKey.ExpiresAt == nil {
+			break
+		}
+
+		return e.complexity.ApiKey.ExpiresAt(childComplexity), true
+
+	case "ApiKey.id":
+		if e.complexity.ApiKey.Id == nil {
+			break
+		}
+
+		return e.complexity.ApiKey.Id(childComplexity), true
+
+	case "ApiKey.name":
+		if e.complexity.ApiKey.Name == nil {
+			break
+		}
+
+		return e.complexity.ApiKey.Name(childComplexity), true
+
+	case "ApiKey.prefix":
+		if e.complexity.ApiKey.Prefix == nil {
+			break
+		}
+
+		return e.complexity.ApiKey.Prefix(childComplexity), true
+
+	case "ApiKey.revokedAt":
+		if e.complexity.ApiKey.RevokedAt == nil {
+			break
+		}
+
+		return e.complexity.ApiKey.RevokedAt(childComplexity), true
+
+	case "ApiKey.scopes":
+		if e.complexity.ApiKey.Scopes == nil {
+			break
+		}
+
+		return e.complexity.ApiKey.Scopes(childComplexity), true
+
+	case "AuditEntry.actorId":
+		if e.complexity.AuditEntry.ActorId == nil {
+			break
+		}
+
+		return e.complexity.AuditEntry.ActorId(childComplexity), true
+
+	case "AuditEntry.at":
+		if e.complexity.AuditEntry.At == nil {
+			break
+		}
+
+		return e.complexity.AuditEntry.At(childComplexity), true
+
+	case "AuditEntry.details":
+		if e.complexity.AuditEntry.Details == nil {
+			break
+		}
+
+		return e.complexity.AuditEntry.Details(childComplexity), true
+
+	case "AuditEntry.id":
+		if e.complexity.AuditEntry.Id == nil {
+			break
+		}
+
+		return e.complexity.AuditEntry.Id(childComplexity), true
+
+	case "AuditEntry.kind":
+		if e.complexity.AuditEntry.Kind == nil {
+			break
+		}
+
+		return e.complexity.AuditEntry.Kind(childComplexity), true
+
+	case "AuditEntry.outcome":
+		if e.complexity.AuditEntry.Outcome == nil {
+			break
+		}
+
+		return e.complexity.AuditEntry.Outcome(childComplexity), true
+
+	case "AuditEntry.permission":
+		if e.complexity.AuditEntry.Permission == nil {
+			break
+		}
+
+		return e.complexity.AuditEntry.Permission(childComplexity), true
+
+	case "AuditEntry.reason":
+		if e.complexity.AuditEntry.Reason == nil {
+			break
+		}
+
+		return e.complexity.AuditEntry.Reason(childComplexity), true
+
+	case "AuditEntry.requestId":
+		if e.complexity.AuditEntry.RequestId == nil {
+			break
+		}
+
+		return e.complexity.AuditEntry.RequestId(childComplexity), true
+
+	case "AuditEntry.target":
+		if e.complexity.AuditEntry.Target == nil {
+			break
+		}
+
+		return e.complexity.AuditEntry.Target(childComplexity), true
+
+	case "AuditEntryConnection.edges":
+		if e.complexity.AuditEntryConnection.Edges == nil {
+			break
+		}
+
+		return e.complexity.AuditEntryConnection.Edges(childComplexity), true
+
+	case "AuditEntryConnection.pageInfo":
+		if e.complexity.AuditEntryConnection.PageInfo == nil {
+			break
+		}
+
+		return e.complexity.AuditEntryConnection.PageInfo(childComplexity), true
+
+	case "AuditEntryEdge.cursor":
+		if e.complexity.AuditEntryEdge.Cursor == nil {
+			break
+		}
+
+		return e.complexity.AuditEntryEdge.Cursor(childComplexity), true
+
+	case "AuditEntryEdge.node":
+		if e.complexity.AuditEntryEdge.Node == nil {
+			break
+		}
+
+		return e.complexity.AuditEntryEdge.Node(childComplexity), true
+
+	case "AuthToken.accessToken":
+		if e.complexity.AuthToken.AccessToken == nil {
+			break
+		}
+
+		return e.complexity.AuthToken.AccessToken(childComplexity), true
+
+	case "AuthToken.expiresAt":
+		if e.complexity.AuthToken.ExpiresAt == nil {
+			break
+		}
+
+		return e.complexity.AuthToken.ExpiresAt(childComplexity), true
+
+	case "AuthToken.mfaToken":
+		if e.complexity.AuthToken.MFAToken == nil {
+			break
+		}
+
+		return e.complexity.AuthToken.MFAToken(childComplexity), true
+
+	case "AuthToken.refreshToken":
+		if e.complexity.AuthToken.RefreshToken == nil {
+			break
+		}
+
+		return e.complexity.AuthToken.RefreshToken(childComplexity), true
+
+	case "AuthToken.refreshTokenExpiresAt":
+		if e.complexity.AuthToken.RefreshTokenExpiresAt == nil {
+			break
+		}
+
+		return e.complexity.AuthToken.RefreshTokenExpiresAt(childComplexity), true
+
+	case "AuthToken.tokenType":
+		if e.complexity.AuthToken.TokenType == nil {
+			break
+		}
+
+		return e.complexity.AuthToken.TokenType(childComplexity), true
+
+	case "Comment.authorId":
+		if e.complexity.Comment.AuthorId == nil {
+			break
+		}
+
+		return e.complexity.Comment.AuthorId(childComplexity), true
+
+	case "Comment.body":
+		if e.complexity.Comment.Body == nil {
+			break
+		}
+
+		return e.complexity.Comment.Body(childComplexity), true
+
+	case "Comment.createdAt":
+		if e.complexity.Comment.CreatedAt == nil {
+			break
+		}
+
+		return e.complexity.Comment.CreatedAt(childComplexity), true
+
+	case "Comment.depth":
+		if e.complexity.Comment.Depth == nil {
+			break
+		}
+
+		return e.complexity.Comment.Depth(childComplexity), true
+
+	case "Comment.id":
+		if e.complexity.Comment.Id == nil {
+			break
+		}
+
+		return e.complexity.Comment.Id(childComplexity), true
+
+	case "Comment.isDeleted":
+		if e.complexity.Comment.IsDeleted == nil {
+			break
+		}
+
+		return e.complexity.Comment.IsDeleted(childComplexity), true
+
+	case "Comment.parentId":
+		if e.complexity.Comment.ParentId == nil {
+			break
+		}
+
+		return e.complexity.Comment.ParentId(childComplexity), true
+
+	case "Comment.postId":
+		if e.complexity.Comment.PostId == nil {
+			break
+		}
+
+		return e.complexity.Comment.PostId(childComplexity), true
+
+	case "Comment.replies":
+		if e.complexity.Comment.Replies == nil {
+			break
+		}
+
+		return e.complexity.Comment.Replies(childComplexity), true
+
+	case "Comment.updatedAt":
+		if e.complexity.Comment.UpdatedAt == nil {
+			break
+		}
+
+		return e.complexity.Comment.UpdatedAt(childComplexity), true
+
+	case "CommentConnection.edges":
+		if e.complexity.CommentConnection.Edges == nil {
+			break
+		}
+
+		return e.complexity.CommentConnection.Edges(childComplexity), true
+
+	case "CommentConnection.pageInfo":
+		if e.complexity.CommentConnection.PageInfo == nil {
+			break
+		}
+
+		return e.complexity.CommentConnection.PageInfo(childComplexity), true
+
+	case "CommentEdge.cursor":
+		if e.complexity.CommentEdge.Cursor == nil {
+			break
+		}
+
+		return e.complexity.CommentEdge.Cursor(childComplexity), true
+
+	case "CommentEdge.node":
+		if e.complexity.CommentEdge.Node == nil {
+			break
+		}
+
+		return e.complexity.CommentEdge.Node(childComplexity), true
+
+	case "CreatedApiKey.apiKey":
+		if e.complexity.CreatedApiKey.APIKey == nil {
+			break
+		}
+
+		return e.complexity.CreatedApiKey.APIKey(childComplexity), true
+
+	case "CreatedApiKey.key":
+		if e.complexity.CreatedApiKey.Key == nil {
+			break
+		}
+
+		return e.complexity.CreatedApiKey.Key(childComplexity), true
+
+	case "MfaSetup.provisioningUri":
+		if e.complexity.MfaSetup.ProvisioningURI == nil {
+			break
+		}
+
+		return e.complexity.MfaSetup.ProvisioningURI(childComplexity), true
+
+	case "MfaSetup.secret":
+		if e.complexity.MfaSetup.Secret == nil {
+			break
+		}
+
+		return e.complexity.MfaSetup.Secret(childComplexity), true
+
+	case "Mutation.assignRole":
+		if e.complexity.Mutation.AssignRole == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_assignRole_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.AssignRole(childComplexity, args["input"].(model.RoleAssignment)), true
+
 	case "Mutation.awardBadge":
 		if e.complexity.Mutation.AwardBadge == nil {
 			break
@@ -138,150 +667,873 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 			return 0, false
 		}
 
-		return e.complexity.Mutation.AwardBadge(childComplexity, args["input"].(model.AwardBadge)), true
+		return e.complexity.Mutation.AwardBadge(childComplexity, args["input"].(model.AwardBadge)), true
+
+	case "Mutation.banUser":
+		if e.complexity.Mutation.BanUser == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_banUser_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.BanUser(childComplexity, args["id"].(string)), true
+
+	case "Mutation.cancelAccountDeletion":
+		if e.complexity.Mutation.CancelAccountDeletion == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_cancelAccountDeletion_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.CancelAccountDeletion(childComplexity, args["id"].(string)), true
+
+	case "Mutation.changeUsername":
+		if e.complexity.Mutation.ChangeUsername == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_changeUsername_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.ChangeUsername(childComplexity, args["input"].(model.ChangeUsername)), true
+
+	case "Mutation.completeLogin":
+		if e.complexity.Mutation.CompleteLogin == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_completeLogin_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.CompleteLogin(childComplexity, args["input"].(model.CompleteLogin)), true
+
+	case "Mutation.createApiKey":
+		if e.complexity.Mutation.CreateAPIKey == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_createApiKey_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.CreateAPIKey(childComplexity, args["input"].(model.CreateAPIKey)), true
+
+	case "Mutation.createComment":
+		if e.complexity.Mutation.CreateComment == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_createComment_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.CreateComment(childComplexity, args["input"].(model.CreateComment)), true
+
+	case "Mutation.createPost":
+		if e.complexity.Mutation.CreatePost == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_createPost_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.CreatePost(childComplexity, args["input"].(model.CreatePost)), true
+
+	case "Mutation.createReport":
+		if e.complexity.Mutation.CreateReport == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_createReport_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.CreateReport(childComplexity, args["input"].(model.CreateReport)), true
+
+	case "Mutation.defineRole":
+		if e.complexity.Mutation.DefineRole == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_defineRole_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.DefineRole(childComplexity, args["input"].(model.DefineRole)), true
+
+	case "Mutation.deleteComment":
+		if e.complexity.Mutation.DeleteComment == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_deleteComment_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.DeleteComment(childComplexity, args["id"].(string)), true
+
+	case "Mutation.deletePost":
+		if e.complexity.Mutation.DeletePost == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_deletePost_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.DeletePost(childComplexity, args["id"].(string)), true
+
+	case "Mutation.deleteRole":
+		if e.complexity.Mutation.DeleteRole == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_deleteRole_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.DeleteRole(childComplexity, args["name"].(string)), true
+
+	case "Mutation.disableMfa":
+		if e.complexity.Mutation.DisableMfa == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_disableMfa_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.DisableMfa(childComplexity, args["code"].(string)), true
+
+	case "Mutation.editComment":
+		if e.complexity.Mutation.EditComment == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_editComment_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.EditComment(childComplexity, args["input"].(model.EditComment)), true
+
+	case "Mutation.enableMfa":
+		if e.complexity.Mutation.EnableMfa == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_enableMfa_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.EnableMfa(childComplexity, args["code"].(string)), true
+
+	case "Mutation.flipVote":
+		if e.complexity.Mutation.FlipVote == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_flipVote_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.FlipVote(childComplexity, args["postId"].(string)), true
+
+	case "Mutation.login":
+		if e.complexity.Mutation.Login == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_login_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.Login(childComplexity, args["input"].(model.Login)), true
+
+	case "Mutation.logout":
+		if e.complexity.Mutation.Logout == nil {
+			break
+		}
+
+		return e.complexity.Mutation.Logout(childComplexity), true
+
+	case "Mutation.logoutAllSessions":
+		if e.complexity.Mutation.LogoutAllSessions == nil {
+			break
+		}
+
+		return e.complexity.Mutation.LogoutAllSessions(childComplexity), true
+
+	case "Mutation.refreshToken":
+		if e.complexity.Mutation.RefreshToken == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_refreshToken_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.RefreshToken(childComplexity, args["input"].(model.RefreshToken)), true
+
+	case "Mutation.registerUser":
+		if e.complexity.Mutation.RegisterUser == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_registerUser_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.RegisterUser(childComplexity, args["input"].(model.RegisterUser)), true
+
+	case "Mutation.removeRole":
+		if e.complexity.Mutation.RemoveRole == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_removeRole_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.RemoveRole(childComplexity, args["input"].(model.RoleAssignment)), true
+
+	case "Mutation.requestAccountDeletion":
+		if e.complexity.Mutation.RequestAccountDeletion == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_requestAccountDeletion_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.RequestAccountDeletion(childComplexity, args["id"].(string)), true
+
+	case "Mutation.requestDataExport":
+		if e.complexity.Mutation.RequestDataExport == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_requestDataExport_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.RequestDataExport(childComplexity, args["id"].(string)), true
+
+	case "Mutation.requestPasswordReset":
+		if e.complexity.Mutation.RequestPasswordReset == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_requestPasswordReset_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.RequestPasswordReset(childComplexity, args["email"].(string)), true
+
+	case "Mutation.resendVerificationEmail":
+		if e.complexity.Mutation.ResendVerificationEmail == nil {
+			break
+		}
+
+		return e.complexity.Mutation.ResendVerificationEmail(childComplexity), true
+
+	case "Mutation.resetPassword":
+		if e.complexity.Mutation.ResetPassword == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_resetPassword_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.ResetPassword(childComplexity, args["input"].(model.ResetPassword)), true
+
+	case "Mutation.resolveReport":
+		if e.complexity.Mutation.ResolveReport == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_resolveReport_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.ResolveReport(childComplexity, args["input"].(model.ResolveReport)), true
+
+	case "Mutation.retractVote":
+		if e.complexity.Mutation.RetractVote == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_retractVote_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.RetractVote(childComplexity, args["postId"].(string)), true
+
+	case "Mutation.revokeApiKey":
+		if e.complexity.Mutation.RevokeAPIKey == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_revokeApiKey_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.RevokeAPIKey(childComplexity, args["id"].(string)), true
+
+	case "Mutation.revokeAwardedBadge":
+		if e.complexity.Mutation.RevokeAwardedBadge == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_revokeAwardedBadge_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.RevokeAwardedBadge(childComplexity, args["input"].(model.AwardBadge)), true
+
+	case "Mutation.setupMfa":
+		if e.complexity.Mutation.SetupMfa == nil {
+			break
+		}
+
+		return e.complexity.Mutation.SetupMfa(childComplexity), true
+
+	case "Mutation.updatePost":
+		if e.complexity.Mutation.UpdatePost == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_updatePost_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.UpdatePost(childComplexity, args["input"].(model.UpdatePost)), true
+
+	case "Mutation.verifyEmail":
+		if e.complexity.Mutation.VerifyEmail == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_verifyEmail_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.VerifyEmail(childComplexity, args["token"].(string)), true
+
+	case "Mutation.vote":
+		if e.complexity.Mutation.Vote == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_vote_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.Vote(childComplexity, args["input"].(model.VoteInput)), true
+
+	case "PageInfo.endCursor":
+		if e.complexity.PageInfo.EndCursor == nil {
+			break
+		}
+
+		return e.complexity.PageInfo.EndCursor(childComplexity), true
+
+	case "PageInfo.hasNextPage":
+		if e.complexity.PageInfo.HasNextPage == nil {
+			break
+		}
+
+		return e.complexity.PageInfo.HasNextPage(childComplexity), true
+
+	case "PageInfo.hasPreviousPage":
+		if e.complexity.PageInfo.HasPreviousPage == nil {
+			break
+		}
+
+		return e.complexity.PageInfo.HasPreviousPage(childComplexity), true
+
+	case "PageInfo.startCursor":
+		if e.complexity.PageInfo.StartCursor == nil {
+			break
+		}
+
+		return e.complexity.PageInfo.StartCursor(childComplexity), true
+
+	case "Post.authorId":
+		if e.complexity.Post.AuthorId == nil {
+			break
+		}
+
+		return e.complexity.Post.AuthorId(childComplexity), true
+
+	case "Post.body":
+		if e.complexity.Post.Body == nil {
+			break
+		}
+
+		return e.complexity.Post.Body(childComplexity), true
+
+	case "Post.createdAt":
+		if e.complexity.Post.CreatedAt == nil {
+			break
+		}
+
+		return e.complexity.Post.CreatedAt(childComplexity), true
+
+	case "Post.id":
+		if e.complexity.Post.Id == nil {
+			break
+		}
+
+		return e.complexity.Post.Id(childComplexity), true
+
+	case "Post.score":
+		if e.complexity.Post.Score == nil {
+			break
+		}
+
+		return e.complexity.Post.Score(childComplexity), true
+
+	case "Post.status":
+		if e.complexity.Post.Status == nil {
+			break
+		}
+
+		return e.complexity.Post.Status(childComplexity), true
+
+	case "Post.title":
+		if e.complexity.Post.Title == nil {
+			break
+		}
+
+		return e.complexity.Post.Title(childComplexity), true
+
+	case "Post.updatedAt":
+		if e.complexity.Post.UpdatedAt == nil {
+			break
+		}
+
+		return e.complexity.Post.UpdatedAt(childComplexity), true
+
+	case "PostConnection.edges":
+		if e.complexity.PostConnection.Edges == nil {
+			break
+		}
+
+		return e.complexity.PostConnection.Edges(childComplexity), true
+
+	case "PostConnection.pageInfo":
+		if e.complexity.PostConnection.PageInfo == nil {
+			break
+		}
+
+		return e.complexity.PostConnection.PageInfo(childComplexity), true
+
+	case "PostEdge.cursor":
+		if e.complexity.PostEdge.Cursor == nil {
+			break
+		}
+
+		return e.complexity.PostEdge.Cursor(childComplexity), true
+
+	case "PostEdge.node":
+		if e.complexity.PostEdge.Node == nil {
+			break
+		}
+
+		return e.complexity.PostEdge.Node(childComplexity), true
+
+	case "PostScore.downvotes":
+		if e.complexity.PostScore.Downvotes == nil {
+			break
+		}
+
+		return e.complexity.PostScore.Downvotes(childComplexity), true
+
+	case "PostScore.postId":
+		if e.complexity.PostScore.PostId == nil {
+			break
+		}
+
+		return e.complexity.PostScore.PostId(childComplexity), true
+
+	case "PostScore.score":
+		if e.complexity.PostScore.Score == nil {
+			break
+		}
+
+		return e.complexity.PostScore.Score(childComplexity), true
+
+	case "PostScore.upvotes":
+		if e.complexity.PostScore.Upvotes == nil {
+			break
+		}
+
+		return e.complexity.PostScore.Upvotes(childComplexity), true
+
+	case "Query.apiKeys":
+		if e.complexity.Query.APIKeys == nil {
+			break
+		}
+
+		return e.complexity.Query.APIKeys(childComplexity), true
+
+	case "Query.auditLog":
+		if e.complexity.Query.AuditLog == nil {
+			break
+		}
+
+		args, err := ec.field_Query_auditLog_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.AuditLog(childComplexity, args["first"].(*int32), args["after"].(*string), args["actorId"].(*string), args["target"].(*string), args["permission"].(*string), args["kind"].(*audit.Kind), args["outcome"].(*audit.Outcome)), true
+
+	case "Query.comments":
+		if e.complexity.Query.Comments == nil {
+			break
+		}
+
+		args, err := ec.field_Query_comments_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.Comments(childComplexity, args["postId"].(string), args["first"].(*int32), args["after"].(*string), args["layout"].(*query.CommentLayout)), true
+
+	case "Query.getPost":
+		if e.complexity.Query.GetPost == nil {
+			break
+		}
+
+		args, err := ec.field_Query_getPost_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.GetPost(childComplexity, args["id"].(string)), true
+
+	case "Query.getPostScore":
+		if e.complexity.Query.GetPostScore == nil {
+			break
+		}
+
+		args, err := ec.field_Query_getPostScore_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.GetPostScore(childComplexity, args["postId"].(string)), true
+
+	case "Query.getPosts":
+		if e.complexity.Query.GetPosts == nil {
+			break
+		}
+
+		args, err := ec.field_Query_getPosts_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.GetPosts(childComplexity, args["first"].(*int32), args["after"].(*string), args["authorId"].(*string)), true
+
+	case "Query.getUserByEmail":
+		if e.complexity.Query.GetUserByEmail == nil {
+			break
+		}
+
+		args, err := ec.field_Query_getUserByEmail_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.GetUserByEmail(childComplexity, args["email"].(string)), true
+
+	case "Query.getUserById":
+		if e.complexity.Query.GetUserByID == nil {
+			break
+		}
+
+		args, err := ec.field_Query_getUserById_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.GetUserByID(childComplexity, args["id"].(string)), true
+
+	case "Query.getUsers":
+		if e.complexity.Query.GetUsers == nil {
+			break
+		}
+
+		args, err := ec.field_Query_getUsers_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.GetUsers(childComplexity, args["first"].(*int32), args["after"].(*string)), true
+
+	case "Query.getVotes":
+		if e.complexity.Query.GetVotes == nil {
+			break
+		}
+
+		args, err := ec.field_Query_getVotes_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.GetVotes(childComplexity, args["first"].(*int32), args["after"].(*string), args["postId"].(*string), args["userId"].(*string)), true
+
+	case "Query.mfaEnabled":
+		if e.complexity.Query.MfaEnabled == nil {
+			break
+		}
+
+		return e.complexity.Query.MfaEnabled(childComplexity), true
+
+	case "Query.report":
+		if e.complexity.Query.Report == nil {
+			break
+		}
+
+		args, err := ec.field_Query_report_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.Report(childComplexity, args["id"].(string)), true
+
+	case "Query.reports":
+		if e.complexity.Query.Reports == nil {
+			break
+		}
+
+		args, err := ec.field_Query_reports_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.Reports(childComplexity, args["first"].(*int32), args["after"].(*string), args["status"].(*domain.ReportStatus), args["targetType"].(*domain.ReportTargetType)), true
+
+	case "Query.role":
+		if e.complexity.Query.Role == nil {
+			break
+		}
+
+		args, err := ec.field_Query_role_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.Role(childComplexity, args["name"].(string)), true
+
+	case "Query.roles":
+		if e.complexity.Query.Roles == nil {
+			break
+		}
+
+		return e.complexity.Query.Roles(childComplexity), true
 
-	case "Mutation.banUser":
-		if e.complexity.Mutation.BanUser == nil {
+	case "Report.createdAt":
+		if e.complexity.Report.CreatedAt == nil {
 			break
 		}
 
-		args, err := ec.field_Mutation_banUser_args(context.TODO(), rawArgs)
-		if err != nil {
-			return 0, false
+		return e.complexity.Report.CreatedAt(childComplexity), true
+
+	case "Report.details":
+		if e.complexity.Report.Details == nil {
+			break
 		}
 
-		return e.complexity.Mutation.BanUser(childComplexity, args["id"].(string)), true
+		return e.complexity.Report.Details(childComplexity), true
 
-	case "Mutation.changeUsername":
-		if e.complexity.Mutation.ChangeUsername == nil {
+	case "Report.id":
+		if e.complexity.Report.Id == nil {
 			break
 		}
 
-		args, err := ec.field_Mutation_changeUsername_args(context.TODO(), rawArgs)
-		if err != nil {
-			return 0, false
+		return e.complexity.Report.Id(childComplexity), true
+
+	case "Report.reason":
+		if e.complexity.Report.Reason == nil {
+			break
 		}
 
-		return e.complexity.Mutation.ChangeUsername(childComplexity, args["input"].(model.ChangeUsername)), true
+		return e.complexity.Report.Reason(childComplexity), true
 
-	case "Mutation.makeModerator":
-		if e.complexity.Mutation.MakeModerator == nil {
+	case "Report.reporterId":
+		if e.complexity.Report.ReporterId == nil {
 			break
 		}
 
-		args, err := ec.field_Mutation_makeModerator_args(context.TODO(), rawArgs)
-		if err != nil {
-			return 0, false
+		return e.complexity.Report.ReporterId(childComplexity), true
+
+	case "Report.resolution":
+		if e.complexity.Report.Resolution == nil {
+			break
 		}
 
-		return e.complexity.Mutation.MakeModerator(childComplexity, args["id"].(string)), true
+		return e.complexity.Report.Resolution(childComplexity), true
 
-	case "Mutation.registerUser":
-		if e.complexity.Mutation.RegisterUser == nil {
+	case "Report.status":
+		if e.complexity.Report.Status == nil {
 			break
 		}
 
-		args, err := ec.field_Mutation_registerUser_args(context.TODO(), rawArgs)
-		if err != nil {
-			return 0, false
+		return e.complexity.Report.Status(childComplexity), true
+
+	case "Report.targetAuthorId":
+		if e.complexity.Report.TargetAuthorId == nil {
+			break
 		}
 
-		return e.complexity.Mutation.RegisterUser(childComplexity, args["input"].(model.RegisterUser)), true
+		return e.complexity.Report.TargetAuthorId(childComplexity), true
 
-	case "Mutation.revokeAwardedBadge":
-		if e.complexity.Mutation.RevokeAwardedBadge == nil {
+	case "Report.targetId":
+		if e.complexity.Report.TargetId == nil {
 			break
 		}
 
-		args, err := ec.field_Mutation_revokeAwardedBadge_args(context.TODO(), rawArgs)
-		if err != nil {
-			return 0, false
+		return e.complexity.Report.TargetId(childComplexity), true
+
+	case "Report.targetType":
+		if e.complexity.Report.TargetType == nil {
+			break
 		}
 
-		return e.complexity.Mutation.RevokeAwardedBadge(childComplexity, args["input"].(model.AwardBadge)), true
+		return e.complexity.Report.TargetType(childComplexity), true
 
-	case "Mutation.vote":
-		if e.complexity.Mutation.Vote == nil {
+	case "Report.updatedAt":
+		if e.complexity.Report.UpdatedAt == nil {
 			break
 		}
 
-		args, err := ec.field_Mutation_vote_args(context.TODO(), rawArgs)
-		if err != nil {
-			return 0, false
+		return e.complexity.Report.UpdatedAt(childComplexity), true
+
+	case "ReportConnection.edges":
+		if e.complexity.ReportConnection.Edges == nil {
+			break
 		}
 
-		return e.complexity.Mutation.Vote(childComplexity, args["input"].(*model.VoteInput)), true
+		return e.complexity.ReportConnection.Edges(childComplexity), true
 
-	case "PageInfo.endCursor":
-		if e.complexity.PageInfo.EndCursor == nil {
+	case "ReportConnection.pageInfo":
+		if e.complexity.ReportConnection.PageInfo == nil {
 			break
 		}
 
-		return e.complexity.PageInfo.EndCursor(childComplexity), true
+		return e.complexity.ReportConnection.PageInfo(childComplexity), true
 
-	case "PageInfo.hasNextPage":
-		if e.complexity.PageInfo.HasNextPage == nil {
+	case "ReportEdge.cursor":
+		if e.complexity.ReportEdge.Cursor == nil {
 			break
 		}
 
-		return e.complexity.PageInfo.HasNextPage(childComplexity), true
+		return e.complexity.ReportEdge.Cursor(childComplexity), true
 
-	case "PageInfo.hasPreviousPage":
-		if e.complexity.PageInfo.HasPreviousPage == nil {
+	case "ReportEdge.node":
+		if e.complexity.ReportEdge.Node == nil {
 			break
 		}
 
-		return e.complexity.PageInfo.HasPreviousPage(childComplexity), true
+		return e.complexity.ReportEdge.Node(childComplexity), true
 
-	case "PageInfo.startCursor":
-		if e.complexity.PageInfo.StartCursor == nil {
+	case "ReportResolution.action":
+		if e.complexity.ReportResolution.Action == nil {
 			break
 		}
 
-		return e.complexity.PageInfo.StartCursor(childComplexity), true
+		return e.complexity.ReportResolution.Action(childComplexity), true
 
-	case "Query.getUserByEmail":
-		if e.complexity.Query.GetUserByEmail == nil {
+	case "ReportResolution.moderatorId":
+		if e.complexity.ReportResolution.ModeratorId == nil {
 			break
 		}
 
-		args, err := ec.field_Query_getUserByEmail_args(context.TODO(), rawArgs)
-		if err != nil {
-			return 0, false
+		return e.complexity.ReportResolution.ModeratorId(childComplexity), true
+
+	case "ReportResolution.note":
+		if e.complexity.ReportResolution.Note == nil {
+			break
 		}
 
-		return e.complexity.Query.GetUserByEmail(childComplexity, args["email"].(string)), true
+		return e.complexity.ReportResolution.Note(childComplexity), true
 
-	case "Query.getUserById":
-		if e.complexity.Query.GetUserByID == nil {
+	case "ReportResolution.resolvedAt":
+		if e.complexity.ReportResolution.ResolvedAt == nil {
 			break
 		}
 
-		args, err := ec.field_Query_getUserById_args(context.TODO(), rawArgs)
-		if err != nil {
-			return 0, false
+		return e.complexity.ReportResolution.ResolvedAt(childComplexity), true
+
+	case "RoleDefinition.allPermissions":
+		if e.complexity.RoleDefinition.AllPermissions == nil {
+			break
 		}
 
-		return e.complexity.Query.GetUserByID(childComplexity, args["id"].(string)), true
+		return e.complexity.RoleDefinition.AllPermissions(childComplexity), true
 
-	case "Query.getUsers":
-		if e.complexity.Query.GetUsers == nil {
+	case "RoleDefinition.inherits":
+		if e.complexity.RoleDefinition.Inherits == nil {
 			break
 		}
 
-		args, err := ec.field_Query_getUsers_args(context.TODO(), rawArgs)
-		if err != nil {
-			return 0, false
+		return e.complexity.RoleDefinition.Inherits(childComplexity), true
+
+	case "RoleDefinition.permissions":
+		if e.complexity.RoleDefinition.Permissions == nil {
+			break
 		}
 
-		return e.complexity.Query.GetUsers(childComplexity, args["first"].(*int32), args["after"].(*string)), true
+		return e.complexity.RoleDefinition.Permissions(childComplexity), true
 
-	case "Query.getVotes":
-		if e.complexity.Query.GetVotes == nil {
+	case "RoleDefinition.role":
+		if e.complexity.RoleDefinition.Role == nil {
 			break
 		}
 
-		return e.complexity.Query.GetVotes(childComplexity), true
+		return e.complexity.RoleDefinition.Role(childComplexity), true
 
 	case "User.banStatus":
 		if e.complexity.User.BanStatus == nil {
@@ -297,6 +1549,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.User.CreatedAt(childComplexity), true
 
+	case "User.deletionDueAt":
+		if e.complexity.User.DeletionDueAt == nil {
+			break
+		}
+
+		return e.complexity.User.DeletionDueAt(childComplexity), true
+
 	case "User.email":
 		if e.complexity.User.Email == nil {
 			break
@@ -304,6 +1563,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.User.Email(childComplexity), true
 
+	case "User.emailVerified":
+		if e.complexity.User.EmailVerified == nil {
+			break
+		}
+
+		return e.complexity.User.EmailVerified(childComplexity), true
+
 	case "User.id":
 		if e.complexity.User.Id == nil {
 			break
@@ -318,12 +1584,12 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.User.Reputation(childComplexity), true
 
-	case "User.role":
-		if e.complexity.User.Role == nil {
+	case "User.roles":
+		if e.complexity.User.Roles == nil {
 			break
 		}
 
-		return e.complexity.User.Role(childComplexity), true
+		return e.complexity.User.Roles(childComplexity), true
 
 	case "User.updatedAt":
 		if e.complexity.User.UpdatedAt == nil {
@@ -423,6 +1689,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.UserReputation.ReputationScore(childComplexity), true
 
+	case "Vote.createdAt":
+		if e.complexity.Vote.CreatedAt == nil {
+			break
+		}
+
+		return e.complexity.Vote.CreatedAt(childComplexity), true
+
 	case "Vote.postId":
 		if e.complexity.Vote.PostID == nil {
 			break
@@ -437,6 +1710,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Vote.Type(childComplexity), true
 
+	case "Vote.updatedAt":
+		if e.complexity.Vote.UpdatedAt == nil {
+			break
+		}
+
+		return e.complexity.Vote.UpdatedAt(childComplexity), true
+
 	case "Vote.userId":
 		if e.complexity.Vote.UserID == nil {
 			break
@@ -444,6 +1724,34 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Vote.UserID(childComplexity), true
 
+	case "VoteConnection.edges":
+		if e.complexity.VoteConnection.Edges == nil {
+			break
+		}
+
+		return e.complexity.VoteConnection.Edges(childComplexity), true
+
+	case "VoteConnection.pageInfo":
+		if e.complexity.VoteConnection.PageInfo == nil {
+			break
+		}
+
+		return e.complexity.VoteConnection.PageInfo(childComplexity), true
+
+	case "VoteEdge.cursor":
+		if e.complexity.VoteEdge.Cursor == nil {
+			break
+		}
+
+		return e.complexity.VoteEdge.Cursor(childComplexity), true
+
+	case "VoteEdge.node":
+		if e.complexity.VoteEdge.Node == nil {
+			break
+		}
+
+		return e.complexity.VoteEdge.Node(childComplexity), true
+
 	}
 	return 0, false
 }
@@ -454,7 +1762,20 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 	inputUnmarshalMap := graphql.BuildUnmarshalerMap(
 		ec.unmarshalInputAwardBadge,
 		ec.unmarshalInputChangeUsername,
+		ec.unmarshalInputCompleteLogin,
+		ec.unmarshalInputCreateApiKey,
+		ec.unmarshalInputCreateComment,
+		ec.unmarshalInputCreatePost,
+		ec.unmarshalInputCreateReport,
+		ec.unmarshalInputDefineRole,
+		ec.unmarshalInputEditComment,
+		ec.unmarshalInputLogin,
+		ec.unmarshalInputRefreshToken,
 		ec.unmarshalInputRegisterUser,
+		ec.unmarshalInputResetPassword,
+		ec.unmarshalInputResolveReport,
+		ec.unmarshalInputRoleAssignment,
+		ec.unmarshalInputUpdatePost,
 		ec.unmarshalInputVoteInput,
 	)
 	first := true
@@ -559,25 +1880,236 @@ var sources = []*ast.Source{
     startCursor: String
     endCursor: String
 }
+`, BuiltIn: false},
+	{Name: "../../../../internal/content/ports/graphql/comment_schema.graphql", Input: `type Comment {
+    id: String!
+    postId: String!
+    authorId: String
+    parentId: String
+    depth: Int!
+    body: String!
+    isDeleted: Boolean!
+    replies: [Comment!]!
+    createdAt: Time!
+    updatedAt: Time!
+}
+
+enum CommentLayout {
+    TREE
+    FLAT
+}
+
+type CommentEdge {
+    node: Comment!
+    cursor: String!
+}
+
+type CommentConnection {
+    edges: [CommentEdge!]!
+    pageInfo: PageInfo!
+}
+
+input CreateComment {
+    postId: String!
+    parentId: String
+    body: String!
+}
+
+input EditComment {
+    id: String!
+    body: String!
+}
+
+extend type Query {
+    comments(postId: String!, first: Int = 10, after: String, layout: CommentLayout = TREE): CommentConnection!
+}
+
+extend type Mutation {
+    createComment(input: CreateComment!): Comment
+    editComment(input: EditComment!): Comment
+    deleteComment(id: String!): Boolean!
+}
+`, BuiltIn: false},
+	{Name: "../../../../internal/content/ports/graphql/post_schema.graphql", Input: `type Post {
+    id: String!
+    authorId: String!
+    title: String!
+    body: String!
+    status: PostStatus!
+    createdAt: Time!
+    updatedAt: Time!
+}
+
+enum PostStatus {
+    DRAFT
+    PUBLISHED
+}
+
+type PostEdge {
+    node: Post!
+    cursor: String!
+}
+
+type PostConnection {
+    edges: [PostEdge!]!
+    pageInfo: PageInfo!
+}
+
+input CreatePost {
+    title: String!
+    body: String!
+    status: PostStatus = PUBLISHED
+}
+
+input UpdatePost {
+    id: String!
+    title: String
+    body: String
+    publish: Boolean = false
+}
+
+extend type Query {
+    getPost(id: String!): Post
+    getPosts(first: Int = 10, after: String, authorId: String): PostConnection!
+}
+
+extend type Mutation {
+    createPost(input: CreatePost!): Post
+    updatePost(input: UpdatePost!): Post
+    deletePost(id: String!): Boolean!
+}
 `, BuiltIn: false},
 	{Name: "../../../../internal/interaction/ports/graph/vote_schema.graphql", Input: `type Vote {
     userId: String!
     postId: String!
-    type: String!
+    type: VoteType!
+    createdAt: Time!
+    updatedAt: Time!
+}
+
+enum VoteType {
+    UPVOTE
+    DOWNVOTE
+}
+
+type VoteEdge {
+    node: Vote!
+    cursor: String!
+}
+
+type VoteConnection {
+    edges: [VoteEdge!]!
+    pageInfo: PageInfo!
+}
+
+type PostScore {
+    postId: String!
+    upvotes: Int!
+    downvotes: Int!
+    score: Int!
+}
+
+extend type Post {
+    score: PostScore!
 }
 
 extend type Query {
-    getVotes: [Vote]!
+    getVotes(first: Int = 10, after: String, postId: String, userId: String): VoteConnection!
+    getPostScore(postId: String!): PostScore!
 }
 
 input VoteInput {
-    userId: String!
     postId: String!
-    type: String!
+    type: VoteType!
+}
+
+extend type Mutation {
+    vote(input: VoteInput!): Vote
+    flipVote(postId: String!): Vote
+    retractVote(postId: String!): Boolean!
+}
+`, BuiltIn: false},
+	{Name: "../../../../internal/moderation/ports/graphql/report_schema.graphql", Input: `type Report {
+    id: String!
+    reporterId: String!
+    targetType: ReportTargetType!
+    targetId: String!
+    targetAuthorId: String!
+    reason: ReportReason!
+    details: String!
+    status: ReportStatus!
+    resolution: ReportResolution
+    createdAt: Time!
+    updatedAt: Time!
+}
+
+type ReportResolution {
+    action: ReportAction!
+    moderatorId: String!
+    note: String!
+    resolvedAt: Time!
+}
+
+enum ReportTargetType {
+    POST
+    COMMENT
+    USER
+}
+
+enum ReportReason {
+    SPAM
+    HARASSMENT
+    HATE_SPEECH
+    MISINFORMATION
+    INAPPROPRIATE_CONTENT
+    OTHER
+}
+
+enum ReportStatus {
+    PENDING
+    RESOLVED
+    DISMISSED
+}
+
+enum ReportAction {
+    DISMISS
+    REMOVE_CONTENT
+    BAN_AUTHOR
+}
+
+type ReportEdge {
+    node: Report!
+    cursor: String!
+}
+
+type ReportConnection {
+    edges: [ReportEdge!]!
+    pageInfo: PageInfo!
+}
+
+input CreateReport {
+    targetType: ReportTargetType!
+    targetId: String!
+    reason: ReportReason!
+    details: String
+}
+
+input ResolveReport {
+    id: String!
+    action: ReportAction!
+    note: String!
+    banIndefinitely: Boolean
+    banEndDate: Time
+}
+
+extend type Query {
+    reports(first: Int = 10, after: String, status: ReportStatus, targetType: ReportTargetType): ReportConnection!
+    report(id: String!): Report
 }
 
 extend type Mutation {
-    vote(input: VoteInput): Vote
+    createReport(input: CreateReport!): Report
+    resolveReport(input: ResolveReport!): Report
 }
 `, BuiltIn: false},
 	{Name: "../../../../internal/user/ports/graph/user_schema.graphql", Input: `scalar Time
@@ -586,11 +2118,15 @@ type User {
     id: String!
     username: String!
     email: String!
-    role: UserRole!
+    emailVerified: Boolean!
+    "The user has the permissions of all their roles"
+    roles: [String!]!
     reputation: UserReputation
     createdAt: Time!
     updatedAt: Time!
     banStatus: UserBanStatus!
+    "When the account will be erased, if its deletion was requested"
+    deletionDueAt: Time
 }
 
 type UserEdge {
@@ -617,12 +2153,6 @@ type UserBanStatus {
     isBanned: Boolean!
 }
 
-enum UserRole {
-    REGULAR
-    MODERATOR
-    ADMIN
-}
-
 input ChangeUsername {
     id: String!
     username: String!
@@ -631,12 +2161,142 @@ input ChangeUsername {
 input RegisterUser {
     email: String!
     username: String!
+    password: String!
+}
+
+input Login {
+    email: String!
+    password: String!
+    device: String
+}
+
+input ResetPassword {
+    token: String!
+    newPassword: String!
+}
+
+input RefreshToken {
+    refreshToken: String!
+}
+
+type AuthToken {
+    accessToken: String!
+    tokenType: String!
+    expiresAt: Time!
+    refreshToken: String!
+    refreshTokenExpiresAt: Time!
+    "Set instead of the other tokens when the login has to be completed with a two-factor code"
+    mfaToken: String
+}
+
+input CompleteLogin {
+    mfaToken: String!
+    code: String!
+    device: String
+}
+
+type MfaSetup {
+    secret: String!
+    provisioningUri: String!
+}
+
+type ApiKey {
+    id: String!
+    name: String!
+    prefix: String!
+    scopes: [String!]!
+    createdAt: Time!
+    expiresAt: Time
+    revokedAt: Time
+}
+
+"""
+A role of the access policy in force. Permissions are the effective ones, inherited ones included.
+"""
+type RoleDefinition {
+    role: String!
+    inherits: [String!]!
+    allPermissions: Boolean!
+    permissions: [String!]!
+}
+
+"""
+An authorization decision or a state-changing command recorded to the audit log. Target is what
+the request was about, such as the user being banned, and is empty when the permission isn't
+about one thing in particular.
+"""
+type AuditEntry {
+    id: String!
+    kind: AuditKind!
+    actorId: String!
+    target: String!
+    permission: String!
+    details: String!
+    outcome: AuditOutcome!
+    reason: String!
+    requestId: String!
+    at: Time!
+}
+
+enum AuditKind {
+    AUTHORIZATION
+    COMMAND
+}
+
+enum AuditOutcome {
+    ALLOWED
+    DENIED
+    SUCCEEDED
+    FAILED
+}
+
+type AuditEntryEdge {
+    node: AuditEntry!
+    cursor: String!
+}
+
+type AuditEntryConnection {
+    edges: [AuditEntryEdge!]!
+    pageInfo: PageInfo!
+}
+
+type CreatedApiKey {
+    apiKey: ApiKey!
+    key: String!
+}
+
+input CreateApiKey {
+    name: String!
+    scopes: [String!]!
+    expiresAt: Time
 }
 
 extend type Query {
     getUserById(id: String!): User
     getUsers(first: Int = 10, after: String): UserConnection!
     getUserByEmail(email: String!): User
+    apiKeys: [ApiKey!]!
+    mfaEnabled: Boolean!
+    roles: [RoleDefinition!]!
+    role(name: String!): RoleDefinition!
+    "Searches the audit log, newest entries first"
+    auditLog(first: Int = 20, after: String, actorId: String, target: String, permission: String, kind: AuditKind, outcome: AuditOutcome): AuditEntryConnection!
+}
+
+input RoleAssignment {
+    id: String!
+    role: String!
+}
+
+"""
+Creates a role or replaces its definition. Roles can be defined when the access policy is kept in
+the database.
+"""
+input DefineRole {
+    role: String!
+    inherits: [String!]
+    permissions: [String!]
+    allPermissions: Boolean
 }
 
 input AwardBadge {
@@ -646,11 +2306,35 @@ input AwardBadge {
 
 extend type Mutation {
     changeUsername(input: ChangeUsername!): User
-    makeModerator(id: String!): User
+    assignRole(input: RoleAssignment!): User
+    removeRole(input: RoleAssignment!): User
+    defineRole(input: DefineRole!): RoleDefinition!
+    deleteRole(name: String!): Boolean!
     banUser(id: String!): User
     registerUser(input: RegisterUser!): User
     awardBadge(input: AwardBadge!): User
     revokeAwardedBadge(input: AwardBadge!): User
+    login(input: Login!): AuthToken!
+    refreshToken(input: RefreshToken!): AuthToken!
+    logout: Boolean!
+    logoutAllSessions: Boolean!
+    createApiKey(input: CreateApiKey!): CreatedApiKey!
+    revokeApiKey(id: String!): Boolean!
+    verifyEmail(token: String!): Boolean!
+    resendVerificationEmail: Boolean!
+    requestPasswordReset(email: String!): Boolean!
+    resetPassword(input: ResetPassword!): Boolean!
+    completeLogin(input: CompleteLogin!): AuthToken!
+    setupMfa: MfaSetup!
+    "Returns the recovery codes, shown only once"
+    enableMfa(code: String!): [String!]!
+    disableMfa(code: String!): Boolean!
+    "Schedules the account to be erased once the grace period is over"
+    requestAccountDeletion(id: String!): User
+    "Keeps an account whose deletion was requested, until the grace period is over"
+    cancelAccountDeletion(id: String!): User
+    "Returns a JSON archive of everything tied to the user"
+    requestDataExport(id: String!): String!
 }
 `, BuiltIn: false},
 }
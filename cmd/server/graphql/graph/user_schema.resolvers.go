@@ -6,15 +6,33 @@ package graph
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/iammrsea/social-app/cmd/server/graphql/graph/model"
+	"github.com/iammrsea/social-app/internal/shared/audit"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/apikeys"
+	"github.com/iammrsea/social-app/internal/shared/auth/mfa"
+	"github.com/iammrsea/social-app/internal/shared/auth/totp"
+	"github.com/iammrsea/social-app/internal/shared/config"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
 	"github.com/iammrsea/social-app/internal/shared/pagination"
 	"github.com/iammrsea/social-app/internal/user/app/command"
 	"github.com/iammrsea/social-app/internal/user/app/query"
 	"github.com/iammrsea/social-app/internal/user/domain"
+	"github.com/lucsky/cuid"
 )
 
+// Scopes is the resolver for the scopes field.
+func (r *apiKeyResolver) Scopes(ctx context.Context, obj *apikeys.APIKey) ([]string, error) {
+	scopes := make([]string, len(obj.Scopes))
+	for i, scope := range obj.Scopes {
+		scopes[i] = string(scope)
+	}
+	return scopes, nil
+}
+
 // ChangeUsername is the resolver for the changeUsername field.
 func (r *mutationResolver) ChangeUsername(ctx context.Context, input model.ChangeUsername) (*domain.UserReadModel, error) {
 	err := r.Services.UserService.CommandHandler.ChangeUsername.Handle(ctx, command.ChangeUsername{
@@ -29,19 +47,60 @@ func (r *mutationResolver) ChangeUsername(ctx context.Context, input model.Chang
 	})
 }
 
-// MakeModerator is the resolver for the makeModerator field.
-func (r *mutationResolver) MakeModerator(ctx context.Context, id string) (*domain.UserReadModel, error) {
-	err := r.Services.UserService.CommandHandler.MakeModerator.Handle(ctx, command.MakeModerator{
-		Id: id,
+// AssignRole is the resolver for the assignRole field.
+func (r *mutationResolver) AssignRole(ctx context.Context, input model.RoleAssignment) (*domain.UserReadModel, error) {
+	err := r.Services.UserService.CommandHandler.AssignRole.Handle(ctx, command.AssignRole{
+		Id:   input.ID,
+		Role: rbac.UserRole(input.Role),
 	})
 	if err != nil {
 		return nil, err
 	}
 	return r.Services.UserService.QueryHandler.GetUserById.Handle(ctx, query.GetUserById{
-		Id: id,
+		Id: input.ID,
+	})
+}
+
+// RemoveRole is the resolver for the removeRole field.
+func (r *mutationResolver) RemoveRole(ctx context.Context, input model.RoleAssignment) (*domain.UserReadModel, error) {
+	err := r.Services.UserService.CommandHandler.RemoveRole.Handle(ctx, command.RemoveRole{
+		Id:   input.ID,
+		Role: rbac.UserRole(input.Role),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.Services.UserService.QueryHandler.GetUserById.Handle(ctx, query.GetUserById{
+		Id: input.ID,
 	})
 }
 
+// DefineRole is the resolver for the defineRole field.
+func (r *mutationResolver) DefineRole(ctx context.Context, input model.DefineRole) (*rbac.RoleDefinition, error) {
+	cmd := command.DefineRole{
+		Role:        rbac.UserRole(input.Role),
+		Inherits:    make([]rbac.UserRole, len(input.Inherits)),
+		Permissions: make([]rbac.Permission, len(input.Permissions)),
+		All:         input.AllPermissions != nil && *input.AllPermissions,
+	}
+	for i, parent := range input.Inherits {
+		cmd.Inherits[i] = rbac.UserRole(parent)
+	}
+	for i, perm := range input.Permissions {
+		cmd.Permissions[i] = rbac.Permission(perm)
+	}
+	if err := r.Services.UserService.CommandHandler.DefineRole.Handle(ctx, cmd); err != nil {
+		return nil, err
+	}
+	return r.Services.UserService.QueryHandler.GetRole.Handle(ctx, query.GetRole{Role: rbac.NewUserRole(input.Role)})
+}
+
+// DeleteRole is the resolver for the deleteRole field.
+func (r *mutationResolver) DeleteRole(ctx context.Context, name string) (bool, error) {
+	err := r.Services.UserService.CommandHandler.DeleteRole.Handle(ctx, command.DeleteRole{Role: rbac.UserRole(name)})
+	return err == nil, err
+}
+
 // BanUser is the resolver for the banUser field.
 func (r *mutationResolver) BanUser(ctx context.Context, id string) (*domain.UserReadModel, error) {
 	if err := r.Services.UserService.CommandHandler.BanUser.Handle(ctx, command.BanUser{
@@ -59,6 +118,7 @@ func (r *mutationResolver) RegisterUser(ctx context.Context, input model.Registe
 	err := r.Services.UserService.CommandHandler.RegisterUser.Handle(ctx, command.RegisterUser{
 		Email:    input.Email,
 		Username: input.Username,
+		Password: input.Password,
 	})
 	if err != nil {
 		return nil, err
@@ -96,6 +156,219 @@ func (r *mutationResolver) RevokeAwardedBadge(ctx context.Context, input model.A
 	})
 }
 
+// Login is the resolver for the login field.
+func (r *mutationResolver) Login(ctx context.Context, input model.Login) (*auth.Token, error) {
+	device := ""
+	if input.Device != nil {
+		device = *input.Device
+	}
+	return r.Services.UserService.CommandHandler.Login.Handle(ctx, command.Login{
+		Email:    input.Email,
+		Password: input.Password,
+		Device:   device,
+	})
+}
+
+// RefreshToken is the resolver for the refreshToken field.
+func (r *mutationResolver) RefreshToken(ctx context.Context, input model.RefreshToken) (*auth.Token, error) {
+	return r.Services.UserService.CommandHandler.RefreshToken.Handle(ctx, command.RefreshToken{
+		RefreshToken: input.RefreshToken,
+	})
+}
+
+// Logout is the resolver for the logout field.
+func (r *mutationResolver) Logout(ctx context.Context) (bool, error) {
+	err := r.Services.UserService.CommandHandler.Logout.Handle(ctx, command.Logout{})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// LogoutAllSessions is the resolver for the logoutAllSessions field.
+func (r *mutationResolver) LogoutAllSessions(ctx context.Context) (bool, error) {
+	err := r.Services.UserService.CommandHandler.LogoutAllSessions.Handle(ctx, command.LogoutAllSessions{})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateAPIKey is the resolver for the createApiKey field.
+func (r *mutationResolver) CreateAPIKey(ctx context.Context, input model.CreateAPIKey) (*model.CreatedAPIKey, error) {
+	key, err := apikeys.NewKey()
+	if err != nil {
+		return nil, err
+	}
+	id := cuid.New()
+	scopes := make([]rbac.Permission, len(input.Scopes))
+	for i, scope := range input.Scopes {
+		scopes[i] = rbac.Permission(scope)
+	}
+	err = r.Services.UserService.CommandHandler.CreateAPIKey.Handle(ctx, command.CreateAPIKey{
+		Id:        id,
+		Key:       key,
+		Name:      input.Name,
+		Scopes:    scopes,
+		ExpiresAt: input.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	keys, err := r.Services.UserService.QueryHandler.GetAPIKeys.Handle(ctx, query.GetAPIKeys{})
+	if err != nil {
+		return nil, err
+	}
+	for _, apiKey := range keys {
+		if apiKey.Id == id {
+			return &model.CreatedAPIKey{APIKey: &apiKey, Key: key}, nil
+		}
+	}
+	return nil, apikeys.ErrAPIKeyNotFound
+}
+
+// RevokeAPIKey is the resolver for the revokeApiKey field.
+func (r *mutationResolver) RevokeAPIKey(ctx context.Context, id string) (bool, error) {
+	err := r.Services.UserService.CommandHandler.RevokeAPIKey.Handle(ctx, command.RevokeAPIKey{Id: id})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// VerifyEmail is the resolver for the verifyEmail field.
+func (r *mutationResolver) VerifyEmail(ctx context.Context, token string) (bool, error) {
+	err := r.Services.UserService.CommandHandler.VerifyEmail.Handle(ctx, command.VerifyEmail{Token: token})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ResendVerificationEmail is the resolver for the resendVerificationEmail field.
+func (r *mutationResolver) ResendVerificationEmail(ctx context.Context) (bool, error) {
+	err := r.Services.UserService.CommandHandler.RequestEmailVerification.Handle(ctx, command.RequestEmailVerification{})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RequestPasswordReset is the resolver for the requestPasswordReset field.
+func (r *mutationResolver) RequestPasswordReset(ctx context.Context, email string) (bool, error) {
+	err := r.Services.UserService.CommandHandler.RequestPasswordReset.Handle(ctx, command.RequestPasswordReset{Email: email})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ResetPassword is the resolver for the resetPassword field.
+func (r *mutationResolver) ResetPassword(ctx context.Context, input model.ResetPassword) (bool, error) {
+	err := r.Services.UserService.CommandHandler.ResetPassword.Handle(ctx, command.ResetPassword{
+		Token:       input.Token,
+		NewPassword: input.NewPassword,
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CompleteLogin is the resolver for the completeLogin field.
+func (r *mutationResolver) CompleteLogin(ctx context.Context, input model.CompleteLogin) (*auth.Token, error) {
+	device := ""
+	if input.Device != nil {
+		device = *input.Device
+	}
+	return r.Services.UserService.CommandHandler.CompleteLogin.Handle(ctx, command.CompleteLogin{
+		MFAToken: input.MfaToken,
+		Code:     input.Code,
+		Device:   device,
+	})
+}
+
+// SetupMfa is the resolver for the setupMfa field.
+func (r *mutationResolver) SetupMfa(ctx context.Context) (*model.MfaSetup, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	err = r.Services.UserService.CommandHandler.SetupMFA.Handle(ctx, command.SetupMFA{Secret: secret})
+	if err != nil {
+		return nil, err
+	}
+	account := auth.GetUserFromCtx(ctx).Email
+	return &model.MfaSetup{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(config.NewEnv().MFAIssuer(), account, secret),
+	}, nil
+}
+
+// EnableMfa is the resolver for the enableMfa field.
+func (r *mutationResolver) EnableMfa(ctx context.Context, code string) ([]string, error) {
+	recoveryCodes, err := mfa.NewRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = r.Services.UserService.CommandHandler.EnableMFA.Handle(ctx, command.EnableMFA{
+		Code:          code,
+		RecoveryCodes: recoveryCodes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// DisableMfa is the resolver for the disableMfa field.
+func (r *mutationResolver) DisableMfa(ctx context.Context, code string) (bool, error) {
+	err := r.Services.UserService.CommandHandler.DisableMFA.Handle(ctx, command.DisableMFA{Code: code})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RequestAccountDeletion is the resolver for the requestAccountDeletion field.
+func (r *mutationResolver) RequestAccountDeletion(ctx context.Context, id string) (*domain.UserReadModel, error) {
+	if err := r.Services.UserService.CommandHandler.RequestAccountDeletion.Handle(ctx, command.RequestAccountDeletion{
+		Id: id,
+	}); err != nil {
+		return nil, err
+	}
+	return r.Services.UserService.QueryHandler.GetUserById.Handle(ctx, query.GetUserById{
+		Id: id,
+	})
+}
+
+// CancelAccountDeletion is the resolver for the cancelAccountDeletion field.
+func (r *mutationResolver) CancelAccountDeletion(ctx context.Context, id string) (*domain.UserReadModel, error) {
+	if err := r.Services.UserService.CommandHandler.CancelAccountDeletion.Handle(ctx, command.CancelAccountDeletion{
+		Id: id,
+	}); err != nil {
+		return nil, err
+	}
+	return r.Services.UserService.QueryHandler.GetUserById.Handle(ctx, query.GetUserById{
+		Id: id,
+	})
+}
+
+// RequestDataExport is the resolver for the requestDataExport field.
+func (r *mutationResolver) RequestDataExport(ctx context.Context, id string) (string, error) {
+	export, err := r.Services.UserService.QueryHandler.ExportAccountData.Handle(ctx, query.ExportAccountData{
+		Id: id,
+	})
+	if err != nil {
+		return "", err
+	}
+	archive, err := json.Marshal(export)
+	if err != nil {
+		return "", err
+	}
+	return string(archive), nil
+}
+
 // GetUserByID is the resolver for the getUserById field.
 func (r *queryResolver) GetUserByID(ctx context.Context, id string) (*domain.UserReadModel, error) {
 	return r.Services.UserService.QueryHandler.GetUserById.Handle(ctx, query.GetUserById{
@@ -161,12 +434,163 @@ func (r *queryResolver) GetUserByEmail(ctx context.Context, email string) (*doma
 	})
 }
 
+// APIKeys is the resolver for the apiKeys field.
+func (r *queryResolver) APIKeys(ctx context.Context) ([]*apikeys.APIKey, error) {
+	keys, err := r.Services.UserService.QueryHandler.GetAPIKeys.Handle(ctx, query.GetAPIKeys{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*apikeys.APIKey, len(keys))
+	for i := range keys {
+		result[i] = &keys[i]
+	}
+	return result, nil
+}
+
+// MfaEnabled is the resolver for the mfaEnabled field.
+func (r *queryResolver) MfaEnabled(ctx context.Context) (bool, error) {
+	return r.Services.UserService.QueryHandler.GetMFAStatus.Handle(ctx, query.GetMFAStatus{})
+}
+
+// Roles is the resolver for the roles field.
+func (r *queryResolver) Roles(ctx context.Context) ([]*rbac.RoleDefinition, error) {
+	roles, err := r.Services.UserService.QueryHandler.GetRoles.Handle(ctx, query.GetRoles{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*rbac.RoleDefinition, len(roles))
+	for i := range roles {
+		result[i] = &roles[i]
+	}
+	return result, nil
+}
+
+// Role is the resolver for the role field.
+func (r *queryResolver) Role(ctx context.Context, name string) (*rbac.RoleDefinition, error) {
+	return r.Services.UserService.QueryHandler.GetRole.Handle(ctx, query.GetRole{Role: rbac.UserRole(name)})
+}
+
+// AuditLog is the resolver for the auditLog field.
+func (r *queryResolver) AuditLog(ctx context.Context, first *int32, after *string, actorID *string, target *string, permission *string, kind *audit.Kind, outcome *audit.Outcome) (*model.AuditEntryConnection, error) {
+	var limit int32 = 20
+	if first != nil {
+		limit = *first
+	}
+	var afterCursor string
+
+	if after != nil {
+		decoded, err := pagination.DecodeCursor(*after)
+		if err == nil {
+			afterCursor = decoded
+		}
+	}
+
+	opts := query.GetAuditLog{First: limit, After: afterCursor}
+	if actorID != nil {
+		opts.ActorId = *actorID
+	}
+	if target != nil {
+		opts.Target = *target
+	}
+	if permission != nil {
+		opts.Permission = *permission
+	}
+	if kind != nil {
+		opts.Kind = *kind
+	}
+	if outcome != nil {
+		opts.Outcome = *outcome
+	}
+
+	result, err := r.Services.UserService.GetAuditLog.Handle(ctx, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Data) == 0 {
+		return &model.AuditEntryConnection{
+			Edges:    []*model.AuditEntryEdge{},
+			PageInfo: &pagination.PageInfo{},
+		}, nil
+	}
+
+	edges := make([]*model.AuditEntryEdge, len(result.Data))
+
+	for i := range result.Data {
+		cursor := result.Data[i].At.UTC().Format(time.RFC3339Nano)
+		edges[i] = &model.AuditEntryEdge{
+			Cursor: pagination.EncodeCursor(cursor),
+			Node:   &result.Data[i],
+		}
+	}
+
+	return &model.AuditEntryConnection{
+		Edges: edges,
+		PageInfo: &pagination.PageInfo{
+			HasNextPage:     result.PaginationInfo.HasNext,
+			HasPreviousPage: afterCursor != "",
+			StartCursor:     edges[0].Cursor,
+			EndCursor:       edges[len(edges)-1].Cursor,
+		},
+	}, nil
+}
+
+// Role is the resolver for the role field.
+func (r *roleDefinitionResolver) Role(ctx context.Context, obj *rbac.RoleDefinition) (string, error) {
+	return obj.Role.String(), nil
+}
+
+// Inherits is the resolver for the inherits field.
+func (r *roleDefinitionResolver) Inherits(ctx context.Context, obj *rbac.RoleDefinition) ([]string, error) {
+	inherits := make([]string, len(obj.Inherits))
+	for i, role := range obj.Inherits {
+		inherits[i] = role.String()
+	}
+	return inherits, nil
+}
+
+// AllPermissions is the resolver for the allPermissions field.
+func (r *roleDefinitionResolver) AllPermissions(ctx context.Context, obj *rbac.RoleDefinition) (bool, error) {
+	return obj.All, nil
+}
+
+// Permissions is the resolver for the permissions field.
+func (r *roleDefinitionResolver) Permissions(ctx context.Context, obj *rbac.RoleDefinition) ([]string, error) {
+	permissions := make([]string, len(obj.Permissions))
+	for i, perm := range obj.Permissions {
+		permissions[i] = string(perm)
+	}
+	return permissions, nil
+}
+
+// Roles is the resolver for the roles field.
+func (r *userResolver) Roles(ctx context.Context, obj *domain.UserReadModel) ([]string, error) {
+	roles := make([]string, len(obj.Roles))
+	for i, role := range obj.Roles {
+		roles[i] = role.String()
+	}
+	return roles, nil
+}
+
 // ReputationScore is the resolver for the reputationScore field.
 func (r *userReputationResolver) ReputationScore(ctx context.Context, obj *domain.UserReputation) (int32, error) {
 	return int32(obj.ReputationScore), nil
 }
 
+// ApiKey returns ApiKeyResolver implementation.
+func (r *Resolver) ApiKey() ApiKeyResolver { return &apiKeyResolver{r} }
+
+// RoleDefinition returns RoleDefinitionResolver implementation.
+func (r *Resolver) RoleDefinition() RoleDefinitionResolver { return &roleDefinitionResolver{r} }
+
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
 // UserReputation returns UserReputationResolver implementation.
 func (r *Resolver) UserReputation() UserReputationResolver { return &userReputationResolver{r} }
 
+type apiKeyResolver struct{ *Resolver }
+type roleDefinitionResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
 type userReputationResolver struct{ *Resolver }
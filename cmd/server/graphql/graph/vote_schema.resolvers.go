@@ -6,36 +6,124 @@ package graph
 
 import (
 	"context"
-	"fmt"
+	"time"
 
 	"github.com/iammrsea/social-app/cmd/server/graphql/graph/model"
+	domain1 "github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/interaction/app/command"
+	"github.com/iammrsea/social-app/internal/interaction/app/query"
 	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/pagination"
 )
 
 // Vote is the resolver for the vote field.
-func (r *mutationResolver) Vote(ctx context.Context, input *model.VoteInput) (*domain.VoteReadMoel, error) {
-	panic(fmt.Errorf("not implemented: Vote - vote"))
+func (r *mutationResolver) Vote(ctx context.Context, input model.VoteInput) (*domain.VoteReadModel, error) {
+	err := r.Services.InteractionService.CommandHandler.CastVote.Handle(ctx, command.CastVote{
+		PostId: input.PostID,
+		Type:   input.Type,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.Services.InteractionService.QueryHandler.GetVote.Handle(ctx, query.GetVote{
+		UserId: auth.GetUserFromCtx(ctx).Id,
+		PostId: input.PostID,
+	})
 }
 
-// GetVotes is the resolver for the getVotes field.
-func (r *queryResolver) GetVotes(ctx context.Context) ([]*domain.VoteReadMoel, error) {
-	panic(fmt.Errorf("not implemented: GetVotes - getVotes"))
+// FlipVote is the resolver for the flipVote field.
+func (r *mutationResolver) FlipVote(ctx context.Context, postID string) (*domain.VoteReadModel, error) {
+	err := r.Services.InteractionService.CommandHandler.FlipVote.Handle(ctx, command.FlipVote{
+		PostId: postID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.Services.InteractionService.QueryHandler.GetVote.Handle(ctx, query.GetVote{
+		UserId: auth.GetUserFromCtx(ctx).Id,
+		PostId: postID,
+	})
+}
+
+// RetractVote is the resolver for the retractVote field.
+func (r *mutationResolver) RetractVote(ctx context.Context, postID string) (bool, error) {
+	err := r.Services.InteractionService.CommandHandler.RetractVote.Handle(ctx, command.RetractVote{
+		PostId: postID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-// Type is the resolver for the type field.
-func (r *voteResolver) Type(ctx context.Context, obj *domain.VoteReadMoel) (string, error) {
-	panic(fmt.Errorf("not implemented: Type - type"))
+// Score is the resolver for the score field.
+func (r *postResolver) Score(ctx context.Context, obj *domain1.PostReadModel) (*domain.PostScore, error) {
+	return r.Services.InteractionService.QueryHandler.GetPostScore.Handle(ctx, query.GetPostScore{
+		PostId: obj.Id,
+	})
 }
 
-// Mutation returns MutationResolver implementation.
-func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+// GetVotes is the resolver for the getVotes field.
+func (r *queryResolver) GetVotes(ctx context.Context, first *int32, after *string, postID *string, userID *string) (*model.VoteConnection, error) {
+	var limit int32 = 10
+	if first != nil {
+		limit = *first
+	}
+	var afterCursor string
+
+	if after != nil {
+		decoded, err := pagination.DecodeCursor(*after)
+		if err == nil {
+			afterCursor = decoded
+		}
+	}
+
+	opts := query.GetVotes{After: afterCursor, First: limit}
+	if postID != nil {
+		opts.PostId = *postID
+	}
+	if userID != nil {
+		opts.UserId = *userID
+	}
 
-// Query returns QueryResolver implementation.
-func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+	result, err := r.Services.InteractionService.GetVotes.Handle(ctx, opts)
 
-// Vote returns VoteResolver implementation.
-func (r *Resolver) Vote() VoteResolver { return &voteResolver{r} }
+	if err != nil {
+		return nil, err
+	}
 
-type mutationResolver struct{ *Resolver }
-type queryResolver struct{ *Resolver }
-type voteResolver struct{ *Resolver }
+	if len(result.Data) == 0 {
+		return &model.VoteConnection{
+			Edges:    []*model.VoteEdge{},
+			PageInfo: &pagination.PageInfo{},
+		}, nil
+	}
+
+	edges := make([]*model.VoteEdge, len(result.Data))
+
+	for i, vote := range result.Data {
+		cursor := vote.CreatedAt.UTC().Format(time.RFC3339Nano)
+		edges[i] = &model.VoteEdge{
+			Cursor: pagination.EncodeCursor(cursor),
+			Node:   vote,
+		}
+	}
+
+	return &model.VoteConnection{
+		Edges: edges,
+		PageInfo: &pagination.PageInfo{
+			HasNextPage:     result.PaginationInfo.HasNext,
+			HasPreviousPage: afterCursor != "",
+			StartCursor:     edges[0].Cursor,
+			EndCursor:       edges[len(edges)-1].Cursor,
+		},
+	}, nil
+}
+
+// GetPostScore is the resolver for the getPostScore field.
+func (r *queryResolver) GetPostScore(ctx context.Context, postID string) (*domain.PostScore, error) {
+	return r.Services.InteractionService.QueryHandler.GetPostScore.Handle(ctx, query.GetPostScore{
+		PostId: postID,
+	})
+}
@@ -17,6 +17,7 @@ require (
 	github.com/testcontainers/testcontainers-go v0.36.0
 	github.com/vektah/gqlparser/v2 v2.5.23
 	go.mongodb.org/mongo-driver v1.17.3
+	golang.org/x/crypto v0.31.0
 	golang.org/x/text v0.23.0
 )
 
@@ -86,7 +87,6 @@ require (
 	go.opentelemetry.io/otel/sdk v1.35.0 // indirect
 	go.opentelemetry.io/otel/trace v1.35.0 // indirect
 	go.opentelemetry.io/proto/otlp v1.5.0 // indirect
-	golang.org/x/crypto v0.31.0 // indirect
 	golang.org/x/mod v0.24.0 // indirect
 	golang.org/x/sync v0.12.0 // indirect
 	golang.org/x/sys v0.31.0 // indirect
@@ -99,5 +99,5 @@ require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/testify v1.10.0
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -1 +1,29 @@
 package app
+
+import (
+	"github.com/iammrsea/social-app/internal/content/app/command"
+	"github.com/iammrsea/social-app/internal/content/app/query"
+)
+
+type Application struct {
+	CommandHandler
+	QueryHandler
+}
+
+type CommandHandler struct {
+	CreatePost command.CreatePostHandler
+	UpdatePost command.UpdatePostHandler
+	DeletePost command.DeletePostHandler
+
+	CreateComment command.CreateCommentHandler
+	EditComment   command.EditCommentHandler
+	DeleteComment command.DeleteCommentHandler
+}
+
+type QueryHandler struct {
+	GetPostById query.GetPostByIdHandler
+	GetPosts    query.GetPostsHandler
+
+	GetCommentById query.GetCommentByIdHandler
+	GetComments    query.GetCommentsHandler
+}
@@ -0,0 +1,76 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+// CreateComment adds a comment to a post. When ParentId is set the comment is a reply to that comment.
+type CreateComment struct {
+	Id       string
+	PostId   string
+	ParentId string
+	Body     string
+}
+
+type CreateCommentHandler = shared.CommandHandler[CreateComment]
+
+type createCommentHandler struct {
+	commentRepo   domain.CommentRepository
+	postQueryRepo domain.PostReadModelRepository
+	publisher     eventbus.Publisher
+	guard         guards.Guards
+	maxDepth      int
+}
+
+func NewCreateCommentHandler(commentRepo domain.CommentRepository, postQueryRepo domain.PostReadModelRepository, publisher eventbus.Publisher, guard guards.Guards, maxDepth int) CreateCommentHandler {
+	if commentRepo == nil || postQueryRepo == nil || publisher == nil || guard == nil {
+		panic("nil comment repository, post repository, event publisher or guard")
+	}
+	return &createCommentHandler{commentRepo: commentRepo, postQueryRepo: postQueryRepo, publisher: publisher, guard: guard, maxDepth: maxDepth}
+}
+
+func (c *createCommentHandler) Handle(ctx context.Context, cmd CreateComment) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := c.guard.Authorize(ctx, authUser.Subject(), rbac.CreateComment); err != nil {
+		return err
+	}
+	post, err := c.postQueryRepo.GetPostById(ctx, cmd.PostId)
+	if err != nil {
+		return err
+	}
+	// Only published posts are open for discussion
+	if post.Status != domain.Published {
+		return domain.ErrPostNotFound
+	}
+
+	var comment domain.Comment
+	if cmd.ParentId == "" {
+		comment, err = domain.NewRootComment(cmd.Id, cmd.PostId, authUser.Id, cmd.Body, time.Now())
+	} else {
+		var parent *domain.Comment
+		parent, err = c.commentRepo.GetComment(ctx, cmd.ParentId)
+		if err != nil {
+			return err
+		}
+		if parent.PostId() != cmd.PostId {
+			return domain.ErrCommentNotFound
+		}
+		comment, err = parent.Reply(cmd.Id, authUser.Id, cmd.Body, c.maxDepth, time.Now())
+	}
+	if err != nil {
+		return err
+	}
+	raised := comment.PullEvents()
+	if err := c.commentRepo.CreateComment(ctx, comment); err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, raised...)
+}
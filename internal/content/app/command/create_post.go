@@ -0,0 +1,51 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+type CreatePost struct {
+	Id     string
+	Title  string
+	Body   string
+	Status domain.PostStatus
+}
+
+type CreatePostHandler = shared.CommandHandler[CreatePost]
+
+type createPostHandler struct {
+	postRepo  domain.PostRepository
+	publisher eventbus.Publisher
+	guard     guards.Guards
+}
+
+func NewCreatePostHandler(postRepo domain.PostRepository, publisher eventbus.Publisher, guard guards.Guards) CreatePostHandler {
+	if postRepo == nil || publisher == nil || guard == nil {
+		panic("nil post repository, event publisher or guard")
+	}
+	return &createPostHandler{postRepo: postRepo, publisher: publisher, guard: guard}
+}
+
+func (c *createPostHandler) Handle(ctx context.Context, cmd CreatePost) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := c.guard.Authorize(ctx, authUser.Subject(), rbac.CreatePost); err != nil {
+		return err
+	}
+	post, err := domain.CreatePost(cmd.Id, authUser.Id, cmd.Title, cmd.Body, cmd.Status, time.Now())
+	if err != nil {
+		return err
+	}
+	raised := post.PullEvents()
+	if err := c.postRepo.CreatePost(ctx, post); err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, raised...)
+}
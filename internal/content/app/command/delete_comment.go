@@ -0,0 +1,55 @@
+package command
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/abac"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+type DeleteComment struct {
+	Id string
+}
+
+type DeleteCommentHandler = shared.CommandHandler[DeleteComment]
+
+type deleteCommentHandler struct {
+	commentRepo domain.CommentRepository
+	publisher   eventbus.Publisher
+	guard       guards.Guards
+}
+
+func NewDeleteCommentHandler(commentRepo domain.CommentRepository, publisher eventbus.Publisher, guard guards.Guards) DeleteCommentHandler {
+	if commentRepo == nil || publisher == nil || guard == nil {
+		panic("nil comment repository, event publisher or guard")
+	}
+	return &deleteCommentHandler{commentRepo: commentRepo, publisher: publisher, guard: guard}
+}
+
+func (d *deleteCommentHandler) Handle(ctx context.Context, cmd DeleteComment) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := d.guard.Authorize(ctx, authUser.Subject(), rbac.DeleteComment); err != nil {
+		return err
+	}
+	var raised []events.Event
+	err := d.commentRepo.DeleteComment(ctx, cmd.Id, func(comment *domain.Comment) error {
+		if err := d.guard.Can(ctx, abac.Delete, abac.Comment(comment.Id(), comment.AuthorId(), comment.CreatedAt())); err != nil {
+			return err
+		}
+		if err := comment.Delete(); err != nil {
+			return err
+		}
+		raised = comment.PullEvents()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return d.publisher.Publish(ctx, raised...)
+}
@@ -0,0 +1,55 @@
+package command
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/abac"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+type DeletePost struct {
+	Id string
+}
+
+type DeletePostHandler = shared.CommandHandler[DeletePost]
+
+type deletePostHandler struct {
+	postRepo  domain.PostRepository
+	publisher eventbus.Publisher
+	guard     guards.Guards
+}
+
+func NewDeletePostHandler(postRepo domain.PostRepository, publisher eventbus.Publisher, guard guards.Guards) DeletePostHandler {
+	if postRepo == nil || publisher == nil || guard == nil {
+		panic("nil post repository, event publisher or guard")
+	}
+	return &deletePostHandler{postRepo: postRepo, publisher: publisher, guard: guard}
+}
+
+func (d *deletePostHandler) Handle(ctx context.Context, cmd DeletePost) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := d.guard.Authorize(ctx, authUser.Subject(), rbac.DeletePost); err != nil {
+		return err
+	}
+	var raised []events.Event
+	err := d.postRepo.DeletePost(ctx, cmd.Id, func(post *domain.Post) error {
+		if err := d.guard.Can(ctx, abac.Delete, abac.Post(post.Id(), post.AuthorId(), post.CreatedAt())); err != nil {
+			return err
+		}
+		if err := post.Delete(); err != nil {
+			return err
+		}
+		raised = post.PullEvents()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return d.publisher.Publish(ctx, raised...)
+}
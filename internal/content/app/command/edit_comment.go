@@ -0,0 +1,56 @@
+package command
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/abac"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+type EditComment struct {
+	Id   string
+	Body string
+}
+
+type EditCommentHandler = shared.CommandHandler[EditComment]
+
+type editCommentHandler struct {
+	commentRepo domain.CommentRepository
+	publisher   eventbus.Publisher
+	guard       guards.Guards
+}
+
+func NewEditCommentHandler(commentRepo domain.CommentRepository, publisher eventbus.Publisher, guard guards.Guards) EditCommentHandler {
+	if commentRepo == nil || publisher == nil || guard == nil {
+		panic("nil comment repository, event publisher or guard")
+	}
+	return &editCommentHandler{commentRepo: commentRepo, publisher: publisher, guard: guard}
+}
+
+func (e *editCommentHandler) Handle(ctx context.Context, cmd EditComment) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := e.guard.Authorize(ctx, authUser.Subject(), rbac.UpdateComment); err != nil {
+		return err
+	}
+	var raised []events.Event
+	err := e.commentRepo.EditComment(ctx, cmd.Id, func(comment *domain.Comment) error {
+		if err := e.guard.Can(ctx, abac.Edit, abac.Comment(comment.Id(), comment.AuthorId(), comment.CreatedAt())); err != nil {
+			return err
+		}
+		if err := comment.Edit(cmd.Body); err != nil {
+			return err
+		}
+		raised = comment.PullEvents()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return e.publisher.Publish(ctx, raised...)
+}
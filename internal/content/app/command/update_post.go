@@ -0,0 +1,71 @@
+package command
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/abac"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+// UpdatePost changes the title and/or body of a post. Nil fields are left untouched.
+type UpdatePost struct {
+	Id      string
+	Title   *string
+	Body    *string
+	Publish bool
+}
+
+type UpdatePostHandler = shared.CommandHandler[UpdatePost]
+
+type updatePostHandler struct {
+	postRepo  domain.PostRepository
+	publisher eventbus.Publisher
+	guard     guards.Guards
+}
+
+func NewUpdatePostHandler(postRepo domain.PostRepository, publisher eventbus.Publisher, guard guards.Guards) UpdatePostHandler {
+	if postRepo == nil || publisher == nil || guard == nil {
+		panic("nil post repository, event publisher or guard")
+	}
+	return &updatePostHandler{postRepo: postRepo, publisher: publisher, guard: guard}
+}
+
+func (u *updatePostHandler) Handle(ctx context.Context, cmd UpdatePost) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := u.guard.Authorize(ctx, authUser.Subject(), rbac.UpdatePost); err != nil {
+		return err
+	}
+	var raised []events.Event
+	err := u.postRepo.UpdatePost(ctx, cmd.Id, func(post *domain.Post) error {
+		if err := u.guard.Can(ctx, abac.Edit, abac.Post(post.Id(), post.AuthorId(), post.CreatedAt())); err != nil {
+			return err
+		}
+		title, body := post.Title(), post.Body()
+		if cmd.Title != nil {
+			title = *cmd.Title
+		}
+		if cmd.Body != nil {
+			body = *cmd.Body
+		}
+		if err := post.Edit(title, body); err != nil {
+			return err
+		}
+		if cmd.Publish && post.Status() != domain.Published {
+			if err := post.Publish(); err != nil {
+				return err
+			}
+		}
+		raised = post.PullEvents()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return u.publisher.Publish(ctx, raised...)
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"github.com/iammrsea/social-app/internal/content/app/command"
+	"github.com/iammrsea/social-app/internal/content/app/query"
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/bans"
+)
+
+// Constructor of the content application layer. Banned users can't run any of the commands.
+func New(
+	postRepo domain.PostRepository,
+	postReadModelRepo domain.PostReadModelRepository,
+	commentRepo domain.CommentRepository,
+	commentReadModelRepo domain.CommentReadModelRepository,
+	banChecker bans.Checker,
+	publisher eventbus.Publisher,
+	guard guards.Guards,
+	maxCommentDepth int,
+) *Application {
+	return &Application{
+		CommandHandler: CommandHandler{
+			CreatePost: bans.Enforce(command.NewCreatePostHandler(postRepo, publisher, guard), banChecker),
+			UpdatePost: bans.Enforce(command.NewUpdatePostHandler(postRepo, publisher, guard), banChecker),
+			DeletePost: bans.Enforce(command.NewDeletePostHandler(postRepo, publisher, guard), banChecker),
+
+			CreateComment: bans.Enforce(command.NewCreateCommentHandler(commentRepo, postReadModelRepo, publisher, guard, maxCommentDepth), banChecker),
+			EditComment:   bans.Enforce(command.NewEditCommentHandler(commentRepo, publisher, guard), banChecker),
+			DeleteComment: bans.Enforce(command.NewDeleteCommentHandler(commentRepo, publisher, guard), banChecker),
+		},
+		QueryHandler: QueryHandler{
+			GetPostById: query.NewGetPostByIdHandler(postReadModelRepo, guard),
+			GetPosts:    query.NewGetPostsHandler(postReadModelRepo, guard),
+
+			GetCommentById: query.NewGetCommentByIdHandler(commentReadModelRepo, guard),
+			GetComments:    query.NewGetCommentsHandler(commentReadModelRepo, postReadModelRepo, guard),
+		},
+	}
+}
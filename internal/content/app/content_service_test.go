@@ -0,0 +1,770 @@
+package app_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	service "github.com/iammrsea/social-app/internal/content/app"
+	"github.com/iammrsea/social-app/internal/content/app/command"
+	"github.com/iammrsea/social-app/internal/content/app/query"
+	"github.com/iammrsea/social-app/internal/content/domain"
+	domain_mocks "github.com/iammrsea/social-app/internal/content/domain/mocks"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/iammrsea/social-app/internal/shared/guards/abac"
+	"github.com/iammrsea/social-app/internal/shared/guards/bans"
+	guard_mocks "github.com/iammrsea/social-app/internal/shared/guards/mocks"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// notBanned lets every user through the ban check
+var notBanned = bans.CheckerFunc(func(ctx context.Context, userId string) error { return nil })
+
+// publisherStub records the events published by the command handlers
+type publisherStub struct {
+	published []string
+}
+
+func (p *publisherStub) Publish(ctx context.Context, evts ...events.Event) error {
+	for _, event := range evts {
+		p.published = append(p.published, event.EventName())
+	}
+	return nil
+}
+
+const maxCommentDepth = 2
+
+type commandTestCase[T any] struct {
+	name        string
+	command     T
+	expectedErr error
+	authUser    *auth.AuthenticatedUser
+	setupMocks  func(t *testing.T, m *repoMocks, command *T, authUser *auth.AuthenticatedUser)
+	// Names of the events the command is expected to publish
+	expectedEvents []string
+}
+
+type repoMocks struct {
+	postRepo             *domain_mocks.MockPostRepository
+	postReadModelRepo    *domain_mocks.MockPostReadModelRepository
+	commentRepo          *domain_mocks.MockCommentRepository
+	commentReadModelRepo *domain_mocks.MockCommentReadModelRepository
+	publisher            *publisherStub
+	guards               *guard_mocks.MockGuards
+}
+
+type queryResult[D any] struct {
+	data *D
+	err  error
+}
+
+type queryTestCase[T any, D any] struct {
+	name           string
+	query          T
+	expectedResult queryResult[D]
+	authUser       *auth.AuthenticatedUser
+	setupMocks     func(t *testing.T, m *repoMocks, query T, authUser *auth.AuthenticatedUser)
+}
+
+func TestCommandHandler(t *testing.T) {
+	t.Parallel()
+	t.Run("CreatePost", func(t *testing.T) {
+		t.Parallel()
+		testCreatePost(t)
+	})
+	t.Run("UpdatePost", func(t *testing.T) {
+		t.Parallel()
+		testUpdatePost(t)
+	})
+	t.Run("DeletePost", func(t *testing.T) {
+		t.Parallel()
+		testDeletePost(t)
+	})
+	t.Run("CreateComment", func(t *testing.T) {
+		t.Parallel()
+		testCreateComment(t)
+	})
+	t.Run("EditComment", func(t *testing.T) {
+		t.Parallel()
+		testEditComment(t)
+	})
+	t.Run("DeleteComment", func(t *testing.T) {
+		t.Parallel()
+		testDeleteComment(t)
+	})
+}
+
+func TestQueryHandler(t *testing.T) {
+	t.Run("GetPosts", func(t *testing.T) {
+		t.Parallel()
+		testGetPosts(t)
+	})
+	t.Run("GetPostById", func(t *testing.T) {
+		t.Parallel()
+		testGetPostById(t)
+	})
+	t.Run("GetComments", func(t *testing.T) {
+		t.Parallel()
+		testGetComments(t)
+	})
+}
+
+func testCreatePost(t *testing.T) {
+	testCases := []commandTestCase[command.CreatePost]{
+		{
+			name: "authorized user can create post",
+			authUser: &auth.AuthenticatedUser{
+				Id:    "userId-1",
+				Roles: []rbac.UserRole{rbac.Regular},
+				Email: "user@example.com",
+			},
+			command: command.CreatePost{
+				Id:    "postId-1",
+				Title: "title",
+				Body:  "body",
+			},
+			expectedErr:    nil,
+			expectedEvents: []string{"content.post_created"},
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CreatePost, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreatePost).Return(nil)
+				m.postRepo.EXPECT().CreatePost(mock.Anything, mock.AnythingOfType("domain.Post")).RunAndReturn(
+					func(ctx context.Context, post domain.Post) error {
+						require.Equal(t, authUser.Id, post.AuthorId(), "Post author was not taken from the authenticated user")
+						require.Equal(t, domain.Published, post.Status())
+						return nil
+					})
+			},
+		},
+		{
+			name: "unauthorized user cannot create post",
+			authUser: &auth.AuthenticatedUser{
+				Roles: []rbac.UserRole{rbac.Guest},
+			},
+			command: command.CreatePost{
+				Id:    "postId-1",
+				Title: "title",
+				Body:  "body",
+			},
+			expectedErr: rbac.ErrUnauthorized,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CreatePost, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreatePost).Return(rbac.ErrUnauthorized)
+			},
+		},
+		{
+			name: "cannot create post without a title",
+			authUser: &auth.AuthenticatedUser{
+				Id:    "userId-1",
+				Roles: []rbac.UserRole{rbac.Regular},
+				Email: "user@example.com",
+			},
+			command: command.CreatePost{
+				Id:   "postId-1",
+				Body: "body",
+			},
+			expectedErr: domain.ErrPostTitleRequired,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CreatePost, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreatePost).Return(nil)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, contentService := setupCommandContentService(t, tt)
+			err := contentService.CreatePost.Handle(ctx, tt.command)
+			assertError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func testUpdatePost(t *testing.T) {
+	newTitle := "new title"
+	testCases := []commandTestCase[command.UpdatePost]{
+		{
+			name: "author can update their post",
+			authUser: &auth.AuthenticatedUser{
+				Id:    "userId-1",
+				Roles: []rbac.UserRole{rbac.Regular},
+				Email: "user@example.com",
+			},
+			command: command.UpdatePost{
+				Id:    "postId-1",
+				Title: &newTitle,
+			},
+			expectedErr:    nil,
+			expectedEvents: []string{"content.post_edited"},
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.UpdatePost, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.UpdatePost).Return(nil)
+				m.guards.EXPECT().Can(mock.Anything, abac.Edit, ownedBy(abac.PostKind, authUser.Id)).Return(nil)
+				m.postRepo.EXPECT().UpdatePost(mock.Anything, command.Id, mock.AnythingOfType("func(*domain.Post) error")).RunAndReturn(
+					func(ctx context.Context, postId string, updateFn func(post *domain.Post) error) error {
+						post := domain.MustNewPost(postId, authUser.Id, "title", "body", domain.Published, time.Now(), time.Now())
+						err := updateFn(&post)
+						require.NoError(t, err)
+						require.Equal(t, newTitle, post.Title(), "Title was not updated as expected")
+						require.Equal(t, "body", post.Body(), "Body should have been left untouched")
+						return nil
+					})
+			},
+		},
+		{
+			name: "user cannot update another user's post",
+			authUser: &auth.AuthenticatedUser{
+				Id:    "userId-2",
+				Roles: []rbac.UserRole{rbac.Regular},
+				Email: "user2@example.com",
+			},
+			command: command.UpdatePost{
+				Id:    "postId-1",
+				Title: &newTitle,
+			},
+			expectedErr: rbac.ErrUnauthorized,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.UpdatePost, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.UpdatePost).Return(nil)
+				m.guards.EXPECT().Can(mock.Anything, abac.Edit, ownedBy(abac.PostKind, "userId-1")).Return(rbac.ErrUnauthorized)
+				m.postRepo.EXPECT().UpdatePost(mock.Anything, command.Id, mock.AnythingOfType("func(*domain.Post) error")).RunAndReturn(
+					func(ctx context.Context, postId string, updateFn func(post *domain.Post) error) error {
+						post := domain.MustNewPost(postId, "userId-1", "title", "body", domain.Published, time.Now(), time.Now())
+						return updateFn(&post)
+					})
+			},
+		},
+		{
+			name: "guest cannot update post",
+			authUser: &auth.AuthenticatedUser{
+				Roles: []rbac.UserRole{rbac.Guest},
+			},
+			command: command.UpdatePost{
+				Id:    "postId-1",
+				Title: &newTitle,
+			},
+			expectedErr: rbac.ErrUnauthorized,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.UpdatePost, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.UpdatePost).Return(rbac.ErrUnauthorized)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, contentService := setupCommandContentService(t, tt)
+			err := contentService.UpdatePost.Handle(ctx, tt.command)
+			assertError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func testDeletePost(t *testing.T) {
+	testCases := []commandTestCase[command.DeletePost]{
+		{
+			name: "moderator can delete another user's post",
+			authUser: &auth.AuthenticatedUser{
+				Id:    "userId-2",
+				Roles: []rbac.UserRole{rbac.Moderator},
+				Email: "moderator@example.com",
+			},
+			command: command.DeletePost{
+				Id: "postId-1",
+			},
+			expectedErr:    nil,
+			expectedEvents: []string{"content.post_deleted"},
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.DeletePost, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.DeletePost).Return(nil)
+				m.guards.EXPECT().Can(mock.Anything, abac.Delete, ownedBy(abac.PostKind, "userId-1")).Return(nil)
+				m.postRepo.EXPECT().DeletePost(mock.Anything, command.Id, mock.AnythingOfType("func(*domain.Post) error")).RunAndReturn(
+					func(ctx context.Context, postId string, updateFn func(post *domain.Post) error) error {
+						post := domain.MustNewPost(postId, "userId-1", "title", "body", domain.Published, time.Now(), time.Now())
+						err := updateFn(&post)
+						require.NoError(t, err)
+						require.True(t, post.IsDeleted(), "Post was not deleted as expected")
+						return nil
+					})
+			},
+		},
+		{
+			name: "unauthorized user cannot delete post",
+			authUser: &auth.AuthenticatedUser{
+				Roles: []rbac.UserRole{rbac.Guest},
+			},
+			command: command.DeletePost{
+				Id: "postId-1",
+			},
+			expectedErr: rbac.ErrUnauthorized,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.DeletePost, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.DeletePost).Return(rbac.ErrUnauthorized)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, contentService := setupCommandContentService(t, tt)
+			err := contentService.DeletePost.Handle(ctx, tt.command)
+			assertError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func testGetPosts(t *testing.T) {
+	testCases := []queryTestCase[query.GetPosts, query.Result]{
+		{
+			name: "cannot list fewer than one post",
+			authUser: &auth.AuthenticatedUser{
+				Roles: []rbac.UserRole{rbac.Guest},
+			},
+			query: query.GetPosts{First: -1},
+			expectedResult: queryResult[query.Result]{
+				data: nil,
+				err:  domain.ErrInvalidPageSize,
+			},
+			setupMocks: func(t *testing.T, m *repoMocks, query query.GetPosts, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ViewPost).Return(nil)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, contentService := setupQueryContentService(t, tt)
+			res, err := contentService.GetPosts.Handle(ctx, tt.query)
+			assertError(t, err, tt.expectedResult.err)
+			assert.Equal(t, tt.expectedResult.data, res)
+		})
+	}
+}
+
+func testGetPostById(t *testing.T) {
+	draft := &domain.PostReadModel{
+		Id:       "postId-1",
+		AuthorId: "userId-1",
+		Title:    "title",
+		Body:     "body",
+		Status:   domain.Draft,
+	}
+	testCases := []queryTestCase[query.GetPostById, domain.PostReadModel]{
+		{
+			name: "author can view their draft",
+			authUser: &auth.AuthenticatedUser{
+				Id:    "userId-1",
+				Roles: []rbac.UserRole{rbac.Regular},
+				Email: "user@example.com",
+			},
+			query: query.GetPostById{Id: "postId-1"},
+			expectedResult: queryResult[domain.PostReadModel]{
+				data: draft,
+				err:  nil,
+			},
+			setupMocks: func(t *testing.T, m *repoMocks, query query.GetPostById, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ViewPost).Return(nil)
+				m.guards.EXPECT().Can(mock.Anything, abac.Edit, ownedBy(abac.PostKind, draft.AuthorId)).Return(nil)
+				m.postReadModelRepo.EXPECT().GetPostById(mock.Anything, query.Id).Return(draft, nil)
+			},
+		},
+		{
+			name: "other users cannot see a draft",
+			authUser: &auth.AuthenticatedUser{
+				Id:    "userId-2",
+				Roles: []rbac.UserRole{rbac.Regular},
+				Email: "user2@example.com",
+			},
+			query: query.GetPostById{Id: "postId-1"},
+			expectedResult: queryResult[domain.PostReadModel]{
+				data: nil,
+				err:  domain.ErrPostNotFound,
+			},
+			setupMocks: func(t *testing.T, m *repoMocks, query query.GetPostById, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ViewPost).Return(nil)
+				m.guards.EXPECT().Can(mock.Anything, abac.Edit, ownedBy(abac.PostKind, draft.AuthorId)).Return(rbac.ErrUnauthorized)
+				m.postReadModelRepo.EXPECT().GetPostById(mock.Anything, query.Id).Return(draft, nil)
+			},
+		},
+		{
+			name: "post not found",
+			authUser: &auth.AuthenticatedUser{
+				Roles: []rbac.UserRole{rbac.Guest},
+			},
+			query: query.GetPostById{Id: "postId-1"},
+			expectedResult: queryResult[domain.PostReadModel]{
+				data: nil,
+				err:  domain.ErrPostNotFound,
+			},
+			setupMocks: func(t *testing.T, m *repoMocks, query query.GetPostById, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ViewPost).Return(nil)
+				m.postReadModelRepo.EXPECT().GetPostById(mock.Anything, query.Id).Return(nil, domain.ErrPostNotFound)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, contentService := setupQueryContentService(t, tt)
+			res, err := contentService.GetPostById.Handle(ctx, tt.query)
+			assertError(t, err, tt.expectedResult.err)
+			assert.Equal(t, tt.expectedResult.data, res)
+		})
+	}
+}
+
+func testCreateComment(t *testing.T) {
+	regularUser := &auth.AuthenticatedUser{
+		Id:    "userId-1",
+		Roles: []rbac.UserRole{rbac.Regular},
+		Email: "user@example.com",
+	}
+	publishedPost := &domain.PostReadModel{Id: "postId-1", AuthorId: "userId-2", Status: domain.Published}
+	testCases := []commandTestCase[command.CreateComment]{
+		{
+			name:     "authorized user can comment on a published post",
+			authUser: regularUser,
+			command: command.CreateComment{
+				Id:     "commentId-1",
+				PostId: "postId-1",
+				Body:   "body",
+			},
+			expectedErr:    nil,
+			expectedEvents: []string{"content.comment_created"},
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CreateComment, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreateComment).Return(nil)
+				m.postReadModelRepo.EXPECT().GetPostById(mock.Anything, command.PostId).Return(publishedPost, nil)
+				m.commentRepo.EXPECT().CreateComment(mock.Anything, mock.AnythingOfType("domain.Comment")).RunAndReturn(
+					func(ctx context.Context, comment domain.Comment) error {
+						require.Equal(t, authUser.Id, comment.AuthorId())
+						require.True(t, comment.IsRoot())
+						require.Equal(t, comment.Id(), comment.RootId())
+						return nil
+					})
+			},
+		},
+		{
+			name:     "user can reply to a comment",
+			authUser: regularUser,
+			command: command.CreateComment{
+				Id:       "commentId-2",
+				PostId:   "postId-1",
+				ParentId: "commentId-1",
+				Body:     "body",
+			},
+			expectedErr:    nil,
+			expectedEvents: []string{"content.comment_created"},
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CreateComment, authUser *auth.AuthenticatedUser) {
+				parent := domain.MustNewComment("commentId-1", "postId-1", "userId-2", "", "commentId-1", 0, "body", false, time.Now(), time.Now())
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreateComment).Return(nil)
+				m.postReadModelRepo.EXPECT().GetPostById(mock.Anything, command.PostId).Return(publishedPost, nil)
+				m.commentRepo.EXPECT().GetComment(mock.Anything, command.ParentId).Return(&parent, nil)
+				m.commentRepo.EXPECT().CreateComment(mock.Anything, mock.AnythingOfType("domain.Comment")).RunAndReturn(
+					func(ctx context.Context, comment domain.Comment) error {
+						require.Equal(t, "commentId-1", comment.ParentId())
+						require.Equal(t, "commentId-1", comment.RootId())
+						require.Equal(t, 1, comment.Depth())
+						return nil
+					})
+			},
+		},
+		{
+			name:     "user cannot reply deeper than the configured depth",
+			authUser: regularUser,
+			command: command.CreateComment{
+				Id:       "commentId-4",
+				PostId:   "postId-1",
+				ParentId: "commentId-3",
+				Body:     "body",
+			},
+			expectedErr: domain.ErrMaxCommentDepthExceeded,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CreateComment, authUser *auth.AuthenticatedUser) {
+				parent := domain.MustNewComment("commentId-3", "postId-1", "userId-2", "commentId-2", "commentId-1", maxCommentDepth, "body", false, time.Now(), time.Now())
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreateComment).Return(nil)
+				m.postReadModelRepo.EXPECT().GetPostById(mock.Anything, command.PostId).Return(publishedPost, nil)
+				m.commentRepo.EXPECT().GetComment(mock.Anything, command.ParentId).Return(&parent, nil)
+			},
+		},
+		{
+			name:     "user cannot reply to a comment of another post",
+			authUser: regularUser,
+			command: command.CreateComment{
+				Id:       "commentId-2",
+				PostId:   "postId-1",
+				ParentId: "commentId-1",
+				Body:     "body",
+			},
+			expectedErr: domain.ErrCommentNotFound,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CreateComment, authUser *auth.AuthenticatedUser) {
+				parent := domain.MustNewComment("commentId-1", "postId-2", "userId-2", "", "commentId-1", 0, "body", false, time.Now(), time.Now())
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreateComment).Return(nil)
+				m.postReadModelRepo.EXPECT().GetPostById(mock.Anything, command.PostId).Return(publishedPost, nil)
+				m.commentRepo.EXPECT().GetComment(mock.Anything, command.ParentId).Return(&parent, nil)
+			},
+		},
+		{
+			name:     "user cannot comment on a draft",
+			authUser: regularUser,
+			command: command.CreateComment{
+				Id:     "commentId-1",
+				PostId: "postId-1",
+				Body:   "body",
+			},
+			expectedErr: domain.ErrPostNotFound,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CreateComment, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreateComment).Return(nil)
+				m.postReadModelRepo.EXPECT().GetPostById(mock.Anything, command.PostId).Return(&domain.PostReadModel{Id: "postId-1", Status: domain.Draft}, nil)
+			},
+		},
+		{
+			name: "guest cannot comment",
+			authUser: &auth.AuthenticatedUser{
+				Roles: []rbac.UserRole{rbac.Guest},
+			},
+			command: command.CreateComment{
+				Id:     "commentId-1",
+				PostId: "postId-1",
+				Body:   "body",
+			},
+			expectedErr: rbac.ErrUnauthorized,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CreateComment, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreateComment).Return(rbac.ErrUnauthorized)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, contentService := setupCommandContentService(t, tt)
+			err := contentService.CreateComment.Handle(ctx, tt.command)
+			assertError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func testEditComment(t *testing.T) {
+	testCases := []commandTestCase[command.EditComment]{
+		{
+			name: "author can edit their comment",
+			authUser: &auth.AuthenticatedUser{
+				Id:    "userId-1",
+				Roles: []rbac.UserRole{rbac.Regular},
+				Email: "user@example.com",
+			},
+			command: command.EditComment{
+				Id:   "commentId-1",
+				Body: "new body",
+			},
+			expectedErr:    nil,
+			expectedEvents: []string{"content.comment_edited"},
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.EditComment, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.UpdateComment).Return(nil)
+				m.guards.EXPECT().Can(mock.Anything, abac.Edit, ownedBy(abac.CommentKind, authUser.Id)).Return(nil)
+				m.commentRepo.EXPECT().EditComment(mock.Anything, command.Id, mock.AnythingOfType("func(*domain.Comment) error")).RunAndReturn(
+					func(ctx context.Context, commentId string, updateFn func(comment *domain.Comment) error) error {
+						comment := domain.MustNewComment(commentId, "postId-1", authUser.Id, "", commentId, 0, "body", false, time.Now(), time.Now())
+						err := updateFn(&comment)
+						require.NoError(t, err)
+						require.Equal(t, "new body", comment.Body(), "Body was not updated as expected")
+						return nil
+					})
+			},
+		},
+		{
+			name: "user cannot edit another user's comment",
+			authUser: &auth.AuthenticatedUser{
+				Id:    "userId-2",
+				Roles: []rbac.UserRole{rbac.Regular},
+				Email: "user2@example.com",
+			},
+			command: command.EditComment{
+				Id:   "commentId-1",
+				Body: "new body",
+			},
+			expectedErr: rbac.ErrUnauthorized,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.EditComment, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.UpdateComment).Return(nil)
+				m.guards.EXPECT().Can(mock.Anything, abac.Edit, ownedBy(abac.CommentKind, "userId-1")).Return(rbac.ErrUnauthorized)
+				m.commentRepo.EXPECT().EditComment(mock.Anything, command.Id, mock.AnythingOfType("func(*domain.Comment) error")).RunAndReturn(
+					func(ctx context.Context, commentId string, updateFn func(comment *domain.Comment) error) error {
+						comment := domain.MustNewComment(commentId, "postId-1", "userId-1", "", commentId, 0, "body", false, time.Now(), time.Now())
+						return updateFn(&comment)
+					})
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, contentService := setupCommandContentService(t, tt)
+			err := contentService.EditComment.Handle(ctx, tt.command)
+			assertError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func testDeleteComment(t *testing.T) {
+	testCases := []commandTestCase[command.DeleteComment]{
+		{
+			name: "moderator can delete another user's comment",
+			authUser: &auth.AuthenticatedUser{
+				Id:    "userId-2",
+				Roles: []rbac.UserRole{rbac.Moderator},
+				Email: "moderator@example.com",
+			},
+			command: command.DeleteComment{
+				Id: "commentId-1",
+			},
+			expectedErr:    nil,
+			expectedEvents: []string{"content.comment_deleted"},
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.DeleteComment, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.DeleteComment).Return(nil)
+				m.guards.EXPECT().Can(mock.Anything, abac.Delete, ownedBy(abac.CommentKind, "userId-1")).Return(nil)
+				m.commentRepo.EXPECT().DeleteComment(mock.Anything, command.Id, mock.AnythingOfType("func(*domain.Comment) error")).RunAndReturn(
+					func(ctx context.Context, commentId string, updateFn func(comment *domain.Comment) error) error {
+						comment := domain.MustNewComment(commentId, "postId-1", "userId-1", "", commentId, 0, "body", false, time.Now(), time.Now())
+						err := updateFn(&comment)
+						require.NoError(t, err)
+						require.True(t, comment.IsDeleted(), "Comment was not deleted as expected")
+						require.Equal(t, domain.DeletedCommentPlaceholder, comment.Body())
+						return nil
+					})
+			},
+		},
+		{
+			name: "guest cannot delete comment",
+			authUser: &auth.AuthenticatedUser{
+				Roles: []rbac.UserRole{rbac.Guest},
+			},
+			command: command.DeleteComment{
+				Id: "commentId-1",
+			},
+			expectedErr: rbac.ErrUnauthorized,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.DeleteComment, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.DeleteComment).Return(rbac.ErrUnauthorized)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, contentService := setupCommandContentService(t, tt)
+			err := contentService.DeleteComment.Handle(ctx, tt.command)
+			assertError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func testGetComments(t *testing.T) {
+	now := time.Now()
+	newComments := func() ([]*domain.CommentReadModel, []*domain.CommentReadModel) {
+		root := domain.NewCommentReadModel(domain.MustNewComment("c1", "postId-1", "userId-1", "", "c1", 0, "root", false, now, now))
+		reply := domain.NewCommentReadModel(domain.MustNewComment("c2", "postId-1", "userId-2", "c1", "c1", 1, "reply", true, now.Add(time.Minute), now))
+		nested := domain.NewCommentReadModel(domain.MustNewComment("c3", "postId-1", "userId-1", "c2", "c1", 2, "nested", false, now.Add(2*time.Minute), now))
+		return []*domain.CommentReadModel{root}, []*domain.CommentReadModel{reply, nested}
+	}
+	guest := &auth.AuthenticatedUser{Roles: []rbac.UserRole{rbac.Guest}}
+	setupMocks := func(t *testing.T, m *repoMocks, q query.GetComments, authUser *auth.AuthenticatedUser) {
+		roots, replies := newComments()
+		m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ViewComment).Return(nil)
+		m.postReadModelRepo.EXPECT().GetPostById(mock.Anything, q.PostId).Return(&domain.PostReadModel{Id: q.PostId, Status: domain.Published}, nil)
+		m.commentReadModelRepo.EXPECT().GetComments(mock.Anything, q.GetCommentsOptions).Return(roots, false, nil)
+		m.commentReadModelRepo.EXPECT().GetReplies(mock.Anything, []string{"c1"}).Return(replies, nil)
+	}
+
+	t.Run("returns threads as a tree", func(t *testing.T) {
+		t.Parallel()
+		tt := queryTestCase[query.GetComments, query.CommentsResult]{
+			authUser:   guest,
+			query:      query.GetComments{GetCommentsOptions: domain.GetCommentsOptions{PostId: "postId-1", First: 10}, Layout: query.TreeLayout},
+			setupMocks: setupMocks,
+		}
+		ctx, contentService := setupQueryContentService(t, tt)
+		res, err := contentService.GetComments.Handle(ctx, tt.query)
+		require.NoError(t, err)
+		require.Len(t, res.Data, 1)
+		root := res.Data[0]
+		require.Len(t, root.Replies, 1)
+		assert.Equal(t, domain.DeletedCommentPlaceholder, root.Replies[0].Body)
+		assert.Nil(t, root.Replies[0].AuthorId, "Author of a deleted comment should be hidden")
+		require.Len(t, root.Replies[0].Replies, 1)
+		assert.Equal(t, "c3", root.Replies[0].Replies[0].Id)
+	})
+
+	t.Run("returns threads flattened in reading order", func(t *testing.T) {
+		t.Parallel()
+		tt := queryTestCase[query.GetComments, query.CommentsResult]{
+			authUser:   guest,
+			query:      query.GetComments{GetCommentsOptions: domain.GetCommentsOptions{PostId: "postId-1", First: 10}, Layout: query.FlatLayout},
+			setupMocks: setupMocks,
+		}
+		ctx, contentService := setupQueryContentService(t, tt)
+		res, err := contentService.GetComments.Handle(ctx, tt.query)
+		require.NoError(t, err)
+		require.Len(t, res.Data, 3)
+		for i, id := range []string{"c1", "c2", "c3"} {
+			assert.Equal(t, id, res.Data[i].Id)
+			assert.Equal(t, int32(i), res.Data[i].Depth)
+			assert.Empty(t, res.Data[i].Replies)
+		}
+	})
+
+	t.Run("cannot list fewer than one comment", func(t *testing.T) {
+		t.Parallel()
+		tt := queryTestCase[query.GetComments, query.CommentsResult]{
+			authUser: guest,
+			query:    query.GetComments{GetCommentsOptions: domain.GetCommentsOptions{PostId: "postId-1", First: 0}, Layout: query.TreeLayout},
+			setupMocks: func(t *testing.T, m *repoMocks, q query.GetComments, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ViewComment).Return(nil)
+			},
+		}
+		ctx, contentService := setupQueryContentService(t, tt)
+		res, err := contentService.GetComments.Handle(ctx, tt.query)
+		assert.ErrorIs(t, err, domain.ErrInvalidPageSize)
+		assert.Nil(t, res)
+	})
+}
+
+func setupCommandContentService[T any](t *testing.T, tt commandTestCase[T]) (context.Context, *service.Application) {
+	t.Helper()
+	ctx := auth.NewContextWithUser(context.Background(), tt.authUser)
+	m := newRepoMocks(t)
+	tt.setupMocks(t, m, &tt.command, tt.authUser)
+	t.Cleanup(func() { assert.Equal(t, tt.expectedEvents, m.publisher.published, "Unexpected events were published") })
+	return ctx, newContentService(m)
+}
+
+func setupQueryContentService[T any, D any](t *testing.T, tt queryTestCase[T, D]) (context.Context, *service.Application) {
+	t.Helper()
+	ctx := auth.NewContextWithUser(context.Background(), tt.authUser)
+	m := newRepoMocks(t)
+	tt.setupMocks(t, m, tt.query, tt.authUser)
+	return ctx, newContentService(m)
+}
+
+func newRepoMocks(t *testing.T) *repoMocks {
+	return &repoMocks{
+		postRepo:             domain_mocks.NewMockPostRepository(t),
+		postReadModelRepo:    domain_mocks.NewMockPostReadModelRepository(t),
+		commentRepo:          domain_mocks.NewMockCommentRepository(t),
+		commentReadModelRepo: domain_mocks.NewMockCommentReadModelRepository(t),
+		publisher:            &publisherStub{},
+		guards:               guard_mocks.NewMockGuards(t),
+	}
+}
+
+func newContentService(m *repoMocks) *service.Application {
+	return service.New(m.postRepo, m.postReadModelRepo, m.commentRepo, m.commentReadModelRepo, notBanned, m.publisher, m.guards, maxCommentDepth)
+}
+
+func assertError(t *testing.T, err error, expectedErr error) {
+	t.Helper()
+	if err != nil {
+		require.ErrorIs(t, err, expectedErr, expectedErr.Error())
+	} else {
+		assert.NoError(t, err)
+	}
+}
+
+// ownedBy matches the ABAC resources of kind owned by ownerId
+func ownedBy(kind abac.Kind, ownerId string) any {
+	return mock.MatchedBy(func(resource abac.Resource) bool {
+		return resource.Kind == kind && resource.OwnerId == ownerId
+	})
+}
@@ -0,0 +1,37 @@
+package query
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+type GetCommentByIdHandler = shared.QueryHandler[GetCommentById, *domain.CommentReadModel]
+
+type GetCommentById struct {
+	Id string
+}
+
+type getCommentByIdHandler struct {
+	queryRepo domain.CommentReadModelRepository
+	guard     guards.Guards
+}
+
+func NewGetCommentByIdHandler(queryRepo domain.CommentReadModelRepository, guard guards.Guards) GetCommentByIdHandler {
+	if queryRepo == nil || guard == nil {
+		panic("nil comment repository or guard")
+	}
+	return &getCommentByIdHandler{queryRepo: queryRepo, guard: guard}
+}
+
+func (g *getCommentByIdHandler) Handle(ctx context.Context, cmd GetCommentById) (*domain.CommentReadModel, error) {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ViewComment); err != nil {
+		return nil, err
+	}
+	return g.queryRepo.GetCommentById(ctx, cmd.Id)
+}
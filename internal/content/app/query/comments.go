@@ -0,0 +1,91 @@
+package query
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/abac"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/shared/pagination"
+)
+
+// CommentLayout controls how the comments of a thread are returned
+type CommentLayout string
+
+const (
+	// TreeLayout returns the top-level comments with their replies nested under them
+	TreeLayout CommentLayout = "TREE"
+	// FlatLayout returns every comment in reading order, each one followed by its replies
+	FlatLayout CommentLayout = "FLAT"
+)
+
+// GetComments pages through the top-level comments of a post. Every page contains
+// the complete threads of the top-level comments on it.
+type GetComments struct {
+	domain.GetCommentsOptions
+	Layout CommentLayout
+}
+
+type CommentsResult = pagination.PaginatedQueryResult[[]*domain.CommentReadModel]
+
+type GetCommentsHandler = shared.QueryHandler[GetComments, *CommentsResult]
+
+type getCommentsHandler struct {
+	queryRepo     domain.CommentReadModelRepository
+	postQueryRepo domain.PostReadModelRepository
+	guard         guards.Guards
+}
+
+func NewGetCommentsHandler(queryRepo domain.CommentReadModelRepository, postQueryRepo domain.PostReadModelRepository, guard guards.Guards) GetCommentsHandler {
+	if queryRepo == nil || postQueryRepo == nil || guard == nil {
+		panic("nil comment repository, post repository or guard")
+	}
+	return &getCommentsHandler{queryRepo: queryRepo, postQueryRepo: postQueryRepo, guard: guard}
+}
+
+func (g *getCommentsHandler) Handle(ctx context.Context, cmd GetComments) (*CommentsResult, error) {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ViewComment); err != nil {
+		return nil, err
+	}
+	if cmd.First <= 0 {
+		return nil, domain.ErrInvalidPageSize
+	}
+	post, err := g.postQueryRepo.GetPostById(ctx, cmd.PostId)
+	if err != nil {
+		return nil, err
+	}
+	if post.Status == domain.Draft && g.guard.Can(ctx, abac.Edit, abac.Post(post.Id, post.AuthorId, post.CreatedAt)) != nil {
+		return nil, domain.ErrPostNotFound
+	}
+
+	roots, hasNext, err := g.queryRepo.GetComments(ctx, cmd.GetCommentsOptions)
+	if err != nil {
+		return nil, err
+	}
+	var replies []*domain.CommentReadModel
+	if len(roots) > 0 {
+		rootIds := make([]string, len(roots))
+		for i, root := range roots {
+			rootIds[i] = root.Id
+		}
+		replies, err = g.queryRepo.GetReplies(ctx, rootIds)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	comments := domain.BuildCommentThreads(roots, replies)
+	if cmd.Layout == FlatLayout {
+		comments = domain.FlattenCommentThreads(comments)
+	}
+	return &CommentsResult{
+		Data: comments,
+		PaginationInfo: &pagination.PagenationInfo{
+			HasNext: hasNext,
+		},
+	}, nil
+}
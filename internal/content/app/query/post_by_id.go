@@ -0,0 +1,46 @@
+package query
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/abac"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+type GetPostByIdHandler = shared.QueryHandler[GetPostById, *domain.PostReadModel]
+
+type GetPostById struct {
+	Id string
+}
+
+type getPostByIdHandler struct {
+	queryRepo domain.PostReadModelRepository
+	guard     guards.Guards
+}
+
+func NewGetPostByIdHandler(queryRepo domain.PostReadModelRepository, guard guards.Guards) GetPostByIdHandler {
+	if queryRepo == nil || guard == nil {
+		panic("nil post repository or guard")
+	}
+	return &getPostByIdHandler{queryRepo: queryRepo, guard: guard}
+}
+
+func (g *getPostByIdHandler) Handle(ctx context.Context, cmd GetPostById) (*domain.PostReadModel, error) {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ViewPost); err != nil {
+		return nil, err
+	}
+	post, err := g.queryRepo.GetPostById(ctx, cmd.Id)
+	if err != nil {
+		return nil, err
+	}
+	// Drafts are only visible to the people allowed to edit them
+	if post.Status == domain.Draft && g.guard.Can(ctx, abac.Edit, abac.Post(post.Id, post.AuthorId, post.CreatedAt)) != nil {
+		return nil, domain.ErrPostNotFound
+	}
+	return post, nil
+}
@@ -0,0 +1,50 @@
+package query
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/shared/pagination"
+)
+
+type GetPosts = domain.GetPostsOptions
+
+type Result = pagination.PaginatedQueryResult[[]*domain.PostReadModel]
+
+type GetPostsHandler = shared.QueryHandler[GetPosts, *Result]
+
+type getPostsHandler struct {
+	queryRepo domain.PostReadModelRepository
+	guard     guards.Guards
+}
+
+func NewGetPostsHandler(queryRepo domain.PostReadModelRepository, guard guards.Guards) GetPostsHandler {
+	if queryRepo == nil || guard == nil {
+		panic("nil post repository or guard")
+	}
+	return &getPostsHandler{queryRepo: queryRepo, guard: guard}
+}
+
+func (g *getPostsHandler) Handle(ctx context.Context, cmd GetPosts) (*Result, error) {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ViewPost); err != nil {
+		return nil, err
+	}
+	if cmd.First <= 0 {
+		return nil, domain.ErrInvalidPageSize
+	}
+	posts, hasNext, err := g.queryRepo.GetPosts(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		Data: posts,
+		PaginationInfo: &pagination.PagenationInfo{
+			HasNext: hasNext,
+		},
+	}, nil
+}
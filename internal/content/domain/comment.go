@@ -1 +1,209 @@
 package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/events"
+)
+
+// Comment is a remark on a post. Top-level comments have no parent and a depth of 0,
+// replies sit one level below the comment they answer. Every comment also carries the
+// id of the top-level comment of its thread so that a whole thread can be loaded at once.
+type Comment struct {
+	id        string
+	postId    string
+	authorId  string
+	parentId  string
+	rootId    string
+	depth     int
+	body      string
+	deleted   bool
+	createdAt time.Time
+	updatedAt time.Time
+	events    events.Recorder
+}
+
+const (
+	MaxCommentLength = 10000
+	// DeletedCommentPlaceholder replaces the body of a deleted comment so its replies keep their place in the thread
+	DeletedCommentPlaceholder = "[deleted]"
+)
+
+var (
+	ErrCommentIdRequired       = errors.New("comment id cannot be empty")
+	ErrCommentPostRequired     = errors.New("comment post cannot be empty")
+	ErrCommentAuthorRequired   = errors.New("comment author cannot be empty")
+	ErrCommentBodyRequired     = errors.New("comment body cannot be empty")
+	ErrCommentTooLong          = errors.New("comment body is too long")
+	ErrInvalidCommentThread    = errors.New("invalid comment thread")
+	ErrMaxCommentDepthExceeded = errors.New("maximum reply depth reached")
+	ErrCommentAlreadyDeleted   = errors.New("comment is already deleted")
+	ErrCommentNotFound         = errors.New("comment not found")
+)
+
+// NewComment rebuilds a comment from its stored state. Use NewRootComment or Reply to create new comments.
+func NewComment(id, postId, authorId, parentId, rootId string, depth int, body string, deleted bool, createdAt, updatedAt time.Time) (Comment, error) {
+	comment := Comment{}
+	if strings.TrimSpace(id) == "" {
+		return comment, ErrCommentIdRequired
+	}
+	if strings.TrimSpace(postId) == "" {
+		return comment, ErrCommentPostRequired
+	}
+	if strings.TrimSpace(authorId) == "" {
+		return comment, ErrCommentAuthorRequired
+	}
+	if !deleted {
+		if err := validateCommentBody(body); err != nil {
+			return comment, err
+		}
+	}
+	if depth < 0 || (depth == 0) != (parentId == "") || strings.TrimSpace(rootId) == "" {
+		return comment, ErrInvalidCommentThread
+	}
+	if deleted {
+		body = DeletedCommentPlaceholder
+	}
+	return Comment{
+		id:        id,
+		postId:    postId,
+		authorId:  authorId,
+		parentId:  parentId,
+		rootId:    rootId,
+		depth:     depth,
+		body:      body,
+		deleted:   deleted,
+		createdAt: createdAt,
+		updatedAt: updatedAt,
+	}, nil
+}
+
+func MustNewComment(id, postId, authorId, parentId, rootId string, depth int, body string, deleted bool, createdAt, updatedAt time.Time) Comment {
+	comment, err := NewComment(id, postId, authorId, parentId, rootId, depth, body, deleted, createdAt, updatedAt)
+	if err != nil {
+		panic(err.Error())
+	}
+	return comment
+}
+
+// NewRootComment creates a top-level comment on a post and raises CommentCreated
+func NewRootComment(id, postId, authorId, body string, createdAt time.Time) (Comment, error) {
+	return createComment(id, postId, authorId, "", id, 0, body, createdAt)
+}
+
+// Reply creates a comment answering c and raises CommentCreated. maxDepth limits how deep replies
+// can be nested.
+func (c *Comment) Reply(id, authorId, body string, maxDepth int, createdAt time.Time) (Comment, error) {
+	if c.deleted {
+		return Comment{}, ErrCommentAlreadyDeleted
+	}
+	if c.depth+1 > maxDepth {
+		return Comment{}, ErrMaxCommentDepthExceeded
+	}
+	return createComment(id, c.postId, authorId, c.id, c.rootId, c.depth+1, body, createdAt)
+}
+
+func createComment(id, postId, authorId, parentId, rootId string, depth int, body string, createdAt time.Time) (Comment, error) {
+	comment, err := NewComment(id, postId, authorId, parentId, rootId, depth, body, false, createdAt, createdAt)
+	if err != nil {
+		return comment, err
+	}
+	comment.events.Record(CommentCreated{CommentId: id, PostId: postId, AuthorId: authorId, ParentId: parentId, Created: createdAt})
+	return comment, nil
+}
+
+func (c *Comment) Edit(body string) error {
+	if c.deleted {
+		return ErrCommentAlreadyDeleted
+	}
+	if err := validateCommentBody(body); err != nil {
+		return err
+	}
+	c.body = body
+	c.updatedAt = time.Now()
+	c.events.Record(CommentEdited{CommentId: c.id, PostId: c.postId, AuthorId: c.authorId, EditedAt: c.updatedAt})
+	return nil
+}
+
+// Delete soft-deletes the comment. The body is replaced by a placeholder and the comment stays in
+// the thread so that its replies are still reachable.
+func (c *Comment) Delete() error {
+	if c.deleted {
+		return ErrCommentAlreadyDeleted
+	}
+	c.deleted = true
+	c.body = DeletedCommentPlaceholder
+	c.updatedAt = time.Now()
+	c.events.Record(CommentDeleted{CommentId: c.id, PostId: c.postId, AuthorId: c.authorId, DeletedAt: c.updatedAt})
+	return nil
+}
+
+// ReassignAuthor hands the comment over to another author, such as the placeholder of deleted users
+func (c *Comment) ReassignAuthor(authorId string) error {
+	if strings.TrimSpace(authorId) == "" {
+		return ErrCommentAuthorRequired
+	}
+	c.authorId = authorId
+	return nil
+}
+
+// PullEvents returns the events raised since the comment was loaded and clears them
+func (c *Comment) PullEvents() []events.Event {
+	return c.events.PullEvents()
+}
+
+func (c *Comment) Id() string {
+	return c.id
+}
+
+func (c *Comment) PostId() string {
+	return c.postId
+}
+
+func (c *Comment) AuthorId() string {
+	return c.authorId
+}
+
+func (c *Comment) ParentId() string {
+	return c.parentId
+}
+
+func (c *Comment) RootId() string {
+	return c.rootId
+}
+
+func (c *Comment) IsRoot() bool {
+	return c.parentId == ""
+}
+
+func (c *Comment) Depth() int {
+	return c.depth
+}
+
+func (c *Comment) Body() string {
+	return c.body
+}
+
+func (c *Comment) IsDeleted() bool {
+	return c.deleted
+}
+
+func (c *Comment) CreatedAt() time.Time {
+	return c.createdAt
+}
+
+func (c *Comment) UpdatedAt() time.Time {
+	return c.updatedAt
+}
+
+func validateCommentBody(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return ErrCommentBodyRequired
+	}
+	if len([]rune(body)) > MaxCommentLength {
+		return ErrCommentTooLong
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package domain
+
+import "time"
+
+type CommentReadModel struct {
+	Id        string              `json:"id"`
+	PostId    string              `json:"postId"`
+	AuthorId  *string             `json:"authorId"` // nil once the comment is deleted
+	ParentId  *string             `json:"parentId"` // nil for top-level comments
+	RootId    string              `json:"rootId"`
+	Depth     int32               `json:"depth"`
+	Body      string              `json:"body"`
+	IsDeleted bool                `json:"isDeleted"`
+	Replies   []*CommentReadModel `json:"replies"`
+	CreatedAt time.Time           `json:"createdAt"`
+	UpdatedAt time.Time           `json:"updatedAt"`
+}
+
+// NewCommentReadModel converts a comment into its read model, hiding the author of deleted comments
+func NewCommentReadModel(comment Comment) *CommentReadModel {
+	rm := &CommentReadModel{
+		Id:        comment.Id(),
+		PostId:    comment.PostId(),
+		RootId:    comment.RootId(),
+		Depth:     int32(comment.Depth()),
+		Body:      comment.Body(),
+		IsDeleted: comment.IsDeleted(),
+		CreatedAt: comment.CreatedAt(),
+		UpdatedAt: comment.UpdatedAt(),
+	}
+	if !comment.IsDeleted() {
+		authorId := comment.AuthorId()
+		rm.AuthorId = &authorId
+	}
+	if !comment.IsRoot() {
+		parentId := comment.ParentId()
+		rm.ParentId = &parentId
+	}
+	return rm
+}
+
+// BuildCommentThreads attaches every reply to its parent. Replies are expected oldest first,
+// which is the order they end up in under their parent. The roots are returned in their original order.
+func BuildCommentThreads(roots []*CommentReadModel, replies []*CommentReadModel) []*CommentReadModel {
+	byId := make(map[string]*CommentReadModel, len(roots)+len(replies))
+	for _, c := range roots {
+		c.Replies = []*CommentReadModel{}
+		byId[c.Id] = c
+	}
+	for _, c := range replies {
+		c.Replies = []*CommentReadModel{}
+		byId[c.Id] = c
+	}
+	for _, c := range replies {
+		if c.ParentId == nil {
+			continue
+		}
+		if parent, ok := byId[*c.ParentId]; ok {
+			parent.Replies = append(parent.Replies, c)
+		}
+	}
+	return roots
+}
+
+// FlattenCommentThreads returns the comments of the given threads in reading order, every comment
+// followed by its replies. The returned comments don't carry their replies, use Depth to indent them.
+func FlattenCommentThreads(roots []*CommentReadModel) []*CommentReadModel {
+	flat := []*CommentReadModel{}
+	var walk func(c *CommentReadModel)
+	walk = func(c *CommentReadModel) {
+		item := *c
+		item.Replies = []*CommentReadModel{}
+		flat = append(flat, &item)
+		for _, reply := range c.Replies {
+			walk(reply)
+		}
+	}
+	for _, root := range roots {
+		walk(root)
+	}
+	return flat
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+type GetCommentsOptions struct {
+	PostId        string
+	First         int32
+	After         string
+	SortDirection string // "ASC" (oldest first, the default) or "DESC"
+}
+
+type CommentReadModelRepository interface {
+	// GetComments returns a page of the top-level comments of a post
+	GetComments(ctx context.Context, opts GetCommentsOptions) (comments []*CommentReadModel, hasNext bool, err error)
+	// GetReplies returns every reply in the threads started by the given top-level comments, oldest first
+	GetReplies(ctx context.Context, rootIds []string) ([]*CommentReadModel, error)
+	GetCommentById(ctx context.Context, id string) (*CommentReadModel, error)
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "context"
+
+type CommentRepository interface {
+	CreateComment(ctx context.Context, comment Comment) error
+	EditComment(ctx context.Context, commentId string, updateFn func(comment *Comment) error) error
+	DeleteComment(ctx context.Context, commentId string, updateFn func(comment *Comment) error) error
+	GetComment(ctx context.Context, commentId string) (*Comment, error)
+	// ReassignAuthor hands every comment of authorId over to newAuthorId, deleted comments included
+	ReassignAuthor(ctx context.Context, authorId, newAuthorId string) error
+}
@@ -0,0 +1,118 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRootComment(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should start a new thread", func(t *testing.T) {
+		t.Parallel()
+		comment, err := domain.NewRootComment("comment-1", "post-1", "user-1", "body", time.Now())
+		assert.Nil(t, err)
+		assert.True(t, comment.IsRoot())
+		assert.Equal(t, "comment-1", comment.RootId())
+		assert.Equal(t, 0, comment.Depth())
+	})
+
+	t.Run("should return correct error if body is empty", func(t *testing.T) {
+		t.Parallel()
+		_, err := domain.NewRootComment("comment-1", "post-1", "user-1", " ", time.Now())
+		assert.Equal(t, domain.ErrCommentBodyRequired, err)
+	})
+
+	t.Run("should return correct error if body is too long", func(t *testing.T) {
+		t.Parallel()
+		body := strings.Repeat("a", domain.MaxCommentLength+1)
+		_, err := domain.NewRootComment("comment-1", "post-1", "user-1", body, time.Now())
+		assert.Equal(t, domain.ErrCommentTooLong, err)
+	})
+
+	t.Run("should return correct error if post is empty", func(t *testing.T) {
+		t.Parallel()
+		_, err := domain.NewRootComment("comment-1", "", "user-1", "body", time.Now())
+		assert.Equal(t, domain.ErrCommentPostRequired, err)
+	})
+}
+
+func TestNewComment(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return correct error if a reply has no parent", func(t *testing.T) {
+		t.Parallel()
+		_, err := domain.NewComment("comment-2", "post-1", "user-1", "", "comment-1", 1, "body", false, time.Now(), time.Now())
+		assert.Equal(t, domain.ErrInvalidCommentThread, err)
+	})
+
+	t.Run("should use the placeholder as body of deleted comments", func(t *testing.T) {
+		t.Parallel()
+		comment, err := domain.NewComment("comment-1", "post-1", "user-1", "", "comment-1", 0, "", true, time.Now(), time.Now())
+		assert.Nil(t, err)
+		assert.Equal(t, domain.DeletedCommentPlaceholder, comment.Body())
+	})
+}
+
+func TestReplyToComment(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should nest the reply under its parent", func(t *testing.T) {
+		t.Parallel()
+		parent := domain.MustNewComment("comment-2", "post-1", "user-1", "comment-1", "comment-1", 1, "body", false, time.Now(), time.Now())
+		reply, err := parent.Reply("comment-3", "user-2", "reply", 5, time.Now())
+		assert.Nil(t, err)
+		assert.Equal(t, "comment-2", reply.ParentId())
+		assert.Equal(t, "comment-1", reply.RootId())
+		assert.Equal(t, "post-1", reply.PostId())
+		assert.Equal(t, 2, reply.Depth())
+	})
+
+	t.Run("should return correct error if max depth is reached", func(t *testing.T) {
+		t.Parallel()
+		parent := domain.MustNewComment("comment-2", "post-1", "user-1", "comment-1", "comment-1", 1, "body", false, time.Now(), time.Now())
+		_, err := parent.Reply("comment-3", "user-2", "reply", 1, time.Now())
+		assert.Equal(t, domain.ErrMaxCommentDepthExceeded, err)
+	})
+
+	t.Run("should return correct error if parent is deleted", func(t *testing.T) {
+		t.Parallel()
+		parent := domain.MustNewComment("comment-1", "post-1", "user-1", "", "comment-1", 0, "", true, time.Now(), time.Now())
+		_, err := parent.Reply("comment-2", "user-2", "reply", 5, time.Now())
+		assert.Equal(t, domain.ErrCommentAlreadyDeleted, err)
+	})
+}
+
+func TestDeleteComment(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should replace the body with a placeholder", func(t *testing.T) {
+		t.Parallel()
+		comment := domain.MustNewComment("comment-1", "post-1", "user-1", "", "comment-1", 0, "body", false, time.Now(), time.Now())
+		err := comment.Delete()
+		assert.Nil(t, err)
+		assert.True(t, comment.IsDeleted())
+		assert.Equal(t, domain.DeletedCommentPlaceholder, comment.Body())
+		assert.Equal(t, "user-1", comment.AuthorId())
+	})
+
+	t.Run("should return correct error if comment is already deleted", func(t *testing.T) {
+		t.Parallel()
+		comment := domain.MustNewComment("comment-1", "post-1", "user-1", "", "comment-1", 0, "", true, time.Now(), time.Now())
+		assert.Equal(t, domain.ErrCommentAlreadyDeleted, comment.Delete())
+		assert.Equal(t, domain.ErrCommentAlreadyDeleted, comment.Edit("new body"))
+	})
+
+	t.Run("should hide the author in the read model", func(t *testing.T) {
+		t.Parallel()
+		comment := domain.MustNewComment("comment-1", "post-1", "user-1", "", "comment-1", 0, "body", false, time.Now(), time.Now())
+		assert.Nil(t, comment.Delete())
+		rm := domain.NewCommentReadModel(comment)
+		assert.Nil(t, rm.AuthorId)
+		assert.True(t, rm.IsDeleted)
+	})
+}
@@ -1 +1,73 @@
 package domain
+
+import "time"
+
+type PostCreated struct {
+	PostId   string
+	AuthorId string
+	Title    string
+	Status   PostStatus
+	Created  time.Time
+}
+
+func (e PostCreated) EventName() string     { return "content.post_created" }
+func (e PostCreated) OccurredAt() time.Time { return e.Created }
+
+type PostEdited struct {
+	PostId   string
+	AuthorId string
+	EditedAt time.Time
+}
+
+func (e PostEdited) EventName() string     { return "content.post_edited" }
+func (e PostEdited) OccurredAt() time.Time { return e.EditedAt }
+
+type PostPublished struct {
+	PostId      string
+	AuthorId    string
+	PublishedAt time.Time
+}
+
+func (e PostPublished) EventName() string     { return "content.post_published" }
+func (e PostPublished) OccurredAt() time.Time { return e.PublishedAt }
+
+type PostDeleted struct {
+	PostId    string
+	AuthorId  string
+	DeletedAt time.Time
+}
+
+func (e PostDeleted) EventName() string     { return "content.post_deleted" }
+func (e PostDeleted) OccurredAt() time.Time { return e.DeletedAt }
+
+type CommentCreated struct {
+	CommentId string
+	PostId    string
+	AuthorId  string
+	// ParentId is empty for top-level comments
+	ParentId string
+	Created  time.Time
+}
+
+func (e CommentCreated) EventName() string     { return "content.comment_created" }
+func (e CommentCreated) OccurredAt() time.Time { return e.Created }
+
+type CommentEdited struct {
+	CommentId string
+	PostId    string
+	AuthorId  string
+	EditedAt  time.Time
+}
+
+func (e CommentEdited) EventName() string     { return "content.comment_edited" }
+func (e CommentEdited) OccurredAt() time.Time { return e.EditedAt }
+
+type CommentDeleted struct {
+	CommentId string
+	PostId    string
+	AuthorId  string
+	DeletedAt time.Time
+}
+
+func (e CommentDeleted) EventName() string     { return "content.comment_deleted" }
+func (e CommentDeleted) OccurredAt() time.Time { return e.DeletedAt }
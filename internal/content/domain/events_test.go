@@ -0,0 +1,92 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostEvents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should raise PostCreated when a post is created", func(t *testing.T) {
+		t.Parallel()
+		createdAt := time.Now()
+		post, err := domain.CreatePost("post-1", "user-1", "title", "body", domain.Draft, createdAt)
+		require.Nil(t, err)
+		assert.Equal(t, []events.Event{domain.PostCreated{
+			PostId:   "post-1",
+			AuthorId: "user-1",
+			Title:    "title",
+			Status:   domain.Draft,
+			Created:  createdAt,
+		}}, post.PullEvents())
+	})
+
+	t.Run("should not raise events when a post is loaded", func(t *testing.T) {
+		t.Parallel()
+		post := createPost(domain.Published)
+		assert.Empty(t, post.PullEvents())
+	})
+
+	t.Run("should raise PostEdited, PostPublished and PostDeleted", func(t *testing.T) {
+		t.Parallel()
+		post := createPost(domain.Draft)
+		require.Nil(t, post.Edit("new title", "new body"))
+		require.Nil(t, post.Publish())
+		require.Nil(t, post.Delete())
+		raised := post.PullEvents()
+		require.Len(t, raised, 3)
+		assert.IsType(t, domain.PostEdited{}, raised[0])
+		assert.IsType(t, domain.PostPublished{}, raised[1])
+		deleted := raised[2].(domain.PostDeleted)
+		assert.Equal(t, "post-1", deleted.PostId)
+		assert.Equal(t, "user-1", deleted.AuthorId)
+		assert.Empty(t, post.PullEvents(), "Events should be cleared once pulled")
+	})
+
+	t.Run("should not raise events when a change is rejected", func(t *testing.T) {
+		t.Parallel()
+		post := createPost(domain.Published)
+		require.Error(t, post.Edit("", "body"))
+		assert.Empty(t, post.PullEvents())
+	})
+}
+
+func TestCommentEvents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should raise CommentCreated for root comments and replies", func(t *testing.T) {
+		t.Parallel()
+		root, err := domain.NewRootComment("comment-1", "post-1", "user-1", "body", time.Now())
+		require.Nil(t, err)
+		reply, err := root.Reply("comment-2", "user-2", "reply", 2, time.Now())
+		require.Nil(t, err)
+
+		raised := root.PullEvents()
+		require.Len(t, raised, 1)
+		assert.Equal(t, "", raised[0].(domain.CommentCreated).ParentId)
+
+		raised = reply.PullEvents()
+		require.Len(t, raised, 1)
+		created := raised[0].(domain.CommentCreated)
+		assert.Equal(t, "comment-1", created.ParentId)
+		assert.Equal(t, "post-1", created.PostId)
+		assert.Equal(t, "user-2", created.AuthorId)
+	})
+
+	t.Run("should raise CommentEdited and CommentDeleted", func(t *testing.T) {
+		t.Parallel()
+		comment := domain.MustNewComment("comment-1", "post-1", "user-1", "", "comment-1", 0, "body", false, time.Now(), time.Now())
+		require.Nil(t, comment.Edit("new body"))
+		require.Nil(t, comment.Delete())
+		raised := comment.PullEvents()
+		require.Len(t, raised, 2)
+		assert.IsType(t, domain.CommentEdited{}, raised[0])
+		assert.IsType(t, domain.CommentDeleted{}, raised[1])
+	})
+}
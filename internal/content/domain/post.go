@@ -1 +1,197 @@
 package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/events"
+)
+
+type PostStatus string
+
+const (
+	Draft     PostStatus = "DRAFT"
+	Published PostStatus = "PUBLISHED"
+	Deleted   PostStatus = "DELETED"
+)
+
+func (s PostStatus) String() string {
+	return string(s)
+}
+
+type Post struct {
+	id        string
+	authorId  string
+	title     string
+	body      string
+	status    PostStatus
+	createdAt time.Time
+	updatedAt time.Time
+	events    events.Recorder
+}
+
+const MaxPostTitleLength = 300
+
+var (
+	ErrPostIdRequired       = errors.New("post id cannot be empty")
+	ErrPostAuthorRequired   = errors.New("post author cannot be empty")
+	ErrPostTitleRequired    = errors.New("post title cannot be empty")
+	ErrPostTitleTooLong     = errors.New("post title is too long")
+	ErrPostBodyRequired     = errors.New("post body cannot be empty")
+	ErrInvalidPostStatus    = errors.New("invalid post status")
+	ErrPostAlreadyPublished = errors.New("post is already published")
+	ErrPostAlreadyDeleted   = errors.New("post is already deleted")
+	ErrPostNotFound         = errors.New("post not found")
+)
+
+func NewPost(id, authorId, title, body string, status PostStatus, createdAt, updatedAt time.Time) (Post, error) {
+	post := Post{}
+	if strings.TrimSpace(id) == "" {
+		return post, ErrPostIdRequired
+	}
+	if strings.TrimSpace(authorId) == "" {
+		return post, ErrPostAuthorRequired
+	}
+	if err := validateTitle(title); err != nil {
+		return post, err
+	}
+	if strings.TrimSpace(body) == "" {
+		return post, ErrPostBodyRequired
+	}
+	if status == "" {
+		status = Published
+	}
+	if !isValidStatus(status) {
+		return post, ErrInvalidPostStatus
+	}
+	return Post{
+		id:        id,
+		authorId:  authorId,
+		title:     title,
+		body:      body,
+		status:    status,
+		createdAt: createdAt,
+		updatedAt: updatedAt,
+	}, nil
+}
+
+// CreatePost writes a new post and raises PostCreated. Posts without a status are published.
+func CreatePost(id, authorId, title, body string, status PostStatus, createdAt time.Time) (Post, error) {
+	post, err := NewPost(id, authorId, title, body, status, createdAt, createdAt)
+	if err != nil {
+		return post, err
+	}
+	post.events.Record(PostCreated{PostId: id, AuthorId: authorId, Title: title, Status: post.status, Created: createdAt})
+	return post, nil
+}
+
+func MustNewPost(id, authorId, title, body string, status PostStatus, createdAt, updatedAt time.Time) Post {
+	post, err := NewPost(id, authorId, title, body, status, createdAt, updatedAt)
+	if err != nil {
+		panic(err.Error())
+	}
+	return post
+}
+
+// Edit replaces the title and body of the post
+func (p *Post) Edit(title, body string) error {
+	if p.status == Deleted {
+		return ErrPostAlreadyDeleted
+	}
+	if err := validateTitle(title); err != nil {
+		return err
+	}
+	if strings.TrimSpace(body) == "" {
+		return ErrPostBodyRequired
+	}
+	p.title = title
+	p.body = body
+	p.updatedAt = time.Now()
+	p.events.Record(PostEdited{PostId: p.id, AuthorId: p.authorId, EditedAt: p.updatedAt})
+	return nil
+}
+
+func (p *Post) Publish() error {
+	switch p.status {
+	case Published:
+		return ErrPostAlreadyPublished
+	case Deleted:
+		return ErrPostAlreadyDeleted
+	}
+	p.status = Published
+	p.updatedAt = time.Now()
+	p.events.Record(PostPublished{PostId: p.id, AuthorId: p.authorId, PublishedAt: p.updatedAt})
+	return nil
+}
+
+// Delete marks the post as deleted. Deleted posts are never returned by the read models.
+func (p *Post) Delete() error {
+	if p.status == Deleted {
+		return ErrPostAlreadyDeleted
+	}
+	p.status = Deleted
+	p.updatedAt = time.Now()
+	p.events.Record(PostDeleted{PostId: p.id, AuthorId: p.authorId, DeletedAt: p.updatedAt})
+	return nil
+}
+
+// ReassignAuthor hands the post over to another author, such as the placeholder of deleted users
+func (p *Post) ReassignAuthor(authorId string) error {
+	if strings.TrimSpace(authorId) == "" {
+		return ErrPostAuthorRequired
+	}
+	p.authorId = authorId
+	return nil
+}
+
+// PullEvents returns the events raised since the post was loaded and clears them
+func (p *Post) PullEvents() []events.Event {
+	return p.events.PullEvents()
+}
+
+func (p *Post) Id() string {
+	return p.id
+}
+
+func (p *Post) AuthorId() string {
+	return p.authorId
+}
+
+func (p *Post) Title() string {
+	return p.title
+}
+
+func (p *Post) Body() string {
+	return p.body
+}
+
+func (p *Post) Status() PostStatus {
+	return p.status
+}
+
+func (p *Post) IsDeleted() bool {
+	return p.status == Deleted
+}
+
+func (p *Post) CreatedAt() time.Time {
+	return p.createdAt
+}
+
+func (p *Post) UpdatedAt() time.Time {
+	return p.updatedAt
+}
+
+func validateTitle(title string) error {
+	if strings.TrimSpace(title) == "" {
+		return ErrPostTitleRequired
+	}
+	if len([]rune(title)) > MaxPostTitleLength {
+		return ErrPostTitleTooLong
+	}
+	return nil
+}
+
+func isValidStatus(status PostStatus) bool {
+	return status == Draft || status == Published || status == Deleted
+}
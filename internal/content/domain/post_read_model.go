@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+type PostReadModel struct {
+	Id        string     `json:"id"`
+	AuthorId  string     `json:"authorId"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Status    PostStatus `json:"status"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidPageSize is returned when posts or comments are listed without asking for at least one
+var ErrInvalidPageSize = errors.New("the number of items to list must be at least one")
+
+type GetPostsOptions struct {
+	First         int32
+	After         string
+	SortDirection string // "ASC" or "DESC"
+	AuthorId      string // optional, restricts the result to a single author
+	IncludeDrafts bool   // optional, returns the drafts along with the published posts
+}
+
+type PostReadModelRepository interface {
+	GetPosts(ctx context.Context, opts GetPostsOptions) (posts []*PostReadModel, hasNext bool, err error)
+	GetPostById(ctx context.Context, id string) (*PostReadModel, error)
+}
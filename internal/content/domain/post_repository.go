@@ -1 +1,11 @@
 package domain
+
+import "context"
+
+type PostRepository interface {
+	CreatePost(ctx context.Context, post Post) error
+	UpdatePost(ctx context.Context, postId string, updateFn func(post *Post) error) error
+	DeletePost(ctx context.Context, postId string, updateFn func(post *Post) error) error
+	// ReassignAuthor hands every post of authorId over to newAuthorId, deleted posts included
+	ReassignAuthor(ctx context.Context, authorId, newAuthorId string) error
+}
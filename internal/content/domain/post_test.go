@@ -0,0 +1,119 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPost(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should default status to published", func(t *testing.T) {
+		t.Parallel()
+		post, err := domain.NewPost("post-1", "user-1", "title", "body", "", time.Now(), time.Now())
+		assert.Nil(t, err)
+		assert.Equal(t, domain.Published, post.Status())
+	})
+
+	t.Run("should return correct error if title is empty", func(t *testing.T) {
+		t.Parallel()
+		_, err := domain.NewPost("post-1", "user-1", " ", "body", domain.Published, time.Now(), time.Now())
+		assert.Equal(t, domain.ErrPostTitleRequired, err)
+	})
+
+	t.Run("should return correct error if title is too long", func(t *testing.T) {
+		t.Parallel()
+		title := strings.Repeat("a", domain.MaxPostTitleLength+1)
+		_, err := domain.NewPost("post-1", "user-1", title, "body", domain.Published, time.Now(), time.Now())
+		assert.Equal(t, domain.ErrPostTitleTooLong, err)
+	})
+
+	t.Run("should return correct error if body is empty", func(t *testing.T) {
+		t.Parallel()
+		_, err := domain.NewPost("post-1", "user-1", "title", "", domain.Published, time.Now(), time.Now())
+		assert.Equal(t, domain.ErrPostBodyRequired, err)
+	})
+
+	t.Run("should return correct error if author is empty", func(t *testing.T) {
+		t.Parallel()
+		_, err := domain.NewPost("post-1", "", "title", "body", domain.Published, time.Now(), time.Now())
+		assert.Equal(t, domain.ErrPostAuthorRequired, err)
+	})
+
+	t.Run("should return correct error if status is invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := domain.NewPost("post-1", "user-1", "title", "body", "ARCHIVED", time.Now(), time.Now())
+		assert.Equal(t, domain.ErrInvalidPostStatus, err)
+	})
+}
+
+func TestEditPost(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should correctly edit post", func(t *testing.T) {
+		t.Parallel()
+		post := createPost(domain.Published)
+		err := post.Edit("new title", "new body")
+		assert.Nil(t, err)
+		assert.Equal(t, "new title", post.Title())
+		assert.Equal(t, "new body", post.Body())
+	})
+
+	t.Run("should not edit a deleted post", func(t *testing.T) {
+		t.Parallel()
+		post := createPost(domain.Deleted)
+		err := post.Edit("new title", "new body")
+		assert.Equal(t, domain.ErrPostAlreadyDeleted, err)
+		assert.Equal(t, "title", post.Title())
+	})
+
+	t.Run("should return correct error if body is empty", func(t *testing.T) {
+		t.Parallel()
+		post := createPost(domain.Published)
+		err := post.Edit("new title", "")
+		assert.Equal(t, domain.ErrPostBodyRequired, err)
+		assert.Equal(t, "title", post.Title())
+	})
+}
+
+func TestPublishPost(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should publish a draft", func(t *testing.T) {
+		t.Parallel()
+		post := createPost(domain.Draft)
+		assert.Nil(t, post.Publish())
+		assert.Equal(t, domain.Published, post.Status())
+	})
+
+	t.Run("should return correct error if post is already published", func(t *testing.T) {
+		t.Parallel()
+		post := createPost(domain.Published)
+		assert.Equal(t, domain.ErrPostAlreadyPublished, post.Publish())
+	})
+}
+
+func TestDeletePost(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should mark post as deleted", func(t *testing.T) {
+		t.Parallel()
+		post := createPost(domain.Published)
+		assert.Nil(t, post.Delete())
+		assert.True(t, post.IsDeleted())
+	})
+
+	t.Run("should return correct error if post is already deleted", func(t *testing.T) {
+		t.Parallel()
+		post := createPost(domain.Deleted)
+		assert.Equal(t, domain.ErrPostAlreadyDeleted, post.Delete())
+	})
+}
+
+func createPost(status domain.PostStatus) domain.Post {
+	return domain.MustNewPost("post-1", "user-1", "title", "body", status, time.Now(), time.Now())
+}
@@ -0,0 +1,160 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+)
+
+var ErrCommentAlreadyExists = errors.New("comment with the same id already exists")
+
+// CommentRepository keeps comments in memory. It implements both domain.CommentRepository
+// and domain.CommentReadModelRepository.
+type CommentRepository struct {
+	mu       sync.RWMutex
+	comments map[string]domain.Comment
+}
+
+func NewCommentRepository() *CommentRepository {
+	return &CommentRepository{comments: map[string]domain.Comment{}}
+}
+
+func (m *CommentRepository) CreateComment(ctx context.Context, comment domain.Comment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.comments[comment.Id()]; ok {
+		return ErrCommentAlreadyExists
+	}
+	m.comments[comment.Id()] = comment
+	return nil
+}
+
+func (m *CommentRepository) EditComment(ctx context.Context, commentId string, updateFn func(comment *domain.Comment) error) error {
+	return m.updateComment(commentId, updateFn)
+}
+
+func (m *CommentRepository) DeleteComment(ctx context.Context, commentId string, updateFn func(comment *domain.Comment) error) error {
+	return m.updateComment(commentId, updateFn)
+}
+
+func (m *CommentRepository) ReassignAuthor(ctx context.Context, authorId, newAuthorId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, comment := range m.comments {
+		if comment.AuthorId() != authorId {
+			continue
+		}
+		if err := comment.ReassignAuthor(newAuthorId); err != nil {
+			return err
+		}
+		m.comments[id] = comment
+	}
+	return nil
+}
+
+func (m *CommentRepository) GetComment(ctx context.Context, commentId string) (*domain.Comment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	comment, ok := m.comments[commentId]
+	if !ok {
+		return nil, domain.ErrCommentNotFound
+	}
+	return &comment, nil
+}
+
+func (m *CommentRepository) GetComments(ctx context.Context, opts domain.GetCommentsOptions) ([]*domain.CommentReadModel, bool, error) {
+	if opts.First <= 0 {
+		return nil, false, domain.ErrInvalidPageSize
+	}
+	asc := true
+	switch opts.SortDirection {
+	case "", "ASC":
+	case "DESC":
+		asc = false
+	default:
+		return nil, false, errors.New("invalid sort direction, must be 'ASC' or 'DESC'")
+	}
+	var after time.Time
+	if opts.After != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, opts.After)
+		if err != nil {
+			return nil, false, err
+		}
+		after = parsed
+	}
+
+	m.mu.RLock()
+	comments := []*domain.CommentReadModel{}
+	for _, comment := range m.comments {
+		if comment.PostId() != opts.PostId || !comment.IsRoot() {
+			continue
+		}
+		if !after.IsZero() {
+			if asc && !comment.CreatedAt().After(after) {
+				continue
+			}
+			if !asc && !comment.CreatedAt().Before(after) {
+				continue
+			}
+		}
+		comments = append(comments, domain.NewCommentReadModel(comment))
+	}
+	m.mu.RUnlock()
+
+	slices.SortFunc(comments, func(a, b *domain.CommentReadModel) int {
+		if asc {
+			return a.CreatedAt.Compare(b.CreatedAt)
+		}
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	hasNext := len(comments) > int(opts.First)
+	if hasNext {
+		comments = comments[:opts.First]
+	}
+	return comments, hasNext, nil
+}
+
+func (m *CommentRepository) GetReplies(ctx context.Context, rootIds []string) ([]*domain.CommentReadModel, error) {
+	m.mu.RLock()
+	replies := []*domain.CommentReadModel{}
+	for _, comment := range m.comments {
+		if comment.IsRoot() || !slices.Contains(rootIds, comment.RootId()) {
+			continue
+		}
+		replies = append(replies, domain.NewCommentReadModel(comment))
+	}
+	m.mu.RUnlock()
+
+	slices.SortFunc(replies, func(a, b *domain.CommentReadModel) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return replies, nil
+}
+
+func (m *CommentRepository) GetCommentById(ctx context.Context, id string) (*domain.CommentReadModel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	comment, ok := m.comments[id]
+	if !ok {
+		return nil, domain.ErrCommentNotFound
+	}
+	return domain.NewCommentReadModel(comment), nil
+}
+
+func (m *CommentRepository) updateComment(commentId string, updateFn func(comment *domain.Comment) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	comment, ok := m.comments[commentId]
+	if !ok {
+		return domain.ErrCommentNotFound
+	}
+	if err := updateFn(&comment); err != nil {
+		return err
+	}
+	m.comments[commentId] = comment
+	return nil
+}
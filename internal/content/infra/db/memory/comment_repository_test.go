@@ -0,0 +1,105 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/content/infra/db/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetComments(t *testing.T) {
+	t.Parallel()
+
+	memRepo := memory.NewCommentRepository()
+	ctx := context.Background()
+	now := time.Now()
+	comments := []domain.Comment{
+		domain.MustNewComment("c1", "post-1", "user-1", "", "c1", 0, "first", false, now, now),
+		domain.MustNewComment("c2", "post-1", "user-2", "", "c2", 0, "second", false, now.Add(time.Minute), now),
+		domain.MustNewComment("c3", "post-1", "user-2", "c1", "c1", 1, "reply", false, now.Add(2*time.Minute), now),
+		domain.MustNewComment("c4", "post-1", "user-1", "c3", "c1", 2, "nested", false, now.Add(3*time.Minute), now),
+		domain.MustNewComment("c5", "post-2", "user-1", "", "c5", 0, "other post", false, now, now),
+	}
+	for _, c := range comments {
+		assert.Nil(t, memRepo.CreateComment(ctx, c))
+	}
+
+	t.Run("should page through top-level comments oldest first", func(t *testing.T) {
+		t.Parallel()
+		result, hasNext, err := memRepo.GetComments(ctx, domain.GetCommentsOptions{PostId: "post-1", First: 1})
+		assert.Nil(t, err)
+		assert.True(t, hasNext)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "c1", result[0].Id)
+
+		result, hasNext, err = memRepo.GetComments(ctx, domain.GetCommentsOptions{
+			PostId: "post-1",
+			First:  1,
+			After:  result[0].CreatedAt.Format(time.RFC3339Nano),
+		})
+		assert.Nil(t, err)
+		assert.False(t, hasNext)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "c2", result[0].Id)
+	})
+
+	t.Run("should return every reply of a thread", func(t *testing.T) {
+		t.Parallel()
+		replies, err := memRepo.GetReplies(ctx, []string{"c1"})
+		assert.Nil(t, err)
+		assert.Len(t, replies, 2)
+		assert.Equal(t, "c3", replies[0].Id)
+		assert.Equal(t, "c4", replies[1].Id)
+	})
+
+	t.Run("should reject fewer than one comment", func(t *testing.T) {
+		t.Parallel()
+		for _, first := range []int32{0, -1} {
+			_, _, err := memRepo.GetComments(ctx, domain.GetCommentsOptions{PostId: "post-1", First: first})
+			assert.ErrorIs(t, err, domain.ErrInvalidPageSize, "first: %d", first)
+		}
+	})
+}
+
+func TestDeleteComment(t *testing.T) {
+	t.Parallel()
+
+	memRepo := memory.NewCommentRepository()
+	ctx := context.Background()
+	comment := domain.MustNewComment("c1", "post-1", "user-1", "", "c1", 0, "body", false, time.Now(), time.Now())
+	assert.Nil(t, memRepo.CreateComment(ctx, comment))
+
+	err := memRepo.DeleteComment(ctx, comment.Id(), func(c *domain.Comment) error {
+		return c.Delete()
+	})
+	assert.Nil(t, err)
+
+	// Deleted comments stay in the thread as placeholders
+	saved, err := memRepo.GetCommentById(ctx, comment.Id())
+	assert.Nil(t, err)
+	assert.True(t, saved.IsDeleted)
+	assert.Equal(t, domain.DeletedCommentPlaceholder, saved.Body)
+	assert.Nil(t, saved.AuthorId)
+}
+
+func TestReassignCommentAuthor(t *testing.T) {
+	t.Parallel()
+
+	memRepo := memory.NewCommentRepository()
+	ctx := context.Background()
+	now := time.Now()
+	assert.Nil(t, memRepo.CreateComment(ctx, domain.MustNewComment("c1", "post-1", "user-1", "", "c1", 0, "first", false, now, now)))
+	assert.Nil(t, memRepo.CreateComment(ctx, domain.MustNewComment("c2", "post-1", "user-2", "c1", "c1", 1, "reply", false, now, now)))
+
+	assert.Nil(t, memRepo.ReassignAuthor(ctx, "user-1", "deleted-user"))
+
+	comment, err := memRepo.GetCommentById(ctx, "c1")
+	assert.Nil(t, err)
+	assert.Equal(t, "deleted-user", *comment.AuthorId)
+	comment, err = memRepo.GetCommentById(ctx, "c2")
+	assert.Nil(t, err)
+	assert.Equal(t, "user-2", *comment.AuthorId)
+}
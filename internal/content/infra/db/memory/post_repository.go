@@ -1 +1,148 @@
 package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+)
+
+var ErrPostAlreadyExists = errors.New("post with the same id already exists")
+
+// PostRepository keeps posts in memory. It implements both domain.PostRepository
+// and domain.PostReadModelRepository.
+type PostRepository struct {
+	mu    sync.RWMutex
+	posts map[string]domain.Post
+}
+
+func NewPostRepository() *PostRepository {
+	return &PostRepository{posts: map[string]domain.Post{}}
+}
+
+func (m *PostRepository) CreatePost(ctx context.Context, post domain.Post) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.posts[post.Id()]; ok {
+		return ErrPostAlreadyExists
+	}
+	m.posts[post.Id()] = post
+	return nil
+}
+
+func (m *PostRepository) UpdatePost(ctx context.Context, postId string, updateFn func(post *domain.Post) error) error {
+	return m.updatePost(postId, updateFn)
+}
+
+func (m *PostRepository) DeletePost(ctx context.Context, postId string, updateFn func(post *domain.Post) error) error {
+	return m.updatePost(postId, updateFn)
+}
+
+func (m *PostRepository) ReassignAuthor(ctx context.Context, authorId, newAuthorId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, post := range m.posts {
+		if post.AuthorId() != authorId {
+			continue
+		}
+		if err := post.ReassignAuthor(newAuthorId); err != nil {
+			return err
+		}
+		m.posts[id] = post
+	}
+	return nil
+}
+
+func (m *PostRepository) GetPostById(ctx context.Context, id string) (*domain.PostReadModel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	post, ok := m.posts[id]
+	if !ok || post.IsDeleted() {
+		return nil, domain.ErrPostNotFound
+	}
+	return toReadModel(post), nil
+}
+
+func (m *PostRepository) GetPosts(ctx context.Context, opts domain.GetPostsOptions) ([]*domain.PostReadModel, bool, error) {
+	if opts.First <= 0 {
+		return nil, false, domain.ErrInvalidPageSize
+	}
+	asc := false
+	switch opts.SortDirection {
+	case "", "DESC":
+	case "ASC":
+		asc = true
+	default:
+		return nil, false, errors.New("invalid sort direction, must be 'ASC' or 'DESC'")
+	}
+	var after time.Time
+	if opts.After != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, opts.After)
+		if err != nil {
+			return nil, false, err
+		}
+		after = parsed
+	}
+
+	m.mu.RLock()
+	posts := []*domain.PostReadModel{}
+	for _, post := range m.posts {
+		if post.Status() != domain.Published && !(opts.IncludeDrafts && post.Status() == domain.Draft) {
+			continue
+		}
+		if opts.AuthorId != "" && post.AuthorId() != opts.AuthorId {
+			continue
+		}
+		if !after.IsZero() {
+			if asc && !post.CreatedAt().After(after) {
+				continue
+			}
+			if !asc && !post.CreatedAt().Before(after) {
+				continue
+			}
+		}
+		posts = append(posts, toReadModel(post))
+	}
+	m.mu.RUnlock()
+
+	slices.SortFunc(posts, func(a, b *domain.PostReadModel) int {
+		if asc {
+			return a.CreatedAt.Compare(b.CreatedAt)
+		}
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	hasNext := len(posts) > int(opts.First)
+	if hasNext {
+		posts = posts[:opts.First]
+	}
+	return posts, hasNext, nil
+}
+
+func (m *PostRepository) updatePost(postId string, updateFn func(post *domain.Post) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	post, ok := m.posts[postId]
+	if !ok || post.IsDeleted() {
+		return domain.ErrPostNotFound
+	}
+	if err := updateFn(&post); err != nil {
+		return err
+	}
+	m.posts[postId] = post
+	return nil
+}
+
+func toReadModel(post domain.Post) *domain.PostReadModel {
+	return &domain.PostReadModel{
+		Id:        post.Id(),
+		AuthorId:  post.AuthorId(),
+		Title:     post.Title(),
+		Body:      post.Body(),
+		Status:    post.Status(),
+		CreatedAt: post.CreatedAt(),
+		UpdatedAt: post.UpdatedAt(),
+	}
+}
@@ -0,0 +1,142 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/content/infra/db/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePost(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should be able to create post", func(t *testing.T) {
+		t.Parallel()
+		post := domain.MustNewPost("post-id", "author-id", "title", "body", domain.Published, time.Now(), time.Now())
+		memRepo := memory.NewPostRepository()
+		ctx := context.Background()
+
+		err := memRepo.CreatePost(ctx, post)
+
+		assert.Nil(t, err)
+		savedPost, err := memRepo.GetPostById(ctx, post.Id())
+		assert.Nil(t, err)
+		assert.Equal(t, post.Title(), savedPost.Title)
+	})
+
+	t.Run("should return correct error if post already exists", func(t *testing.T) {
+		t.Parallel()
+		post := domain.MustNewPost("post-id", "author-id", "title", "body", domain.Published, time.Now(), time.Now())
+		memRepo := memory.NewPostRepository()
+		ctx := context.Background()
+
+		err := memRepo.CreatePost(ctx, post)
+		assert.Nil(t, err)
+
+		err = memRepo.CreatePost(ctx, post)
+		assert.ErrorIs(t, err, memory.ErrPostAlreadyExists)
+	})
+}
+
+func TestDeletePost(t *testing.T) {
+	t.Parallel()
+
+	post := domain.MustNewPost("post-id", "author-id", "title", "body", domain.Published, time.Now(), time.Now())
+	memRepo := memory.NewPostRepository()
+	ctx := context.Background()
+	assert.Nil(t, memRepo.CreatePost(ctx, post))
+
+	err := memRepo.DeletePost(ctx, post.Id(), func(p *domain.Post) error {
+		return p.Delete()
+	})
+	assert.Nil(t, err)
+
+	_, err = memRepo.GetPostById(ctx, post.Id())
+	assert.ErrorIs(t, err, domain.ErrPostNotFound)
+
+	err = memRepo.UpdatePost(ctx, post.Id(), func(p *domain.Post) error { return nil })
+	assert.ErrorIs(t, err, domain.ErrPostNotFound)
+}
+
+func TestGetPosts(t *testing.T) {
+	t.Parallel()
+
+	memRepo := memory.NewPostRepository()
+	ctx := context.Background()
+	now := time.Now()
+	posts := []domain.Post{
+		domain.MustNewPost("post-1", "author-1", "title 1", "body", domain.Published, now.Add(-3*time.Hour), now),
+		domain.MustNewPost("post-2", "author-2", "title 2", "body", domain.Published, now.Add(-2*time.Hour), now),
+		domain.MustNewPost("post-3", "author-1", "title 3", "body", domain.Draft, now.Add(-1*time.Hour), now),
+		domain.MustNewPost("post-4", "author-1", "title 4", "body", domain.Published, now, now),
+	}
+	for _, p := range posts {
+		assert.Nil(t, memRepo.CreatePost(ctx, p))
+	}
+
+	t.Run("should return published posts newest first", func(t *testing.T) {
+		t.Parallel()
+		result, hasNext, err := memRepo.GetPosts(ctx, domain.GetPostsOptions{First: 2})
+		assert.Nil(t, err)
+		assert.True(t, hasNext)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "post-4", result[0].Id)
+		assert.Equal(t, "post-2", result[1].Id)
+
+		result, hasNext, err = memRepo.GetPosts(ctx, domain.GetPostsOptions{
+			First: 2,
+			After: result[1].CreatedAt.Format(time.RFC3339Nano),
+		})
+		assert.Nil(t, err)
+		assert.False(t, hasNext)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "post-1", result[0].Id)
+	})
+
+	t.Run("should filter by author", func(t *testing.T) {
+		t.Parallel()
+		result, hasNext, err := memRepo.GetPosts(ctx, domain.GetPostsOptions{First: 10, AuthorId: "author-1", SortDirection: "ASC"})
+		assert.Nil(t, err)
+		assert.False(t, hasNext)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "post-1", result[0].Id)
+		assert.Equal(t, "post-4", result[1].Id)
+	})
+
+	t.Run("should include the drafts of the author when asked", func(t *testing.T) {
+		t.Parallel()
+		result, _, err := memRepo.GetPosts(ctx, domain.GetPostsOptions{First: 10, AuthorId: "author-1", SortDirection: "ASC", IncludeDrafts: true})
+		assert.Nil(t, err)
+		assert.Len(t, result, 3)
+		assert.Equal(t, "post-3", result[1].Id)
+	})
+
+	t.Run("should reject fewer than one post", func(t *testing.T) {
+		t.Parallel()
+		for _, first := range []int32{0, -1} {
+			_, _, err := memRepo.GetPosts(ctx, domain.GetPostsOptions{First: first})
+			assert.ErrorIs(t, err, domain.ErrInvalidPageSize, "first: %d", first)
+		}
+	})
+}
+
+func TestReassignPostAuthor(t *testing.T) {
+	t.Parallel()
+
+	memRepo := memory.NewPostRepository()
+	ctx := context.Background()
+	assert.Nil(t, memRepo.CreatePost(ctx, domain.MustNewPost("post-1", "author-1", "title 1", "body", domain.Published, time.Now(), time.Now())))
+	assert.Nil(t, memRepo.CreatePost(ctx, domain.MustNewPost("post-2", "author-2", "title 2", "body", domain.Published, time.Now(), time.Now())))
+
+	assert.Nil(t, memRepo.ReassignAuthor(ctx, "author-1", "deleted-user"))
+
+	post, err := memRepo.GetPostById(ctx, "post-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "deleted-user", post.AuthorId)
+	post, err = memRepo.GetPostById(ctx, "post-2")
+	assert.Nil(t, err)
+	assert.Equal(t, "author-2", post.AuthorId)
+}
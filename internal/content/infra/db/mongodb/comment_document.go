@@ -0,0 +1,42 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+)
+
+// commentDocument represents how a comment is stored in MongoDB
+type commentDocument struct {
+	ID        string    `bson:"_id"`
+	PostID    string    `bson:"postId"`
+	AuthorID  string    `bson:"authorId"`
+	ParentID  string    `bson:"parentId,omitempty"`
+	RootID    string    `bson:"rootId"`
+	Depth     int       `bson:"depth"`
+	Body      string    `bson:"body"`
+	IsDeleted bool      `bson:"isDeleted"`
+	CreatedAt time.Time `bson:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+// commentFromDomain converts a domain Comment to commentDocument
+func commentFromDomain(comment domain.Comment) commentDocument {
+	return commentDocument{
+		ID:        comment.Id(),
+		PostID:    comment.PostId(),
+		AuthorID:  comment.AuthorId(),
+		ParentID:  comment.ParentId(),
+		RootID:    comment.RootId(),
+		Depth:     comment.Depth(),
+		Body:      comment.Body(),
+		IsDeleted: comment.IsDeleted(),
+		CreatedAt: comment.CreatedAt(),
+		UpdatedAt: comment.UpdatedAt(),
+	}
+}
+
+// toDomain converts a commentDocument to domain Comment
+func (c commentDocument) toDomain() domain.Comment {
+	return domain.MustNewComment(c.ID, c.PostID, c.AuthorID, c.ParentID, c.RootID, c.Depth, c.Body, c.IsDeleted, c.CreatedAt, c.UpdatedAt)
+}
@@ -0,0 +1,110 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CommentReadModelRepository implements the domain.CommentReadModelRepository interface
+type CommentReadModelRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCommentReadModelRepository(db *mongo.Database) *CommentReadModelRepository {
+	return &CommentReadModelRepository{
+		collection: db.Collection("comments"),
+	}
+}
+
+// GetComments retrieves paginated top-level comments of a post sorted by createdAt
+func (r *CommentReadModelRepository) GetComments(ctx context.Context, opts domain.GetCommentsOptions) ([]*domain.CommentReadModel, bool, error) {
+	if opts.First <= 0 {
+		return nil, false, domain.ErrInvalidPageSize
+	}
+	sortValue, comparison := 1, "$gt" // Oldest first by default
+	switch opts.SortDirection {
+	case "", "ASC":
+	case "DESC":
+		sortValue, comparison = -1, "$lt"
+	default:
+		return nil, false, errors.New("invalid sort direction, must be 'ASC' or 'DESC'")
+	}
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(opts.First + 1)) //Fetch one more to determine if there are more results
+	findOptions.SetSort(bson.D{{Key: "createdAt", Value: sortValue}})
+
+	filter := bson.M{"postId": opts.PostId, "depth": 0}
+	if opts.After != "" {
+		createdAt, err := time.Parse(time.RFC3339Nano, opts.After)
+		if err != nil {
+			return nil, false, err
+		}
+		filter["createdAt"] = bson.M{comparison: createdAt}
+	}
+
+	docs, err := r.find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, false, err
+	}
+	hasNext := false
+	if len(docs) > int(opts.First) {
+		hasNext = true
+		docs = docs[:opts.First]
+	}
+	return documentsToCommentReadModels(docs), hasNext, nil
+}
+
+// GetReplies retrieves every reply of the given threads, oldest first
+func (r *CommentReadModelRepository) GetReplies(ctx context.Context, rootIds []string) ([]*domain.CommentReadModel, error) {
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "createdAt", Value: 1}})
+
+	filter := bson.M{"rootId": bson.M{"$in": rootIds}, "depth": bson.M{"$gt": 0}}
+	docs, err := r.find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	return documentsToCommentReadModels(docs), nil
+}
+
+// GetCommentById finds a comment by its id
+func (r *CommentReadModelRepository) GetCommentById(ctx context.Context, id string) (*domain.CommentReadModel, error) {
+	var doc commentDocument
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return domain.NewCommentReadModel(doc.toDomain()), nil
+}
+
+func (r *CommentReadModelRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]commentDocument, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []commentDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func documentsToCommentReadModels(docs []commentDocument) []*domain.CommentReadModel {
+	comments := make([]*domain.CommentReadModel, 0, len(docs))
+	for _, doc := range docs {
+		comments = append(comments, domain.NewCommentReadModel(doc.toDomain()))
+	}
+	return comments
+}
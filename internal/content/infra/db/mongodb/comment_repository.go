@@ -0,0 +1,70 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CommentRepository implements the domain.CommentRepository interface
+type CommentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCommentRepository(db *mongo.Database) *CommentRepository {
+	return &CommentRepository{
+		collection: db.Collection("comments"),
+	}
+}
+
+// CreateComment adds a new comment to the database
+func (r *CommentRepository) CreateComment(ctx context.Context, comment domain.Comment) error {
+	_, err := r.collection.InsertOne(ctx, commentFromDomain(comment))
+	return err
+}
+
+// ReassignAuthor hands every comment of an author over to another one
+func (r *CommentRepository) ReassignAuthor(ctx context.Context, authorId, newAuthorId string) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{"authorId": authorId}, bson.M{"$set": bson.M{"authorId": newAuthorId}})
+	return err
+}
+
+// EditComment applies updateFn to an existing comment
+func (r *CommentRepository) EditComment(ctx context.Context, commentId string, updateFn func(comment *domain.Comment) error) error {
+	return r.getAndUpdateComment(ctx, commentId, updateFn)
+}
+
+// DeleteComment soft-deletes a comment through updateFn
+func (r *CommentRepository) DeleteComment(ctx context.Context, commentId string, updateFn func(comment *domain.Comment) error) error {
+	return r.getAndUpdateComment(ctx, commentId, updateFn)
+}
+
+// GetComment finds a comment by its id
+func (r *CommentRepository) GetComment(ctx context.Context, commentId string) (*domain.Comment, error) {
+	var doc commentDocument
+	err := r.collection.FindOne(ctx, bson.M{"_id": commentId}).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	comment := doc.toDomain()
+	return &comment, nil
+}
+
+// getAndUpdateComment is a helper function for updating comment documents
+func (r *CommentRepository) getAndUpdateComment(ctx context.Context, commentId string, updateFn func(comment *domain.Comment) error) error {
+	comment, err := r.GetComment(ctx, commentId)
+	if err != nil {
+		return err
+	}
+	if err := updateFn(comment); err != nil {
+		return err
+	}
+	_, err = r.collection.ReplaceOne(ctx, bson.M{"_id": commentId}, commentFromDomain(*comment))
+	return err
+}
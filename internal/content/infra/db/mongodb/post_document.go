@@ -0,0 +1,49 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+)
+
+// postDocument represents how a post is stored in MongoDB
+type postDocument struct {
+	ID        string    `bson:"_id"`
+	AuthorID  string    `bson:"authorId"`
+	Title     string    `bson:"title"`
+	Body      string    `bson:"body"`
+	Status    string    `bson:"status"`
+	CreatedAt time.Time `bson:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+// fromDomain converts a domain Post to postDocument
+func fromDomain(post domain.Post) postDocument {
+	return postDocument{
+		ID:        post.Id(),
+		AuthorID:  post.AuthorId(),
+		Title:     post.Title(),
+		Body:      post.Body(),
+		Status:    post.Status().String(),
+		CreatedAt: post.CreatedAt(),
+		UpdatedAt: post.UpdatedAt(),
+	}
+}
+
+// toDomain converts a postDocument to domain Post
+func (p postDocument) toDomain() domain.Post {
+	return domain.MustNewPost(p.ID, p.AuthorID, p.Title, p.Body, domain.PostStatus(p.Status), p.CreatedAt, p.UpdatedAt)
+}
+
+// documentToReadModel converts postDocument to PostReadModel
+func documentToReadModel(doc postDocument) *domain.PostReadModel {
+	return &domain.PostReadModel{
+		Id:        doc.ID,
+		AuthorId:  doc.AuthorID,
+		Title:     doc.Title,
+		Body:      doc.Body,
+		Status:    domain.PostStatus(doc.Status),
+		CreatedAt: doc.CreatedAt,
+		UpdatedAt: doc.UpdatedAt,
+	}
+}
@@ -0,0 +1,92 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PostReadModelRepository implements the domain.PostReadModelRepository interface
+type PostReadModelRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPostReadModelRepository(db *mongo.Database) *PostReadModelRepository {
+	return &PostReadModelRepository{
+		collection: db.Collection("posts"),
+	}
+}
+
+// GetPosts retrieves paginated published posts sorted by createdAt
+func (r *PostReadModelRepository) GetPosts(ctx context.Context, opts domain.GetPostsOptions) ([]*domain.PostReadModel, bool, error) {
+	if opts.First <= 0 {
+		return nil, false, domain.ErrInvalidPageSize
+	}
+	sortValue, comparison := -1, "$lt" // Newest first by default
+	switch opts.SortDirection {
+	case "", "DESC":
+	case "ASC":
+		sortValue, comparison = 1, "$gt"
+	default:
+		return nil, false, errors.New("invalid sort direction, must be 'ASC' or 'DESC'")
+	}
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(opts.First + 1)) //Fetch one more to determine if there are more results
+	findOptions.SetSort(bson.D{{Key: "createdAt", Value: sortValue}})
+
+	filter := bson.M{"status": domain.Published.String()}
+	if opts.IncludeDrafts {
+		filter["status"] = bson.M{"$in": []string{domain.Published.String(), domain.Draft.String()}}
+	}
+	if opts.After != "" {
+		createdAt, err := time.Parse(time.RFC3339Nano, opts.After)
+		if err != nil {
+			return nil, false, err
+		}
+		filter["createdAt"] = bson.M{comparison: createdAt}
+	}
+	if opts.AuthorId != "" {
+		filter["authorId"] = opts.AuthorId
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, false, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []postDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, false, err
+	}
+	hasNext := false
+	if len(docs) > int(opts.First) {
+		hasNext = true
+		docs = docs[:opts.First]
+	}
+	var posts []*domain.PostReadModel
+	for _, doc := range docs {
+		posts = append(posts, documentToReadModel(doc))
+	}
+	return posts, hasNext, nil
+}
+
+// GetPostById finds a post that has not been deleted by its id
+func (r *PostReadModelRepository) GetPostById(ctx context.Context, id string) (*domain.PostReadModel, error) {
+	var doc postDocument
+	filter := bson.M{"_id": id, "status": bson.M{"$ne": domain.Deleted.String()}}
+	err := r.collection.FindOne(ctx, filter).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrPostNotFound
+		}
+		return nil, err
+	}
+	return documentToReadModel(doc), nil
+}
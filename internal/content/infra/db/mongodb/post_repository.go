@@ -1 +1,62 @@
 package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PostRepository implements the domain.PostRepository interface
+type PostRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPostRepository(db *mongo.Database) *PostRepository {
+	return &PostRepository{
+		collection: db.Collection("posts"),
+	}
+}
+
+// CreatePost adds a new post to the database
+func (r *PostRepository) CreatePost(ctx context.Context, post domain.Post) error {
+	_, err := r.collection.InsertOne(ctx, fromDomain(post))
+	return err
+}
+
+// UpdatePost applies updateFn to an existing post
+func (r *PostRepository) UpdatePost(ctx context.Context, postId string, updateFn func(post *domain.Post) error) error {
+	return r.getAndUpdatePost(ctx, postId, updateFn)
+}
+
+// DeletePost soft-deletes a post through updateFn
+func (r *PostRepository) DeletePost(ctx context.Context, postId string, updateFn func(post *domain.Post) error) error {
+	return r.getAndUpdatePost(ctx, postId, updateFn)
+}
+
+// ReassignAuthor hands every post of an author over to another one
+func (r *PostRepository) ReassignAuthor(ctx context.Context, authorId, newAuthorId string) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{"authorId": authorId}, bson.M{"$set": bson.M{"authorId": newAuthorId}})
+	return err
+}
+
+// getAndUpdatePost is a helper function for updating post documents
+func (r *PostRepository) getAndUpdatePost(ctx context.Context, postId string, updateFn func(post *domain.Post) error) error {
+	var doc postDocument
+	filter := bson.M{"_id": postId, "status": bson.M{"$ne": domain.Deleted.String()}}
+	err := r.collection.FindOne(ctx, filter).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.ErrPostNotFound
+		}
+		return err
+	}
+	post := doc.toDomain()
+	if err := updateFn(&post); err != nil {
+		return err
+	}
+	_, err = r.collection.ReplaceOne(ctx, bson.M{"_id": postId}, fromDomain(post))
+	return err
+}
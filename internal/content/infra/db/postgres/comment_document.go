@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// commentDocument represents how a comment is stored in Postgres
+type commentDocument struct {
+	ID        string    `db:"id"`
+	PostID    string    `db:"post_id"`
+	AuthorID  string    `db:"author_id"`
+	ParentID  *string   `db:"parent_id"`
+	RootID    string    `db:"root_id"`
+	Depth     int       `db:"depth"`
+	Body      string    `db:"body"`
+	IsDeleted bool      `db:"is_deleted"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+const commentColumns = `id, post_id, author_id, parent_id, root_id, depth, body, is_deleted, created_at, updated_at`
+
+// toDomain converts a commentDocument to a domain.Comment
+func (c *commentDocument) toDomain() domain.Comment {
+	parentId := ""
+	if c.ParentID != nil {
+		parentId = *c.ParentID
+	}
+	return domain.MustNewComment(
+		c.ID,
+		c.PostID,
+		c.AuthorID,
+		parentId,
+		c.RootID,
+		c.Depth,
+		c.Body,
+		c.IsDeleted,
+		c.CreatedAt,
+		c.UpdatedAt,
+	)
+}
+
+func scanCommentRow(row pgx.Row, doc *commentDocument) error {
+	err := row.Scan(
+		&doc.ID,
+		&doc.PostID,
+		&doc.AuthorID,
+		&doc.ParentID,
+		&doc.RootID,
+		&doc.Depth,
+		&doc.Body,
+		&doc.IsDeleted,
+		&doc.CreatedAt,
+		&doc.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrCommentNotFound
+		}
+		return err
+	}
+	return nil
+}
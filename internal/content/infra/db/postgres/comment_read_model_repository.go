@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CommentReadModelRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCommentReadModelRepository(db *pgxpool.Pool) *CommentReadModelRepository {
+	return &CommentReadModelRepository{db: db}
+}
+
+// GetComments retrieves paginated top-level comments of a post sorted by created_at
+func (r *CommentReadModelRepository) GetComments(ctx context.Context, opts domain.GetCommentsOptions) (comments []*domain.CommentReadModel, hasNext bool, err error) {
+	if opts.First <= 0 {
+		return nil, false, domain.ErrInvalidPageSize
+	}
+	sortDirection := "ASC" // Oldest first by default
+	if opts.SortDirection != "" {
+		if opts.SortDirection != "ASC" && opts.SortDirection != "DESC" {
+			return nil, false, errors.New("invalid sort direction, must be 'ASC' or 'DESC'")
+		}
+		sortDirection = opts.SortDirection
+	}
+
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM comments
+        WHERE post_id = $1
+            AND parent_id IS NULL
+            AND ($2::TIMESTAMP IS NULL OR created_at %s $2)
+        ORDER BY created_at %s
+        LIMIT $3
+    `, commentColumns, getComparisonOperator(sortDirection), sortDirection)
+
+	var afterTimestamp *time.Time
+	if opts.After != "" {
+		parsedTime, err := time.Parse(time.RFC3339Nano, opts.After)
+		if err != nil {
+			return nil, false, errors.New("invalid after timestamp format")
+		}
+		afterTimestamp = &parsedTime
+	}
+
+	// Fetch one extra row to check for "hasNext"
+	rows, err := r.db.Query(ctx, query, opts.PostId, afterTimestamp, opts.First+1)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var doc commentDocument
+		if err := scanCommentRow(rows, &doc); err != nil {
+			return nil, false, err
+		}
+		comments = append(comments, domain.NewCommentReadModel(doc.toDomain()))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	hasNext = len(comments) > int(opts.First)
+	if hasNext {
+		comments = comments[:opts.First]
+	}
+	return comments, hasNext, nil
+}
+
+// GetReplies retrieves every reply of the given threads, oldest first
+func (r *CommentReadModelRepository) GetReplies(ctx context.Context, rootIds []string) ([]*domain.CommentReadModel, error) {
+	query := `
+        SELECT ` + commentColumns + `
+        FROM comments
+        WHERE root_id = ANY($1) AND parent_id IS NOT NULL
+        ORDER BY created_at ASC
+    `
+	rows, err := r.db.Query(ctx, query, rootIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var replies []*domain.CommentReadModel
+	for rows.Next() {
+		var doc commentDocument
+		if err := scanCommentRow(rows, &doc); err != nil {
+			return nil, err
+		}
+		replies = append(replies, domain.NewCommentReadModel(doc.toDomain()))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return replies, nil
+}
+
+func (r *CommentReadModelRepository) GetCommentById(ctx context.Context, id string) (*domain.CommentReadModel, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE id = $1`
+	var doc commentDocument
+	if err := scanCommentRow(r.db.QueryRow(ctx, query, id), &doc); err != nil {
+		return nil, err
+	}
+	return domain.NewCommentReadModel(doc.toDomain()), nil
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CommentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCommentRepository(db *pgxpool.Pool) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+func (r *CommentRepository) CreateComment(ctx context.Context, comment domain.Comment) error {
+	query := `
+        INSERT INTO comments (id, post_id, author_id, parent_id, root_id, depth, body, is_deleted, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+    `
+	var parentId *string
+	if !comment.IsRoot() {
+		id := comment.ParentId()
+		parentId = &id
+	}
+	_, err := r.db.Exec(ctx, query,
+		comment.Id(),
+		comment.PostId(),
+		comment.AuthorId(),
+		parentId,
+		comment.RootId(),
+		comment.Depth(),
+		comment.Body(),
+		comment.IsDeleted(),
+		comment.CreatedAt(),
+		comment.UpdatedAt(),
+	)
+	return err
+}
+
+func (r *CommentRepository) EditComment(ctx context.Context, commentId string, updateFn func(comment *domain.Comment) error) error {
+	return r.updateComment(ctx, commentId, updateFn)
+}
+
+func (r *CommentRepository) DeleteComment(ctx context.Context, commentId string, updateFn func(comment *domain.Comment) error) error {
+	return r.updateComment(ctx, commentId, updateFn)
+}
+
+func (r *CommentRepository) GetComment(ctx context.Context, commentId string) (*domain.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE id = $1`
+	var doc commentDocument
+	if err := scanCommentRow(r.db.QueryRow(ctx, query, commentId), &doc); err != nil {
+		return nil, err
+	}
+	comment := doc.toDomain()
+	return &comment, nil
+}
+
+func (r *CommentRepository) ReassignAuthor(ctx context.Context, authorId, newAuthorId string) error {
+	_, err := r.db.Exec(ctx, `UPDATE comments SET author_id = $2 WHERE author_id = $1`, authorId, newAuthorId)
+	return err
+}
+
+func (r *CommentRepository) updateComment(ctx context.Context, commentId string, updateFn func(comment *domain.Comment) error) error {
+	comment, err := r.GetComment(ctx, commentId)
+	if err != nil {
+		return err
+	}
+	if err := updateFn(comment); err != nil {
+		return err
+	}
+	update := `
+        UPDATE comments
+        SET body = $1, is_deleted = $2, updated_at = $3
+        WHERE id = $4
+    `
+	_, err = r.db.Exec(ctx, update,
+		comment.Body(),
+		comment.IsDeleted(),
+		time.Now(),
+		comment.Id(),
+	)
+	return err
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// postDocument represents how a post is stored in Postgres
+type postDocument struct {
+	ID        string    `db:"id"`
+	AuthorID  string    `db:"author_id"`
+	Title     string    `db:"title"`
+	Body      string    `db:"body"`
+	Status    string    `db:"status"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+const postColumns = `id, author_id, title, body, status, created_at, updated_at`
+
+// Helper function to determine the comparison operator based on sort direction
+func getComparisonOperator(sortDirection string) string {
+	if sortDirection == "ASC" {
+		return ">"
+	}
+	return "<"
+}
+
+// toDomain converts a postDocument to a domain.Post
+func (p *postDocument) toDomain() domain.Post {
+	return domain.MustNewPost(
+		p.ID,
+		p.AuthorID,
+		p.Title,
+		p.Body,
+		domain.PostStatus(p.Status),
+		p.CreatedAt,
+		p.UpdatedAt,
+	)
+}
+
+// documentToReadModel converts postDocument to PostReadModel
+func documentToReadModel(doc postDocument) *domain.PostReadModel {
+	return &domain.PostReadModel{
+		Id:        doc.ID,
+		AuthorId:  doc.AuthorID,
+		Title:     doc.Title,
+		Body:      doc.Body,
+		Status:    domain.PostStatus(doc.Status),
+		CreatedAt: doc.CreatedAt,
+		UpdatedAt: doc.UpdatedAt,
+	}
+}
+
+func scanPostRow(row pgx.Row, doc *postDocument) error {
+	err := row.Scan(
+		&doc.ID,
+		&doc.AuthorID,
+		&doc.Title,
+		&doc.Body,
+		&doc.Status,
+		&doc.CreatedAt,
+		&doc.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrPostNotFound
+		}
+		return err
+	}
+	return nil
+}
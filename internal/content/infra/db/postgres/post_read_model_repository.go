@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostReadModelRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostReadModelRepository(db *pgxpool.Pool) *PostReadModelRepository {
+	return &PostReadModelRepository{db: db}
+}
+
+// GetPosts retrieves paginated published posts, and drafts if asked for, sorted by created_at
+func (r *PostReadModelRepository) GetPosts(ctx context.Context, opts domain.GetPostsOptions) (posts []*domain.PostReadModel, hasNext bool, err error) {
+	if opts.First <= 0 {
+		return nil, false, domain.ErrInvalidPageSize
+	}
+	sortDirection := "DESC" // Newest first by default
+	if opts.SortDirection != "" {
+		if opts.SortDirection != "ASC" && opts.SortDirection != "DESC" {
+			return nil, false, errors.New("invalid sort direction, must be 'ASC' or 'DESC'")
+		}
+		sortDirection = opts.SortDirection
+	}
+
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM posts
+        WHERE (status = $1 OR ($5 AND status = $6))
+            AND ($2::TIMESTAMP IS NULL OR created_at %s $2)
+            AND ($3 = '' OR author_id = $3)
+        ORDER BY created_at %s
+        LIMIT $4
+    `, postColumns, getComparisonOperator(sortDirection), sortDirection)
+
+	var afterTimestamp *time.Time
+	if opts.After != "" {
+		parsedTime, err := time.Parse(time.RFC3339Nano, opts.After)
+		if err != nil {
+			return nil, false, errors.New("invalid after timestamp format")
+		}
+		afterTimestamp = &parsedTime
+	}
+
+	// Fetch one extra row to check for "hasNext"
+	rows, err := r.db.Query(ctx, query, domain.Published.String(), afterTimestamp, opts.AuthorId, opts.First+1, opts.IncludeDrafts, domain.Draft.String())
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var doc postDocument
+		if err := scanPostRow(rows, &doc); err != nil {
+			return nil, false, err
+		}
+		posts = append(posts, documentToReadModel(doc))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	hasNext = len(posts) > int(opts.First)
+	if hasNext {
+		posts = posts[:opts.First]
+	}
+	return posts, hasNext, nil
+}
+
+func (r *PostReadModelRepository) GetPostById(ctx context.Context, id string) (*domain.PostReadModel, error) {
+	query := `SELECT ` + postColumns + ` FROM posts WHERE id = $1 AND status <> $2`
+	var doc postDocument
+	if err := scanPostRow(r.db.QueryRow(ctx, query, id, domain.Deleted.String()), &doc); err != nil {
+		return nil, err
+	}
+	return documentToReadModel(doc), nil
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostRepository(db *pgxpool.Pool) *PostRepository {
+	return &PostRepository{db: db}
+}
+
+func (r *PostRepository) CreatePost(ctx context.Context, post domain.Post) error {
+	query := `
+        INSERT INTO posts (id, author_id, title, body, status, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+    `
+	_, err := r.db.Exec(ctx, query,
+		post.Id(),
+		post.AuthorId(),
+		post.Title(),
+		post.Body(),
+		post.Status().String(),
+		post.CreatedAt(),
+		post.UpdatedAt(),
+	)
+	return err
+}
+
+func (r *PostRepository) UpdatePost(ctx context.Context, postId string, updateFn func(post *domain.Post) error) error {
+	return r.updatePost(ctx, postId, updateFn)
+}
+
+func (r *PostRepository) DeletePost(ctx context.Context, postId string, updateFn func(post *domain.Post) error) error {
+	return r.updatePost(ctx, postId, updateFn)
+}
+
+func (r *PostRepository) ReassignAuthor(ctx context.Context, authorId, newAuthorId string) error {
+	_, err := r.db.Exec(ctx, `UPDATE posts SET author_id = $2 WHERE author_id = $1`, authorId, newAuthorId)
+	return err
+}
+
+func (r *PostRepository) updatePost(ctx context.Context, postId string, updateFn func(post *domain.Post) error) error {
+	query := `SELECT ` + postColumns + ` FROM posts WHERE id = $1 AND status <> $2`
+	var doc postDocument
+	if err := scanPostRow(r.db.QueryRow(ctx, query, postId, domain.Deleted.String()), &doc); err != nil {
+		return err
+	}
+	post := doc.toDomain()
+	if err := updateFn(&post); err != nil {
+		return err
+	}
+	update := `
+        UPDATE posts
+        SET title = $1, body = $2, status = $3, updated_at = $4
+        WHERE id = $5
+    `
+	_, err := r.db.Exec(ctx, update,
+		post.Title(),
+		post.Body(),
+		post.Status().String(),
+		time.Now(),
+		post.Id(),
+	)
+	return err
+}
@@ -0,0 +1,5 @@
+package graphql
+
+import "github.com/iammrsea/social-app/internal/content/domain"
+
+type Comment = domain.CommentReadModel
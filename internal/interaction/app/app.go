@@ -1 +1,23 @@
 package app
+
+import (
+	"github.com/iammrsea/social-app/internal/interaction/app/command"
+	"github.com/iammrsea/social-app/internal/interaction/app/query"
+)
+
+type Application struct {
+	CommandHandler
+	QueryHandler
+}
+
+type CommandHandler struct {
+	CastVote    command.CastVoteHandler
+	FlipVote    command.FlipVoteHandler
+	RetractVote command.RetractVoteHandler
+}
+
+type QueryHandler struct {
+	GetVote      query.GetVoteHandler
+	GetVotes     query.GetVotesHandler
+	GetPostScore query.GetPostScoreHandler
+}
@@ -0,0 +1,76 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	contentDomain "github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+// CastVote records the vote of the authenticated user on a post. Casting the same vote
+// twice is a no-op and casting the opposite vote changes the existing one.
+type CastVote struct {
+	PostId string
+	Type   domain.VoteType
+}
+
+type CastVoteHandler = shared.CommandHandler[CastVote]
+
+type castVoteHandler struct {
+	voteRepo      domain.VoteRepository
+	postQueryRepo contentDomain.PostReadModelRepository
+	publisher     eventbus.Publisher
+	guard         guards.Guards
+}
+
+func NewCastVoteHandler(voteRepo domain.VoteRepository, postQueryRepo contentDomain.PostReadModelRepository, publisher eventbus.Publisher, guard guards.Guards) CastVoteHandler {
+	if voteRepo == nil || postQueryRepo == nil || publisher == nil || guard == nil {
+		panic("nil vote repository, post repository, event publisher or guard")
+	}
+	return &castVoteHandler{voteRepo: voteRepo, postQueryRepo: postQueryRepo, publisher: publisher, guard: guard}
+}
+
+func (c *castVoteHandler) Handle(ctx context.Context, cmd CastVote) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := c.guard.Authorize(ctx, authUser.Subject(), rbac.CastVote); err != nil {
+		return err
+	}
+	if !cmd.Type.IsValid() {
+		return domain.ErrInvalidVoteType
+	}
+	post, err := c.postQueryRepo.GetPostById(ctx, cmd.PostId)
+	if err != nil {
+		return err
+	}
+	// Only published posts can be voted on
+	if post.Status != contentDomain.Published {
+		return contentDomain.ErrPostNotFound
+	}
+	var raised []events.Event
+	err = c.voteRepo.CastVote(ctx, authUser.Id, cmd.PostId, func(vote *domain.Vote) (*domain.Vote, error) {
+		if vote == nil {
+			newVote, err := domain.CastVote(authUser.Id, cmd.PostId, cmd.Type, time.Now())
+			if err != nil {
+				return nil, err
+			}
+			raised = newVote.PullEvents()
+			return &newVote, nil
+		}
+		if err := vote.ChangeType(cmd.Type); err != nil {
+			return nil, err
+		}
+		raised = vote.PullEvents()
+		return vote, nil
+	})
+	if err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, raised...)
+}
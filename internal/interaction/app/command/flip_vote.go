@@ -0,0 +1,50 @@
+package command
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+// FlipVote turns the upvote of the authenticated user on a post into a downvote and vice versa
+type FlipVote struct {
+	PostId string
+}
+
+type FlipVoteHandler = shared.CommandHandler[FlipVote]
+
+type flipVoteHandler struct {
+	voteRepo  domain.VoteRepository
+	publisher eventbus.Publisher
+	guard     guards.Guards
+}
+
+func NewFlipVoteHandler(voteRepo domain.VoteRepository, publisher eventbus.Publisher, guard guards.Guards) FlipVoteHandler {
+	if voteRepo == nil || publisher == nil || guard == nil {
+		panic("nil vote repository, event publisher or guard")
+	}
+	return &flipVoteHandler{voteRepo: voteRepo, publisher: publisher, guard: guard}
+}
+
+func (f *flipVoteHandler) Handle(ctx context.Context, cmd FlipVote) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := f.guard.Authorize(ctx, authUser.Subject(), rbac.CastVote); err != nil {
+		return err
+	}
+	var raised []events.Event
+	err := f.voteRepo.FlipVote(ctx, authUser.Id, cmd.PostId, func(vote *domain.Vote) error {
+		vote.Flip()
+		raised = vote.PullEvents()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return f.publisher.Publish(ctx, raised...)
+}
@@ -0,0 +1,44 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+// RetractVote removes the vote of the authenticated user on a post, if there is one
+type RetractVote struct {
+	PostId string
+}
+
+type RetractVoteHandler = shared.CommandHandler[RetractVote]
+
+type retractVoteHandler struct {
+	voteRepo  domain.VoteRepository
+	publisher eventbus.Publisher
+	guard     guards.Guards
+}
+
+func NewRetractVoteHandler(voteRepo domain.VoteRepository, publisher eventbus.Publisher, guard guards.Guards) RetractVoteHandler {
+	if voteRepo == nil || publisher == nil || guard == nil {
+		panic("nil vote repository, event publisher or guard")
+	}
+	return &retractVoteHandler{voteRepo: voteRepo, publisher: publisher, guard: guard}
+}
+
+func (r *retractVoteHandler) Handle(ctx context.Context, cmd RetractVote) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := r.guard.Authorize(ctx, authUser.Subject(), rbac.CastVote); err != nil {
+		return err
+	}
+	if err := r.voteRepo.RetractVote(ctx, authUser.Id, cmd.PostId); err != nil {
+		return err
+	}
+	return r.publisher.Publish(ctx, domain.VoteRetracted{UserId: authUser.Id, PostId: cmd.PostId, RetractedAt: time.Now()})
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	contentDomain "github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/interaction/app/command"
+	"github.com/iammrsea/social-app/internal/interaction/app/query"
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/bans"
+)
+
+// Constructor of the interaction application layer. Banned users can't run any of the commands.
+func New(
+	voteRepo domain.VoteRepository,
+	voteReadModelRepo domain.VoteReadModelRepository,
+	postReadModelRepo contentDomain.PostReadModelRepository,
+	banChecker bans.Checker,
+	publisher eventbus.Publisher,
+	guard guards.Guards,
+) *Application {
+	return &Application{
+		CommandHandler: CommandHandler{
+			CastVote:    bans.Enforce(command.NewCastVoteHandler(voteRepo, postReadModelRepo, publisher, guard), banChecker),
+			FlipVote:    bans.Enforce(command.NewFlipVoteHandler(voteRepo, publisher, guard), banChecker),
+			RetractVote: bans.Enforce(command.NewRetractVoteHandler(voteRepo, publisher, guard), banChecker),
+		},
+		QueryHandler: QueryHandler{
+			GetVote:      query.NewGetVoteHandler(voteReadModelRepo, guard),
+			GetVotes:     query.NewGetVotesHandler(voteReadModelRepo, guard),
+			GetPostScore: query.NewGetPostScoreHandler(voteReadModelRepo, guard),
+		},
+	}
+}
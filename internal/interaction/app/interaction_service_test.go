@@ -0,0 +1,232 @@
+package app_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	contentDomain "github.com/iammrsea/social-app/internal/content/domain"
+	content_mocks "github.com/iammrsea/social-app/internal/content/domain/mocks"
+	service "github.com/iammrsea/social-app/internal/interaction/app"
+	"github.com/iammrsea/social-app/internal/interaction/app/command"
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	domain_mocks "github.com/iammrsea/social-app/internal/interaction/domain/mocks"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/iammrsea/social-app/internal/shared/guards/bans"
+	guard_mocks "github.com/iammrsea/social-app/internal/shared/guards/mocks"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// notBanned lets every user through the ban check
+var notBanned = bans.CheckerFunc(func(ctx context.Context, userId string) error { return nil })
+
+// publisherStub records the events published by the command handlers
+type publisherStub struct {
+	published []string
+}
+
+func (p *publisherStub) Publish(ctx context.Context, evts ...events.Event) error {
+	for _, event := range evts {
+		p.published = append(p.published, event.EventName())
+	}
+	return nil
+}
+
+type repoMocks struct {
+	voteRepo          *domain_mocks.MockVoteRepository
+	voteReadModelRepo *domain_mocks.MockVoteReadModelRepository
+	postReadModelRepo *content_mocks.MockPostReadModelRepository
+	publisher         *publisherStub
+	guards            *guard_mocks.MockGuards
+}
+
+type commandTestCase[T any] struct {
+	name        string
+	command     T
+	expectedErr error
+	authUser    *auth.AuthenticatedUser
+	setupMocks  func(t *testing.T, m *repoMocks, command *T, authUser *auth.AuthenticatedUser)
+	// Names of the events the command is expected to publish
+	expectedEvents []string
+}
+
+func TestCommandHandler(t *testing.T) {
+	t.Parallel()
+	t.Run("CastVote", func(t *testing.T) {
+		t.Parallel()
+		testCastVote(t)
+	})
+	t.Run("RetractVote", func(t *testing.T) {
+		t.Parallel()
+		testRetractVote(t)
+	})
+}
+
+func testCastVote(t *testing.T) {
+	voter := &auth.AuthenticatedUser{
+		Id:    "userId-1",
+		Roles: []rbac.UserRole{rbac.Regular},
+		Email: "user@example.com",
+	}
+	publishedPost := &contentDomain.PostReadModel{Id: "postId-1", Status: contentDomain.Published}
+	testCases := []commandTestCase[command.CastVote]{
+		{
+			name:     "user can upvote a post",
+			authUser: voter,
+			command: command.CastVote{
+				PostId: "postId-1",
+				Type:   domain.Upvote,
+			},
+			expectedErr:    nil,
+			expectedEvents: []string{"interaction.vote_cast"},
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CastVote, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CastVote).Return(nil)
+				m.postReadModelRepo.EXPECT().GetPostById(mock.Anything, command.PostId).Return(publishedPost, nil)
+				m.voteRepo.EXPECT().CastVote(mock.Anything, authUser.Id, command.PostId, mock.AnythingOfType("func(*domain.Vote) (*domain.Vote, error)")).RunAndReturn(
+					func(ctx context.Context, userId, postId string, updateFn func(vote *domain.Vote) (*domain.Vote, error)) error {
+						vote, err := updateFn(nil)
+						require.NoError(t, err)
+						require.Equal(t, authUser.Id, vote.UserId(), "Voter was not taken from the authenticated user")
+						require.Equal(t, domain.Upvote, vote.Type())
+						return nil
+					})
+			},
+		},
+		{
+			name:     "casting the opposite vote changes the existing vote",
+			authUser: voter,
+			command: command.CastVote{
+				PostId: "postId-1",
+				Type:   domain.Downvote,
+			},
+			expectedErr:    nil,
+			expectedEvents: []string{"interaction.vote_changed"},
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CastVote, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CastVote).Return(nil)
+				m.postReadModelRepo.EXPECT().GetPostById(mock.Anything, command.PostId).Return(publishedPost, nil)
+				m.voteRepo.EXPECT().CastVote(mock.Anything, authUser.Id, command.PostId, mock.AnythingOfType("func(*domain.Vote) (*domain.Vote, error)")).RunAndReturn(
+					func(ctx context.Context, userId, postId string, updateFn func(vote *domain.Vote) (*domain.Vote, error)) error {
+						existing := domain.MustNewVote(userId, postId, domain.Upvote, time.Now(), time.Now())
+						vote, err := updateFn(&existing)
+						require.NoError(t, err)
+						require.Equal(t, domain.Downvote, vote.Type())
+						return nil
+					})
+			},
+		},
+		{
+			name:     "user cannot vote on a draft",
+			authUser: voter,
+			command: command.CastVote{
+				PostId: "postId-1",
+				Type:   domain.Upvote,
+			},
+			expectedErr: contentDomain.ErrPostNotFound,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CastVote, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CastVote).Return(nil)
+				m.postReadModelRepo.EXPECT().GetPostById(mock.Anything, command.PostId).Return(&contentDomain.PostReadModel{Id: "postId-1", Status: contentDomain.Draft}, nil)
+			},
+		},
+		{
+			name:     "invalid vote type is rejected",
+			authUser: voter,
+			command: command.CastVote{
+				PostId: "postId-1",
+				Type:   "sideways",
+			},
+			expectedErr: domain.ErrInvalidVoteType,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CastVote, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CastVote).Return(nil)
+			},
+		},
+		{
+			name: "guest cannot vote",
+			authUser: &auth.AuthenticatedUser{
+				Roles: []rbac.UserRole{rbac.Guest},
+			},
+			command: command.CastVote{
+				PostId: "postId-1",
+				Type:   domain.Upvote,
+			},
+			expectedErr: rbac.ErrUnauthorized,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CastVote, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CastVote).Return(rbac.ErrUnauthorized)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, interactionService := setupCommandInteractionService(t, tt)
+			err := interactionService.CastVote.Handle(ctx, tt.command)
+			assertError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func testRetractVote(t *testing.T) {
+	testCases := []commandTestCase[command.RetractVote]{
+		{
+			name: "user can retract their vote",
+			authUser: &auth.AuthenticatedUser{
+				Id:    "userId-1",
+				Roles: []rbac.UserRole{rbac.Regular},
+				Email: "user@example.com",
+			},
+			command:        command.RetractVote{PostId: "postId-1"},
+			expectedErr:    nil,
+			expectedEvents: []string{"interaction.vote_retracted"},
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.RetractVote, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CastVote).Return(nil)
+				m.voteRepo.EXPECT().RetractVote(mock.Anything, authUser.Id, command.PostId).Return(nil)
+			},
+		},
+		{
+			name: "guest cannot retract votes",
+			authUser: &auth.AuthenticatedUser{
+				Roles: []rbac.UserRole{rbac.Guest},
+			},
+			command:     command.RetractVote{PostId: "postId-1"},
+			expectedErr: rbac.ErrUnauthorized,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.RetractVote, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CastVote).Return(rbac.ErrUnauthorized)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, interactionService := setupCommandInteractionService(t, tt)
+			err := interactionService.RetractVote.Handle(ctx, tt.command)
+			assertError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func setupCommandInteractionService[T any](t *testing.T, tt commandTestCase[T]) (context.Context, *service.Application) {
+	t.Helper()
+	ctx := auth.NewContextWithUser(context.Background(), tt.authUser)
+	m := &repoMocks{
+		voteRepo:          domain_mocks.NewMockVoteRepository(t),
+		voteReadModelRepo: domain_mocks.NewMockVoteReadModelRepository(t),
+		postReadModelRepo: content_mocks.NewMockPostReadModelRepository(t),
+		publisher:         &publisherStub{},
+		guards:            guard_mocks.NewMockGuards(t),
+	}
+	tt.setupMocks(t, m, &tt.command, tt.authUser)
+	t.Cleanup(func() { assert.Equal(t, tt.expectedEvents, m.publisher.published, "Unexpected events were published") })
+	return ctx, service.New(m.voteRepo, m.voteReadModelRepo, m.postReadModelRepo, notBanned, m.publisher, m.guards)
+}
+
+func assertError(t *testing.T, err error, expectedErr error) {
+	t.Helper()
+	if err != nil {
+		require.ErrorIs(t, err, expectedErr, expectedErr.Error())
+	} else {
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,37 @@
+package query
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+type GetPostScoreHandler = shared.QueryHandler[GetPostScore, *domain.PostScore]
+
+type GetPostScore struct {
+	PostId string
+}
+
+type getPostScoreHandler struct {
+	queryRepo domain.VoteReadModelRepository
+	guard     guards.Guards
+}
+
+func NewGetPostScoreHandler(queryRepo domain.VoteReadModelRepository, guard guards.Guards) GetPostScoreHandler {
+	if queryRepo == nil || guard == nil {
+		panic("nil vote repository or guard")
+	}
+	return &getPostScoreHandler{queryRepo: queryRepo, guard: guard}
+}
+
+func (g *getPostScoreHandler) Handle(ctx context.Context, cmd GetPostScore) (*domain.PostScore, error) {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ViewVote); err != nil {
+		return nil, err
+	}
+	return g.queryRepo.GetPostScore(ctx, cmd.PostId)
+}
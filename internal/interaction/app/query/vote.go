@@ -0,0 +1,38 @@
+package query
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+type GetVoteHandler = shared.QueryHandler[GetVote, *domain.VoteReadModel]
+
+type GetVote struct {
+	UserId string
+	PostId string
+}
+
+type getVoteHandler struct {
+	queryRepo domain.VoteReadModelRepository
+	guard     guards.Guards
+}
+
+func NewGetVoteHandler(queryRepo domain.VoteReadModelRepository, guard guards.Guards) GetVoteHandler {
+	if queryRepo == nil || guard == nil {
+		panic("nil vote repository or guard")
+	}
+	return &getVoteHandler{queryRepo: queryRepo, guard: guard}
+}
+
+func (g *getVoteHandler) Handle(ctx context.Context, cmd GetVote) (*domain.VoteReadModel, error) {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ViewVote); err != nil {
+		return nil, err
+	}
+	return g.queryRepo.GetVote(ctx, cmd.UserId, cmd.PostId)
+}
@@ -0,0 +1,50 @@
+package query
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/shared/pagination"
+)
+
+type GetVotes = domain.GetVotesOptions
+
+type Result = pagination.PaginatedQueryResult[[]*domain.VoteReadModel]
+
+type GetVotesHandler = shared.QueryHandler[GetVotes, *Result]
+
+type getVotesHandler struct {
+	queryRepo domain.VoteReadModelRepository
+	guard     guards.Guards
+}
+
+func NewGetVotesHandler(queryRepo domain.VoteReadModelRepository, guard guards.Guards) GetVotesHandler {
+	if queryRepo == nil || guard == nil {
+		panic("nil vote repository or guard")
+	}
+	return &getVotesHandler{queryRepo: queryRepo, guard: guard}
+}
+
+func (g *getVotesHandler) Handle(ctx context.Context, cmd GetVotes) (*Result, error) {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ViewVote); err != nil {
+		return nil, err
+	}
+	if cmd.First <= 0 {
+		return nil, domain.ErrInvalidPageSize
+	}
+	votes, hasNext, err := g.queryRepo.GetVotes(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		Data: votes,
+		PaginationInfo: &pagination.PagenationInfo{
+			HasNext: hasNext,
+		},
+	}, nil
+}
@@ -1 +1,36 @@
 package domain
+
+import "time"
+
+type VoteCast struct {
+	UserId string
+	PostId string
+	Type   VoteType
+	CastAt time.Time
+}
+
+func (e VoteCast) EventName() string     { return "interaction.vote_cast" }
+func (e VoteCast) OccurredAt() time.Time { return e.CastAt }
+
+// VoteChanged is raised when a user turns an upvote into a downvote or the other way around
+type VoteChanged struct {
+	UserId    string
+	PostId    string
+	From      VoteType
+	To        VoteType
+	ChangedAt time.Time
+}
+
+func (e VoteChanged) EventName() string     { return "interaction.vote_changed" }
+func (e VoteChanged) OccurredAt() time.Time { return e.ChangedAt }
+
+// VoteRetracted is raised when a user takes back their vote on a post. Retracting is idempotent, so
+// it is also raised when the user had no vote left on the post.
+type VoteRetracted struct {
+	UserId      string
+	PostId      string
+	RetractedAt time.Time
+}
+
+func (e VoteRetracted) EventName() string     { return "interaction.vote_retracted" }
+func (e VoteRetracted) OccurredAt() time.Time { return e.RetractedAt }
@@ -0,0 +1,46 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoteEvents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should raise VoteCast when a vote is cast", func(t *testing.T) {
+		t.Parallel()
+		castAt := time.Now()
+		vote, err := domain.CastVote("user-1", "post-1", domain.Upvote, castAt)
+		require.Nil(t, err)
+		assert.Equal(t, []events.Event{domain.VoteCast{
+			UserId: "user-1",
+			PostId: "post-1",
+			Type:   domain.Upvote,
+			CastAt: castAt,
+		}}, vote.PullEvents())
+	})
+
+	t.Run("should raise VoteChanged with the previous type", func(t *testing.T) {
+		t.Parallel()
+		vote := domain.MustNewVote("user-1", "post-1", domain.Upvote, time.Now(), time.Now())
+		vote.Flip()
+		raised := vote.PullEvents()
+		require.Len(t, raised, 1)
+		changed := raised[0].(domain.VoteChanged)
+		assert.Equal(t, domain.Upvote, changed.From)
+		assert.Equal(t, domain.Downvote, changed.To)
+	})
+
+	t.Run("should not raise events when the type is unchanged", func(t *testing.T) {
+		t.Parallel()
+		vote := domain.MustNewVote("user-1", "post-1", domain.Upvote, time.Now(), time.Now())
+		require.Nil(t, vote.ChangeType(domain.Upvote))
+		assert.Empty(t, vote.PullEvents())
+	})
+}
@@ -1,14 +1,154 @@
 package domain
 
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/events"
+)
+
 type VoteType string
 
 const (
-	Upvote   VoteType = "upvote"
-	Downvote VoteType = "downvote"
+	Upvote   VoteType = "UPVOTE"
+	Downvote VoteType = "DOWNVOTE"
 )
 
+func (t VoteType) String() string {
+	return string(t)
+}
+
+func (t VoteType) IsValid() bool {
+	return t == Upvote || t == Downvote
+}
+
+// Opposite returns the other vote type
+func (t VoteType) Opposite() VoteType {
+	if t == Upvote {
+		return Downvote
+	}
+	return Upvote
+}
+
+// Vote is the up or down vote of a user on a post. A user has at most one vote per post.
 type Vote struct {
-	UserID string
-	PostID string
-	Type   VoteType
+	userId    string
+	postId    string
+	voteType  VoteType
+	createdAt time.Time
+	updatedAt time.Time
+	events    events.Recorder
+}
+
+var (
+	ErrVoteUserRequired = errors.New("vote user cannot be empty")
+	ErrVotePostRequired = errors.New("vote post cannot be empty")
+	ErrInvalidVoteType  = errors.New("invalid vote type")
+	ErrVoteNotFound     = errors.New("vote not found")
+)
+
+func NewVote(userId, postId string, voteType VoteType, createdAt, updatedAt time.Time) (Vote, error) {
+	vote := Vote{}
+	if strings.TrimSpace(userId) == "" {
+		return vote, ErrVoteUserRequired
+	}
+	if strings.TrimSpace(postId) == "" {
+		return vote, ErrVotePostRequired
+	}
+	if !voteType.IsValid() {
+		return vote, ErrInvalidVoteType
+	}
+	return Vote{
+		userId:    userId,
+		postId:    postId,
+		voteType:  voteType,
+		createdAt: createdAt,
+		updatedAt: updatedAt,
+	}, nil
+}
+
+// CastVote creates the first vote of the user on the post and raises VoteCast
+func CastVote(userId, postId string, voteType VoteType, castAt time.Time) (Vote, error) {
+	vote, err := NewVote(userId, postId, voteType, castAt, castAt)
+	if err != nil {
+		return vote, err
+	}
+	vote.events.Record(VoteCast{UserId: userId, PostId: postId, Type: voteType, CastAt: castAt})
+	return vote, nil
+}
+
+func MustNewVote(userId, postId string, voteType VoteType, createdAt, updatedAt time.Time) Vote {
+	vote, err := NewVote(userId, postId, voteType, createdAt, updatedAt)
+	if err != nil {
+		panic(err.Error())
+	}
+	return vote
+}
+
+// ChangeType sets the type of the vote. Setting the type the vote already has is a no-op.
+func (v *Vote) ChangeType(voteType VoteType) error {
+	if !voteType.IsValid() {
+		return ErrInvalidVoteType
+	}
+	if v.voteType == voteType {
+		return nil
+	}
+	v.setType(voteType)
+	return nil
+}
+
+// Flip turns an upvote into a downvote and vice versa
+func (v *Vote) Flip() {
+	v.setType(v.voteType.Opposite())
+}
+
+func (v *Vote) setType(voteType VoteType) {
+	previous := v.voteType
+	v.voteType = voteType
+	v.updatedAt = time.Now()
+	v.events.Record(VoteChanged{UserId: v.userId, PostId: v.postId, From: previous, To: voteType, ChangedAt: v.updatedAt})
+}
+
+// PullEvents returns the events raised since the vote was loaded and clears them
+func (v *Vote) PullEvents() []events.Event {
+	return v.events.PullEvents()
+}
+
+func (v *Vote) UserId() string {
+	return v.userId
+}
+
+func (v *Vote) PostId() string {
+	return v.postId
+}
+
+func (v *Vote) Type() VoteType {
+	return v.voteType
+}
+
+func (v *Vote) CreatedAt() time.Time {
+	return v.createdAt
+}
+
+func (v *Vote) UpdatedAt() time.Time {
+	return v.updatedAt
+}
+
+// ScoreChange returns how the upvote and downvote counts of a post change when the vote
+// before is replaced by the vote after. A nil vote means there is no vote.
+func ScoreChange(before, after *Vote) (upvotes, downvotes int) {
+	count := func(vote *Vote, delta int) {
+		if vote == nil {
+			return
+		}
+		if vote.voteType == Upvote {
+			upvotes += delta
+		} else {
+			downvotes += delta
+		}
+	}
+	count(before, -1)
+	count(after, 1)
+	return upvotes, downvotes
 }
@@ -1,7 +1,28 @@
 package domain
 
-type VoteReadMoel struct {
-	UserID string   `json:"user_id"`
-	PostID string   `json:"post_id"`
-	Type   VoteType `json:"type"`
+import "time"
+
+type VoteReadModel struct {
+	UserID    string    `json:"userId"`
+	PostID    string    `json:"postId"`
+	Type      VoteType  `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// PostScore is the tally of the votes cast on a post
+type PostScore struct {
+	PostId    string `json:"postId"`
+	Upvotes   int32  `json:"upvotes"`
+	Downvotes int32  `json:"downvotes"`
+	Score     int32  `json:"score"`
+}
+
+func NewPostScore(postId string, upvotes, downvotes int32) *PostScore {
+	return &PostScore{
+		PostId:    postId,
+		Upvotes:   upvotes,
+		Downvotes: downvotes,
+		Score:     upvotes - downvotes,
+	}
 }
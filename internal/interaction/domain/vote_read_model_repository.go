@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidPageSize is returned when votes are listed without asking for at least one
+var ErrInvalidPageSize = errors.New("the number of votes to list must be at least one")
+
+type GetVotesOptions struct {
+	First         int32
+	After         string
+	SortDirection string // "ASC" or "DESC"
+	PostId        string // optional, restricts the result to the votes on a post
+	UserId        string // optional, restricts the result to the votes of a user
+}
+
+type VoteReadModelRepository interface {
+	GetVotes(ctx context.Context, opts GetVotesOptions) (votes []*VoteReadModel, hasNext bool, err error)
+	GetVote(ctx context.Context, userId, postId string) (*VoteReadModel, error)
+	// GetPostScore returns the score of a post, a post nobody voted on has a score of 0
+	GetPostScore(ctx context.Context, postId string) (*PostScore, error)
+}
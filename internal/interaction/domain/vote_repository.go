@@ -1 +1,16 @@
 package domain
+
+import "context"
+
+// VoteRepository stores votes. Every change to a vote also updates the score of the post,
+// both are saved together.
+type VoteRepository interface {
+	// CastVote passes the current vote of the user on the post to updateFn, nil when there is none,
+	// and stores the vote it returns.
+	CastVote(ctx context.Context, userId, postId string, updateFn func(vote *Vote) (*Vote, error)) error
+	FlipVote(ctx context.Context, userId, postId string, updateFn func(vote *Vote) error) error
+	// RetractVote removes the vote of the user on the post. Retracting a vote that doesn't exist is a no-op.
+	RetractVote(ctx context.Context, userId, postId string) error
+	// RetractAllVotes removes every vote of the user and takes them off the scores of the posts
+	RetractAllVotes(ctx context.Context, userId string) error
+}
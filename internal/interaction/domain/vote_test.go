@@ -0,0 +1,68 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVote(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create vote", func(t *testing.T) {
+		t.Parallel()
+		vote, err := domain.NewVote("user-1", "post-1", domain.Upvote, time.Now(), time.Now())
+		assert.Nil(t, err)
+		assert.Equal(t, domain.Upvote, vote.Type())
+	})
+
+	t.Run("should return correct error if type is invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := domain.NewVote("user-1", "post-1", "sideways", time.Now(), time.Now())
+		assert.Equal(t, domain.ErrInvalidVoteType, err)
+	})
+
+	t.Run("should return correct error if user is empty", func(t *testing.T) {
+		t.Parallel()
+		_, err := domain.NewVote("", "post-1", domain.Upvote, time.Now(), time.Now())
+		assert.Equal(t, domain.ErrVoteUserRequired, err)
+	})
+}
+
+func TestFlipVote(t *testing.T) {
+	t.Parallel()
+	vote := domain.MustNewVote("user-1", "post-1", domain.Upvote, time.Now(), time.Now())
+	vote.Flip()
+	assert.Equal(t, domain.Downvote, vote.Type())
+	vote.Flip()
+	assert.Equal(t, domain.Upvote, vote.Type())
+}
+
+func TestScoreChange(t *testing.T) {
+	t.Parallel()
+	upvote := domain.MustNewVote("user-1", "post-1", domain.Upvote, time.Now(), time.Now())
+	downvote := domain.MustNewVote("user-1", "post-1", domain.Downvote, time.Now(), time.Now())
+
+	testCases := []struct {
+		name              string
+		before            *domain.Vote
+		after             *domain.Vote
+		expectedUpvotes   int
+		expectedDownvotes int
+	}{
+		{name: "casting an upvote", before: nil, after: &upvote, expectedUpvotes: 1, expectedDownvotes: 0},
+		{name: "casting the same vote again", before: &upvote, after: &upvote, expectedUpvotes: 0, expectedDownvotes: 0},
+		{name: "flipping an upvote", before: &upvote, after: &downvote, expectedUpvotes: -1, expectedDownvotes: 1},
+		{name: "retracting a downvote", before: &downvote, after: nil, expectedUpvotes: 0, expectedDownvotes: -1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			upvotes, downvotes := domain.ScoreChange(tc.before, tc.after)
+			assert.Equal(t, tc.expectedUpvotes, upvotes)
+			assert.Equal(t, tc.expectedDownvotes, downvotes)
+		})
+	}
+}
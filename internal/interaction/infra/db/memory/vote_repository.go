@@ -1 +1,199 @@
 package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+)
+
+type voteKey struct {
+	userId string
+	postId string
+}
+
+type score struct {
+	upvotes   int
+	downvotes int
+}
+
+// VoteRepository keeps votes and post scores in memory. It implements both domain.VoteRepository
+// and domain.VoteReadModelRepository.
+type VoteRepository struct {
+	mu     sync.RWMutex
+	votes  map[voteKey]domain.Vote
+	scores map[string]score
+}
+
+func NewVoteRepository() *VoteRepository {
+	return &VoteRepository{
+		votes:  map[voteKey]domain.Vote{},
+		scores: map[string]score{},
+	}
+}
+
+func (m *VoteRepository) CastVote(ctx context.Context, userId, postId string, updateFn func(vote *domain.Vote) (*domain.Vote, error)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := voteKey{userId: userId, postId: postId}
+	var current *domain.Vote
+	if vote, ok := m.votes[key]; ok {
+		current = &vote
+	}
+	before := copyVote(current)
+	vote, err := updateFn(current)
+	if err != nil {
+		return err
+	}
+	if vote == nil {
+		return nil
+	}
+	m.votes[key] = *vote
+	m.applyScoreChange(postId, before, vote)
+	return nil
+}
+
+func (m *VoteRepository) FlipVote(ctx context.Context, userId, postId string, updateFn func(vote *domain.Vote) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := voteKey{userId: userId, postId: postId}
+	vote, ok := m.votes[key]
+	if !ok {
+		return domain.ErrVoteNotFound
+	}
+	before := vote
+	if err := updateFn(&vote); err != nil {
+		return err
+	}
+	m.votes[key] = vote
+	m.applyScoreChange(postId, &before, &vote)
+	return nil
+}
+
+func (m *VoteRepository) RetractVote(ctx context.Context, userId, postId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := voteKey{userId: userId, postId: postId}
+	vote, ok := m.votes[key]
+	if !ok {
+		return nil
+	}
+	delete(m.votes, key)
+	m.applyScoreChange(postId, &vote, nil)
+	return nil
+}
+
+func (m *VoteRepository) RetractAllVotes(ctx context.Context, userId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, vote := range m.votes {
+		if key.userId != userId {
+			continue
+		}
+		delete(m.votes, key)
+		m.applyScoreChange(key.postId, &vote, nil)
+	}
+	return nil
+}
+
+func (m *VoteRepository) GetVotes(ctx context.Context, opts domain.GetVotesOptions) ([]*domain.VoteReadModel, bool, error) {
+	if opts.First <= 0 {
+		return nil, false, domain.ErrInvalidPageSize
+	}
+	asc := false
+	switch opts.SortDirection {
+	case "", "DESC":
+	case "ASC":
+		asc = true
+	default:
+		return nil, false, errors.New("invalid sort direction, must be 'ASC' or 'DESC'")
+	}
+	var after time.Time
+	if opts.After != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, opts.After)
+		if err != nil {
+			return nil, false, err
+		}
+		after = parsed
+	}
+
+	m.mu.RLock()
+	votes := []*domain.VoteReadModel{}
+	for _, vote := range m.votes {
+		if opts.PostId != "" && vote.PostId() != opts.PostId {
+			continue
+		}
+		if opts.UserId != "" && vote.UserId() != opts.UserId {
+			continue
+		}
+		if !after.IsZero() {
+			if asc && !vote.CreatedAt().After(after) {
+				continue
+			}
+			if !asc && !vote.CreatedAt().Before(after) {
+				continue
+			}
+		}
+		votes = append(votes, toReadModel(vote))
+	}
+	m.mu.RUnlock()
+
+	slices.SortFunc(votes, func(a, b *domain.VoteReadModel) int {
+		if asc {
+			return a.CreatedAt.Compare(b.CreatedAt)
+		}
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	hasNext := len(votes) > int(opts.First)
+	if hasNext {
+		votes = votes[:opts.First]
+	}
+	return votes, hasNext, nil
+}
+
+func (m *VoteRepository) GetVote(ctx context.Context, userId, postId string) (*domain.VoteReadModel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	vote, ok := m.votes[voteKey{userId: userId, postId: postId}]
+	if !ok {
+		return nil, domain.ErrVoteNotFound
+	}
+	return toReadModel(vote), nil
+}
+
+func (m *VoteRepository) GetPostScore(ctx context.Context, postId string) (*domain.PostScore, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s := m.scores[postId]
+	return domain.NewPostScore(postId, int32(s.upvotes), int32(s.downvotes)), nil
+}
+
+// copyVote keeps the state of a vote before updateFn gets to change it
+func copyVote(vote *domain.Vote) *domain.Vote {
+	if vote == nil {
+		return nil
+	}
+	c := *vote
+	return &c
+}
+
+func (m *VoteRepository) applyScoreChange(postId string, before, after *domain.Vote) {
+	upvotes, downvotes := domain.ScoreChange(before, after)
+	s := m.scores[postId]
+	s.upvotes += upvotes
+	s.downvotes += downvotes
+	m.scores[postId] = s
+}
+
+func toReadModel(vote domain.Vote) *domain.VoteReadModel {
+	return &domain.VoteReadModel{
+		UserID:    vote.UserId(),
+		PostID:    vote.PostId(),
+		Type:      vote.Type(),
+		CreatedAt: vote.CreatedAt(),
+		UpdatedAt: vote.UpdatedAt(),
+	}
+}
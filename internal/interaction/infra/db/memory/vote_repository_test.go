@@ -0,0 +1,121 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"github.com/iammrsea/social-app/internal/interaction/infra/db/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func castVote(t *testing.T, repo *memory.VoteRepository, userId, postId string, voteType domain.VoteType) {
+	t.Helper()
+	err := repo.CastVote(context.Background(), userId, postId, func(vote *domain.Vote) (*domain.Vote, error) {
+		if vote == nil {
+			newVote := domain.MustNewVote(userId, postId, voteType, time.Now(), time.Now())
+			return &newVote, nil
+		}
+		return vote, vote.ChangeType(voteType)
+	})
+	assert.Nil(t, err)
+}
+
+func TestVoteScore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should count each user once", func(t *testing.T) {
+		t.Parallel()
+		repo := memory.NewVoteRepository()
+		castVote(t, repo, "user-1", "post-1", domain.Upvote)
+		castVote(t, repo, "user-1", "post-1", domain.Upvote)
+		castVote(t, repo, "user-2", "post-1", domain.Downvote)
+
+		score, err := repo.GetPostScore(context.Background(), "post-1")
+		assert.Nil(t, err)
+		assert.Equal(t, &domain.PostScore{PostId: "post-1", Upvotes: 1, Downvotes: 1, Score: 0}, score)
+	})
+
+	t.Run("should move the vote when it is flipped", func(t *testing.T) {
+		t.Parallel()
+		repo := memory.NewVoteRepository()
+		castVote(t, repo, "user-1", "post-1", domain.Upvote)
+		err := repo.FlipVote(context.Background(), "user-1", "post-1", func(vote *domain.Vote) error {
+			vote.Flip()
+			return nil
+		})
+		assert.Nil(t, err)
+
+		score, _ := repo.GetPostScore(context.Background(), "post-1")
+		assert.Equal(t, int32(0), score.Upvotes)
+		assert.Equal(t, int32(1), score.Downvotes)
+		assert.Equal(t, int32(-1), score.Score)
+	})
+
+	t.Run("should return correct error when flipping a missing vote", func(t *testing.T) {
+		t.Parallel()
+		repo := memory.NewVoteRepository()
+		err := repo.FlipVote(context.Background(), "user-1", "post-1", func(vote *domain.Vote) error {
+			vote.Flip()
+			return nil
+		})
+		assert.ErrorIs(t, err, domain.ErrVoteNotFound)
+	})
+
+	t.Run("should remove the vote from the score when it is retracted", func(t *testing.T) {
+		t.Parallel()
+		repo := memory.NewVoteRepository()
+		castVote(t, repo, "user-1", "post-1", domain.Upvote)
+		assert.Nil(t, repo.RetractVote(context.Background(), "user-1", "post-1"))
+		// Retracting twice is a no-op
+		assert.Nil(t, repo.RetractVote(context.Background(), "user-1", "post-1"))
+
+		score, _ := repo.GetPostScore(context.Background(), "post-1")
+		assert.Equal(t, int32(0), score.Upvotes)
+		_, err := repo.GetVote(context.Background(), "user-1", "post-1")
+		assert.ErrorIs(t, err, domain.ErrVoteNotFound)
+	})
+
+	t.Run("should retract every vote of a user from the scores", func(t *testing.T) {
+		t.Parallel()
+		repo := memory.NewVoteRepository()
+		castVote(t, repo, "user-1", "post-1", domain.Upvote)
+		castVote(t, repo, "user-1", "post-2", domain.Downvote)
+		castVote(t, repo, "user-2", "post-1", domain.Upvote)
+
+		assert.Nil(t, repo.RetractAllVotes(context.Background(), "user-1"))
+
+		score, _ := repo.GetPostScore(context.Background(), "post-1")
+		assert.Equal(t, &domain.PostScore{PostId: "post-1", Upvotes: 1, Downvotes: 0, Score: 1}, score)
+		score, _ = repo.GetPostScore(context.Background(), "post-2")
+		assert.Equal(t, int32(0), score.Downvotes)
+		votes, _, err := repo.GetVotes(context.Background(), domain.GetVotesOptions{First: 10, UserId: "user-1"})
+		assert.Nil(t, err)
+		assert.Empty(t, votes)
+	})
+}
+
+func TestGetVotes(t *testing.T) {
+	t.Parallel()
+
+	repo := memory.NewVoteRepository()
+	castVote(t, repo, "user-1", "post-1", domain.Upvote)
+	castVote(t, repo, "user-1", "post-2", domain.Downvote)
+
+	t.Run("should page through the votes of a user", func(t *testing.T) {
+		t.Parallel()
+		votes, hasNext, err := repo.GetVotes(context.Background(), domain.GetVotesOptions{First: 1, UserId: "user-1"})
+		assert.Nil(t, err)
+		assert.True(t, hasNext)
+		assert.Len(t, votes, 1)
+	})
+
+	t.Run("should reject fewer than one vote", func(t *testing.T) {
+		t.Parallel()
+		for _, first := range []int32{0, -1} {
+			_, _, err := repo.GetVotes(context.Background(), domain.GetVotesOptions{First: first})
+			assert.ErrorIs(t, err, domain.ErrInvalidPageSize, "first: %d", first)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+)
+
+// voteDocument represents how a vote is stored in MongoDB
+type voteDocument struct {
+	ID        string    `bson:"_id"`
+	UserID    string    `bson:"userId"`
+	PostID    string    `bson:"postId"`
+	Type      string    `bson:"type"`
+	CreatedAt time.Time `bson:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+// postScoreDocument represents how the score of a post is stored in MongoDB
+type postScoreDocument struct {
+	PostID    string `bson:"_id"`
+	Upvotes   int32  `bson:"upvotes"`
+	Downvotes int32  `bson:"downvotes"`
+}
+
+// voteId builds the id of a vote. A user has at most one vote per post.
+func voteId(userId, postId string) string {
+	return userId + ":" + postId
+}
+
+// fromDomain converts a domain Vote to voteDocument
+func fromDomain(vote domain.Vote) voteDocument {
+	return voteDocument{
+		ID:        voteId(vote.UserId(), vote.PostId()),
+		UserID:    vote.UserId(),
+		PostID:    vote.PostId(),
+		Type:      vote.Type().String(),
+		CreatedAt: vote.CreatedAt(),
+		UpdatedAt: vote.UpdatedAt(),
+	}
+}
+
+// toDomain converts a voteDocument to domain Vote
+func (v voteDocument) toDomain() domain.Vote {
+	return domain.MustNewVote(v.UserID, v.PostID, domain.VoteType(v.Type), v.CreatedAt, v.UpdatedAt)
+}
+
+// documentToReadModel converts voteDocument to VoteReadModel
+func documentToReadModel(doc voteDocument) *domain.VoteReadModel {
+	return &domain.VoteReadModel{
+		UserID:    doc.UserID,
+		PostID:    doc.PostID,
+		Type:      domain.VoteType(doc.Type),
+		CreatedAt: doc.CreatedAt,
+		UpdatedAt: doc.UpdatedAt,
+	}
+}
@@ -0,0 +1,103 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VoteReadModelRepository implements the domain.VoteReadModelRepository interface
+type VoteReadModelRepository struct {
+	votes  *mongo.Collection
+	scores *mongo.Collection
+}
+
+func NewVoteReadModelRepository(db *mongo.Database) *VoteReadModelRepository {
+	return &VoteReadModelRepository{
+		votes:  db.Collection("votes"),
+		scores: db.Collection("postScores"),
+	}
+}
+
+// GetVotes retrieves paginated votes sorted by createdAt
+func (r *VoteReadModelRepository) GetVotes(ctx context.Context, opts domain.GetVotesOptions) ([]*domain.VoteReadModel, bool, error) {
+	if opts.First <= 0 {
+		return nil, false, domain.ErrInvalidPageSize
+	}
+	sortValue, comparison := -1, "$lt" // Newest first by default
+	switch opts.SortDirection {
+	case "", "DESC":
+	case "ASC":
+		sortValue, comparison = 1, "$gt"
+	default:
+		return nil, false, errors.New("invalid sort direction, must be 'ASC' or 'DESC'")
+	}
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(opts.First + 1)) //Fetch one more to determine if there are more results
+	findOptions.SetSort(bson.D{{Key: "createdAt", Value: sortValue}})
+
+	filter := bson.M{}
+	if opts.After != "" {
+		createdAt, err := time.Parse(time.RFC3339Nano, opts.After)
+		if err != nil {
+			return nil, false, err
+		}
+		filter["createdAt"] = bson.M{comparison: createdAt}
+	}
+	if opts.PostId != "" {
+		filter["postId"] = opts.PostId
+	}
+	if opts.UserId != "" {
+		filter["userId"] = opts.UserId
+	}
+
+	cursor, err := r.votes.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, false, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []voteDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, false, err
+	}
+	hasNext := false
+	if len(docs) > int(opts.First) {
+		hasNext = true
+		docs = docs[:opts.First]
+	}
+	var votes []*domain.VoteReadModel
+	for _, doc := range docs {
+		votes = append(votes, documentToReadModel(doc))
+	}
+	return votes, hasNext, nil
+}
+
+// GetVote finds the vote of a user on a post
+func (r *VoteReadModelRepository) GetVote(ctx context.Context, userId, postId string) (*domain.VoteReadModel, error) {
+	var doc voteDocument
+	err := r.votes.FindOne(ctx, bson.M{"_id": voteId(userId, postId)}).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrVoteNotFound
+		}
+		return nil, err
+	}
+	return documentToReadModel(doc), nil
+}
+
+// GetPostScore returns the score of a post
+func (r *VoteReadModelRepository) GetPostScore(ctx context.Context, postId string) (*domain.PostScore, error) {
+	var doc postScoreDocument
+	err := r.scores.FindOne(ctx, bson.M{"_id": postId}).Decode(&doc)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	return domain.NewPostScore(postId, doc.Upvotes, doc.Downvotes), nil
+}
@@ -1 +1,156 @@
 package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VoteRepository implements the domain.VoteRepository interface. Votes and post scores
+// are written in the same transaction.
+type VoteRepository struct {
+	votes  *mongo.Collection
+	scores *mongo.Collection
+}
+
+func NewVoteRepository(db *mongo.Database) *VoteRepository {
+	return &VoteRepository{
+		votes:  db.Collection("votes"),
+		scores: db.Collection("postScores"),
+	}
+}
+
+// CastVote creates or changes the vote of a user on a post
+func (r *VoteRepository) CastVote(ctx context.Context, userId, postId string, updateFn func(vote *domain.Vote) (*domain.Vote, error)) error {
+	return r.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		current, err := r.getVote(sessCtx, userId, postId)
+		if err != nil && !errors.Is(err, domain.ErrVoteNotFound) {
+			return err
+		}
+		before := copyVote(current)
+		vote, err := updateFn(current)
+		if err != nil {
+			return err
+		}
+		if vote == nil {
+			return nil
+		}
+		if before == nil {
+			_, err = r.votes.InsertOne(sessCtx, fromDomain(*vote))
+		} else {
+			_, err = r.votes.ReplaceOne(sessCtx, bson.M{"_id": voteId(userId, postId)}, fromDomain(*vote))
+		}
+		if err != nil {
+			return err
+		}
+		return r.updateScore(sessCtx, postId, before, vote)
+	})
+}
+
+// FlipVote applies updateFn to an existing vote
+func (r *VoteRepository) FlipVote(ctx context.Context, userId, postId string, updateFn func(vote *domain.Vote) error) error {
+	return r.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		vote, err := r.getVote(sessCtx, userId, postId)
+		if err != nil {
+			return err
+		}
+		before := copyVote(vote)
+		if err := updateFn(vote); err != nil {
+			return err
+		}
+		_, err = r.votes.ReplaceOne(sessCtx, bson.M{"_id": voteId(userId, postId)}, fromDomain(*vote))
+		if err != nil {
+			return err
+		}
+		return r.updateScore(sessCtx, postId, before, vote)
+	})
+}
+
+// RetractVote removes the vote of a user on a post
+func (r *VoteRepository) RetractVote(ctx context.Context, userId, postId string) error {
+	return r.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		var doc voteDocument
+		err := r.votes.FindOneAndDelete(sessCtx, bson.M{"_id": voteId(userId, postId)}).Decode(&doc)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return nil
+			}
+			return err
+		}
+		vote := doc.toDomain()
+		return r.updateScore(sessCtx, postId, &vote, nil)
+	})
+}
+
+// RetractAllVotes removes every vote of a user
+func (r *VoteRepository) RetractAllVotes(ctx context.Context, userId string) error {
+	return r.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		cursor, err := r.votes.Find(sessCtx, bson.M{"userId": userId})
+		if err != nil {
+			return err
+		}
+		var docs []voteDocument
+		if err := cursor.All(sessCtx, &docs); err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			if _, err := r.votes.DeleteOne(sessCtx, bson.M{"_id": doc.ID}); err != nil {
+				return err
+			}
+			vote := doc.toDomain()
+			if err := r.updateScore(sessCtx, vote.PostId(), &vote, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *VoteRepository) getVote(ctx context.Context, userId, postId string) (*domain.Vote, error) {
+	var doc voteDocument
+	err := r.votes.FindOne(ctx, bson.M{"_id": voteId(userId, postId)}).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrVoteNotFound
+		}
+		return nil, err
+	}
+	vote := doc.toDomain()
+	return &vote, nil
+}
+
+// updateScore adjusts the score of a post for a vote going from before to after
+func (r *VoteRepository) updateScore(ctx context.Context, postId string, before, after *domain.Vote) error {
+	upvotes, downvotes := domain.ScoreChange(before, after)
+	if upvotes == 0 && downvotes == 0 {
+		return nil
+	}
+	update := bson.M{"$inc": bson.M{"upvotes": upvotes, "downvotes": downvotes}}
+	_, err := r.scores.UpdateOne(ctx, bson.M{"_id": postId}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *VoteRepository) withTransaction(ctx context.Context, fn func(sessCtx mongo.SessionContext) error) error {
+	session, err := r.votes.Database().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (any, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
+func copyVote(vote *domain.Vote) *domain.Vote {
+	if vote == nil {
+		return nil
+	}
+	c := *vote
+	return &c
+}
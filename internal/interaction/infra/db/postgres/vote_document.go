@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// voteDocument represents how a vote is stored in Postgres
+type voteDocument struct {
+	UserID    string    `db:"user_id"`
+	PostID    string    `db:"post_id"`
+	Type      string    `db:"type"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+const voteColumns = `user_id, post_id, type, created_at, updated_at`
+
+// Helper function to determine the comparison operator based on sort direction
+func getComparisonOperator(sortDirection string) string {
+	if sortDirection == "ASC" {
+		return ">"
+	}
+	return "<"
+}
+
+// toDomain converts a voteDocument to a domain.Vote
+func (v *voteDocument) toDomain() domain.Vote {
+	return domain.MustNewVote(v.UserID, v.PostID, domain.VoteType(v.Type), v.CreatedAt, v.UpdatedAt)
+}
+
+// documentToReadModel converts voteDocument to VoteReadModel
+func documentToReadModel(doc voteDocument) *domain.VoteReadModel {
+	return &domain.VoteReadModel{
+		UserID:    doc.UserID,
+		PostID:    doc.PostID,
+		Type:      domain.VoteType(doc.Type),
+		CreatedAt: doc.CreatedAt,
+		UpdatedAt: doc.UpdatedAt,
+	}
+}
+
+func scanVoteRow(row pgx.Row, doc *voteDocument) error {
+	err := row.Scan(
+		&doc.UserID,
+		&doc.PostID,
+		&doc.Type,
+		&doc.CreatedAt,
+		&doc.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrVoteNotFound
+		}
+		return err
+	}
+	return nil
+}
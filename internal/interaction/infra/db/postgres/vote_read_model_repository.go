@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type VoteReadModelRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewVoteReadModelRepository(db *pgxpool.Pool) *VoteReadModelRepository {
+	return &VoteReadModelRepository{db: db}
+}
+
+// GetVotes retrieves paginated votes sorted by created_at
+func (r *VoteReadModelRepository) GetVotes(ctx context.Context, opts domain.GetVotesOptions) (votes []*domain.VoteReadModel, hasNext bool, err error) {
+	if opts.First <= 0 {
+		return nil, false, domain.ErrInvalidPageSize
+	}
+	sortDirection := "DESC" // Newest first by default
+	if opts.SortDirection != "" {
+		if opts.SortDirection != "ASC" && opts.SortDirection != "DESC" {
+			return nil, false, errors.New("invalid sort direction, must be 'ASC' or 'DESC'")
+		}
+		sortDirection = opts.SortDirection
+	}
+
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM votes
+        WHERE ($1::TIMESTAMP IS NULL OR created_at %s $1)
+            AND ($2 = '' OR post_id = $2)
+            AND ($3 = '' OR user_id = $3)
+        ORDER BY created_at %s
+        LIMIT $4
+    `, voteColumns, getComparisonOperator(sortDirection), sortDirection)
+
+	var afterTimestamp *time.Time
+	if opts.After != "" {
+		parsedTime, err := time.Parse(time.RFC3339Nano, opts.After)
+		if err != nil {
+			return nil, false, errors.New("invalid after timestamp format")
+		}
+		afterTimestamp = &parsedTime
+	}
+
+	// Fetch one extra row to check for "hasNext"
+	rows, err := r.db.Query(ctx, query, afterTimestamp, opts.PostId, opts.UserId, opts.First+1)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var doc voteDocument
+		if err := scanVoteRow(rows, &doc); err != nil {
+			return nil, false, err
+		}
+		votes = append(votes, documentToReadModel(doc))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	hasNext = len(votes) > int(opts.First)
+	if hasNext {
+		votes = votes[:opts.First]
+	}
+	return votes, hasNext, nil
+}
+
+func (r *VoteReadModelRepository) GetVote(ctx context.Context, userId, postId string) (*domain.VoteReadModel, error) {
+	query := `SELECT ` + voteColumns + ` FROM votes WHERE user_id = $1 AND post_id = $2`
+	var doc voteDocument
+	if err := scanVoteRow(r.db.QueryRow(ctx, query, userId, postId), &doc); err != nil {
+		return nil, err
+	}
+	return documentToReadModel(doc), nil
+}
+
+func (r *VoteReadModelRepository) GetPostScore(ctx context.Context, postId string) (*domain.PostScore, error) {
+	query := `SELECT upvotes, downvotes FROM post_scores WHERE post_id = $1`
+	var upvotes, downvotes int32
+	err := r.db.QueryRow(ctx, query, postId).Scan(&upvotes, &downvotes)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	return domain.NewPostScore(postId, upvotes, downvotes), nil
+}
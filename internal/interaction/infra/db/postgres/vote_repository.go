@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type VoteRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewVoteRepository(db *pgxpool.Pool) *VoteRepository {
+	return &VoteRepository{db: db}
+}
+
+func (r *VoteRepository) CastVote(ctx context.Context, userId, postId string, updateFn func(vote *domain.Vote) (*domain.Vote, error)) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		// FOR UPDATE can't lock a vote that doesn't exist yet, so concurrent first votes
+		// of the same user on the same post are serialized with an advisory lock instead
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtextextended($1 || ':' || $2, 0))`, userId, postId); err != nil {
+			return err
+		}
+		current, err := getVoteForUpdate(ctx, tx, userId, postId)
+		if err != nil && !errors.Is(err, domain.ErrVoteNotFound) {
+			return err
+		}
+		before := copyVote(current)
+		vote, err := updateFn(current)
+		if err != nil {
+			return err
+		}
+		if vote == nil {
+			return nil
+		}
+		if err := saveVote(ctx, tx, vote); err != nil {
+			return err
+		}
+		return updateScore(ctx, tx, postId, before, vote)
+	})
+}
+
+func (r *VoteRepository) FlipVote(ctx context.Context, userId, postId string, updateFn func(vote *domain.Vote) error) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		vote, err := getVoteForUpdate(ctx, tx, userId, postId)
+		if err != nil {
+			return err
+		}
+		before := copyVote(vote)
+		if err := updateFn(vote); err != nil {
+			return err
+		}
+		if err := saveVote(ctx, tx, vote); err != nil {
+			return err
+		}
+		return updateScore(ctx, tx, postId, before, vote)
+	})
+}
+
+func (r *VoteRepository) RetractVote(ctx context.Context, userId, postId string) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		query := `DELETE FROM votes WHERE user_id = $1 AND post_id = $2 RETURNING ` + voteColumns
+		var doc voteDocument
+		if err := scanVoteRow(tx.QueryRow(ctx, query, userId, postId), &doc); err != nil {
+			if errors.Is(err, domain.ErrVoteNotFound) {
+				return nil
+			}
+			return err
+		}
+		vote := doc.toDomain()
+		return updateScore(ctx, tx, postId, &vote, nil)
+	})
+}
+
+func (r *VoteRepository) RetractAllVotes(ctx context.Context, userId string) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `DELETE FROM votes WHERE user_id = $1 RETURNING `+voteColumns, userId)
+		if err != nil {
+			return err
+		}
+		votes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Vote, error) {
+			var doc voteDocument
+			if err := scanVoteRow(row, &doc); err != nil {
+				return domain.Vote{}, err
+			}
+			return doc.toDomain(), nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, vote := range votes {
+			if err := updateScore(ctx, tx, vote.PostId(), &vote, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func getVoteForUpdate(ctx context.Context, tx pgx.Tx, userId, postId string) (*domain.Vote, error) {
+	query := `SELECT ` + voteColumns + ` FROM votes WHERE user_id = $1 AND post_id = $2 FOR UPDATE`
+	var doc voteDocument
+	if err := scanVoteRow(tx.QueryRow(ctx, query, userId, postId), &doc); err != nil {
+		return nil, err
+	}
+	vote := doc.toDomain()
+	return &vote, nil
+}
+
+func saveVote(ctx context.Context, tx pgx.Tx, vote *domain.Vote) error {
+	query := `
+        INSERT INTO votes (user_id, post_id, type, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (user_id, post_id) DO UPDATE
+        SET type = EXCLUDED.type, updated_at = EXCLUDED.updated_at
+    `
+	_, err := tx.Exec(ctx, query,
+		vote.UserId(),
+		vote.PostId(),
+		vote.Type().String(),
+		vote.CreatedAt(),
+		vote.UpdatedAt(),
+	)
+	return err
+}
+
+// updateScore adjusts the score of a post for a vote going from before to after
+func updateScore(ctx context.Context, tx pgx.Tx, postId string, before, after *domain.Vote) error {
+	upvotes, downvotes := domain.ScoreChange(before, after)
+	if upvotes == 0 && downvotes == 0 {
+		return nil
+	}
+	// The changes can be negative, which an upsert would insert as they are and break the checks on
+	// the columns, so they are added to the existing score and only a post without one gets a row
+	query := `UPDATE post_scores SET upvotes = upvotes + $2, downvotes = downvotes + $3 WHERE post_id = $1`
+	tag, err := tx.Exec(ctx, query, postId, upvotes, downvotes)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+	// The first vote on the post, so the changes aren't negative. Concurrent first votes of other
+	// users may insert the row in the meantime, then the changes are added to it
+	query = `
+        INSERT INTO post_scores (post_id, upvotes, downvotes)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (post_id) DO UPDATE
+        SET upvotes = post_scores.upvotes + EXCLUDED.upvotes,
+            downvotes = post_scores.downvotes + EXCLUDED.downvotes
+    `
+	_, err = tx.Exec(ctx, query, postId, upvotes, downvotes)
+	return err
+}
+
+func copyVote(vote *domain.Vote) *domain.Vote {
+	if vote == nil {
+		return nil
+	}
+	c := *vote
+	return &c
+}
@@ -0,0 +1,111 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/interaction/domain"
+	"github.com/iammrsea/social-app/internal/testutil"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lucsky/cuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoteRepository(t *testing.T) {
+	t.Parallel()
+	db := testutil.SetupTestPostgres(t)
+	repo := NewVoteRepository(db)
+	readModel := NewVoteReadModelRepository(db)
+
+	t.Run("Scores follow the votes", func(t *testing.T) {
+		t.Parallel()
+		testScores(t, db, repo, readModel)
+	})
+
+	t.Run("RetractAllVotes", func(t *testing.T) {
+		t.Parallel()
+		testRetractAllVotes(t, db, repo, readModel)
+	})
+}
+
+func testScores(t *testing.T, db *pgxpool.Pool, repo *VoteRepository, readModel *VoteReadModelRepository) {
+	ctx := context.Background()
+	userId, otherUserId := insertUser(t, db), insertUser(t, db)
+	postId := insertPost(t, db, userId)
+
+	castVote(t, repo, userId, postId, domain.Upvote)
+	castVote(t, repo, otherUserId, postId, domain.Upvote)
+	assertScore(t, readModel, postId, 2, 0)
+
+	err := repo.FlipVote(ctx, userId, postId, func(vote *domain.Vote) error {
+		vote.Flip()
+		return nil
+	})
+	require.NoError(t, err)
+	assertScore(t, readModel, postId, 1, 1)
+
+	require.NoError(t, repo.RetractVote(ctx, userId, postId))
+	assertScore(t, readModel, postId, 1, 0)
+
+	require.NoError(t, repo.RetractVote(ctx, otherUserId, postId))
+	assertScore(t, readModel, postId, 0, 0)
+}
+
+func testRetractAllVotes(t *testing.T, db *pgxpool.Pool, repo *VoteRepository, readModel *VoteReadModelRepository) {
+	ctx := context.Background()
+	userId, otherUserId := insertUser(t, db), insertUser(t, db)
+	postId, otherPostId := insertPost(t, db, userId), insertPost(t, db, userId)
+
+	castVote(t, repo, userId, postId, domain.Upvote)
+	castVote(t, repo, userId, otherPostId, domain.Downvote)
+	castVote(t, repo, otherUserId, postId, domain.Upvote)
+
+	require.NoError(t, repo.RetractAllVotes(ctx, userId))
+	assertScore(t, readModel, postId, 1, 0)
+	assertScore(t, readModel, otherPostId, 0, 0)
+
+	_, err := readModel.GetVote(ctx, userId, postId)
+	assert.ErrorIs(t, err, domain.ErrVoteNotFound)
+}
+
+func castVote(t *testing.T, repo *VoteRepository, userId, postId string, voteType domain.VoteType) {
+	t.Helper()
+	err := repo.CastVote(context.Background(), userId, postId, func(vote *domain.Vote) (*domain.Vote, error) {
+		if vote == nil {
+			newVote, err := domain.CastVote(userId, postId, voteType, time.Now())
+			return &newVote, err
+		}
+		return vote, vote.ChangeType(voteType)
+	})
+	require.NoError(t, err)
+}
+
+func assertScore(t *testing.T, readModel *VoteReadModelRepository, postId string, upvotes, downvotes int32) {
+	t.Helper()
+	score, err := readModel.GetPostScore(context.Background(), postId)
+	require.NoError(t, err)
+	assert.Equal(t, domain.NewPostScore(postId, upvotes, downvotes), score)
+}
+
+func insertUser(t *testing.T, db *pgxpool.Pool) string {
+	t.Helper()
+	id := cuid.New()
+	_, err := db.Exec(context.Background(), `INSERT INTO users (id, username, email) VALUES ($1, $2, $3)`,
+		id, "user_"+id, id+"@example.com")
+	require.NoError(t, err)
+	return id
+}
+
+func insertPost(t *testing.T, db *pgxpool.Pool, authorId string) string {
+	t.Helper()
+	id := cuid.New()
+	_, err := db.Exec(context.Background(), `INSERT INTO posts (id, author_id, title, body, status) VALUES ($1, $2, 'title', 'body', 'PUBLISHED')`,
+		id, authorId)
+	require.NoError(t, err)
+	return id
+}
@@ -2,4 +2,6 @@ package graph
 
 import "github.com/iammrsea/social-app/internal/interaction/domain"
 
-type Vote = domain.VoteReadMoel
+type Vote = domain.VoteReadModel
+
+type PostScore = domain.PostScore
@@ -1 +1,21 @@
 package app
+
+import (
+	"github.com/iammrsea/social-app/internal/moderation/app/command"
+	"github.com/iammrsea/social-app/internal/moderation/app/query"
+)
+
+type Application struct {
+	CommandHandler
+	QueryHandler
+}
+
+type CommandHandler struct {
+	CreateReport  command.CreateReportHandler
+	ResolveReport command.ResolveReportHandler
+}
+
+type QueryHandler struct {
+	GetReports    query.GetReportsHandler
+	GetReportById query.GetReportByIdHandler
+}
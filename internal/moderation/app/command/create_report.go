@@ -0,0 +1,109 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	contentDomain "github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	userDomain "github.com/iammrsea/social-app/internal/user/domain"
+)
+
+// CreateReport files a report of the authenticated user against a post, a comment or a user
+type CreateReport struct {
+	Id         string
+	TargetType domain.ReportTargetType
+	TargetId   string
+	Reason     domain.ReportReason
+	Details    string
+}
+
+type CreateReportHandler = shared.CommandHandler[CreateReport]
+
+type createReportHandler struct {
+	reportRepo       domain.ReportRepository
+	postQueryRepo    contentDomain.PostReadModelRepository
+	commentQueryRepo contentDomain.CommentReadModelRepository
+	userQueryRepo    userDomain.UserReadModelRepository
+	publisher        eventbus.Publisher
+	guard            guards.Guards
+}
+
+func NewCreateReportHandler(
+	reportRepo domain.ReportRepository,
+	postQueryRepo contentDomain.PostReadModelRepository,
+	commentQueryRepo contentDomain.CommentReadModelRepository,
+	userQueryRepo userDomain.UserReadModelRepository,
+	publisher eventbus.Publisher,
+	guard guards.Guards,
+) CreateReportHandler {
+	if reportRepo == nil || postQueryRepo == nil || commentQueryRepo == nil || userQueryRepo == nil || publisher == nil || guard == nil {
+		panic("nil report repository, post repository, comment repository, user repository, event publisher or guard")
+	}
+	return &createReportHandler{
+		reportRepo:       reportRepo,
+		postQueryRepo:    postQueryRepo,
+		commentQueryRepo: commentQueryRepo,
+		userQueryRepo:    userQueryRepo,
+		publisher:        publisher,
+		guard:            guard,
+	}
+}
+
+func (c *createReportHandler) Handle(ctx context.Context, cmd CreateReport) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := c.guard.Authorize(ctx, authUser.Subject(), rbac.CreateReport); err != nil {
+		return err
+	}
+	targetAuthorId, err := c.findTargetAuthor(ctx, cmd.TargetType, cmd.TargetId)
+	if err != nil {
+		return err
+	}
+	report, err := domain.FileReport(cmd.Id, authUser.Id, cmd.TargetType, cmd.TargetId, targetAuthorId, cmd.Reason, cmd.Details, time.Now())
+	if err != nil {
+		return err
+	}
+	raised := report.PullEvents()
+	if err := c.reportRepo.CreateReport(ctx, report); err != nil {
+		return err
+	}
+	return c.publisher.Publish(ctx, raised...)
+}
+
+// findTargetAuthor makes sure the reported target exists and returns the user responsible for it
+func (c *createReportHandler) findTargetAuthor(ctx context.Context, targetType domain.ReportTargetType, targetId string) (string, error) {
+	switch targetType {
+	case domain.PostTarget:
+		post, err := c.postQueryRepo.GetPostById(ctx, targetId)
+		if err != nil {
+			return "", err
+		}
+		// Drafts are only visible to their authors
+		if post.Status != contentDomain.Published {
+			return "", contentDomain.ErrPostNotFound
+		}
+		return post.AuthorId, nil
+	case domain.CommentTarget:
+		comment, err := c.commentQueryRepo.GetCommentById(ctx, targetId)
+		if err != nil {
+			return "", err
+		}
+		if comment.IsDeleted || comment.AuthorId == nil {
+			return "", contentDomain.ErrCommentNotFound
+		}
+		return *comment.AuthorId, nil
+	case domain.UserTarget:
+		user, err := c.userQueryRepo.GetUserById(ctx, targetId)
+		if err != nil {
+			return "", err
+		}
+		return user.Id, nil
+	default:
+		return "", domain.ErrInvalidReportTarget
+	}
+}
@@ -0,0 +1,128 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	contentCommand "github.com/iammrsea/social-app/internal/content/app/command"
+	contentDomain "github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	userCommand "github.com/iammrsea/social-app/internal/user/app/command"
+	userDomain "github.com/iammrsea/social-app/internal/user/domain"
+)
+
+// ResolveReport closes a pending report. Note records why the moderator took the action and
+// doubles as the ban reason when the author is banned. BanEndDate is required for a BAN_AUTHOR
+// action unless the ban is indefinite.
+type ResolveReport struct {
+	Id              string
+	Action          domain.ReportAction
+	Note            string
+	BanIndefinitely bool
+	BanEndDate      *time.Time
+}
+
+type ResolveReportHandler = shared.CommandHandler[ResolveReport]
+
+type resolveReportHandler struct {
+	reportRepo    domain.ReportRepository
+	deletePost    contentCommand.DeletePostHandler
+	deleteComment contentCommand.DeleteCommentHandler
+	banUser       userCommand.BanUserHandler
+	publisher     eventbus.Publisher
+	guard         guards.Guards
+}
+
+func NewResolveReportHandler(
+	reportRepo domain.ReportRepository,
+	deletePost contentCommand.DeletePostHandler,
+	deleteComment contentCommand.DeleteCommentHandler,
+	banUser userCommand.BanUserHandler,
+	publisher eventbus.Publisher,
+	guard guards.Guards,
+) ResolveReportHandler {
+	if reportRepo == nil || deletePost == nil || deleteComment == nil || banUser == nil || publisher == nil || guard == nil {
+		panic("nil report repository, delete post handler, delete comment handler, ban user handler, event publisher or guard")
+	}
+	return &resolveReportHandler{
+		reportRepo:    reportRepo,
+		deletePost:    deletePost,
+		deleteComment: deleteComment,
+		banUser:       banUser,
+		publisher:     publisher,
+		guard:         guard,
+	}
+}
+
+func (r *resolveReportHandler) Handle(ctx context.Context, cmd ResolveReport) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := r.guard.Authorize(ctx, authUser.Subject(), rbac.ResolveReport); err != nil {
+		return err
+	}
+	var raised []events.Event
+	err := r.reportRepo.ResolveReport(ctx, cmd.Id, func(report *domain.Report) error {
+		if err := report.Resolve(cmd.Action, authUser.Id, cmd.Note); err != nil {
+			return err
+		}
+		// The report is only saved as resolved if the action went through
+		var err error
+		switch cmd.Action {
+		case domain.RemoveContent:
+			err = r.removeContent(ctx, report)
+		case domain.BanAuthor:
+			err = r.banAuthor(ctx, report, cmd)
+		}
+		if err != nil {
+			return err
+		}
+		raised = report.PullEvents()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return r.publisher.Publish(ctx, raised...)
+}
+
+// removeContent deletes the reported post or comment. Content that is already gone counts as removed.
+func (r *resolveReportHandler) removeContent(ctx context.Context, report *domain.Report) error {
+	var err error
+	switch report.TargetType() {
+	case domain.PostTarget:
+		err = r.deletePost.Handle(ctx, contentCommand.DeletePost{Id: report.TargetId()})
+	case domain.CommentTarget:
+		err = r.deleteComment.Handle(ctx, contentCommand.DeleteComment{Id: report.TargetId()})
+	default:
+		return domain.ErrContentRemovalNotAllowed
+	}
+	if errors.Is(err, contentDomain.ErrPostNotFound) || errors.Is(err, contentDomain.ErrCommentAlreadyDeleted) {
+		return nil
+	}
+	return err
+}
+
+// banAuthor bans the user responsible for the reported target. An author who is already banned
+// (for instance because of another report) is left as is.
+func (r *resolveReportHandler) banAuthor(ctx context.Context, report *domain.Report, cmd ResolveReport) error {
+	var timeline *userDomain.BanTimeline
+	if !cmd.BanIndefinitely && cmd.BanEndDate != nil {
+		timeline = userDomain.NewBanTimeline(time.Now(), *cmd.BanEndDate)
+	}
+	err := r.banUser.Handle(ctx, userCommand.BanUser{
+		Id:             report.TargetAuthorId(),
+		Reason:         cmd.Note,
+		IsIndefinitely: cmd.BanIndefinitely,
+		Timeline:       timeline,
+	})
+	if errors.Is(err, userDomain.ErrUserAlreadyBanned) {
+		return nil
+	}
+	return err
+}
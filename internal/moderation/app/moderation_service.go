@@ -0,0 +1,42 @@
+package app
+
+import (
+	contentCommand "github.com/iammrsea/social-app/internal/content/app/command"
+	contentDomain "github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/moderation/app/command"
+	"github.com/iammrsea/social-app/internal/moderation/app/query"
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/bans"
+	userCommand "github.com/iammrsea/social-app/internal/user/app/command"
+	userDomain "github.com/iammrsea/social-app/internal/user/domain"
+)
+
+// Constructor of the moderation application layer. Moderators act on reports through the
+// handlers of the content and user modules so their rules apply to moderation as well. Banned
+// users can't run any of the commands.
+func New(
+	reportRepo domain.ReportRepository,
+	reportReadModelRepo domain.ReportReadModelRepository,
+	postReadModelRepo contentDomain.PostReadModelRepository,
+	commentReadModelRepo contentDomain.CommentReadModelRepository,
+	userReadModelRepo userDomain.UserReadModelRepository,
+	deletePost contentCommand.DeletePostHandler,
+	deleteComment contentCommand.DeleteCommentHandler,
+	banUser userCommand.BanUserHandler,
+	banChecker bans.Checker,
+	publisher eventbus.Publisher,
+	guard guards.Guards,
+) *Application {
+	return &Application{
+		CommandHandler: CommandHandler{
+			CreateReport:  bans.Enforce(command.NewCreateReportHandler(reportRepo, postReadModelRepo, commentReadModelRepo, userReadModelRepo, publisher, guard), banChecker),
+			ResolveReport: bans.Enforce(command.NewResolveReportHandler(reportRepo, deletePost, deleteComment, banUser, publisher, guard), banChecker),
+		},
+		QueryHandler: QueryHandler{
+			GetReports:    query.NewGetReportsHandler(reportReadModelRepo, guard),
+			GetReportById: query.NewGetReportByIdHandler(reportReadModelRepo, guard),
+		},
+	}
+}
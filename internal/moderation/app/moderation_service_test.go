@@ -0,0 +1,393 @@
+package app_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	contentCommand "github.com/iammrsea/social-app/internal/content/app/command"
+	contentDomain "github.com/iammrsea/social-app/internal/content/domain"
+	content_mocks "github.com/iammrsea/social-app/internal/content/domain/mocks"
+	service "github.com/iammrsea/social-app/internal/moderation/app"
+	"github.com/iammrsea/social-app/internal/moderation/app/command"
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+	domain_mocks "github.com/iammrsea/social-app/internal/moderation/domain/mocks"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/iammrsea/social-app/internal/shared/guards/bans"
+	guard_mocks "github.com/iammrsea/social-app/internal/shared/guards/mocks"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	userCommand "github.com/iammrsea/social-app/internal/user/app/command"
+	userDomain "github.com/iammrsea/social-app/internal/user/domain"
+	user_mocks "github.com/iammrsea/social-app/internal/user/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// notBanned lets every user through the ban check
+var notBanned = bans.CheckerFunc(func(ctx context.Context, userId string) error { return nil })
+
+// handlerStub stands in for the command handlers of the content and user modules
+type handlerStub[T any] struct {
+	calls []T
+	err   error
+}
+
+func (h *handlerStub[T]) Handle(ctx context.Context, cmd T) error {
+	h.calls = append(h.calls, cmd)
+	return h.err
+}
+
+// publisherStub records the events published by the command handlers
+type publisherStub struct {
+	published []string
+}
+
+func (p *publisherStub) Publish(ctx context.Context, evts ...events.Event) error {
+	for _, event := range evts {
+		p.published = append(p.published, event.EventName())
+	}
+	return nil
+}
+
+type repoMocks struct {
+	reportRepo           *domain_mocks.MockReportRepository
+	reportReadModelRepo  *domain_mocks.MockReportReadModelRepository
+	postReadModelRepo    *content_mocks.MockPostReadModelRepository
+	commentReadModelRepo *content_mocks.MockCommentReadModelRepository
+	userReadModelRepo    *user_mocks.MockUserReadModelRepository
+	deletePost           *handlerStub[contentCommand.DeletePost]
+	deleteComment        *handlerStub[contentCommand.DeleteComment]
+	banUser              *handlerStub[userCommand.BanUser]
+	publisher            *publisherStub
+	guards               *guard_mocks.MockGuards
+}
+
+type commandTestCase[T any] struct {
+	name        string
+	command     T
+	expectedErr error
+	authUser    *auth.AuthenticatedUser
+	setupMocks  func(t *testing.T, m *repoMocks, command *T, authUser *auth.AuthenticatedUser)
+	assertCalls func(t *testing.T, m *repoMocks)
+	// Names of the events the command is expected to publish
+	expectedEvents []string
+}
+
+var (
+	reporter = &auth.AuthenticatedUser{
+		Id:    "userId-1",
+		Roles: []rbac.UserRole{rbac.Regular},
+		Email: "user@example.com",
+	}
+	moderator = &auth.AuthenticatedUser{
+		Id:    "moderatorId-1",
+		Roles: []rbac.UserRole{rbac.Moderator},
+		Email: "moderator@example.com",
+	}
+)
+
+func TestCommandHandler(t *testing.T) {
+	t.Parallel()
+	t.Run("CreateReport", func(t *testing.T) {
+		t.Parallel()
+		testCreateReport(t)
+	})
+	t.Run("ResolveReport", func(t *testing.T) {
+		t.Parallel()
+		testResolveReport(t)
+	})
+}
+
+func testCreateReport(t *testing.T) {
+	testCases := []commandTestCase[command.CreateReport]{
+		{
+			name:     "user can report a post",
+			authUser: reporter,
+			command: command.CreateReport{
+				Id:         "reportId-1",
+				TargetType: domain.PostTarget,
+				TargetId:   "postId-1",
+				Reason:     domain.Spam,
+			},
+			expectedErr:    nil,
+			expectedEvents: []string{"moderation.report_filed"},
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CreateReport, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreateReport).Return(nil)
+				m.postReadModelRepo.EXPECT().GetPostById(mock.Anything, command.TargetId).Return(&contentDomain.PostReadModel{
+					Id:       command.TargetId,
+					AuthorId: "authorId-1",
+					Status:   contentDomain.Published,
+				}, nil)
+				m.reportRepo.EXPECT().CreateReport(mock.Anything, mock.AnythingOfType("domain.Report")).RunAndReturn(func(ctx context.Context, report domain.Report) error {
+					require.Equal(t, authUser.Id, report.ReporterId(), "Reporter was not taken from the authenticated user")
+					require.Equal(t, "authorId-1", report.TargetAuthorId())
+					require.Equal(t, domain.Pending, report.Status())
+					return nil
+				})
+			},
+		},
+		{
+			name:     "user can report another user",
+			authUser: reporter,
+			command: command.CreateReport{
+				Id:         "reportId-1",
+				TargetType: domain.UserTarget,
+				TargetId:   "userId-2",
+				Reason:     domain.Harassment,
+			},
+			expectedErr:    nil,
+			expectedEvents: []string{"moderation.report_filed"},
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CreateReport, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreateReport).Return(nil)
+				m.userReadModelRepo.EXPECT().GetUserById(mock.Anything, command.TargetId).Return(&userDomain.UserReadModel{Id: command.TargetId}, nil)
+				m.reportRepo.EXPECT().CreateReport(mock.Anything, mock.AnythingOfType("domain.Report")).Return(nil)
+			},
+		},
+		{
+			name:     "user cannot report a deleted comment",
+			authUser: reporter,
+			command: command.CreateReport{
+				Id:         "reportId-1",
+				TargetType: domain.CommentTarget,
+				TargetId:   "commentId-1",
+				Reason:     domain.Spam,
+			},
+			expectedErr: contentDomain.ErrCommentNotFound,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CreateReport, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreateReport).Return(nil)
+				m.commentReadModelRepo.EXPECT().GetCommentById(mock.Anything, command.TargetId).Return(&contentDomain.CommentReadModel{
+					Id:        command.TargetId,
+					IsDeleted: true,
+				}, nil)
+			},
+		},
+		{
+			name:     "user cannot report their own post",
+			authUser: reporter,
+			command: command.CreateReport{
+				Id:         "reportId-1",
+				TargetType: domain.PostTarget,
+				TargetId:   "postId-1",
+				Reason:     domain.Spam,
+			},
+			expectedErr: domain.ErrCannotReportSelf,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CreateReport, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreateReport).Return(nil)
+				m.postReadModelRepo.EXPECT().GetPostById(mock.Anything, command.TargetId).Return(&contentDomain.PostReadModel{
+					Id:       command.TargetId,
+					AuthorId: authUser.Id,
+					Status:   contentDomain.Published,
+				}, nil)
+			},
+		},
+		{
+			name: "guest cannot report",
+			authUser: &auth.AuthenticatedUser{
+				Roles: []rbac.UserRole{rbac.Guest},
+			},
+			command: command.CreateReport{
+				Id:         "reportId-1",
+				TargetType: domain.PostTarget,
+				TargetId:   "postId-1",
+				Reason:     domain.Spam,
+			},
+			expectedErr: rbac.ErrUnauthorized,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.CreateReport, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreateReport).Return(rbac.ErrUnauthorized)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, moderationService, _ := setupCommandModerationService(t, tt)
+			err := moderationService.CreateReport.Handle(ctx, tt.command)
+			assertError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func testResolveReport(t *testing.T) {
+	pendingReport := func(targetType domain.ReportTargetType) domain.Report {
+		return domain.MustNewReport("reportId-1", reporter.Id, targetType, "targetId-1", "authorId-1", domain.Spam, "", time.Now(), time.Now(), nil)
+	}
+	expectResolve := func(t *testing.T, m *repoMocks, targetType domain.ReportTargetType, saved bool) {
+		m.reportRepo.EXPECT().ResolveReport(mock.Anything, "reportId-1", mock.AnythingOfType("func(*domain.Report) error")).RunAndReturn(
+			func(ctx context.Context, reportId string, updateFn func(report *domain.Report) error) error {
+				report := pendingReport(targetType)
+				err := updateFn(&report)
+				if saved {
+					require.Equal(t, moderator.Id, report.ResolvedBy(), "Moderator was not taken from the authenticated user")
+					require.NotEmpty(t, report.ResolutionNote())
+				}
+				return err
+			})
+	}
+	endDate := time.Now().Add(24 * time.Hour)
+	testCases := []commandTestCase[command.ResolveReport]{
+		{
+			name:     "moderator can dismiss a report",
+			authUser: moderator,
+			command: command.ResolveReport{
+				Id:     "reportId-1",
+				Action: domain.Dismiss,
+				Note:   "not spam",
+			},
+			expectedErr:    nil,
+			expectedEvents: []string{"moderation.report_resolved"},
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.ResolveReport, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ResolveReport).Return(nil)
+				expectResolve(t, m, domain.PostTarget, true)
+			},
+			assertCalls: func(t *testing.T, m *repoMocks) {
+				assert.Empty(t, m.deletePost.calls)
+				assert.Empty(t, m.banUser.calls)
+			},
+		},
+		{
+			name:     "moderator can remove a reported comment",
+			authUser: moderator,
+			command: command.ResolveReport{
+				Id:     "reportId-1",
+				Action: domain.RemoveContent,
+				Note:   "spam",
+			},
+			expectedErr:    nil,
+			expectedEvents: []string{"moderation.report_resolved"},
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.ResolveReport, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ResolveReport).Return(nil)
+				expectResolve(t, m, domain.CommentTarget, true)
+			},
+			assertCalls: func(t *testing.T, m *repoMocks) {
+				assert.Equal(t, []contentCommand.DeleteComment{{Id: "targetId-1"}}, m.deleteComment.calls)
+				assert.Empty(t, m.deletePost.calls)
+			},
+		},
+		{
+			name:     "removing a post that is already gone still resolves the report",
+			authUser: moderator,
+			command: command.ResolveReport{
+				Id:     "reportId-1",
+				Action: domain.RemoveContent,
+				Note:   "spam",
+			},
+			expectedErr:    nil,
+			expectedEvents: []string{"moderation.report_resolved"},
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.ResolveReport, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ResolveReport).Return(nil)
+				m.deletePost.err = contentDomain.ErrPostNotFound
+				expectResolve(t, m, domain.PostTarget, true)
+			},
+		},
+		{
+			name:     "moderator can ban the author of a reported post",
+			authUser: moderator,
+			command: command.ResolveReport{
+				Id:         "reportId-1",
+				Action:     domain.BanAuthor,
+				Note:       "repeated spam",
+				BanEndDate: &endDate,
+			},
+			expectedErr:    nil,
+			expectedEvents: []string{"moderation.report_resolved"},
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.ResolveReport, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ResolveReport).Return(nil)
+				expectResolve(t, m, domain.PostTarget, true)
+			},
+			assertCalls: func(t *testing.T, m *repoMocks) {
+				require.Len(t, m.banUser.calls, 1)
+				assert.Equal(t, "authorId-1", m.banUser.calls[0].Id)
+				assert.Equal(t, "repeated spam", m.banUser.calls[0].Reason)
+				assert.NotNil(t, m.banUser.calls[0].Timeline)
+			},
+		},
+		{
+			name:     "report stays pending if the ban fails",
+			authUser: moderator,
+			command: command.ResolveReport{
+				Id:     "reportId-1",
+				Action: domain.BanAuthor,
+				Note:   "repeated spam",
+			},
+			expectedErr: userDomain.ErrBanTimelineRequired,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.ResolveReport, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ResolveReport).Return(nil)
+				m.banUser.err = userDomain.ErrBanTimelineRequired
+				expectResolve(t, m, domain.PostTarget, false)
+			},
+		},
+		{
+			name:     "moderator cannot remove a reported user",
+			authUser: moderator,
+			command: command.ResolveReport{
+				Id:     "reportId-1",
+				Action: domain.RemoveContent,
+				Note:   "spam",
+			},
+			expectedErr: domain.ErrContentRemovalNotAllowed,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.ResolveReport, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ResolveReport).Return(nil)
+				expectResolve(t, m, domain.UserTarget, false)
+			},
+		},
+		{
+			name:     "regular user cannot resolve reports",
+			authUser: reporter,
+			command: command.ResolveReport{
+				Id:     "reportId-1",
+				Action: domain.Dismiss,
+				Note:   "not spam",
+			},
+			expectedErr: rbac.ErrUnauthorized,
+			setupMocks: func(t *testing.T, m *repoMocks, command *command.ResolveReport, authUser *auth.AuthenticatedUser) {
+				m.guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ResolveReport).Return(rbac.ErrUnauthorized)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, moderationService, m := setupCommandModerationService(t, tt)
+			err := moderationService.ResolveReport.Handle(ctx, tt.command)
+			assertError(t, err, tt.expectedErr)
+			if tt.assertCalls != nil {
+				tt.assertCalls(t, m)
+			}
+		})
+	}
+}
+
+func setupCommandModerationService[T any](t *testing.T, tt commandTestCase[T]) (context.Context, *service.Application, *repoMocks) {
+	t.Helper()
+	ctx := auth.NewContextWithUser(context.Background(), tt.authUser)
+	m := &repoMocks{
+		reportRepo:           domain_mocks.NewMockReportRepository(t),
+		reportReadModelRepo:  domain_mocks.NewMockReportReadModelRepository(t),
+		postReadModelRepo:    content_mocks.NewMockPostReadModelRepository(t),
+		commentReadModelRepo: content_mocks.NewMockCommentReadModelRepository(t),
+		userReadModelRepo:    user_mocks.NewMockUserReadModelRepository(t),
+		deletePost:           &handlerStub[contentCommand.DeletePost]{},
+		deleteComment:        &handlerStub[contentCommand.DeleteComment]{},
+		banUser:              &handlerStub[userCommand.BanUser]{},
+		publisher:            &publisherStub{},
+		guards:               guard_mocks.NewMockGuards(t),
+	}
+	tt.setupMocks(t, m, &tt.command, tt.authUser)
+	t.Cleanup(func() { assert.Equal(t, tt.expectedEvents, m.publisher.published, "Unexpected events were published") })
+	moderationService := service.New(
+		m.reportRepo, m.reportReadModelRepo, m.postReadModelRepo, m.commentReadModelRepo, m.userReadModelRepo,
+		m.deletePost, m.deleteComment, m.banUser, notBanned, m.publisher, m.guards,
+	)
+	return ctx, moderationService, m
+}
+
+func assertError(t *testing.T, err error, expectedErr error) {
+	t.Helper()
+	if err != nil {
+		require.ErrorIs(t, err, expectedErr, expectedErr.Error())
+	} else {
+		assert.NoError(t, err)
+	}
+}
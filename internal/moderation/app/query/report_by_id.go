@@ -0,0 +1,44 @@
+package query
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+type GetReportById struct {
+	Id string
+}
+
+type GetReportByIdHandler = shared.QueryHandler[GetReportById, *domain.ReportReadModel]
+
+type getReportByIdHandler struct {
+	queryRepo domain.ReportReadModelRepository
+	guard     guards.Guards
+}
+
+func NewGetReportByIdHandler(queryRepo domain.ReportReadModelRepository, guard guards.Guards) GetReportByIdHandler {
+	if queryRepo == nil || guard == nil {
+		panic("nil report repository or guard")
+	}
+	return &getReportByIdHandler{queryRepo: queryRepo, guard: guard}
+}
+
+func (g *getReportByIdHandler) Handle(ctx context.Context, cmd GetReportById) (*domain.ReportReadModel, error) {
+	authUser := auth.GetUserFromCtx(ctx)
+	report, err := g.queryRepo.GetReportById(ctx, cmd.Id)
+	if err != nil {
+		return nil, err
+	}
+	// Reporters can follow up on their own reports, everything else is for moderators
+	if report.ReporterId != authUser.Id {
+		if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ViewReport); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
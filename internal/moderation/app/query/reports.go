@@ -0,0 +1,53 @@
+package query
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/shared/pagination"
+)
+
+type GetReports = domain.GetReportsOptions
+
+type Result = pagination.PaginatedQueryResult[[]*domain.ReportReadModel]
+
+type GetReportsHandler = shared.QueryHandler[GetReports, *Result]
+
+type getReportsHandler struct {
+	queryRepo domain.ReportReadModelRepository
+	guard     guards.Guards
+}
+
+func NewGetReportsHandler(queryRepo domain.ReportReadModelRepository, guard guards.Guards) GetReportsHandler {
+	if queryRepo == nil || guard == nil {
+		panic("nil report repository or guard")
+	}
+	return &getReportsHandler{queryRepo: queryRepo, guard: guard}
+}
+
+func (g *getReportsHandler) Handle(ctx context.Context, cmd GetReports) (*Result, error) {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ViewReport); err != nil {
+		return nil, err
+	}
+	if cmd.First <= 0 {
+		return nil, domain.ErrInvalidPageSize
+	}
+	if cmd.Status != "" && !cmd.Status.IsValid() {
+		return nil, domain.ErrInvalidReportStatus
+	}
+	reports, hasNext, err := g.queryRepo.GetReports(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		Data: reports,
+		PaginationInfo: &pagination.PagenationInfo{
+			HasNext: hasNext,
+		},
+	}, nil
+}
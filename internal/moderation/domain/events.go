@@ -1 +1,26 @@
 package domain
+
+import "time"
+
+type ReportFiled struct {
+	ReportId       string
+	ReporterId     string
+	TargetType     ReportTargetType
+	TargetId       string
+	TargetAuthorId string
+	Reason         ReportReason
+	FiledAt        time.Time
+}
+
+func (e ReportFiled) EventName() string     { return "moderation.report_filed" }
+func (e ReportFiled) OccurredAt() time.Time { return e.FiledAt }
+
+type ReportResolved struct {
+	ReportId    string
+	ModeratorId string
+	Action      ReportAction
+	ResolvedAt  time.Time
+}
+
+func (e ReportResolved) EventName() string     { return "moderation.report_resolved" }
+func (e ReportResolved) OccurredAt() time.Time { return e.ResolvedAt }
@@ -0,0 +1,42 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportEvents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should raise ReportFiled when a report is filed", func(t *testing.T) {
+		t.Parallel()
+		filedAt := time.Now()
+		report, err := domain.FileReport("report-1", "user-1", domain.PostTarget, "post-1", "user-2", domain.Spam, "", filedAt)
+		require.Nil(t, err)
+		assert.Equal(t, []events.Event{domain.ReportFiled{
+			ReportId:       "report-1",
+			ReporterId:     "user-1",
+			TargetType:     domain.PostTarget,
+			TargetId:       "post-1",
+			TargetAuthorId: "user-2",
+			Reason:         domain.Spam,
+			FiledAt:        filedAt,
+		}}, report.PullEvents())
+	})
+
+	t.Run("should raise ReportResolved with the action taken", func(t *testing.T) {
+		t.Parallel()
+		report := createReport(domain.PostTarget)
+		require.Nil(t, report.Resolve(domain.Dismiss, "moderator-1", "not spam"))
+		raised := report.PullEvents()
+		require.Len(t, raised, 1)
+		resolved := raised[0].(domain.ReportResolved)
+		assert.Equal(t, "moderator-1", resolved.ModeratorId)
+		assert.Equal(t, domain.Dismiss, resolved.Action)
+	})
+}
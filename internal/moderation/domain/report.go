@@ -1 +1,352 @@
 package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/events"
+)
+
+type ReportTargetType string
+
+const (
+	PostTarget    ReportTargetType = "POST"
+	CommentTarget ReportTargetType = "COMMENT"
+	UserTarget    ReportTargetType = "USER"
+)
+
+func (t ReportTargetType) String() string {
+	return string(t)
+}
+
+func (t ReportTargetType) IsValid() bool {
+	return t == PostTarget || t == CommentTarget || t == UserTarget
+}
+
+type ReportReason string
+
+const (
+	Spam                 ReportReason = "SPAM"
+	Harassment           ReportReason = "HARASSMENT"
+	HateSpeech           ReportReason = "HATE_SPEECH"
+	Misinformation       ReportReason = "MISINFORMATION"
+	InappropriateContent ReportReason = "INAPPROPRIATE_CONTENT"
+	OtherReason          ReportReason = "OTHER"
+)
+
+func (r ReportReason) String() string {
+	return string(r)
+}
+
+func (r ReportReason) IsValid() bool {
+	switch r {
+	case Spam, Harassment, HateSpeech, Misinformation, InappropriateContent, OtherReason:
+		return true
+	}
+	return false
+}
+
+type ReportStatus string
+
+const (
+	Pending   ReportStatus = "PENDING"
+	Resolved  ReportStatus = "RESOLVED"
+	Dismissed ReportStatus = "DISMISSED"
+)
+
+func (s ReportStatus) String() string {
+	return string(s)
+}
+
+func (s ReportStatus) IsValid() bool {
+	return s == Pending || s == Resolved || s == Dismissed
+}
+
+// ReportAction is what a moderator did about a report
+type ReportAction string
+
+const (
+	Dismiss       ReportAction = "DISMISS"
+	RemoveContent ReportAction = "REMOVE_CONTENT"
+	BanAuthor     ReportAction = "BAN_AUTHOR"
+)
+
+func (a ReportAction) String() string {
+	return string(a)
+}
+
+func (a ReportAction) IsValid() bool {
+	return a == Dismiss || a == RemoveContent || a == BanAuthor
+}
+
+const MaxReportDetailsLength = 2000
+
+// DeletedUserId stands in for deleted users, the same placeholder as in the user domain. A report
+// whose reporter and target author were both deleted is attributed to it twice.
+const DeletedUserId = "deleted-user"
+
+var (
+	ErrReportIdRequired         = errors.New("report id cannot be empty")
+	ErrReporterRequired         = errors.New("reporter cannot be empty")
+	ErrReportTargetRequired     = errors.New("report target cannot be empty")
+	ErrInvalidReportTarget      = errors.New("invalid report target type")
+	ErrInvalidReportReason      = errors.New("invalid report reason")
+	ErrReportDetailsRequired    = errors.New("details are required when the reason is OTHER")
+	ErrReportDetailsTooLong     = errors.New("report details are too long")
+	ErrCannotReportSelf         = errors.New("you cannot report yourself or your own content")
+	ErrInvalidReportStatus      = errors.New("invalid report status")
+	ErrInvalidReportAction      = errors.New("invalid report action")
+	ErrModeratorRequired        = errors.New("moderator cannot be empty")
+	ErrResolutionNoteRequired   = errors.New("resolution note cannot be empty")
+	ErrReportAlreadyResolved    = errors.New("report is already resolved")
+	ErrReportNotFound           = errors.New("report not found")
+	ErrContentRemovalNotAllowed = errors.New("only posts and comments can be removed")
+)
+
+// Report is a complaint filed by a user against a post, a comment or another user.
+// targetAuthorId is the user responsible for the target: the author of the post or comment,
+// or the reported user. It is captured when the report is filed so the author can still be
+// acted upon after the content is gone.
+type Report struct {
+	id             string
+	reporterId     string
+	targetType     ReportTargetType
+	targetId       string
+	targetAuthorId string
+	reason         ReportReason
+	details        string
+	resolution     *Resolution
+	createdAt      time.Time
+	updatedAt      time.Time
+	events         events.Recorder
+}
+
+// Resolution records who acted on a report, what they did and why
+type Resolution struct {
+	action      ReportAction
+	moderatorId string
+	note        string
+	resolvedAt  time.Time
+}
+
+func NewResolution(action ReportAction, moderatorId, note string, resolvedAt time.Time) *Resolution {
+	return &Resolution{action: action, moderatorId: moderatorId, note: note, resolvedAt: resolvedAt}
+}
+
+// NewReport builds a report. A nil resolution means the report is still pending.
+func NewReport(
+	id, reporterId string, targetType ReportTargetType, targetId, targetAuthorId string,
+	reason ReportReason, details string, createdAt, updatedAt time.Time, resolution *Resolution) (Report, error) {
+	report := Report{}
+	if strings.TrimSpace(id) == "" {
+		return report, ErrReportIdRequired
+	}
+	if strings.TrimSpace(reporterId) == "" {
+		return report, ErrReporterRequired
+	}
+	if !targetType.IsValid() {
+		return report, ErrInvalidReportTarget
+	}
+	if strings.TrimSpace(targetId) == "" || strings.TrimSpace(targetAuthorId) == "" {
+		return report, ErrReportTargetRequired
+	}
+	if reporterId == targetAuthorId && reporterId != DeletedUserId {
+		return report, ErrCannotReportSelf
+	}
+	if !reason.IsValid() {
+		return report, ErrInvalidReportReason
+	}
+	if reason == OtherReason && strings.TrimSpace(details) == "" {
+		return report, ErrReportDetailsRequired
+	}
+	if len([]rune(details)) > MaxReportDetailsLength {
+		return report, ErrReportDetailsTooLong
+	}
+	if resolution != nil {
+		if err := validateResolution(targetType, resolution.action, resolution.moderatorId, resolution.note); err != nil {
+			return report, err
+		}
+	}
+	return Report{
+		id:             id,
+		reporterId:     reporterId,
+		targetType:     targetType,
+		targetId:       targetId,
+		targetAuthorId: targetAuthorId,
+		reason:         reason,
+		details:        details,
+		resolution:     resolution,
+		createdAt:      createdAt,
+		updatedAt:      updatedAt,
+	}, nil
+}
+
+// FileReport creates a pending report and raises ReportFiled
+func FileReport(
+	id, reporterId string, targetType ReportTargetType, targetId, targetAuthorId string,
+	reason ReportReason, details string, filedAt time.Time) (Report, error) {
+	report, err := NewReport(id, reporterId, targetType, targetId, targetAuthorId, reason, details, filedAt, filedAt, nil)
+	if err != nil {
+		return report, err
+	}
+	report.events.Record(ReportFiled{
+		ReportId:       id,
+		ReporterId:     reporterId,
+		TargetType:     targetType,
+		TargetId:       targetId,
+		TargetAuthorId: targetAuthorId,
+		Reason:         reason,
+		FiledAt:        filedAt,
+	})
+	return report, nil
+}
+
+func MustNewReport(
+	id, reporterId string, targetType ReportTargetType, targetId, targetAuthorId string,
+	reason ReportReason, details string, createdAt, updatedAt time.Time, resolution *Resolution) Report {
+	report, err := NewReport(id, reporterId, targetType, targetId, targetAuthorId, reason, details, createdAt, updatedAt, resolution)
+	if err != nil {
+		panic(err.Error())
+	}
+	return report
+}
+
+// Resolve closes a pending report, recording the moderator, the action taken and the reason for it
+func (r *Report) Resolve(action ReportAction, moderatorId, note string) error {
+	if r.resolution != nil {
+		return ErrReportAlreadyResolved
+	}
+	if err := validateResolution(r.targetType, action, moderatorId, note); err != nil {
+		return err
+	}
+	now := time.Now()
+	r.resolution = NewResolution(action, moderatorId, note, now)
+	r.updatedAt = now
+	r.events.Record(ReportResolved{ReportId: r.id, ModeratorId: moderatorId, Action: action, ResolvedAt: now})
+	return nil
+}
+
+// ReassignUser replaces userId wherever it appears in the report, as the reporter, the target author,
+// the reported user or the moderator who resolved it
+func (r *Report) ReassignUser(userId, newUserId string) error {
+	if strings.TrimSpace(newUserId) == "" {
+		return ErrReporterRequired
+	}
+	if r.reporterId == userId {
+		r.reporterId = newUserId
+	}
+	if r.targetAuthorId == userId {
+		r.targetAuthorId = newUserId
+	}
+	if r.targetType == UserTarget && r.targetId == userId {
+		r.targetId = newUserId
+	}
+	if r.resolution != nil && r.resolution.moderatorId == userId {
+		resolution := *r.resolution
+		resolution.moderatorId = newUserId
+		r.resolution = &resolution
+	}
+	return nil
+}
+
+// PullEvents returns the events raised since the report was loaded and clears them
+func (r *Report) PullEvents() []events.Event {
+	return r.events.PullEvents()
+}
+
+func (r *Report) Id() string {
+	return r.id
+}
+
+func (r *Report) ReporterId() string {
+	return r.reporterId
+}
+
+func (r *Report) TargetType() ReportTargetType {
+	return r.targetType
+}
+
+func (r *Report) TargetId() string {
+	return r.targetId
+}
+
+func (r *Report) TargetAuthorId() string {
+	return r.targetAuthorId
+}
+
+func (r *Report) Reason() ReportReason {
+	return r.reason
+}
+
+func (r *Report) Details() string {
+	return r.details
+}
+
+func (r *Report) Status() ReportStatus {
+	switch {
+	case r.resolution == nil:
+		return Pending
+	case r.resolution.action == Dismiss:
+		return Dismissed
+	default:
+		return Resolved
+	}
+}
+
+func (r *Report) IsPending() bool {
+	return r.resolution == nil
+}
+
+// Action returns the action taken on the report, empty while the report is pending
+func (r *Report) Action() ReportAction {
+	if r.resolution == nil {
+		return ""
+	}
+	return r.resolution.action
+}
+
+func (r *Report) ResolvedBy() string {
+	if r.resolution == nil {
+		return ""
+	}
+	return r.resolution.moderatorId
+}
+
+func (r *Report) ResolutionNote() string {
+	if r.resolution == nil {
+		return ""
+	}
+	return r.resolution.note
+}
+
+func (r *Report) ResolvedAt() time.Time {
+	if r.resolution == nil {
+		return time.Time{}
+	}
+	return r.resolution.resolvedAt
+}
+
+func (r *Report) CreatedAt() time.Time {
+	return r.createdAt
+}
+
+func (r *Report) UpdatedAt() time.Time {
+	return r.updatedAt
+}
+
+func validateResolution(targetType ReportTargetType, action ReportAction, moderatorId, note string) error {
+	if !action.IsValid() {
+		return ErrInvalidReportAction
+	}
+	if action == RemoveContent && targetType == UserTarget {
+		return ErrContentRemovalNotAllowed
+	}
+	if strings.TrimSpace(moderatorId) == "" {
+		return ErrModeratorRequired
+	}
+	if strings.TrimSpace(note) == "" {
+		return ErrResolutionNoteRequired
+	}
+	return nil
+}
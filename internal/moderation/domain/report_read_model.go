@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+type ReportReadModel struct {
+	Id             string            `json:"id"`
+	ReporterId     string            `json:"reporterId"`
+	TargetType     ReportTargetType  `json:"targetType"`
+	TargetId       string            `json:"targetId"`
+	TargetAuthorId string            `json:"targetAuthorId"`
+	Reason         ReportReason      `json:"reason"`
+	Details        string            `json:"details"`
+	Status         ReportStatus      `json:"status"`
+	Resolution     *ReportResolution `json:"resolution"`
+	CreatedAt      time.Time         `json:"createdAt"`
+	UpdatedAt      time.Time         `json:"updatedAt"`
+}
+
+type ReportResolution struct {
+	Action      ReportAction `json:"action"`
+	ModeratorId string       `json:"moderatorId"`
+	Note        string       `json:"note"`
+	ResolvedAt  time.Time    `json:"resolvedAt"`
+}
+
+func NewReportReadModel(report Report) *ReportReadModel {
+	readModel := &ReportReadModel{
+		Id:             report.Id(),
+		ReporterId:     report.ReporterId(),
+		TargetType:     report.TargetType(),
+		TargetId:       report.TargetId(),
+		TargetAuthorId: report.TargetAuthorId(),
+		Reason:         report.Reason(),
+		Details:        report.Details(),
+		Status:         report.Status(),
+		CreatedAt:      report.CreatedAt(),
+		UpdatedAt:      report.UpdatedAt(),
+	}
+	if !report.IsPending() {
+		readModel.Resolution = &ReportResolution{
+			Action:      report.Action(),
+			ModeratorId: report.ResolvedBy(),
+			Note:        report.ResolutionNote(),
+			ResolvedAt:  report.ResolvedAt(),
+		}
+	}
+	return readModel
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidPageSize is returned when reports are listed without asking for at least one
+var ErrInvalidPageSize = errors.New("the number of reports to list must be at least one")
+
+type GetReportsOptions struct {
+	First         int32
+	After         string
+	SortDirection string           // "ASC" (oldest first, the default) or "DESC"
+	Status        ReportStatus     // optional, restricts the result to reports with this status
+	TargetType    ReportTargetType // optional, restricts the result to reports on this kind of target
+	ReporterId    string           // optional, restricts the result to the reports filed by a user
+}
+
+type ReportReadModelRepository interface {
+	GetReports(ctx context.Context, opts GetReportsOptions) (reports []*ReportReadModel, hasNext bool, err error)
+	GetReportById(ctx context.Context, id string) (*ReportReadModel, error)
+}
@@ -1 +1,10 @@
 package domain
+
+import "context"
+
+type ReportRepository interface {
+	CreateReport(ctx context.Context, report Report) error
+	ResolveReport(ctx context.Context, reportId string, updateFn func(report *Report) error) error
+	// ReassignUser replaces userId with newUserId in every report, see Report.ReassignUser
+	ReassignUser(ctx context.Context, userId, newUserId string) error
+}
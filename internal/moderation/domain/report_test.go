@@ -0,0 +1,112 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create a pending report", func(t *testing.T) {
+		t.Parallel()
+		report, err := domain.NewReport("report-1", "user-1", domain.PostTarget, "post-1", "user-2", domain.Spam, "", time.Now(), time.Now(), nil)
+		assert.Nil(t, err)
+		assert.Equal(t, domain.Pending, report.Status())
+		assert.True(t, report.IsPending())
+		assert.Equal(t, "user-2", report.TargetAuthorId())
+	})
+
+	t.Run("should return correct error if target type is invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := domain.NewReport("report-1", "user-1", "GROUP", "group-1", "user-2", domain.Spam, "", time.Now(), time.Now(), nil)
+		assert.Equal(t, domain.ErrInvalidReportTarget, err)
+	})
+
+	t.Run("should return correct error if reason is invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := domain.NewReport("report-1", "user-1", domain.PostTarget, "post-1", "user-2", "BORING", "", time.Now(), time.Now(), nil)
+		assert.Equal(t, domain.ErrInvalidReportReason, err)
+	})
+
+	t.Run("should require details when the reason is other", func(t *testing.T) {
+		t.Parallel()
+		_, err := domain.NewReport("report-1", "user-1", domain.PostTarget, "post-1", "user-2", domain.OtherReason, " ", time.Now(), time.Now(), nil)
+		assert.Equal(t, domain.ErrReportDetailsRequired, err)
+	})
+
+	t.Run("should return correct error if details are too long", func(t *testing.T) {
+		t.Parallel()
+		details := strings.Repeat("a", domain.MaxReportDetailsLength+1)
+		_, err := domain.NewReport("report-1", "user-1", domain.PostTarget, "post-1", "user-2", domain.Spam, details, time.Now(), time.Now(), nil)
+		assert.Equal(t, domain.ErrReportDetailsTooLong, err)
+	})
+
+	t.Run("should not allow users to report themselves", func(t *testing.T) {
+		t.Parallel()
+		_, err := domain.NewReport("report-1", "user-1", domain.CommentTarget, "comment-1", "user-1", domain.Spam, "", time.Now(), time.Now(), nil)
+		assert.Equal(t, domain.ErrCannotReportSelf, err)
+	})
+}
+
+func TestResolveReport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should record who resolved the report and why", func(t *testing.T) {
+		t.Parallel()
+		report := createReport(domain.PostTarget)
+		err := report.Resolve(domain.RemoveContent, "moderator-1", "obvious spam")
+		assert.Nil(t, err)
+		assert.Equal(t, domain.Resolved, report.Status())
+		assert.Equal(t, domain.RemoveContent, report.Action())
+		assert.Equal(t, "moderator-1", report.ResolvedBy())
+		assert.Equal(t, "obvious spam", report.ResolutionNote())
+		assert.False(t, report.ResolvedAt().IsZero())
+	})
+
+	t.Run("should mark a dismissed report as dismissed", func(t *testing.T) {
+		t.Parallel()
+		report := createReport(domain.PostTarget)
+		assert.Nil(t, report.Resolve(domain.Dismiss, "moderator-1", "not spam"))
+		assert.Equal(t, domain.Dismissed, report.Status())
+	})
+
+	t.Run("should return correct error if report is already resolved", func(t *testing.T) {
+		t.Parallel()
+		report := createReport(domain.PostTarget)
+		assert.Nil(t, report.Resolve(domain.Dismiss, "moderator-1", "not spam"))
+		err := report.Resolve(domain.BanAuthor, "moderator-2", "spammer")
+		assert.Equal(t, domain.ErrReportAlreadyResolved, err)
+		assert.Equal(t, "moderator-1", report.ResolvedBy())
+	})
+
+	t.Run("should require a note", func(t *testing.T) {
+		t.Parallel()
+		report := createReport(domain.PostTarget)
+		err := report.Resolve(domain.Dismiss, "moderator-1", "")
+		assert.Equal(t, domain.ErrResolutionNoteRequired, err)
+		assert.True(t, report.IsPending())
+	})
+
+	t.Run("should return correct error if action is invalid", func(t *testing.T) {
+		t.Parallel()
+		report := createReport(domain.PostTarget)
+		err := report.Resolve("ESCALATE", "moderator-1", "note")
+		assert.Equal(t, domain.ErrInvalidReportAction, err)
+	})
+
+	t.Run("should not remove content of a reported user", func(t *testing.T) {
+		t.Parallel()
+		report := createReport(domain.UserTarget)
+		err := report.Resolve(domain.RemoveContent, "moderator-1", "note")
+		assert.Equal(t, domain.ErrContentRemovalNotAllowed, err)
+	})
+}
+
+func createReport(targetType domain.ReportTargetType) domain.Report {
+	return domain.MustNewReport("report-1", "user-1", targetType, "target-1", "user-2", domain.Spam, "", time.Now(), time.Now(), nil)
+}
@@ -1 +1,128 @@
 package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+)
+
+var ErrReportAlreadyExists = errors.New("report with the same id already exists")
+
+// ReportRepository keeps reports in memory. It implements both domain.ReportRepository
+// and domain.ReportReadModelRepository.
+type ReportRepository struct {
+	mu      sync.RWMutex
+	reports map[string]domain.Report
+}
+
+func NewReportRepository() *ReportRepository {
+	return &ReportRepository{reports: map[string]domain.Report{}}
+}
+
+func (m *ReportRepository) CreateReport(ctx context.Context, report domain.Report) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.reports[report.Id()]; ok {
+		return ErrReportAlreadyExists
+	}
+	m.reports[report.Id()] = report
+	return nil
+}
+
+func (m *ReportRepository) ResolveReport(ctx context.Context, reportId string, updateFn func(report *domain.Report) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	report, ok := m.reports[reportId]
+	if !ok {
+		return domain.ErrReportNotFound
+	}
+	if err := updateFn(&report); err != nil {
+		return err
+	}
+	m.reports[reportId] = report
+	return nil
+}
+
+func (m *ReportRepository) ReassignUser(ctx context.Context, userId, newUserId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, report := range m.reports {
+		if err := report.ReassignUser(userId, newUserId); err != nil {
+			return err
+		}
+		m.reports[id] = report
+	}
+	return nil
+}
+
+func (m *ReportRepository) GetReportById(ctx context.Context, id string) (*domain.ReportReadModel, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	report, ok := m.reports[id]
+	if !ok {
+		return nil, domain.ErrReportNotFound
+	}
+	return domain.NewReportReadModel(report), nil
+}
+
+func (m *ReportRepository) GetReports(ctx context.Context, opts domain.GetReportsOptions) ([]*domain.ReportReadModel, bool, error) {
+	if opts.First <= 0 {
+		return nil, false, domain.ErrInvalidPageSize
+	}
+	asc := true
+	switch opts.SortDirection {
+	case "", "ASC":
+	case "DESC":
+		asc = false
+	default:
+		return nil, false, errors.New("invalid sort direction, must be 'ASC' or 'DESC'")
+	}
+	var after time.Time
+	if opts.After != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, opts.After)
+		if err != nil {
+			return nil, false, err
+		}
+		after = parsed
+	}
+
+	m.mu.RLock()
+	reports := []*domain.ReportReadModel{}
+	for _, report := range m.reports {
+		if opts.Status != "" && report.Status() != opts.Status {
+			continue
+		}
+		if opts.TargetType != "" && report.TargetType() != opts.TargetType {
+			continue
+		}
+		if opts.ReporterId != "" && report.ReporterId() != opts.ReporterId {
+			continue
+		}
+		if !after.IsZero() {
+			if asc && !report.CreatedAt().After(after) {
+				continue
+			}
+			if !asc && !report.CreatedAt().Before(after) {
+				continue
+			}
+		}
+		reports = append(reports, domain.NewReportReadModel(report))
+	}
+	m.mu.RUnlock()
+
+	slices.SortFunc(reports, func(a, b *domain.ReportReadModel) int {
+		if asc {
+			return a.CreatedAt.Compare(b.CreatedAt)
+		}
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	hasNext := len(reports) > int(opts.First)
+	if hasNext {
+		reports = reports[:opts.First]
+	}
+	return reports, hasNext, nil
+}
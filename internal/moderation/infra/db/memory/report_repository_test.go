@@ -0,0 +1,148 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+	"github.com/iammrsea/social-app/internal/moderation/infra/db/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func createReport(t *testing.T, repo *memory.ReportRepository, id string, targetType domain.ReportTargetType, createdAt time.Time) {
+	t.Helper()
+	report := domain.MustNewReport(id, "user-1", targetType, "target-"+id, "user-2", domain.Spam, "", createdAt, createdAt, nil)
+	assert.Nil(t, repo.CreateReport(context.Background(), report))
+}
+
+func TestGetReports(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should page through the queue oldest first", func(t *testing.T) {
+		t.Parallel()
+		repo := memory.NewReportRepository()
+		now := time.Now()
+		createReport(t, repo, "report-1", domain.PostTarget, now)
+		createReport(t, repo, "report-2", domain.PostTarget, now.Add(time.Minute))
+		createReport(t, repo, "report-3", domain.PostTarget, now.Add(2*time.Minute))
+
+		reports, hasNext, err := repo.GetReports(context.Background(), domain.GetReportsOptions{First: 2})
+		assert.Nil(t, err)
+		assert.True(t, hasNext)
+		assert.Equal(t, "report-1", reports[0].Id)
+		assert.Equal(t, "report-2", reports[1].Id)
+
+		after := reports[1].CreatedAt.Format(time.RFC3339Nano)
+		reports, hasNext, err = repo.GetReports(context.Background(), domain.GetReportsOptions{First: 2, After: after})
+		assert.Nil(t, err)
+		assert.False(t, hasNext)
+		assert.Len(t, reports, 1)
+		assert.Equal(t, "report-3", reports[0].Id)
+	})
+
+	t.Run("should filter by status and target type", func(t *testing.T) {
+		t.Parallel()
+		repo := memory.NewReportRepository()
+		now := time.Now()
+		createReport(t, repo, "report-1", domain.PostTarget, now)
+		createReport(t, repo, "report-2", domain.CommentTarget, now.Add(time.Minute))
+		createReport(t, repo, "report-3", domain.PostTarget, now.Add(2*time.Minute))
+		err := repo.ResolveReport(context.Background(), "report-1", func(report *domain.Report) error {
+			return report.Resolve(domain.Dismiss, "moderator-1", "not spam")
+		})
+		assert.Nil(t, err)
+
+		reports, _, err := repo.GetReports(context.Background(), domain.GetReportsOptions{First: 10, Status: domain.Pending})
+		assert.Nil(t, err)
+		assert.Len(t, reports, 2)
+
+		reports, _, err = repo.GetReports(context.Background(), domain.GetReportsOptions{First: 10, Status: domain.Pending, TargetType: domain.PostTarget})
+		assert.Nil(t, err)
+		assert.Len(t, reports, 1)
+		assert.Equal(t, "report-3", reports[0].Id)
+
+		reports, _, err = repo.GetReports(context.Background(), domain.GetReportsOptions{First: 10, Status: domain.Dismissed})
+		assert.Nil(t, err)
+		assert.Len(t, reports, 1)
+		assert.Equal(t, "moderator-1", reports[0].Resolution.ModeratorId)
+	})
+
+	t.Run("should filter by reporter", func(t *testing.T) {
+		t.Parallel()
+		repo := memory.NewReportRepository()
+		now := time.Now()
+		createReport(t, repo, "report-1", domain.PostTarget, now)
+		other := domain.MustNewReport("report-2", "user-3", domain.PostTarget, "target-2", "user-2", domain.Spam, "", now, now, nil)
+		assert.Nil(t, repo.CreateReport(context.Background(), other))
+
+		reports, _, err := repo.GetReports(context.Background(), domain.GetReportsOptions{First: 10, ReporterId: "user-3"})
+		assert.Nil(t, err)
+		assert.Len(t, reports, 1)
+		assert.Equal(t, "report-2", reports[0].Id)
+	})
+
+	t.Run("should reject fewer than one report", func(t *testing.T) {
+		t.Parallel()
+		repo := memory.NewReportRepository()
+		for _, first := range []int32{0, -1} {
+			_, _, err := repo.GetReports(context.Background(), domain.GetReportsOptions{First: first})
+			assert.ErrorIs(t, err, domain.ErrInvalidPageSize, "first: %d", first)
+		}
+	})
+}
+
+func TestResolveReport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should not save the report if updateFn fails", func(t *testing.T) {
+		t.Parallel()
+		repo := memory.NewReportRepository()
+		createReport(t, repo, "report-1", domain.PostTarget, time.Now())
+		err := repo.ResolveReport(context.Background(), "report-1", func(report *domain.Report) error {
+			if err := report.Resolve(domain.RemoveContent, "moderator-1", "spam"); err != nil {
+				return err
+			}
+			return assert.AnError
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+
+		report, err := repo.GetReportById(context.Background(), "report-1")
+		assert.Nil(t, err)
+		assert.Equal(t, domain.Pending, report.Status)
+		assert.Nil(t, report.Resolution)
+	})
+
+	t.Run("should return correct error if report does not exist", func(t *testing.T) {
+		t.Parallel()
+		repo := memory.NewReportRepository()
+		err := repo.ResolveReport(context.Background(), "report-1", func(report *domain.Report) error {
+			return nil
+		})
+		assert.Equal(t, domain.ErrReportNotFound, err)
+	})
+}
+
+func TestReassignUser(t *testing.T) {
+	t.Parallel()
+
+	repo := memory.NewReportRepository()
+	ctx := context.Background()
+	now := time.Now()
+	// user-1 reported user-2, and user-2 reported the profile of user-1
+	createReport(t, repo, "report-1", domain.PostTarget, now)
+	profile := domain.MustNewReport("report-2", "user-2", domain.UserTarget, "user-1", "user-1", domain.Spam, "", now, now, nil)
+	assert.Nil(t, repo.CreateReport(ctx, profile))
+
+	assert.Nil(t, repo.ReassignUser(ctx, "user-1", domain.DeletedUserId))
+
+	report, err := repo.GetReportById(ctx, "report-1")
+	assert.Nil(t, err)
+	assert.Equal(t, domain.DeletedUserId, report.ReporterId)
+	assert.Equal(t, "user-2", report.TargetAuthorId)
+	report, err = repo.GetReportById(ctx, "report-2")
+	assert.Nil(t, err)
+	assert.Equal(t, "user-2", report.ReporterId)
+	assert.Equal(t, domain.DeletedUserId, report.TargetId)
+	assert.Equal(t, domain.DeletedUserId, report.TargetAuthorId)
+}
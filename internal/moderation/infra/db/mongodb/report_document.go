@@ -0,0 +1,77 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+)
+
+// reportDocument represents how a report is stored in MongoDB
+type reportDocument struct {
+	ID             string                    `bson:"_id"`
+	ReporterID     string                    `bson:"reporterId"`
+	TargetType     string                    `bson:"targetType"`
+	TargetID       string                    `bson:"targetId"`
+	TargetAuthorID string                    `bson:"targetAuthorId"`
+	Reason         string                    `bson:"reason"`
+	Details        string                    `bson:"details"`
+	Status         string                    `bson:"status"`
+	Resolution     *reportResolutionDocument `bson:"resolution,omitempty"`
+	CreatedAt      time.Time                 `bson:"createdAt"`
+	UpdatedAt      time.Time                 `bson:"updatedAt"`
+}
+
+type reportResolutionDocument struct {
+	Action      string    `bson:"action"`
+	ModeratorID string    `bson:"moderatorId"`
+	Note        string    `bson:"note"`
+	ResolvedAt  time.Time `bson:"resolvedAt"`
+}
+
+// fromDomain converts a domain Report to reportDocument
+func fromDomain(report domain.Report) reportDocument {
+	doc := reportDocument{
+		ID:             report.Id(),
+		ReporterID:     report.ReporterId(),
+		TargetType:     report.TargetType().String(),
+		TargetID:       report.TargetId(),
+		TargetAuthorID: report.TargetAuthorId(),
+		Reason:         report.Reason().String(),
+		Details:        report.Details(),
+		Status:         report.Status().String(),
+		CreatedAt:      report.CreatedAt(),
+		UpdatedAt:      report.UpdatedAt(),
+	}
+	if !report.IsPending() {
+		doc.Resolution = &reportResolutionDocument{
+			Action:      report.Action().String(),
+			ModeratorID: report.ResolvedBy(),
+			Note:        report.ResolutionNote(),
+			ResolvedAt:  report.ResolvedAt(),
+		}
+	}
+	return doc
+}
+
+// toDomain converts a reportDocument to domain Report
+func (r reportDocument) toDomain() domain.Report {
+	return domain.MustNewReport(
+		r.ID,
+		r.ReporterID,
+		domain.ReportTargetType(r.TargetType),
+		r.TargetID,
+		r.TargetAuthorID,
+		domain.ReportReason(r.Reason),
+		r.Details,
+		r.CreatedAt,
+		r.UpdatedAt,
+		r.Resolution.toDomain(),
+	)
+}
+
+func (r *reportResolutionDocument) toDomain() *domain.Resolution {
+	if r == nil {
+		return nil
+	}
+	return domain.NewResolution(domain.ReportAction(r.Action), r.ModeratorID, r.Note, r.ResolvedAt)
+}
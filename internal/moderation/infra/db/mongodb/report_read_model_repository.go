@@ -0,0 +1,94 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReportReadModelRepository implements the domain.ReportReadModelRepository interface
+type ReportReadModelRepository struct {
+	collection *mongo.Collection
+}
+
+func NewReportReadModelRepository(db *mongo.Database) *ReportReadModelRepository {
+	return &ReportReadModelRepository{
+		collection: db.Collection("reports"),
+	}
+}
+
+// GetReports retrieves paginated reports sorted by createdAt
+func (r *ReportReadModelRepository) GetReports(ctx context.Context, opts domain.GetReportsOptions) ([]*domain.ReportReadModel, bool, error) {
+	if opts.First <= 0 {
+		return nil, false, domain.ErrInvalidPageSize
+	}
+	sortValue, comparison := 1, "$gt" // Oldest first by default
+	switch opts.SortDirection {
+	case "", "ASC":
+	case "DESC":
+		sortValue, comparison = -1, "$lt"
+	default:
+		return nil, false, errors.New("invalid sort direction, must be 'ASC' or 'DESC'")
+	}
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(opts.First + 1)) //Fetch one more to determine if there are more results
+	findOptions.SetSort(bson.D{{Key: "createdAt", Value: sortValue}})
+
+	filter := bson.M{}
+	if opts.After != "" {
+		createdAt, err := time.Parse(time.RFC3339Nano, opts.After)
+		if err != nil {
+			return nil, false, err
+		}
+		filter["createdAt"] = bson.M{comparison: createdAt}
+	}
+	if opts.Status != "" {
+		filter["status"] = opts.Status.String()
+	}
+	if opts.TargetType != "" {
+		filter["targetType"] = opts.TargetType.String()
+	}
+	if opts.ReporterId != "" {
+		filter["reporterId"] = opts.ReporterId
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, false, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []reportDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, false, err
+	}
+	hasNext := false
+	if len(docs) > int(opts.First) {
+		hasNext = true
+		docs = docs[:opts.First]
+	}
+	var reports []*domain.ReportReadModel
+	for _, doc := range docs {
+		reports = append(reports, domain.NewReportReadModel(doc.toDomain()))
+	}
+	return reports, hasNext, nil
+}
+
+// GetReportById finds a report by its id
+func (r *ReportReadModelRepository) GetReportById(ctx context.Context, id string) (*domain.ReportReadModel, error) {
+	var doc reportDocument
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrReportNotFound
+		}
+		return nil, err
+	}
+	return domain.NewReportReadModel(doc.toDomain()), nil
+}
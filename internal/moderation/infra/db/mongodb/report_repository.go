@@ -1 +1,72 @@
 package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ReportRepository implements the domain.ReportRepository interface
+type ReportRepository struct {
+	collection *mongo.Collection
+}
+
+func NewReportRepository(db *mongo.Database) *ReportRepository {
+	return &ReportRepository{
+		collection: db.Collection("reports"),
+	}
+}
+
+// CreateReport adds a new report to the database
+func (r *ReportRepository) CreateReport(ctx context.Context, report domain.Report) error {
+	_, err := r.collection.InsertOne(ctx, fromDomain(report))
+	return err
+}
+
+// ReassignUser replaces a user wherever they appear in reports
+func (r *ReportRepository) ReassignUser(ctx context.Context, userId, newUserId string) error {
+	updates := []struct {
+		filter bson.M
+		field  string
+	}{
+		{filter: bson.M{"reporterId": userId}, field: "reporterId"},
+		{filter: bson.M{"targetAuthorId": userId}, field: "targetAuthorId"},
+		{filter: bson.M{"targetType": domain.UserTarget.String(), "targetId": userId}, field: "targetId"},
+		{filter: bson.M{"resolution.moderatorId": userId}, field: "resolution.moderatorId"},
+	}
+	for _, update := range updates {
+		if _, err := r.collection.UpdateMany(ctx, update.filter, bson.M{"$set": bson.M{update.field: newUserId}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResolveReport applies updateFn to a report. The report is only replaced if it was still
+// pending, so two moderators can't resolve the same report.
+func (r *ReportRepository) ResolveReport(ctx context.Context, reportId string, updateFn func(report *domain.Report) error) error {
+	var doc reportDocument
+	err := r.collection.FindOne(ctx, bson.M{"_id": reportId}).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.ErrReportNotFound
+		}
+		return err
+	}
+	report := doc.toDomain()
+	if err := updateFn(&report); err != nil {
+		return err
+	}
+	filter := bson.M{"_id": reportId, "status": domain.Pending.String()}
+	result, err := r.collection.ReplaceOne(ctx, filter, fromDomain(report))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrReportAlreadyResolved
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// reportDocument represents how a report is stored in Postgres. The resolution
+// columns are NULL while the report is pending.
+type reportDocument struct {
+	ID             string     `db:"id"`
+	ReporterID     string     `db:"reporter_id"`
+	TargetType     string     `db:"target_type"`
+	TargetID       string     `db:"target_id"`
+	TargetAuthorID string     `db:"target_author_id"`
+	Reason         string     `db:"reason"`
+	Details        string     `db:"details"`
+	Status         string     `db:"status"`
+	Action         *string    `db:"action"`
+	ResolvedBy     *string    `db:"resolved_by"`
+	ResolutionNote *string    `db:"resolution_note"`
+	ResolvedAt     *time.Time `db:"resolved_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+}
+
+const reportColumns = `id, reporter_id, target_type, target_id, target_author_id, reason, details, status,
+        action, resolved_by, resolution_note, resolved_at, created_at, updated_at`
+
+// Helper function to determine the comparison operator based on sort direction
+func getComparisonOperator(sortDirection string) string {
+	if sortDirection == "ASC" {
+		return ">"
+	}
+	return "<"
+}
+
+// fromDomain converts a domain.Report to a reportDocument
+func fromDomain(report domain.Report) reportDocument {
+	doc := reportDocument{
+		ID:             report.Id(),
+		ReporterID:     report.ReporterId(),
+		TargetType:     report.TargetType().String(),
+		TargetID:       report.TargetId(),
+		TargetAuthorID: report.TargetAuthorId(),
+		Reason:         report.Reason().String(),
+		Details:        report.Details(),
+		Status:         report.Status().String(),
+		CreatedAt:      report.CreatedAt(),
+		UpdatedAt:      report.UpdatedAt(),
+	}
+	if !report.IsPending() {
+		action := report.Action().String()
+		resolvedBy := report.ResolvedBy()
+		note := report.ResolutionNote()
+		resolvedAt := report.ResolvedAt()
+		doc.Action = &action
+		doc.ResolvedBy = &resolvedBy
+		doc.ResolutionNote = &note
+		doc.ResolvedAt = &resolvedAt
+	}
+	return doc
+}
+
+// toDomain converts a reportDocument to a domain.Report
+func (r *reportDocument) toDomain() domain.Report {
+	var resolution *domain.Resolution
+	if r.Action != nil {
+		resolution = domain.NewResolution(domain.ReportAction(*r.Action), *r.ResolvedBy, *r.ResolutionNote, *r.ResolvedAt)
+	}
+	return domain.MustNewReport(
+		r.ID,
+		r.ReporterID,
+		domain.ReportTargetType(r.TargetType),
+		r.TargetID,
+		r.TargetAuthorID,
+		domain.ReportReason(r.Reason),
+		r.Details,
+		r.CreatedAt,
+		r.UpdatedAt,
+		resolution,
+	)
+}
+
+func scanReportRow(row pgx.Row, doc *reportDocument) error {
+	err := row.Scan(
+		&doc.ID,
+		&doc.ReporterID,
+		&doc.TargetType,
+		&doc.TargetID,
+		&doc.TargetAuthorID,
+		&doc.Reason,
+		&doc.Details,
+		&doc.Status,
+		&doc.Action,
+		&doc.ResolvedBy,
+		&doc.ResolutionNote,
+		&doc.ResolvedAt,
+		&doc.CreatedAt,
+		&doc.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrReportNotFound
+		}
+		return err
+	}
+	return nil
+}
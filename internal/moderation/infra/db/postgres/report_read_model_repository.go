@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReportReadModelRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReportReadModelRepository(db *pgxpool.Pool) *ReportReadModelRepository {
+	return &ReportReadModelRepository{db: db}
+}
+
+// GetReports retrieves paginated reports sorted by created_at
+func (r *ReportReadModelRepository) GetReports(ctx context.Context, opts domain.GetReportsOptions) (reports []*domain.ReportReadModel, hasNext bool, err error) {
+	if opts.First <= 0 {
+		return nil, false, domain.ErrInvalidPageSize
+	}
+	sortDirection := "ASC" // Oldest first by default, the queue is worked through in order
+	if opts.SortDirection != "" {
+		if opts.SortDirection != "ASC" && opts.SortDirection != "DESC" {
+			return nil, false, errors.New("invalid sort direction, must be 'ASC' or 'DESC'")
+		}
+		sortDirection = opts.SortDirection
+	}
+
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM reports
+        WHERE ($1::TIMESTAMP IS NULL OR created_at %s $1)
+            AND ($2 = '' OR status = $2)
+            AND ($3 = '' OR target_type = $3)
+            AND ($5 = '' OR reporter_id = $5)
+        ORDER BY created_at %s
+        LIMIT $4
+    `, reportColumns, getComparisonOperator(sortDirection), sortDirection)
+
+	var afterTimestamp *time.Time
+	if opts.After != "" {
+		parsedTime, err := time.Parse(time.RFC3339Nano, opts.After)
+		if err != nil {
+			return nil, false, errors.New("invalid after timestamp format")
+		}
+		afterTimestamp = &parsedTime
+	}
+
+	// Fetch one extra row to check for "hasNext"
+	rows, err := r.db.Query(ctx, query, afterTimestamp, opts.Status.String(), opts.TargetType.String(), opts.First+1, opts.ReporterId)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var doc reportDocument
+		if err := scanReportRow(rows, &doc); err != nil {
+			return nil, false, err
+		}
+		reports = append(reports, domain.NewReportReadModel(doc.toDomain()))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	hasNext = len(reports) > int(opts.First)
+	if hasNext {
+		reports = reports[:opts.First]
+	}
+	return reports, hasNext, nil
+}
+
+func (r *ReportReadModelRepository) GetReportById(ctx context.Context, id string) (*domain.ReportReadModel, error) {
+	query := `SELECT ` + reportColumns + ` FROM reports WHERE id = $1`
+	var doc reportDocument
+	if err := scanReportRow(r.db.QueryRow(ctx, query, id), &doc); err != nil {
+		return nil, err
+	}
+	return domain.NewReportReadModel(doc.toDomain()), nil
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/moderation/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReportRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReportRepository(db *pgxpool.Pool) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+func (r *ReportRepository) CreateReport(ctx context.Context, report domain.Report) error {
+	query := `
+        INSERT INTO reports (` + reportColumns + `)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+    `
+	doc := fromDomain(report)
+	_, err := r.db.Exec(ctx, query,
+		doc.ID,
+		doc.ReporterID,
+		doc.TargetType,
+		doc.TargetID,
+		doc.TargetAuthorID,
+		doc.Reason,
+		doc.Details,
+		doc.Status,
+		doc.Action,
+		doc.ResolvedBy,
+		doc.ResolutionNote,
+		doc.ResolvedAt,
+		doc.CreatedAt,
+		doc.UpdatedAt,
+	)
+	return err
+}
+
+func (r *ReportRepository) ReassignUser(ctx context.Context, userId, newUserId string) error {
+	query := `
+        UPDATE reports
+        SET reporter_id = CASE WHEN reporter_id = $1 THEN $2 ELSE reporter_id END,
+            target_author_id = CASE WHEN target_author_id = $1 THEN $2 ELSE target_author_id END,
+            target_id = CASE WHEN target_type = $3 AND target_id = $1 THEN $2 ELSE target_id END,
+            resolved_by = CASE WHEN resolved_by = $1 THEN $2 ELSE resolved_by END
+        WHERE reporter_id = $1 OR target_author_id = $1 OR resolved_by = $1 OR (target_type = $3 AND target_id = $1)
+    `
+	_, err := r.db.Exec(ctx, query, userId, newUserId, domain.UserTarget.String())
+	return err
+}
+
+// ResolveReport locks the report for the duration of updateFn so that concurrent
+// resolutions of the same report are serialized
+func (r *ReportRepository) ResolveReport(ctx context.Context, reportId string, updateFn func(report *domain.Report) error) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		query := `SELECT ` + reportColumns + ` FROM reports WHERE id = $1 FOR UPDATE`
+		var doc reportDocument
+		if err := scanReportRow(tx.QueryRow(ctx, query, reportId), &doc); err != nil {
+			return err
+		}
+		report := doc.toDomain()
+		if err := updateFn(&report); err != nil {
+			return err
+		}
+		updated := fromDomain(report)
+		update := `
+            UPDATE reports
+            SET status = $1, action = $2, resolved_by = $3, resolution_note = $4, resolved_at = $5, updated_at = $6
+            WHERE id = $7
+        `
+		_, err := tx.Exec(ctx, update,
+			updated.Status,
+			updated.Action,
+			updated.ResolvedBy,
+			updated.ResolutionNote,
+			updated.ResolvedAt,
+			updated.UpdatedAt,
+			updated.ID,
+		)
+		return err
+	})
+}
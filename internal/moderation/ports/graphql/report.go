@@ -0,0 +1,7 @@
+package graphql
+
+import "github.com/iammrsea/social-app/internal/moderation/domain"
+
+type Report = domain.ReportReadModel
+
+type ReportResolution = domain.ReportResolution
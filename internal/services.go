@@ -1,7 +1,15 @@
 package internal
 
-import userService "github.com/iammrsea/social-app/internal/user/app"
+import (
+	contentService "github.com/iammrsea/social-app/internal/content/app"
+	interactionService "github.com/iammrsea/social-app/internal/interaction/app"
+	moderationService "github.com/iammrsea/social-app/internal/moderation/app"
+	userService "github.com/iammrsea/social-app/internal/user/app"
+)
 
 type Services struct {
-	UserService *userService.Application
+	UserService        *userService.Application
+	ContentService     *contentService.Application
+	InteractionService *interactionService.Application
+	ModerationService  *moderationService.Application
 }
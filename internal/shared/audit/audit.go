@@ -0,0 +1,142 @@
+package audit
+
+// Records who did what, and who was refused what, so that requests can be traced after the fact.
+// The log is append-only: entries are never changed or deleted.
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/lucsky/cuid"
+)
+
+var ErrInvalidSearch = errors.New("invalid audit log search")
+
+// Kind tells what an entry records
+type Kind string
+
+const (
+	// Authorization entries record the decision of a guard
+	Authorization Kind = "AUTHORIZATION"
+	// Command entries record a state-changing command and whether it succeeded
+	Command Kind = "COMMAND"
+)
+
+type Outcome string
+
+const (
+	Allowed   Outcome = "ALLOWED"
+	Denied    Outcome = "DENIED"
+	Succeeded Outcome = "SUCCEEDED"
+	Failed    Outcome = "FAILED"
+)
+
+func (o Outcome) String() string {
+	return string(o)
+}
+
+func (k Kind) String() string {
+	return string(k)
+}
+
+type Entry struct {
+	Id   string
+	Kind Kind
+	// ActorId is the user who sent the request, empty for guests
+	ActorId string
+	// Target is what the request was about, such as the user being banned. It is empty when the
+	// permission isn't about one thing in particular.
+	Target     string
+	Permission string
+	// Details says more about what was done, such as the role assigned to the target
+	Details string
+	Outcome Outcome
+	// Reason is the error that denied or failed the request
+	Reason string
+	// RequestId is the id middleware.RequestID gave the HTTP request
+	RequestId string
+	At        time.Time
+}
+
+// SearchOptions narrows a search of the log. Entries are returned newest first; empty fields
+// match every entry.
+type SearchOptions struct {
+	First      int32
+	After      string // RFC3339Nano time of the last entry of the previous page
+	ActorId    string
+	Target     string
+	Permission string
+	Kind       Kind
+	Outcome    Outcome
+}
+
+type Store interface {
+	Append(ctx context.Context, entry Entry) error
+	Search(ctx context.Context, opts SearchOptions) (entries []Entry, hasNext bool, err error)
+}
+
+type Option func(*Log)
+
+// WithClock replaces time.Now as the source of the current time
+func WithClock(now func() time.Time) Option {
+	return func(l *Log) {
+		l.now = now
+	}
+}
+
+// Log records entries to a store, filling in the actor and request id from the context
+type Log struct {
+	store Store
+	now   func() time.Time
+}
+
+func NewLog(store Store, opts ...Option) *Log {
+	if store == nil {
+		panic("nil audit store")
+	}
+	l := &Log{store: store, now: time.Now}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Record appends entry to the log. The request isn't failed when the log can't be written to; the
+// error is logged instead.
+func (l *Log) Record(ctx context.Context, entry Entry) {
+	entry.Id = cuid.New()
+	entry.At = l.now()
+	entry.RequestId = middleware.GetReqID(ctx)
+	if authUser := auth.GetUserFromCtx(ctx); authUser != nil {
+		entry.ActorId = authUser.Id
+	}
+	// The entry is written even if the request was cancelled meanwhile
+	if err := l.store.Append(context.WithoutCancel(ctx), entry); err != nil {
+		log.Printf("audit log: %v", err)
+	}
+}
+
+func (l *Log) Search(ctx context.Context, opts SearchOptions) ([]Entry, bool, error) {
+	if opts.First <= 0 {
+		return nil, false, ErrInvalidSearch
+	}
+	if opts.After != "" {
+		if _, err := time.Parse(time.RFC3339Nano, opts.After); err != nil {
+			return nil, false, ErrInvalidSearch
+		}
+	}
+	return l.store.Search(ctx, opts)
+}
+
+// matches tells whether entry is one opts searches for, cursor aside
+func (opts SearchOptions) matches(entry Entry) bool {
+	return (opts.ActorId == "" || entry.ActorId == opts.ActorId) &&
+		(opts.Target == "" || entry.Target == opts.Target) &&
+		(opts.Permission == "" || entry.Permission == opts.Permission) &&
+		(opts.Kind == "" || entry.Kind == opts.Kind) &&
+		(opts.Outcome == "" || entry.Outcome == opts.Outcome)
+}
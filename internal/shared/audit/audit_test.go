@@ -0,0 +1,155 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/iammrsea/social-app/internal/shared/audit"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type banUser struct {
+	Id     string
+	Reason string
+}
+
+type handlerFunc func(ctx context.Context, cmd banUser) error
+
+func (f handlerFunc) Handle(ctx context.Context, cmd banUser) error {
+	return f(ctx, cmd)
+}
+
+func describeBan(cmd banUser) (string, string) {
+	return cmd.Id, cmd.Reason
+}
+
+// clock returns a time one second later on every call, so entries are ordered
+func clock() func() time.Time {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+}
+
+func requestCtx(userId, requestId string) context.Context {
+	ctx := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Id: userId, Roles: []rbac.UserRole{rbac.Admin}})
+	return context.WithValue(ctx, middleware.RequestIDKey, requestId)
+}
+
+func TestLog(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should record the actor, request id and time of entries", func(t *testing.T) {
+		t.Parallel()
+		store := audit.NewMemoryStore()
+		log := audit.NewLog(store, audit.WithClock(clock()))
+
+		log.Record(requestCtx("admin-1", "req-1"), audit.Entry{
+			Kind:       audit.Authorization,
+			Permission: string(rbac.BanUser),
+			Outcome:    audit.Allowed,
+		})
+
+		entries, hasNext, err := log.Search(context.Background(), audit.SearchOptions{First: 10})
+		require.NoError(t, err)
+		assert.False(t, hasNext)
+		require.Len(t, entries, 1)
+		assert.NotEmpty(t, entries[0].Id)
+		assert.Equal(t, "admin-1", entries[0].ActorId)
+		assert.Equal(t, "req-1", entries[0].RequestId)
+		assert.Equal(t, time.Date(2025, 6, 1, 12, 0, 1, 0, time.UTC), entries[0].At)
+	})
+
+	t.Run("should search newest entries first, a page at a time", func(t *testing.T) {
+		t.Parallel()
+		log := audit.NewLog(audit.NewMemoryStore(), audit.WithClock(clock()))
+		for _, target := range []string{"user-1", "user-2", "user-3"} {
+			log.Record(requestCtx("admin-1", "req-1"), audit.Entry{Kind: audit.Command, Target: target, Outcome: audit.Succeeded})
+		}
+
+		page, hasNext, err := log.Search(context.Background(), audit.SearchOptions{First: 2})
+		require.NoError(t, err)
+		assert.True(t, hasNext)
+		require.Len(t, page, 2)
+		assert.Equal(t, "user-3", page[0].Target)
+		assert.Equal(t, "user-2", page[1].Target)
+
+		after := page[1].At.Format(time.RFC3339Nano)
+		page, hasNext, err = log.Search(context.Background(), audit.SearchOptions{First: 2, After: after})
+		require.NoError(t, err)
+		assert.False(t, hasNext)
+		require.Len(t, page, 1)
+		assert.Equal(t, "user-1", page[0].Target)
+	})
+
+	t.Run("should filter entries", func(t *testing.T) {
+		t.Parallel()
+		log := audit.NewLog(audit.NewMemoryStore())
+		log.Record(requestCtx("admin-1", "req-1"), audit.Entry{Kind: audit.Authorization, Permission: "ban:user", Outcome: audit.Allowed})
+		log.Record(requestCtx("user-1", "req-2"), audit.Entry{Kind: audit.Authorization, Permission: "ban:user", Outcome: audit.Denied})
+		log.Record(requestCtx("admin-1", "req-3"), audit.Entry{Kind: audit.Command, Target: "user-2", Permission: "ban:user", Outcome: audit.Succeeded})
+
+		denied, _, err := log.Search(context.Background(), audit.SearchOptions{First: 10, Outcome: audit.Denied})
+		require.NoError(t, err)
+		require.Len(t, denied, 1)
+		assert.Equal(t, "user-1", denied[0].ActorId)
+
+		byAdmin, _, err := log.Search(context.Background(), audit.SearchOptions{First: 10, ActorId: "admin-1", Kind: audit.Command})
+		require.NoError(t, err)
+		require.Len(t, byAdmin, 1)
+		assert.Equal(t, "user-2", byAdmin[0].Target)
+	})
+
+	t.Run("should reject invalid searches", func(t *testing.T) {
+		t.Parallel()
+		log := audit.NewLog(audit.NewMemoryStore())
+		_, _, err := log.Search(context.Background(), audit.SearchOptions{})
+		assert.ErrorIs(t, err, audit.ErrInvalidSearch)
+		_, _, err = log.Search(context.Background(), audit.SearchOptions{First: 10, After: "not a time"})
+		assert.ErrorIs(t, err, audit.ErrInvalidSearch)
+	})
+}
+
+func TestAudit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should record commands that succeed", func(t *testing.T) {
+		t.Parallel()
+		log := audit.NewLog(audit.NewMemoryStore())
+		handler := audit.Audit[banUser](handlerFunc(func(ctx context.Context, cmd banUser) error { return nil }), log, rbac.BanUser, describeBan)
+
+		require.NoError(t, handler.Handle(requestCtx("admin-1", "req-1"), banUser{Id: "user-1", Reason: "spam"}))
+
+		entries, _, err := log.Search(context.Background(), audit.SearchOptions{First: 10})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.Command, entries[0].Kind)
+		assert.Equal(t, "admin-1", entries[0].ActorId)
+		assert.Equal(t, "user-1", entries[0].Target)
+		assert.Equal(t, "ban:user", entries[0].Permission)
+		assert.Equal(t, "spam", entries[0].Details)
+		assert.Equal(t, audit.Succeeded, entries[0].Outcome)
+	})
+
+	t.Run("should record commands that fail with the error", func(t *testing.T) {
+		t.Parallel()
+		log := audit.NewLog(audit.NewMemoryStore())
+		failure := errors.New("user not found")
+		handler := audit.Audit[banUser](handlerFunc(func(ctx context.Context, cmd banUser) error { return failure }), log, rbac.BanUser, describeBan)
+
+		assert.ErrorIs(t, handler.Handle(requestCtx("admin-1", "req-1"), banUser{Id: "user-1"}), failure)
+
+		entries, _, err := log.Search(context.Background(), audit.SearchOptions{First: 10})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.Failed, entries[0].Outcome)
+		assert.Equal(t, "user not found", entries[0].Reason)
+	})
+}
@@ -0,0 +1,35 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+type auditedHandler[T any] struct {
+	handler    shared.CommandHandler[T]
+	log        *Log
+	permission rbac.Permission
+	describe   func(cmd T) (target, details string)
+}
+
+// Audit records every command handler handles and whether it succeeded. describe tells what the
+// command is about and the details worth recording, such as the reason of a ban.
+func Audit[T any](handler shared.CommandHandler[T], log *Log, permission rbac.Permission, describe func(cmd T) (target, details string)) shared.CommandHandler[T] {
+	if handler == nil || log == nil || describe == nil {
+		panic("nil command handler, audit log or describe")
+	}
+	return &auditedHandler[T]{handler: handler, log: log, permission: permission, describe: describe}
+}
+
+func (a *auditedHandler[T]) Handle(ctx context.Context, cmd T) error {
+	err := a.handler.Handle(ctx, cmd)
+	target, details := a.describe(cmd)
+	entry := Entry{Kind: Command, Target: target, Permission: string(a.permission), Details: details, Outcome: Succeeded}
+	if err != nil {
+		entry.Outcome, entry.Reason = Failed, err.Error()
+	}
+	a.log.Record(ctx, entry)
+	return err
+}
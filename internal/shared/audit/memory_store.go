@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an audit log kept in memory, for the in-memory repositories and for tests
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Append(ctx context.Context, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *MemoryStore) Search(ctx context.Context, opts SearchOptions) ([]Entry, bool, error) {
+	var after time.Time
+	if opts.After != "" {
+		var err error
+		if after, err = time.Parse(time.RFC3339Nano, opts.After); err != nil {
+			return nil, false, err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := []Entry{}
+	// Entries are appended in time order, so the newest are at the end
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		entry := s.entries[i]
+		if !after.IsZero() && !entry.At.Before(after) {
+			continue
+		}
+		if !opts.matches(entry) {
+			continue
+		}
+		if len(entries) == int(opts.First) {
+			return entries, true, nil
+		}
+		entries = append(entries, entry)
+	}
+	return entries, false, nil
+}
@@ -0,0 +1,82 @@
+package apikeys
+
+// Personal API keys let bots and integrations act for a user without a login session. A key
+// carries a set of scopes that caps the permissions of the user's role, and is stored by the hash
+// of the key handed to the user, never the key itself.
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+// KeyPrefix starts every API key, so leaked keys are easy to spot
+const KeyPrefix = "sa_"
+
+// displayedKeyLength is how much of a key is kept in clear to tell keys apart in listings
+const displayedKeyLength = len(KeyPrefix) + 6
+
+const maxNameLength = 100
+
+var (
+	ErrAPIKeyNotFound  = errors.New("api key not found")
+	ErrInvalidAPIKey   = errors.New("invalid, expired or revoked api key")
+	ErrNameRequired    = errors.New("api key name is required")
+	ErrNameTooLong     = errors.New("api key name is too long")
+	ErrScopesRequired  = errors.New("api key needs at least one scope")
+	ErrInvalidScope    = errors.New("unknown api key scope")
+	ErrExpiryInThePast = errors.New("api key expiry must be in the future")
+)
+
+type APIKey struct {
+	Id     string
+	UserId string
+	Name   string
+	// Prefix is the start of the key, shown to tell keys apart
+	Prefix    string
+	KeyHash   string
+	Scopes    []rbac.Permission
+	CreatedAt time.Time
+	// ExpiresAt is nil for keys that don't expire
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+}
+
+// IsActive tells whether the key can still be used at now
+func (k APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || k.ExpiresAt.After(now)
+}
+
+type Store interface {
+	Save(ctx context.Context, key APIKey) error
+	// FindByHash returns ErrAPIKeyNotFound if no key has the hash
+	FindByHash(ctx context.Context, keyHash string) (APIKey, error)
+	// ListByUser returns the keys of a user, revoked ones included, newest first
+	ListByUser(ctx context.Context, userId string) ([]APIKey, error)
+	// Revoke revokes a key of a user. It returns ErrAPIKeyNotFound if the user has no such key.
+	Revoke(ctx context.Context, userId, keyId string, now time.Time) error
+}
+
+// NewKey generates a new API key to hand to the user
+func NewKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return KeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashKey is how API keys are looked up in the store
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
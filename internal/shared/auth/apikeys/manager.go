@@ -0,0 +1,127 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+type Option func(*Manager)
+
+// WithClock replaces time.Now as the source of the current time
+func WithClock(now func() time.Time) Option {
+	return func(m *Manager) {
+		m.now = now
+	}
+}
+
+// NewAPIKey is a key about to be created. Key is the generated key, see NewKey.
+type NewAPIKey struct {
+	Id        string
+	UserId    string
+	Key       string
+	Name      string
+	Scopes    []rbac.Permission
+	ExpiresAt *time.Time
+}
+
+// Manager creates, lists, revokes and verifies API keys
+type Manager struct {
+	store Store
+	now   func() time.Time
+}
+
+func NewManager(store Store, opts ...Option) *Manager {
+	if store == nil {
+		panic("nil api key store")
+	}
+	m := &Manager{store: store, now: time.Now}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (m *Manager) Create(ctx context.Context, newKey NewAPIKey) (APIKey, error) {
+	name := strings.TrimSpace(newKey.Name)
+	if name == "" {
+		return APIKey{}, ErrNameRequired
+	}
+	if len(name) > maxNameLength {
+		return APIKey{}, ErrNameTooLong
+	}
+	scopes, err := ValidateScopes(newKey.Scopes)
+	if err != nil {
+		return APIKey{}, err
+	}
+	now := m.now()
+	if newKey.ExpiresAt != nil && !newKey.ExpiresAt.After(now) {
+		return APIKey{}, ErrExpiryInThePast
+	}
+	if newKey.Id == "" || newKey.UserId == "" || !strings.HasPrefix(newKey.Key, KeyPrefix) || len(newKey.Key) <= displayedKeyLength {
+		return APIKey{}, errors.New("api key needs an id, a user and a key made by NewKey")
+	}
+	key := APIKey{
+		Id:        newKey.Id,
+		UserId:    newKey.UserId,
+		Name:      name,
+		Prefix:    newKey.Key[:displayedKeyLength],
+		KeyHash:   HashKey(newKey.Key),
+		Scopes:    scopes,
+		CreatedAt: now,
+		ExpiresAt: newKey.ExpiresAt,
+	}
+	if err := m.store.Save(ctx, key); err != nil {
+		return APIKey{}, err
+	}
+	return key, nil
+}
+
+func (m *Manager) List(ctx context.Context, userId string) ([]APIKey, error) {
+	return m.store.ListByUser(ctx, userId)
+}
+
+func (m *Manager) Revoke(ctx context.Context, userId, keyId string) error {
+	return m.store.Revoke(ctx, userId, keyId, m.now())
+}
+
+// Verify returns the API key matching key. It returns ErrInvalidAPIKey for unknown, expired and
+// revoked keys.
+func (m *Manager) Verify(ctx context.Context, key string) (APIKey, error) {
+	if !strings.HasPrefix(key, KeyPrefix) {
+		return APIKey{}, ErrInvalidAPIKey
+	}
+	found, err := m.store.FindByHash(ctx, HashKey(key))
+	if err != nil {
+		if errors.Is(err, ErrAPIKeyNotFound) {
+			return APIKey{}, ErrInvalidAPIKey
+		}
+		return APIKey{}, err
+	}
+	if !found.IsActive(m.now()) {
+		return APIKey{}, ErrInvalidAPIKey
+	}
+	return found, nil
+}
+
+// ValidateScopes rejects empty and unknown scopes, and drops duplicates
+func ValidateScopes(scopes []rbac.Permission) ([]rbac.Permission, error) {
+	if len(scopes) == 0 {
+		return nil, ErrScopesRequired
+	}
+	valid := make([]rbac.Permission, 0, len(scopes))
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+		if !slices.Contains(valid, scope) {
+			valid = append(valid, scope)
+		}
+	}
+	return valid, nil
+}
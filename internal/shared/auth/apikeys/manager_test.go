@@ -0,0 +1,114 @@
+package apikeys_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth/apikeys"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newKey(t *testing.T, id string, scopes ...rbac.Permission) apikeys.NewAPIKey {
+	t.Helper()
+	key, err := apikeys.NewKey()
+	require.NoError(t, err)
+	return apikeys.NewAPIKey{Id: id, UserId: "user-1", Key: key, Name: "deploy bot", Scopes: scopes}
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should store only the hash of a key and verify the key", func(t *testing.T) {
+		t.Parallel()
+		store := apikeys.NewMemoryStore()
+		manager := apikeys.NewManager(store)
+		newAPIKey := newKey(t, "key-1", rbac.CreatePost, rbac.ViewPost, rbac.CreatePost)
+
+		created, err := manager.Create(ctx, newAPIKey)
+		require.NoError(t, err)
+		assert.NotContains(t, created.KeyHash, newAPIKey.Key)
+		assert.Equal(t, newAPIKey.Key[:len(created.Prefix)], created.Prefix)
+		assert.Less(t, len(created.Prefix), len(newAPIKey.Key))
+		assert.Equal(t, []rbac.Permission{rbac.CreatePost, rbac.ViewPost}, created.Scopes)
+
+		verified, err := manager.Verify(ctx, newAPIKey.Key)
+		require.NoError(t, err)
+		assert.Equal(t, "key-1", verified.Id)
+		assert.Equal(t, "user-1", verified.UserId)
+
+		_, err = manager.Verify(ctx, newAPIKey.Key+"x")
+		assert.ErrorIs(t, err, apikeys.ErrInvalidAPIKey)
+		_, err = manager.Verify(ctx, "not-a-key")
+		assert.ErrorIs(t, err, apikeys.ErrInvalidAPIKey)
+	})
+
+	t.Run("should reject invalid keys", func(t *testing.T) {
+		t.Parallel()
+		now := time.Now()
+		past := now.Add(-time.Minute)
+		manager := apikeys.NewManager(apikeys.NewMemoryStore(), apikeys.WithClock((&clock{now: now}).Now))
+
+		noName := newKey(t, "key-1", rbac.CreatePost)
+		noName.Name = "  "
+		expired := newKey(t, "key-2", rbac.CreatePost)
+		expired.ExpiresAt = &past
+
+		cases := map[error]apikeys.NewAPIKey{
+			apikeys.ErrNameRequired:    noName,
+			apikeys.ErrScopesRequired:  newKey(t, "key-3"),
+			apikeys.ErrInvalidScope:    newKey(t, "key-4", rbac.Permission("fly:plane")),
+			apikeys.ErrExpiryInThePast: expired,
+		}
+		for expected, newAPIKey := range cases {
+			_, err := manager.Create(ctx, newAPIKey)
+			assert.ErrorIs(t, err, expected)
+		}
+	})
+
+	t.Run("should refuse expired and revoked keys", func(t *testing.T) {
+		t.Parallel()
+		c := &clock{now: time.Now()}
+		manager := apikeys.NewManager(apikeys.NewMemoryStore(), apikeys.WithClock(c.Now))
+
+		expiring := newKey(t, "key-1", rbac.CreatePost)
+		expiresAt := c.now.Add(time.Hour)
+		expiring.ExpiresAt = &expiresAt
+		revoked := newKey(t, "key-2", rbac.CreatePost)
+		for _, newAPIKey := range []apikeys.NewAPIKey{expiring, revoked} {
+			_, err := manager.Create(ctx, newAPIKey)
+			require.NoError(t, err)
+		}
+
+		_, err := manager.Verify(ctx, expiring.Key)
+		require.NoError(t, err)
+		c.now = c.now.Add(2 * time.Hour)
+		_, err = manager.Verify(ctx, expiring.Key)
+		assert.ErrorIs(t, err, apikeys.ErrInvalidAPIKey)
+
+		assert.ErrorIs(t, manager.Revoke(ctx, "someone-else", "key-2"), apikeys.ErrAPIKeyNotFound)
+		_, err = manager.Verify(ctx, revoked.Key)
+		require.NoError(t, err)
+
+		require.NoError(t, manager.Revoke(ctx, "user-1", "key-2"))
+		_, err = manager.Verify(ctx, revoked.Key)
+		assert.ErrorIs(t, err, apikeys.ErrInvalidAPIKey)
+
+		keys, err := manager.List(ctx, "user-1")
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		assert.Equal(t, "key-2", keys[0].Id)
+		assert.NotNil(t, keys[0].RevokedAt)
+	})
+}
@@ -0,0 +1,69 @@
+package apikeys
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps API keys in memory, for the in-memory repositories and for tests
+type MemoryStore struct {
+	mu   sync.Mutex
+	keys []*APIKey
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Save(ctx context.Context, key APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key.Scopes = slices.Clone(key.Scopes)
+	s.keys = append(s.keys, &key)
+	return nil
+}
+
+func (s *MemoryStore) FindByHash(ctx context.Context, keyHash string) (APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range s.keys {
+		if key.KeyHash == keyHash {
+			return copyKey(key), nil
+		}
+	}
+	return APIKey{}, ErrAPIKeyNotFound
+}
+
+func (s *MemoryStore) ListByUser(ctx context.Context, userId string) ([]APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := []APIKey{}
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		if s.keys[i].UserId == userId {
+			keys = append(keys, copyKey(s.keys[i]))
+		}
+	}
+	return keys, nil
+}
+
+func (s *MemoryStore) Revoke(ctx context.Context, userId, keyId string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range s.keys {
+		if key.Id == keyId && key.UserId == userId {
+			if key.RevokedAt == nil {
+				key.RevokedAt = &now
+			}
+			return nil
+		}
+	}
+	return ErrAPIKeyNotFound
+}
+
+func copyKey(key *APIKey) APIKey {
+	copied := *key
+	copied.Scopes = slices.Clone(key.Scopes)
+	return copied
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -14,58 +15,137 @@ type contextKey int
 
 const userCtxKey contextKey = iota
 
+// APIKeyScheme is the Authorization scheme of API keys
+const APIKeyScheme = "ApiKey"
+
 type AuthenticatedUser struct {
 	Email string
 	Id    string
-	Role  rbac.UserRole
+	Roles []rbac.UserRole
+	// SessionId is the login session the access token belongs to
+	SessionId string
+	// TokenId is the jti of the access token the user presented
+	TokenId string
+	// APIKeyId is the API key the user authenticated with instead of an access token
+	APIKeyId string
+	// Scopes caps the permissions of the roles when the user authenticated with an API key
+	Scopes []rbac.Permission
+	// EmailUnverified is set for users who haven't verified their email yet
+	EmailUnverified bool
+	// MFA is set when the user logged in with two-factor authentication
+	MFA bool
 }
 
 func (a *AuthenticatedUser) IsZero() bool {
-	return *a == AuthenticatedUser{}
+	return a.Email == "" && a.Id == "" && a.Roles == nil && a.SessionId == "" && a.TokenId == "" &&
+		a.APIKeyId == "" && a.Scopes == nil && !a.EmailUnverified && !a.MFA
 }
 
 func (a *AuthenticatedUser) IsAuthenticated() bool {
 	return !a.IsZero()
 }
 
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if config.NewEnv().GoEnv() == config.Test {
-			user := GetFakeUser(rbac.Admin)
+// HasRole tells whether role is one of the user's roles
+func (a *AuthenticatedUser) HasRole(role rbac.UserRole) bool {
+	return slices.Contains(a.Roles, role)
+}
+
+// Subject is what the guard checks permissions for
+func (a *AuthenticatedUser) Subject() rbac.Subject {
+	return rbac.Subject{Roles: a.Roles, Scopes: a.Scopes, Unverified: a.EmailUnverified, MFA: a.MFA}
+}
+
+// RevocationChecker tells whether an access token was revoked before it expired
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, tokenId string) (bool, error)
+}
+
+// APIKeyAuthenticator finds the user an API key belongs to
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*AuthenticatedUser, error)
+}
+
+// Middleware authenticates requests with the bearer token or API key they carry. Tokens that
+// revoked reports as revoked, or that can't be checked, leave the request unauthenticated, and so
+// do API keys that apiKeys rejects.
+func Middleware(revoked RevocationChecker, apiKeys APIKeyAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if config.NewEnv().GoEnv() == config.Test {
+				user := GetFakeUser(rbac.Admin)
+				ctx := context.WithValue(r.Context(), userCtxKey, user)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+			if key, ok := apiKeyFromRequest(r); ok && apiKeys != nil {
+				user, err := apiKeys.AuthenticateAPIKey(r.Context(), key)
+				if err != nil || user == nil {
+					user = &AuthenticatedUser{}
+				}
+				ctx := context.WithValue(r.Context(), userCtxKey, user)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			claims := ParseTokenFromRequest(r)
+
+			user := &AuthenticatedUser{}
+
+			if !claims.IsZero() && !isRevoked(r.Context(), revoked, claims.ID) {
+				user.Email = claims.Email
+				user.Id = claims.UserId
+				user.Roles = claims.Roles
+				user.SessionId = claims.SessionId
+				user.TokenId = claims.ID
+				user.EmailUnverified = claims.EmailUnverified
+				user.MFA = claims.MFA
+			}
+
 			ctx := context.WithValue(r.Context(), userCtxKey, user)
 			next.ServeHTTP(w, r.WithContext(ctx))
-			return
-		}
-		claims := ParseTokenFromRequest(r)
-
-		user := &AuthenticatedUser{}
+		})
+	}
+}
 
-		if !claims.IsZero() {
-			user.Email = claims.Email
-			user.Id = claims.UserId
-			user.Role = claims.Role
-		}
+// apiKeyFromRequest reads an "Authorization: ApiKey <key>" header
+func apiKeyFromRequest(r *http.Request) (string, bool) {
+	key, ok := strings.CutPrefix(r.Header.Get("Authorization"), APIKeyScheme+" ")
+	key = strings.TrimSpace(key)
+	return key, ok && key != ""
+}
 
-		ctx := context.WithValue(r.Context(), userCtxKey, user)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+func isRevoked(ctx context.Context, revoked RevocationChecker, tokenId string) bool {
+	if revoked == nil {
+		return false
+	}
+	// Tokens that can't be revoked are refused along with those that are
+	if tokenId == "" {
+		return true
+	}
+	isRevoked, err := revoked.IsRevoked(ctx, tokenId)
+	return err != nil || isRevoked
 }
 
 type AuthClaims struct {
-	UserId string        `json:"sub"`
-	Email  string        `json:"email"`
-	Role   rbac.UserRole `json:"role"`
+	UserId    string          `json:"sub"`
+	Email     string          `json:"email"`
+	Roles     []rbac.UserRole `json:"roles"`
+	SessionId string          `json:"sid,omitempty"`
+	// EmailUnverified is left out once the user has verified their email
+	EmailUnverified bool `json:"email_unverified,omitempty"`
+	// MFA is set when the user logged in with two-factor authentication
+	MFA bool `json:"mfa,omitempty"`
 	jwt.RegisteredClaims
 }
 
 func (c *AuthClaims) IsZero() bool {
-	return c.Email == "" || c.UserId == "" || c.Role == rbac.Guest
+	return c.Email == "" || c.UserId == "" || slices.Contains(c.Roles, rbac.Guest)
 }
 
 func ParseTokenFromRequest(r *http.Request) *AuthClaims {
 	authHeader := r.Header.Get("Authorization")
 	zeroClaims := &AuthClaims{
-		Role: rbac.Guest,
+		Roles: []rbac.UserRole{rbac.Guest},
 	}
 
 	if strings.TrimSpace(authHeader) == "" {
@@ -79,25 +159,35 @@ func ParseTokenFromRequest(r *http.Request) *AuthClaims {
 
 	bearerToken := parts[1]
 
-	secret := []byte(config.NewEnv().AuthSecret())
-
-	token, err := jwt.ParseWithClaims(bearerToken, &AuthClaims{}, func(t *jwt.Token) (any, error) {
-		return secret, nil
-	})
-
-	if err != nil || !token.Valid {
+	keys, err := DefaultKeySet()
+	if err != nil {
 		return zeroClaims
 	}
 
-	claims, ok := token.Claims.(*AuthClaims)
-
-	if !ok {
+	claims, err := ParseToken(keys, bearerToken)
+	if err != nil {
 		return zeroClaims
 	}
 
 	return claims
 }
 
+// ParseToken verifies a signed access token against keys and the configured
+// issuer and audience, and returns its claims.
+func ParseToken(keys *KeySet, tokenString string) (*AuthClaims, error) {
+	env := config.NewEnv()
+	claims := &AuthClaims{}
+	err := keys.Parse(tokenString, claims,
+		jwt.WithIssuer(env.AuthIssuer()),
+		jwt.WithAudience(env.AuthAudience()),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
 func GetUserFromCtx(ctx context.Context) *AuthenticatedUser {
 	user, _ := ctx.Value(userCtxKey).(*AuthenticatedUser)
 	return user
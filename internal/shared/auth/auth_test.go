@@ -1,14 +1,19 @@
 package auth_test
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/config"
 	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseTokenFromRequest(t *testing.T) {
@@ -39,8 +44,122 @@ func TestParseTokenFromRequest(t *testing.T) {
 		assert.NotNil(t, claims)
 		assert.False(t, claims.IsZero())
 		assert.Equal(t, fakeUser.Email, claims.Email)
-		assert.Equal(t, fakeUser.Role, claims.Role)
+		assert.Equal(t, fakeUser.Roles, claims.Roles)
 		assert.Equal(t, fakeUser.Id, claims.UserId)
 		assert.False(t, claims.ExpiresAt.IsZero())
 	})
+
+	t.Run("should reject tokens from another issuer, for another audience or without expiry", func(t *testing.T) {
+		t.Parallel()
+		env := config.NewEnv()
+		fakeUser := auth.GetFakeUser(rbac.Moderator)
+		sign := func(claims auth.AuthClaims) string {
+			token, err := defaultKeys(t).Sign(claims)
+			require.NoError(t, err)
+			return token
+		}
+		claimsWith := func(registered jwt.RegisteredClaims) auth.AuthClaims {
+			return auth.AuthClaims{UserId: fakeUser.Id, Email: fakeUser.Email, Roles: fakeUser.Roles, RegisteredClaims: registered}
+		}
+		expiresAt := jwt.NewNumericDate(time.Now().Add(time.Hour))
+		otherIssuer := auth.NewTokenIssuer(defaultKeys(t), "someone-else", env.AuthAudience(), time.Hour)
+		otherAudience := auth.NewTokenIssuer(defaultKeys(t), env.AuthIssuer(), "another-app", time.Hour)
+		otherSecret := auth.NewTokenIssuer(auth.NewHMACKeySet([]byte("another-secret")), env.AuthIssuer(), env.AuthAudience(), time.Hour)
+
+		tokens := map[string]string{
+			"issuer":      mustIssue(t, otherIssuer, fakeUser),
+			"audience":    mustIssue(t, otherAudience, fakeUser),
+			"secret":      mustIssue(t, otherSecret, fakeUser),
+			"no expiry":   sign(claimsWith(jwt.RegisteredClaims{Issuer: env.AuthIssuer(), Audience: jwt.ClaimStrings{env.AuthAudience()}})),
+			"no issuer":   sign(claimsWith(jwt.RegisteredClaims{Audience: jwt.ClaimStrings{env.AuthAudience()}, ExpiresAt: expiresAt})),
+			"no audience": sign(claimsWith(jwt.RegisteredClaims{Issuer: env.AuthIssuer(), ExpiresAt: expiresAt})),
+		}
+		for name, token := range tokens {
+			req := httptest.NewRequest(http.MethodGet, "/some-url", nil)
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+			assert.True(t, auth.ParseTokenFromRequest(req).IsZero(), name)
+		}
+	})
+}
+
+func defaultKeys(t *testing.T) *auth.KeySet {
+	t.Helper()
+	keys, err := auth.DefaultKeySet()
+	require.NoError(t, err)
+	return keys
+}
+
+func mustIssue(t *testing.T, issuer auth.TokenIssuer, user *auth.AuthenticatedUser) string {
+	t.Helper()
+	token, err := issuer.Issue(user)
+	require.NoError(t, err)
+	return token.AccessToken
+}
+
+func TestTokenIssuer(t *testing.T) {
+	t.Parallel()
+	env := config.NewEnv()
+	issuer := auth.NewTokenIssuer(defaultKeys(t), env.AuthIssuer(), env.AuthAudience(), 15*time.Minute)
+	fakeUser := auth.GetFakeUser(rbac.Regular)
+
+	before := time.Now()
+	token, err := issuer.Issue(fakeUser)
+	require.NoError(t, err)
+	assert.Equal(t, auth.BearerTokenType, token.TokenType)
+	assert.WithinDuration(t, before.Add(15*time.Minute), token.ExpiresAt, 2*time.Second)
+
+	req := httptest.NewRequest(http.MethodGet, "/some-url", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	claims := auth.ParseTokenFromRequest(req)
+	assert.Equal(t, fakeUser.Id, claims.UserId)
+	assert.Equal(t, env.AuthIssuer(), claims.Issuer)
+	assert.Equal(t, jwt.ClaimStrings{env.AuthAudience()}, claims.Audience)
+	assert.False(t, claims.IssuedAt.IsZero())
+}
+
+func TestPassword(t *testing.T) {
+	t.Parallel()
+	hash, err := auth.HashPassword("correct horse battery staple")
+	require.NoError(t, err)
+	assert.NotEqual(t, "correct horse battery staple", hash)
+
+	assert.NoError(t, auth.CheckPassword(hash, "correct horse battery staple"))
+	assert.ErrorIs(t, auth.CheckPassword(hash, "wrong password"), auth.ErrInvalidCredentials)
+	assert.ErrorIs(t, auth.CheckPassword("", "correct horse battery staple"), auth.ErrInvalidCredentials)
+}
+
+type revokedTokens map[string]bool
+
+func (r revokedTokens) IsRevoked(ctx context.Context, tokenId string) (bool, error) {
+	return r[tokenId], nil
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+	env := config.NewEnv()
+	issuer := auth.NewTokenIssuer(defaultKeys(t), env.AuthIssuer(), env.AuthAudience(), time.Hour)
+	fakeUser := auth.GetFakeUser(rbac.Moderator)
+	fakeUser.SessionId = "session-1"
+	revoked, err := issuer.Issue(fakeUser)
+	require.NoError(t, err)
+	valid, err := issuer.Issue(fakeUser)
+	require.NoError(t, err)
+
+	authenticate := func(token string) *auth.AuthenticatedUser {
+		var user *auth.AuthenticatedUser
+		handler := auth.Middleware(revokedTokens{revoked.Id: true}, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user = auth.GetUserFromCtx(r.Context())
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/some-url", nil)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return user
+	}
+
+	user := authenticate(valid.AccessToken)
+	assert.True(t, user.IsAuthenticated())
+	assert.Equal(t, "session-1", user.SessionId)
+	assert.Equal(t, valid.Id, user.TokenId)
+
+	assert.False(t, authenticate(revoked.AccessToken).IsAuthenticated())
 }
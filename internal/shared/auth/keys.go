@@ -0,0 +1,292 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/iammrsea/social-app/internal/shared/config"
+)
+
+var (
+	ErrNoSigningKey   = errors.New("no signing key configured, set AUTH_SIGNING_KEY_FILE or AUTH_SECRET")
+	ErrUnknownKey     = errors.New("token was signed with an unknown key")
+	ErrUnsupportedKey = errors.New("unsupported key type, use RSA or Ed25519")
+)
+
+type verificationKey struct {
+	method jwt.SigningMethod
+	key    any
+}
+
+// KeySet signs access tokens with one key and verifies them with any of several keys, so that a
+// new signing key can be rolled out while tokens signed with the previous one are still valid.
+// Every key is identified by a kid: the RFC 7638 thumbprint of public keys.
+type KeySet struct {
+	signingKid    string
+	signingMethod jwt.SigningMethod
+	signingKey    any
+	verification  map[string]verificationKey
+}
+
+// NewHMACKeySet signs and verifies HS256 tokens with a shared secret. Services that only verify
+// tokens need the secret too, so it is meant for development and tests.
+func NewHMACKeySet(secret []byte) *KeySet {
+	sum := sha256.Sum256(secret)
+	kid := base64.RawURLEncoding.EncodeToString(sum[:8])
+	return &KeySet{
+		signingKid:    kid,
+		signingMethod: jwt.SigningMethodHS256,
+		signingKey:    secret,
+		verification:  map[string]verificationKey{kid: {method: jwt.SigningMethodHS256, key: secret}},
+	}
+}
+
+// NewKeySet signs with signingKey, a *rsa.PrivateKey (RS256) or an ed25519.PrivateKey (EdDSA),
+// and verifies with its public key and verificationKeys, the public keys of earlier signing keys
+func NewKeySet(signingKey crypto.Signer, verificationKeys ...crypto.PublicKey) (*KeySet, error) {
+	method, err := signingMethodFor(signingKey.Public())
+	if err != nil {
+		return nil, err
+	}
+	keys := &KeySet{signingMethod: method, signingKey: signingKey, verification: map[string]verificationKey{}}
+	for _, key := range append([]crypto.PublicKey{signingKey.Public()}, verificationKeys...) {
+		kid, err := keys.addVerificationKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if keys.signingKid == "" {
+			keys.signingKid = kid
+		}
+	}
+	return keys, nil
+}
+
+// LoadKeySet reads a PEM encoded private signing key and PEM encoded public verification keys
+func LoadKeySet(signingKeyFile string, verificationKeyFiles ...string) (*KeySet, error) {
+	signingKey, err := readPrivateKey(signingKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	verificationKeys := []crypto.PublicKey{}
+	for _, file := range verificationKeyFiles {
+		key, err := readPublicKey(file)
+		if err != nil {
+			return nil, err
+		}
+		verificationKeys = append(verificationKeys, key)
+	}
+	return NewKeySet(signingKey, verificationKeys...)
+}
+
+var (
+	defaultKeys    *KeySet
+	defaultKeysErr error
+	loadKeysOnce   sync.Once
+)
+
+// DefaultKeySet is the key set configured in the environment. Asymmetric keys from
+// AUTH_SIGNING_KEY_FILE and AUTH_VERIFICATION_KEY_FILES are used when set, AUTH_SECRET otherwise.
+// Keys are read once.
+func DefaultKeySet() (*KeySet, error) {
+	loadKeysOnce.Do(func() {
+		env := config.NewEnv()
+		switch {
+		case env.AuthSigningKeyFile() != "":
+			defaultKeys, defaultKeysErr = LoadKeySet(env.AuthSigningKeyFile(), env.AuthVerificationKeyFiles()...)
+		case env.AuthSecret() != "":
+			defaultKeys = NewHMACKeySet([]byte(env.AuthSecret()))
+		default:
+			defaultKeysErr = ErrNoSigningKey
+		}
+	})
+	return defaultKeys, defaultKeysErr
+}
+
+// Sign signs claims with the signing key and names the key in the kid header
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.signingMethod, claims)
+	token.Header["kid"] = k.signingKid
+	return token.SignedString(k.signingKey)
+}
+
+// Parse verifies a token signed with one of the keys and decodes it into claims
+func (k *KeySet) Parse(tokenString string, claims jwt.Claims, opts ...jwt.ParserOption) error {
+	opts = append([]jwt.ParserOption{jwt.WithValidMethods(k.validMethods())}, opts...)
+	token, err := jwt.ParseWithClaims(tokenString, claims, k.keyfunc, opts...)
+	if err != nil {
+		return err
+	}
+	if !token.Valid {
+		return jwt.ErrTokenInvalidClaims
+	}
+	return nil
+}
+
+// keyfunc finds the key a token was signed with by its kid, and refuses tokens whose alg header
+// doesn't match the key
+func (k *KeySet) keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.verification[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("%w: unexpected signing method %s", ErrUnknownKey, token.Method.Alg())
+	}
+	return key.key, nil
+}
+
+func (k *KeySet) validMethods() []string {
+	methods := []string{}
+	for _, key := range k.verification {
+		if !slices.Contains(methods, key.method.Alg()) {
+			methods = append(methods, key.method.Alg())
+		}
+	}
+	return methods
+}
+
+func (k *KeySet) addVerificationKey(key crypto.PublicKey) (string, error) {
+	method, err := signingMethodFor(key)
+	if err != nil {
+		return "", err
+	}
+	jwk, err := newJWK(key)
+	if err != nil {
+		return "", err
+	}
+	k.verification[jwk.Kid] = verificationKey{method: method, key: key}
+	return jwk.Kid, nil
+}
+
+// JWK is a public key in the JSON Web Key format
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS lists the public verification keys. HMAC secrets are never published.
+func (k *KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for _, key := range k.verification {
+		if jwk, err := newJWK(key.key); err == nil {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+	slices.SortFunc(jwks.Keys, func(a, b JWK) int { return strings.Compare(a.Kid, b.Kid) })
+	return jwks
+}
+
+// JWKSHandler serves the public verification keys, meant for /.well-known/jwks.json
+func JWKSHandler(keys *KeySet) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		_ = json.NewEncoder(w).Encode(keys.JWKS())
+	})
+}
+
+func newJWK(key any) (JWK, error) {
+	b64 := base64.RawURLEncoding.EncodeToString
+	var jwk JWK
+	var thumbprintInput string
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		jwk = JWK{Kty: "RSA", Alg: jwt.SigningMethodRS256.Alg(), N: b64(key.N.Bytes()), E: b64(big.NewInt(int64(key.E)).Bytes())}
+		thumbprintInput = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, jwk.E, jwk.N)
+	case ed25519.PublicKey:
+		jwk = JWK{Kty: "OKP", Alg: jwt.SigningMethodEdDSA.Alg(), Crv: "Ed25519", X: b64(key)}
+		thumbprintInput = fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`, jwk.X)
+	default:
+		return JWK{}, ErrUnsupportedKey
+	}
+	thumbprint := sha256.Sum256([]byte(thumbprintInput))
+	jwk.Kid = b64(thumbprint[:])
+	jwk.Use = "sig"
+	return jwk, nil
+}
+
+func signingMethodFor(key crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
+
+func readPEM(file string) (*pem.Block, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not PEM encoded", file)
+	}
+	return block, nil
+}
+
+func readPrivateKey(file string) (crypto.Signer, error) {
+	block, err := readPEM(file)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("reading signing key %s: %w", file, err)
+	}
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
+
+func readPublicKey(file string) (crypto.PublicKey, error) {
+	block, err := readPEM(file)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("reading verification key %s: %w", file, err)
+	}
+	return key, nil
+}
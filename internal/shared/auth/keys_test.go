@@ -0,0 +1,171 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/config"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeySet(t *testing.T) {
+	t.Parallel()
+	env := config.NewEnv()
+	fakeUser := auth.GetFakeUser(rbac.Regular)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	issue := func(keys *auth.KeySet) string {
+		return mustIssue(t, auth.NewTokenIssuer(keys, env.AuthIssuer(), env.AuthAudience(), time.Hour), fakeUser)
+	}
+	headerOf := func(token string) map[string]any {
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, &auth.AuthClaims{})
+		require.NoError(t, err)
+		return parsed.Header
+	}
+
+	t.Run("should sign with RS256 and EdDSA and set a kid", func(t *testing.T) {
+		t.Parallel()
+		for alg, signer := range map[string]crypto.Signer{"RS256": rsaKey, "EdDSA": edKey} {
+			keys, err := auth.NewKeySet(signer)
+			require.NoError(t, err)
+
+			token := issue(keys)
+			header := headerOf(token)
+			assert.Equal(t, alg, header["alg"])
+			assert.Equal(t, keys.JWKS().Keys[0].Kid, header["kid"])
+
+			claims, err := auth.ParseToken(keys, token)
+			require.NoError(t, err, alg)
+			assert.Equal(t, fakeUser.Id, claims.UserId)
+		}
+	})
+
+	t.Run("should keep verifying tokens signed with a rotated out key", func(t *testing.T) {
+		t.Parallel()
+		previous, err := auth.NewKeySet(rsaKey)
+		require.NoError(t, err)
+		current, err := auth.NewKeySet(edKey, rsaKey.Public())
+		require.NoError(t, err)
+
+		oldToken := issue(previous)
+		newToken := issue(current)
+
+		_, err = auth.ParseToken(current, oldToken)
+		assert.NoError(t, err)
+		_, err = auth.ParseToken(current, newToken)
+		assert.NoError(t, err)
+		_, err = auth.ParseToken(previous, newToken)
+		assert.Error(t, err)
+		assert.Len(t, current.JWKS().Keys, 2)
+	})
+
+	t.Run("should reject unknown kids and algorithms that do not match the key", func(t *testing.T) {
+		t.Parallel()
+		keys, err := auth.NewKeySet(rsaKey)
+		require.NoError(t, err)
+		kid := keys.JWKS().Keys[0].Kid
+
+		claims := auth.AuthClaims{UserId: fakeUser.Id, RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    env.AuthIssuer(),
+			Audience:  jwt.ClaimStrings{env.AuthAudience()},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}}
+
+		unknown := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		unknown.Header["kid"] = "not-a-key"
+		unknownToken, err := unknown.SignedString(rsaKey)
+		require.NoError(t, err)
+
+		publicDER, err := x509.MarshalPKIXPublicKey(rsaKey.Public())
+		require.NoError(t, err)
+		confused := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		confused.Header["kid"] = kid
+		confusedToken, err := confused.SignedString(publicDER)
+		require.NoError(t, err)
+
+		_, err = auth.ParseToken(keys, unknownToken)
+		assert.ErrorIs(t, err, auth.ErrUnknownKey)
+		_, err = auth.ParseToken(keys, confusedToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("should load PEM keys from files", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		writePEM := func(name, blockType string, der []byte) string {
+			file := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+			return file
+		}
+		edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+		require.NoError(t, err)
+		rsaPublicDER, err := x509.MarshalPKIXPublicKey(rsaKey.Public())
+		require.NoError(t, err)
+
+		signing := writePEM("signing.pem", "PRIVATE KEY", edDER)
+		verification := writePEM("previous.pem", "PUBLIC KEY", rsaPublicDER)
+		legacy := writePEM("legacy.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+		keys, err := auth.LoadKeySet(signing, verification)
+		require.NoError(t, err)
+		previous, err := auth.LoadKeySet(legacy)
+		require.NoError(t, err)
+
+		_, err = auth.ParseToken(keys, issue(previous))
+		assert.NoError(t, err)
+		assert.Equal(t, "EdDSA", headerOf(issue(keys))["alg"])
+
+		_, err = auth.LoadKeySet(filepath.Join(dir, "missing.pem"))
+		assert.Error(t, err)
+		_, err = auth.LoadKeySet(verification)
+		assert.Error(t, err)
+	})
+
+	t.Run("should publish public keys only", func(t *testing.T) {
+		t.Parallel()
+		keys, err := auth.NewKeySet(rsaKey, edKey.Public())
+		require.NoError(t, err)
+		assert.Empty(t, auth.NewHMACKeySet([]byte("secret")).JWKS().Keys)
+
+		rec := httptest.NewRecorder()
+		auth.JWKSHandler(keys).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var jwks auth.JWKS
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &jwks))
+		require.Len(t, jwks.Keys, 2)
+		byType := map[string]auth.JWK{}
+		for _, key := range jwks.Keys {
+			byType[key.Kty] = key
+			assert.Equal(t, "sig", key.Use)
+			assert.NotEmpty(t, key.Kid)
+		}
+		assert.Equal(t, "RS256", byType["RSA"].Alg)
+		assert.NotEmpty(t, byType["RSA"].N)
+		assert.Equal(t, "AQAB", byType["RSA"].E)
+		assert.Equal(t, "EdDSA", byType["OKP"].Alg)
+		assert.Equal(t, "Ed25519", byType["OKP"].Crv)
+		assert.NotEmpty(t, byType["OKP"].X)
+		assert.NotContains(t, rec.Body.String(), `"d"`)
+	})
+}
@@ -0,0 +1,151 @@
+package mfa
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth/onetime"
+	"github.com/iammrsea/social-app/internal/shared/auth/totp"
+)
+
+const defaultLoginTTL = 5 * time.Minute
+
+type Option func(*Manager)
+
+// WithClock replaces time.Now as the source of the current time
+func WithClock(now func() time.Time) Option {
+	return func(m *Manager) {
+		m.now = now
+	}
+}
+
+// WithLoginTTL sets how long a user who logged in with a password has to enter their code
+func WithLoginTTL(ttl time.Duration) Option {
+	return func(m *Manager) {
+		m.loginTTL = ttl
+	}
+}
+
+// Manager sets up, checks and disables two-factor authentication, and runs the second step of
+// logging in
+type Manager struct {
+	store    Store
+	tokens   *onetime.Tokens
+	loginTTL time.Duration
+	now      func() time.Time
+}
+
+func NewManager(store Store, tokens *onetime.Tokens, opts ...Option) *Manager {
+	if store == nil || tokens == nil {
+		panic("nil two-factor store or single-use tokens")
+	}
+	m := &Manager{store: store, tokens: tokens, loginTTL: defaultLoginTTL, now: time.Now}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Setup starts enrolling a user with a secret made by totp.GenerateSecret. Two-factor
+// authentication is only enabled once Enable confirms the secret.
+func (m *Manager) Setup(ctx context.Context, userId, secret string) error {
+	if _, err := totp.Code(secret, m.now()); err != nil {
+		return ErrInvalidSecret
+	}
+	enabled, err := m.IsEnabled(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		return ErrAlreadyEnabled
+	}
+	return m.store.Save(ctx, Enrollment{UserId: userId, Secret: secret, CreatedAt: m.now()})
+}
+
+// Enable turns on two-factor authentication once the user proves their app has the secret, and
+// stores the recovery codes made by NewRecoveryCodes
+func (m *Manager) Enable(ctx context.Context, userId, code string, recoveryCodes []string) error {
+	if len(recoveryCodes) == 0 {
+		return ErrNoRecoveryCodes
+	}
+	enrollment, err := m.store.Find(ctx, userId)
+	if err != nil {
+		if errors.Is(err, ErrNotEnrolled) {
+			return ErrSetupNotCompleted
+		}
+		return err
+	}
+	if enrollment.IsEnabled() {
+		return ErrAlreadyEnabled
+	}
+	now := m.now()
+	step, ok := totp.Validate(enrollment.Secret, code, now)
+	if !ok {
+		return ErrInvalidCode
+	}
+	enrollment.RecoveryCodeHashes = make([]string, len(recoveryCodes))
+	for i, recoveryCode := range recoveryCodes {
+		enrollment.RecoveryCodeHashes[i] = HashRecoveryCode(recoveryCode)
+	}
+	enrollment.LastUsedStep = step
+	enrollment.EnabledAt = &now
+	return m.store.Save(ctx, enrollment)
+}
+
+// Verify accepts a code from the authenticator app or one of the recovery codes, each only once
+func (m *Manager) Verify(ctx context.Context, userId, code string) error {
+	enrollment, err := m.store.Find(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if !enrollment.IsEnabled() {
+		return ErrNotEnrolled
+	}
+	if step, ok := totp.Validate(enrollment.Secret, code, m.now()); ok {
+		return m.store.UseStep(ctx, userId, step)
+	}
+	return m.store.UseRecoveryCode(ctx, userId, HashRecoveryCode(code))
+}
+
+func (m *Manager) IsEnabled(ctx context.Context, userId string) (bool, error) {
+	enrollment, err := m.store.Find(ctx, userId)
+	if err != nil {
+		if errors.Is(err, ErrNotEnrolled) {
+			return false, nil
+		}
+		return false, err
+	}
+	return enrollment.IsEnabled(), nil
+}
+
+// Disable turns off two-factor authentication. It takes a code, so a stolen session can't do it.
+func (m *Manager) Disable(ctx context.Context, userId, code string) error {
+	if err := m.Verify(ctx, userId, code); err != nil {
+		return err
+	}
+	return m.store.Delete(ctx, userId)
+}
+
+// Challenge issues the token a user who logged in with a password trades, along with a code, for
+// a session. It returns when the token expires.
+func (m *Manager) Challenge(userId, email string) (string, time.Time, error) {
+	token, err := m.tokens.Issue(onetime.CompleteLogin, userId, email, m.loginTTL)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, m.now().Add(m.loginTTL).Truncate(time.Second), nil
+}
+
+// CompleteChallenge checks the code of the user a challenge token was issued to and returns the
+// user's id. The token works once, so a wrong code means logging in again.
+func (m *Manager) CompleteChallenge(ctx context.Context, token, code string) (string, error) {
+	claims, err := m.tokens.Use(ctx, onetime.CompleteLogin, token)
+	if err != nil {
+		return "", err
+	}
+	if err := m.Verify(ctx, claims.Subject, code); err != nil {
+		return "", err
+	}
+	return claims.Subject, nil
+}
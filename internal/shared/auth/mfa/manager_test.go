@@ -0,0 +1,138 @@
+package mfa_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/mfa"
+	"github.com/iammrsea/social-app/internal/shared/auth/onetime"
+	"github.com/iammrsea/social-app/internal/shared/auth/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newManager(c *clock) *mfa.Manager {
+	tokens := onetime.NewTokens(auth.NewHMACKeySet([]byte("test-secret")), onetime.NewMemoryStore(), "social-app", onetime.WithClock(c.Now))
+	return mfa.NewManager(mfa.NewMemoryStore(), tokens, mfa.WithClock(c.Now))
+}
+
+func code(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	code, err := totp.Code(secret, at)
+	require.NoError(t, err)
+	return code
+}
+
+// enable sets up two-factor authentication for user-1 and returns its secret and recovery codes
+func enable(t *testing.T, manager *mfa.Manager, c *clock) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	recoveryCodes, err := mfa.NewRecoveryCodes()
+	require.NoError(t, err)
+	require.NoError(t, manager.Setup(ctx, "user-1", secret))
+	require.NoError(t, manager.Enable(ctx, "user-1", code(t, secret, c.now), recoveryCodes))
+	return secret, recoveryCodes
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should only enable once the secret is confirmed", func(t *testing.T) {
+		t.Parallel()
+		c := &clock{now: time.Now()}
+		manager := newManager(c)
+		secret, err := totp.GenerateSecret()
+		require.NoError(t, err)
+		recoveryCodes, err := mfa.NewRecoveryCodes()
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, manager.Enable(ctx, "user-1", "123456", recoveryCodes), mfa.ErrSetupNotCompleted)
+		assert.ErrorIs(t, manager.Setup(ctx, "user-1", "not base32!"), mfa.ErrInvalidSecret)
+		require.NoError(t, manager.Setup(ctx, "user-1", secret))
+		enabled, err := manager.IsEnabled(ctx, "user-1")
+		require.NoError(t, err)
+		assert.False(t, enabled)
+
+		wrong := code(t, secret, c.now.Add(10*totp.Period))
+		assert.ErrorIs(t, manager.Enable(ctx, "user-1", wrong, recoveryCodes), mfa.ErrInvalidCode)
+		require.NoError(t, manager.Enable(ctx, "user-1", code(t, secret, c.now), recoveryCodes))
+		enabled, err = manager.IsEnabled(ctx, "user-1")
+		require.NoError(t, err)
+		assert.True(t, enabled)
+
+		assert.ErrorIs(t, manager.Setup(ctx, "user-1", secret), mfa.ErrAlreadyEnabled)
+	})
+
+	t.Run("should accept each code once", func(t *testing.T) {
+		t.Parallel()
+		c := &clock{now: time.Now()}
+		manager := newManager(c)
+		secret, _ := enable(t, manager, c)
+
+		// The code that enabled two-factor authentication is used up
+		assert.ErrorIs(t, manager.Verify(ctx, "user-1", code(t, secret, c.now)), mfa.ErrInvalidCode)
+
+		c.now = c.now.Add(totp.Period)
+		next := code(t, secret, c.now)
+		require.NoError(t, manager.Verify(ctx, "user-1", next))
+		assert.ErrorIs(t, manager.Verify(ctx, "user-1", next), mfa.ErrInvalidCode)
+	})
+
+	t.Run("should accept each recovery code once", func(t *testing.T) {
+		t.Parallel()
+		c := &clock{now: time.Now()}
+		manager := newManager(c)
+		_, recoveryCodes := enable(t, manager, c)
+		assert.Len(t, recoveryCodes, mfa.RecoveryCodeCount)
+
+		require.NoError(t, manager.Verify(ctx, "user-1", recoveryCodes[0]))
+		assert.ErrorIs(t, manager.Verify(ctx, "user-1", recoveryCodes[0]), mfa.ErrInvalidCode)
+		assert.ErrorIs(t, manager.Verify(ctx, "user-1", "00000-00000"), mfa.ErrInvalidCode)
+	})
+
+	t.Run("should complete a login challenge with a code", func(t *testing.T) {
+		t.Parallel()
+		c := &clock{now: time.Now()}
+		manager := newManager(c)
+		secret, _ := enable(t, manager, c)
+		c.now = c.now.Add(totp.Period)
+
+		token, expiresAt, err := manager.Challenge("user-1", "johndoe@example.com")
+		require.NoError(t, err)
+		assert.True(t, expiresAt.After(c.now))
+		userId, err := manager.CompleteChallenge(ctx, token, code(t, secret, c.now))
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", userId)
+
+		// The challenge can't be completed twice
+		_, err = manager.CompleteChallenge(ctx, token, code(t, secret, c.now))
+		assert.ErrorIs(t, err, onetime.ErrTokenUsed)
+	})
+
+	t.Run("should take a code to disable", func(t *testing.T) {
+		t.Parallel()
+		c := &clock{now: time.Now()}
+		manager := newManager(c)
+		_, recoveryCodes := enable(t, manager, c)
+
+		assert.ErrorIs(t, manager.Disable(ctx, "user-1", "00000-00000"), mfa.ErrInvalidCode)
+		require.NoError(t, manager.Disable(ctx, "user-1", recoveryCodes[1]))
+		enabled, err := manager.IsEnabled(ctx, "user-1")
+		require.NoError(t, err)
+		assert.False(t, enabled)
+		assert.ErrorIs(t, manager.Verify(ctx, "user-1", recoveryCodes[2]), mfa.ErrNotEnrolled)
+	})
+}
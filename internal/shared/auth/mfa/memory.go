@@ -0,0 +1,71 @@
+package mfa
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// MemoryStore keeps enrollments in memory, for the in-memory repositories and for tests
+type MemoryStore struct {
+	mu          sync.Mutex
+	enrollments map[string]Enrollment
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{enrollments: map[string]Enrollment{}}
+}
+
+func (s *MemoryStore) Save(ctx context.Context, enrollment Enrollment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	enrollment.RecoveryCodeHashes = slices.Clone(enrollment.RecoveryCodeHashes)
+	s.enrollments[enrollment.UserId] = enrollment
+	return nil
+}
+
+func (s *MemoryStore) Find(ctx context.Context, userId string) (Enrollment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	enrollment, ok := s.enrollments[userId]
+	if !ok {
+		return Enrollment{}, ErrNotEnrolled
+	}
+	enrollment.RecoveryCodeHashes = slices.Clone(enrollment.RecoveryCodeHashes)
+	return enrollment, nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, userId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.enrollments, userId)
+	return nil
+}
+
+func (s *MemoryStore) UseStep(ctx context.Context, userId string, step int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	enrollment, ok := s.enrollments[userId]
+	if !ok || enrollment.LastUsedStep >= step {
+		return ErrInvalidCode
+	}
+	enrollment.LastUsedStep = step
+	s.enrollments[userId] = enrollment
+	return nil
+}
+
+func (s *MemoryStore) UseRecoveryCode(ctx context.Context, userId, codeHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	enrollment, ok := s.enrollments[userId]
+	if !ok {
+		return ErrInvalidCode
+	}
+	i := slices.Index(enrollment.RecoveryCodeHashes, codeHash)
+	if i < 0 {
+		return ErrInvalidCode
+	}
+	enrollment.RecoveryCodeHashes = slices.Delete(slices.Clone(enrollment.RecoveryCodeHashes), i, i+1)
+	s.enrollments[userId] = enrollment
+	return nil
+}
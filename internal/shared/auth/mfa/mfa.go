@@ -0,0 +1,81 @@
+package mfa
+
+// Two-factor authentication with the time-based one-time codes of authenticator apps. Users set
+// up a secret, confirm it with a first code and receive recovery codes, each usable once, for
+// when they lose their device. Logging in then takes a code on top of the password.
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+// RecoveryCodeCount is how many recovery codes a user gets when enabling two-factor authentication
+const RecoveryCodeCount = 10
+
+var (
+	ErrNotEnrolled       = errors.New("two-factor authentication is not enabled")
+	ErrAlreadyEnabled    = errors.New("two-factor authentication is already enabled")
+	ErrInvalidCode       = errors.New("invalid or already used two-factor code")
+	ErrInvalidSecret     = errors.New("invalid two-factor secret")
+	ErrNoRecoveryCodes   = errors.New("two-factor authentication needs recovery codes")
+	ErrSetupNotCompleted = errors.New("two-factor authentication was not set up")
+)
+
+type Enrollment struct {
+	UserId string
+	// Secret is shared with the authenticator app. Unlike a password it is needed in clear to check
+	// codes, so it can't be hashed.
+	Secret             string
+	RecoveryCodeHashes []string
+	// LastUsedStep is the time step of the last code accepted, so that no code is accepted twice
+	LastUsedStep int64
+	CreatedAt    time.Time
+	// EnabledAt is nil until the user confirms the secret with a code
+	EnabledAt *time.Time
+}
+
+func (e Enrollment) IsEnabled() bool {
+	return e.EnabledAt != nil
+}
+
+type Store interface {
+	// Save creates or replaces the enrollment of a user
+	Save(ctx context.Context, enrollment Enrollment) error
+	// Find returns ErrNotEnrolled if the user never set up two-factor authentication
+	Find(ctx context.Context, userId string) (Enrollment, error)
+	Delete(ctx context.Context, userId string) error
+	// UseStep records that the code of step was used. It returns ErrInvalidCode if a code of the
+	// same or a later step was used before.
+	UseStep(ctx context.Context, userId string, step int64) error
+	// UseRecoveryCode removes a recovery code. It returns ErrInvalidCode if the user has no such code.
+	UseRecoveryCode(ctx context.Context, userId, codeHash string) error
+}
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewRecoveryCodes generates RecoveryCodeCount random codes such as "k3xq7-m2pwd"
+func NewRecoveryCodes() ([]string, error) {
+	codes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(recoveryEncoding.EncodeToString(b))[:10]
+		codes[i] = code[:5] + "-" + code[5:]
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode is how recovery codes are stored. Case, spaces and dashes don't matter.
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,10 +2,8 @@ package auth
 
 import (
 	"context"
-	"strings"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/iammrsea/social-app/internal/shared/config"
 	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
 )
@@ -15,36 +13,30 @@ const (
 	authUserEmail = "johndoe@example.com"
 )
 
-func GetFakeUser(role rbac.UserRole) *AuthenticatedUser {
-	r := role
-	if strings.TrimSpace(string(r)) == "" {
-		r = rbac.Moderator
+func GetFakeUser(roles ...rbac.UserRole) *AuthenticatedUser {
+	if len(roles) == 0 {
+		roles = []rbac.UserRole{rbac.Moderator}
 	}
 	return &AuthenticatedUser{
 		Email: authUserEmail,
 		Id:    authUserId,
-		Role:  r,
+		Roles: roles,
 	}
 }
 
 func GenerateTestToken(user *AuthenticatedUser) string {
-	claims := AuthClaims{
-		UserId: user.Id,
-		Email:  user.Email,
-		Role:   user.Role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)),
-		},
+	keys, err := DefaultKeySet()
+	if err != nil {
+		panic(err)
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	secret := []byte(config.NewEnv().AuthSecret())
-	signedToken, err := token.SignedString(secret)
+	env := config.NewEnv()
+	issuer := NewTokenIssuer(keys, env.AuthIssuer(), env.AuthAudience(), time.Hour*24)
+	token, err := issuer.Issue(user)
 
 	if err != nil {
 		panic(err)
 	}
-	return signedToken
+	return token.AccessToken
 }
 
 func NewContextWithUser(ctx context.Context, user *AuthenticatedUser) context.Context {
@@ -0,0 +1,33 @@
+package onetime
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps used tokens in memory, for the in-memory repositories and for tests
+type MemoryStore struct {
+	mu   sync.Mutex
+	used map[string]time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{used: map[string]time.Time{}}
+}
+
+func (s *MemoryStore) Use(ctx context.Context, tokenId string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for id, expiry := range s.used {
+		if !expiry.After(now) {
+			delete(s.used, id)
+		}
+	}
+	if _, ok := s.used[tokenId]; ok {
+		return ErrTokenUsed
+	}
+	s.used[tokenId] = expiresAt
+	return nil
+}
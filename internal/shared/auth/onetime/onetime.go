@@ -0,0 +1,106 @@
+package onetime
+
+// Single-use tokens for the links the app sends by email, such as email verification and password
+// reset, and for the second step of logging in with two-factor authentication. The tokens are
+// signed JWTs, so they can't be forged or altered, and the id of every used token is kept until
+// the token expires, so each token works once.
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/lucsky/cuid"
+)
+
+// Purpose is what a token can be used for. A token issued for one purpose is refused for another.
+type Purpose string
+
+const (
+	VerifyEmail   Purpose = "verify_email"
+	ResetPassword Purpose = "reset_password"
+	// CompleteLogin is the second step of logging in with two-factor authentication
+	CompleteLogin Purpose = "complete_login"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid or expired token")
+	ErrTokenUsed    = errors.New("token was already used")
+)
+
+type Claims struct {
+	// Email is the address the token was sent to
+	Email string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// Store remembers used tokens until they expire
+type Store interface {
+	// Use marks a token as used. It returns ErrTokenUsed if it was used before.
+	Use(ctx context.Context, tokenId string, expiresAt time.Time) error
+}
+
+type Option func(*Tokens)
+
+// WithClock replaces time.Now as the source of the current time
+func WithClock(now func() time.Time) Option {
+	return func(t *Tokens) {
+		t.now = now
+	}
+}
+
+// Tokens issues and redeems single-use tokens
+type Tokens struct {
+	keys   *auth.KeySet
+	store  Store
+	issuer string
+	now    func() time.Time
+}
+
+func NewTokens(keys *auth.KeySet, store Store, issuer string, opts ...Option) *Tokens {
+	if keys == nil || store == nil || issuer == "" {
+		panic("single-use tokens need keys, a store and an issuer")
+	}
+	t := &Tokens{keys: keys, store: store, issuer: issuer, now: time.Now}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Issue creates a token for purpose, sent to the email of the user, that expires after ttl
+func (t *Tokens) Issue(purpose Purpose, userId, email string, ttl time.Duration) (string, error) {
+	issuedAt := t.now()
+	return t.keys.Sign(Claims{
+		Email: email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        cuid.New(),
+			Subject:   userId,
+			Issuer:    t.issuer,
+			Audience:  jwt.ClaimStrings{string(purpose)},
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(issuedAt.Add(ttl)),
+		},
+	})
+}
+
+// Use redeems a token issued for purpose. It returns ErrInvalidToken for forged, expired and
+// misused tokens, and ErrTokenUsed for tokens redeemed before.
+func (t *Tokens) Use(ctx context.Context, purpose Purpose, token string) (Claims, error) {
+	var claims Claims
+	err := t.keys.Parse(token, &claims,
+		jwt.WithIssuer(t.issuer),
+		jwt.WithAudience(string(purpose)),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(t.now),
+	)
+	if err != nil || claims.ID == "" || claims.Subject == "" {
+		return Claims{}, ErrInvalidToken
+	}
+	if err := t.store.Use(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return Claims{}, err
+	}
+	return claims, nil
+}
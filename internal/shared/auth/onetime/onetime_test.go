@@ -0,0 +1,77 @@
+package onetime_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/onetime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newTokens(opts ...onetime.Option) *onetime.Tokens {
+	return onetime.NewTokens(auth.NewHMACKeySet([]byte("test-secret")), onetime.NewMemoryStore(), "social-app", opts...)
+}
+
+func TestTokens(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should redeem a token once", func(t *testing.T) {
+		t.Parallel()
+		tokens := newTokens()
+		token, err := tokens.Issue(onetime.VerifyEmail, "user-1", "johndoe@example.com", time.Hour)
+		require.NoError(t, err)
+
+		claims, err := tokens.Use(ctx, onetime.VerifyEmail, token)
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", claims.Subject)
+		assert.Equal(t, "johndoe@example.com", claims.Email)
+
+		_, err = tokens.Use(ctx, onetime.VerifyEmail, token)
+		assert.ErrorIs(t, err, onetime.ErrTokenUsed)
+	})
+
+	t.Run("should refuse tokens issued for another purpose", func(t *testing.T) {
+		t.Parallel()
+		tokens := newTokens()
+		token, err := tokens.Issue(onetime.VerifyEmail, "user-1", "johndoe@example.com", time.Hour)
+		require.NoError(t, err)
+
+		_, err = tokens.Use(ctx, onetime.ResetPassword, token)
+		assert.ErrorIs(t, err, onetime.ErrInvalidToken)
+		// The refused attempt didn't use up the token
+		_, err = tokens.Use(ctx, onetime.VerifyEmail, token)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should refuse expired, forged and access tokens", func(t *testing.T) {
+		t.Parallel()
+		c := &clock{now: time.Now()}
+		tokens := newTokens(onetime.WithClock(c.Now))
+		expiring, err := tokens.Issue(onetime.ResetPassword, "user-1", "johndoe@example.com", time.Hour)
+		require.NoError(t, err)
+		forged, err := onetime.NewTokens(auth.NewHMACKeySet([]byte("another-secret")), onetime.NewMemoryStore(), "social-app").
+			Issue(onetime.ResetPassword, "user-1", "johndoe@example.com", time.Hour)
+		require.NoError(t, err)
+		accessToken, err := auth.NewTokenIssuer(auth.NewHMACKeySet([]byte("test-secret")), "social-app", "social-app", time.Hour).
+			Issue(&auth.AuthenticatedUser{Id: "user-1", Email: "johndoe@example.com"})
+		require.NoError(t, err)
+
+		c.now = c.now.Add(2 * time.Hour)
+		for _, token := range []string{expiring, forged, accessToken.AccessToken, "not-a-token"} {
+			_, err := tokens.Use(ctx, onetime.ResetPassword, token)
+			assert.ErrorIs(t, err, onetime.ErrInvalidToken)
+		}
+	})
+}
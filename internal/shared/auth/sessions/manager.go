@@ -0,0 +1,166 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/lucsky/cuid"
+)
+
+const defaultRefreshTTL = 30 * 24 * time.Hour
+
+type Option func(*Manager)
+
+// WithRefreshTTL sets how long a refresh token can be used
+func WithRefreshTTL(ttl time.Duration) Option {
+	return func(m *Manager) {
+		m.refreshTTL = ttl
+	}
+}
+
+// WithClock replaces time.Now as the source of the current time
+func WithClock(now func() time.Time) Option {
+	return func(m *Manager) {
+		m.now = now
+	}
+}
+
+// UserLoader loads the user a refresh token was issued to, so that the new access token carries
+// the user's current role
+type UserLoader func(ctx context.Context, userId string) (*auth.AuthenticatedUser, error)
+
+// Manager starts, refreshes and ends login sessions
+type Manager struct {
+	store      Store
+	denylist   Denylist
+	issuer     auth.TokenIssuer
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+func NewManager(store Store, denylist Denylist, issuer auth.TokenIssuer, opts ...Option) *Manager {
+	if store == nil || denylist == nil || issuer == nil {
+		panic("nil session store, denylist or token issuer")
+	}
+	m := &Manager{
+		store:      store,
+		denylist:   denylist,
+		issuer:     issuer,
+		refreshTTL: defaultRefreshTTL,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Start opens a new session for a user who just logged in on device
+func (m *Manager) Start(ctx context.Context, user auth.AuthenticatedUser, device string) (*auth.Token, error) {
+	user.SessionId = cuid.New()
+	token, next, err := m.issue(&user, device)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.store.Save(ctx, next); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// Refresh trades a refresh token for a new access token and refresh token. A refresh token that
+// was already used revokes its session.
+func (m *Manager) Refresh(ctx context.Context, refreshToken string, loadUser UserLoader) (*auth.Token, error) {
+	current, err := m.store.FindByHash(ctx, HashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, ErrRefreshTokenNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	if current.Rotated {
+		return nil, m.revokeReusedSession(ctx, current.SessionId)
+	}
+	if current.Revoked || !m.now().Before(current.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := loadUser(ctx, current.UserId)
+	if err != nil {
+		return nil, err
+	}
+	user.SessionId = current.SessionId
+	user.MFA = current.MFA
+	token, next, err := m.issue(user, current.Device)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.store.Rotate(ctx, current.Id, next, m.now()); err != nil {
+		if errors.Is(err, ErrRefreshTokenReused) {
+			return nil, m.revokeReusedSession(ctx, current.SessionId)
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// Logout ends a session. The access tokens issued in it stop working right away.
+func (m *Manager) Logout(ctx context.Context, sessionId string) error {
+	revoked, err := m.store.RevokeSession(ctx, sessionId, m.now())
+	if err != nil {
+		return err
+	}
+	return m.deny(ctx, revoked)
+}
+
+// LogoutAll ends every session of a user
+func (m *Manager) LogoutAll(ctx context.Context, userId string) error {
+	revoked, err := m.store.RevokeUserSessions(ctx, userId, m.now())
+	if err != nil {
+		return err
+	}
+	return m.deny(ctx, revoked)
+}
+
+func (m *Manager) issue(user *auth.AuthenticatedUser, device string) (*auth.Token, RefreshToken, error) {
+	token, err := m.issuer.Issue(user)
+	if err != nil {
+		return nil, RefreshToken{}, err
+	}
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return nil, RefreshToken{}, err
+	}
+	now := m.now()
+	next := RefreshToken{
+		Id:          cuid.New(),
+		SessionId:   user.SessionId,
+		UserId:      user.Id,
+		TokenHash:   HashRefreshToken(refreshToken),
+		Device:      device,
+		AccessToken: AccessToken{Id: token.Id, ExpiresAt: token.ExpiresAt},
+		MFA:         user.MFA,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(m.refreshTTL),
+	}
+	token.RefreshToken = refreshToken
+	token.RefreshTokenExpiresAt = next.ExpiresAt
+	return token, next, nil
+}
+
+func (m *Manager) revokeReusedSession(ctx context.Context, sessionId string) error {
+	if err := m.Logout(ctx, sessionId); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+func (m *Manager) deny(ctx context.Context, tokens []AccessToken) error {
+	tokens = stillValid(tokens, m.now())
+	if len(tokens) == 0 {
+		return nil
+	}
+	return m.denylist.Deny(ctx, tokens)
+}
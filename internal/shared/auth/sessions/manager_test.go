@@ -0,0 +1,183 @@
+package sessions_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/sessions"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+var user = auth.AuthenticatedUser{Id: "user-1", Email: "johndoe@example.com", Roles: []rbac.UserRole{rbac.Regular}}
+
+func loadUser(role rbac.UserRole) sessions.UserLoader {
+	return func(ctx context.Context, userId string) (*auth.AuthenticatedUser, error) {
+		u := user
+		u.Roles = []rbac.UserRole{role}
+		return &u, nil
+	}
+}
+
+func newManager(t *testing.T, opts ...sessions.Option) (*sessions.Manager, *sessions.MemoryDenylist) {
+	t.Helper()
+	denylist := sessions.NewMemoryDenylist()
+	issuer := auth.NewTokenIssuer(auth.NewHMACKeySet([]byte("test-secret")), "social-app", "social-app", time.Hour)
+	return sessions.NewManager(sessions.NewMemoryStore(), denylist, issuer, opts...), denylist
+}
+
+func isRevoked(t *testing.T, denylist *sessions.MemoryDenylist, token *auth.Token) bool {
+	t.Helper()
+	revoked, err := denylist.IsRevoked(context.Background(), token.Id)
+	require.NoError(t, err)
+	return revoked
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should issue an access token and a refresh token when a session starts", func(t *testing.T) {
+		t.Parallel()
+		manager, _ := newManager(t, sessions.WithRefreshTTL(time.Hour*24))
+
+		token, err := manager.Start(ctx, user, "phone")
+		require.NoError(t, err)
+		assert.NotEmpty(t, token.Id)
+		assert.NotEmpty(t, token.AccessToken)
+		assert.NotEmpty(t, token.RefreshToken)
+		assert.WithinDuration(t, time.Now().Add(24*time.Hour), token.RefreshTokenExpiresAt, time.Minute)
+	})
+
+	t.Run("should rotate the refresh token and pick up the user's current role", func(t *testing.T) {
+		t.Parallel()
+		manager, _ := newManager(t)
+		first, err := manager.Start(ctx, user, "phone")
+		require.NoError(t, err)
+
+		second, err := manager.Refresh(ctx, first.RefreshToken, loadUser(rbac.Moderator))
+		require.NoError(t, err)
+		assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+		assert.NotEqual(t, first.Id, second.Id)
+
+		third, err := manager.Refresh(ctx, second.RefreshToken, loadUser(rbac.Moderator))
+		require.NoError(t, err)
+		assert.NotEmpty(t, third.AccessToken)
+	})
+
+	t.Run("should revoke the session when a refresh token is used twice", func(t *testing.T) {
+		t.Parallel()
+		manager, denylist := newManager(t)
+		first, err := manager.Start(ctx, user, "phone")
+		require.NoError(t, err)
+		second, err := manager.Refresh(ctx, first.RefreshToken, loadUser(rbac.Regular))
+		require.NoError(t, err)
+
+		_, err = manager.Refresh(ctx, first.RefreshToken, loadUser(rbac.Regular))
+		assert.ErrorIs(t, err, sessions.ErrRefreshTokenReused)
+
+		_, err = manager.Refresh(ctx, second.RefreshToken, loadUser(rbac.Regular))
+		assert.ErrorIs(t, err, sessions.ErrInvalidRefreshToken, "the legitimate token was revoked with the session")
+		assert.True(t, isRevoked(t, denylist, first))
+		assert.True(t, isRevoked(t, denylist, second))
+	})
+
+	t.Run("should refuse unknown and expired refresh tokens", func(t *testing.T) {
+		t.Parallel()
+		clk := &clock{now: time.Now()}
+		manager, _ := newManager(t, sessions.WithClock(clk.Now), sessions.WithRefreshTTL(time.Hour))
+		token, err := manager.Start(ctx, user, "phone")
+		require.NoError(t, err)
+
+		_, err = manager.Refresh(ctx, "not-a-refresh-token", loadUser(rbac.Regular))
+		assert.ErrorIs(t, err, sessions.ErrInvalidRefreshToken)
+
+		clk.now = clk.now.Add(time.Hour)
+		_, err = manager.Refresh(ctx, token.RefreshToken, loadUser(rbac.Regular))
+		assert.ErrorIs(t, err, sessions.ErrInvalidRefreshToken)
+	})
+
+	t.Run("should end only the session that logs out", func(t *testing.T) {
+		t.Parallel()
+		manager, denylist := newManager(t)
+		phone, err := manager.Start(ctx, user, "phone")
+		require.NoError(t, err)
+		laptop, err := manager.Start(ctx, user, "laptop")
+		require.NoError(t, err)
+
+		require.NoError(t, manager.Logout(ctx, sessionOf(t, phone)))
+
+		assert.True(t, isRevoked(t, denylist, phone))
+		assert.False(t, isRevoked(t, denylist, laptop))
+		_, err = manager.Refresh(ctx, phone.RefreshToken, loadUser(rbac.Regular))
+		assert.ErrorIs(t, err, sessions.ErrInvalidRefreshToken)
+		_, err = manager.Refresh(ctx, laptop.RefreshToken, loadUser(rbac.Regular))
+		assert.NoError(t, err)
+	})
+
+	t.Run("should end every session of a user", func(t *testing.T) {
+		t.Parallel()
+		manager, denylist := newManager(t)
+		phone, err := manager.Start(ctx, user, "phone")
+		require.NoError(t, err)
+		laptop, err := manager.Start(ctx, user, "laptop")
+		require.NoError(t, err)
+		other := user
+		other.Id = "user-2"
+		someoneElse, err := manager.Start(ctx, other, "phone")
+		require.NoError(t, err)
+
+		require.NoError(t, manager.LogoutAll(ctx, user.Id))
+
+		assert.True(t, isRevoked(t, denylist, phone))
+		assert.True(t, isRevoked(t, denylist, laptop))
+		assert.False(t, isRevoked(t, denylist, someoneElse))
+	})
+
+	t.Run("should keep the second factor of the session across refreshes", func(t *testing.T) {
+		t.Parallel()
+		manager, _ := newManager(t)
+		withMFA := user
+		withMFA.MFA = true
+		first, err := manager.Start(ctx, withMFA, "phone")
+		require.NoError(t, err)
+
+		// The user loader knows nothing about how the user logged in
+		second, err := manager.Refresh(ctx, first.RefreshToken, loadUser(rbac.Regular))
+		require.NoError(t, err)
+		assert.True(t, claimsOf(t, first).MFA)
+		assert.True(t, claimsOf(t, second).MFA)
+
+		without, err := manager.Start(ctx, user, "laptop")
+		require.NoError(t, err)
+		assert.False(t, claimsOf(t, without).MFA)
+	})
+}
+
+// sessionOf reads the session id from the claims of an access token, like the auth middleware
+func sessionOf(t *testing.T, token *auth.Token) string {
+	t.Helper()
+	claims := claimsOf(t, token)
+	require.NotEmpty(t, claims.SessionId)
+	return claims.SessionId
+}
+
+func claimsOf(t *testing.T, token *auth.Token) *auth.AuthClaims {
+	t.Helper()
+	claims := &auth.AuthClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(token.AccessToken, claims)
+	require.NoError(t, err)
+	return claims
+}
@@ -0,0 +1,107 @@
+package sessions
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps refresh tokens in memory, for the in-memory repositories and for tests
+type MemoryStore struct {
+	mu     sync.Mutex
+	tokens []*RefreshToken
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Save(ctx context.Context, token RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = append(s.tokens, &token)
+	return nil
+}
+
+func (s *MemoryStore) FindByHash(ctx context.Context, tokenHash string) (RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, token := range s.tokens {
+		if token.TokenHash == tokenHash {
+			return *token, nil
+		}
+	}
+	return RefreshToken{}, ErrRefreshTokenNotFound
+}
+
+func (s *MemoryStore) Rotate(ctx context.Context, currentId string, next RefreshToken, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, token := range s.tokens {
+		if token.Id != currentId {
+			continue
+		}
+		if token.Rotated || token.Revoked {
+			return ErrRefreshTokenReused
+		}
+		token.Rotated = true
+		s.tokens = append(s.tokens, &next)
+		return nil
+	}
+	return ErrRefreshTokenNotFound
+}
+
+func (s *MemoryStore) RevokeSession(ctx context.Context, sessionId string, now time.Time) ([]AccessToken, error) {
+	return s.revoke(func(token *RefreshToken) bool { return token.SessionId == sessionId }, now), nil
+}
+
+func (s *MemoryStore) RevokeUserSessions(ctx context.Context, userId string, now time.Time) ([]AccessToken, error) {
+	return s.revoke(func(token *RefreshToken) bool { return token.UserId == userId }, now), nil
+}
+
+func (s *MemoryStore) revoke(match func(token *RefreshToken) bool, now time.Time) []AccessToken {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	revoked := []AccessToken{}
+	for _, token := range s.tokens {
+		if !match(token) || token.Revoked {
+			continue
+		}
+		token.Revoked = true
+		revoked = append(revoked, token.AccessToken)
+	}
+	return stillValid(revoked, now)
+}
+
+// MemoryDenylist keeps revoked access tokens in memory, for the in-memory repositories and for tests
+type MemoryDenylist struct {
+	mu     sync.Mutex
+	tokens map[string]time.Time
+	now    func() time.Time
+}
+
+func NewMemoryDenylist() *MemoryDenylist {
+	return &MemoryDenylist{tokens: map[string]time.Time{}, now: time.Now}
+}
+
+func (d *MemoryDenylist) Deny(ctx context.Context, tokens []AccessToken) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	for id, expiresAt := range d.tokens {
+		if !expiresAt.After(now) {
+			delete(d.tokens, id)
+		}
+	}
+	for _, token := range tokens {
+		d.tokens[token.Id] = token.ExpiresAt
+	}
+	return nil
+}
+
+func (d *MemoryDenylist) IsRevoked(ctx context.Context, tokenId string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.tokens[tokenId]
+	return ok, nil
+}
@@ -0,0 +1,89 @@
+package sessions
+
+// Keeps track of login sessions. Every session holds a chain of refresh tokens: each one can be
+// used once to get a new access token and the next refresh token. Using a refresh token a second
+// time means it was stolen, so the whole session is revoked.
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidRefreshToken  = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused   = errors.New("refresh token was already used, the session has been revoked")
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+)
+
+// RefreshToken is stored by the hash of the token handed to the user, never the token itself
+type RefreshToken struct {
+	Id        string
+	SessionId string
+	UserId    string
+	TokenHash string
+	Device    string
+	// AccessToken is the access token issued together with the refresh token
+	AccessToken AccessToken
+	// MFA is set for sessions started with two-factor authentication, and carried over to every
+	// access token issued in the session
+	MFA       bool
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	Rotated   bool
+	Revoked   bool
+}
+
+// AccessToken identifies an issued access token by its jti
+type AccessToken struct {
+	Id        string
+	ExpiresAt time.Time
+}
+
+type Store interface {
+	Save(ctx context.Context, token RefreshToken) error
+	// FindByHash returns ErrRefreshTokenNotFound if no refresh token has the hash
+	FindByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
+	// Rotate marks a refresh token as used and saves the one replacing it. It returns
+	// ErrRefreshTokenReused if the token was rotated or revoked in the meantime.
+	Rotate(ctx context.Context, currentId string, next RefreshToken, now time.Time) error
+	// RevokeSession revokes the refresh tokens of a session and returns the access tokens issued
+	// with them that haven't expired yet
+	RevokeSession(ctx context.Context, sessionId string, now time.Time) ([]AccessToken, error)
+	// RevokeUserSessions revokes every session of a user, like RevokeSession
+	RevokeUserSessions(ctx context.Context, userId string, now time.Time) ([]AccessToken, error)
+}
+
+// Denylist holds revoked access tokens until they expire
+type Denylist interface {
+	Deny(ctx context.Context, tokens []AccessToken) error
+	IsRevoked(ctx context.Context, tokenId string) (bool, error)
+}
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashRefreshToken is how refresh tokens are looked up in the store
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func stillValid(tokens []AccessToken, now time.Time) []AccessToken {
+	valid := []AccessToken{}
+	for _, token := range tokens {
+		if token.Id != "" && token.ExpiresAt.After(now) {
+			valid = append(valid, token)
+		}
+	}
+	return valid
+}
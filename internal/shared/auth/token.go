@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/iammrsea/social-app/internal/shared/config"
+	"github.com/lucsky/cuid"
+)
+
+const BearerTokenType = "Bearer"
+
+// Token is a signed access token handed to a user who logged in, along with the refresh token
+// that gets the next one
+type Token struct {
+	// Id is the jti of the access token
+	Id                    string
+	AccessToken           string
+	TokenType             string
+	ExpiresAt             time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+	// MFAToken is set instead of the other tokens when the user still has to enter a two-factor
+	// code. ExpiresAt is then when the MFA token expires.
+	MFAToken string
+}
+
+type TokenIssuer interface {
+	// Issue signs an access token that identifies user
+	Issue(user *AuthenticatedUser) (*Token, error)
+}
+
+type tokenIssuer struct {
+	keys     *KeySet
+	issuer   string
+	audience string
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+// NewTokenIssuer issues access tokens signed with the signing key of keys
+func NewTokenIssuer(keys *KeySet, issuer, audience string, ttl time.Duration) TokenIssuer {
+	if keys == nil || issuer == "" || audience == "" || ttl <= 0 {
+		panic("token issuer needs keys, an issuer, an audience and a positive ttl")
+	}
+	return &tokenIssuer{keys: keys, issuer: issuer, audience: audience, ttl: ttl, now: time.Now}
+}
+
+// NewTokenIssuerFromEnv issues access tokens that ParseTokenFromRequest accepts, with the keys,
+// issuer, audience and ttl from the environment
+func NewTokenIssuerFromEnv() TokenIssuer {
+	keys, err := DefaultKeySet()
+	if err != nil {
+		panic(err)
+	}
+	env := config.NewEnv()
+	return NewTokenIssuer(keys, env.AuthIssuer(), env.AuthAudience(), env.AccessTokenTTL())
+}
+
+func (i *tokenIssuer) Issue(user *AuthenticatedUser) (*Token, error) {
+	issuedAt := i.now()
+	expiresAt := issuedAt.Add(i.ttl)
+	tokenId := cuid.New()
+	claims := AuthClaims{
+		UserId:    user.Id,
+		Email:     user.Email,
+		Roles:     user.Roles,
+		SessionId: user.SessionId,
+		// Tokens issued before email verification existed carry no claim and count as verified
+		EmailUnverified: user.EmailUnverified,
+		MFA:             user.MFA,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenId,
+			Issuer:    i.issuer,
+			Audience:  jwt.ClaimStrings{i.audience},
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			NotBefore: jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	signed, err := i.keys.Sign(claims)
+	if err != nil {
+		return nil, err
+	}
+	return &Token{Id: tokenId, AccessToken: signed, TokenType: BearerTokenType, ExpiresAt: expiresAt.Truncate(time.Second)}, nil
+}
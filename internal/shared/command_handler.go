@@ -5,3 +5,9 @@ import "context"
 type CommandHandler[T any] interface {
 	Handle(ctx context.Context, cmd T) error
 }
+
+// ResultCommandHandler runs a command that hands back what it produced, when the caller can't make
+// it up front the way it makes ids and keys, such as the tokens of a new session
+type ResultCommandHandler[T any, R any] interface {
+	Handle(ctx context.Context, cmd T) (R, error)
+}
@@ -37,6 +37,48 @@ const (
 	MONGODB_RETRY_WRITES ENV_VARIABLE = "MONGODB_RETRY_WRITES"
 	MONGODB_RETRY_READS  ENV_VARIABLE = "MONGODB_RETRY_READS"
 	POSTGRES_URI         ENV_VARIABLE = "POSTGRES_URI"
+	MAX_COMMENT_DEPTH    ENV_VARIABLE = "MAX_COMMENT_DEPTH"
+	BAN_EXPIRY_INTERVAL  ENV_VARIABLE = "BAN_EXPIRY_INTERVAL"
+	AUTH_ISSUER          ENV_VARIABLE = "AUTH_ISSUER"
+	AUTH_AUDIENCE        ENV_VARIABLE = "AUTH_AUDIENCE"
+	ACCESS_TOKEN_TTL     ENV_VARIABLE = "ACCESS_TOKEN_TTL"
+	REFRESH_TOKEN_TTL    ENV_VARIABLE = "REFRESH_TOKEN_TTL"
+
+	AUTH_SIGNING_KEY_FILE       ENV_VARIABLE = "AUTH_SIGNING_KEY_FILE"
+	AUTH_VERIFICATION_KEY_FILES ENV_VARIABLE = "AUTH_VERIFICATION_KEY_FILES"
+
+	APP_URL                ENV_VARIABLE = "APP_URL"
+	EMAIL_VERIFICATION_TTL ENV_VARIABLE = "EMAIL_VERIFICATION_TTL"
+	PASSWORD_RESET_TTL     ENV_VARIABLE = "PASSWORD_RESET_TTL"
+	MAIL_FROM              ENV_VARIABLE = "MAIL_FROM"
+	MAIL_DROP_DIR          ENV_VARIABLE = "MAIL_DROP_DIR"
+	SMTP_HOST              ENV_VARIABLE = "SMTP_HOST"
+	SMTP_PORT              ENV_VARIABLE = "SMTP_PORT"
+	SMTP_USERNAME          ENV_VARIABLE = "SMTP_USERNAME"
+	SMTP_PASSWORD          ENV_VARIABLE = "SMTP_PASSWORD"
+
+	MFA_ISSUER                        ENV_VARIABLE = "MFA_ISSUER"
+	MFA_LOGIN_TTL                     ENV_VARIABLE = "MFA_LOGIN_TTL"
+	MFA_REQUIRED_FOR_PRIVILEGED_ROLES ENV_VARIABLE = "MFA_REQUIRED_FOR_PRIVILEGED_ROLES"
+
+	RBAC_POLICY_SOURCE          ENV_VARIABLE = "RBAC_POLICY_SOURCE"
+	RBAC_POLICY_FILE            ENV_VARIABLE = "RBAC_POLICY_FILE"
+	RBAC_POLICY_RELOAD_INTERVAL ENV_VARIABLE = "RBAC_POLICY_RELOAD_INTERVAL"
+
+	ACCOUNT_DELETION_GRACE_PERIOD ENV_VARIABLE = "ACCOUNT_DELETION_GRACE_PERIOD"
+	ACCOUNT_DELETION_INTERVAL     ENV_VARIABLE = "ACCOUNT_DELETION_INTERVAL"
+
+	STORAGE_ENGINE     ENV_VARIABLE = "STORAGE_ENGINE"
+	MIGRATE_ON_STARTUP ENV_VARIABLE = "MIGRATE_ON_STARTUP"
+)
+
+type PolicySource string
+
+const (
+	// BuiltinPolicy is the default policy compiled into the app
+	BuiltinPolicy  PolicySource = "builtin"
+	FilePolicy     PolicySource = "file"
+	DatabasePolicy PolicySource = "database"
 )
 
 type env struct {
@@ -53,6 +95,39 @@ type env struct {
 	mongoDbRetryReads  bool
 	timeout            time.Duration
 	postgresURI        string
+	maxCommentDepth    int
+	banExpiryInterval  time.Duration
+	authIssuer         string
+	authAudience       string
+	accessTokenTTL     time.Duration
+	refreshTokenTTL    time.Duration
+
+	authSigningKeyFile       string
+	authVerificationKeyFiles []string
+
+	appURL               string
+	emailVerificationTTL time.Duration
+	passwordResetTTL     time.Duration
+	mailFrom             string
+	mailDropDir          string
+	smtpHost             string
+	smtpPort             int
+	smtpUsername         string
+	smtpPassword         string
+
+	mfaIssuer                     string
+	mfaLoginTTL                   time.Duration
+	mfaRequiredForPrivilegedRoles bool
+
+	rbacPolicySource         PolicySource
+	rbacPolicyFile           string
+	rbacPolicyReloadInterval time.Duration
+
+	accountDeletionGracePeriod time.Duration
+	accountDeletionInterval    time.Duration
+
+	storageEngine    StorageEngine
+	migrateOnStartup bool
 }
 
 func init() {
@@ -69,7 +144,7 @@ const DEFAULT_PORT = "8080"
 
 func NewEnv() *env {
 	return &env{
-		authSecret:         mustGetEnv(AUTH_SECRET),
+		authSecret:         getEnv(AUTH_SECRET),
 		goEnv:              Environment(mustGetEnv(GO_ENV)),
 		port:               getEnvWithDefault(PORT, DEFAULT_PORT),
 		mongoDbURI:         getEnv(MONGODB_URI),
@@ -82,9 +157,43 @@ func NewEnv() *env {
 		mongoDbRetryWrites: getEnvBool(MONGODB_RETRY_WRITES, true),
 		mongoDbRetryReads:  getEnvBool(MONGODB_RETRY_READS, true),
 		postgresURI:        getEnv(POSTGRES_URI),
+		maxCommentDepth:    getEnvInt(MAX_COMMENT_DEPTH, 5),
+		banExpiryInterval:  time.Duration(getEnvInt(BAN_EXPIRY_INTERVAL, 60)) * time.Second,
+		authIssuer:         getEnvWithDefault(AUTH_ISSUER, "social-app"),
+		authAudience:       getEnvWithDefault(AUTH_AUDIENCE, "social-app"),
+		accessTokenTTL:     time.Duration(getEnvInt(ACCESS_TOKEN_TTL, 15)) * time.Minute,
+		refreshTokenTTL:    time.Duration(getEnvInt(REFRESH_TOKEN_TTL, 30)) * 24 * time.Hour,
+
+		authSigningKeyFile:       getEnv(AUTH_SIGNING_KEY_FILE),
+		authVerificationKeyFiles: getEnvList(AUTH_VERIFICATION_KEY_FILES),
+
+		appURL:               strings.TrimRight(getEnvWithDefault(APP_URL, "http://localhost:"+DEFAULT_PORT), "/"),
+		emailVerificationTTL: time.Duration(getEnvInt(EMAIL_VERIFICATION_TTL, 48)) * time.Hour,
+		passwordResetTTL:     time.Duration(getEnvInt(PASSWORD_RESET_TTL, 60)) * time.Minute,
+		mailFrom:             getEnvWithDefault(MAIL_FROM, "no-reply@localhost"),
+		mailDropDir:          getEnvWithDefault(MAIL_DROP_DIR, "tmp/mail"),
+		smtpHost:             getEnv(SMTP_HOST),
+		smtpPort:             getEnvInt(SMTP_PORT, 587),
+		smtpUsername:         getEnv(SMTP_USERNAME),
+		smtpPassword:         getEnv(SMTP_PASSWORD),
+
+		mfaIssuer:                     getEnvWithDefault(MFA_ISSUER, "Social App"),
+		mfaLoginTTL:                   time.Duration(getEnvInt(MFA_LOGIN_TTL, 5)) * time.Minute,
+		mfaRequiredForPrivilegedRoles: getEnvBool(MFA_REQUIRED_FOR_PRIVILEGED_ROLES, false),
+
+		rbacPolicySource:         PolicySource(getEnvWithDefault(RBAC_POLICY_SOURCE, string(BuiltinPolicy))),
+		rbacPolicyFile:           getEnvWithDefault(RBAC_POLICY_FILE, "rbac-policy.yaml"),
+		rbacPolicyReloadInterval: time.Duration(getEnvInt(RBAC_POLICY_RELOAD_INTERVAL, 30)) * time.Second,
+
+		accountDeletionGracePeriod: time.Duration(getEnvInt(ACCOUNT_DELETION_GRACE_PERIOD, 30)) * 24 * time.Hour,
+		accountDeletionInterval:    time.Duration(getEnvInt(ACCOUNT_DELETION_INTERVAL, 3600)) * time.Second,
+
+		storageEngine:    StorageEngine(getEnvWithDefault(STORAGE_ENGINE, string(postgreSQL))),
+		migrateOnStartup: getEnvBool(MIGRATE_ON_STARTUP, true),
 	}
 }
 
+// AuthSecret signs access tokens with HS256 when no signing key file is set
 func (e *env) AuthSecret() string {
 	return e.authSecret
 }
@@ -135,6 +244,143 @@ func (e *env) MongoDbRetryWrites() bool {
 	return e.mongoDbRetryWrites
 }
 
+// MaxCommentDepth is how deep replies to comments can be nested
+func (e *env) MaxCommentDepth() int {
+	return e.maxCommentDepth
+}
+
+// BanExpiryInterval is how often expired bans are looked for and lifted
+func (e *env) BanExpiryInterval() time.Duration {
+	return e.banExpiryInterval
+}
+
+// AuthIssuer is the iss claim of the access tokens the app issues and accepts
+func (e *env) AuthIssuer() string {
+	return e.authIssuer
+}
+
+// AuthAudience is the aud claim of the access tokens the app issues and accepts
+func (e *env) AuthAudience() string {
+	return e.authAudience
+}
+
+// AccessTokenTTL is how long an access token is valid after it is issued
+func (e *env) AccessTokenTTL() time.Duration {
+	return e.accessTokenTTL
+}
+
+// RefreshTokenTTL is how long a refresh token can be used after it is issued
+func (e *env) RefreshTokenTTL() time.Duration {
+	return e.refreshTokenTTL
+}
+
+// AuthSigningKeyFile is the PEM encoded RSA or Ed25519 private key access tokens are signed with
+func (e *env) AuthSigningKeyFile() string {
+	return e.authSigningKeyFile
+}
+
+// AuthVerificationKeyFiles are PEM encoded public keys of previous signing keys, which tokens are
+// still verified with while keys are rotated
+func (e *env) AuthVerificationKeyFiles() []string {
+	return e.authVerificationKeyFiles
+}
+
+// AppURL is where the links in the emails the app sends point to
+func (e *env) AppURL() string {
+	return e.appURL
+}
+
+// EmailVerificationTTL is how long an email verification link can be used
+func (e *env) EmailVerificationTTL() time.Duration {
+	return e.emailVerificationTTL
+}
+
+// PasswordResetTTL is how long a password reset link can be used
+func (e *env) PasswordResetTTL() time.Duration {
+	return e.passwordResetTTL
+}
+
+// MailFrom is the sender of the emails the app sends
+func (e *env) MailFrom() string {
+	return e.mailFrom
+}
+
+// MailDropDir is where emails are written to when no SMTP host is set
+func (e *env) MailDropDir() string {
+	return e.mailDropDir
+}
+
+// SMTPHost is the mail server emails are sent through. Without it, emails go to MailDropDir.
+func (e *env) SMTPHost() string {
+	return e.smtpHost
+}
+
+func (e *env) SMTPPort() int {
+	return e.smtpPort
+}
+
+func (e *env) SMTPUsername() string {
+	return e.smtpUsername
+}
+
+func (e *env) SMTPPassword() string {
+	return e.smtpPassword
+}
+
+// MFAIssuer names the app in authenticator apps
+func (e *env) MFAIssuer() string {
+	return e.mfaIssuer
+}
+
+// MFALoginTTL is how long a user who logged in with a password has to enter their one-time code
+func (e *env) MFALoginTTL() time.Duration {
+	return e.mfaLoginTTL
+}
+
+// MFARequiredForPrivilegedRoles refuses admins and moderators the permissions regular users lack
+// until they log in with a second factor
+func (e *env) MFARequiredForPrivilegedRoles() bool {
+	return e.mfaRequiredForPrivilegedRoles
+}
+
+// RBACPolicySource is where the role-based access policy is loaded from: builtin, file or database
+func (e *env) RBACPolicySource() PolicySource {
+	return e.rbacPolicySource
+}
+
+// RBACPolicyFile is the YAML or JSON file the policy is loaded from when its source is file
+func (e *env) RBACPolicyFile() string {
+	return e.rbacPolicyFile
+}
+
+// RBACPolicyReloadInterval is how often the policy is reloaded from its file or table
+func (e *env) RBACPolicyReloadInterval() time.Duration {
+	return e.rbacPolicyReloadInterval
+}
+
+// AccountDeletionGracePeriod is how long, in days, users can change their mind after asking for
+// their account to be deleted
+func (e *env) AccountDeletionGracePeriod() time.Duration {
+	return e.accountDeletionGracePeriod
+}
+
+// StorageEngine is where the app keeps its data: postgresql, mongodb or inmemory. The in-memory
+// engine loses everything when the server stops; it is meant for development and CI.
+func (e *env) StorageEngine() StorageEngine {
+	return e.storageEngine
+}
+
+// MigrateOnStartup applies the pending schema migrations when the server starts. Deployments that
+// migrate with the migrate command before rolling out can turn it off.
+func (e *env) MigrateOnStartup() bool {
+	return e.migrateOnStartup
+}
+
+// AccountDeletionInterval is how often accounts whose grace period is over are looked for and erased
+func (e *env) AccountDeletionInterval() time.Duration {
+	return e.accountDeletionInterval
+}
+
 func getEnv(key ENV_VARIABLE) string {
 	return os.Getenv(strings.TrimSpace(string(key)))
 }
@@ -155,6 +401,17 @@ func mustGetEnv(key ENV_VARIABLE) string {
 	return value
 }
 
+// getEnvList splits a comma separated variable, skipping empty items
+func getEnvList(key ENV_VARIABLE) []string {
+	values := []string{}
+	for _, value := range strings.Split(getEnv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getEnvInt(key ENV_VARIABLE, defaultValue int) int {
 	value := getEnv(key)
 	if value == "" {
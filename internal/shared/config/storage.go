@@ -11,16 +11,18 @@ type StorageEngine string
 var (
 	mongodb    StorageEngine = "mongodb"
 	postgreSQL StorageEngine = "postgresql"
-	// inMemory   StorageEngine = "inmemory" // Not fully implemented yet
+	inMemory   StorageEngine = "inmemory"
 	// Add more storage engines here
 )
 
 var StorageEngines = struct {
 	Mongodb    StorageEngine
 	PostgreSQL StorageEngine
+	InMemory   StorageEngine
 }{
 	Mongodb:    mongodb,
 	PostgreSQL: postgreSQL,
+	InMemory:   inMemory,
 }
 
 var (
@@ -59,12 +61,14 @@ func (p *PostgresConfig) Engine() StorageEngine {
 	return postgreSQL
 }
 
+// InMemoryConfig keeps everything in the memory of the server, which needs no database but loses
+// the data when it stops
 type InMemoryConfig struct {
 }
 
-// func (i *InMemoryConfig) Engine() StorageEngine {
-// 	return inMemory
-// }
+func (i *InMemoryConfig) Engine() StorageEngine {
+	return inMemory
+}
 
 func NewStorageEngineConfig(engine StorageEngine) (StorageEngineConfig, error) {
 	config := NewEnv()
@@ -98,7 +102,9 @@ func NewStorageEngineConfig(engine StorageEngine) (StorageEngineConfig, error) {
 			ConnIdleTime: config.ConnIdleTime(),
 			Timeout:      config.Timeout(),
 		}, nil
+	case inMemory:
+		return &InMemoryConfig{}, nil
 	default:
-		return nil, ErrUnsupportedEngine
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedEngine, engine)
 	}
 }
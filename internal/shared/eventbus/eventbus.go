@@ -0,0 +1,149 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+
+	"github.com/iammrsea/social-app/internal/shared/events"
+)
+
+// Handler reacts to a published event
+type Handler func(ctx context.Context, event events.Event) error
+
+type DeliveryMode int
+
+const (
+	// Sync delivers the event before Publish returns. Errors of sync subscribers are returned by Publish.
+	Sync DeliveryMode = iota
+	// Async delivers the event in its own goroutine. Errors of async subscribers are only logged.
+	Async
+)
+
+// Publisher is what command handlers depend on to publish the events raised by their aggregates
+type Publisher interface {
+	Publish(ctx context.Context, evts ...events.Event) error
+}
+
+type Subscriber interface {
+	Subscribe(eventName string, handler Handler, opts ...SubscribeOption)
+}
+
+type Bus interface {
+	Publisher
+	Subscriber
+}
+
+type subscription struct {
+	handler Handler
+	mode    DeliveryMode
+}
+
+type SubscribeOption func(*subscription)
+
+// WithDelivery overrides the default delivery mode of the bus for one subscriber
+func WithDelivery(mode DeliveryMode) SubscribeOption {
+	return func(s *subscription) {
+		s.mode = mode
+	}
+}
+
+type Option func(*InProcessBus)
+
+// WithDefaultDelivery sets the delivery mode of subscribers that don't choose one. Defaults to Sync.
+func WithDefaultDelivery(mode DeliveryMode) Option {
+	return func(b *InProcessBus) {
+		b.defaultMode = mode
+	}
+}
+
+// InProcessBus delivers events to subscribers running in the same process. A subscriber that
+// fails or panics never prevents the other subscribers from receiving the event.
+type InProcessBus struct {
+	mu            sync.RWMutex
+	subscriptions map[string][]subscription
+	defaultMode   DeliveryMode
+	inFlight      sync.WaitGroup
+}
+
+func New(opts ...Option) *InProcessBus {
+	bus := &InProcessBus{subscriptions: map[string][]subscription{}, defaultMode: Sync}
+	for _, opt := range opts {
+		opt(bus)
+	}
+	return bus
+}
+
+func (b *InProcessBus) Subscribe(eventName string, handler Handler, opts ...SubscribeOption) {
+	if handler == nil {
+		panic("nil event handler")
+	}
+	sub := subscription{handler: handler, mode: b.defaultMode}
+	for _, opt := range opts {
+		opt(&sub)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions[eventName] = append(b.subscriptions[eventName], sub)
+}
+
+// SubscribeTo registers a handler for a single event type
+func SubscribeTo[E events.Event](s Subscriber, handler func(ctx context.Context, event E) error, opts ...SubscribeOption) {
+	var zero E
+	s.Subscribe(zero.EventName(), func(ctx context.Context, event events.Event) error {
+		typed, ok := event.(E)
+		if !ok {
+			return fmt.Errorf("eventbus: unexpected event type %T for %s", event, zero.EventName())
+		}
+		return handler(ctx, typed)
+	}, opts...)
+}
+
+// Publish delivers each event to its subscribers in the order they subscribed
+func (b *InProcessBus) Publish(ctx context.Context, evts ...events.Event) error {
+	var errs []error
+	for _, event := range evts {
+		b.mu.RLock()
+		subs := b.subscriptions[event.EventName()]
+		b.mu.RUnlock()
+
+		for _, sub := range subs {
+			if sub.mode == Async {
+				b.inFlight.Add(1)
+				go func(sub subscription, event events.Event) {
+					defer b.inFlight.Done()
+					// The request that published the event may be over before the subscriber runs
+					if err := deliver(context.WithoutCancel(ctx), sub, event); err != nil {
+						log.Printf("eventbus: %v", err)
+					}
+				}(sub, event)
+				continue
+			}
+			if err := deliver(ctx, sub, event); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Wait blocks until every async delivery has finished
+func (b *InProcessBus) Wait() {
+	b.inFlight.Wait()
+}
+
+// deliver calls a subscriber, turning a panic into an error
+func deliver(ctx context.Context, sub subscription, event events.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("subscriber of %s panicked: %v\n%s", event.EventName(), r, debug.Stack())
+		}
+	}()
+	if err := sub.handler(ctx, event); err != nil {
+		return fmt.Errorf("subscriber of %s failed: %w", event.EventName(), err)
+	}
+	return nil
+}
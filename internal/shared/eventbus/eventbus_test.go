@@ -0,0 +1,127 @@
+package eventbus_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type somethingHappened struct {
+	Value string
+}
+
+func (e somethingHappened) EventName() string     { return "test.something_happened" }
+func (e somethingHappened) OccurredAt() time.Time { return time.Time{} }
+
+type somethingElseHappened struct{}
+
+func (e somethingElseHappened) EventName() string     { return "test.something_else_happened" }
+func (e somethingElseHappened) OccurredAt() time.Time { return time.Time{} }
+
+func TestPublish(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should deliver events to subscribers of their type only", func(t *testing.T) {
+		t.Parallel()
+		bus := eventbus.New()
+		var received []string
+		eventbus.SubscribeTo(bus, func(ctx context.Context, event somethingHappened) error {
+			received = append(received, event.Value)
+			return nil
+		})
+		eventbus.SubscribeTo(bus, func(ctx context.Context, event somethingElseHappened) error {
+			received = append(received, "else")
+			return nil
+		})
+
+		err := bus.Publish(context.Background(), somethingHappened{Value: "first"}, somethingHappened{Value: "second"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"first", "second"}, received)
+	})
+
+	t.Run("should succeed when an event has no subscribers", func(t *testing.T) {
+		t.Parallel()
+		bus := eventbus.New()
+		assert.Nil(t, bus.Publish(context.Background(), somethingHappened{}))
+	})
+
+	t.Run("should return errors of sync subscribers and keep delivering", func(t *testing.T) {
+		t.Parallel()
+		bus := eventbus.New()
+		failure := errors.New("failure")
+		delivered := false
+		bus.Subscribe("test.something_happened", func(ctx context.Context, event events.Event) error {
+			return failure
+		})
+		bus.Subscribe("test.something_happened", func(ctx context.Context, event events.Event) error {
+			delivered = true
+			return nil
+		})
+
+		err := bus.Publish(context.Background(), somethingHappened{})
+		assert.ErrorIs(t, err, failure)
+		assert.True(t, delivered)
+	})
+
+	t.Run("should isolate panicking subscribers", func(t *testing.T) {
+		t.Parallel()
+		bus := eventbus.New()
+		delivered := false
+		bus.Subscribe("test.something_happened", func(ctx context.Context, event events.Event) error {
+			panic("boom")
+		})
+		bus.Subscribe("test.something_happened", func(ctx context.Context, event events.Event) error {
+			delivered = true
+			return nil
+		})
+
+		err := bus.Publish(context.Background(), somethingHappened{})
+		assert.ErrorContains(t, err, "panicked: boom")
+		assert.True(t, delivered)
+	})
+
+	t.Run("should deliver async events in the background", func(t *testing.T) {
+		t.Parallel()
+		bus := eventbus.New(eventbus.WithDefaultDelivery(eventbus.Async))
+		var delivered atomic.Int32
+		release := make(chan struct{})
+		eventbus.SubscribeTo(bus, func(ctx context.Context, event somethingHappened) error {
+			<-release
+			delivered.Add(1)
+			return nil
+		})
+		eventbus.SubscribeTo(bus, func(ctx context.Context, event somethingHappened) error {
+			panic("boom")
+		})
+
+		// Async subscribers don't block the publisher, and their failures don't reach it
+		ctx, cancel := context.WithCancel(context.Background())
+		err := bus.Publish(ctx, somethingHappened{})
+		cancel()
+		assert.Nil(t, err)
+		assert.Equal(t, int32(0), delivered.Load())
+
+		close(release)
+		bus.Wait()
+		assert.Equal(t, int32(1), delivered.Load())
+	})
+
+	t.Run("should let subscribers override the default delivery", func(t *testing.T) {
+		t.Parallel()
+		bus := eventbus.New(eventbus.WithDefaultDelivery(eventbus.Async))
+		delivered := false
+		eventbus.SubscribeTo(bus, func(ctx context.Context, event somethingHappened) error {
+			delivered = true
+			return nil
+		}, eventbus.WithDelivery(eventbus.Sync))
+
+		assert.Nil(t, bus.Publish(context.Background(), somethingHappened{}))
+		assert.True(t, delivered)
+	})
+}
@@ -0,0 +1,31 @@
+package events
+
+import "time"
+
+// Event is something that happened in the domain. Subscribers are matched to events by EventName,
+// so every event type must return a constant name.
+type Event interface {
+	EventName() string
+	OccurredAt() time.Time
+}
+
+// Recorder collects the events raised by an aggregate until they are published
+type Recorder struct {
+	events []Event
+}
+
+func (r *Recorder) Record(event Event) {
+	r.events = append(r.events, event)
+}
+
+// Events returns the recorded events without clearing them
+func (r *Recorder) Events() []Event {
+	return r.events
+}
+
+// PullEvents returns the recorded events and clears the recorder
+func (r *Recorder) PullEvents() []Event {
+	events := r.events
+	r.events = nil
+	return events
+}
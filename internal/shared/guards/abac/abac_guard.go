@@ -1,17 +1,82 @@
 package abac
 
 import (
+	"context"
+	"time"
+
 	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
 )
 
 //ABAC => Attribute-Based Access Control
 
 type Guard interface {
-	CanChangeUsername(userId string, authUser *auth.AuthenticatedUser) error
+	// Can tells whether the user of ctx may take action on resource. It returns rbac.ErrUnauthorized
+	// when no rule allows it.
+	Can(ctx context.Context, action Action, resource Resource) error
+}
+
+// AttributeBasedGuard evaluates rules against the attributes of the subject, the resource and the
+// environment. Access is denied unless a rule for the kind of resource and the action allows it.
+type AttributeBasedGuard struct {
+	roles rbac.Guard
+	rules []Rule
+	now   func() time.Time
+}
+
+type Option func(*AttributeBasedGuard)
+
+// WithRules replaces the default rules
+func WithRules(rules ...Rule) Option {
+	return func(g *AttributeBasedGuard) {
+		g.rules = rules
+	}
 }
 
-type AttributeBasedGuard struct{}
+// WithClock sets the time rules are evaluated at, for tests
+func WithClock(now func() time.Time) Option {
+	return func(g *AttributeBasedGuard) {
+		g.now = now
+	}
+}
+
+// New returns a guard enforcing DefaultRules. Rules check permissions against roles.
+func New(roles rbac.Guard, opts ...Option) *AttributeBasedGuard {
+	if roles == nil {
+		panic("nil role guard")
+	}
+	g := &AttributeBasedGuard{roles: roles, rules: DefaultRules(), now: time.Now}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// DefaultRules are the ownership rules of users, posts and comments
+func DefaultRules() []Rule {
+	var rules []Rule
+	rules = append(rules, userRules...)
+	rules = append(rules, postRules...)
+	rules = append(rules, commentRules...)
+	return rules
+}
 
-func New() *AttributeBasedGuard {
-	return &AttributeBasedGuard{}
+func (g *AttributeBasedGuard) Can(ctx context.Context, action Action, resource Resource) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if authUser == nil {
+		return rbac.ErrUnauthorized
+	}
+	req := Request{
+		Subject:     authUser,
+		Action:      action,
+		Resource:    resource,
+		Environment: Environment{Now: g.now()},
+		Roles:       g.roles,
+	}
+	for _, rule := range g.rules {
+		if rule.Kind == resource.Kind && rule.Action == action && rule.When(req) {
+			return nil
+		}
+	}
+	return rbac.ErrUnauthorized
 }
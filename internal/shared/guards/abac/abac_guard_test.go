@@ -1,12 +1,15 @@
 package abac_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/iammrsea/social-app/internal/shared/auth"
 	"github.com/iammrsea/social-app/internal/shared/guards/abac"
 	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type testCase struct {
@@ -19,6 +22,10 @@ type input struct {
 	authUser *auth.AuthenticatedUser
 }
 
+func (tc testCase) ctx() context.Context {
+	return auth.NewContextWithUser(context.Background(), tc.input.authUser)
+}
+
 func TestGuard_CanChangeUsername(t *testing.T) {
 	testCases := []testCase{
 		{
@@ -28,7 +35,7 @@ func TestGuard_CanChangeUsername(t *testing.T) {
 				authUser: &auth.AuthenticatedUser{
 					Id:    "user1",
 					Email: "user1@example.com",
-					Role:  rbac.Regular,
+					Roles: []rbac.UserRole{rbac.Regular},
 				},
 			},
 			expectedErr: nil,
@@ -40,7 +47,7 @@ func TestGuard_CanChangeUsername(t *testing.T) {
 				authUser: &auth.AuthenticatedUser{
 					Id:    "user1",
 					Email: "user1@example.com",
-					Role:  rbac.Moderator,
+					Roles: []rbac.UserRole{rbac.Moderator},
 				},
 			},
 			expectedErr: nil,
@@ -52,7 +59,7 @@ func TestGuard_CanChangeUsername(t *testing.T) {
 				authUser: &auth.AuthenticatedUser{
 					Id:    "user1",
 					Email: "user1@example.com",
-					Role:  rbac.Admin,
+					Roles: []rbac.UserRole{rbac.Admin},
 				},
 			},
 			expectedErr: nil,
@@ -64,7 +71,7 @@ func TestGuard_CanChangeUsername(t *testing.T) {
 				authUser: &auth.AuthenticatedUser{
 					Id:    "user1",
 					Email: "user1@example.com",
-					Role:  rbac.Regular,
+					Roles: []rbac.UserRole{rbac.Regular},
 				},
 			},
 			expectedErr: rbac.ErrUnauthorized,
@@ -76,7 +83,7 @@ func TestGuard_CanChangeUsername(t *testing.T) {
 				authUser: &auth.AuthenticatedUser{
 					Id:    "user1",
 					Email: "user1@example.com",
-					Role:  rbac.Moderator,
+					Roles: []rbac.UserRole{rbac.Moderator},
 				},
 			},
 			expectedErr: rbac.ErrUnauthorized,
@@ -88,15 +95,435 @@ func TestGuard_CanChangeUsername(t *testing.T) {
 				authUser: &auth.AuthenticatedUser{
 					Id:    "",
 					Email: "",
-					Role:  rbac.Guest,
+					Roles: []rbac.UserRole{rbac.Guest},
+				},
+			},
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := abac.New(rbac.New()).Can(tc.ctx(), abac.Edit, abac.User(tc.input.userId))
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestGuard_CanEditPost(t *testing.T) {
+	testCases := []testCase{
+		{
+			name: "author with regular role can edit their post",
+			input: input{
+				userId: "user1",
+				authUser: &auth.AuthenticatedUser{
+					Id:    "user1",
+					Email: "user1@example.com",
+					Roles: []rbac.UserRole{rbac.Regular},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "user with admin role can edit another user's post",
+			input: input{
+				userId: "user2",
+				authUser: &auth.AuthenticatedUser{
+					Id:    "user1",
+					Email: "user1@example.com",
+					Roles: []rbac.UserRole{rbac.Admin},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "user with moderator role cannot edit another user's post",
+			input: input{
+				userId: "user2",
+				authUser: &auth.AuthenticatedUser{
+					Id:    "user1",
+					Email: "user1@example.com",
+					Roles: []rbac.UserRole{rbac.Moderator},
+				},
+			},
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name: "user with regular role cannot edit another user's post",
+			input: input{
+				userId: "user2",
+				authUser: &auth.AuthenticatedUser{
+					Id:    "user1",
+					Email: "user1@example.com",
+					Roles: []rbac.UserRole{rbac.Regular},
+				},
+			},
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name: "user with guest role cannot edit a post",
+			input: input{
+				userId: "user1",
+				authUser: &auth.AuthenticatedUser{
+					Roles: []rbac.UserRole{rbac.Guest},
+				},
+			},
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := abac.New(rbac.New()).Can(tc.ctx(), abac.Edit, abac.Post("post1", tc.input.userId, time.Now()))
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestGuard_CanDeletePost(t *testing.T) {
+	testCases := []testCase{
+		{
+			name: "author with regular role can delete their post",
+			input: input{
+				userId: "user1",
+				authUser: &auth.AuthenticatedUser{
+					Id:    "user1",
+					Email: "user1@example.com",
+					Roles: []rbac.UserRole{rbac.Regular},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "user with moderator role can delete another user's post",
+			input: input{
+				userId: "user2",
+				authUser: &auth.AuthenticatedUser{
+					Id:    "user1",
+					Email: "user1@example.com",
+					Roles: []rbac.UserRole{rbac.Moderator},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "user with regular role cannot delete another user's post",
+			input: input{
+				userId: "user2",
+				authUser: &auth.AuthenticatedUser{
+					Id:    "user1",
+					Email: "user1@example.com",
+					Roles: []rbac.UserRole{rbac.Regular},
+				},
+			},
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name: "user with guest role cannot delete a post",
+			input: input{
+				userId: "user1",
+				authUser: &auth.AuthenticatedUser{
+					Roles: []rbac.UserRole{rbac.Guest},
+				},
+			},
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := abac.New(rbac.New()).Can(tc.ctx(), abac.Delete, abac.Post("post1", tc.input.userId, time.Now()))
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestGuard_CanEditComment(t *testing.T) {
+	testCases := []testCase{
+		{
+			name: "author with regular role can edit their comment",
+			input: input{
+				userId: "user1",
+				authUser: &auth.AuthenticatedUser{
+					Id:    "user1",
+					Email: "user1@example.com",
+					Roles: []rbac.UserRole{rbac.Regular},
 				},
 			},
+			expectedErr: nil,
+		},
+		{
+			name: "user with admin role can edit another user's comment",
+			input: input{
+				userId: "user2",
+				authUser: &auth.AuthenticatedUser{
+					Id:    "user1",
+					Email: "user1@example.com",
+					Roles: []rbac.UserRole{rbac.Admin},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "user with moderator role cannot edit another user's comment",
+			input: input{
+				userId: "user2",
+				authUser: &auth.AuthenticatedUser{
+					Id:    "user1",
+					Email: "user1@example.com",
+					Roles: []rbac.UserRole{rbac.Moderator},
+				},
+			},
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name: "user with regular role cannot edit another user's comment",
+			input: input{
+				userId: "user2",
+				authUser: &auth.AuthenticatedUser{
+					Id:    "user1",
+					Email: "user1@example.com",
+					Roles: []rbac.UserRole{rbac.Regular},
+				},
+			},
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name: "user with guest role cannot edit a comment",
+			input: input{
+				userId: "user1",
+				authUser: &auth.AuthenticatedUser{
+					Roles: []rbac.UserRole{rbac.Guest},
+				},
+			},
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := abac.New(rbac.New()).Can(tc.ctx(), abac.Edit, abac.Comment("comment1", tc.input.userId, time.Now()))
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestGuard_CanDeleteComment(t *testing.T) {
+	testCases := []testCase{
+		{
+			name: "author with regular role can delete their comment",
+			input: input{
+				userId: "user1",
+				authUser: &auth.AuthenticatedUser{
+					Id:    "user1",
+					Email: "user1@example.com",
+					Roles: []rbac.UserRole{rbac.Regular},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "user with moderator role can delete another user's comment",
+			input: input{
+				userId: "user2",
+				authUser: &auth.AuthenticatedUser{
+					Id:    "user1",
+					Email: "user1@example.com",
+					Roles: []rbac.UserRole{rbac.Moderator},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "user with regular role cannot delete another user's comment",
+			input: input{
+				userId: "user2",
+				authUser: &auth.AuthenticatedUser{
+					Id:    "user1",
+					Email: "user1@example.com",
+					Roles: []rbac.UserRole{rbac.Regular},
+				},
+			},
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name: "user with guest role cannot delete a comment",
+			input: input{
+				userId: "user1",
+				authUser: &auth.AuthenticatedUser{
+					Roles: []rbac.UserRole{rbac.Guest},
+				},
+			},
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := abac.New(rbac.New()).Can(tc.ctx(), abac.Delete, abac.Comment("comment1", tc.input.userId, time.Now()))
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestGuard_Rules(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	guard := abac.New(rbac.New(),
+		abac.WithClock(func() time.Time { return now }),
+		abac.WithRules(
+			abac.Allow(abac.PostKind, abac.Edit, abac.Any(
+				abac.HasRole(rbac.Admin),
+				abac.All(abac.IsOwner(), abac.CreatedWithin(24*time.Hour), abac.Not(abac.AttributeIs("locked", true))),
+			)),
+		),
+	)
+	author := &auth.AuthenticatedUser{Id: "user1", Roles: []rbac.UserRole{rbac.Regular}}
+	admin := &auth.AuthenticatedUser{Id: "admin1", Roles: []rbac.UserRole{rbac.Admin}}
+	locked := func(resource abac.Resource) abac.Resource {
+		resource.Attributes = map[string]any{"locked": true}
+		return resource
+	}
+
+	testCases := []struct {
+		name        string
+		authUser    *auth.AuthenticatedUser
+		action      abac.Action
+		resource    abac.Resource
+		expectedErr error
+	}{
+		{
+			name:     "owner can edit a recent post",
+			authUser: author,
+			action:   abac.Edit,
+			resource: abac.Post("post1", "user1", now.Add(-time.Hour)),
+		},
+		{
+			name:        "owner cannot edit a post past the time limit",
+			authUser:    author,
+			action:      abac.Edit,
+			resource:    abac.Post("post1", "user1", now.Add(-25*time.Hour)),
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name:        "owner cannot edit a locked post",
+			authUser:    author,
+			action:      abac.Edit,
+			resource:    locked(abac.Post("post1", "user1", now.Add(-time.Hour))),
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name:     "admin can edit an old locked post",
+			authUser: admin,
+			action:   abac.Edit,
+			resource: locked(abac.Post("post1", "user1", now.Add(-48*time.Hour))),
+		},
+		{
+			name:        "actions without a rule are denied",
+			authUser:    admin,
+			action:      abac.Delete,
+			resource:    abac.Post("post1", "user1", now),
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name:        "kinds of resources without a rule are denied",
+			authUser:    admin,
+			action:      abac.Edit,
+			resource:    abac.Comment("comment1", "user1", now),
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := guard.Can(auth.NewContextWithUser(context.Background(), tc.authUser), tc.action, tc.resource)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+
+	t.Run("requests without a user are denied", func(t *testing.T) {
+		t.Parallel()
+		err := abac.New(rbac.New()).Can(context.Background(), abac.Edit, abac.User(""))
+		assert.ErrorIs(t, err, rbac.ErrUnauthorized)
+	})
+}
+
+func TestGuard_FollowsRolePolicy(t *testing.T) {
+	t.Parallel()
+	roleGuard := rbac.New(rbac.RequireMFA(rbac.Admin, rbac.Moderator))
+	conf := rbac.DefaultPolicyConfig()
+	conf.Roles["EDITOR"] = rbac.RoleConfig{Inherits: []rbac.UserRole{rbac.Regular}}
+	require.NoError(t, roleGuard.Load(conf))
+	guard := abac.New(roleGuard)
+
+	testCases := []struct {
+		name        string
+		authUser    *auth.AuthenticatedUser
+		action      abac.Action
+		authorId    string
+		expectedErr error
+	}{
+		{
+			name:        "admin without two-factor authentication cannot edit another user's post",
+			authUser:    &auth.AuthenticatedUser{Id: "admin1", Roles: []rbac.UserRole{rbac.Admin}},
+			action:      abac.Edit,
+			authorId:    "user1",
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name:        "admin with two-factor authentication can edit another user's post",
+			authUser:    &auth.AuthenticatedUser{Id: "admin1", Roles: []rbac.UserRole{rbac.Admin}, MFA: true},
+			action:      abac.Edit,
+			authorId:    "user1",
+			expectedErr: nil,
+		},
+		{
+			name:        "moderator without two-factor authentication cannot delete another user's post",
+			authUser:    &auth.AuthenticatedUser{Id: "moderator1", Roles: []rbac.UserRole{rbac.Moderator}},
+			action:      abac.Delete,
+			authorId:    "user1",
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name:        "moderator with two-factor authentication can delete another user's post",
+			authUser:    &auth.AuthenticatedUser{Id: "moderator1", Roles: []rbac.UserRole{rbac.Moderator}, MFA: true},
+			action:      abac.Delete,
+			authorId:    "user1",
+			expectedErr: nil,
+		},
+		{
+			name:        "moderator without two-factor authentication can still delete their own post",
+			authUser:    &auth.AuthenticatedUser{Id: "moderator1", Roles: []rbac.UserRole{rbac.Moderator}},
+			action:      abac.Delete,
+			authorId:    "moderator1",
+			expectedErr: nil,
+		},
+		{
+			name:        "role inheriting regular can edit its own post",
+			authUser:    &auth.AuthenticatedUser{Id: "editor1", Roles: []rbac.UserRole{"EDITOR"}},
+			action:      abac.Edit,
+			authorId:    "editor1",
+			expectedErr: nil,
+		},
+		{
+			name:        "role inheriting regular cannot edit another user's post",
+			authUser:    &auth.AuthenticatedUser{Id: "editor1", Roles: []rbac.UserRole{"EDITOR"}},
+			action:      abac.Edit,
+			authorId:    "user1",
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name: "api key without the update:post scope cannot edit the owner's post",
+			authUser: &auth.AuthenticatedUser{
+				Id:     "user1",
+				Roles:  []rbac.UserRole{rbac.Regular},
+				Scopes: []rbac.Permission{rbac.ViewPost},
+			},
+			action:      abac.Edit,
+			authorId:    "user1",
 			expectedErr: rbac.ErrUnauthorized,
 		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := abac.New().CanChangeUsername(tc.input.userId, tc.input.authUser)
+			t.Parallel()
+			ctx := auth.NewContextWithUser(context.Background(), tc.authUser)
+			err := guard.Can(ctx, tc.action, abac.Post("post1", tc.authorId, time.Now()))
 			assert.ErrorIs(t, err, tc.expectedErr)
 		})
 	}
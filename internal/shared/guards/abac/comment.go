@@ -0,0 +1,20 @@
+package abac
+
+import (
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+// Authors can edit and delete their comments. Editing or deleting the comments of others takes the
+// "any" permissions, which admins have, and moderators for deleting.
+var commentRules = []Rule{
+	Allow(CommentKind, Edit, HasPermission(rbac.UpdateAnyComment)),
+	Allow(CommentKind, Edit, HasPermission(rbac.UpdateComment), IsOwner()),
+	Allow(CommentKind, Delete, HasPermission(rbac.DeleteAnyComment)),
+	Allow(CommentKind, Delete, HasPermission(rbac.DeleteComment), IsOwner()),
+}
+
+func Comment(id, authorId string, createdAt time.Time) Resource {
+	return Resource{Kind: CommentKind, Id: id, OwnerId: authorId, CreatedAt: createdAt}
+}
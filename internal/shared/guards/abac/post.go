@@ -0,0 +1,20 @@
+package abac
+
+import (
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+// Authors can edit and delete their posts. Editing or deleting the posts of others takes the "any"
+// permissions, which admins have, and moderators for deleting.
+var postRules = []Rule{
+	Allow(PostKind, Edit, HasPermission(rbac.UpdateAnyPost)),
+	Allow(PostKind, Edit, HasPermission(rbac.UpdatePost), IsOwner()),
+	Allow(PostKind, Delete, HasPermission(rbac.DeleteAnyPost)),
+	Allow(PostKind, Delete, HasPermission(rbac.DeletePost), IsOwner()),
+}
+
+func Post(id, authorId string, createdAt time.Time) Resource {
+	return Resource{Kind: PostKind, Id: id, OwnerId: authorId, CreatedAt: createdAt}
+}
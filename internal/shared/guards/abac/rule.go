@@ -0,0 +1,132 @@
+package abac
+
+import (
+	"slices"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+type Action string
+
+const (
+	Edit   Action = "edit"
+	Delete Action = "delete"
+)
+
+// Kind is the type of a resource, such as a post
+type Kind string
+
+const (
+	UserKind    Kind = "user"
+	PostKind    Kind = "post"
+	CommentKind Kind = "comment"
+)
+
+// Resource is what an action is taken on, described by its attributes. Attributes holds those that
+// only some kinds of resources have, such as whether a post is locked.
+type Resource struct {
+	Kind       Kind
+	Id         string
+	OwnerId    string
+	CreatedAt  time.Time
+	Attributes map[string]any
+}
+
+// Environment holds the attributes of the request that belong to neither the subject nor the resource
+type Environment struct {
+	Now time.Time
+}
+
+// Request is what rules are evaluated against. Roles checks the permissions of the subject against
+// the role-based policy in force.
+type Request struct {
+	Subject     *auth.AuthenticatedUser
+	Action      Action
+	Resource    Resource
+	Environment Environment
+	Roles       rbac.Guard
+}
+
+// Predicate is a condition on a request. Predicates compose with All, Any and Not, e.g. the owner
+// can edit a post within 24 hours unless it is locked:
+//
+//	All(IsOwner(), CreatedWithin(24*time.Hour), Not(AttributeIs("locked", true)))
+type Predicate func(req Request) bool
+
+// Rule allows Action on resources of Kind when its condition holds
+type Rule struct {
+	Kind   Kind
+	Action Action
+	When   Predicate
+}
+
+// Allow returns the rule allowing action on resources of kind when all of preds hold
+func Allow(kind Kind, action Action, preds ...Predicate) Rule {
+	return Rule{Kind: kind, Action: action, When: All(preds...)}
+}
+
+// All holds when every one of preds holds
+func All(preds ...Predicate) Predicate {
+	return func(req Request) bool {
+		for _, pred := range preds {
+			if !pred(req) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Any holds when one of preds holds
+func Any(preds ...Predicate) Predicate {
+	return func(req Request) bool {
+		return slices.ContainsFunc(preds, func(pred Predicate) bool {
+			return pred(req)
+		})
+	}
+}
+
+func Not(pred Predicate) Predicate {
+	return func(req Request) bool {
+		return !pred(req)
+	}
+}
+
+// HasRole holds when the subject has one of roles. It only looks at the names of the roles, so
+// prefer HasPermission, which follows inheritance and the MFA and API key checks of the policy.
+func HasRole(roles ...rbac.UserRole) Predicate {
+	return func(req Request) bool {
+		return slices.ContainsFunc(roles, req.Subject.HasRole)
+	}
+}
+
+// HasPermission holds when the role-based policy grants perm to the subject
+func HasPermission(perm rbac.Permission) Predicate {
+	return func(req Request) bool {
+		return req.Roles.Authorize(req.Subject.Subject(), perm) == nil
+	}
+}
+
+// IsOwner holds when the subject owns the resource, such as the author of a post
+func IsOwner() Predicate {
+	return func(req Request) bool {
+		return req.Subject.Id != "" && req.Subject.Id == req.Resource.OwnerId
+	}
+}
+
+// CreatedWithin holds when the resource was created less than d ago
+func CreatedWithin(d time.Duration) Predicate {
+	return func(req Request) bool {
+		return req.Environment.Now.Sub(req.Resource.CreatedAt) < d
+	}
+}
+
+// AttributeIs holds when the resource has the attribute name set to value
+func AttributeIs(name string, value any) Predicate {
+	return func(req Request) bool {
+		v, ok := req.Resource.Attributes[name]
+		return ok && v == value
+	}
+}
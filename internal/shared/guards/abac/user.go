@@ -1,21 +1,16 @@
 package abac
 
 import (
-	"github.com/iammrsea/social-app/internal/shared/auth"
 	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
 )
 
-func (g *AttributeBasedGuard) CanChangeUsername(userId string, authUser *auth.AuthenticatedUser) error {
-	switch authUser.Role {
-	case rbac.Admin:
-		return nil
-	case rbac.Regular, rbac.Moderator:
-		if userId == authUser.Id {
-			return nil
-		} else {
-			return rbac.ErrUnauthorized
-		}
-	default:
-		return rbac.ErrUnauthorized
-	}
+// Users can change their own username; admins can change anyone's
+var userRules = []Rule{
+	Allow(UserKind, Edit, HasPermission(rbac.UpdateAnyUser)),
+	Allow(UserKind, Edit, HasPermission(rbac.UpdateUser), IsOwner()),
+}
+
+// User describes the account of user id, which the user owns
+func User(id string) Resource {
+	return Resource{Kind: UserKind, Id: id, OwnerId: id}
 }
@@ -0,0 +1,45 @@
+package guards
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iammrsea/social-app/internal/shared/audit"
+	"github.com/iammrsea/social-app/internal/shared/guards/abac"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+type auditedGuards struct {
+	Guards
+	log *audit.Log
+}
+
+// WithAudit records every decision of g, allowed or denied, to log
+func WithAudit(g Guards, log *audit.Log) Guards {
+	if g == nil || log == nil {
+		panic("nil guards or audit log")
+	}
+	return &auditedGuards{Guards: g, log: log}
+}
+
+func (a *auditedGuards) Authorize(ctx context.Context, subject rbac.Subject, perm rbac.Permission) error {
+	err := a.Guards.Authorize(ctx, subject, perm)
+	a.record(ctx, "", string(perm), err)
+	return err
+}
+
+func (a *auditedGuards) Can(ctx context.Context, action abac.Action, resource abac.Resource) error {
+	err := a.Guards.Can(ctx, action, resource)
+	// Permissions are named like the role-based ones, e.g. edit:post
+	perm := fmt.Sprintf("%s:%s", action, resource.Kind)
+	a.record(ctx, fmt.Sprintf("%s:%s", resource.Kind, resource.Id), perm, err)
+	return err
+}
+
+func (a *auditedGuards) record(ctx context.Context, target, perm string, err error) {
+	entry := audit.Entry{Kind: audit.Authorization, Target: target, Permission: perm, Outcome: audit.Allowed}
+	if err != nil {
+		entry.Outcome, entry.Reason = audit.Denied, err.Error()
+	}
+	a.log.Record(ctx, entry)
+}
@@ -0,0 +1,43 @@
+package guards_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iammrsea/social-app/internal/shared/audit"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/abac"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAudit(t *testing.T) {
+	t.Parallel()
+	log := audit.NewLog(audit.NewMemoryStore())
+	guard := guards.WithAudit(guards.New(), log)
+	user := &auth.AuthenticatedUser{Id: "user-1", Roles: []rbac.UserRole{rbac.Regular}}
+	ctx := auth.NewContextWithUser(context.Background(), user)
+
+	require.NoError(t, guard.Authorize(ctx, user.Subject(), rbac.CreatePost))
+	require.ErrorIs(t, guard.Authorize(ctx, user.Subject(), rbac.BanUser), rbac.ErrUnauthorized)
+	require.ErrorIs(t, guard.Can(ctx, abac.Edit, abac.User("user-2")), rbac.ErrUnauthorized)
+
+	entries, _, err := log.Search(context.Background(), audit.SearchOptions{First: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	for _, entry := range entries {
+		assert.Equal(t, audit.Authorization, entry.Kind)
+		assert.Equal(t, "user-1", entry.ActorId)
+	}
+	// Newest first
+	assert.Equal(t, "edit:user", entries[0].Permission)
+	assert.Equal(t, "user:user-2", entries[0].Target)
+	assert.Equal(t, audit.Denied, entries[0].Outcome)
+	assert.Equal(t, string(rbac.BanUser), entries[1].Permission)
+	assert.Equal(t, audit.Denied, entries[1].Outcome)
+	assert.Equal(t, rbac.ErrUnauthorized.Error(), entries[1].Reason)
+	assert.Equal(t, string(rbac.CreatePost), entries[2].Permission)
+	assert.Equal(t, audit.Allowed, entries[2].Outcome)
+}
@@ -0,0 +1,180 @@
+package bans
+
+// Keeps banned users from changing anything. Reads are not affected.
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+)
+
+// ErrUserBanned is returned to a banned user who tries to change something. Until is zero when
+// the ban is indefinite.
+type ErrUserBanned struct {
+	Reason string
+	Until  time.Time
+}
+
+func (e *ErrUserBanned) Error() string {
+	if e.Until.IsZero() {
+		return fmt.Sprintf("you are banned: %s", e.Reason)
+	}
+	return fmt.Sprintf("you are banned until %s: %s", e.Until.Format(time.RFC3339), e.Reason)
+}
+
+// Status is the ban recorded on a user. A ban with a zero Until is indefinite.
+type Status struct {
+	Banned bool
+	Reason string
+	From   time.Time
+	Until  time.Time
+}
+
+func (s Status) activeAt(t time.Time) bool {
+	if !s.Banned {
+		return false
+	}
+	if s.Until.IsZero() {
+		return true
+	}
+	return !t.Before(s.From) && t.Before(s.Until)
+}
+
+// Lookup fetches the ban recorded on a user
+type Lookup interface {
+	BanStatus(ctx context.Context, userId string) (Status, error)
+}
+
+type Checker interface {
+	// CheckNotBanned returns an *ErrUserBanned if the user is banned right now
+	CheckNotBanned(ctx context.Context, userId string) error
+}
+
+// CheckerFunc lets a function be used as a Checker
+type CheckerFunc func(ctx context.Context, userId string) error
+
+func (f CheckerFunc) CheckNotBanned(ctx context.Context, userId string) error {
+	return f(ctx, userId)
+}
+
+const (
+	defaultTTL        = time.Minute
+	defaultMaxEntries = 100_000
+)
+
+type CacheOption func(*CachedChecker)
+
+// WithTTL sets how long a looked up ban is trusted. Invalidate makes changes seen sooner.
+func WithTTL(ttl time.Duration) CacheOption {
+	return func(c *CachedChecker) {
+		c.ttl = ttl
+	}
+}
+
+// WithClock replaces time.Now as the source of the current time
+func WithClock(now func() time.Time) CacheOption {
+	return func(c *CachedChecker) {
+		c.now = now
+	}
+}
+
+type cacheEntry struct {
+	status    Status
+	fetchedAt time.Time
+}
+
+// CachedChecker checks bans against a cache, so that writes don't cost a database round trip.
+// Bans are evaluated against the clock on every check, so a cached ban still ends on time.
+type CachedChecker struct {
+	lookup     Lookup
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.RWMutex
+	entries map[string]cacheEntry
+}
+
+func NewCachedChecker(lookup Lookup, opts ...CacheOption) *CachedChecker {
+	if lookup == nil {
+		panic("nil ban lookup")
+	}
+	c := &CachedChecker{
+		lookup:     lookup,
+		ttl:        defaultTTL,
+		maxEntries: defaultMaxEntries,
+		now:        time.Now,
+		entries:    map[string]cacheEntry{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *CachedChecker) CheckNotBanned(ctx context.Context, userId string) error {
+	status, err := c.status(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if status.activeAt(c.now()) {
+		return &ErrUserBanned{Reason: status.Reason, Until: status.Until}
+	}
+	return nil
+}
+
+// Invalidate forgets the cached ban of a user, to be called when the user is banned or unbanned
+func (c *CachedChecker) Invalidate(userId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, userId)
+}
+
+func (c *CachedChecker) status(ctx context.Context, userId string) (Status, error) {
+	now := c.now()
+	c.mu.RLock()
+	entry, ok := c.entries[userId]
+	c.mu.RUnlock()
+	if ok && now.Sub(entry.fetchedAt) < c.ttl {
+		return entry.status, nil
+	}
+
+	status, err := c.lookup.BanStatus(ctx, userId)
+	if err != nil {
+		return Status{}, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= c.maxEntries {
+		c.entries = map[string]cacheEntry{}
+	}
+	c.entries[userId] = cacheEntry{status: status, fetchedAt: now}
+	return status, nil
+}
+
+type enforcedHandler[T any] struct {
+	handler shared.CommandHandler[T]
+	checker Checker
+}
+
+// Enforce rejects commands sent by banned users before they reach handler. Commands without an
+// authenticated user, like registration or scheduled jobs, go through.
+func Enforce[T any](handler shared.CommandHandler[T], checker Checker) shared.CommandHandler[T] {
+	if handler == nil || checker == nil {
+		panic("nil command handler or ban checker")
+	}
+	return &enforcedHandler[T]{handler: handler, checker: checker}
+}
+
+func (e *enforcedHandler[T]) Handle(ctx context.Context, cmd T) error {
+	if authUser := auth.GetUserFromCtx(ctx); authUser != nil && authUser.Id != "" {
+		if err := e.checker.CheckNotBanned(ctx, authUser.Id); err != nil {
+			return err
+		}
+	}
+	return e.handler.Handle(ctx, cmd)
+}
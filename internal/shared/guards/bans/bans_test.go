@@ -0,0 +1,163 @@
+package bans_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards/bans"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type lookupStub struct {
+	statuses map[string]bans.Status
+	err      error
+	calls    int
+}
+
+func (l *lookupStub) BanStatus(ctx context.Context, userId string) (bans.Status, error) {
+	l.calls++
+	return l.statuses[userId], l.err
+}
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func TestCachedChecker(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should let users without a ban through", func(t *testing.T) {
+		t.Parallel()
+		checker := bans.NewCachedChecker(&lookupStub{})
+		assert.NoError(t, checker.CheckNotBanned(context.Background(), "user-1"))
+	})
+
+	t.Run("should reject a banned user with the reason and end of the ban", func(t *testing.T) {
+		t.Parallel()
+		lookup := &lookupStub{statuses: map[string]bans.Status{
+			"user-1": {Banned: true, Reason: "spam", From: start, Until: start.Add(time.Hour)},
+		}}
+		checker := bans.NewCachedChecker(lookup, bans.WithClock((&clock{now: start}).Now))
+
+		err := checker.CheckNotBanned(context.Background(), "user-1")
+		var banned *bans.ErrUserBanned
+		require.True(t, errors.As(err, &banned))
+		assert.Equal(t, "spam", banned.Reason)
+		assert.Equal(t, start.Add(time.Hour), banned.Until)
+	})
+
+	t.Run("should reject users banned indefinitely", func(t *testing.T) {
+		t.Parallel()
+		lookup := &lookupStub{statuses: map[string]bans.Status{"user-1": {Banned: true, Reason: "abuse"}}}
+		checker := bans.NewCachedChecker(lookup)
+
+		err := checker.CheckNotBanned(context.Background(), "user-1")
+		var banned *bans.ErrUserBanned
+		require.True(t, errors.As(err, &banned))
+		assert.True(t, banned.Until.IsZero())
+	})
+
+	t.Run("should follow the ban timeline without looking it up again", func(t *testing.T) {
+		t.Parallel()
+		clk := &clock{now: start}
+		lookup := &lookupStub{statuses: map[string]bans.Status{
+			"user-1": {Banned: true, Reason: "spam", From: start.Add(time.Minute), Until: start.Add(2 * time.Minute)},
+		}}
+		checker := bans.NewCachedChecker(lookup, bans.WithClock(clk.Now), bans.WithTTL(time.Hour))
+		ctx := context.Background()
+
+		assert.NoError(t, checker.CheckNotBanned(ctx, "user-1"), "ban has not started yet")
+		clk.now = start.Add(time.Minute)
+		assert.Error(t, checker.CheckNotBanned(ctx, "user-1"))
+		clk.now = start.Add(2 * time.Minute)
+		assert.NoError(t, checker.CheckNotBanned(ctx, "user-1"), "ban is over")
+		assert.Equal(t, 1, lookup.calls)
+	})
+
+	t.Run("should look the ban up again once the cache entry is stale or invalidated", func(t *testing.T) {
+		t.Parallel()
+		clk := &clock{now: start}
+		lookup := &lookupStub{statuses: map[string]bans.Status{}}
+		checker := bans.NewCachedChecker(lookup, bans.WithClock(clk.Now), bans.WithTTL(time.Minute))
+		ctx := context.Background()
+
+		require.NoError(t, checker.CheckNotBanned(ctx, "user-1"))
+		require.NoError(t, checker.CheckNotBanned(ctx, "user-1"))
+		assert.Equal(t, 1, lookup.calls)
+
+		lookup.statuses["user-1"] = bans.Status{Banned: true, Reason: "spam"}
+		checker.Invalidate("user-1")
+		assert.Error(t, checker.CheckNotBanned(ctx, "user-1"))
+		assert.Equal(t, 2, lookup.calls)
+
+		lookup.statuses["user-1"] = bans.Status{}
+		clk.now = start.Add(time.Minute)
+		assert.NoError(t, checker.CheckNotBanned(ctx, "user-1"))
+		assert.Equal(t, 3, lookup.calls)
+	})
+
+	t.Run("should return lookup errors", func(t *testing.T) {
+		t.Parallel()
+		checker := bans.NewCachedChecker(&lookupStub{err: assert.AnError})
+		assert.ErrorIs(t, checker.CheckNotBanned(context.Background(), "user-1"), assert.AnError)
+	})
+}
+
+type handlerStub struct {
+	called bool
+}
+
+func (h *handlerStub) Handle(ctx context.Context, cmd string) error {
+	h.called = true
+	return nil
+}
+
+func TestEnforce(t *testing.T) {
+	t.Parallel()
+	bannedUsers := bans.CheckerFunc(func(ctx context.Context, userId string) error {
+		if userId == "banned" {
+			return &bans.ErrUserBanned{Reason: "spam"}
+		}
+		return nil
+	})
+
+	testCases := []struct {
+		name       string
+		authUser   *auth.AuthenticatedUser
+		wantCalled bool
+	}{
+		{name: "should stop commands of banned users", authUser: &auth.AuthenticatedUser{Id: "banned", Roles: []rbac.UserRole{rbac.Regular}}},
+		{name: "should pass commands of users who are not banned", authUser: &auth.AuthenticatedUser{Id: "user-1", Roles: []rbac.UserRole{rbac.Regular}}, wantCalled: true},
+		{name: "should pass commands of guests", authUser: &auth.AuthenticatedUser{Roles: []rbac.UserRole{rbac.Guest}}, wantCalled: true},
+		{name: "should pass commands without a user", wantCalled: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			if tc.authUser != nil {
+				ctx = auth.NewContextWithUser(ctx, tc.authUser)
+			}
+			handler := &handlerStub{}
+
+			err := bans.Enforce(handler, bannedUsers).Handle(ctx, "cmd")
+			assert.Equal(t, tc.wantCalled, handler.called)
+			if tc.wantCalled {
+				assert.NoError(t, err)
+			} else {
+				var banned *bans.ErrUserBanned
+				assert.True(t, errors.As(err, &banned))
+			}
+		})
+	}
+}
@@ -1,25 +1,46 @@
 package guards
 
 import (
+	"context"
+
 	"github.com/iammrsea/social-app/internal/shared/guards/abac"
 	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
 )
 
 type Guards interface {
 	// RBAC
-	rbac.Guard
+	Authorize(ctx context.Context, subject rbac.Subject, perm rbac.Permission) error
+	// Roles lists the roles of the policy in force with their effective permissions
+	Roles() []rbac.RoleDefinition
 	// ABAC
 	abac.Guard
 }
 
 type guards struct {
-	*rbac.RoleBasedGuard
+	roleGuard *rbac.RoleBasedGuard
 	*abac.AttributeBasedGuard
 }
 
-func New() Guards {
+// New takes the options of the role-based policy, such as rbac.RequireMFA
+func New(opts ...rbac.PolicyOption) Guards {
+	return NewWithRoleBasedGuard(rbac.New(opts...))
+}
+
+// NewWithRoleBasedGuard uses roleGuard for permissions, so its policy can be reloaded by the caller
+func NewWithRoleBasedGuard(roleGuard *rbac.RoleBasedGuard) Guards {
+	if roleGuard == nil {
+		panic("nil role based guard")
+	}
 	return &guards{
-		RoleBasedGuard:      rbac.New(),
-		AttributeBasedGuard: abac.New(),
+		roleGuard:           roleGuard,
+		AttributeBasedGuard: abac.New(roleGuard),
 	}
 }
+
+func (g *guards) Authorize(ctx context.Context, subject rbac.Subject, perm rbac.Permission) error {
+	return g.roleGuard.Authorize(subject, perm)
+}
+
+func (g *guards) Roles() []rbac.RoleDefinition {
+	return g.roleGuard.Roles()
+}
@@ -5,7 +5,9 @@
 package guards_mocks
 
 import (
-	"github.com/iammrsea/social-app/internal/shared/auth"
+	"context"
+
+	"github.com/iammrsea/social-app/internal/shared/guards/abac"
 	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -38,16 +40,16 @@ func (_m *MockGuards) EXPECT() *MockGuards_Expecter {
 }
 
 // Authorize provides a mock function for the type MockGuards
-func (_mock *MockGuards) Authorize(role rbac.UserRole, perm rbac.Permission) error {
-	ret := _mock.Called(role, perm)
+func (_mock *MockGuards) Authorize(ctx context.Context, subject rbac.Subject, perm rbac.Permission) error {
+	ret := _mock.Called(ctx, subject, perm)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Authorize")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(rbac.UserRole, rbac.Permission) error); ok {
-		r0 = returnFunc(role, perm)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, rbac.Subject, rbac.Permission) error); ok {
+		r0 = returnFunc(ctx, subject, perm)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -60,15 +62,16 @@ type MockGuards_Authorize_Call struct {
 }
 
 // Authorize is a helper method to define mock.On call
-//   - role
+//   - ctx
+//   - subject
 //   - perm
-func (_e *MockGuards_Expecter) Authorize(role interface{}, perm interface{}) *MockGuards_Authorize_Call {
-	return &MockGuards_Authorize_Call{Call: _e.mock.On("Authorize", role, perm)}
+func (_e *MockGuards_Expecter) Authorize(ctx interface{}, subject interface{}, perm interface{}) *MockGuards_Authorize_Call {
+	return &MockGuards_Authorize_Call{Call: _e.mock.On("Authorize", ctx, subject, perm)}
 }
 
-func (_c *MockGuards_Authorize_Call) Run(run func(role rbac.UserRole, perm rbac.Permission)) *MockGuards_Authorize_Call {
+func (_c *MockGuards_Authorize_Call) Run(run func(ctx context.Context, subject rbac.Subject, perm rbac.Permission)) *MockGuards_Authorize_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(rbac.UserRole), args[1].(rbac.Permission))
+		run(args[0].(context.Context), args[1].(rbac.Subject), args[2].(rbac.Permission))
 	})
 	return _c
 }
@@ -78,53 +81,100 @@ func (_c *MockGuards_Authorize_Call) Return(err error) *MockGuards_Authorize_Cal
 	return _c
 }
 
-func (_c *MockGuards_Authorize_Call) RunAndReturn(run func(role rbac.UserRole, perm rbac.Permission) error) *MockGuards_Authorize_Call {
+func (_c *MockGuards_Authorize_Call) RunAndReturn(run func(ctx context.Context, subject rbac.Subject, perm rbac.Permission) error) *MockGuards_Authorize_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CanChangeUsername provides a mock function for the type MockGuards
-func (_mock *MockGuards) CanChangeUsername(userId string, authUser *auth.AuthenticatedUser) error {
-	ret := _mock.Called(userId, authUser)
+// Can provides a mock function for the type MockGuards
+func (_mock *MockGuards) Can(ctx context.Context, action abac.Action, resource abac.Resource) error {
+	ret := _mock.Called(ctx, action, resource)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CanChangeUsername")
+		panic("no return value specified for Can")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(string, *auth.AuthenticatedUser) error); ok {
-		r0 = returnFunc(userId, authUser)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, abac.Action, abac.Resource) error); ok {
+		r0 = returnFunc(ctx, action, resource)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockGuards_CanChangeUsername_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CanChangeUsername'
-type MockGuards_CanChangeUsername_Call struct {
+// MockGuards_Can_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Can'
+type MockGuards_Can_Call struct {
 	*mock.Call
 }
 
-// CanChangeUsername is a helper method to define mock.On call
-//   - userId
-//   - authUser
-func (_e *MockGuards_Expecter) CanChangeUsername(userId interface{}, authUser interface{}) *MockGuards_CanChangeUsername_Call {
-	return &MockGuards_CanChangeUsername_Call{Call: _e.mock.On("CanChangeUsername", userId, authUser)}
+// Can is a helper method to define mock.On call
+//   - ctx
+//   - action
+//   - resource
+func (_e *MockGuards_Expecter) Can(ctx interface{}, action interface{}, resource interface{}) *MockGuards_Can_Call {
+	return &MockGuards_Can_Call{Call: _e.mock.On("Can", ctx, action, resource)}
 }
 
-func (_c *MockGuards_CanChangeUsername_Call) Run(run func(userId string, authUser *auth.AuthenticatedUser)) *MockGuards_CanChangeUsername_Call {
+func (_c *MockGuards_Can_Call) Run(run func(ctx context.Context, action abac.Action, resource abac.Resource)) *MockGuards_Can_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(*auth.AuthenticatedUser))
+		run(args[0].(context.Context), args[1].(abac.Action), args[2].(abac.Resource))
 	})
 	return _c
 }
 
-func (_c *MockGuards_CanChangeUsername_Call) Return(err error) *MockGuards_CanChangeUsername_Call {
+func (_c *MockGuards_Can_Call) Return(err error) *MockGuards_Can_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockGuards_CanChangeUsername_Call) RunAndReturn(run func(userId string, authUser *auth.AuthenticatedUser) error) *MockGuards_CanChangeUsername_Call {
+func (_c *MockGuards_Can_Call) RunAndReturn(run func(ctx context.Context, action abac.Action, resource abac.Resource) error) *MockGuards_Can_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Roles provides a mock function for the type MockGuards
+func (_mock *MockGuards) Roles() []rbac.RoleDefinition {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Roles")
+	}
+
+	var r0 []rbac.RoleDefinition
+	if returnFunc, ok := ret.Get(0).(func() []rbac.RoleDefinition); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]rbac.RoleDefinition)
+		}
+	}
+	return r0
+}
+
+// MockGuards_Roles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Roles'
+type MockGuards_Roles_Call struct {
+	*mock.Call
+}
+
+// Roles is a helper method to define mock.On call
+func (_e *MockGuards_Expecter) Roles() *MockGuards_Roles_Call {
+	return &MockGuards_Roles_Call{Call: _e.mock.On("Roles")}
+}
+
+func (_c *MockGuards_Roles_Call) Run(run func()) *MockGuards_Roles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockGuards_Roles_Call) Return(vs []rbac.RoleDefinition) *MockGuards_Roles_Call {
+	_c.Call.Return(vs)
+	return _c
+}
+
+func (_c *MockGuards_Roles_Call) RunAndReturn(run func() []rbac.RoleDefinition) *MockGuards_Roles_Call {
 	_c.Call.Return(run)
 	return _c
 }
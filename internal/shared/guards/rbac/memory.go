@@ -0,0 +1,33 @@
+package rbac
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryPolicyStore is a PolicyStore kept in memory, for tests and the in-memory storage engine
+type MemoryPolicyStore struct {
+	mu   sync.Mutex
+	conf *PolicyConfig
+}
+
+func NewMemoryPolicyStore() *MemoryPolicyStore {
+	return &MemoryPolicyStore{}
+}
+
+func (s *MemoryPolicyStore) LoadPolicy(ctx context.Context) (PolicyConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conf == nil {
+		return PolicyConfig{}, ErrPolicyNotFound
+	}
+	return s.conf.clone(), nil
+}
+
+func (s *MemoryPolicyStore) SavePolicy(ctx context.Context, conf PolicyConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conf = conf.clone()
+	s.conf = &conf
+	return nil
+}
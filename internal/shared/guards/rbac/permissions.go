@@ -1,5 +1,7 @@
 package rbac
 
+import "slices"
+
 type Permission string
 
 const (
@@ -11,9 +13,43 @@ const (
 	DeleteUser    Permission = "delete:user"
 	AwardBadge    Permission = "award:badge"
 	RevokeBadge   Permission = "revoke:badge"
-	MakeModerator Permission = "make:moderator"
-	MakeRegular   Permission = "make:regular"
+	AssignRole    Permission = "assign:role"
+	ManageRoles   Permission = "manage:roles"
 	CreateAccount Permission = "create:account"
 	ViewUser      Permission = "view:user"
 	ListUsers     Permission = "list:users"
+	ViewPost      Permission = "view:post"
+	ViewComment   Permission = "view:comment"
+	CreateComment Permission = "create:comment"
+	UpdateComment Permission = "update:comment"
+	DeleteComment Permission = "delete:comment"
+	CastVote      Permission = "cast:vote"
+	ViewVote      Permission = "view:vote"
+	CreateReport  Permission = "create:report"
+	ViewReport    Permission = "view:report"
+	ResolveReport Permission = "resolve:report"
+	ManageAPIKeys Permission = "manage:apikeys"
+	ManageMFA     Permission = "manage:mfa"
+	ViewRoles     Permission = "view:roles"
+	ViewAuditLog  Permission = "view:audit_log"
+	UpdateUser    Permission = "update:user"
+	// The "any" permissions let a user act on what other users own, where the plain ones only
+	// reach the user's own posts, comments and account
+	UpdateAnyUser    Permission = "update:any_user"
+	UpdateAnyPost    Permission = "update:any_post"
+	DeleteAnyPost    Permission = "delete:any_post"
+	UpdateAnyComment Permission = "update:any_comment"
+	DeleteAnyComment Permission = "delete:any_comment"
 )
+
+var permissions = []Permission{
+	BanUser, UnbanUser, CreatePost, DeletePost, UpdatePost, DeleteUser, AwardBadge, RevokeBadge,
+	AssignRole, ManageRoles, CreateAccount, ViewUser, ListUsers, ViewPost, ViewComment,
+	CreateComment, UpdateComment, DeleteComment, CastVote, ViewVote, CreateReport, ViewReport,
+	ResolveReport, ManageAPIKeys, ManageMFA, ViewRoles, ViewAuditLog, UpdateUser, UpdateAnyUser,
+	UpdateAnyPost, DeleteAnyPost, UpdateAnyComment, DeleteAnyComment,
+}
+
+func (p Permission) IsValid() bool {
+	return slices.Contains(permissions, p)
+}
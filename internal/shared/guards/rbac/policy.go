@@ -1,31 +1,223 @@
 package rbac
 
 import (
+	"errors"
+	"fmt"
 	"slices"
+	"sort"
 )
 
+var (
+	ErrInvalidPolicy  = errors.New("invalid policy")
+	ErrPolicyNotFound = errors.New("policy not found")
+	ErrUnknownRole    = errors.New("unknown role")
+)
+
+// PolicyConfig is the role-to-permission policy as written in a file or stored in the database
+type PolicyConfig struct {
+	Roles map[UserRole]RoleConfig `json:"roles" yaml:"roles"`
+	// Unverified caps the permissions of users who haven't verified their email, whatever their role
+	Unverified []Permission `json:"unverified" yaml:"unverified"`
+}
+
+type RoleConfig struct {
+	// Inherits are the roles whose permissions the role also has
+	Inherits    []UserRole   `json:"inherits,omitempty" yaml:"inherits,omitempty"`
+	Permissions []Permission `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	// All grants every permission, as admins have
+	All bool `json:"all,omitempty" yaml:"all,omitempty"`
+}
+
+// DefaultPolicyConfig is the policy in force when none is configured
+func DefaultPolicyConfig() PolicyConfig {
+	return PolicyConfig{
+		Roles: map[UserRole]RoleConfig{
+			Guest: {
+				Permissions: []Permission{CreateAccount, ViewPost, ViewComment, ViewVote},
+			},
+			Regular: {
+				Permissions: []Permission{
+					ViewUser, ViewPost, CreatePost, UpdatePost, DeletePost, ViewComment, CreateComment,
+					UpdateComment, DeleteComment, CastVote, ViewVote, CreateReport, ManageAPIKeys, ManageMFA,
+					UpdateUser,
+				},
+			},
+			Moderator: {
+				Inherits:    []UserRole{Regular},
+				Permissions: []Permission{ListUsers, BanUser, UnbanUser, ViewReport, ResolveReport, DeleteAnyPost, DeleteAnyComment},
+			},
+			Admin: {All: true},
+		},
+		Unverified: []Permission{ViewUser, ViewPost, ViewComment, ViewVote},
+	}
+}
+
+// clone copies conf, so that changes to the copy don't reach the original
+func (conf PolicyConfig) clone() PolicyConfig {
+	roles := make(map[UserRole]RoleConfig, len(conf.Roles))
+	for role, rc := range conf.Roles {
+		roles[role] = RoleConfig{
+			Inherits:    slices.Clone(rc.Inherits),
+			Permissions: slices.Clone(rc.Permissions),
+			All:         rc.All,
+		}
+	}
+	return PolicyConfig{Roles: roles, Unverified: slices.Clone(conf.Unverified)}
+}
+
+// RoleDefinition is a role of the policy with its effective permissions, inherited ones included
+type RoleDefinition struct {
+	Role        UserRole
+	Inherits    []UserRole
+	All         bool
+	Permissions []Permission
+}
+
 type Policy struct {
+	// rules are the effective permissions of each role, inheritance resolved
 	rules map[UserRole][]Permission
+	roles map[UserRole]RoleConfig
+	// unverified caps the permissions of users who haven't verified their email, whatever their role
+	unverified []Permission
+	// mfaRequired are the roles refused privileged permissions until they log in with a second factor
+	mfaRequired []UserRole
 }
 
-func NewPolicy() *Policy {
-	return &Policy{
-		rules: map[UserRole][]Permission{
-			Regular:   {ViewUser},
-			Admin:     {ViewUser},
-			Moderator: {ViewUser, ListUsers, BanUser, UnbanUser},
-			Guest:     {CreateAccount},
-		},
+type PolicyOption func(*Policy)
+
+// RequireMFA makes roles log in with two-factor authentication before they get any privileged
+// permission, one that regular users don't have
+func RequireMFA(roles ...UserRole) PolicyOption {
+	return func(p *Policy) {
+		p.mfaRequired = append(p.mfaRequired, roles...)
 	}
 }
 
-func (p *Policy) IsAllowed(role UserRole, perm Permission) bool {
-	if role == Admin {
-		return true
+// NewPolicy returns the default policy
+func NewPolicy(opts ...PolicyOption) *Policy {
+	p, err := NewPolicyFromConfig(DefaultPolicyConfig(), opts...)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// NewPolicyFromConfig validates conf and resolves the permissions each role inherits. Unknown
+// permissions, roles inheriting undefined roles and inheritance cycles are rejected.
+func NewPolicyFromConfig(conf PolicyConfig, opts ...PolicyOption) (*Policy, error) {
+	if len(conf.Roles) == 0 {
+		return nil, fmt.Errorf("%w: no roles", ErrInvalidPolicy)
+	}
+	for role, rc := range conf.Roles {
+		if !role.IsValid() {
+			return nil, fmt.Errorf("%w: invalid role name %q", ErrInvalidPolicy, role)
+		}
+		for _, perm := range rc.Permissions {
+			if !perm.IsValid() {
+				return nil, fmt.Errorf("%w: role %s has unknown permission %q", ErrInvalidPolicy, role, perm)
+			}
+		}
+		for _, parent := range rc.Inherits {
+			if _, ok := conf.Roles[parent]; !ok {
+				return nil, fmt.Errorf("%w: role %s inherits undefined role %q", ErrInvalidPolicy, role, parent)
+			}
+		}
+	}
+	for _, perm := range conf.Unverified {
+		if !perm.IsValid() {
+			return nil, fmt.Errorf("%w: unknown unverified permission %q", ErrInvalidPolicy, perm)
+		}
+	}
+
+	p := &Policy{
+		rules:      make(map[UserRole][]Permission, len(conf.Roles)),
+		roles:      conf.clone().Roles,
+		unverified: slices.Clone(conf.Unverified),
 	}
+	for role := range conf.Roles {
+		perms, err := resolve(conf.Roles, role, nil)
+		if err != nil {
+			return nil, err
+		}
+		p.rules[role] = perms
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// resolve returns the permissions of role and of the roles it inherits, directly or not. path
+// holds the roles being resolved, to catch cycles.
+func resolve(roles map[UserRole]RoleConfig, role UserRole, path []UserRole) ([]Permission, error) {
+	if slices.Contains(path, role) {
+		return nil, fmt.Errorf("%w: inheritance cycle %v", ErrInvalidPolicy, append(path, role))
+	}
+	rc := roles[role]
+	if rc.All {
+		return slices.Clone(permissions), nil
+	}
+	perms := slices.Clone(rc.Permissions)
+	for _, parent := range rc.Inherits {
+		inherited, err := resolve(roles, parent, append(path, role))
+		if err != nil {
+			return nil, err
+		}
+		perms = append(perms, inherited...)
+	}
+	slices.Sort(perms)
+	return slices.Compact(perms), nil
+}
+
+func (p *Policy) IsAllowed(role UserRole, perm Permission) bool {
 	perms, ok := p.rules[role]
 	if !ok {
 		return false
 	}
 	return slices.Contains(perms, perm)
 }
+
+// IsAllowedUnverified tells whether users who haven't verified their email may use perm
+func (p *Policy) IsAllowedUnverified(perm Permission) bool {
+	return slices.Contains(p.unverified, perm)
+}
+
+// RequiresMFA tells whether role needs two-factor authentication to use perm
+func (p *Policy) RequiresMFA(role UserRole, perm Permission) bool {
+	return slices.Contains(p.mfaRequired, role) && !p.IsAllowed(Regular, perm)
+}
+
+// Roles lists the roles of the policy by name
+func (p *Policy) Roles() []RoleDefinition {
+	roles := make([]RoleDefinition, 0, len(p.rules))
+	for role := range p.rules {
+		roles = append(roles, p.definition(role))
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Role < roles[j].Role
+	})
+	return roles
+}
+
+// Role returns the definition of role, if the policy has it
+func (p *Policy) Role(role UserRole) (RoleDefinition, bool) {
+	if _, ok := p.rules[role]; !ok {
+		return RoleDefinition{}, false
+	}
+	return p.definition(role), true
+}
+
+// Config returns the policy as it was configured, before inheritance is resolved
+func (p *Policy) Config() PolicyConfig {
+	return PolicyConfig{Roles: p.roles, Unverified: p.unverified}.clone()
+}
+
+func (p *Policy) definition(role UserRole) RoleDefinition {
+	rc := p.roles[role]
+	return RoleDefinition{
+		Role:        role,
+		Inherits:    slices.Clone(rc.Inherits),
+		All:         rc.All,
+		Permissions: slices.Clone(p.rules[role]),
+	}
+}
@@ -0,0 +1,200 @@
+package rbac_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyFromConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should give roles the permissions of the roles they inherit", func(t *testing.T) {
+		t.Parallel()
+		policy, err := rbac.NewPolicyFromConfig(rbac.PolicyConfig{
+			Roles: map[rbac.UserRole]rbac.RoleConfig{
+				rbac.Regular:   {Permissions: []rbac.Permission{rbac.ViewPost}},
+				rbac.Moderator: {Inherits: []rbac.UserRole{rbac.Regular}, Permissions: []rbac.Permission{rbac.BanUser}},
+				"SUPPORT":      {Inherits: []rbac.UserRole{rbac.Moderator}, Permissions: []rbac.Permission{rbac.AwardBadge}},
+			},
+		})
+		require.NoError(t, err)
+
+		assert.True(t, policy.IsAllowed("SUPPORT", rbac.ViewPost))
+		assert.True(t, policy.IsAllowed("SUPPORT", rbac.BanUser))
+		assert.True(t, policy.IsAllowed("SUPPORT", rbac.AwardBadge))
+		assert.False(t, policy.IsAllowed(rbac.Moderator, rbac.AwardBadge))
+		assert.False(t, policy.IsAllowed(rbac.Admin, rbac.ViewPost), "roles missing from the policy have no permission")
+
+		role, ok := policy.Role("SUPPORT")
+		require.True(t, ok)
+		assert.Equal(t, []rbac.UserRole{rbac.Moderator}, role.Inherits)
+		assert.ElementsMatch(t, []rbac.Permission{rbac.ViewPost, rbac.BanUser, rbac.AwardBadge}, role.Permissions)
+	})
+
+	t.Run("should give every permission to roles with all", func(t *testing.T) {
+		t.Parallel()
+		policy, err := rbac.NewPolicyFromConfig(rbac.PolicyConfig{
+			Roles: map[rbac.UserRole]rbac.RoleConfig{rbac.Admin: {All: true}},
+		})
+		require.NoError(t, err)
+		assert.True(t, policy.IsAllowed(rbac.Admin, rbac.ViewRoles))
+		assert.True(t, policy.IsAllowed(rbac.Admin, rbac.AssignRole))
+	})
+
+	invalid := []struct {
+		name string
+		conf rbac.PolicyConfig
+	}{
+		{name: "no roles", conf: rbac.PolicyConfig{}},
+		{
+			name: "unknown permission",
+			conf: rbac.PolicyConfig{Roles: map[rbac.UserRole]rbac.RoleConfig{
+				rbac.Regular: {Permissions: []rbac.Permission{"fly:plane"}},
+			}},
+		},
+		{
+			name: "unknown unverified permission",
+			conf: rbac.PolicyConfig{
+				Roles:      map[rbac.UserRole]rbac.RoleConfig{rbac.Regular: {}},
+				Unverified: []rbac.Permission{"fly:plane"},
+			},
+		},
+		{
+			name: "undefined inherited role",
+			conf: rbac.PolicyConfig{Roles: map[rbac.UserRole]rbac.RoleConfig{
+				rbac.Moderator: {Inherits: []rbac.UserRole{rbac.Regular}},
+			}},
+		},
+		{
+			name: "inheritance cycle",
+			conf: rbac.PolicyConfig{Roles: map[rbac.UserRole]rbac.RoleConfig{
+				rbac.Regular:   {Inherits: []rbac.UserRole{"SUPPORT"}},
+				rbac.Moderator: {Inherits: []rbac.UserRole{rbac.Regular}},
+				"SUPPORT":      {Inherits: []rbac.UserRole{rbac.Moderator}},
+			}},
+		},
+	}
+	for _, tc := range invalid {
+		t.Run("should reject a policy with "+tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := rbac.NewPolicyFromConfig(tc.conf)
+			assert.ErrorIs(t, err, rbac.ErrInvalidPolicy)
+		})
+	}
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	t.Parallel()
+	policy := rbac.NewPolicy()
+
+	moderator, ok := policy.Role(rbac.Moderator)
+	require.True(t, ok)
+	regular, ok := policy.Role(rbac.Regular)
+	require.True(t, ok)
+	assert.Subset(t, moderator.Permissions, regular.Permissions)
+
+	assert.True(t, policy.IsAllowed(rbac.Admin, rbac.ViewRoles))
+	assert.False(t, policy.IsAllowed(rbac.Moderator, rbac.ViewRoles))
+	assert.Len(t, policy.Roles(), 4)
+}
+
+func TestFileSource(t *testing.T) {
+	t.Parallel()
+	write := func(t *testing.T, name, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("should load a yaml policy", func(t *testing.T) {
+		t.Parallel()
+		path := write(t, "policy.yaml", `
+roles:
+  REGULAR:
+    permissions: [view:post]
+  MODERATOR:
+    inherits: [REGULAR]
+    permissions: [award:badge]
+unverified: [view:post]
+`)
+		conf, err := rbac.NewFileSource(path).LoadPolicy(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []rbac.UserRole{rbac.Regular}, conf.Roles[rbac.Moderator].Inherits)
+		assert.Equal(t, []rbac.Permission{rbac.AwardBadge}, conf.Roles[rbac.Moderator].Permissions)
+		assert.Equal(t, []rbac.Permission{rbac.ViewPost}, conf.Unverified)
+	})
+
+	t.Run("should load a json policy", func(t *testing.T) {
+		t.Parallel()
+		path := write(t, "policy.json", `{"roles": {"ADMIN": {"all": true}}, "unverified": []}`)
+		conf, err := rbac.NewFileSource(path).LoadPolicy(context.Background())
+		require.NoError(t, err)
+		assert.True(t, conf.Roles[rbac.Admin].All)
+	})
+
+	t.Run("should reject unknown fields", func(t *testing.T) {
+		t.Parallel()
+		path := write(t, "policy.yaml", "roles:\n  REGULAR:\n    permisions: [view:post]\n")
+		_, err := rbac.NewFileSource(path).LoadPolicy(context.Background())
+		assert.ErrorIs(t, err, rbac.ErrInvalidPolicy)
+	})
+
+	t.Run("should tell a missing file", func(t *testing.T) {
+		t.Parallel()
+		_, err := rbac.NewFileSource(filepath.Join(t.TempDir(), "missing.yaml")).LoadPolicy(context.Background())
+		assert.ErrorIs(t, err, rbac.ErrPolicyNotFound)
+	})
+
+	t.Run("should load the example policy", func(t *testing.T) {
+		t.Parallel()
+		conf, err := rbac.NewFileSource("../../../../rbac-policy.example.yaml").LoadPolicy(context.Background())
+		require.NoError(t, err)
+		policy, err := rbac.NewPolicyFromConfig(conf)
+		require.NoError(t, err)
+		assert.Equal(t, rbac.NewPolicy().Roles(), policy.Roles())
+	})
+}
+
+func TestReload(t *testing.T) {
+	t.Parallel()
+	awardingModerators := rbac.DefaultPolicyConfig()
+	moderator := awardingModerators.Roles[rbac.Moderator]
+	moderator.Permissions = append(moderator.Permissions, rbac.AwardBadge)
+	awardingModerators.Roles[rbac.Moderator] = moderator
+
+	t.Run("should put a new policy in force", func(t *testing.T) {
+		t.Parallel()
+		guard := rbac.New()
+		subject := rbac.Subject{Roles: []rbac.UserRole{rbac.Moderator}}
+		assert.ErrorIs(t, guard.Authorize(subject, rbac.AwardBadge), rbac.ErrUnauthorized)
+
+		require.NoError(t, guard.Load(awardingModerators))
+		assert.NoError(t, guard.Authorize(subject, rbac.AwardBadge))
+	})
+
+	t.Run("should keep the policy in force when the new one is invalid", func(t *testing.T) {
+		t.Parallel()
+		guard := rbac.New()
+		conf := rbac.DefaultPolicyConfig()
+		conf.Roles[rbac.Moderator] = rbac.RoleConfig{Permissions: []rbac.Permission{"fly:plane"}}
+
+		assert.ErrorIs(t, guard.Load(conf), rbac.ErrInvalidPolicy)
+		assert.NoError(t, guard.Authorize(rbac.Subject{Roles: []rbac.UserRole{rbac.Moderator}}, rbac.BanUser))
+	})
+
+	t.Run("should apply the guard's options to new policies", func(t *testing.T) {
+		t.Parallel()
+		guard := rbac.New(rbac.RequireMFA(rbac.Moderator))
+		require.NoError(t, guard.Load(awardingModerators))
+
+		assert.ErrorIs(t, guard.Authorize(rbac.Subject{Roles: []rbac.UserRole{rbac.Moderator}}, rbac.AwardBadge), rbac.ErrUnauthorized)
+		assert.NoError(t, guard.Authorize(rbac.Subject{Roles: []rbac.UserRole{rbac.Moderator}, MFA: true}, rbac.AwardBadge))
+	})
+}
@@ -2,31 +2,98 @@ package rbac
 
 //rbac => Role-Based Access Control
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+)
 
 var (
 	ErrUnauthorized = errors.New("unauthorized")
 )
 
-type Guard interface {
-	Authorize(role UserRole, perm Permission) error
+// Subject is who a permission is checked for. It has the permissions of all its roles. Scopes, when
+// not nil, caps the permissions of the roles, as it does for API keys. Unverified users are capped
+// by the policy's unverified permissions. MFA tells whether the subject logged in with two-factor
+// authentication.
+type Subject struct {
+	Roles      []UserRole
+	Scopes     []Permission
+	Unverified bool
+	MFA        bool
 }
 
-type policy interface {
-	IsAllowed(role UserRole, perm Permission) bool
+type Guard interface {
+	Authorize(subject Subject, perm Permission) error
+	// Roles lists the roles of the policy in force with their effective permissions
+	Roles() []RoleDefinition
 }
 
+// RoleBasedGuard checks permissions against a policy that can be swapped while requests are
+// served, so a new policy is in force without a restart
 type RoleBasedGuard struct {
-	policy
+	policy atomic.Pointer[Policy]
+	// opts are applied to every policy the guard loads
+	opts []PolicyOption
 }
 
-func New() *RoleBasedGuard {
-	return &RoleBasedGuard{policy: NewPolicy()}
+// New returns a guard enforcing the default policy
+func New(opts ...PolicyOption) *RoleBasedGuard {
+	rg := &RoleBasedGuard{opts: opts}
+	rg.policy.Store(NewPolicy(opts...))
+	return rg
 }
 
-func (rg *RoleBasedGuard) Authorize(role UserRole, perm Permission) error {
-	if !rg.policy.IsAllowed(role, perm) {
+// Load puts conf in force, unless it is invalid, in which case the policy in force is kept
+func (rg *RoleBasedGuard) Load(conf PolicyConfig) error {
+	p, err := NewPolicyFromConfig(conf, rg.opts...)
+	if err != nil {
+		return err
+	}
+	rg.policy.Store(p)
+	return nil
+}
+
+// Reload loads the policy of source and puts it in force
+func (rg *RoleBasedGuard) Reload(ctx context.Context, source PolicySource) error {
+	conf, err := source.LoadPolicy(ctx)
+	if err != nil {
+		return err
+	}
+	return rg.Load(conf)
+}
+
+// Policy returns the policy in force
+func (rg *RoleBasedGuard) Policy() *Policy {
+	return rg.policy.Load()
+}
+
+func (rg *RoleBasedGuard) Roles() []RoleDefinition {
+	return rg.policy.Load().Roles()
+}
+
+func (rg *RoleBasedGuard) Authorize(subject Subject, perm Permission) error {
+	// All the checks below are made against one policy, even if it is swapped meanwhile
+	policy := rg.policy.Load()
+	allowed := slices.ContainsFunc(subject.Roles, func(role UserRole) bool {
+		return policy.IsAllowed(role, perm)
+	})
+	if !allowed {
 		return ErrUnauthorized
 	}
+	if subject.Unverified && !policy.IsAllowedUnverified(perm) {
+		return fmt.Errorf("%w: verify your email to %s", ErrUnauthorized, perm)
+	}
+	requiresMFA := slices.ContainsFunc(subject.Roles, func(role UserRole) bool {
+		return policy.RequiresMFA(role, perm)
+	})
+	if !subject.MFA && requiresMFA {
+		return fmt.Errorf("%w: log in with two-factor authentication to %s", ErrUnauthorized, perm)
+	}
+	if subject.Scopes != nil && !slices.Contains(subject.Scopes, perm) {
+		return fmt.Errorf("%w: %s is outside the scope of the api key", ErrUnauthorized, perm)
+	}
 	return nil
 }
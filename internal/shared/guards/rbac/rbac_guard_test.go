@@ -5,6 +5,7 @@ import (
 
 	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type testCase struct {
@@ -46,7 +47,7 @@ func TestPermision_BanUser(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			err := rbac.New().Authorize(tc.userRole, tc.permission)
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
 			assert.ErrorIs(t, err, tc.expectedErr)
 		})
 	}
@@ -84,7 +85,7 @@ func TestPermission_UnbanUser(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			err := rbac.New().Authorize(tc.userRole, tc.permission)
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
 			assert.ErrorIs(t, err, tc.expectedErr)
 		})
 	}
@@ -122,7 +123,7 @@ func TestPermission_ViewUser(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			err := rbac.New().Authorize(tc.userRole, tc.permission)
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
 			assert.ErrorIs(t, err, tc.expectedErr)
 		})
 	}
@@ -159,7 +160,7 @@ func TestPermission_DeleteUser(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			err := rbac.New().Authorize(tc.userRole, tc.permission)
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
 			assert.ErrorIs(t, err, tc.expectedErr)
 		})
 	}
@@ -196,7 +197,7 @@ func TestPermission_AwardBadge(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			err := rbac.New().Authorize(tc.userRole, tc.permission)
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
 			assert.ErrorIs(t, err, tc.expectedErr)
 		})
 	}
@@ -233,81 +234,81 @@ func TestPermission_RevokeBadge(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			err := rbac.New().Authorize(tc.userRole, tc.permission)
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
 			assert.ErrorIs(t, err, tc.expectedErr)
 		})
 	}
 }
 
-func TestPermission_MakeModerator(t *testing.T) {
+func TestPermission_AssignRole(t *testing.T) {
 	t.Parallel()
 	testCases := []testCase{
 		{
-			name:        "user with admin role can make moderator",
+			name:        "user with admin role can assign roles",
 			userRole:    rbac.Admin,
-			permission:  rbac.MakeModerator,
+			permission:  rbac.AssignRole,
 			expectedErr: nil,
 		},
 		{
-			name:        "user with moderator role cannot make moderator",
+			name:        "user with moderator role cannot assign roles",
 			userRole:    rbac.Moderator,
-			permission:  rbac.MakeModerator,
+			permission:  rbac.AssignRole,
 			expectedErr: rbac.ErrUnauthorized,
 		},
 		{
-			name:        "user with regular role cannot make moderator",
+			name:        "user with regular role cannot assign roles",
 			userRole:    rbac.Regular,
-			permission:  rbac.MakeModerator,
+			permission:  rbac.AssignRole,
 			expectedErr: rbac.ErrUnauthorized,
 		},
 		{
-			name:        "user with guest role cannot make moderator",
+			name:        "user with guest role cannot assign roles",
 			userRole:    rbac.Guest,
-			permission:  rbac.MakeModerator,
+			permission:  rbac.AssignRole,
 			expectedErr: rbac.ErrUnauthorized,
 		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			err := rbac.New().Authorize(tc.userRole, tc.permission)
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
 			assert.ErrorIs(t, err, tc.expectedErr)
 		})
 	}
 }
 
-func TestPermission_MakeRegular(t *testing.T) {
+func TestPermission_ManageRoles(t *testing.T) {
 	t.Parallel()
 	testCases := []testCase{
 		{
-			name:        "user with admin role can make regular",
+			name:        "user with admin role can manage roles",
 			userRole:    rbac.Admin,
-			permission:  rbac.MakeRegular,
+			permission:  rbac.ManageRoles,
 			expectedErr: nil,
 		},
 		{
-			name:        "user with moderator role cannot make regular",
+			name:        "user with moderator role cannot manage roles",
 			userRole:    rbac.Moderator,
-			permission:  rbac.MakeRegular,
+			permission:  rbac.ManageRoles,
 			expectedErr: rbac.ErrUnauthorized,
 		},
 		{
-			name:        "user with regular role cannot make regular",
+			name:        "user with regular role cannot manage roles",
 			userRole:    rbac.Regular,
-			permission:  rbac.MakeRegular,
+			permission:  rbac.ManageRoles,
 			expectedErr: rbac.ErrUnauthorized,
 		},
 		{
-			name:        "user with guest role cannot make regular",
+			name:        "user with guest role cannot manage roles",
 			userRole:    rbac.Guest,
-			permission:  rbac.MakeRegular,
+			permission:  rbac.ManageRoles,
 			expectedErr: rbac.ErrUnauthorized,
 		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			err := rbac.New().Authorize(tc.userRole, tc.permission)
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
 			assert.ErrorIs(t, err, tc.expectedErr)
 		})
 	}
@@ -344,7 +345,7 @@ func TestPermission_CreateAccount(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			err := rbac.New().Authorize(tc.userRole, tc.permission)
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
 			assert.ErrorIs(t, err, tc.expectedErr)
 		})
 	}
@@ -381,8 +382,698 @@ func TestPermission_ListUsers(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			err := rbac.New().Authorize(tc.userRole, tc.permission)
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
 			assert.ErrorIs(t, err, tc.expectedErr)
 		})
 	}
 }
+
+func TestPermission_CreatePost(t *testing.T) {
+	t.Parallel()
+	testCases := []testCase{
+		{
+			name:        "user with admin role can create post",
+			userRole:    rbac.Admin,
+			permission:  rbac.CreatePost,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with moderator role can create post",
+			userRole:    rbac.Moderator,
+			permission:  rbac.CreatePost,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with regular role can create post",
+			userRole:    rbac.Regular,
+			permission:  rbac.CreatePost,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with guest role cannot create post",
+			userRole:    rbac.Guest,
+			permission:  rbac.CreatePost,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPermission_UpdatePost(t *testing.T) {
+	t.Parallel()
+	testCases := []testCase{
+		{
+			name:        "user with admin role can update post",
+			userRole:    rbac.Admin,
+			permission:  rbac.UpdatePost,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with moderator role can update post",
+			userRole:    rbac.Moderator,
+			permission:  rbac.UpdatePost,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with regular role can update post",
+			userRole:    rbac.Regular,
+			permission:  rbac.UpdatePost,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with guest role cannot update post",
+			userRole:    rbac.Guest,
+			permission:  rbac.UpdatePost,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPermission_DeletePost(t *testing.T) {
+	t.Parallel()
+	testCases := []testCase{
+		{
+			name:        "user with admin role can delete post",
+			userRole:    rbac.Admin,
+			permission:  rbac.DeletePost,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with moderator role can delete post",
+			userRole:    rbac.Moderator,
+			permission:  rbac.DeletePost,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with regular role can delete post",
+			userRole:    rbac.Regular,
+			permission:  rbac.DeletePost,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with guest role cannot delete post",
+			userRole:    rbac.Guest,
+			permission:  rbac.DeletePost,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPermission_ViewPost(t *testing.T) {
+	t.Parallel()
+	testCases := []testCase{
+		{
+			name:        "user with admin role can view post",
+			userRole:    rbac.Admin,
+			permission:  rbac.ViewPost,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with moderator role can view post",
+			userRole:    rbac.Moderator,
+			permission:  rbac.ViewPost,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with regular role can view post",
+			userRole:    rbac.Regular,
+			permission:  rbac.ViewPost,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with guest role can view post",
+			userRole:    rbac.Guest,
+			permission:  rbac.ViewPost,
+			expectedErr: nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPermission_CreateComment(t *testing.T) {
+	t.Parallel()
+	testCases := []testCase{
+		{
+			name:        "user with admin role can create comment",
+			userRole:    rbac.Admin,
+			permission:  rbac.CreateComment,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with moderator role can create comment",
+			userRole:    rbac.Moderator,
+			permission:  rbac.CreateComment,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with regular role can create comment",
+			userRole:    rbac.Regular,
+			permission:  rbac.CreateComment,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with guest role cannot create comment",
+			userRole:    rbac.Guest,
+			permission:  rbac.CreateComment,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPermission_UpdateComment(t *testing.T) {
+	t.Parallel()
+	testCases := []testCase{
+		{
+			name:        "user with admin role can update comment",
+			userRole:    rbac.Admin,
+			permission:  rbac.UpdateComment,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with moderator role can update comment",
+			userRole:    rbac.Moderator,
+			permission:  rbac.UpdateComment,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with regular role can update comment",
+			userRole:    rbac.Regular,
+			permission:  rbac.UpdateComment,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with guest role cannot update comment",
+			userRole:    rbac.Guest,
+			permission:  rbac.UpdateComment,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPermission_DeleteComment(t *testing.T) {
+	t.Parallel()
+	testCases := []testCase{
+		{
+			name:        "user with admin role can delete comment",
+			userRole:    rbac.Admin,
+			permission:  rbac.DeleteComment,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with moderator role can delete comment",
+			userRole:    rbac.Moderator,
+			permission:  rbac.DeleteComment,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with regular role can delete comment",
+			userRole:    rbac.Regular,
+			permission:  rbac.DeleteComment,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with guest role cannot delete comment",
+			userRole:    rbac.Guest,
+			permission:  rbac.DeleteComment,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPermission_ViewComment(t *testing.T) {
+	t.Parallel()
+	testCases := []testCase{
+		{
+			name:        "user with admin role can view comment",
+			userRole:    rbac.Admin,
+			permission:  rbac.ViewComment,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with moderator role can view comment",
+			userRole:    rbac.Moderator,
+			permission:  rbac.ViewComment,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with regular role can view comment",
+			userRole:    rbac.Regular,
+			permission:  rbac.ViewComment,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with guest role can view comment",
+			userRole:    rbac.Guest,
+			permission:  rbac.ViewComment,
+			expectedErr: nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPermission_CastVote(t *testing.T) {
+	t.Parallel()
+	testCases := []testCase{
+		{
+			name:        "user with admin role can vote",
+			userRole:    rbac.Admin,
+			permission:  rbac.CastVote,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with moderator role can vote",
+			userRole:    rbac.Moderator,
+			permission:  rbac.CastVote,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with regular role can vote",
+			userRole:    rbac.Regular,
+			permission:  rbac.CastVote,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with guest role cannot vote",
+			userRole:    rbac.Guest,
+			permission:  rbac.CastVote,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPermission_ViewVote(t *testing.T) {
+	t.Parallel()
+	testCases := []testCase{
+		{
+			name:        "user with admin role can view votes",
+			userRole:    rbac.Admin,
+			permission:  rbac.ViewVote,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with moderator role can view votes",
+			userRole:    rbac.Moderator,
+			permission:  rbac.ViewVote,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with regular role can view votes",
+			userRole:    rbac.Regular,
+			permission:  rbac.ViewVote,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with guest role can view votes",
+			userRole:    rbac.Guest,
+			permission:  rbac.ViewVote,
+			expectedErr: nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPermission_CreateReport(t *testing.T) {
+	t.Parallel()
+	testCases := []testCase{
+		{
+			name:        "user with admin role can report content",
+			userRole:    rbac.Admin,
+			permission:  rbac.CreateReport,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with moderator role can report content",
+			userRole:    rbac.Moderator,
+			permission:  rbac.CreateReport,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with regular role can report content",
+			userRole:    rbac.Regular,
+			permission:  rbac.CreateReport,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with guest role cannot report content",
+			userRole:    rbac.Guest,
+			permission:  rbac.CreateReport,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPermission_ViewReport(t *testing.T) {
+	t.Parallel()
+	testCases := []testCase{
+		{
+			name:        "user with admin role can view reports",
+			userRole:    rbac.Admin,
+			permission:  rbac.ViewReport,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with moderator role can view reports",
+			userRole:    rbac.Moderator,
+			permission:  rbac.ViewReport,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with regular role cannot view reports",
+			userRole:    rbac.Regular,
+			permission:  rbac.ViewReport,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name:        "user with guest role cannot view reports",
+			userRole:    rbac.Guest,
+			permission:  rbac.ViewReport,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPermission_ResolveReport(t *testing.T) {
+	t.Parallel()
+	testCases := []testCase{
+		{
+			name:        "user with admin role can resolve reports",
+			userRole:    rbac.Admin,
+			permission:  rbac.ResolveReport,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with moderator role can resolve reports",
+			userRole:    rbac.Moderator,
+			permission:  rbac.ResolveReport,
+			expectedErr: nil,
+		},
+		{
+			name:        "user with regular role cannot resolve reports",
+			userRole:    rbac.Regular,
+			permission:  rbac.ResolveReport,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name:        "user with guest role cannot resolve reports",
+			userRole:    rbac.Guest,
+			permission:  rbac.ResolveReport,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{tc.userRole}}, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestMultipleRoles(t *testing.T) {
+	t.Parallel()
+	guard := rbac.New()
+	require.NoError(t, guard.Load(rbac.PolicyConfig{
+		Roles: map[rbac.UserRole]rbac.RoleConfig{
+			rbac.Regular: {Permissions: []rbac.Permission{rbac.ViewPost}},
+			"SUPPORT":    {Permissions: []rbac.Permission{rbac.AwardBadge}},
+		},
+	}))
+	testCases := []struct {
+		name        string
+		subject     rbac.Subject
+		permission  rbac.Permission
+		expectedErr error
+	}{
+		{
+			name:        "user has the permissions of their first role",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Regular, "SUPPORT"}},
+			permission:  rbac.ViewPost,
+			expectedErr: nil,
+		},
+		{
+			name:        "user has the permissions of their other roles",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Regular, "SUPPORT"}},
+			permission:  rbac.AwardBadge,
+			expectedErr: nil,
+		},
+		{
+			name:        "roles missing from the policy grant nothing",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{"DELETED_ROLE"}},
+			permission:  rbac.ViewPost,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name:        "user without roles has no permission",
+			subject:     rbac.Subject{},
+			permission:  rbac.ViewPost,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := guard.Authorize(tc.subject, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestNewUserRole(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, rbac.UserRole("SUPPORT_AGENT"), rbac.NewUserRole(" support_agent "))
+	assert.True(t, rbac.NewUserRole("support_agent").IsValid())
+	assert.False(t, rbac.UserRole("support").IsValid())
+	assert.False(t, rbac.UserRole("9LIVES").IsValid())
+	assert.False(t, rbac.UserRole("").IsValid())
+	assert.True(t, rbac.Moderator.IsBuiltin())
+	assert.False(t, rbac.UserRole("SUPPORT").IsBuiltin())
+}
+
+func TestScopes(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name        string
+		subject     rbac.Subject
+		permission  rbac.Permission
+		expectedErr error
+	}{
+		{
+			name:        "scoped regular user can use a permission of the role within the scopes",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Regular}, Scopes: []rbac.Permission{rbac.CreatePost}},
+			permission:  rbac.CreatePost,
+			expectedErr: nil,
+		},
+		{
+			name:        "scoped regular user cannot use a permission of the role outside the scopes",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Regular}, Scopes: []rbac.Permission{rbac.CreatePost}},
+			permission:  rbac.DeletePost,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name:        "scopes do not add permissions to the role",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Regular}, Scopes: []rbac.Permission{rbac.BanUser}},
+			permission:  rbac.BanUser,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name:        "scopes cap admins too",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Admin}, Scopes: []rbac.Permission{rbac.ViewUser}},
+			permission:  rbac.BanUser,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name:        "empty scopes allow nothing",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Admin}, Scopes: []rbac.Permission{}},
+			permission:  rbac.ViewUser,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.New().Authorize(tc.subject, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestUnverified(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name        string
+		subject     rbac.Subject
+		permission  rbac.Permission
+		expectedErr error
+	}{
+		{
+			name:        "unverified regular user can view posts",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Regular}, Unverified: true},
+			permission:  rbac.ViewPost,
+			expectedErr: nil,
+		},
+		{
+			name:        "unverified regular user cannot create posts",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Regular}, Unverified: true},
+			permission:  rbac.CreatePost,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name:        "unverified moderator cannot ban users",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Moderator}, Unverified: true},
+			permission:  rbac.BanUser,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name:        "unverified admin cannot ban users",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Admin}, Unverified: true},
+			permission:  rbac.BanUser,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name:        "unverified guest still cannot list users",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Guest}, Unverified: true},
+			permission:  rbac.ListUsers,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.New().Authorize(tc.subject, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestRequireMFA(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name        string
+		subject     rbac.Subject
+		permission  rbac.Permission
+		expectedErr error
+	}{
+		{
+			name:        "admin without mfa cannot ban users",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Admin}},
+			permission:  rbac.BanUser,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name:        "admin with mfa can ban users",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Admin}, MFA: true},
+			permission:  rbac.BanUser,
+			expectedErr: nil,
+		},
+		{
+			name:        "moderator without mfa cannot resolve reports",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Moderator}},
+			permission:  rbac.ResolveReport,
+			expectedErr: rbac.ErrUnauthorized,
+		},
+		{
+			name:        "moderator without mfa can still create posts",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Moderator}},
+			permission:  rbac.CreatePost,
+			expectedErr: nil,
+		},
+		{
+			name:        "regular user without mfa can create posts",
+			subject:     rbac.Subject{Roles: []rbac.UserRole{rbac.Regular}},
+			permission:  rbac.CreatePost,
+			expectedErr: nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := rbac.New(rbac.RequireMFA(rbac.Admin, rbac.Moderator)).Authorize(tc.subject, tc.permission)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+
+	t.Run("mfa is optional by default", func(t *testing.T) {
+		t.Parallel()
+		assert.NoError(t, rbac.New().Authorize(rbac.Subject{Roles: []rbac.UserRole{rbac.Admin}}, rbac.BanUser))
+	})
+}
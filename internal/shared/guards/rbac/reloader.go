@@ -0,0 +1,39 @@
+package rbac
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// PolicyReloader periodically reloads the policy of a guard from its source, so edits to the file
+// or table are put in force without a restart. A policy that fails to load or validate is logged
+// and the one in force is kept.
+type PolicyReloader struct {
+	guard    *RoleBasedGuard
+	source   PolicySource
+	interval time.Duration
+}
+
+func NewPolicyReloader(guard *RoleBasedGuard, source PolicySource, interval time.Duration) *PolicyReloader {
+	if guard == nil || source == nil {
+		panic("nil guard or policy source")
+	}
+	return &PolicyReloader{guard: guard, source: source, interval: interval}
+}
+
+// Run reloads the policy until ctx is cancelled
+func (r *PolicyReloader) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := r.guard.Reload(ctx, r.source); err != nil && ctx.Err() == nil {
+			log.Printf("rbac policy: %v", err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrBuiltinRole    = errors.New("built-in roles cannot be deleted")
+	ErrPolicyReadOnly = errors.New("roles can only be defined when the policy is kept in the database")
+)
+
+// PolicyStore is a policy source that can be written to, such as the database
+type PolicyStore interface {
+	PolicySource
+	SavePolicy(ctx context.Context, conf PolicyConfig) error
+}
+
+// RoleManager lets admins define roles. Changes are saved to the store and put in force on the
+// guard right away; other instances of the app pick them up when they reload the policy.
+type RoleManager struct {
+	guard *RoleBasedGuard
+	store PolicyStore
+}
+
+// NewRoleManager manages the roles of guard's policy. Without a store, roles are read-only and
+// changing them returns ErrPolicyReadOnly.
+func NewRoleManager(guard *RoleBasedGuard, store PolicyStore) *RoleManager {
+	if guard == nil {
+		panic("nil guard")
+	}
+	return &RoleManager{guard: guard, store: store}
+}
+
+// DefineRole creates role or replaces its definition
+func (m *RoleManager) DefineRole(ctx context.Context, role UserRole, rc RoleConfig) error {
+	return m.update(ctx, func(conf *PolicyConfig) error {
+		conf.Roles[role] = rc
+		return nil
+	})
+}
+
+// DeleteRole removes role from the policy. Built-in roles and roles other roles inherit can't be
+// deleted. Users keep the role, but it no longer grants them anything.
+func (m *RoleManager) DeleteRole(ctx context.Context, role UserRole) error {
+	if role.IsBuiltin() {
+		return fmt.Errorf("%w: %s", ErrBuiltinRole, role)
+	}
+	return m.update(ctx, func(conf *PolicyConfig) error {
+		if _, ok := conf.Roles[role]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownRole, role)
+		}
+		delete(conf.Roles, role)
+		return nil
+	})
+}
+
+// update changes the stored policy, falling back to the one in force when none is stored yet. The
+// changed policy is validated before it is saved.
+func (m *RoleManager) update(ctx context.Context, change func(conf *PolicyConfig) error) error {
+	if m.store == nil {
+		return ErrPolicyReadOnly
+	}
+	conf, err := m.store.LoadPolicy(ctx)
+	if errors.Is(err, ErrPolicyNotFound) {
+		conf, err = m.guard.Policy().Config(), nil
+	}
+	if err != nil {
+		return err
+	}
+	if conf.Roles == nil {
+		conf.Roles = map[UserRole]RoleConfig{}
+	}
+	if err := change(&conf); err != nil {
+		return err
+	}
+	if _, err := NewPolicyFromConfig(conf); err != nil {
+		return err
+	}
+	if err := m.store.SavePolicy(ctx, conf); err != nil {
+		return err
+	}
+	return m.guard.Load(conf)
+}
@@ -0,0 +1,70 @@
+package rbac_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleManager(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	support := rbac.RoleConfig{Inherits: []rbac.UserRole{rbac.Regular}, Permissions: []rbac.Permission{rbac.AwardBadge}}
+
+	t.Run("should save a defined role and put it in force", func(t *testing.T) {
+		t.Parallel()
+		guard := rbac.New()
+		store := rbac.NewMemoryPolicyStore()
+		manager := rbac.NewRoleManager(guard, store)
+
+		require.NoError(t, manager.DefineRole(ctx, "SUPPORT", support))
+		assert.NoError(t, guard.Authorize(rbac.Subject{Roles: []rbac.UserRole{"SUPPORT"}}, rbac.AwardBadge))
+		assert.NoError(t, guard.Authorize(rbac.Subject{Roles: []rbac.UserRole{"SUPPORT"}}, rbac.CreatePost))
+
+		saved, err := store.LoadPolicy(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, saved.Roles, rbac.UserRole("SUPPORT"))
+		assert.Contains(t, saved.Roles, rbac.Admin, "the policy in force is saved along with the new role")
+	})
+
+	t.Run("should delete a defined role", func(t *testing.T) {
+		t.Parallel()
+		guard := rbac.New()
+		manager := rbac.NewRoleManager(guard, rbac.NewMemoryPolicyStore())
+
+		require.NoError(t, manager.DefineRole(ctx, "SUPPORT", support))
+		require.NoError(t, manager.DeleteRole(ctx, "SUPPORT"))
+		assert.ErrorIs(t, guard.Authorize(rbac.Subject{Roles: []rbac.UserRole{"SUPPORT"}}, rbac.AwardBadge), rbac.ErrUnauthorized)
+		assert.ErrorIs(t, manager.DeleteRole(ctx, "SUPPORT"), rbac.ErrUnknownRole)
+	})
+
+	t.Run("should not delete built-in roles", func(t *testing.T) {
+		t.Parallel()
+		manager := rbac.NewRoleManager(rbac.New(), rbac.NewMemoryPolicyStore())
+		assert.ErrorIs(t, manager.DeleteRole(ctx, rbac.Moderator), rbac.ErrBuiltinRole)
+	})
+
+	t.Run("should reject roles that make the policy invalid", func(t *testing.T) {
+		t.Parallel()
+		guard := rbac.New()
+		store := rbac.NewMemoryPolicyStore()
+		manager := rbac.NewRoleManager(guard, store)
+
+		err := manager.DefineRole(ctx, "SUPPORT", rbac.RoleConfig{Inherits: []rbac.UserRole{"UNDEFINED"}})
+		assert.ErrorIs(t, err, rbac.ErrInvalidPolicy)
+		_, err = store.LoadPolicy(ctx)
+		assert.ErrorIs(t, err, rbac.ErrPolicyNotFound, "invalid policies are not saved")
+		_, ok := guard.Policy().Role("SUPPORT")
+		assert.False(t, ok)
+	})
+
+	t.Run("should not change roles without a store", func(t *testing.T) {
+		t.Parallel()
+		manager := rbac.NewRoleManager(rbac.New(), nil)
+		assert.ErrorIs(t, manager.DefineRole(ctx, "SUPPORT", support), rbac.ErrPolicyReadOnly)
+		assert.ErrorIs(t, manager.DeleteRole(ctx, "SUPPORT"), rbac.ErrPolicyReadOnly)
+	})
+}
@@ -1,5 +1,11 @@
 package rbac
 
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
 type UserRole string
 
 const (
@@ -9,6 +15,27 @@ const (
 	Guest     UserRole = "GUEST"
 )
 
+// builtinRoles are the roles the app relies on. Other roles are defined by admins.
+var builtinRoles = []UserRole{Admin, Regular, Moderator, Guest}
+
+var roleName = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,31}$`)
+
+// NewUserRole normalizes the name of a role, so that "editor" and " Editor" are the same role
+func NewUserRole(name string) UserRole {
+	return UserRole(strings.ToUpper(strings.TrimSpace(name)))
+}
+
 func (r UserRole) String() string {
 	return string(r)
 }
+
+// IsValid tells whether r is a well-formed role name: upper case letters, digits and underscores,
+// starting with a letter. It doesn't tell whether the policy defines the role.
+func (r UserRole) IsValid() bool {
+	return roleName.MatchString(string(r))
+}
+
+// IsBuiltin tells whether r is one of the roles the app relies on, which can't be deleted
+func (r UserRole) IsBuiltin() bool {
+	return slices.Contains(builtinRoles, r)
+}
@@ -0,0 +1,54 @@
+package rbac
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PolicySource is where the policy is loaded from, such as a file or a database table. It returns
+// ErrPolicyNotFound when there is no policy to load.
+type PolicySource interface {
+	LoadPolicy(ctx context.Context) (PolicyConfig, error)
+}
+
+// FileSource loads the policy from a JSON file, when its name ends in .json, or a YAML one
+type FileSource struct {
+	path string
+}
+
+func NewFileSource(path string) *FileSource {
+	return &FileSource{path: path}
+}
+
+func (f *FileSource) LoadPolicy(ctx context.Context) (PolicyConfig, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return PolicyConfig{}, fmt.Errorf("%w: %s", ErrPolicyNotFound, f.path)
+	}
+	if err != nil {
+		return PolicyConfig{}, err
+	}
+	var conf PolicyConfig
+	if strings.EqualFold(filepath.Ext(f.path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&conf)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&conf)
+	}
+	if err != nil {
+		return PolicyConfig{}, fmt.Errorf("%w: %s: %v", ErrInvalidPolicy, f.path, err)
+	}
+	return conf, nil
+}
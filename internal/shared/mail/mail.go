@@ -0,0 +1,59 @@
+package mail
+
+// Sending email. Mailer is the extension point; SMTPMailer delivers through a mail server,
+// FileMailer drops emails into a directory for local development and MemoryMailer keeps them
+// for tests.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/config"
+)
+
+var ErrNoRecipient = errors.New("email has no recipient")
+
+type Message struct {
+	To      string
+	Subject string
+	// Body is plain text
+	Body string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewMailerFromEnv sends through SMTP_HOST when it is set, and into MAIL_DROP_DIR otherwise
+func NewMailerFromEnv() Mailer {
+	env := config.NewEnv()
+	if env.SMTPHost() != "" {
+		return NewSMTPMailer(env.SMTPHost(), env.SMTPPort(), env.SMTPUsername(), env.SMTPPassword(), env.MailFrom())
+	}
+	return NewFileMailer(env.MailDropDir(), env.MailFrom())
+}
+
+// format renders msg as an RFC 5322 email
+func format(from string, msg Message, date time.Time) ([]byte, error) {
+	if msg.To == "" {
+		return nil, ErrNoRecipient
+	}
+	for _, header := range []string{from, msg.To, msg.Subject} {
+		if strings.ContainsAny(header, "\r\n") {
+			return nil, fmt.Errorf("email header contains a line break: %q", header)
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String()), nil
+}
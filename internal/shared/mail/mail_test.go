@@ -0,0 +1,60 @@
+package mail_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iammrsea/social-app/internal/shared/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailer(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer := mail.NewFileMailer(dir, "no-reply@example.com")
+
+	err := mailer.Send(ctx, mail.Message{To: "johndoe@example.com", Subject: "Hello", Body: "line one\nline two"})
+	require.NoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.True(t, strings.HasSuffix(files[0].Name(), ".eml"))
+	content, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "From: no-reply@example.com\r\n")
+	assert.Contains(t, string(content), "To: johndoe@example.com\r\n")
+	assert.Contains(t, string(content), "Subject: Hello\r\n")
+	assert.True(t, strings.HasSuffix(string(content), "\r\n\r\nline one\r\nline two"))
+
+	t.Run("should refuse header injection", func(t *testing.T) {
+		err := mailer.Send(ctx, mail.Message{To: "johndoe@example.com", Subject: "Hello\r\nBcc: janedoe@example.com"})
+		assert.Error(t, err)
+	})
+	t.Run("should refuse emails without a recipient", func(t *testing.T) {
+		err := mailer.Send(ctx, mail.Message{Subject: "Hello"})
+		assert.ErrorIs(t, err, mail.ErrNoRecipient)
+	})
+}
+
+func TestMemoryMailer(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	mailer := mail.NewMemoryMailer()
+
+	require.NoError(t, mailer.Send(ctx, mail.Message{To: "johndoe@example.com", Subject: "First"}))
+	require.NoError(t, mailer.Send(ctx, mail.Message{To: "janedoe@example.com", Subject: "Second"}))
+	require.NoError(t, mailer.Send(ctx, mail.Message{To: "johndoe@example.com", Subject: "Third"}))
+
+	assert.Len(t, mailer.Sent(), 3)
+	last, ok := mailer.Last("johndoe@example.com")
+	assert.True(t, ok)
+	assert.Equal(t, "Third", last.Subject)
+	_, ok = mailer.Last("nobody@example.com")
+	assert.False(t, ok)
+}
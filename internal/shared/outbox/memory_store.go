@@ -0,0 +1,75 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	message      Message
+	dispatchedAt time.Time
+	lastError    string
+}
+
+// MemoryStore is an outbox kept in memory, for the in-memory repositories and for tests
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries []*memoryEntry
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Append(ctx context.Context, messages ...Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, msg := range messages {
+		s.entries = append(s.entries, &memoryEntry{message: msg})
+	}
+	return nil
+}
+
+func (s *MemoryStore) Pending(ctx context.Context, limit int) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	messages := []Message{}
+	for _, entry := range s.entries {
+		if len(messages) == limit {
+			break
+		}
+		if entry.dispatchedAt.IsZero() && entry.message.Attempts < MaxDeliveryAttempts {
+			messages = append(messages, entry.message)
+		}
+	}
+	return messages, nil
+}
+
+func (s *MemoryStore) MarkDispatched(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry := s.find(id); entry != nil {
+		entry.dispatchedAt = time.Now()
+	}
+	return nil
+}
+
+func (s *MemoryStore) MarkFailed(ctx context.Context, id string, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry := s.find(id); entry != nil {
+		entry.message.Attempts++
+		entry.lastError = reason
+	}
+	return nil
+}
+
+func (s *MemoryStore) find(id string) *memoryEntry {
+	for _, entry := range s.entries {
+		if entry.message.Id == id {
+			return entry
+		}
+	}
+	return nil
+}
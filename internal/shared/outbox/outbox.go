@@ -0,0 +1,93 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/lucsky/cuid"
+)
+
+// MaxDeliveryAttempts is how many times the relay tries to publish a message before giving up on it.
+// Messages that reach it stay in the outbox, with their last error, for someone to look at.
+const MaxDeliveryAttempts = 10
+
+var ErrUnknownEvent = errors.New("no decoder registered for event")
+
+// Message is an event waiting in the outbox to be published
+type Message struct {
+	Id         string
+	EventName  string
+	Payload    []byte
+	OccurredAt time.Time
+	CreatedAt  time.Time
+	Attempts   int
+}
+
+// NewMessages serializes events so they can be written to the outbox in the same transaction as the
+// aggregate that raised them
+func NewMessages(evts []events.Event) ([]Message, error) {
+	messages := make([]Message, 0, len(evts))
+	for _, event := range evts {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return nil, fmt.Errorf("outbox: encoding %s: %w", event.EventName(), err)
+		}
+		messages = append(messages, Message{
+			Id:         cuid.New(),
+			EventName:  event.EventName(),
+			Payload:    payload,
+			OccurredAt: event.OccurredAt(),
+			CreatedAt:  time.Now(),
+		})
+	}
+	return messages, nil
+}
+
+// Store is where the relay reads pending messages from. Writing to the outbox is left to the
+// repositories, since it must happen inside their transactions.
+type Store interface {
+	// Pending returns up to limit messages that are neither dispatched nor out of attempts, oldest first
+	Pending(ctx context.Context, limit int) ([]Message, error)
+	MarkDispatched(ctx context.Context, id string) error
+	// MarkFailed counts a failed delivery attempt and keeps the reason
+	MarkFailed(ctx context.Context, id string, reason string) error
+}
+
+type decoder func(payload []byte) (events.Event, error)
+
+// Registry turns outbox messages back into the events they were created from
+type Registry struct {
+	decoders map[string]decoder
+}
+
+func NewRegistry() *Registry {
+	return &Registry{decoders: map[string]decoder{}}
+}
+
+// Register makes events of type E decodable by their name
+func Register[E events.Event](r *Registry) {
+	var zero E
+	r.decoders[zero.EventName()] = func(payload []byte) (events.Event, error) {
+		var event E
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, err
+		}
+		return event, nil
+	}
+}
+
+func (r *Registry) Decode(msg Message) (events.Event, error) {
+	decode, ok := r.decoders[msg.EventName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, msg.EventName)
+	}
+	event, err := decode(msg.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("outbox: decoding %s: %w", msg.EventName, err)
+	}
+	return event, nil
+}
@@ -0,0 +1,103 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 100
+)
+
+type RelayOption func(*Relay)
+
+func WithPollInterval(interval time.Duration) RelayOption {
+	return func(r *Relay) {
+		r.pollInterval = interval
+	}
+}
+
+func WithBatchSize(size int) RelayOption {
+	return func(r *Relay) {
+		r.batchSize = size
+	}
+}
+
+// Relay moves messages from the outbox to the event bus. Delivery is at least once: a message is
+// marked as dispatched only after it was published, so a crash in between publishes it again and
+// subscribers must tolerate duplicates.
+type Relay struct {
+	store        Store
+	registry     *Registry
+	publisher    eventbus.Publisher
+	pollInterval time.Duration
+	batchSize    int
+}
+
+func NewRelay(store Store, registry *Registry, publisher eventbus.Publisher, opts ...RelayOption) *Relay {
+	if store == nil || registry == nil || publisher == nil {
+		panic("nil outbox store, event registry or publisher")
+	}
+	relay := &Relay{
+		store:        store,
+		registry:     registry,
+		publisher:    publisher,
+		pollInterval: defaultPollInterval,
+		batchSize:    defaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(relay)
+	}
+	return relay
+}
+
+// Run polls the outbox until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.DispatchPending(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("outbox: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending publishes one batch of pending messages and returns how many were dispatched.
+// It stops at the first message that fails to publish, so later events of the batch are not
+// delivered ahead of it. Messages that cannot be decoded are skipped, as retrying won't fix them.
+func (r *Relay) DispatchPending(ctx context.Context) (int, error) {
+	messages, err := r.store.Pending(ctx, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	dispatched := 0
+	for _, msg := range messages {
+		event, err := r.registry.Decode(msg)
+		if err != nil {
+			if err := r.store.MarkFailed(ctx, msg.Id, err.Error()); err != nil {
+				return dispatched, err
+			}
+			continue
+		}
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			if markErr := r.store.MarkFailed(ctx, msg.Id, err.Error()); markErr != nil {
+				return dispatched, markErr
+			}
+			return dispatched, err
+		}
+		if err := r.store.MarkDispatched(ctx, msg.Id); err != nil {
+			return dispatched, err
+		}
+		dispatched++
+	}
+	return dispatched, nil
+}
@@ -0,0 +1,192 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/iammrsea/social-app/internal/shared/outbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type somethingHappened struct {
+	Value    string
+	Happened time.Time
+}
+
+func (e somethingHappened) EventName() string     { return "test.something_happened" }
+func (e somethingHappened) OccurredAt() time.Time { return e.Happened }
+
+type unregisteredEvent struct{}
+
+func (e unregisteredEvent) EventName() string     { return "test.unregistered" }
+func (e unregisteredEvent) OccurredAt() time.Time { return time.Time{} }
+
+func newRegistry() *outbox.Registry {
+	registry := outbox.NewRegistry()
+	outbox.Register[somethingHappened](registry)
+	return registry
+}
+
+func appendEvents(t *testing.T, store *outbox.MemoryStore, evts ...events.Event) []outbox.Message {
+	t.Helper()
+	messages, err := outbox.NewMessages(evts)
+	require.NoError(t, err)
+	require.NoError(t, store.Append(context.Background(), messages...))
+	return messages
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should decode a message back into its event", func(t *testing.T) {
+		t.Parallel()
+		happened := time.Now().UTC().Truncate(time.Millisecond)
+		messages, err := outbox.NewMessages([]events.Event{somethingHappened{Value: "a", Happened: happened}})
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.Equal(t, "test.something_happened", messages[0].EventName)
+		assert.Equal(t, happened, messages[0].OccurredAt)
+
+		event, err := newRegistry().Decode(messages[0])
+		require.NoError(t, err)
+		assert.Equal(t, somethingHappened{Value: "a", Happened: happened}, event)
+	})
+
+	t.Run("should reject events that were not registered", func(t *testing.T) {
+		t.Parallel()
+		messages, err := outbox.NewMessages([]events.Event{unregisteredEvent{}})
+		require.NoError(t, err)
+
+		_, err = newRegistry().Decode(messages[0])
+		assert.ErrorIs(t, err, outbox.ErrUnknownEvent)
+	})
+}
+
+func TestRelay(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should publish pending messages in order and mark them as dispatched", func(t *testing.T) {
+		t.Parallel()
+		store := outbox.NewMemoryStore()
+		appendEvents(t, store, somethingHappened{Value: "a"}, somethingHappened{Value: "b"})
+		bus := eventbus.New()
+		var received []string
+		eventbus.SubscribeTo(bus, func(ctx context.Context, event somethingHappened) error {
+			received = append(received, event.Value)
+			return nil
+		})
+		relay := outbox.NewRelay(store, newRegistry(), bus)
+
+		dispatched, err := relay.DispatchPending(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, dispatched)
+		assert.Equal(t, []string{"a", "b"}, received)
+
+		pending, err := store.Pending(context.Background(), 10)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	t.Run("should keep a message pending and stop the batch when publishing fails", func(t *testing.T) {
+		t.Parallel()
+		store := outbox.NewMemoryStore()
+		messages := appendEvents(t, store, somethingHappened{Value: "a"}, somethingHappened{Value: "b"})
+		bus := eventbus.New()
+		fail := true
+		var received []string
+		eventbus.SubscribeTo(bus, func(ctx context.Context, event somethingHappened) error {
+			if fail {
+				return errors.New("subscriber is down")
+			}
+			received = append(received, event.Value)
+			return nil
+		})
+		relay := outbox.NewRelay(store, newRegistry(), bus)
+
+		dispatched, err := relay.DispatchPending(context.Background())
+		assert.Error(t, err)
+		assert.Zero(t, dispatched)
+
+		pending, err := store.Pending(context.Background(), 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 2)
+		assert.Equal(t, messages[0].Id, pending[0].Id)
+		assert.Equal(t, 1, pending[0].Attempts)
+		assert.Zero(t, pending[1].Attempts)
+
+		fail = false
+		dispatched, err = relay.DispatchPending(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, dispatched)
+		assert.Equal(t, []string{"a", "b"}, received)
+	})
+
+	t.Run("should skip messages it cannot decode", func(t *testing.T) {
+		t.Parallel()
+		store := outbox.NewMemoryStore()
+		appendEvents(t, store, unregisteredEvent{}, somethingHappened{Value: "a"})
+		bus := eventbus.New()
+		var received []string
+		eventbus.SubscribeTo(bus, func(ctx context.Context, event somethingHappened) error {
+			received = append(received, event.Value)
+			return nil
+		})
+		relay := outbox.NewRelay(store, newRegistry(), bus)
+
+		dispatched, err := relay.DispatchPending(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, dispatched)
+		assert.Equal(t, []string{"a"}, received)
+	})
+
+	t.Run("should give up on a message after the maximum number of attempts", func(t *testing.T) {
+		t.Parallel()
+		store := outbox.NewMemoryStore()
+		appendEvents(t, store, unregisteredEvent{})
+		relay := outbox.NewRelay(store, newRegistry(), eventbus.New())
+
+		for range outbox.MaxDeliveryAttempts {
+			_, err := relay.DispatchPending(context.Background())
+			require.NoError(t, err)
+		}
+		pending, err := store.Pending(context.Background(), 10)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	t.Run("should dispatch until the context is cancelled", func(t *testing.T) {
+		t.Parallel()
+		store := outbox.NewMemoryStore()
+		bus := eventbus.New()
+		received := make(chan string, 1)
+		eventbus.SubscribeTo(bus, func(ctx context.Context, event somethingHappened) error {
+			received <- event.Value
+			return nil
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			outbox.NewRelay(store, newRegistry(), bus, outbox.WithPollInterval(10*time.Millisecond)).Run(ctx)
+			close(done)
+		}()
+
+		appendEvents(t, store, somethingHappened{Value: "a"})
+		select {
+		case value := <-received:
+			assert.Equal(t, "a", value)
+		case <-time.After(time.Second):
+			t.Fatal("relay did not publish the message")
+		}
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("relay did not stop")
+		}
+	})
+}
@@ -0,0 +1,157 @@
+// Package migrate applies and reverts versioned schema migrations. The storage engines provide a
+// Driver that knows how to run their migrations and where to record the applied ones; the Migrator
+// decides which to run and keeps two instances from migrating at the same time.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	ErrDuplicateVersion = errors.New("two migrations have the same version")
+	ErrUnknownVersion   = errors.New("migration is unknown to this build")
+)
+
+// Migration is a step of the schema, identified by its version. Versions are never reused or
+// changed once released.
+type Migration struct {
+	Version int
+	Name    string
+}
+
+// Status tells whether a migration was applied. AppliedAt is zero for pending migrations.
+type Status struct {
+	Migration
+	AppliedAt time.Time
+	// Unknown is set for migrations applied by a newer build, which this one can't revert
+	Unknown bool
+}
+
+func (s Status) Applied() bool {
+	return !s.AppliedAt.IsZero()
+}
+
+// Driver runs the migrations of a storage engine
+type Driver interface {
+	// Migrations are the migrations the driver knows, in any order
+	Migrations() []Migration
+	// Lock blocks until no other instance migrates the database and returns what releases the lock
+	Lock(ctx context.Context) (unlock func(ctx context.Context) error, err error)
+	// Applied returns when each applied migration was applied, by version
+	Applied(ctx context.Context) (map[int]time.Time, error)
+	// Apply runs the migration and records it as applied
+	Apply(ctx context.Context, migration Migration) error
+	// Revert undoes the migration and forgets it was applied
+	Revert(ctx context.Context, migration Migration) error
+}
+
+type Migrator struct {
+	driver Driver
+}
+
+func New(driver Driver) *Migrator {
+	if driver == nil {
+		panic("nil migration driver")
+	}
+	return &Migrator{driver: driver}
+}
+
+// Up applies the pending migrations in order of version and returns those it applied. Migrations
+// applied by a newer build are left alone, so instances of the previous build can still start
+// during a rollout.
+func (m *Migrator) Up(ctx context.Context) (applied []Migration, err error) {
+	err = m.locked(ctx, func(statuses []Status) error {
+		for _, status := range statuses {
+			if status.Applied() {
+				continue
+			}
+			if err := m.driver.Apply(ctx, status.Migration); err != nil {
+				return fmt.Errorf("applying migration %d %s: %w", status.Version, status.Name, err)
+			}
+			applied = append(applied, status.Migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the last steps applied migrations, newest first, and returns those it reverted
+func (m *Migrator) Down(ctx context.Context, steps int) (reverted []Migration, err error) {
+	err = m.locked(ctx, func(statuses []Status) error {
+		for i := len(statuses) - 1; i >= 0 && len(reverted) < steps; i-- {
+			status := statuses[i]
+			if !status.Applied() {
+				continue
+			}
+			if status.Unknown {
+				return fmt.Errorf("%w: %d", ErrUnknownVersion, status.Version)
+			}
+			if err := m.driver.Revert(ctx, status.Migration); err != nil {
+				return fmt.Errorf("reverting migration %d %s: %w", status.Version, status.Name, err)
+			}
+			reverted = append(reverted, status.Migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status lists the migrations in order of version and tells which were applied
+func (m *Migrator) Status(ctx context.Context) (statuses []Status, err error) {
+	err = m.locked(ctx, func(s []Status) error {
+		statuses = s
+		return nil
+	})
+	return statuses, err
+}
+
+// locked runs fn with the status of every migration while holding the migration lock
+func (m *Migrator) locked(ctx context.Context, fn func(statuses []Status) error) (err error) {
+	unlock, err := m.driver.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("taking the migration lock: %w", err)
+	}
+	defer func() {
+		if unlockErr := unlock(context.WithoutCancel(ctx)); unlockErr != nil {
+			err = errors.Join(err, fmt.Errorf("releasing the migration lock: %w", unlockErr))
+		}
+	}()
+	statuses, err := m.statuses(ctx)
+	if err != nil {
+		return err
+	}
+	return fn(statuses)
+}
+
+func (m *Migrator) statuses(ctx context.Context) ([]Status, error) {
+	migrations := slices.Clone(m.driver.Migrations())
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return a.Version - b.Version
+	})
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateVersion, migrations[i].Version)
+		}
+	}
+	applied, err := m.driver.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(migrations)+len(applied))
+	for _, migration := range migrations {
+		statuses = append(statuses, Status{Migration: migration, AppliedAt: applied[migration.Version]})
+		delete(applied, migration.Version)
+	}
+	// What is left was applied by a newer build
+	for version, appliedAt := range applied {
+		statuses = append(statuses, Status{Migration: Migration{Version: version}, AppliedAt: appliedAt, Unknown: true})
+	}
+	slices.SortFunc(statuses, func(a, b Status) int {
+		return a.Version - b.Version
+	})
+	return statuses, nil
+}
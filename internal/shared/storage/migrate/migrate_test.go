@@ -0,0 +1,167 @@
+package migrate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/storage/migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver keeps the applied migrations in memory and records what it was asked to do
+type fakeDriver struct {
+	migrations []migrate.Migration
+	applied    map[int]time.Time
+	failOn     int
+	locked     bool
+	calls      []string
+}
+
+func newFakeDriver(migrations ...migrate.Migration) *fakeDriver {
+	return &fakeDriver{migrations: migrations, applied: map[int]time.Time{}}
+}
+
+func (d *fakeDriver) Migrations() []migrate.Migration {
+	return d.migrations
+}
+
+func (d *fakeDriver) Lock(ctx context.Context) (func(ctx context.Context) error, error) {
+	if d.locked {
+		return nil, errors.New("already locked")
+	}
+	d.locked = true
+	return func(ctx context.Context) error {
+		d.locked = false
+		return nil
+	}, nil
+}
+
+func (d *fakeDriver) Applied(ctx context.Context) (map[int]time.Time, error) {
+	applied := make(map[int]time.Time, len(d.applied))
+	for version, appliedAt := range d.applied {
+		applied[version] = appliedAt
+	}
+	return applied, nil
+}
+
+func (d *fakeDriver) Apply(ctx context.Context, migration migrate.Migration) error {
+	if migration.Version == d.failOn {
+		return errors.New("boom")
+	}
+	d.calls = append(d.calls, "up "+migration.Name)
+	d.applied[migration.Version] = time.Now()
+	return nil
+}
+
+func (d *fakeDriver) Revert(ctx context.Context, migration migrate.Migration) error {
+	if migration.Version == d.failOn {
+		return errors.New("boom")
+	}
+	d.calls = append(d.calls, "down "+migration.Name)
+	delete(d.applied, migration.Version)
+	return nil
+}
+
+var (
+	first  = migrate.Migration{Version: 1, Name: "first"}
+	second = migrate.Migration{Version: 2, Name: "second"}
+	third  = migrate.Migration{Version: 3, Name: "third"}
+)
+
+func TestMigrator(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should apply the pending migrations in order of version", func(t *testing.T) {
+		t.Parallel()
+		driver := newFakeDriver(third, first, second)
+		driver.applied[1] = time.Now()
+
+		applied, err := migrate.New(driver).Up(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []migrate.Migration{second, third}, applied)
+		assert.Equal(t, []string{"up second", "up third"}, driver.calls)
+		assert.False(t, driver.locked)
+
+		applied, err = migrate.New(driver).Up(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, applied)
+	})
+
+	t.Run("should stop at the first migration that fails", func(t *testing.T) {
+		t.Parallel()
+		driver := newFakeDriver(first, second, third)
+		driver.failOn = 2
+
+		applied, err := migrate.New(driver).Up(ctx)
+		require.ErrorContains(t, err, "applying migration 2 second: boom")
+		assert.Equal(t, []migrate.Migration{first}, applied)
+		assert.False(t, driver.locked)
+	})
+
+	t.Run("should revert the last applied migrations, newest first", func(t *testing.T) {
+		t.Parallel()
+		driver := newFakeDriver(first, second, third)
+		driver.applied[1] = time.Now()
+		driver.applied[2] = time.Now()
+
+		reverted, err := migrate.New(driver).Down(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []migrate.Migration{second}, reverted)
+
+		reverted, err = migrate.New(driver).Down(ctx, 5)
+		require.NoError(t, err)
+		assert.Equal(t, []migrate.Migration{first}, reverted)
+		assert.Empty(t, driver.applied)
+	})
+
+	t.Run("should list the migrations with when they were applied", func(t *testing.T) {
+		t.Parallel()
+		driver := newFakeDriver(second, first)
+		appliedAt := time.Now()
+		driver.applied[1] = appliedAt
+
+		statuses, err := migrate.New(driver).Status(ctx)
+		require.NoError(t, err)
+		require.Len(t, statuses, 2)
+		assert.Equal(t, migrate.Status{Migration: first, AppliedAt: appliedAt}, statuses[0])
+		assert.True(t, statuses[0].Applied())
+		assert.Equal(t, second, statuses[1].Migration)
+		assert.False(t, statuses[1].Applied())
+	})
+
+	t.Run("should leave migrations of a newer build alone", func(t *testing.T) {
+		t.Parallel()
+		driver := newFakeDriver(first)
+		driver.applied[1] = time.Now()
+		driver.applied[7] = time.Now()
+
+		statuses, err := migrate.New(driver).Status(ctx)
+		require.NoError(t, err)
+		require.Len(t, statuses, 2)
+		assert.True(t, statuses[1].Unknown)
+		assert.Equal(t, 7, statuses[1].Version)
+
+		applied, err := migrate.New(driver).Up(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, applied)
+
+		_, err = migrate.New(driver).Down(ctx, 1)
+		require.ErrorIs(t, err, migrate.ErrUnknownVersion)
+		assert.Contains(t, driver.applied, 7)
+		assert.False(t, driver.locked)
+	})
+
+	t.Run("should reject two migrations with the same version", func(t *testing.T) {
+		t.Parallel()
+		driver := newFakeDriver(first, migrate.Migration{Version: 1, Name: "again"})
+
+		_, err := migrate.New(driver).Up(ctx)
+		require.ErrorIs(t, err, migrate.ErrDuplicateVersion)
+		assert.Empty(t, driver.calls)
+		assert.False(t, driver.locked)
+	})
+}
@@ -0,0 +1,116 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth/apikeys"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const apiKeysCollection = "api_keys"
+
+// apiKeyDocument represents how an API key is stored in MongoDB
+type apiKeyDocument struct {
+	ID        string     `bson:"_id"`
+	UserID    string     `bson:"user_id"`
+	Name      string     `bson:"name"`
+	Prefix    string     `bson:"prefix"`
+	KeyHash   string     `bson:"key_hash"`
+	Scopes    []string   `bson:"scopes"`
+	CreatedAt time.Time  `bson:"created_at"`
+	ExpiresAt *time.Time `bson:"expires_at"`
+	RevokedAt *time.Time `bson:"revoked_at"`
+}
+
+func (doc apiKeyDocument) toAPIKey() apikeys.APIKey {
+	key := apikeys.APIKey{
+		Id:        doc.ID,
+		UserId:    doc.UserID,
+		Name:      doc.Name,
+		Prefix:    doc.Prefix,
+		KeyHash:   doc.KeyHash,
+		CreatedAt: doc.CreatedAt,
+		ExpiresAt: doc.ExpiresAt,
+		RevokedAt: doc.RevokedAt,
+	}
+	for _, scope := range doc.Scopes {
+		key.Scopes = append(key.Scopes, rbac.Permission(scope))
+	}
+	return key
+}
+
+// APIKeyStore implements apikeys.Store on top of the api_keys collection
+type APIKeyStore struct {
+	collection *mongo.Collection
+}
+
+func NewAPIKeyStore(db *mongo.Database) *APIKeyStore {
+	return &APIKeyStore{collection: db.Collection(apiKeysCollection)}
+}
+
+func (s *APIKeyStore) Save(ctx context.Context, key apikeys.APIKey) error {
+	doc := apiKeyDocument{
+		ID:        key.Id,
+		UserID:    key.UserId,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		KeyHash:   key.KeyHash,
+		CreatedAt: key.CreatedAt,
+		ExpiresAt: key.ExpiresAt,
+		RevokedAt: key.RevokedAt,
+	}
+	for _, scope := range key.Scopes {
+		doc.Scopes = append(doc.Scopes, string(scope))
+	}
+	_, err := s.collection.InsertOne(ctx, doc)
+	return err
+}
+
+func (s *APIKeyStore) FindByHash(ctx context.Context, keyHash string) (apikeys.APIKey, error) {
+	var doc apiKeyDocument
+	err := s.collection.FindOne(ctx, bson.M{"key_hash": keyHash}).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return apikeys.APIKey{}, apikeys.ErrAPIKeyNotFound
+		}
+		return apikeys.APIKey{}, err
+	}
+	return doc.toAPIKey(), nil
+}
+
+func (s *APIKeyStore) ListByUser(ctx context.Context, userId string) ([]apikeys.APIKey, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := s.collection.Find(ctx, bson.M{"user_id": userId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	keys := []apikeys.APIKey{}
+	for cursor.Next(ctx) {
+		var doc apiKeyDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		keys = append(keys, doc.toAPIKey())
+	}
+	return keys, cursor.Err()
+}
+
+func (s *APIKeyStore) Revoke(ctx context.Context, userId, keyId string, now time.Time) error {
+	result, err := s.collection.UpdateOne(ctx,
+		bson.M{"_id": keyId, "user_id": userId},
+		bson.A{bson.M{"$set": bson.M{"revoked_at": bson.M{"$ifNull": bson.A{"$revoked_at", now}}}}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return apikeys.ErrAPIKeyNotFound
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/audit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const auditCollection = "audit_log"
+
+// auditDocument represents how an audit log entry is stored in MongoDB
+type auditDocument struct {
+	ID         string    `bson:"_id"`
+	Kind       string    `bson:"kind"`
+	ActorID    string    `bson:"actor_id"`
+	Target     string    `bson:"target"`
+	Permission string    `bson:"permission"`
+	Details    string    `bson:"details"`
+	Outcome    string    `bson:"outcome"`
+	Reason     string    `bson:"reason"`
+	RequestID  string    `bson:"request_id"`
+	At         time.Time `bson:"at"`
+}
+
+func (doc auditDocument) toEntry() audit.Entry {
+	return audit.Entry{
+		Id:         doc.ID,
+		Kind:       audit.Kind(doc.Kind),
+		ActorId:    doc.ActorID,
+		Target:     doc.Target,
+		Permission: doc.Permission,
+		Details:    doc.Details,
+		Outcome:    audit.Outcome(doc.Outcome),
+		Reason:     doc.Reason,
+		RequestId:  doc.RequestID,
+		At:         doc.At,
+	}
+}
+
+// AuditStore implements audit.Store on top of the audit_log collection
+type AuditStore struct {
+	collection *mongo.Collection
+}
+
+func NewAuditStore(db *mongo.Database) *AuditStore {
+	return &AuditStore{collection: db.Collection(auditCollection)}
+}
+
+func (s *AuditStore) Append(ctx context.Context, entry audit.Entry) error {
+	_, err := s.collection.InsertOne(ctx, auditDocument{
+		ID:         entry.Id,
+		Kind:       entry.Kind.String(),
+		ActorID:    entry.ActorId,
+		Target:     entry.Target,
+		Permission: entry.Permission,
+		Details:    entry.Details,
+		Outcome:    entry.Outcome.String(),
+		Reason:     entry.Reason,
+		RequestID:  entry.RequestId,
+		At:         entry.At,
+	})
+	return err
+}
+
+func (s *AuditStore) Search(ctx context.Context, opts audit.SearchOptions) ([]audit.Entry, bool, error) {
+	filter := bson.M{}
+	if opts.After != "" {
+		at, err := time.Parse(time.RFC3339Nano, opts.After)
+		if err != nil {
+			return nil, false, err
+		}
+		filter["at"] = bson.M{"$lt": at}
+	}
+	for field, value := range map[string]string{
+		"actor_id":   opts.ActorId,
+		"target":     opts.Target,
+		"permission": opts.Permission,
+		"kind":       opts.Kind.String(),
+		"outcome":    opts.Outcome.String(),
+	} {
+		if value != "" {
+			filter[field] = value
+		}
+	}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "at", Value: -1}}).
+		SetLimit(int64(opts.First + 1)) //Fetch one more to determine if there are more results
+	cursor, err := s.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, false, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []auditDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, false, err
+	}
+	hasNext := len(docs) > int(opts.First)
+	if hasNext {
+		docs = docs[:opts.First]
+	}
+	entries := make([]audit.Entry, len(docs))
+	for i, doc := range docs {
+		entries[i] = doc.toEntry()
+	}
+	return entries, hasNext, nil
+}
@@ -0,0 +1,100 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth/mfa"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const mfaEnrollmentsCollection = "mfa_enrollments"
+
+// mfaEnrollmentDocument represents how a two-factor enrollment is stored in MongoDB
+type mfaEnrollmentDocument struct {
+	UserID             string     `bson:"_id"`
+	Secret             string     `bson:"secret"`
+	RecoveryCodeHashes []string   `bson:"recovery_code_hashes"`
+	LastUsedStep       int64      `bson:"last_used_step"`
+	CreatedAt          time.Time  `bson:"created_at"`
+	EnabledAt          *time.Time `bson:"enabled_at"`
+}
+
+// MFAStore implements mfa.Store on top of the mfa_enrollments collection
+type MFAStore struct {
+	collection *mongo.Collection
+}
+
+func NewMFAStore(db *mongo.Database) *MFAStore {
+	return &MFAStore{collection: db.Collection(mfaEnrollmentsCollection)}
+}
+
+func (s *MFAStore) Save(ctx context.Context, enrollment mfa.Enrollment) error {
+	recoveryCodes := enrollment.RecoveryCodeHashes
+	if recoveryCodes == nil {
+		recoveryCodes = []string{}
+	}
+	doc := mfaEnrollmentDocument{
+		UserID:             enrollment.UserId,
+		Secret:             enrollment.Secret,
+		RecoveryCodeHashes: recoveryCodes,
+		LastUsedStep:       enrollment.LastUsedStep,
+		CreatedAt:          enrollment.CreatedAt,
+		EnabledAt:          enrollment.EnabledAt,
+	}
+	_, err := s.collection.ReplaceOne(ctx, bson.M{"_id": doc.UserID}, doc, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (s *MFAStore) Find(ctx context.Context, userId string) (mfa.Enrollment, error) {
+	var doc mfaEnrollmentDocument
+	err := s.collection.FindOne(ctx, bson.M{"_id": userId}).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return mfa.Enrollment{}, mfa.ErrNotEnrolled
+		}
+		return mfa.Enrollment{}, err
+	}
+	return mfa.Enrollment{
+		UserId:             doc.UserID,
+		Secret:             doc.Secret,
+		RecoveryCodeHashes: doc.RecoveryCodeHashes,
+		LastUsedStep:       doc.LastUsedStep,
+		CreatedAt:          doc.CreatedAt,
+		EnabledAt:          doc.EnabledAt,
+	}, nil
+}
+
+func (s *MFAStore) Delete(ctx context.Context, userId string) error {
+	_, err := s.collection.DeleteOne(ctx, bson.M{"_id": userId})
+	return err
+}
+
+func (s *MFAStore) UseStep(ctx context.Context, userId string, step int64) error {
+	result, err := s.collection.UpdateOne(ctx,
+		bson.M{"_id": userId, "last_used_step": bson.M{"$lt": step}},
+		bson.M{"$set": bson.M{"last_used_step": step}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mfa.ErrInvalidCode
+	}
+	return nil
+}
+
+func (s *MFAStore) UseRecoveryCode(ctx context.Context, userId, codeHash string) error {
+	result, err := s.collection.UpdateOne(ctx,
+		bson.M{"_id": userId, "recovery_code_hashes": codeHash},
+		bson.M{"$pull": bson.M{"recovery_code_hashes": codeHash}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mfa.ErrInvalidCode
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/storage/migrate"
+	"github.com/lucsky/cuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// migrationLockTTL is how long a lock is honoured. The lock of an instance that died while
+	// migrating is taken over once it is that old.
+	migrationLockTTL = 10 * time.Minute
+	// migrationLockPoll is how often an instance waiting for the lock tries again
+	migrationLockPoll = time.Second
+)
+
+// Migration is a step of the schema, such as the indexes or the validator of a collection
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, db *mongo.Database) error
+	Down    func(ctx context.Context, db *mongo.Database) error
+}
+
+// migrationDriver records the applied migrations in the schema_migrations collection. MongoDB has
+// no advisory locks, so instances take turns through a document of the migration_lock collection.
+type migrationDriver struct {
+	db         *mongo.Database
+	migrations map[int]Migration
+}
+
+// NewMigrator applies migrations to db
+func NewMigrator(db *mongo.Database, migrations ...Migration) *migrate.Migrator {
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, migration := range migrations {
+		if migration.Up == nil || migration.Down == nil {
+			panic(fmt.Sprintf("migration %d %s needs both an up and a down step", migration.Version, migration.Name))
+		}
+		if _, ok := byVersion[migration.Version]; ok {
+			panic(fmt.Sprintf("%v: %d", migrate.ErrDuplicateVersion, migration.Version))
+		}
+		byVersion[migration.Version] = migration
+	}
+	return migrate.New(&migrationDriver{db: db, migrations: byVersion})
+}
+
+func (d *migrationDriver) Migrations() []migrate.Migration {
+	migrations := make([]migrate.Migration, 0, len(d.migrations))
+	for _, migration := range d.migrations {
+		migrations = append(migrations, migrate.Migration{Version: migration.Version, Name: migration.Name})
+	}
+	return migrations
+}
+
+// Lock inserts the lock document, or takes it over once it expired, and waits for its turn
+// while another instance holds it
+func (d *migrationDriver) Lock(ctx context.Context) (func(ctx context.Context) error, error) {
+	locks := d.db.Collection("migration_lock")
+	owner := cuid.New()
+	for {
+		now := time.Now()
+		lock := bson.M{"_id": "migrations", "owner": owner, "expiresAt": now.Add(migrationLockTTL)}
+		_, err := locks.InsertOne(ctx, lock)
+		if err == nil {
+			break
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+		result, err := locks.ReplaceOne(ctx, bson.M{"_id": "migrations", "expiresAt": bson.M{"$lte": now}}, lock)
+		if err != nil {
+			return nil, err
+		}
+		if result.ModifiedCount == 1 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(migrationLockPoll):
+		}
+	}
+	unlock := func(ctx context.Context) error {
+		_, err := locks.DeleteOne(ctx, bson.M{"_id": "migrations", "owner": owner})
+		return err
+	}
+	return unlock, nil
+}
+
+func (d *migrationDriver) Applied(ctx context.Context) (map[int]time.Time, error) {
+	cursor, err := d.db.Collection("schema_migrations").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	applied := map[int]time.Time{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			Version   int       `bson:"_id"`
+			AppliedAt time.Time `bson:"appliedAt"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		applied[doc.Version] = doc.AppliedAt
+	}
+	return applied, cursor.Err()
+}
+
+// Apply runs the up step of the migration, then records it. Steps that change indexes and
+// validators can't run in a transaction, they are written to be run again if recording fails.
+func (d *migrationDriver) Apply(ctx context.Context, migration migrate.Migration) error {
+	if err := d.migrations[migration.Version].Up(ctx, d.db); err != nil {
+		return err
+	}
+	_, err := d.db.Collection("schema_migrations").InsertOne(ctx, bson.M{
+		"_id":       migration.Version,
+		"name":      migration.Name,
+		"appliedAt": time.Now(),
+	})
+	return err
+}
+
+// Revert runs the down step of the migration, then forgets it was applied
+func (d *migrationDriver) Revert(ctx context.Context, migration migrate.Migration) error {
+	if err := d.migrations[migration.Version].Down(ctx, d.db); err != nil {
+		return err
+	}
+	_, err := d.db.Collection("schema_migrations").DeleteOne(ctx, bson.M{"_id": migration.Version})
+	return err
+}
@@ -0,0 +1,33 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth/onetime"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const usedTokensCollection = "used_tokens"
+
+// UsedTokenStore implements onetime.Store on top of the used_tokens collection
+type UsedTokenStore struct {
+	collection *mongo.Collection
+}
+
+func NewUsedTokenStore(db *mongo.Database) *UsedTokenStore {
+	return &UsedTokenStore{collection: db.Collection(usedTokensCollection)}
+}
+
+// Use records a used token and drops the ones that have expired since
+func (s *UsedTokenStore) Use(ctx context.Context, tokenId string, expiresAt time.Time) error {
+	if _, err := s.collection.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lte": time.Now()}}); err != nil {
+		return err
+	}
+	_, err := s.collection.InsertOne(ctx, bson.M{"_id": tokenId, "expires_at": expiresAt})
+	if mongo.IsDuplicateKeyError(err) {
+		return onetime.ErrTokenUsed
+	}
+	return err
+}
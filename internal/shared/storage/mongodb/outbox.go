@@ -0,0 +1,104 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/iammrsea/social-app/internal/shared/outbox"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const outboxCollection = "outbox"
+
+// outboxDocument represents how an outbox message is stored in MongoDB
+type outboxDocument struct {
+	ID           string     `bson:"_id"`
+	EventName    string     `bson:"event_name"`
+	Payload      string     `bson:"payload"`
+	OccurredAt   time.Time  `bson:"occurred_at"`
+	CreatedAt    time.Time  `bson:"created_at"`
+	DispatchedAt *time.Time `bson:"dispatched_at"`
+	Attempts     int        `bson:"attempts"`
+	LastError    string     `bson:"last_error,omitempty"`
+}
+
+// AppendToOutbox writes events to the outbox collection. ctx must be the session context of the
+// transaction that saves the aggregate, so both are committed together.
+func AppendToOutbox(ctx context.Context, db *mongo.Database, evts []events.Event) error {
+	if len(evts) == 0 {
+		return nil
+	}
+	messages, err := outbox.NewMessages(evts)
+	if err != nil {
+		return err
+	}
+	docs := make([]any, 0, len(messages))
+	for _, msg := range messages {
+		docs = append(docs, outboxDocument{
+			ID:         msg.Id,
+			EventName:  msg.EventName,
+			Payload:    string(msg.Payload),
+			OccurredAt: msg.OccurredAt,
+			CreatedAt:  msg.CreatedAt,
+		})
+	}
+	_, err = db.Collection(outboxCollection).InsertMany(ctx, docs)
+	return err
+}
+
+// OutboxStore implements outbox.Store on top of the outbox collection
+type OutboxStore struct {
+	collection *mongo.Collection
+}
+
+func NewOutboxStore(db *mongo.Database) *OutboxStore {
+	return &OutboxStore{collection: db.Collection(outboxCollection)}
+}
+
+func (s *OutboxStore) Pending(ctx context.Context, limit int) ([]outbox.Message, error) {
+	filter := bson.M{
+		"dispatched_at": nil,
+		"attempts":      bson.M{"$lt": outbox.MaxDeliveryAttempts},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := s.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	messages := []outbox.Message{}
+	for cursor.Next(ctx) {
+		var doc outboxDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		messages = append(messages, outbox.Message{
+			Id:         doc.ID,
+			EventName:  doc.EventName,
+			Payload:    []byte(doc.Payload),
+			OccurredAt: doc.OccurredAt,
+			CreatedAt:  doc.CreatedAt,
+			Attempts:   doc.Attempts,
+		})
+	}
+	return messages, cursor.Err()
+}
+
+func (s *OutboxStore) MarkDispatched(ctx context.Context, id string) error {
+	_, err := s.collection.UpdateByID(ctx, id, bson.M{"$set": bson.M{"dispatched_at": time.Now()}})
+	return err
+}
+
+func (s *OutboxStore) MarkFailed(ctx context.Context, id string, reason string) error {
+	_, err := s.collection.UpdateByID(ctx, id, bson.M{
+		"$inc": bson.M{"attempts": 1},
+		"$set": bson.M{"last_error": reason},
+	})
+	return err
+}
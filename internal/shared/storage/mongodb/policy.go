@@ -0,0 +1,138 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	rbacPolicyCollection = "rbac_policy"
+	// rbacPolicyId is the id of the single document holding the policy
+	rbacPolicyId = "policy"
+)
+
+// policyDocument represents how the role-based access policy is stored in MongoDB
+type policyDocument struct {
+	ID         string                  `bson:"_id"`
+	Roles      map[string]roleDocument `bson:"roles"`
+	Unverified []string                `bson:"unverified"`
+}
+
+type roleDocument struct {
+	Inherits    []string `bson:"inherits"`
+	Permissions []string `bson:"permissions"`
+	All         bool     `bson:"all"`
+}
+
+// PolicyStore implements rbac.PolicySource on top of the rbac_policy collection
+type PolicyStore struct {
+	collection *mongo.Collection
+}
+
+func NewPolicyStore(db *mongo.Database) *PolicyStore {
+	return &PolicyStore{collection: db.Collection(rbacPolicyCollection)}
+}
+
+func (s *PolicyStore) LoadPolicy(ctx context.Context) (rbac.PolicyConfig, error) {
+	var doc policyDocument
+	err := s.collection.FindOne(ctx, bson.M{"_id": rbacPolicyId}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return rbac.PolicyConfig{}, rbac.ErrPolicyNotFound
+	}
+	if err != nil {
+		return rbac.PolicyConfig{}, err
+	}
+	conf := rbac.PolicyConfig{
+		Roles:      make(map[rbac.UserRole]rbac.RoleConfig, len(doc.Roles)),
+		Unverified: toPermissions(doc.Unverified),
+	}
+	for role, rd := range doc.Roles {
+		inherits := make([]rbac.UserRole, len(rd.Inherits))
+		for i, parent := range rd.Inherits {
+			inherits[i] = rbac.UserRole(parent)
+		}
+		conf.Roles[rbac.UserRole(role)] = rbac.RoleConfig{
+			Inherits:    inherits,
+			Permissions: toPermissions(rd.Permissions),
+			All:         rd.All,
+		}
+	}
+	return conf, nil
+}
+
+// SavePolicy replaces the stored policy with conf. The reloaders of the app put it in force.
+func (s *PolicyStore) SavePolicy(ctx context.Context, conf rbac.PolicyConfig) error {
+	doc := policyDocument{
+		ID:         rbacPolicyId,
+		Roles:      make(map[string]roleDocument, len(conf.Roles)),
+		Unverified: fromPermissions(conf.Unverified),
+	}
+	for role, rc := range conf.Roles {
+		inherits := make([]string, len(rc.Inherits))
+		for i, parent := range rc.Inherits {
+			inherits[i] = string(parent)
+		}
+		doc.Roles[string(role)] = roleDocument{
+			Inherits:    inherits,
+			Permissions: fromPermissions(rc.Permissions),
+			All:         rc.All,
+		}
+	}
+	_, err := s.collection.ReplaceOne(ctx, bson.M{"_id": rbacPolicyId}, doc, options.Replace().SetUpsert(true))
+	return err
+}
+
+// anyPermissions are the permissions the ownership rules check instead of role names, by the
+// built-in role they were added to
+var anyPermissions = map[rbac.UserRole][]rbac.Permission{
+	rbac.Regular:   {rbac.UpdateUser},
+	rbac.Moderator: {rbac.DeleteAnyPost, rbac.DeleteAnyComment},
+}
+
+// GrantAnyPermissions adds anyPermissions to the built-in roles of a stored policy. Roles an
+// operator removed are left alone.
+func GrantAnyPermissions(ctx context.Context, db *mongo.Database) error {
+	return updateAnyPermissions(ctx, db, "$addToSet", func(perms []rbac.Permission) any {
+		return bson.M{"$each": fromPermissions(perms)}
+	})
+}
+
+// RevokeAnyPermissions takes back what GrantAnyPermissions added
+func RevokeAnyPermissions(ctx context.Context, db *mongo.Database) error {
+	return updateAnyPermissions(ctx, db, "$pullAll", func(perms []rbac.Permission) any {
+		return fromPermissions(perms)
+	})
+}
+
+func updateAnyPermissions(ctx context.Context, db *mongo.Database, operator string, value func(perms []rbac.Permission) any) error {
+	collection := db.Collection(rbacPolicyCollection)
+	for role, perms := range anyPermissions {
+		field := "roles." + string(role) + ".permissions"
+		filter := bson.M{"_id": rbacPolicyId, "roles." + string(role): bson.M{"$exists": true}}
+		if _, err := collection.UpdateOne(ctx, filter, bson.M{operator: bson.M{field: value(perms)}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toPermissions(values []string) []rbac.Permission {
+	perms := make([]rbac.Permission, len(values))
+	for i, value := range values {
+		perms[i] = rbac.Permission(value)
+	}
+	return perms
+}
+
+func fromPermissions(perms []rbac.Permission) []string {
+	values := make([]string, len(perms))
+	for i, perm := range perms {
+		values[i] = string(perm)
+	}
+	return values
+}
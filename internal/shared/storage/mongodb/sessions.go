@@ -0,0 +1,166 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth/sessions"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	refreshTokensCollection       = "refresh_tokens"
+	revokedAccessTokensCollection = "revoked_access_tokens"
+)
+
+// refreshTokenDocument represents how a refresh token is stored in MongoDB
+type refreshTokenDocument struct {
+	ID                   string     `bson:"_id"`
+	SessionID            string     `bson:"session_id"`
+	UserID               string     `bson:"user_id"`
+	TokenHash            string     `bson:"token_hash"`
+	Device               string     `bson:"device"`
+	AccessTokenID        string     `bson:"access_token_id"`
+	AccessTokenExpiresAt time.Time  `bson:"access_token_expires_at"`
+	CreatedAt            time.Time  `bson:"created_at"`
+	ExpiresAt            time.Time  `bson:"expires_at"`
+	MFA                  bool       `bson:"mfa"`
+	RotatedAt            *time.Time `bson:"rotated_at"`
+	RevokedAt            *time.Time `bson:"revoked_at"`
+}
+
+func toRefreshTokenDocument(token sessions.RefreshToken) refreshTokenDocument {
+	return refreshTokenDocument{
+		ID:                   token.Id,
+		SessionID:            token.SessionId,
+		UserID:               token.UserId,
+		TokenHash:            token.TokenHash,
+		Device:               token.Device,
+		AccessTokenID:        token.AccessToken.Id,
+		AccessTokenExpiresAt: token.AccessToken.ExpiresAt,
+		CreatedAt:            token.CreatedAt,
+		ExpiresAt:            token.ExpiresAt,
+		MFA:                  token.MFA,
+	}
+}
+
+// SessionStore implements sessions.Store on top of the refresh_tokens collection
+type SessionStore struct {
+	collection *mongo.Collection
+}
+
+func NewSessionStore(db *mongo.Database) *SessionStore {
+	return &SessionStore{collection: db.Collection(refreshTokensCollection)}
+}
+
+func (s *SessionStore) Save(ctx context.Context, token sessions.RefreshToken) error {
+	_, err := s.collection.InsertOne(ctx, toRefreshTokenDocument(token))
+	return err
+}
+
+func (s *SessionStore) FindByHash(ctx context.Context, tokenHash string) (sessions.RefreshToken, error) {
+	var doc refreshTokenDocument
+	err := s.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return sessions.RefreshToken{}, sessions.ErrRefreshTokenNotFound
+		}
+		return sessions.RefreshToken{}, err
+	}
+	return sessions.RefreshToken{
+		Id:          doc.ID,
+		SessionId:   doc.SessionID,
+		UserId:      doc.UserID,
+		TokenHash:   doc.TokenHash,
+		Device:      doc.Device,
+		AccessToken: sessions.AccessToken{Id: doc.AccessTokenID, ExpiresAt: doc.AccessTokenExpiresAt},
+		CreatedAt:   doc.CreatedAt,
+		ExpiresAt:   doc.ExpiresAt,
+		MFA:         doc.MFA,
+		Rotated:     doc.RotatedAt != nil,
+		Revoked:     doc.RevokedAt != nil,
+	}, nil
+}
+
+// Rotate marks the current token as rotated before saving the next one. Should saving fail, the
+// session can't be refreshed any more and the user has to log in again.
+func (s *SessionStore) Rotate(ctx context.Context, currentId string, next sessions.RefreshToken, now time.Time) error {
+	filter := bson.M{"_id": currentId, "rotated_at": nil, "revoked_at": nil}
+	result, err := s.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"rotated_at": now}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return sessions.ErrRefreshTokenReused
+	}
+	return s.Save(ctx, next)
+}
+
+func (s *SessionStore) RevokeSession(ctx context.Context, sessionId string, now time.Time) ([]sessions.AccessToken, error) {
+	return s.revoke(ctx, bson.M{"session_id": sessionId, "revoked_at": nil}, now)
+}
+
+func (s *SessionStore) RevokeUserSessions(ctx context.Context, userId string, now time.Time) ([]sessions.AccessToken, error) {
+	return s.revoke(ctx, bson.M{"user_id": userId, "revoked_at": nil}, now)
+}
+
+func (s *SessionStore) revoke(ctx context.Context, filter bson.M, now time.Time) ([]sessions.AccessToken, error) {
+	cursor, err := s.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	ids := []string{}
+	revoked := []sessions.AccessToken{}
+	for cursor.Next(ctx) {
+		var doc refreshTokenDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		ids = append(ids, doc.ID)
+		if doc.AccessTokenExpiresAt.After(now) {
+			revoked = append(revoked, sessions.AccessToken{Id: doc.AccessTokenID, ExpiresAt: doc.AccessTokenExpiresAt})
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return revoked, nil
+	}
+	_, err = s.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$set": bson.M{"revoked_at": now}})
+	return revoked, err
+}
+
+// Denylist implements sessions.Denylist on top of the revoked_access_tokens collection
+type Denylist struct {
+	collection *mongo.Collection
+}
+
+func NewDenylist(db *mongo.Database) *Denylist {
+	return &Denylist{collection: db.Collection(revokedAccessTokensCollection)}
+}
+
+// Deny adds tokens to the denylist and drops the ones that have expired since
+func (d *Denylist) Deny(ctx context.Context, tokens []sessions.AccessToken) error {
+	if _, err := d.collection.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lte": time.Now()}}); err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		_, err := d.collection.UpdateByID(ctx, token.Id,
+			bson.M{"$setOnInsert": bson.M{"expires_at": token.ExpiresAt}},
+			options.Update().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Denylist) IsRevoked(ctx context.Context, tokenId string) (bool, error) {
+	count, err := d.collection.CountDocuments(ctx, bson.M{"_id": tokenId}, options.Count().SetLimit(1))
+	return count > 0, err
+}
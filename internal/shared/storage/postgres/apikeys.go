@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth/apikeys"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// APIKeyStore implements apikeys.Store on top of the api_keys table
+type APIKeyStore struct {
+	db *pgxpool.Pool
+}
+
+func NewAPIKeyStore(db *pgxpool.Pool) *APIKeyStore {
+	return &APIKeyStore{db: db}
+}
+
+const apiKeyColumns = "id, user_id, name, prefix, key_hash, scopes, created_at, expires_at, revoked_at"
+
+func (s *APIKeyStore) Save(ctx context.Context, key apikeys.APIKey) error {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+	_, err := s.db.Exec(ctx, `
+        INSERT INTO api_keys (`+apiKeyColumns+`)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+    `, key.Id, key.UserId, key.Name, key.Prefix, key.KeyHash, scopes, key.CreatedAt, key.ExpiresAt, key.RevokedAt)
+	return err
+}
+
+func (s *APIKeyStore) FindByHash(ctx context.Context, keyHash string) (apikeys.APIKey, error) {
+	row := s.db.QueryRow(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = $1`, keyHash)
+	key, err := scanAPIKey(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return key, apikeys.ErrAPIKeyNotFound
+	}
+	return key, err
+}
+
+func (s *APIKeyStore) ListByUser(ctx context.Context, userId string) ([]apikeys.APIKey, error) {
+	rows, err := s.db.Query(ctx, `
+        SELECT `+apiKeyColumns+` FROM api_keys
+        WHERE user_id = $1
+        ORDER BY created_at DESC, id DESC
+    `, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	keys := []apikeys.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (s *APIKeyStore) Revoke(ctx context.Context, userId, keyId string, now time.Time) error {
+	tag, err := s.db.Exec(ctx, `
+        UPDATE api_keys SET revoked_at = COALESCE(revoked_at, $3)
+        WHERE id = $1 AND user_id = $2
+    `, keyId, userId, now)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apikeys.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func scanAPIKey(row pgx.Row) (apikeys.APIKey, error) {
+	var key apikeys.APIKey
+	var scopes []string
+	err := row.Scan(
+		&key.Id, &key.UserId, &key.Name, &key.Prefix, &key.KeyHash, &scopes, &key.CreatedAt,
+		&key.ExpiresAt, &key.RevokedAt,
+	)
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, rbac.Permission(scope))
+	}
+	return key, err
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/audit"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AuditStore implements audit.Store on top of the audit_log table
+type AuditStore struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditStore(db *pgxpool.Pool) *AuditStore {
+	return &AuditStore{db: db}
+}
+
+const auditColumns = "id, kind, actor_id, target, permission, details, outcome, reason, request_id, at"
+
+func (s *AuditStore) Append(ctx context.Context, entry audit.Entry) error {
+	_, err := s.db.Exec(ctx, `
+        INSERT INTO audit_log (`+auditColumns+`)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+    `, entry.Id, entry.Kind.String(), entry.ActorId, entry.Target, entry.Permission, entry.Details, entry.Outcome.String(),
+		entry.Reason, entry.RequestId, entry.At)
+	return err
+}
+
+func (s *AuditStore) Search(ctx context.Context, opts audit.SearchOptions) ([]audit.Entry, bool, error) {
+	var before *time.Time
+	if opts.After != "" {
+		at, err := time.Parse(time.RFC3339Nano, opts.After)
+		if err != nil {
+			return nil, false, err
+		}
+		before = &at
+	}
+	// Fetch one extra row to check for "hasNext"
+	rows, err := s.db.Query(ctx, `
+        SELECT `+auditColumns+` FROM audit_log
+        WHERE ($1::TIMESTAMP IS NULL OR at < $1)
+            AND ($2 = '' OR actor_id = $2)
+            AND ($3 = '' OR target = $3)
+            AND ($4 = '' OR permission = $4)
+            AND ($5 = '' OR kind = $5)
+            AND ($6 = '' OR outcome = $6)
+        ORDER BY at DESC
+        LIMIT $7
+    `, before, opts.ActorId, opts.Target, opts.Permission, opts.Kind.String(), opts.Outcome.String(), opts.First+1)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+	entries := []audit.Entry{}
+	for rows.Next() {
+		var entry audit.Entry
+		var kind, outcome string
+		if err := rows.Scan(&entry.Id, &kind, &entry.ActorId, &entry.Target, &entry.Permission, &entry.Details, &outcome,
+			&entry.Reason, &entry.RequestId, &entry.At); err != nil {
+			return nil, false, err
+		}
+		entry.Kind, entry.Outcome = audit.Kind(kind), audit.Outcome(outcome)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	hasNext := len(entries) > int(opts.First)
+	if hasNext {
+		entries = entries[:opts.First]
+	}
+	return entries, hasNext, nil
+}
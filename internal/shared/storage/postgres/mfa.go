@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iammrsea/social-app/internal/shared/auth/mfa"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// MFAStore implements mfa.Store on top of the mfa_enrollments table
+type MFAStore struct {
+	db *pgxpool.Pool
+}
+
+func NewMFAStore(db *pgxpool.Pool) *MFAStore {
+	return &MFAStore{db: db}
+}
+
+func (s *MFAStore) Save(ctx context.Context, enrollment mfa.Enrollment) error {
+	recoveryCodes := enrollment.RecoveryCodeHashes
+	if recoveryCodes == nil {
+		recoveryCodes = []string{}
+	}
+	_, err := s.db.Exec(ctx, `
+        INSERT INTO mfa_enrollments (user_id, secret, recovery_code_hashes, last_used_step, created_at, enabled_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (user_id) DO UPDATE SET
+            secret = EXCLUDED.secret,
+            recovery_code_hashes = EXCLUDED.recovery_code_hashes,
+            last_used_step = EXCLUDED.last_used_step,
+            created_at = EXCLUDED.created_at,
+            enabled_at = EXCLUDED.enabled_at
+    `, enrollment.UserId, enrollment.Secret, recoveryCodes, enrollment.LastUsedStep, enrollment.CreatedAt, enrollment.EnabledAt)
+	return err
+}
+
+func (s *MFAStore) Find(ctx context.Context, userId string) (mfa.Enrollment, error) {
+	var enrollment mfa.Enrollment
+	err := s.db.QueryRow(ctx, `
+        SELECT user_id, secret, recovery_code_hashes, last_used_step, created_at, enabled_at
+        FROM mfa_enrollments WHERE user_id = $1
+    `, userId).Scan(
+		&enrollment.UserId, &enrollment.Secret, &enrollment.RecoveryCodeHashes, &enrollment.LastUsedStep,
+		&enrollment.CreatedAt, &enrollment.EnabledAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return enrollment, mfa.ErrNotEnrolled
+	}
+	return enrollment, err
+}
+
+func (s *MFAStore) Delete(ctx context.Context, userId string) error {
+	_, err := s.db.Exec(ctx, `DELETE FROM mfa_enrollments WHERE user_id = $1`, userId)
+	return err
+}
+
+func (s *MFAStore) UseStep(ctx context.Context, userId string, step int64) error {
+	tag, err := s.db.Exec(ctx, `
+        UPDATE mfa_enrollments SET last_used_step = $2
+        WHERE user_id = $1 AND last_used_step < $2
+    `, userId, step)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return mfa.ErrInvalidCode
+	}
+	return nil
+}
+
+func (s *MFAStore) UseRecoveryCode(ctx context.Context, userId, codeHash string) error {
+	tag, err := s.db.Exec(ctx, `
+        UPDATE mfa_enrollments SET recovery_code_hashes = array_remove(recovery_code_hashes, $2)
+        WHERE user_id = $1 AND $2 = ANY(recovery_code_hashes)
+    `, userId, codeHash)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return mfa.ErrInvalidCode
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/storage/migrate"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationFiles hold a NNNN_name.up.sql and a NNNN_name.down.sql file per version
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// migrationLockId names the advisory lock held while migrating
+const migrationLockId = 7_265_491_032
+
+type sqlMigration struct {
+	migrate.Migration
+	up   string
+	down string
+}
+
+// migrationDriver runs the SQL migrations embedded in the binary and records the applied ones in
+// the schema_migrations table
+type migrationDriver struct {
+	db         *pgxpool.Pool
+	migrations map[int]sqlMigration
+}
+
+// NewMigrator applies the SQL migrations embedded in the binary to db
+func NewMigrator(db *pgxpool.Pool) (*migrate.Migrator, error) {
+	migrations, err := readMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(&migrationDriver{db: db, migrations: migrations}), nil
+}
+
+func (d *migrationDriver) Migrations() []migrate.Migration {
+	migrations := make([]migrate.Migration, 0, len(d.migrations))
+	for _, migration := range d.migrations {
+		migrations = append(migrations, migration.Migration)
+	}
+	return migrations
+}
+
+// Lock takes a session-level advisory lock on a connection kept aside until unlock
+func (d *migrationDriver) Lock(ctx context.Context) (func(ctx context.Context) error, error) {
+	conn, err := d.db.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockId); err != nil {
+		conn.Release()
+		return nil, err
+	}
+	unlock := func(ctx context.Context) error {
+		defer conn.Release()
+		_, err := conn.Exec(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockId)
+		return err
+	}
+	return unlock, nil
+}
+
+func (d *migrationDriver) Applied(ctx context.Context) (map[int]time.Time, error) {
+	_, err := d.db.Exec(ctx, `
+        CREATE TABLE IF NOT EXISTS schema_migrations (
+            version BIGINT PRIMARY KEY,
+            name TEXT NOT NULL,
+            applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+        )
+    `)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := d.db.Query(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// Apply runs the up file of the migration in the transaction that records it
+func (d *migrationDriver) Apply(ctx context.Context, migration migrate.Migration) error {
+	return pgx.BeginFunc(ctx, d.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, d.migrations[migration.Version].up); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`,
+			migration.Version, migration.Name, time.Now())
+		return err
+	})
+}
+
+// Revert runs the down file of the migration in the transaction that forgets it
+func (d *migrationDriver) Revert(ctx context.Context, migration migrate.Migration) error {
+	return pgx.BeginFunc(ctx, d.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, d.migrations[migration.Version].down); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+		return err
+	})
+}
+
+// readMigrations pairs the up and down files of every version
+func readMigrations(files fs.FS) (map[int]sqlMigration, error) {
+	names, err := fs.Glob(files, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	migrations := map[int]sqlMigration{}
+	for _, name := range names {
+		match := migrationFileName.FindStringSubmatch(path.Base(name))
+		if match == nil {
+			return nil, fmt.Errorf("migration file %s isn't named NNNN_name.up.sql or NNNN_name.down.sql", name)
+		}
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(files, name)
+		if err != nil {
+			return nil, err
+		}
+		migration := migrations[version]
+		if migration.Name != "" && migration.Name != match[2] {
+			return nil, fmt.Errorf("migrations %s and %s have the same version", migration.Name, match[2])
+		}
+		migration.Version = version
+		migration.Name = match[2]
+		if match[3] == "up" {
+			migration.up = string(content)
+		} else {
+			migration.down = string(content)
+		}
+		migrations[version] = migration
+	}
+	for version, migration := range migrations {
+		if migration.up == "" || migration.down == "" {
+			return nil, fmt.Errorf("migration %d %s needs both an up and a down file", version, migration.Name)
+		}
+	}
+	return migrations, nil
+}
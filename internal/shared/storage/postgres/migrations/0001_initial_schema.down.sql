@@ -0,0 +1,2 @@
+-- Drops the users table, and the users in it
+DROP TABLE IF EXISTS users;
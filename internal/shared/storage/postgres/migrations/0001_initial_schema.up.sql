@@ -1,3 +1,10 @@
+-- The schema of the former scripts/postgres-init.sql, which databases were set up with before
+-- migrations were introduced. It is created only if missing, so such databases are taken over as
+-- they are and brought up to date by the migrations that follow.
+--
+-- The demo users the script seeded (johndoe and janedoe) are not part of the schema and were
+-- dropped with it: databases that have them keep them, new databases start without users.
+
 -- Create the users table
 CREATE TABLE IF NOT EXISTS users (
     id TEXT PRIMARY KEY,
@@ -15,10 +22,3 @@ CREATE TABLE IF NOT EXISTS users (
     created_at TIMESTAMP NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMP NOT NULL DEFAULT NOW()
 );
-
--- Optional: Seed initial data
-INSERT INTO users (id, username, email, role, reputation_score, badges, is_banned, created_at, updated_at)
-VALUES
-    ('cuid1', 'johndoe', 'johndoe@example.com', 'REGULAR', 100, ARRAY['badge1', 'badge2'], FALSE, NOW(), NOW()),
-    ('cuid2', 'janedoe', 'janedoe@example.com', 'MODERATOR', 200, ARRAY['badge3'], FALSE, NOW(), NOW())
-ON CONFLICT DO NOTHING;
\ No newline at end of file
@@ -0,0 +1,26 @@
+-- Takes the users table back to the initial schema. Users keep their first built-in role, or
+-- become REGULAR; their other roles, passwords, email verification and deletion requests are lost.
+DELETE FROM users WHERE id = 'deleted-user';
+
+DROP INDEX IF EXISTS idx_users_deletion_due_at;
+DROP INDEX IF EXISTS idx_users_ban_end_date;
+DROP INDEX IF EXISTS idx_users_username_lower;
+DROP INDEX IF EXISTS idx_users_email_lower;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+
+ALTER TABLE users ADD COLUMN role TEXT;
+UPDATE users SET role = COALESCE(
+    (SELECT r FROM unnest(roles) AS r WHERE r IN ('ADMIN', 'REGULAR', 'MODERATOR', 'GUEST') LIMIT 1),
+    'REGULAR'
+);
+ALTER TABLE users
+    ALTER COLUMN role SET NOT NULL,
+    ADD CONSTRAINT users_role_check CHECK (role IN ('ADMIN', 'REGULAR', 'MODERATOR', 'GUEST'));
+
+ALTER TABLE users
+    DROP COLUMN roles,
+    DROP COLUMN password_hash,
+    DROP COLUMN email_verified_at,
+    DROP COLUMN deletion_requested_at,
+    DROP COLUMN deletion_due_at,
+    DROP COLUMN version;
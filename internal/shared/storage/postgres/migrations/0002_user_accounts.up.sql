@@ -0,0 +1,43 @@
+-- Brings the users table of the initial schema up to what the user module stores: roles defined by
+-- the rbac policy, passwords, email verification, account deletion and a version for optimistic
+-- concurrency. Steps already taken are skipped.
+
+-- Users have a list of roles instead of one of the four built-in roles
+ALTER TABLE users ADD COLUMN IF NOT EXISTS roles TEXT[] NOT NULL DEFAULT '{REGULAR}' CHECK (cardinality(roles) > 0);
+
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'role'
+    ) THEN
+        UPDATE users SET roles = ARRAY[role];
+        ALTER TABLE users DROP COLUMN role;
+    END IF;
+END;
+$$;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS password_hash TEXT NOT NULL DEFAULT '', -- bcrypt hash, empty for users registered before passwords
+    ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP, -- NULL until the user verifies the email
+    ADD COLUMN IF NOT EXISTS deletion_requested_at TIMESTAMP, -- NULL unless the user asked for the account to be deleted
+    ADD COLUMN IF NOT EXISTS deletion_due_at TIMESTAMP, -- End of the grace period, during which the deletion can be cancelled
+    ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1; -- Incremented on every update, for optimistic concurrency
+
+-- Emails and usernames are unique regardless of case. Users whose emails or usernames only differ
+-- in case have to be told apart by hand before this migration can run.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users (lower(email));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_lower ON users (lower(username));
+
+-- Time-boxed bans, looked up by the ban expiry scheduler
+CREATE INDEX IF NOT EXISTS idx_users_ban_end_date ON users (ban_end_date) WHERE is_banned AND NOT is_ban_indefinite;
+
+-- Pending account deletions, looked up by the account deletion scheduler
+CREATE INDEX IF NOT EXISTS idx_users_deletion_due_at ON users (deletion_due_at) WHERE deletion_due_at IS NOT NULL;
+
+-- Stands in for deleted users: their posts, comments and reports are handed over to it. Nobody can
+-- log in as this user, it has no password.
+INSERT INTO users (id, username, email, roles)
+VALUES ('deleted-user', '[deleted]', 'deleted-user@invalid', ARRAY['GUEST'])
+ON CONFLICT DO NOTHING;
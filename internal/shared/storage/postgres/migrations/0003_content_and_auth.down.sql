@@ -0,0 +1,16 @@
+-- Drops every table of the up migration, and the data in them
+DROP TABLE IF EXISTS audit_log;
+DROP FUNCTION IF EXISTS reject_audit_log_change();
+DROP TABLE IF EXISTS rbac_unverified_permissions;
+DROP TABLE IF EXISTS rbac_roles;
+DROP TABLE IF EXISTS mfa_enrollments;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS used_tokens;
+DROP TABLE IF EXISTS revoked_access_tokens;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS outbox;
+DROP TABLE IF EXISTS reports;
+DROP TABLE IF EXISTS post_scores;
+DROP TABLE IF EXISTS votes;
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS posts;
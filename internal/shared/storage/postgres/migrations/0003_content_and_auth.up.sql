@@ -0,0 +1,208 @@
+-- The tables added since the initial schema: posts, comments, votes and reports, the outbox of
+-- domain events, refresh tokens, API keys, two-factor authentication, the role-based access
+-- policy and the audit log. Tables that already exist are left as they are.
+
+-- Create the posts table
+CREATE TABLE IF NOT EXISTS posts (
+    id TEXT PRIMARY KEY,
+    author_id TEXT NOT NULL REFERENCES users(id),
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('DRAFT', 'PUBLISHED', 'DELETED')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_posts_status_created_at ON posts (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_posts_author_id ON posts (author_id);
+
+-- Create the comments table. root_id points at the top-level comment of the thread
+-- (the comment itself for top-level comments) so whole threads can be loaded at once.
+CREATE TABLE IF NOT EXISTS comments (
+    id TEXT PRIMARY KEY,
+    post_id TEXT NOT NULL REFERENCES posts(id),
+    author_id TEXT NOT NULL REFERENCES users(id),
+    parent_id TEXT REFERENCES comments(id),
+    root_id TEXT NOT NULL,
+    depth INTEGER NOT NULL DEFAULT 0 CHECK (depth >= 0),
+    body TEXT NOT NULL,
+    is_deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_post_id_created_at ON comments (post_id, created_at) WHERE parent_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_comments_root_id_created_at ON comments (root_id, created_at);
+
+-- Create the votes table. A user has at most one vote per post.
+CREATE TABLE IF NOT EXISTS votes (
+    user_id TEXT NOT NULL REFERENCES users(id),
+    post_id TEXT NOT NULL REFERENCES posts(id),
+    type TEXT NOT NULL CHECK (type IN ('UPVOTE', 'DOWNVOTE')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_votes_post_id_created_at ON votes (post_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_votes_created_at ON votes (created_at);
+
+-- Vote tallies per post, kept up to date in the same transaction as the votes
+CREATE TABLE IF NOT EXISTS post_scores (
+    post_id TEXT PRIMARY KEY REFERENCES posts(id),
+    upvotes INTEGER NOT NULL DEFAULT 0 CHECK (upvotes >= 0),
+    downvotes INTEGER NOT NULL DEFAULT 0 CHECK (downvotes >= 0)
+);
+
+-- Create the reports table. The resolution columns stay NULL while a report is pending.
+CREATE TABLE IF NOT EXISTS reports (
+    id TEXT PRIMARY KEY,
+    reporter_id TEXT NOT NULL REFERENCES users(id),
+    target_type TEXT NOT NULL CHECK (target_type IN ('POST', 'COMMENT', 'USER')),
+    target_id TEXT NOT NULL,
+    target_author_id TEXT NOT NULL REFERENCES users(id),
+    reason TEXT NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'RESOLVED', 'DISMISSED')),
+    action TEXT CHECK (action IN ('DISMISS', 'REMOVE_CONTENT', 'BAN_AUTHOR')),
+    resolved_by TEXT REFERENCES users(id),
+    resolution_note TEXT,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reports_status_created_at ON reports (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_reports_target ON reports (target_type, target_id);
+
+-- Domain events waiting to be published. Rows are written in the same transaction as the
+-- aggregate that raised them and marked as dispatched by the outbox relay.
+CREATE TABLE IF NOT EXISTS outbox (
+    id TEXT PRIMARY KEY,
+    sequence BIGSERIAL NOT NULL,
+    event_name TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    dispatched_at TIMESTAMP,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox (sequence) WHERE dispatched_at IS NULL;
+
+-- Refresh tokens, one row per token. Tokens of the same login session share a session_id; a
+-- token is rotated when it is exchanged for the next one. Only the SHA-256 of a token is stored.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    device TEXT NOT NULL DEFAULT '',
+    access_token_id TEXT NOT NULL,
+    access_token_expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    rotated_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    -- mfa is set for sessions started with two-factor authentication
+    mfa BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens (session_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+
+-- Access tokens revoked before they expire, checked on every authenticated request
+CREATE TABLE IF NOT EXISTS revoked_access_tokens (
+    token_id TEXT PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- Ids of the used single-use tokens sent by email (email verification, password reset), kept
+-- until the tokens expire
+CREATE TABLE IF NOT EXISTS used_tokens (
+    token_id TEXT PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- Personal API keys. Only the SHA-256 of a key is stored; prefix is the start of the key, kept
+-- to tell keys apart. scopes caps the permissions of the owner's role.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys (user_id);
+
+-- Two-factor authentication. secret is the TOTP secret shared with the authenticator app; only
+-- the SHA-256 of recovery codes is stored. last_used_step stops codes from being used twice.
+CREATE TABLE IF NOT EXISTS mfa_enrollments (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    recovery_code_hashes TEXT[] NOT NULL DEFAULT '{}',
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    enabled_at TIMESTAMP
+);
+
+-- Role-based access policy, loaded when RBAC_POLICY_SOURCE is database. Roles have the
+-- permissions of the roles they inherit.
+CREATE TABLE IF NOT EXISTS rbac_roles (
+    role TEXT PRIMARY KEY,
+    inherits TEXT[] NOT NULL DEFAULT '{}',
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    all_permissions BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- Permissions users have before they verify their email, whatever their role
+CREATE TABLE IF NOT EXISTS rbac_unverified_permissions (
+    permission TEXT PRIMARY KEY
+);
+
+INSERT INTO rbac_roles (role, inherits, permissions, all_permissions)
+VALUES
+    ('GUEST', '{}', ARRAY['create:account', 'view:post', 'view:comment', 'view:vote'], FALSE),
+    ('REGULAR', '{}', ARRAY['view:user', 'view:post', 'create:post', 'update:post', 'delete:post', 'view:comment', 'create:comment', 'update:comment', 'delete:comment', 'cast:vote', 'view:vote', 'create:report', 'manage:apikeys', 'manage:mfa'], FALSE),
+    ('MODERATOR', ARRAY['REGULAR'], ARRAY['list:users', 'ban:user', 'unban:user', 'view:report', 'resolve:report'], FALSE),
+    ('ADMIN', '{}', '{}', TRUE)
+ON CONFLICT (role) DO NOTHING;
+
+INSERT INTO rbac_unverified_permissions (permission)
+VALUES ('view:user'), ('view:post'), ('view:comment'), ('view:vote')
+ON CONFLICT (permission) DO NOTHING;
+
+-- Authorization decisions and state-changing commands. The log is append-only: rows can't be
+-- changed or deleted.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    actor_id TEXT NOT NULL DEFAULT '',
+    target TEXT NOT NULL DEFAULT '',
+    permission TEXT NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    outcome TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT '',
+    at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_at ON audit_log (at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_id_at ON audit_log (actor_id, at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_target_at ON audit_log (target, at);
+
+CREATE OR REPLACE FUNCTION reject_audit_log_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION reject_audit_log_change();
@@ -0,0 +1,4 @@
+-- Takes back the permissions granted by the up migration
+UPDATE rbac_roles SET permissions = array_remove(permissions, 'update:user') WHERE role = 'REGULAR';
+UPDATE rbac_roles SET permissions = array_remove(array_remove(permissions, 'delete:any_post'), 'delete:any_comment')
+WHERE role = 'MODERATOR';
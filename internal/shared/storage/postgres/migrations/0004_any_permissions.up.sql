@@ -0,0 +1,11 @@
+-- Owners need update:user to change their own username, and moderators delete:any_post and
+-- delete:any_comment to delete what others wrote, now that ownership rules check permissions
+-- instead of role names. Roles an operator removed or changed are left alone.
+UPDATE rbac_roles SET permissions = array_append(permissions, 'update:user')
+WHERE role = 'REGULAR' AND NOT 'update:user' = ANY(permissions);
+
+UPDATE rbac_roles SET permissions = array_append(permissions, 'delete:any_post')
+WHERE role = 'MODERATOR' AND NOT 'delete:any_post' = ANY(permissions);
+
+UPDATE rbac_roles SET permissions = array_append(permissions, 'delete:any_comment')
+WHERE role = 'MODERATOR' AND NOT 'delete:any_comment' = ANY(permissions);
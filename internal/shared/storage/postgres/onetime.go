@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth/onetime"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// UsedTokenStore implements onetime.Store on top of the used_tokens table
+type UsedTokenStore struct {
+	db *pgxpool.Pool
+}
+
+func NewUsedTokenStore(db *pgxpool.Pool) *UsedTokenStore {
+	return &UsedTokenStore{db: db}
+}
+
+// Use records a used token and drops the ones that have expired since
+func (s *UsedTokenStore) Use(ctx context.Context, tokenId string, expiresAt time.Time) error {
+	if _, err := s.db.Exec(ctx, `DELETE FROM used_tokens WHERE expires_at <= NOW()`); err != nil {
+		return err
+	}
+	tag, err := s.db.Exec(ctx, `
+        INSERT INTO used_tokens (token_id, expires_at) VALUES ($1, $2)
+        ON CONFLICT (token_id) DO NOTHING
+    `, tokenId, expiresAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return onetime.ErrTokenUsed
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/iammrsea/social-app/internal/shared/outbox"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AppendToOutbox writes events to the outbox table within tx, so they are committed or rolled back
+// together with the aggregate that raised them
+func AppendToOutbox(ctx context.Context, tx pgx.Tx, evts []events.Event) error {
+	if len(evts) == 0 {
+		return nil
+	}
+	messages, err := outbox.NewMessages(evts)
+	if err != nil {
+		return err
+	}
+	batch := &pgx.Batch{}
+	for _, msg := range messages {
+		batch.Queue(`
+            INSERT INTO outbox (id, event_name, payload, occurred_at, created_at)
+            VALUES ($1, $2, $3, $4, $5)
+        `, msg.Id, msg.EventName, msg.Payload, msg.OccurredAt, msg.CreatedAt)
+	}
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+// OutboxStore implements outbox.Store on top of the outbox table
+type OutboxStore struct {
+	db *pgxpool.Pool
+}
+
+func NewOutboxStore(db *pgxpool.Pool) *OutboxStore {
+	return &OutboxStore{db: db}
+}
+
+func (s *OutboxStore) Pending(ctx context.Context, limit int) ([]outbox.Message, error) {
+	query := `
+        SELECT id, event_name, payload, occurred_at, created_at, attempts
+        FROM outbox
+        WHERE dispatched_at IS NULL AND attempts < $1
+        ORDER BY sequence
+        LIMIT $2
+    `
+	rows, err := s.db.Query(ctx, query, outbox.MaxDeliveryAttempts, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []outbox.Message{}
+	for rows.Next() {
+		var msg outbox.Message
+		if err := rows.Scan(&msg.Id, &msg.EventName, &msg.Payload, &msg.OccurredAt, &msg.CreatedAt, &msg.Attempts); err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	return messages, rows.Err()
+}
+
+func (s *OutboxStore) MarkDispatched(ctx context.Context, id string) error {
+	_, err := s.db.Exec(ctx, `UPDATE outbox SET dispatched_at = NOW() WHERE id = $1`, id)
+	return err
+}
+
+func (s *OutboxStore) MarkFailed(ctx context.Context, id string, reason string) error {
+	_, err := s.db.Exec(ctx, `UPDATE outbox SET attempts = attempts + 1, last_error = $2 WHERE id = $1`, id, reason)
+	return err
+}
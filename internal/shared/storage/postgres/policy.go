@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PolicyStore implements rbac.PolicySource on top of the rbac_roles and rbac_unverified_permissions
+// tables
+type PolicyStore struct {
+	db *pgxpool.Pool
+}
+
+func NewPolicyStore(db *pgxpool.Pool) *PolicyStore {
+	return &PolicyStore{db: db}
+}
+
+func (s *PolicyStore) LoadPolicy(ctx context.Context) (rbac.PolicyConfig, error) {
+	conf := rbac.PolicyConfig{Roles: map[rbac.UserRole]rbac.RoleConfig{}}
+	rows, err := s.db.Query(ctx, `SELECT role, inherits, permissions, all_permissions FROM rbac_roles`)
+	if err != nil {
+		return conf, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			role                  string
+			inherits, permissions []string
+			all                   bool
+		)
+		if err := rows.Scan(&role, &inherits, &permissions, &all); err != nil {
+			return conf, err
+		}
+		conf.Roles[rbac.UserRole(role)] = rbac.RoleConfig{
+			Inherits:    toRoles(inherits),
+			Permissions: toPermissions(permissions),
+			All:         all,
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return conf, err
+	}
+	if len(conf.Roles) == 0 {
+		return conf, rbac.ErrPolicyNotFound
+	}
+
+	rows, err = s.db.Query(ctx, `SELECT permission FROM rbac_unverified_permissions`)
+	if err != nil {
+		return conf, err
+	}
+	unverified, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return conf, err
+	}
+	conf.Unverified = toPermissions(unverified)
+	return conf, nil
+}
+
+// SavePolicy replaces the stored policy with conf. The reloaders of the app put it in force.
+func (s *PolicyStore) SavePolicy(ctx context.Context, conf rbac.PolicyConfig) error {
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM rbac_roles`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM rbac_unverified_permissions`); err != nil {
+			return err
+		}
+		for role, rc := range conf.Roles {
+			inherits := make([]string, len(rc.Inherits))
+			for i, parent := range rc.Inherits {
+				inherits[i] = string(parent)
+			}
+			permissions := make([]string, len(rc.Permissions))
+			for i, perm := range rc.Permissions {
+				permissions[i] = string(perm)
+			}
+			if _, err := tx.Exec(ctx, `
+                INSERT INTO rbac_roles (role, inherits, permissions, all_permissions)
+                VALUES ($1, $2, $3, $4)
+            `, string(role), inherits, permissions, rc.All); err != nil {
+				return err
+			}
+		}
+		for _, perm := range conf.Unverified {
+			if _, err := tx.Exec(ctx, `INSERT INTO rbac_unverified_permissions (permission) VALUES ($1)`, string(perm)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func toRoles(values []string) []rbac.UserRole {
+	roles := make([]rbac.UserRole, len(values))
+	for i, value := range values {
+		roles[i] = rbac.UserRole(value)
+	}
+	return roles
+}
+
+func toPermissions(values []string) []rbac.Permission {
+	perms := make([]rbac.Permission, len(values))
+	for i, value := range values {
+		perms[i] = rbac.Permission(value)
+	}
+	return perms
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth/sessions"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SessionStore implements sessions.Store on top of the refresh_tokens table
+type SessionStore struct {
+	db *pgxpool.Pool
+}
+
+func NewSessionStore(db *pgxpool.Pool) *SessionStore {
+	return &SessionStore{db: db}
+}
+
+const insertRefreshToken = `
+    INSERT INTO refresh_tokens (
+        id, session_id, user_id, token_hash, device, access_token_id, access_token_expires_at,
+        created_at, expires_at, mfa
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+`
+
+func refreshTokenArgs(token sessions.RefreshToken) []any {
+	return []any{
+		token.Id, token.SessionId, token.UserId, token.TokenHash, token.Device,
+		token.AccessToken.Id, token.AccessToken.ExpiresAt, token.CreatedAt, token.ExpiresAt, token.MFA,
+	}
+}
+
+func (s *SessionStore) Save(ctx context.Context, token sessions.RefreshToken) error {
+	_, err := s.db.Exec(ctx, insertRefreshToken, refreshTokenArgs(token)...)
+	return err
+}
+
+func (s *SessionStore) FindByHash(ctx context.Context, tokenHash string) (sessions.RefreshToken, error) {
+	query := `
+        SELECT id, session_id, user_id, token_hash, device, access_token_id, access_token_expires_at,
+            created_at, expires_at, mfa, rotated_at IS NOT NULL, revoked_at IS NOT NULL
+        FROM refresh_tokens
+        WHERE token_hash = $1
+    `
+	var token sessions.RefreshToken
+	err := s.db.QueryRow(ctx, query, tokenHash).Scan(
+		&token.Id, &token.SessionId, &token.UserId, &token.TokenHash, &token.Device,
+		&token.AccessToken.Id, &token.AccessToken.ExpiresAt, &token.CreatedAt, &token.ExpiresAt,
+		&token.MFA, &token.Rotated, &token.Revoked,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return token, sessions.ErrRefreshTokenNotFound
+	}
+	return token, err
+}
+
+func (s *SessionStore) Rotate(ctx context.Context, currentId string, next sessions.RefreshToken, now time.Time) error {
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+            UPDATE refresh_tokens SET rotated_at = $2
+            WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL
+        `, currentId, now)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return sessions.ErrRefreshTokenReused
+		}
+		_, err = tx.Exec(ctx, insertRefreshToken, refreshTokenArgs(next)...)
+		return err
+	})
+}
+
+func (s *SessionStore) RevokeSession(ctx context.Context, sessionId string, now time.Time) ([]sessions.AccessToken, error) {
+	return s.revoke(ctx, `session_id = $1`, sessionId, now)
+}
+
+func (s *SessionStore) RevokeUserSessions(ctx context.Context, userId string, now time.Time) ([]sessions.AccessToken, error) {
+	return s.revoke(ctx, `user_id = $1`, userId, now)
+}
+
+func (s *SessionStore) revoke(ctx context.Context, condition string, value string, now time.Time) ([]sessions.AccessToken, error) {
+	rows, err := s.db.Query(ctx, `
+        UPDATE refresh_tokens SET revoked_at = $2
+        WHERE `+condition+` AND revoked_at IS NULL
+        RETURNING access_token_id, access_token_expires_at
+    `, value, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	revoked := []sessions.AccessToken{}
+	for rows.Next() {
+		var token sessions.AccessToken
+		if err := rows.Scan(&token.Id, &token.ExpiresAt); err != nil {
+			return nil, err
+		}
+		if token.ExpiresAt.After(now) {
+			revoked = append(revoked, token)
+		}
+	}
+	return revoked, rows.Err()
+}
+
+// Denylist implements sessions.Denylist on top of the revoked_access_tokens table
+type Denylist struct {
+	db *pgxpool.Pool
+}
+
+func NewDenylist(db *pgxpool.Pool) *Denylist {
+	return &Denylist{db: db}
+}
+
+// Deny adds tokens to the denylist and drops the ones that have expired since
+func (d *Denylist) Deny(ctx context.Context, tokens []sessions.AccessToken) error {
+	batch := &pgx.Batch{}
+	batch.Queue(`DELETE FROM revoked_access_tokens WHERE expires_at <= NOW()`)
+	for _, token := range tokens {
+		batch.Queue(`
+            INSERT INTO revoked_access_tokens (token_id, expires_at) VALUES ($1, $2)
+            ON CONFLICT (token_id) DO NOTHING
+        `, token.Id, token.ExpiresAt)
+	}
+	return d.db.SendBatch(ctx, batch).Close()
+}
+
+func (d *Denylist) IsRevoked(ctx context.Context, tokenId string) (bool, error) {
+	var revoked bool
+	err := d.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM revoked_access_tokens WHERE token_id = $1)`, tokenId).Scan(&revoked)
+	return revoked, err
+}
@@ -4,21 +4,80 @@ import (
 	"context"
 	"fmt"
 
+	contentDomain "github.com/iammrsea/social-app/internal/content/domain"
+	memoryPostRepo "github.com/iammrsea/social-app/internal/content/infra/db/memory"
+	mongoPostRepo "github.com/iammrsea/social-app/internal/content/infra/db/mongodb"
+	pgPostRepo "github.com/iammrsea/social-app/internal/content/infra/db/postgres"
+	interactionDomain "github.com/iammrsea/social-app/internal/interaction/domain"
+	memoryVoteRepo "github.com/iammrsea/social-app/internal/interaction/infra/db/memory"
+	mongoVoteRepo "github.com/iammrsea/social-app/internal/interaction/infra/db/mongodb"
+	pgVoteRepo "github.com/iammrsea/social-app/internal/interaction/infra/db/postgres"
+	moderationDomain "github.com/iammrsea/social-app/internal/moderation/domain"
+	memoryReportRepo "github.com/iammrsea/social-app/internal/moderation/infra/db/memory"
+	mongoReportRepo "github.com/iammrsea/social-app/internal/moderation/infra/db/mongodb"
+	pgReportRepo "github.com/iammrsea/social-app/internal/moderation/infra/db/postgres"
+	"github.com/iammrsea/social-app/internal/shared/audit"
+	"github.com/iammrsea/social-app/internal/shared/auth/apikeys"
+	"github.com/iammrsea/social-app/internal/shared/auth/mfa"
+	"github.com/iammrsea/social-app/internal/shared/auth/onetime"
+	"github.com/iammrsea/social-app/internal/shared/auth/sessions"
 	"github.com/iammrsea/social-app/internal/shared/config"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/shared/outbox"
+	"github.com/iammrsea/social-app/internal/shared/storage/migrate"
 	"github.com/iammrsea/social-app/internal/shared/storage/mongodb"
 	"github.com/iammrsea/social-app/internal/shared/storage/postgres"
 	"github.com/iammrsea/social-app/internal/user/domain"
+	memoryUserRepo "github.com/iammrsea/social-app/internal/user/infra/repos/memoryimpl"
 	mongoUserRepo "github.com/iammrsea/social-app/internal/user/infra/repos/mongoimpl"
 	pgUserRepo "github.com/iammrsea/social-app/internal/user/infra/repos/postgresimpl"
 )
 
 type Storage struct {
 	Repos Repos
+	// Outbox holds the events saved by the repositories until the relay publishes them
+	Outbox outbox.Store
+	// Sessions holds the refresh tokens of login sessions
+	Sessions sessions.Store
+	// RevokedTokens holds access tokens revoked before they expire
+	RevokedTokens sessions.Denylist
+	// APIKeys holds the hashed personal API keys of users
+	APIKeys apikeys.Store
+	// UsedTokens holds the single-use tokens sent by email that were already used
+	UsedTokens onetime.Store
+	// MFA holds the two-factor authentication secrets and recovery codes of users
+	MFA mfa.Store
+	// Policy holds the role-based access policy, when it is kept in the database
+	Policy rbac.PolicyStore
+	// Audit holds the log of authorization decisions and state-changing commands
+	Audit audit.Store
+	// Migrations applies and reverts the schema of the database. It is nil for the in-memory engine,
+	// which has no schema.
+	Migrations *migrate.Migrator
 }
 
 type Repos struct {
 	UserRepo          domain.UserRepository
 	UserReadModelRepo domain.UserReadModelRepository
+	PostRepo          contentDomain.PostRepository
+	PostReadModelRepo contentDomain.PostReadModelRepository
+
+	CommentRepo          contentDomain.CommentRepository
+	CommentReadModelRepo contentDomain.CommentReadModelRepository
+
+	VoteRepo          interactionDomain.VoteRepository
+	VoteReadModelRepo interactionDomain.VoteReadModelRepository
+
+	ReportRepo          moderationDomain.ReportRepository
+	ReportReadModelRepo moderationDomain.ReportReadModelRepository
+}
+
+// mongoMigrations set up the collections of MongoDB, in order of version. Versions are never reused
+// or changed once released.
+var mongoMigrations = []mongodb.Migration{
+	{Version: 1, Name: "user_indexes", Up: mongoUserRepo.CreateIndexes, Down: mongoUserRepo.DropIndexes},
+	{Version: 2, Name: "user_validator", Up: mongoUserRepo.SetValidator, Down: mongoUserRepo.RemoveValidator},
+	{Version: 3, Name: "any_permissions", Up: mongodb.GrantAnyPermissions, Down: mongodb.RevokeAnyPermissions},
 }
 
 func NewStorage(ctx context.Context, storageEngine config.StorageEngine) (*Storage, func() error, error) {
@@ -35,6 +94,8 @@ func buildStorage(ctx context.Context, storageConfig config.StorageEngineConfig)
 		return buildMongoRepos(ctx, conf)
 	case *config.PostgresConfig:
 		return buildPostgresRepos(ctx, conf)
+	case *config.InMemoryConfig:
+		return buildInMemoryRepos()
 	default:
 		return nil, nil, fmt.Errorf("unsupported storage engine: %s", storageConfig.Engine())
 	}
@@ -47,19 +108,100 @@ func buildMongoRepos(ctx context.Context, conf *config.MongoConfig) (*Storage, f
 		Repos: Repos{
 			UserRepo:          mongoUserRepo.NewUserRepository(db),
 			UserReadModelRepo: mongoUserRepo.NewUserReadModelRepository(db),
+			PostRepo:          mongoPostRepo.NewPostRepository(db),
+			PostReadModelRepo: mongoPostRepo.NewPostReadModelRepository(db),
+
+			CommentRepo:          mongoPostRepo.NewCommentRepository(db),
+			CommentReadModelRepo: mongoPostRepo.NewCommentReadModelRepository(db),
+
+			VoteRepo:          mongoVoteRepo.NewVoteRepository(db),
+			VoteReadModelRepo: mongoVoteRepo.NewVoteReadModelRepository(db),
+
+			ReportRepo:          mongoReportRepo.NewReportRepository(db),
+			ReportReadModelRepo: mongoReportRepo.NewReportReadModelRepository(db),
 		},
+		Outbox:        mongodb.NewOutboxStore(db),
+		Sessions:      mongodb.NewSessionStore(db),
+		RevokedTokens: mongodb.NewDenylist(db),
+		APIKeys:       mongodb.NewAPIKeyStore(db),
+		UsedTokens:    mongodb.NewUsedTokenStore(db),
+		MFA:           mongodb.NewMFAStore(db),
+		Policy:        mongodb.NewPolicyStore(db),
+		Audit:         mongodb.NewAuditStore(db),
+		Migrations:    mongodb.NewMigrator(db, mongoMigrations...),
 	}
 	return storage, closeStorage, nil
 }
 
 func buildPostgresRepos(ctx context.Context, cf *config.PostgresConfig) (*Storage, func() error, error) {
 	pool, closeStorage := postgres.SetupPostgreSQL(ctx, cf)
+	migrations, err := postgres.NewMigrator(pool)
+	if err != nil {
+		_ = closeStorage()
+		return nil, nil, fmt.Errorf("reading migrations: %w", err)
+	}
 	// Repositories
 	storage := &Storage{
 		Repos: Repos{
 			UserRepo:          pgUserRepo.NewUserRepository(pool),
 			UserReadModelRepo: pgUserRepo.NewUserReadModelRepository(pool),
+			PostRepo:          pgPostRepo.NewPostRepository(pool),
+			PostReadModelRepo: pgPostRepo.NewPostReadModelRepository(pool),
+
+			CommentRepo:          pgPostRepo.NewCommentRepository(pool),
+			CommentReadModelRepo: pgPostRepo.NewCommentReadModelRepository(pool),
+
+			VoteRepo:          pgVoteRepo.NewVoteRepository(pool),
+			VoteReadModelRepo: pgVoteRepo.NewVoteReadModelRepository(pool),
+
+			ReportRepo:          pgReportRepo.NewReportRepository(pool),
+			ReportReadModelRepo: pgReportRepo.NewReportReadModelRepository(pool),
 		},
+		Outbox:        postgres.NewOutboxStore(pool),
+		Sessions:      postgres.NewSessionStore(pool),
+		RevokedTokens: postgres.NewDenylist(pool),
+		APIKeys:       postgres.NewAPIKeyStore(pool),
+		UsedTokens:    postgres.NewUsedTokenStore(pool),
+		MFA:           postgres.NewMFAStore(pool),
+		Policy:        postgres.NewPolicyStore(pool),
+		Audit:         postgres.NewAuditStore(pool),
+		Migrations:    migrations,
 	}
 	return storage, closeStorage, nil
 }
+
+func buildInMemoryRepos() (*Storage, func() error, error) {
+	events := outbox.NewMemoryStore()
+	userRepo := memoryUserRepo.NewUserRepository(events)
+	postRepo := memoryPostRepo.NewPostRepository()
+	commentRepo := memoryPostRepo.NewCommentRepository()
+	voteRepo := memoryVoteRepo.NewVoteRepository()
+	reportRepo := memoryReportRepo.NewReportRepository()
+	// Repositories
+	storage := &Storage{
+		Repos: Repos{
+			UserRepo:          userRepo,
+			UserReadModelRepo: userRepo,
+			PostRepo:          postRepo,
+			PostReadModelRepo: postRepo,
+
+			CommentRepo:          commentRepo,
+			CommentReadModelRepo: commentRepo,
+
+			VoteRepo:          voteRepo,
+			VoteReadModelRepo: voteRepo,
+
+			ReportRepo:          reportRepo,
+			ReportReadModelRepo: reportRepo,
+		},
+		Outbox:        events,
+		Sessions:      sessions.NewMemoryStore(),
+		RevokedTokens: sessions.NewMemoryDenylist(),
+		APIKeys:       apikeys.NewMemoryStore(),
+		UsedTokens:    onetime.NewMemoryStore(),
+		MFA:           mfa.NewMemoryStore(),
+		Policy:        rbac.NewMemoryPolicyStore(),
+		Audit:         audit.NewMemoryStore(),
+	}
+	return storage, func() error { return nil }, nil
+}
@@ -0,0 +1,114 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/iammrsea/social-app/internal/shared/storage/postgres"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lucsky/cuid"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// templateDatabase is migrated once, then copied for every test
+const templateDatabase = "socialapp_db"
+
+var (
+	pgOnce sync.Once
+	pgErr  error
+	// pgAdmin is connected to the postgres database, from where test databases are made and dropped
+	pgAdmin *pgxpool.Pool
+	pgURI   string
+	// createMu copies the template one database at a time, Postgres refuses to copy a template in use
+	createMu sync.Mutex
+)
+
+// SetupTestPostgres gives the test a database of its own, migrated to the latest schema, and drops
+// it when the test is over. The container is started by the first test that asks for a database
+// and is removed by testcontainers once the tests are done.
+func SetupTestPostgres(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	ctx := context.Background()
+	pgOnce.Do(func() {
+		pgAdmin, pgURI, pgErr = setupPostgresContainer(ctx)
+	})
+	if pgErr != nil {
+		t.Fatalf("failed to set up postgres: %v", pgErr)
+	}
+
+	dbName := fmt.Sprintf("test_%s", cuid.New())
+	createMu.Lock()
+	_, err := pgAdmin.Exec(ctx, fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s", pgx.Identifier{dbName}.Sanitize(), templateDatabase))
+	createMu.Unlock()
+	if err != nil {
+		t.Fatalf("failed to create database %s: %v", dbName, err)
+	}
+	pool, err := pgxpool.New(ctx, fmt.Sprintf("%s/%s?sslmode=disable", pgURI, dbName))
+	if err != nil {
+		t.Fatalf("failed to connect to database %s: %v", dbName, err)
+	}
+	t.Cleanup(func() {
+		pool.Close()
+		if _, err := pgAdmin.Exec(ctx, fmt.Sprintf("DROP DATABASE %s WITH (FORCE)", pgx.Identifier{dbName}.Sanitize())); err != nil {
+			t.Logf("failed to drop database %s: %v", dbName, err)
+		}
+	})
+	return pool
+}
+
+func setupPostgresContainer(ctx context.Context) (*pgxpool.Pool, string, error) {
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "postgres:15",
+			ExposedPorts: []string{"5432/tcp"},
+			Env: map[string]string{
+				"POSTGRES_USER":     "socialapp",
+				"POSTGRES_PASSWORD": "socialapp_password",
+				"POSTGRES_DB":       templateDatabase,
+			},
+			// The server restarts once the database is initialized
+			WaitingFor: wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
+		},
+		Started: true,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to start container: %w", err)
+	}
+
+	host, _ := container.Host(ctx)
+	port, _ := container.MappedPort(ctx, "5432/tcp")
+	uri := fmt.Sprintf("postgres://socialapp:socialapp_password@%s:%s", host, port.Port())
+
+	if err := migrateTemplate(ctx, uri); err != nil {
+		_ = container.Terminate(ctx)
+		return nil, "", err
+	}
+	admin, err := pgxpool.New(ctx, uri+"/postgres?sslmode=disable")
+	if err != nil {
+		_ = container.Terminate(ctx)
+		return nil, "", fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	return admin, uri, nil
+}
+
+// migrateTemplate applies the migrations to the template database and disconnects from it, as a
+// database can't be copied while it is in use
+func migrateTemplate(ctx context.Context, uri string) error {
+	pool, err := pgxpool.New(ctx, fmt.Sprintf("%s/%s?sslmode=disable", uri, templateDatabase))
+	if err != nil {
+		return fmt.Errorf("failed to connect to the template database: %w", err)
+	}
+	defer pool.Close()
+	migrator, err := postgres.NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		return fmt.Errorf("failed to migrate the template database: %w", err)
+	}
+	return nil
+}
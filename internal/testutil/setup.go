@@ -53,7 +53,9 @@ func setupMongoDbContainer(ctx context.Context, t *testing.T) (string, func()) {
 		ContainerRequest: testcontainers.ContainerRequest{
 			Image:        "mongo:7",
 			ExposedPorts: []string{"27017/tcp"},
-			WaitingFor:   wait.ForLog("Waiting for connections"),
+			// Repositories write to the outbox in transactions, which need a replica set
+			Cmd:        []string{"--replSet", "rs0", "--bind_ip_all"},
+			WaitingFor: wait.ForLog("Waiting for connections"),
 		},
 		Started: true,
 	})
@@ -65,9 +67,16 @@ func setupMongoDbContainer(ctx context.Context, t *testing.T) (string, func()) {
 		_ = container.Terminate(ctx)
 	}
 
+	initiate := []string{"mongosh", "--quiet", "--eval",
+		"rs.initiate(); while (!db.hello().isWritablePrimary) { sleep(100) }"}
+	if code, _, err := container.Exec(ctx, initiate); err != nil || code != 0 {
+		terminate()
+		t.Fatalf("failed to initiate replica set (exit code %d): %v", code, err)
+	}
+
 	host, _ := container.Host(ctx)
 	port, _ := container.MappedPort(ctx, "27017/tcp")
-	mongoURI := fmt.Sprintf("mongodb://%s:%s", host, port.Port())
+	mongoURI := fmt.Sprintf("mongodb://%s:%s/?directConnection=true", host, port.Port())
 
 	return mongoURI, terminate
 }
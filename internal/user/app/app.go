@@ -11,17 +11,44 @@ type Application struct {
 }
 
 type CommandHandler struct {
-	RegisterUser       command.RegisterUserHandler
-	RevokeAwardedBadge command.RevokeAwardedBadgeHandler
-	AwardBadge         command.AwardBadgeHandler
-	MakeModerator      command.MakeModeratorHandler
-	ChangeUsername     command.ChangeUsernameHandler
-	BanUser            command.BanUserHandler
-	UnbanUser          command.UnbanUserHandler
+	RegisterUser             command.RegisterUserHandler
+	RevokeAwardedBadge       command.RevokeAwardedBadgeHandler
+	AwardBadge               command.AwardBadgeHandler
+	AssignRole               command.AssignRoleHandler
+	RemoveRole               command.RemoveRoleHandler
+	DefineRole               command.DefineRoleHandler
+	DeleteRole               command.DeleteRoleHandler
+	ChangeUsername           command.ChangeUsernameHandler
+	BanUser                  command.BanUserHandler
+	UnbanUser                command.UnbanUserHandler
+	LiftExpiredBans          command.LiftExpiredBansHandler
+	Logout                   command.LogoutHandler
+	LogoutAllSessions        command.LogoutAllSessionsHandler
+	CreateAPIKey             command.CreateAPIKeyHandler
+	RevokeAPIKey             command.RevokeAPIKeyHandler
+	VerifyEmail              command.VerifyEmailHandler
+	RequestEmailVerification command.RequestEmailVerificationHandler
+	RequestPasswordReset     command.RequestPasswordResetHandler
+	ResetPassword            command.ResetPasswordHandler
+	SetupMFA                 command.SetupMFAHandler
+	EnableMFA                command.EnableMFAHandler
+	DisableMFA               command.DisableMFAHandler
+	RequestAccountDeletion   command.RequestAccountDeletionHandler
+	CancelAccountDeletion    command.CancelAccountDeletionHandler
+	EraseDueAccounts         command.EraseDueAccountsHandler
+	Login                    command.LoginHandler
+	CompleteLogin            command.CompleteLoginHandler
+	RefreshToken             command.RefreshTokenHandler
 }
 
 type QueryHandler struct {
-	GetUserById    query.GetUserByIdHandler
-	GetUsers       query.GetUsersHandler
-	GetUserByEmail query.GetUserByEmailHandler
+	GetUserById       query.GetUserByIdHandler
+	GetUsers          query.GetUsersHandler
+	GetUserByEmail    query.GetUserByEmailHandler
+	GetAPIKeys        query.GetAPIKeysHandler
+	GetMFAStatus      query.GetMFAStatusHandler
+	GetRoles          query.GetRolesHandler
+	GetRole           query.GetRoleHandler
+	GetAuditLog       query.GetAuditLogHandler
+	ExportAccountData query.ExportAccountDataHandler
 }
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/user/app/command"
+)
+
+// What the audit log records of the commands that change users and roles
+
+func describeBanUser(cmd command.BanUser) (string, string) {
+	return cmd.Id, cmd.Reason
+}
+
+func describeUnbanUser(cmd command.UnbanUser) (string, string) {
+	return cmd.Id, ""
+}
+
+func describeAwardBadge(cmd command.AwardBadge) (string, string) {
+	return cmd.Id, cmd.Badge
+}
+
+func describeRevokeBadge(cmd command.RevokeAwardedBadge) (string, string) {
+	return cmd.Id, cmd.Badge
+}
+
+func describeAssignRole(cmd command.AssignRole) (string, string) {
+	return cmd.Id, rbac.NewUserRole(cmd.Role.String()).String()
+}
+
+func describeRemoveRole(cmd command.RemoveRole) (string, string) {
+	return cmd.Id, rbac.NewUserRole(cmd.Role.String()).String()
+}
+
+func describeDefineRole(cmd command.DefineRole) (string, string) {
+	if cmd.All {
+		return rbac.NewUserRole(cmd.Role.String()).String(), "all permissions"
+	}
+	perms := make([]string, len(cmd.Permissions))
+	for i, perm := range cmd.Permissions {
+		perms[i] = string(perm)
+	}
+	return rbac.NewUserRole(cmd.Role.String()).String(), strings.Join(perms, ",")
+}
+
+func describeDeleteRole(cmd command.DeleteRole) (string, string) {
+	return rbac.NewUserRole(cmd.Role.String()).String(), ""
+}
+
+func describeRequestAccountDeletion(cmd command.RequestAccountDeletion) (string, string) {
+	return cmd.Id, "requested"
+}
+
+func describeCancelAccountDeletion(cmd command.CancelAccountDeletion) (string, string) {
+	return cmd.Id, "cancelled"
+}
@@ -0,0 +1,88 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+// RequestAccountDeletion schedules the account of user Id to be deleted once the grace period is
+// over. Users can delete their own account; deleting someone else's takes rbac.DeleteUser.
+type RequestAccountDeletion struct {
+	Id string
+}
+
+type RequestAccountDeletionHandler = shared.CommandHandler[RequestAccountDeletion]
+
+type requestAccountDeletionHandler struct {
+	userRepo    domain.UserRepository
+	gracePeriod time.Duration
+	guard       guards.Guards
+}
+
+func NewRequestAccountDeletionHandler(userRepo domain.UserRepository, gracePeriod time.Duration, guard guards.Guards) RequestAccountDeletionHandler {
+	if userRepo == nil || guard == nil {
+		panic("nil user repository or guard")
+	}
+	return &requestAccountDeletionHandler{userRepo: userRepo, gracePeriod: gracePeriod, guard: guard}
+}
+
+func (r *requestAccountDeletionHandler) Handle(ctx context.Context, cmd RequestAccountDeletion) error {
+	if err := authorizeAccountDeletion(ctx, r.guard, cmd.Id); err != nil {
+		return err
+	}
+	return retryOnConflict(ctx, func() error {
+		return r.userRepo.ScheduleDeletion(ctx, cmd.Id, func(user *domain.User) error {
+			return user.RequestDeletion(time.Now(), r.gracePeriod)
+		})
+	})
+}
+
+// CancelAccountDeletion keeps the account of user Id, as long as the grace period isn't over.
+// It takes the same permissions as requesting the deletion.
+type CancelAccountDeletion struct {
+	Id string
+}
+
+type CancelAccountDeletionHandler = shared.CommandHandler[CancelAccountDeletion]
+
+type cancelAccountDeletionHandler struct {
+	userRepo domain.UserRepository
+	guard    guards.Guards
+}
+
+func NewCancelAccountDeletionHandler(userRepo domain.UserRepository, guard guards.Guards) CancelAccountDeletionHandler {
+	if userRepo == nil || guard == nil {
+		panic("nil user repository or guard")
+	}
+	return &cancelAccountDeletionHandler{userRepo: userRepo, guard: guard}
+}
+
+func (c *cancelAccountDeletionHandler) Handle(ctx context.Context, cmd CancelAccountDeletion) error {
+	if err := authorizeAccountDeletion(ctx, c.guard, cmd.Id); err != nil {
+		return err
+	}
+	return retryOnConflict(ctx, func() error {
+		return c.userRepo.ScheduleDeletion(ctx, cmd.Id, func(user *domain.User) error {
+			return user.CancelDeletion(time.Now())
+		})
+	})
+}
+
+// authorizeAccountDeletion lets users delete their own account and those with rbac.DeleteUser
+// delete anyone's. An API key needs rbac.DeleteUser in its scopes even for the account of its owner.
+func authorizeAccountDeletion(ctx context.Context, guard guards.Guards, userId string) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if authUser == nil || authUser.Id == "" {
+		return rbac.ErrUnauthorized
+	}
+	if authUser.Id == userId && authUser.APIKeyId == "" {
+		return nil
+	}
+	return guard.Authorize(ctx, authUser.Subject(), rbac.DeleteUser)
+}
@@ -31,10 +31,12 @@ func NewAwardBadgeHandler(userRepo domain.UserRepository, guard guards.Guards) A
 
 func (a *awardBadgeHandler) Handle(ctx context.Context, cmd AwardBadge) error {
 	authUser := auth.GetUserFromCtx(ctx)
-	if err := a.guard.Authorize(authUser.Role, rbac.AwardBadge); err != nil {
+	if err := a.guard.Authorize(ctx, authUser.Subject(), rbac.AwardBadge); err != nil {
 		return err
 	}
-	return a.userRepo.AwardBadge(ctx, cmd.Id, func(user *domain.User) error {
-		return user.AwardBadge(cmd.Badge)
+	return retryOnConflict(ctx, func() error {
+		return a.userRepo.AwardBadge(ctx, cmd.Id, func(user *domain.User) error {
+			return user.AwardBadge(cmd.Badge)
+		})
 	})
 }
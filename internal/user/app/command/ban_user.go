@@ -33,10 +33,12 @@ func NewBanUserHandler(userRepo domain.UserRepository, guard guards.Guards) BanU
 
 func (a *banUserHandler) Handle(ctx context.Context, cmd BanUser) error {
 	authUser := auth.GetUserFromCtx(ctx)
-	if err := a.guard.Authorize(authUser.Role, rbac.BanUser); err != nil {
+	if err := a.guard.Authorize(ctx, authUser.Subject(), rbac.BanUser); err != nil {
 		return err
 	}
-	return a.userRepo.BanUser(ctx, cmd.Id, func(user *domain.User) error {
-		return user.Ban(cmd.Reason, cmd.IsIndefinitely, cmd.Timeline)
+	return retryOnConflict(ctx, func() error {
+		return a.userRepo.BanUser(ctx, cmd.Id, func(user *domain.User) error {
+			return user.Ban(cmd.Reason, cmd.IsIndefinitely, cmd.Timeline)
+		})
 	})
 }
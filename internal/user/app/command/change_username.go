@@ -5,9 +5,9 @@ import (
 	"errors"
 
 	"github.com/iammrsea/social-app/internal/shared"
-	"github.com/iammrsea/social-app/internal/shared/auth"
 	"github.com/iammrsea/social-app/internal/shared/custom_errors"
 	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/abac"
 	"github.com/iammrsea/social-app/internal/user/domain"
 )
 
@@ -31,7 +31,6 @@ func NewChangeUsernameHandler(userRepo domain.UserRepository, guard guards.Guard
 }
 
 func (c *changeUsernameHandler) Handle(ctx context.Context, cmd ChangeUsername) error {
-	authUser := auth.GetUserFromCtx(ctx)
 	userExists, err := c.userRepo.UserExists(ctx, "", cmd.Username)
 
 	if err != nil {
@@ -42,10 +41,12 @@ func (c *changeUsernameHandler) Handle(ctx context.Context, cmd ChangeUsername)
 	if userExists {
 		return domain.ErrEmailOrUsernameAlreadyExists
 	}
-	if err := c.guard.CanChangeUsername(cmd.Id, authUser); err != nil {
+	if err := c.guard.Can(ctx, abac.Edit, abac.User(cmd.Id)); err != nil {
 		return err
 	}
-	return c.userRepo.ChangeUsername(ctx, cmd.Id, func(user *domain.User) error {
-		return user.ChangeUsername(cmd.Username)
+	return retryOnConflict(ctx, func() error {
+		return c.userRepo.ChangeUsername(ctx, cmd.Id, func(user *domain.User) error {
+			return user.ChangeUsername(cmd.Username)
+		})
 	})
 }
@@ -0,0 +1,58 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/onetime"
+	"github.com/iammrsea/social-app/internal/shared/custom_errors"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+// CompleteLogin is the second step of logging in for users with two-factor authentication. Code
+// comes from the user's authenticator app, or is one of their recovery codes.
+type CompleteLogin struct {
+	MFAToken string
+	Code     string
+	Device   string
+}
+
+type CompleteLoginHandler = shared.ResultCommandHandler[CompleteLogin, *auth.Token]
+
+type completeLoginHandler struct {
+	userRepo     domain.UserRepository
+	sessions     SessionStarter
+	secondFactor SecondFactor
+}
+
+// NewCompleteLoginHandler starts a session once the code checks out. The MFA token is the
+// credential, so there is no guard.
+func NewCompleteLoginHandler(userRepo domain.UserRepository, sessions SessionStarter, secondFactor SecondFactor) CompleteLoginHandler {
+	if userRepo == nil || sessions == nil || secondFactor == nil {
+		panic("nil user repository, session starter or second factor")
+	}
+	return &completeLoginHandler{userRepo: userRepo, sessions: sessions, secondFactor: secondFactor}
+}
+
+func (c *completeLoginHandler) Handle(ctx context.Context, cmd CompleteLogin) (*auth.Token, error) {
+	userId, err := c.secondFactor.CompleteChallenge(ctx, cmd.MFAToken, cmd.Code)
+	if err != nil {
+		return nil, err
+	}
+	user, err := c.userRepo.GetUserBy(ctx, "id", userId)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, onetime.ErrInvalidToken
+		}
+		return nil, custom_errors.ErrInternalServerError
+	}
+	return c.sessions.Start(ctx, auth.AuthenticatedUser{
+		Id:              user.Id(),
+		Email:           user.Email(),
+		Roles:           user.Roles(),
+		EmailUnverified: !user.IsEmailVerified(),
+		MFA:             true,
+	}, cmd.Device)
+}
@@ -0,0 +1,63 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/apikeys"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+// CreateAPIKey creates a personal API key for the authenticated user. Key is generated by the
+// caller with apikeys.NewKey and handed to the user; only its hash is kept.
+type CreateAPIKey struct {
+	Id        string
+	Key       string
+	Name      string
+	Scopes    []rbac.Permission
+	ExpiresAt *time.Time
+}
+
+type CreateAPIKeyHandler = shared.CommandHandler[CreateAPIKey]
+
+// APIKeyCreator creates personal API keys
+type APIKeyCreator interface {
+	Create(ctx context.Context, newKey apikeys.NewAPIKey) (apikeys.APIKey, error)
+}
+
+type createAPIKeyHandler struct {
+	apiKeys APIKeyCreator
+	guard   guards.Guards
+}
+
+func NewCreateAPIKeyHandler(apiKeys APIKeyCreator, guard guards.Guards) CreateAPIKeyHandler {
+	if apiKeys == nil || guard == nil {
+		panic("nil api key creator or guard")
+	}
+	return &createAPIKeyHandler{apiKeys: apiKeys, guard: guard}
+}
+
+func (c *createAPIKeyHandler) Handle(ctx context.Context, cmd CreateAPIKey) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := c.guard.Authorize(ctx, authUser.Subject(), rbac.ManageAPIKeys); err != nil {
+		return err
+	}
+	// A key can't grant more than the user, or the key the user authenticated with, is allowed
+	for _, scope := range cmd.Scopes {
+		if err := c.guard.Authorize(ctx, authUser.Subject(), scope); err != nil {
+			return err
+		}
+	}
+	_, err := c.apiKeys.Create(ctx, apikeys.NewAPIKey{
+		Id:        cmd.Id,
+		UserId:    authUser.Id,
+		Key:       cmd.Key,
+		Name:      cmd.Name,
+		Scopes:    cmd.Scopes,
+		ExpiresAt: cmd.ExpiresAt,
+	})
+	return err
+}
@@ -0,0 +1,76 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+// AccountDataEraser anonymizes what the rest of the app keeps about a user, so the user can be
+// deleted without deleting their posts, comments and reports along with them
+type AccountDataEraser interface {
+	EraseAccountData(ctx context.Context, userId string) error
+}
+
+// EraseDueAccounts deletes the users whose grace period ended at or before Now, at most BatchSize
+// of them per run. Their data elsewhere is anonymized first. It is run by the account deletion
+// scheduler and is not exposed through the API, so it doesn't check the caller's permissions.
+type EraseDueAccounts struct {
+	Now       time.Time
+	BatchSize int
+}
+
+type EraseDueAccountsHandler = shared.CommandHandler[EraseDueAccounts]
+
+type eraseDueAccountsHandler struct {
+	userRepo domain.UserRepository
+	eraser   AccountDataEraser
+	sessions SessionRevoker
+}
+
+func NewEraseDueAccountsHandler(userRepo domain.UserRepository, eraser AccountDataEraser, sessions SessionRevoker) EraseDueAccountsHandler {
+	if userRepo == nil || eraser == nil || sessions == nil {
+		panic("nil user repository, account data eraser or session revoker")
+	}
+	return &eraseDueAccountsHandler{userRepo: userRepo, eraser: eraser, sessions: sessions}
+}
+
+func (e *eraseDueAccountsHandler) Handle(ctx context.Context, cmd EraseDueAccounts) error {
+	userIds, err := e.userRepo.DueDeletions(ctx, cmd.Now, cmd.BatchSize)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, userId := range userIds {
+		if err := e.erase(ctx, userId, cmd.Now); err != nil {
+			errs = append(errs, fmt.Errorf("erasing user %s: %w", userId, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// erase ends the sessions of the user and anonymizes their data before deleting them, as what
+// refers to the user must be gone by then. The deletion can no longer be cancelled, so an erasure
+// that fails halfway is completed by the next run.
+func (e *eraseDueAccountsHandler) erase(ctx context.Context, userId string, now time.Time) error {
+	if err := e.sessions.LogoutAll(ctx, userId); err != nil {
+		return err
+	}
+	if err := e.eraser.EraseAccountData(ctx, userId); err != nil {
+		return err
+	}
+	err := retryOnConflict(ctx, func() error {
+		return e.userRepo.DeleteUser(ctx, userId, func(user *domain.User) error {
+			return user.Erase(now)
+		})
+	})
+	// The user may have been deleted since the lookup
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+// LiftExpiredBans unbans users whose time-boxed ban ended at or before Now, at most BatchSize of
+// them per run. It is run by the ban expiry scheduler and is not exposed through the API, so it
+// doesn't check the caller's permissions.
+type LiftExpiredBans struct {
+	Now       time.Time
+	BatchSize int
+}
+
+type LiftExpiredBansHandler = shared.CommandHandler[LiftExpiredBans]
+
+type liftExpiredBansHandler struct {
+	userRepo domain.UserRepository
+}
+
+func NewLiftExpiredBansHandler(userRepo domain.UserRepository) LiftExpiredBansHandler {
+	if userRepo == nil {
+		panic("nil user repository")
+	}
+	return &liftExpiredBansHandler{userRepo: userRepo}
+}
+
+func (l *liftExpiredBansHandler) Handle(ctx context.Context, cmd LiftExpiredBans) error {
+	userIds, err := l.userRepo.ExpiredBans(ctx, cmd.Now, cmd.BatchSize)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, userId := range userIds {
+		err := retryOnConflict(ctx, func() error {
+			return l.userRepo.UnbanUser(ctx, userId, func(user *domain.User) error {
+				return user.LiftExpiredBan(cmd.Now)
+			})
+		})
+		// The user may have been unbanned, banned again or deleted since the lookup
+		if err != nil && !errors.Is(err, domain.ErrBanNotExpired) &&
+			!errors.Is(err, domain.ErrUserIsNotBanned) && !errors.Is(err, domain.ErrUserNotFound) {
+			errs = append(errs, fmt.Errorf("lifting ban of user %s: %w", userId, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/custom_errors"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+type LoginHandler = shared.ResultCommandHandler[Login, *auth.Token]
+
+type Login struct {
+	Email    string
+	Password string
+	// Device names the device the user logs in on, to tell sessions apart
+	Device string
+}
+
+// SessionStarter opens a login session and issues its first tokens
+type SessionStarter interface {
+	Start(ctx context.Context, user auth.AuthenticatedUser, device string) (*auth.Token, error)
+}
+
+// SecondFactor runs the second step of logging in for users with two-factor authentication
+type SecondFactor interface {
+	IsEnabled(ctx context.Context, userId string) (bool, error)
+	// Challenge issues the token the user trades, along with a code, for a session
+	Challenge(userId, email string) (token string, expiresAt time.Time, err error)
+	// CompleteChallenge checks the code and returns the id of the user the token was issued to
+	CompleteChallenge(ctx context.Context, token, code string) (userId string, err error)
+}
+
+type loginHandler struct {
+	userRepo     domain.UserRepository
+	sessions     SessionStarter
+	secondFactor SecondFactor
+}
+
+// NewLoginHandler checks a user's password and starts a session. Users with two-factor
+// authentication get an MFA token instead, for CompleteLogin. Anyone can log in, so there is no
+// guard. Banned users can still log in to read; the ban stops their commands.
+func NewLoginHandler(userRepo domain.UserRepository, sessions SessionStarter, secondFactor SecondFactor) LoginHandler {
+	if userRepo == nil || sessions == nil || secondFactor == nil {
+		panic("nil user repository, session starter or second factor")
+	}
+	return &loginHandler{userRepo: userRepo, sessions: sessions, secondFactor: secondFactor}
+}
+
+func (l *loginHandler) Handle(ctx context.Context, cmd Login) (*auth.Token, error) {
+	user, err := l.userRepo.GetUserBy(ctx, "email", strings.TrimSpace(cmd.Email))
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		return nil, custom_errors.ErrInternalServerError
+	}
+
+	// Unknown emails go through the same password check so they can't be told apart by timing
+	passwordHash := ""
+	if user != nil {
+		passwordHash = user.PasswordHash()
+	}
+	if err := auth.CheckPassword(passwordHash, cmd.Password); err != nil {
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	mfaEnabled, err := l.secondFactor.IsEnabled(ctx, user.Id())
+	if err != nil {
+		return nil, custom_errors.ErrInternalServerError
+	}
+	if mfaEnabled {
+		mfaToken, expiresAt, err := l.secondFactor.Challenge(user.Id(), user.Email())
+		if err != nil {
+			return nil, custom_errors.ErrInternalServerError
+		}
+		return &auth.Token{MFAToken: mfaToken, ExpiresAt: expiresAt}, nil
+	}
+
+	return l.sessions.Start(ctx, auth.AuthenticatedUser{
+		Id:              user.Id(),
+		Email:           user.Email(),
+		Roles:           user.Roles(),
+		EmailUnverified: !user.IsEmailVerified(),
+	}, cmd.Device)
+}
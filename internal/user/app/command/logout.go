@@ -0,0 +1,39 @@
+package command
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+// Logout ends the session of the access token the user is authenticated with
+type Logout struct{}
+
+type LogoutHandler = shared.CommandHandler[Logout]
+
+// SessionRevoker ends login sessions and revokes their tokens
+type SessionRevoker interface {
+	Logout(ctx context.Context, sessionId string) error
+	LogoutAll(ctx context.Context, userId string) error
+}
+
+type logoutHandler struct {
+	sessions SessionRevoker
+}
+
+func NewLogoutHandler(sessions SessionRevoker) LogoutHandler {
+	if sessions == nil {
+		panic("nil session revoker")
+	}
+	return &logoutHandler{sessions: sessions}
+}
+
+func (l *logoutHandler) Handle(ctx context.Context, cmd Logout) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if authUser == nil || authUser.Id == "" || authUser.SessionId == "" {
+		return rbac.ErrUnauthorized
+	}
+	return l.sessions.Logout(ctx, authUser.SessionId)
+}
@@ -0,0 +1,33 @@
+package command
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+// LogoutAllSessions ends every session of the authenticated user, on every device
+type LogoutAllSessions struct{}
+
+type LogoutAllSessionsHandler = shared.CommandHandler[LogoutAllSessions]
+
+type logoutAllSessionsHandler struct {
+	sessions SessionRevoker
+}
+
+func NewLogoutAllSessionsHandler(sessions SessionRevoker) LogoutAllSessionsHandler {
+	if sessions == nil {
+		panic("nil session revoker")
+	}
+	return &logoutAllSessionsHandler{sessions: sessions}
+}
+
+func (l *logoutAllSessionsHandler) Handle(ctx context.Context, cmd LogoutAllSessions) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if authUser == nil || authUser.Id == "" {
+		return rbac.ErrUnauthorized
+	}
+	return l.sessions.LogoutAll(ctx, authUser.Id)
+}
@@ -0,0 +1,101 @@
+package command
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+// MFAEnroller sets up and disables the two-factor authentication of users
+type MFAEnroller interface {
+	Setup(ctx context.Context, userId, secret string) error
+	Enable(ctx context.Context, userId, code string, recoveryCodes []string) error
+	Disable(ctx context.Context, userId, code string) error
+}
+
+// SetupMFA starts setting up two-factor authentication for the authenticated user. Secret is made
+// by totp.GenerateSecret and shown to the user to add to their authenticator app.
+type SetupMFA struct {
+	Secret string
+}
+
+type SetupMFAHandler = shared.CommandHandler[SetupMFA]
+
+type setupMFAHandler struct {
+	mfa   MFAEnroller
+	guard guards.Guards
+}
+
+func NewSetupMFAHandler(mfa MFAEnroller, guard guards.Guards) SetupMFAHandler {
+	if mfa == nil || guard == nil {
+		panic("nil mfa enroller or guard")
+	}
+	return &setupMFAHandler{mfa: mfa, guard: guard}
+}
+
+func (s *setupMFAHandler) Handle(ctx context.Context, cmd SetupMFA) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := s.guard.Authorize(ctx, authUser.Subject(), rbac.ManageMFA); err != nil {
+		return err
+	}
+	return s.mfa.Setup(ctx, authUser.Id, cmd.Secret)
+}
+
+// EnableMFA turns on two-factor authentication once the user enters a code from their app.
+// RecoveryCodes are made by mfa.NewRecoveryCodes and shown to the user once.
+type EnableMFA struct {
+	Code          string
+	RecoveryCodes []string
+}
+
+type EnableMFAHandler = shared.CommandHandler[EnableMFA]
+
+type enableMFAHandler struct {
+	mfa   MFAEnroller
+	guard guards.Guards
+}
+
+func NewEnableMFAHandler(mfa MFAEnroller, guard guards.Guards) EnableMFAHandler {
+	if mfa == nil || guard == nil {
+		panic("nil mfa enroller or guard")
+	}
+	return &enableMFAHandler{mfa: mfa, guard: guard}
+}
+
+func (e *enableMFAHandler) Handle(ctx context.Context, cmd EnableMFA) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := e.guard.Authorize(ctx, authUser.Subject(), rbac.ManageMFA); err != nil {
+		return err
+	}
+	return e.mfa.Enable(ctx, authUser.Id, cmd.Code, cmd.RecoveryCodes)
+}
+
+// DisableMFA turns off two-factor authentication. It takes a code from the app or a recovery code.
+type DisableMFA struct {
+	Code string
+}
+
+type DisableMFAHandler = shared.CommandHandler[DisableMFA]
+
+type disableMFAHandler struct {
+	mfa   MFAEnroller
+	guard guards.Guards
+}
+
+func NewDisableMFAHandler(mfa MFAEnroller, guard guards.Guards) DisableMFAHandler {
+	if mfa == nil || guard == nil {
+		panic("nil mfa enroller or guard")
+	}
+	return &disableMFAHandler{mfa: mfa, guard: guard}
+}
+
+func (d *disableMFAHandler) Handle(ctx context.Context, cmd DisableMFA) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := d.guard.Authorize(ctx, authUser.Subject(), rbac.ManageMFA); err != nil {
+		return err
+	}
+	return d.mfa.Disable(ctx, authUser.Id, cmd.Code)
+}
@@ -0,0 +1,58 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/sessions"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+type RefreshTokenHandler = shared.ResultCommandHandler[RefreshToken, *auth.Token]
+
+type RefreshToken struct {
+	RefreshToken string
+}
+
+// SessionRefresher rotates the refresh token of a session
+type SessionRefresher interface {
+	Refresh(ctx context.Context, refreshToken string, loadUser sessions.UserLoader) (*auth.Token, error)
+}
+
+type refreshTokenHandler struct {
+	userRepo domain.UserRepository
+	sessions SessionRefresher
+}
+
+// NewRefreshTokenHandler trades a refresh token for new tokens. The refresh token is the
+// credential, so there is no guard.
+func NewRefreshTokenHandler(userRepo domain.UserRepository, sessions SessionRefresher) RefreshTokenHandler {
+	if userRepo == nil || sessions == nil {
+		panic("nil user repository or session refresher")
+	}
+	return &refreshTokenHandler{userRepo: userRepo, sessions: sessions}
+}
+
+func (r *refreshTokenHandler) Handle(ctx context.Context, cmd RefreshToken) (*auth.Token, error) {
+	return r.sessions.Refresh(ctx, cmd.RefreshToken, r.loadUser)
+}
+
+// loadUser reads the user again, so that the new access token carries the current roles and
+// whether the email has been verified since
+func (r *refreshTokenHandler) loadUser(ctx context.Context, userId string) (*auth.AuthenticatedUser, error) {
+	user, err := r.userRepo.GetUserBy(ctx, "id", userId)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, sessions.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	return &auth.AuthenticatedUser{
+		Id:              user.Id(),
+		Email:           user.Email(),
+		Roles:           user.Roles(),
+		EmailUnverified: !user.IsEmailVerified(),
+	}, nil
+}
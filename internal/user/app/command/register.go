@@ -17,6 +17,7 @@ import (
 type RegisterUser struct {
 	Email    string
 	Username string
+	Password string
 }
 
 type RegisterUserHandler = shared.CommandHandler[RegisterUser]
@@ -35,7 +36,11 @@ func NewRegisterUserHandler(userRepo domain.UserRepository, guard guards.Guards)
 
 func (r *registerUserHandler) Handle(ctx context.Context, cmd RegisterUser) error {
 	authUser := auth.GetUserFromCtx(ctx)
-	if err := r.guard.Authorize(authUser.Role, rbac.CreateAccount); err != nil {
+	if err := r.guard.Authorize(ctx, authUser.Subject(), rbac.CreateAccount); err != nil {
+		return err
+	}
+
+	if err := domain.ValidatePassword(cmd.Password); err != nil {
 		return err
 	}
 
@@ -50,9 +55,13 @@ func (r *registerUserHandler) Handle(ctx context.Context, cmd RegisterUser) erro
 		return domain.ErrEmailOrUsernameAlreadyExists
 	}
 
-	user, err := domain.NewUser(
-		cuid.New(), cmd.Email, cmd.Username,
-		rbac.Regular, time.Now(), time.Now(), nil, nil)
+	passwordHash, err := auth.HashPassword(cmd.Password)
+	if err != nil {
+		return custom_errors.ErrInternalServerError
+	}
+
+	// The repository saves the UserRegistered event together with the user
+	user, err := domain.RegisterUser(cuid.New(), cmd.Email, cmd.Username, passwordHash, time.Now())
 	if err != nil {
 		return err
 	}
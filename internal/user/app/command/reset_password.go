@@ -0,0 +1,100 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/onetime"
+	"github.com/iammrsea/social-app/internal/shared/custom_errors"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+// PasswordResetter emails users single-use links that reset their password, and redeems the tokens
+// in them
+type PasswordResetter interface {
+	SendPasswordResetEmail(ctx context.Context, userId, email string) error
+	RedeemPasswordResetToken(ctx context.Context, token string) (onetime.Claims, error)
+}
+
+// RequestPasswordReset emails a password reset link to the user with the email, if there is one
+type RequestPasswordReset struct {
+	Email string
+}
+
+type RequestPasswordResetHandler = shared.CommandHandler[RequestPasswordReset]
+
+type requestPasswordResetHandler struct {
+	userRepo domain.UserRepository
+	resetter PasswordResetter
+}
+
+// NewRequestPasswordResetHandler has no guard; users who forgot their password can't log in
+func NewRequestPasswordResetHandler(userRepo domain.UserRepository, resetter PasswordResetter) RequestPasswordResetHandler {
+	if userRepo == nil || resetter == nil {
+		panic("nil user repository or password resetter")
+	}
+	return &requestPasswordResetHandler{userRepo: userRepo, resetter: resetter}
+}
+
+// Handle succeeds for unknown emails too, so the request can't be used to find out who has an account
+func (r *requestPasswordResetHandler) Handle(ctx context.Context, cmd RequestPasswordReset) error {
+	user, err := r.userRepo.GetUserBy(ctx, "email", strings.TrimSpace(cmd.Email))
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+		return custom_errors.ErrInternalServerError
+	}
+	return r.resetter.SendPasswordResetEmail(ctx, user.Id(), user.Email())
+}
+
+// ResetPassword sets a new password for the user the token in a password reset link was sent to
+type ResetPassword struct {
+	Token       string
+	NewPassword string
+}
+
+type ResetPasswordHandler = shared.CommandHandler[ResetPassword]
+
+type resetPasswordHandler struct {
+	userRepo domain.UserRepository
+	resetter PasswordResetter
+}
+
+// NewResetPasswordHandler has no guard; the token proves the caller received the email. The
+// sessions of the user end once the password is reset.
+func NewResetPasswordHandler(userRepo domain.UserRepository, resetter PasswordResetter) ResetPasswordHandler {
+	if userRepo == nil || resetter == nil {
+		panic("nil user repository or password resetter")
+	}
+	return &resetPasswordHandler{userRepo: userRepo, resetter: resetter}
+}
+
+func (r *resetPasswordHandler) Handle(ctx context.Context, cmd ResetPassword) error {
+	// An invalid password doesn't use up the token
+	if err := domain.ValidatePassword(cmd.NewPassword); err != nil {
+		return err
+	}
+	claims, err := r.resetter.RedeemPasswordResetToken(ctx, cmd.Token)
+	if err != nil {
+		return err
+	}
+	passwordHash, err := auth.HashPassword(cmd.NewPassword)
+	if err != nil {
+		return custom_errors.ErrInternalServerError
+	}
+	err = r.userRepo.ResetPassword(ctx, claims.Subject, func(user *domain.User) error {
+		if user.Email() != claims.Email {
+			return onetime.ErrInvalidToken
+		}
+		return user.ResetPassword(passwordHash, time.Now())
+	})
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return onetime.ErrInvalidToken
+	}
+	return err
+}
@@ -0,0 +1,24 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+// maxUpdateAttempts bounds how many times an update is tried when other writes to the same user keep getting in first
+const maxUpdateAttempts = 3
+
+// retryOnConflict runs update again while the repository reports that the user was modified
+// concurrently. Repositories read the user afresh on every call, so each attempt sees the latest state.
+func retryOnConflict(ctx context.Context, update func() error) error {
+	var err error
+	for range maxUpdateAttempts {
+		err = update()
+		if !errors.Is(err, domain.ErrConcurrentModification) || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
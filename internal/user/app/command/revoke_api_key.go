@@ -0,0 +1,42 @@
+package command
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+// RevokeAPIKey revokes one of the authenticated user's API keys
+type RevokeAPIKey struct {
+	Id string
+}
+
+type RevokeAPIKeyHandler = shared.CommandHandler[RevokeAPIKey]
+
+// APIKeyRevoker revokes personal API keys
+type APIKeyRevoker interface {
+	Revoke(ctx context.Context, userId, keyId string) error
+}
+
+type revokeAPIKeyHandler struct {
+	apiKeys APIKeyRevoker
+	guard   guards.Guards
+}
+
+func NewRevokeAPIKeyHandler(apiKeys APIKeyRevoker, guard guards.Guards) RevokeAPIKeyHandler {
+	if apiKeys == nil || guard == nil {
+		panic("nil api key revoker or guard")
+	}
+	return &revokeAPIKeyHandler{apiKeys: apiKeys, guard: guard}
+}
+
+func (r *revokeAPIKeyHandler) Handle(ctx context.Context, cmd RevokeAPIKey) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := r.guard.Authorize(ctx, authUser.Subject(), rbac.ManageAPIKeys); err != nil {
+		return err
+	}
+	return r.apiKeys.Revoke(ctx, authUser.Id, cmd.Id)
+}
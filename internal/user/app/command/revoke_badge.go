@@ -31,10 +31,12 @@ func NewRevokeAwardedBadgeHandler(userRepo domain.UserRepository, guard guards.G
 
 func (r *revokeAwardedBagdeHandler) Handle(ctx context.Context, cmd RevokeAwardedBadge) error {
 	authUser := auth.GetUserFromCtx(ctx)
-	if err := r.guard.Authorize(authUser.Role, rbac.RevokeBadge); err != nil {
+	if err := r.guard.Authorize(ctx, authUser.Subject(), rbac.RevokeBadge); err != nil {
 		return err
 	}
-	return r.userRepo.RevokeAwardedBadge(ctx, cmd.Id, func(user *domain.User) error {
-		return user.RevokeAwardedBadge(cmd.Badge)
+	return retryOnConflict(ctx, func() error {
+		return r.userRepo.RevokeAwardedBadge(ctx, cmd.Id, func(user *domain.User) error {
+			return user.RevokeAwardedBadge(cmd.Badge)
+		})
 	})
 }
@@ -0,0 +1,156 @@
+package command
+
+import (
+	"context"
+	"slices"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+// RoleDefiner creates, changes and deletes the roles of the access policy
+type RoleDefiner interface {
+	DefineRole(ctx context.Context, role rbac.UserRole, rc rbac.RoleConfig) error
+	DeleteRole(ctx context.Context, role rbac.UserRole) error
+}
+
+// AssignRole gives a user a role of the access policy on top of the roles they have
+type AssignRole struct {
+	Id   string
+	Role rbac.UserRole
+}
+
+type AssignRoleHandler = shared.CommandHandler[AssignRole]
+
+type assignRoleHandler struct {
+	userRepo domain.UserRepository
+	guard    guards.Guards
+}
+
+func NewAssignRoleHandler(userRepo domain.UserRepository, guard guards.Guards) AssignRoleHandler {
+	if userRepo == nil || guard == nil {
+		panic("nil user repository or guard")
+	}
+	return &assignRoleHandler{userRepo: userRepo, guard: guard}
+}
+
+func (a *assignRoleHandler) Handle(ctx context.Context, cmd AssignRole) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := a.guard.Authorize(ctx, authUser.Subject(), rbac.AssignRole); err != nil {
+		return err
+	}
+	role := rbac.NewUserRole(cmd.Role.String())
+	defined := slices.ContainsFunc(a.guard.Roles(), func(def rbac.RoleDefinition) bool {
+		return def.Role == role
+	})
+	if !defined {
+		return rbac.ErrUnknownRole
+	}
+	return retryOnConflict(ctx, func() error {
+		return a.userRepo.UpdateRoles(ctx, cmd.Id, func(user *domain.User) error {
+			return user.AssignRole(role)
+		})
+	})
+}
+
+// RemoveRole takes a role away from a user, who keeps at least one role
+type RemoveRole struct {
+	Id   string
+	Role rbac.UserRole
+}
+
+type RemoveRoleHandler = shared.CommandHandler[RemoveRole]
+
+type removeRoleHandler struct {
+	userRepo domain.UserRepository
+	guard    guards.Guards
+}
+
+func NewRemoveRoleHandler(userRepo domain.UserRepository, guard guards.Guards) RemoveRoleHandler {
+	if userRepo == nil || guard == nil {
+		panic("nil user repository or guard")
+	}
+	return &removeRoleHandler{userRepo: userRepo, guard: guard}
+}
+
+func (r *removeRoleHandler) Handle(ctx context.Context, cmd RemoveRole) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := r.guard.Authorize(ctx, authUser.Subject(), rbac.AssignRole); err != nil {
+		return err
+	}
+	role := rbac.NewUserRole(cmd.Role.String())
+	return retryOnConflict(ctx, func() error {
+		return r.userRepo.UpdateRoles(ctx, cmd.Id, func(user *domain.User) error {
+			return user.RemoveRole(role)
+		})
+	})
+}
+
+// DefineRole creates a role of the access policy or replaces its definition
+type DefineRole struct {
+	Role        rbac.UserRole
+	Inherits    []rbac.UserRole
+	Permissions []rbac.Permission
+	// All grants every permission
+	All bool
+}
+
+type DefineRoleHandler = shared.CommandHandler[DefineRole]
+
+type defineRoleHandler struct {
+	roles RoleDefiner
+	guard guards.Guards
+}
+
+func NewDefineRoleHandler(roles RoleDefiner, guard guards.Guards) DefineRoleHandler {
+	if roles == nil || guard == nil {
+		panic("nil role definer or guard")
+	}
+	return &defineRoleHandler{roles: roles, guard: guard}
+}
+
+func (d *defineRoleHandler) Handle(ctx context.Context, cmd DefineRole) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := d.guard.Authorize(ctx, authUser.Subject(), rbac.ManageRoles); err != nil {
+		return err
+	}
+	inherits := make([]rbac.UserRole, len(cmd.Inherits))
+	for i, parent := range cmd.Inherits {
+		inherits[i] = rbac.NewUserRole(parent.String())
+	}
+	return d.roles.DefineRole(ctx, rbac.NewUserRole(cmd.Role.String()), rbac.RoleConfig{
+		Inherits:    inherits,
+		Permissions: cmd.Permissions,
+		All:         cmd.All,
+	})
+}
+
+// DeleteRole removes a role from the access policy. Built-in roles can't be deleted.
+type DeleteRole struct {
+	Role rbac.UserRole
+}
+
+type DeleteRoleHandler = shared.CommandHandler[DeleteRole]
+
+type deleteRoleHandler struct {
+	roles RoleDefiner
+	guard guards.Guards
+}
+
+func NewDeleteRoleHandler(roles RoleDefiner, guard guards.Guards) DeleteRoleHandler {
+	if roles == nil || guard == nil {
+		panic("nil role definer or guard")
+	}
+	return &deleteRoleHandler{roles: roles, guard: guard}
+}
+
+func (d *deleteRoleHandler) Handle(ctx context.Context, cmd DeleteRole) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := d.guard.Authorize(ctx, authUser.Subject(), rbac.ManageRoles); err != nil {
+		return err
+	}
+	return d.roles.DeleteRole(ctx, rbac.NewUserRole(cmd.Role.String()))
+}
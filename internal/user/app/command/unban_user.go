@@ -30,10 +30,12 @@ func NewUnbanUserHandler(userRepo domain.UserRepository, guard guards.Guards) Un
 
 func (a *unbanUserHandler) Handle(ctx context.Context, cmd UnbanUser) error {
 	authUser := auth.GetUserFromCtx(ctx)
-	if err := a.guard.Authorize(authUser.Role, rbac.UnbanUser); err != nil {
+	if err := a.guard.Authorize(ctx, authUser.Subject(), rbac.UnbanUser); err != nil {
 		return err
 	}
-	return a.userRepo.UnbanUser(ctx, cmd.Id, func(user *domain.User) error {
-		return user.UnBan()
+	return retryOnConflict(ctx, func() error {
+		return a.userRepo.UnbanUser(ctx, cmd.Id, func(user *domain.User) error {
+			return user.UnBan()
+		})
 	})
 }
@@ -0,0 +1,95 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/onetime"
+	"github.com/iammrsea/social-app/internal/shared/custom_errors"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+// VerifyEmail verifies the email of the user the token in a verification link was sent to
+type VerifyEmail struct {
+	Token string
+}
+
+type VerifyEmailHandler = shared.CommandHandler[VerifyEmail]
+
+// EmailVerifier emails users single-use links that verify their email, and redeems the tokens in them
+type EmailVerifier interface {
+	SendVerificationEmail(ctx context.Context, userId, email string) error
+	RedeemVerificationToken(ctx context.Context, token string) (onetime.Claims, error)
+}
+
+type verifyEmailHandler struct {
+	userRepo domain.UserRepository
+	verifier EmailVerifier
+}
+
+// NewVerifyEmailHandler has no guard; the token proves the caller received the email
+func NewVerifyEmailHandler(userRepo domain.UserRepository, verifier EmailVerifier) VerifyEmailHandler {
+	if userRepo == nil || verifier == nil {
+		panic("nil user repository or email verifier")
+	}
+	return &verifyEmailHandler{userRepo: userRepo, verifier: verifier}
+}
+
+func (v *verifyEmailHandler) Handle(ctx context.Context, cmd VerifyEmail) error {
+	claims, err := v.verifier.RedeemVerificationToken(ctx, cmd.Token)
+	if err != nil {
+		return err
+	}
+	err = v.userRepo.VerifyEmail(ctx, claims.Subject, func(user *domain.User) error {
+		// The link only verifies the address it was sent to
+		if user.Email() != claims.Email {
+			return onetime.ErrInvalidToken
+		}
+		return user.VerifyEmail(time.Now())
+	})
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return onetime.ErrInvalidToken
+	}
+	return err
+}
+
+// RequestEmailVerification sends the authenticated user a new email verification link
+type RequestEmailVerification struct{}
+
+type RequestEmailVerificationHandler = shared.CommandHandler[RequestEmailVerification]
+
+type requestEmailVerificationHandler struct {
+	userRepo domain.UserRepository
+	verifier EmailVerifier
+}
+
+// NewRequestEmailVerificationHandler has no guard beyond being logged in; unverified users are
+// the ones who need it
+func NewRequestEmailVerificationHandler(userRepo domain.UserRepository, verifier EmailVerifier) RequestEmailVerificationHandler {
+	if userRepo == nil || verifier == nil {
+		panic("nil user repository or email verifier")
+	}
+	return &requestEmailVerificationHandler{userRepo: userRepo, verifier: verifier}
+}
+
+func (r *requestEmailVerificationHandler) Handle(ctx context.Context, cmd RequestEmailVerification) error {
+	authUser := auth.GetUserFromCtx(ctx)
+	if authUser == nil || authUser.Id == "" {
+		return rbac.ErrUnauthorized
+	}
+	user, err := r.userRepo.GetUserBy(ctx, "id", authUser.Id)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return err
+		}
+		return custom_errors.ErrInternalServerError
+	}
+	if user.IsEmailVerified() {
+		return domain.ErrEmailAlreadyVerified
+	}
+	return r.verifier.SendVerificationEmail(ctx, user.Id(), user.Email())
+}
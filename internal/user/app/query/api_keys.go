@@ -0,0 +1,41 @@
+package query
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/apikeys"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+// GetAPIKeys lists the API keys of the authenticated user, revoked and expired ones included
+type GetAPIKeys struct{}
+
+type GetAPIKeysHandler = shared.QueryHandler[GetAPIKeys, []apikeys.APIKey]
+
+// APIKeyLister lists the API keys of a user
+type APIKeyLister interface {
+	List(ctx context.Context, userId string) ([]apikeys.APIKey, error)
+}
+
+type getAPIKeysHandler struct {
+	apiKeys APIKeyLister
+	guard   guards.Guards
+}
+
+func NewGetAPIKeysHandler(apiKeys APIKeyLister, guard guards.Guards) GetAPIKeysHandler {
+	if apiKeys == nil || guard == nil {
+		panic("nil api key lister or guard")
+	}
+	return &getAPIKeysHandler{apiKeys: apiKeys, guard: guard}
+}
+
+func (g *getAPIKeysHandler) Handle(ctx context.Context, cmd GetAPIKeys) ([]apikeys.APIKey, error) {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ManageAPIKeys); err != nil {
+		return nil, err
+	}
+	return g.apiKeys.List(ctx, authUser.Id)
+}
@@ -0,0 +1,53 @@
+package query
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/audit"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/shared/pagination"
+)
+
+// AuditLogSearcher searches the log of authorization decisions and state-changing commands
+type AuditLogSearcher interface {
+	Search(ctx context.Context, opts audit.SearchOptions) ([]audit.Entry, bool, error)
+}
+
+// GetAuditLog searches the audit log, newest entries first
+type GetAuditLog = audit.SearchOptions
+
+type AuditLogResult = pagination.PaginatedQueryResult[[]audit.Entry]
+
+type GetAuditLogHandler = shared.QueryHandler[GetAuditLog, *AuditLogResult]
+
+type getAuditLogHandler struct {
+	auditLog AuditLogSearcher
+	guard    guards.Guards
+}
+
+func NewGetAuditLogHandler(auditLog AuditLogSearcher, guard guards.Guards) GetAuditLogHandler {
+	if auditLog == nil || guard == nil {
+		panic("nil audit log or guard")
+	}
+	return &getAuditLogHandler{auditLog: auditLog, guard: guard}
+}
+
+func (g *getAuditLogHandler) Handle(ctx context.Context, query GetAuditLog) (*AuditLogResult, error) {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ViewAuditLog); err != nil {
+		return nil, err
+	}
+	entries, hasNext, err := g.auditLog.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditLogResult{
+		Data: entries,
+		PaginationInfo: &pagination.PagenationInfo{
+			HasNext: hasNext,
+		},
+	}, nil
+}
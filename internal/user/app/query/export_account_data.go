@@ -0,0 +1,132 @@
+package query
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	contentDomain "github.com/iammrsea/social-app/internal/content/domain"
+	interactionDomain "github.com/iammrsea/social-app/internal/interaction/domain"
+	moderationDomain "github.com/iammrsea/social-app/internal/moderation/domain"
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/audit"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+const auditPageSize = 100
+
+// AccountActivity is what a user did in the rest of the app
+type AccountActivity struct {
+	// Posts holds the published posts and the drafts of the user
+	Posts   []*contentDomain.PostReadModel      `json:"posts"`
+	Votes   []*interactionDomain.VoteReadModel  `json:"votes"`
+	Reports []*moderationDomain.ReportReadModel `json:"reports"`
+}
+
+// AccountDataCollector gathers what the rest of the app keeps about a user
+type AccountDataCollector interface {
+	CollectAccountData(ctx context.Context, userId string) (*AccountActivity, error)
+}
+
+// BanRecord is a ban or an unban of the user, as recorded to the audit log
+type BanRecord struct {
+	Banned bool      `json:"banned"`
+	Reason string    `json:"reason,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// AccountDataExport is everything tied to a user, as handed to them when they ask for their data.
+// Badges are part of the profile.
+type AccountDataExport struct {
+	ExportedAt time.Time             `json:"exportedAt"`
+	Profile    *domain.UserReadModel `json:"profile"`
+	BanHistory []BanRecord           `json:"banHistory"`
+	AccountActivity
+}
+
+// ExportAccountData gathers the data of user Id. Users can export their own data; exporting
+// someone else's takes rbac.DeleteUser, the permission of those who handle account erasures.
+type ExportAccountData struct {
+	Id string
+}
+
+type ExportAccountDataHandler = shared.QueryHandler[ExportAccountData, *AccountDataExport]
+
+type exportAccountDataHandler struct {
+	userReadModelRepo domain.UserReadModelRepository
+	collector         AccountDataCollector
+	auditLog          AuditLogSearcher
+	guard             guards.Guards
+}
+
+func NewExportAccountDataHandler(userReadModelRepo domain.UserReadModelRepository, collector AccountDataCollector, auditLog AuditLogSearcher, guard guards.Guards) ExportAccountDataHandler {
+	if userReadModelRepo == nil || collector == nil || auditLog == nil || guard == nil {
+		panic("nil user read model repository, account data collector, audit log or guard")
+	}
+	return &exportAccountDataHandler{userReadModelRepo: userReadModelRepo, collector: collector, auditLog: auditLog, guard: guard}
+}
+
+func (e *exportAccountDataHandler) Handle(ctx context.Context, query ExportAccountData) (*AccountDataExport, error) {
+	authUser := auth.GetUserFromCtx(ctx)
+	if authUser == nil || authUser.Id == "" {
+		return nil, rbac.ErrUnauthorized
+	}
+	// Like deleting an account, an API key needs rbac.DeleteUser even for the data of its owner
+	if authUser.Id != query.Id || authUser.APIKeyId != "" {
+		if err := e.guard.Authorize(ctx, authUser.Subject(), rbac.DeleteUser); err != nil {
+			return nil, err
+		}
+	}
+	profile, err := e.userReadModelRepo.GetUserById(ctx, query.Id)
+	if err != nil {
+		return nil, err
+	}
+	banHistory, err := e.banHistory(ctx, query.Id)
+	if err != nil {
+		return nil, err
+	}
+	activity, err := e.collector.CollectAccountData(ctx, query.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountDataExport{
+		ExportedAt:      time.Now(),
+		Profile:         profile,
+		BanHistory:      banHistory,
+		AccountActivity: *activity,
+	}, nil
+}
+
+// banHistory reads the bans and unbans of the user from the audit log, oldest first
+func (e *exportAccountDataHandler) banHistory(ctx context.Context, userId string) ([]BanRecord, error) {
+	history := []BanRecord{}
+	for _, perm := range []rbac.Permission{rbac.BanUser, rbac.UnbanUser} {
+		opts := audit.SearchOptions{
+			First:      auditPageSize,
+			Target:     userId,
+			Permission: string(perm),
+			Kind:       audit.Command,
+			Outcome:    audit.Succeeded,
+		}
+		for {
+			entries, hasNext, err := e.auditLog.Search(ctx, opts)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range entries {
+				history = append(history, BanRecord{Banned: perm == rbac.BanUser, Reason: entry.Details, At: entry.At})
+			}
+			if !hasNext || len(entries) == 0 {
+				break
+			}
+			opts.After = entries[len(entries)-1].At.Format(time.RFC3339Nano)
+		}
+	}
+	slices.SortFunc(history, func(a, b BanRecord) int {
+		return a.At.Compare(b.At)
+	})
+	return history, nil
+}
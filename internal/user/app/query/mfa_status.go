@@ -0,0 +1,40 @@
+package query
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+// GetMFAStatus tells whether the authenticated user has two-factor authentication enabled
+type GetMFAStatus struct{}
+
+type GetMFAStatusHandler = shared.QueryHandler[GetMFAStatus, bool]
+
+// MFAStatusReader tells whether a user has two-factor authentication enabled
+type MFAStatusReader interface {
+	IsEnabled(ctx context.Context, userId string) (bool, error)
+}
+
+type getMFAStatusHandler struct {
+	secondFactor MFAStatusReader
+	guard        guards.Guards
+}
+
+func NewGetMFAStatusHandler(secondFactor MFAStatusReader, guard guards.Guards) GetMFAStatusHandler {
+	if secondFactor == nil || guard == nil {
+		panic("nil second factor or guard")
+	}
+	return &getMFAStatusHandler{secondFactor: secondFactor, guard: guard}
+}
+
+func (g *getMFAStatusHandler) Handle(ctx context.Context, cmd GetMFAStatus) (bool, error) {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ManageMFA); err != nil {
+		return false, err
+	}
+	return g.secondFactor.IsEnabled(ctx, authUser.Id)
+}
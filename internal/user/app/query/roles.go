@@ -0,0 +1,65 @@
+package query
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/shared"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+// GetRoles lists the roles of the access policy in force with their effective permissions
+type GetRoles struct{}
+
+type GetRolesHandler = shared.QueryHandler[GetRoles, []rbac.RoleDefinition]
+
+type getRolesHandler struct {
+	guard guards.Guards
+}
+
+func NewGetRolesHandler(guard guards.Guards) GetRolesHandler {
+	if guard == nil {
+		panic("nil guard")
+	}
+	return &getRolesHandler{guard: guard}
+}
+
+func (g *getRolesHandler) Handle(ctx context.Context, query GetRoles) ([]rbac.RoleDefinition, error) {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ViewRoles); err != nil {
+		return nil, err
+	}
+	return g.guard.Roles(), nil
+}
+
+// GetRole returns a role of the access policy in force with its effective permissions
+type GetRole struct {
+	Role rbac.UserRole
+}
+
+type GetRoleHandler = shared.QueryHandler[GetRole, *rbac.RoleDefinition]
+
+type getRoleHandler struct {
+	guard guards.Guards
+}
+
+func NewGetRoleHandler(guard guards.Guards) GetRoleHandler {
+	if guard == nil {
+		panic("nil guard")
+	}
+	return &getRoleHandler{guard: guard}
+}
+
+func (g *getRoleHandler) Handle(ctx context.Context, query GetRole) (*rbac.RoleDefinition, error) {
+	authUser := auth.GetUserFromCtx(ctx)
+	if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ViewRoles); err != nil {
+		return nil, err
+	}
+	for _, role := range g.guard.Roles() {
+		if role.Role == query.Role {
+			return &role, nil
+		}
+	}
+	return nil, rbac.ErrUnknownRole
+}
@@ -34,7 +34,7 @@ func NewGetUserByEmailHandler(queryRepo domain.UserReadModelRepository, guard gu
 
 func (g *getUserByEmailHandler) Handle(ctx context.Context, cmd GetUserByEmail) (*domain.UserReadModel, error) {
 	authUser := auth.GetUserFromCtx(ctx)
-	if err := g.guard.Authorize(authUser.Role, rbac.ViewUser); err != nil {
+	if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ViewUser); err != nil {
 		return nil, err
 	}
 	return g.queryRepo.GetUserByEmail(ctx, cmd.Email)
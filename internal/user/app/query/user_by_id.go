@@ -30,7 +30,7 @@ func NewGetUserByIdHandler(queryRepo domain.UserReadModelRepository, guard guard
 
 func (g *getUserByIdHandler) Handle(ctx context.Context, cmd GetUserById) (*domain.UserReadModel, error) {
 	authUser := auth.GetUserFromCtx(ctx)
-	if err := g.guard.Authorize(authUser.Role, rbac.ViewUser); err != nil {
+	if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ViewUser); err != nil {
 		return nil, err
 	}
 	return g.queryRepo.GetUserById(ctx, cmd.Id)
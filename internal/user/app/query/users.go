@@ -31,9 +31,12 @@ func NewGetUsersHandler(queryRepo domain.UserReadModelRepository, guard guards.G
 
 func (g *getUsersHandler) Handle(ctx context.Context, cmd GetUsers) (*Result, error) {
 	authUser := auth.GetUserFromCtx(ctx)
-	if err := g.guard.Authorize(authUser.Role, rbac.ListUsers); err != nil {
+	if err := g.guard.Authorize(ctx, authUser.Subject(), rbac.ListUsers); err != nil {
 		return nil, err
 	}
+	if cmd.First <= 0 {
+		return nil, domain.ErrInvalidPageSize
+	}
 	users, hasNext, err := g.queryRepo.GetUsers(ctx, cmd)
 	if err != nil {
 		return nil, err
@@ -1,28 +1,99 @@
 package service
 
 import (
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/audit"
 	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/bans"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
 	"github.com/iammrsea/social-app/internal/user/app/command"
 	"github.com/iammrsea/social-app/internal/user/app/query"
 	"github.com/iammrsea/social-app/internal/user/domain"
 )
 
-// Constructor of the user application layer
-func New(userRepo domain.UserRepository, userReadModelRepo domain.UserReadModelRepository, guard guards.Guards) *Application {
+// Sessions starts, refreshes and ends the login sessions of users
+type Sessions interface {
+	command.SessionStarter
+	command.SessionRefresher
+	command.SessionRevoker
+}
+
+// APIKeys creates, lists and revokes the personal API keys of users
+type APIKeys interface {
+	command.APIKeyCreator
+	command.APIKeyRevoker
+	query.APIKeyLister
+}
+
+// AccountMail emails users the links that verify their email and reset their password, and
+// redeems the tokens in them
+type AccountMail interface {
+	command.EmailVerifier
+	command.PasswordResetter
+}
+
+// MFA sets up two-factor authentication and runs the second step of logging in
+type MFA interface {
+	command.MFAEnroller
+	command.SecondFactor
+}
+
+// AccountData anonymizes what the rest of the app keeps about deleted users and gathers it for
+// the exports of their data
+type AccountData interface {
+	command.AccountDataEraser
+	query.AccountDataCollector
+}
+
+// Constructor of the user application layer. The events raised by the user aggregate are saved
+// by the repository along with the user and published from the outbox. Banned users can't run
+// any of the commands, though they can still log out, revoke their API keys, verify their email,
+// reset their password, disable two-factor authentication and delete their account. Accounts are
+// deleted once deletionGracePeriod has passed. Bans, badges, role changes and account deletions
+// are recorded to the audit log.
+func New(userRepo domain.UserRepository, userReadModelRepo domain.UserReadModelRepository, banChecker bans.Checker, sessions Sessions, apiKeys APIKeys, accountMail AccountMail, mfa MFA, roles command.RoleDefiner, accountData AccountData, deletionGracePeriod time.Duration, auditLog *audit.Log, guard guards.Guards) *Application {
 	return &Application{
 		CommandHandler: CommandHandler{
-			RegisterUser:       command.NewRegisterUserHandler(userRepo, guard),
-			RevokeAwardedBadge: command.NewRevokeAwardedBadgeHandler(userRepo, guard),
-			AwardBadge:         command.NewAwardBadgeHandler(userRepo, guard),
-			MakeModerator:      command.NewMakeModeratorHandler(userRepo, guard),
-			ChangeUsername:     command.NewChangeUsernameHandler(userRepo, guard),
-			BanUser:            command.NewBanUserHandler(userRepo, guard),
-			UnbanUser:          command.NewUnbanUserHandler(userRepo, guard),
+			RegisterUser:             bans.Enforce(command.NewRegisterUserHandler(userRepo, guard), banChecker),
+			RevokeAwardedBadge:       bans.Enforce(audit.Audit(command.NewRevokeAwardedBadgeHandler(userRepo, guard), auditLog, rbac.RevokeBadge, describeRevokeBadge), banChecker),
+			AwardBadge:               bans.Enforce(audit.Audit(command.NewAwardBadgeHandler(userRepo, guard), auditLog, rbac.AwardBadge, describeAwardBadge), banChecker),
+			AssignRole:               bans.Enforce(audit.Audit(command.NewAssignRoleHandler(userRepo, guard), auditLog, rbac.AssignRole, describeAssignRole), banChecker),
+			RemoveRole:               bans.Enforce(audit.Audit(command.NewRemoveRoleHandler(userRepo, guard), auditLog, rbac.AssignRole, describeRemoveRole), banChecker),
+			DefineRole:               bans.Enforce(audit.Audit(command.NewDefineRoleHandler(roles, guard), auditLog, rbac.ManageRoles, describeDefineRole), banChecker),
+			DeleteRole:               bans.Enforce(audit.Audit(command.NewDeleteRoleHandler(roles, guard), auditLog, rbac.ManageRoles, describeDeleteRole), banChecker),
+			ChangeUsername:           bans.Enforce(command.NewChangeUsernameHandler(userRepo, guard), banChecker),
+			BanUser:                  bans.Enforce(audit.Audit(command.NewBanUserHandler(userRepo, guard), auditLog, rbac.BanUser, describeBanUser), banChecker),
+			UnbanUser:                bans.Enforce(audit.Audit(command.NewUnbanUserHandler(userRepo, guard), auditLog, rbac.UnbanUser, describeUnbanUser), banChecker),
+			LiftExpiredBans:          command.NewLiftExpiredBansHandler(userRepo),
+			Logout:                   command.NewLogoutHandler(sessions),
+			LogoutAllSessions:        command.NewLogoutAllSessionsHandler(sessions),
+			CreateAPIKey:             bans.Enforce(command.NewCreateAPIKeyHandler(apiKeys, guard), banChecker),
+			RevokeAPIKey:             command.NewRevokeAPIKeyHandler(apiKeys, guard),
+			VerifyEmail:              command.NewVerifyEmailHandler(userRepo, accountMail),
+			RequestEmailVerification: command.NewRequestEmailVerificationHandler(userRepo, accountMail),
+			RequestPasswordReset:     command.NewRequestPasswordResetHandler(userRepo, accountMail),
+			ResetPassword:            command.NewResetPasswordHandler(userRepo, accountMail),
+			SetupMFA:                 bans.Enforce(command.NewSetupMFAHandler(mfa, guard), banChecker),
+			EnableMFA:                bans.Enforce(command.NewEnableMFAHandler(mfa, guard), banChecker),
+			DisableMFA:               command.NewDisableMFAHandler(mfa, guard),
+			RequestAccountDeletion:   audit.Audit(command.NewRequestAccountDeletionHandler(userRepo, deletionGracePeriod, guard), auditLog, rbac.DeleteUser, describeRequestAccountDeletion),
+			CancelAccountDeletion:    audit.Audit(command.NewCancelAccountDeletionHandler(userRepo, guard), auditLog, rbac.DeleteUser, describeCancelAccountDeletion),
+			EraseDueAccounts:         command.NewEraseDueAccountsHandler(userRepo, accountData, sessions),
+			Login:                    command.NewLoginHandler(userRepo, sessions, mfa),
+			CompleteLogin:            command.NewCompleteLoginHandler(userRepo, sessions, mfa),
+			RefreshToken:             command.NewRefreshTokenHandler(userRepo, sessions),
 		},
 		QueryHandler: QueryHandler{
-			GetUserById:    query.NewGetUserByIdHandler(userReadModelRepo, guard),
-			GetUsers:       query.NewGetUsersHandler(userReadModelRepo, guard),
-			GetUserByEmail: query.NewGetUserByEmailHandler(userReadModelRepo, guard),
+			GetUserById:       query.NewGetUserByIdHandler(userReadModelRepo, guard),
+			GetUsers:          query.NewGetUsersHandler(userReadModelRepo, guard),
+			GetUserByEmail:    query.NewGetUserByEmailHandler(userReadModelRepo, guard),
+			GetAPIKeys:        query.NewGetAPIKeysHandler(apiKeys, guard),
+			GetMFAStatus:      query.NewGetMFAStatusHandler(mfa, guard),
+			GetRoles:          query.NewGetRolesHandler(guard),
+			GetRole:           query.NewGetRoleHandler(guard),
+			GetAuditLog:       query.NewGetAuditLogHandler(auditLog, guard),
+			ExportAccountData: query.NewExportAccountDataHandler(userReadModelRepo, accountData, auditLog, guard),
 		},
 	}
 }
@@ -2,23 +2,103 @@ package service_test
 
 import (
 	"context"
+	"net/url"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	contentDomain "github.com/iammrsea/social-app/internal/content/domain"
+	"github.com/iammrsea/social-app/internal/shared/audit"
 	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/apikeys"
+	"github.com/iammrsea/social-app/internal/shared/auth/mfa"
+	"github.com/iammrsea/social-app/internal/shared/auth/onetime"
+	"github.com/iammrsea/social-app/internal/shared/auth/sessions"
+	"github.com/iammrsea/social-app/internal/shared/auth/totp"
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/abac"
+	"github.com/iammrsea/social-app/internal/shared/guards/bans"
 	guard_mocks "github.com/iammrsea/social-app/internal/shared/guards/mocks"
 	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/shared/mail"
+	"github.com/iammrsea/social-app/internal/shared/outbox"
 	"github.com/iammrsea/social-app/internal/shared/pagination"
 	service "github.com/iammrsea/social-app/internal/user/app"
 	"github.com/iammrsea/social-app/internal/user/app/command"
 	"github.com/iammrsea/social-app/internal/user/app/query"
 	"github.com/iammrsea/social-app/internal/user/domain"
 	domain_mocks "github.com/iammrsea/social-app/internal/user/domain/mocks"
+	"github.com/iammrsea/social-app/internal/user/infra/accountmail"
+	"github.com/iammrsea/social-app/internal/user/infra/repos/memoryimpl"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
+// notBanned lets every user through the ban check
+var notBanned = bans.CheckerFunc(func(ctx context.Context, userId string) error { return nil })
+
+func newSessions() *sessions.Manager {
+	issuer := auth.NewTokenIssuer(auth.NewHMACKeySet([]byte("test-secret")), "social-app", "social-app", 15*time.Minute)
+	return sessions.NewManager(sessions.NewMemoryStore(), sessions.NewMemoryDenylist(), issuer)
+}
+
+func newAPIKeys() *apikeys.Manager {
+	return apikeys.NewManager(apikeys.NewMemoryStore())
+}
+
+func newAccountMail() *accountmail.Mailer {
+	return newAccountMailWith(mail.NewMemoryMailer())
+}
+
+func newMFA() *mfa.Manager {
+	tokens := onetime.NewTokens(auth.NewHMACKeySet([]byte("test-secret")), onetime.NewMemoryStore(), "social-app")
+	return mfa.NewManager(mfa.NewMemoryStore(), tokens)
+}
+
+func newRoles() *rbac.RoleManager {
+	return rbac.NewRoleManager(rbac.New(), rbac.NewMemoryPolicyStore())
+}
+
+func newAuditLog() *audit.Log {
+	return audit.NewLog(audit.NewMemoryStore())
+}
+
+const deletionGracePeriod = 30 * 24 * time.Hour
+
+// accountDataStub records the erased users and collects the same activity for everyone
+type accountDataStub struct {
+	mu     sync.Mutex
+	erased []string
+	err    error
+	posts  []*contentDomain.PostReadModel
+}
+
+func newAccountData() *accountDataStub {
+	return &accountDataStub{}
+}
+
+func (a *accountDataStub) EraseAccountData(ctx context.Context, userId string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil {
+		return a.err
+	}
+	a.erased = append(a.erased, userId)
+	return nil
+}
+
+func (a *accountDataStub) CollectAccountData(ctx context.Context, userId string) (*query.AccountActivity, error) {
+	return &query.AccountActivity{Posts: a.posts}, nil
+}
+
+func newAccountMailWith(mailer mail.Mailer) *accountmail.Mailer {
+	tokens := onetime.NewTokens(auth.NewHMACKeySet([]byte("test-secret")), onetime.NewMemoryStore(), "social-app")
+	return accountmail.New(tokens, mailer, "https://example.com", time.Hour, time.Hour)
+}
+
 type commandTestCase[T any] struct {
 	name        string
 	command     T
@@ -64,9 +144,13 @@ func TestCommandHandler(t *testing.T) {
 		t.Parallel()
 		testRevokeBadge(t)
 	})
-	t.Run("MakeModerator", func(t *testing.T) {
+	t.Run("AssignRole", func(t *testing.T) {
 		t.Parallel()
-		testMakeModerator(t)
+		testAssignRole(t)
+	})
+	t.Run("RemoveRole", func(t *testing.T) {
+		t.Parallel()
+		testRemoveRole(t)
 	})
 }
 func TestQueryHandler(t *testing.T) {
@@ -94,7 +178,7 @@ func testGetUsers(t *testing.T) {
 			},
 			authUser: &auth.AuthenticatedUser{
 				Id:    "userId-1",
-				Role:  rbac.Admin,
+				Roles: []rbac.UserRole{rbac.Admin},
 				Email: "admin@example.com",
 			},
 			expectedResult: queryResult[query.Result]{
@@ -104,13 +188,13 @@ func testGetUsers(t *testing.T) {
 							Id:       "userId-1",
 							Email:    "user1@example.com",
 							Username: "user1",
-							Role:     rbac.Regular,
+							Roles:    []rbac.UserRole{rbac.Regular},
 						},
 						{
 							Id:       "userId-2",
 							Email:    "user2@example.com",
 							Username: "user2",
-							Role:     rbac.Regular,
+							Roles:    []rbac.UserRole{rbac.Regular},
 						},
 					},
 					PaginationInfo: &pagination.PagenationInfo{
@@ -120,7 +204,7 @@ func testGetUsers(t *testing.T) {
 				err: nil,
 			},
 			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserReadModelRepository, guards *guard_mocks.MockGuards, query query.GetUsers, authUser *auth.AuthenticatedUser) {
-				guards.EXPECT().Authorize(authUser.Role, rbac.ListUsers).Return(nil)
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ListUsers).Return(nil)
 				repo.EXPECT().GetUsers(mock.Anything, query).RunAndReturn(
 					func(ctx context.Context, opts domain.GetUsersOptions) ([]*domain.UserReadModel, bool, error) {
 						result := []*domain.UserReadModel{
@@ -128,13 +212,13 @@ func testGetUsers(t *testing.T) {
 								Id:       "userId-1",
 								Email:    "user1@example.com",
 								Username: "user1",
-								Role:     rbac.Regular,
+								Roles:    []rbac.UserRole{rbac.Regular},
 							},
 							{
 								Id:       "userId-2",
 								Email:    "user2@example.com",
 								Username: "user2",
-								Role:     rbac.Regular,
+								Roles:    []rbac.UserRole{rbac.Regular},
 							},
 						}
 						return result, false, nil
@@ -149,7 +233,7 @@ func testGetUsers(t *testing.T) {
 			},
 			authUser: &auth.AuthenticatedUser{
 				Id:    "",
-				Role:  rbac.Guest,
+				Roles: []rbac.UserRole{rbac.Guest},
 				Email: "",
 			},
 			expectedResult: queryResult[query.Result]{
@@ -157,7 +241,25 @@ func testGetUsers(t *testing.T) {
 				err:  rbac.ErrUnauthorized,
 			},
 			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserReadModelRepository, guards *guard_mocks.MockGuards, query query.GetUsers, authUser *auth.AuthenticatedUser) {
-				guards.EXPECT().Authorize(authUser.Role, rbac.ListUsers).Return(rbac.ErrUnauthorized)
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ListUsers).Return(rbac.ErrUnauthorized)
+			},
+		},
+		{
+			name: "cannot list fewer than one user",
+			query: query.GetUsers{
+				First: -1,
+			},
+			authUser: &auth.AuthenticatedUser{
+				Id:    "userId-1",
+				Roles: []rbac.UserRole{rbac.Admin},
+				Email: "admin@example.com",
+			},
+			expectedResult: queryResult[query.Result]{
+				data: nil,
+				err:  domain.ErrInvalidPageSize,
+			},
+			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserReadModelRepository, guards *guard_mocks.MockGuards, query query.GetUsers, authUser *auth.AuthenticatedUser) {
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ListUsers).Return(nil)
 			},
 		},
 	}
@@ -179,7 +281,7 @@ func testGetUserById(t *testing.T) {
 			name: "authorized user get user by id",
 			authUser: &auth.AuthenticatedUser{
 				Id:    "userId-1",
-				Role:  rbac.Regular,
+				Roles: []rbac.UserRole{rbac.Regular},
 				Email: "user@example.com",
 			},
 			query: query.GetUserById{
@@ -190,19 +292,19 @@ func testGetUserById(t *testing.T) {
 					Id:       "userId-1234",
 					Email:    "user2@example.com",
 					Username: "username",
-					Role:     rbac.Regular,
+					Roles:    []rbac.UserRole{rbac.Regular},
 				},
 				err: nil,
 			},
 			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserReadModelRepository, guards *guard_mocks.MockGuards, query query.GetUserById, authUser *auth.AuthenticatedUser) {
-				guards.EXPECT().Authorize(authUser.Role, rbac.ViewUser).Return(nil)
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ViewUser).Return(nil)
 				repo.EXPECT().GetUserById(mock.Anything, query.Id).RunAndReturn(
 					func(ctx context.Context, id string) (*domain.UserReadModel, error) {
 						return &domain.UserReadModel{
 							Id:       "userId-1234",
 							Email:    "user2@example.com",
 							Username: "username",
-							Role:     rbac.Regular,
+							Roles:    []rbac.UserRole{rbac.Regular},
 						}, nil
 					})
 			},
@@ -211,7 +313,7 @@ func testGetUserById(t *testing.T) {
 			name: "unauthorized user cannot get user by id",
 			authUser: &auth.AuthenticatedUser{
 				Id:    "",
-				Role:  rbac.Guest,
+				Roles: []rbac.UserRole{rbac.Guest},
 				Email: "",
 			},
 			query: query.GetUserById{
@@ -222,14 +324,14 @@ func testGetUserById(t *testing.T) {
 				err:  rbac.ErrUnauthorized,
 			},
 			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserReadModelRepository, guards *guard_mocks.MockGuards, query query.GetUserById, authUser *auth.AuthenticatedUser) {
-				guards.EXPECT().Authorize(authUser.Role, rbac.ViewUser).Return(rbac.ErrUnauthorized)
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ViewUser).Return(rbac.ErrUnauthorized)
 			},
 		},
 		{
 			name: "user not found",
 			authUser: &auth.AuthenticatedUser{
 				Id:    "userId-1234",
-				Role:  rbac.Regular,
+				Roles: []rbac.UserRole{rbac.Regular},
 				Email: "user@example.com",
 			},
 			query: query.GetUserById{
@@ -240,7 +342,7 @@ func testGetUserById(t *testing.T) {
 				err:  domain.ErrUserNotFound,
 			},
 			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserReadModelRepository, guards *guard_mocks.MockGuards, query query.GetUserById, authUser *auth.AuthenticatedUser) {
-				guards.EXPECT().Authorize(authUser.Role, rbac.ViewUser).Return(nil)
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ViewUser).Return(nil)
 				repo.EXPECT().GetUserById(mock.Anything, query.Id).RunAndReturn(
 					func(ctx context.Context, id string) (*domain.UserReadModel, error) {
 						return nil, domain.ErrUserNotFound
@@ -266,7 +368,7 @@ func testGetUserByEmail(t *testing.T) {
 			name: "authorized user get user by email",
 			authUser: &auth.AuthenticatedUser{
 				Id:    "userId-1234",
-				Role:  rbac.Regular,
+				Roles: []rbac.UserRole{rbac.Regular},
 				Email: "user@example.com",
 			},
 			query: query.GetUserByEmail{
@@ -277,19 +379,19 @@ func testGetUserByEmail(t *testing.T) {
 					Id:       "userId-1234",
 					Email:    "user2@example.com",
 					Username: "username",
-					Role:     rbac.Regular,
+					Roles:    []rbac.UserRole{rbac.Regular},
 				},
 				err: nil,
 			},
 			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserReadModelRepository, guards *guard_mocks.MockGuards, query query.GetUserByEmail, authUser *auth.AuthenticatedUser) {
-				guards.EXPECT().Authorize(authUser.Role, rbac.ViewUser).Return(nil)
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ViewUser).Return(nil)
 				repo.EXPECT().GetUserByEmail(mock.Anything, query.Email).RunAndReturn(
 					func(ctx context.Context, email string) (*domain.UserReadModel, error) {
 						return &domain.UserReadModel{
 							Id:       "userId-1234",
 							Email:    "user2@example.com",
 							Username: "username",
-							Role:     rbac.Regular,
+							Roles:    []rbac.UserRole{rbac.Regular},
 						}, nil
 					})
 			},
@@ -298,7 +400,7 @@ func testGetUserByEmail(t *testing.T) {
 			name: "unauthorized user cannot get user by email",
 			authUser: &auth.AuthenticatedUser{
 				Id:    "",
-				Role:  rbac.Guest,
+				Roles: []rbac.UserRole{rbac.Guest},
 				Email: "",
 			},
 			query: query.GetUserByEmail{
@@ -309,14 +411,14 @@ func testGetUserByEmail(t *testing.T) {
 				err:  rbac.ErrUnauthorized,
 			},
 			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserReadModelRepository, guards *guard_mocks.MockGuards, query query.GetUserByEmail, authUser *auth.AuthenticatedUser) {
-				guards.EXPECT().Authorize(authUser.Role, rbac.ViewUser).Return(rbac.ErrUnauthorized)
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ViewUser).Return(rbac.ErrUnauthorized)
 			},
 		},
 		{
 			name: "user not found",
 			authUser: &auth.AuthenticatedUser{
 				Id:    "user-1",
-				Role:  rbac.Regular,
+				Roles: []rbac.UserRole{rbac.Regular},
 				Email: "user1@example.com",
 			},
 			query: query.GetUserByEmail{
@@ -327,7 +429,7 @@ func testGetUserByEmail(t *testing.T) {
 				err:  domain.ErrUserNotFound,
 			},
 			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserReadModelRepository, guards *guard_mocks.MockGuards, query query.GetUserByEmail, authUser *auth.AuthenticatedUser) {
-				guards.EXPECT().Authorize(authUser.Role, rbac.ViewUser).Return(nil)
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.ViewUser).Return(nil)
 				repo.EXPECT().GetUserByEmail(mock.Anything, query.Email).Return(nil, domain.ErrUserNotFound)
 			},
 		},
@@ -350,7 +452,7 @@ func testUnbanUser(t *testing.T) {
 			name: "authorized user can unban user",
 			authUser: &auth.AuthenticatedUser{
 				Id:    "userId-1234",
-				Role:  rbac.Admin,
+				Roles: []rbac.UserRole{rbac.Admin},
 				Email: "admin@example.com",
 			},
 			command: command.UnbanUser{
@@ -358,10 +460,10 @@ func testUnbanUser(t *testing.T) {
 			},
 			expectedErr: nil,
 			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserRepository, guards *guard_mocks.MockGuards, command *command.UnbanUser, authUser *auth.AuthenticatedUser) {
-				guards.EXPECT().Authorize(authUser.Role, rbac.UnbanUser).Return(nil)
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.UnbanUser).Return(nil)
 				repo.EXPECT().UnbanUser(mock.Anything, command.Id, mock.AnythingOfType("func(*domain.User) error")).RunAndReturn(
 					func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
-						user, err := domain.NewUser("userId-1", "user@example.com", "username", rbac.Regular,
+						user, err := domain.NewUser("userId-1", "user@example.com", "username", []rbac.UserRole{rbac.Regular},
 							time.Now(), time.Now(), nil, domain.NewBan(true, "bullying", false, time.Now(), time.Now().Add(time.Hour*48), time.Now()))
 						require.NoError(t, err)
 						require.True(t, user.IsBanned())
@@ -376,7 +478,7 @@ func testUnbanUser(t *testing.T) {
 			name: "unauthorized user cannot unban user",
 			authUser: &auth.AuthenticatedUser{
 				Id:    "userId-1234",
-				Role:  rbac.Guest,
+				Roles: []rbac.UserRole{rbac.Guest},
 				Email: "guest@example.com",
 			},
 			command: command.UnbanUser{
@@ -384,7 +486,7 @@ func testUnbanUser(t *testing.T) {
 			},
 			expectedErr: rbac.ErrUnauthorized,
 			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserRepository, guards *guard_mocks.MockGuards, command *command.UnbanUser, authUser *auth.AuthenticatedUser) {
-				guards.EXPECT().Authorize(authUser.Role, rbac.UnbanUser).Return(rbac.ErrUnauthorized)
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.UnbanUser).Return(rbac.ErrUnauthorized)
 			},
 		},
 	}
@@ -399,45 +501,141 @@ func testUnbanUser(t *testing.T) {
 	}
 }
 
-func testMakeModerator(t *testing.T) {
-	testCases := []commandTestCase[command.MakeModerator]{
+func testAssignRole(t *testing.T) {
+	testCases := []commandTestCase[command.AssignRole]{
 		{
-			name: "authorized user can make user moderator",
+			name: "authorized user can assign a role",
 			authUser: &auth.AuthenticatedUser{
 				Id:    "userId-1234",
-				Role:  rbac.Admin,
+				Roles: []rbac.UserRole{rbac.Admin},
 				Email: "admin@example.com",
 			},
-			command: command.MakeModerator{
-				Id: "userId-3030303048933",
+			command: command.AssignRole{
+				Id:   "userId-3030303048933",
+				Role: "moderator",
 			},
 			expectedErr: nil,
-			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserRepository, guards *guard_mocks.MockGuards, command *command.MakeModerator, authUser *auth.AuthenticatedUser) {
-				guards.EXPECT().Authorize(authUser.Role, rbac.MakeModerator).Return(nil)
-				repo.EXPECT().MakeModerator(mock.Anything, command.Id, mock.AnythingOfType("func(*domain.User) error")).RunAndReturn(
+			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserRepository, guards *guard_mocks.MockGuards, command *command.AssignRole, authUser *auth.AuthenticatedUser) {
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.AssignRole).Return(nil)
+				guards.EXPECT().Roles().Return(rbac.NewPolicy().Roles())
+				repo.EXPECT().UpdateRoles(mock.Anything, command.Id, mock.AnythingOfType("func(*domain.User) error")).RunAndReturn(
 					func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
-						user, err := domain.NewUser("userId-239", "user@example.com", "username", rbac.Regular, time.Now(), time.Now(), nil, nil)
+						user, err := domain.NewUser("userId-239", "user@example.com", "username", []rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil, nil)
 						require.NoError(t, err, "Unable to create new user")
 						err = updateFn(&user)
-						require.NoError(t, err, "Unable to make user moderator")
-						require.Equal(t, user.Role(), rbac.Moderator, "User role was not updated to moderator as expected")
+						require.NoError(t, err, "Unable to assign role")
+						require.Equal(t, []rbac.UserRole{rbac.Regular, rbac.Moderator}, user.Roles(), "Role was not assigned as expected")
 						return nil
 					})
 			},
 		},
 		{
-			name: "unauthorized user cannot make user moderator",
+			name: "role missing from the policy cannot be assigned",
+			authUser: &auth.AuthenticatedUser{
+				Id:    "userId-1234",
+				Roles: []rbac.UserRole{rbac.Admin},
+				Email: "admin@example.com",
+			},
+			command: command.AssignRole{
+				Id:   "userId-3030303048933",
+				Role: "SUPPORT",
+			},
+			expectedErr: rbac.ErrUnknownRole,
+			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserRepository, guards *guard_mocks.MockGuards, command *command.AssignRole, authUser *auth.AuthenticatedUser) {
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.AssignRole).Return(nil)
+				guards.EXPECT().Roles().Return(rbac.NewPolicy().Roles())
+			},
+		},
+		{
+			name: "unauthorized user cannot assign a role",
 			authUser: &auth.AuthenticatedUser{
-				Role:  rbac.Regular,
+				Roles: []rbac.UserRole{rbac.Regular},
 				Id:    "userId-12350500",
 				Email: "user@example.com",
 			},
-			command: command.MakeModerator{
-				Id: "userId-3030303048933",
+			command: command.AssignRole{
+				Id:   "userId-3030303048933",
+				Role: rbac.Moderator,
+			},
+			expectedErr: rbac.ErrUnauthorized,
+			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserRepository, guards *guard_mocks.MockGuards, command *command.AssignRole, authUser *auth.AuthenticatedUser) {
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.AssignRole).Return(rbac.ErrUnauthorized)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, userService := setupCommandUserService(t, tt)
+			err := userService.AssignRole.Handle(ctx, tt.command)
+			assertError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func testRemoveRole(t *testing.T) {
+	testCases := []commandTestCase[command.RemoveRole]{
+		{
+			name: "authorized user can remove a role",
+			authUser: &auth.AuthenticatedUser{
+				Id:    "userId-1234",
+				Roles: []rbac.UserRole{rbac.Admin},
+				Email: "admin@example.com",
+			},
+			command: command.RemoveRole{
+				Id:   "userId-3030303048933",
+				Role: rbac.Moderator,
+			},
+			expectedErr: nil,
+			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserRepository, guards *guard_mocks.MockGuards, command *command.RemoveRole, authUser *auth.AuthenticatedUser) {
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.AssignRole).Return(nil)
+				repo.EXPECT().UpdateRoles(mock.Anything, command.Id, mock.AnythingOfType("func(*domain.User) error")).RunAndReturn(
+					func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+						user, err := domain.NewUser("userId-239", "user@example.com", "username", []rbac.UserRole{rbac.Regular, rbac.Moderator}, time.Now(), time.Now(), nil, nil)
+						require.NoError(t, err, "Unable to create new user")
+						err = updateFn(&user)
+						require.NoError(t, err, "Unable to remove role")
+						require.Equal(t, []rbac.UserRole{rbac.Regular}, user.Roles(), "Role was not removed as expected")
+						return nil
+					})
+			},
+		},
+		{
+			name: "the last role of a user cannot be removed",
+			authUser: &auth.AuthenticatedUser{
+				Id:    "userId-1234",
+				Roles: []rbac.UserRole{rbac.Admin},
+				Email: "admin@example.com",
+			},
+			command: command.RemoveRole{
+				Id:   "userId-3030303048933",
+				Role: rbac.Regular,
+			},
+			expectedErr: domain.ErrLastRole,
+			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserRepository, guards *guard_mocks.MockGuards, command *command.RemoveRole, authUser *auth.AuthenticatedUser) {
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.AssignRole).Return(nil)
+				repo.EXPECT().UpdateRoles(mock.Anything, command.Id, mock.AnythingOfType("func(*domain.User) error")).RunAndReturn(
+					func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+						user := domain.MustNewUser("userId-239", "user@example.com", "username", []rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil, nil)
+						return updateFn(&user)
+					})
+			},
+		},
+		{
+			name: "unauthorized user cannot remove a role",
+			authUser: &auth.AuthenticatedUser{
+				Roles: []rbac.UserRole{rbac.Moderator},
+				Id:    "userId-12350500",
+				Email: "mod@example.com",
+			},
+			command: command.RemoveRole{
+				Id:   "userId-3030303048933",
+				Role: rbac.Moderator,
 			},
 			expectedErr: rbac.ErrUnauthorized,
-			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserRepository, guards *guard_mocks.MockGuards, command *command.MakeModerator, authUser *auth.AuthenticatedUser) {
-				guards.EXPECT().Authorize(authUser.Role, rbac.MakeModerator).Return(rbac.ErrUnauthorized)
+			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserRepository, guards *guard_mocks.MockGuards, command *command.RemoveRole, authUser *auth.AuthenticatedUser) {
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.AssignRole).Return(rbac.ErrUnauthorized)
 			},
 		},
 	}
@@ -446,7 +644,7 @@ func testMakeModerator(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			ctx, userService := setupCommandUserService(t, tt)
-			err := userService.MakeModerator.Handle(ctx, tt.command)
+			err := userService.RemoveRole.Handle(ctx, tt.command)
 			assertError(t, err, tt.expectedErr)
 		})
 	}
@@ -457,7 +655,7 @@ func testRevokeBadge(t *testing.T) {
 		{
 			name: "authorized user can revoke badge",
 			authUser: &auth.AuthenticatedUser{
-				Role:  rbac.Admin,
+				Roles: []rbac.UserRole{rbac.Admin},
 				Id:    "userId-12350500",
 				Email: "admin@example.com",
 			},
@@ -467,10 +665,10 @@ func testRevokeBadge(t *testing.T) {
 			},
 			expectedErr: nil,
 			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserRepository, guards *guard_mocks.MockGuards, command *command.RevokeAwardedBadge, authUser *auth.AuthenticatedUser) {
-				guards.EXPECT().Authorize(authUser.Role, rbac.RevokeBadge).Return(nil)
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.RevokeBadge).Return(nil)
 				repo.EXPECT().RevokeAwardedBadge(mock.Anything, command.Id, mock.AnythingOfType("func(*domain.User) error")).RunAndReturn(
 					func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
-						user, err := domain.NewUser("userId-123", "user@example.com", "username", rbac.Regular,
+						user, err := domain.NewUser("userId-123", "user@example.com", "username", []rbac.UserRole{rbac.Regular},
 							time.Now(), time.Now(), domain.MustNewUserReputation(5, []string{"5-stars"}), nil)
 						require.NoError(t, err, "Unable to create user")
 						require.Contains(t, user.Badges(), "5-stars")
@@ -484,7 +682,7 @@ func testRevokeBadge(t *testing.T) {
 		{
 			name: "unauthorized user cannot revoke badge",
 			authUser: &auth.AuthenticatedUser{
-				Role:  rbac.Regular,
+				Roles: []rbac.UserRole{rbac.Regular},
 				Id:    "userId-123",
 				Email: "user@example.com",
 			},
@@ -494,7 +692,7 @@ func testRevokeBadge(t *testing.T) {
 			},
 			expectedErr: rbac.ErrUnauthorized,
 			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserRepository, guards *guard_mocks.MockGuards, command *command.RevokeAwardedBadge, authUser *auth.AuthenticatedUser) {
-				guards.EXPECT().Authorize(authUser.Role, rbac.RevokeBadge).Return(rbac.ErrUnauthorized)
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.RevokeBadge).Return(rbac.ErrUnauthorized)
 			},
 		},
 	}
@@ -514,7 +712,7 @@ func testAwardBadge(t *testing.T) {
 		{
 			name: "authorized user can award badge",
 			authUser: &auth.AuthenticatedUser{
-				Role:  rbac.Admin,
+				Roles: []rbac.UserRole{rbac.Admin},
 				Id:    "userId-12350500",
 				Email: "admin@example.com",
 			},
@@ -524,10 +722,10 @@ func testAwardBadge(t *testing.T) {
 			},
 			expectedErr: nil,
 			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserRepository, guards *guard_mocks.MockGuards, command *command.AwardBadge, authUser *auth.AuthenticatedUser) {
-				guards.EXPECT().Authorize(authUser.Role, rbac.AwardBadge).Return(nil)
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.AwardBadge).Return(nil)
 				repo.EXPECT().AwardBadge(mock.Anything, command.Id, mock.AnythingOfType("func(*domain.User) error")).RunAndReturn(
 					func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
-						user, err := domain.NewUser("userId-123", "user@example.com", "username", rbac.Regular, time.Now(), time.Now(), nil, nil)
+						user, err := domain.NewUser("userId-123", "user@example.com", "username", []rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil, nil)
 						require.NoError(t, err)
 						err = updateFn(&user)
 						require.NoError(t, err)
@@ -539,7 +737,7 @@ func testAwardBadge(t *testing.T) {
 		{
 			name: "unauthorized user cannot award badge",
 			authUser: &auth.AuthenticatedUser{
-				Role:  rbac.Regular,
+				Roles: []rbac.UserRole{rbac.Regular},
 				Id:    "userId-123",
 				Email: "user@example.com",
 			},
@@ -549,7 +747,7 @@ func testAwardBadge(t *testing.T) {
 			},
 			expectedErr: rbac.ErrUnauthorized,
 			setupMocks: func(t *testing.T, repo *domain_mocks.MockUserRepository, guards *guard_mocks.MockGuards, command *command.AwardBadge, authUser *auth.AuthenticatedUser) {
-				guards.EXPECT().Authorize(authUser.Role, rbac.AwardBadge).Return(rbac.ErrUnauthorized)
+				guards.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.AwardBadge).Return(rbac.ErrUnauthorized)
 			},
 		},
 	}
@@ -570,7 +768,7 @@ func testChangeUsername(t *testing.T) {
 			name:        "user can change their username",
 			expectedErr: nil,
 			authUser: &auth.AuthenticatedUser{
-				Role:  rbac.Regular,
+				Roles: []rbac.UserRole{rbac.Regular},
 				Id:    "userId-123",
 				Email: "user@example.com",
 			},
@@ -580,10 +778,10 @@ func testChangeUsername(t *testing.T) {
 			},
 			setupMocks: func(t *testing.T, userRepo *domain_mocks.MockUserRepository, guard *guard_mocks.MockGuards, cmd *command.ChangeUsername, authUser *auth.AuthenticatedUser) {
 				userRepo.EXPECT().UserExists(mock.Anything, "", cmd.Username).Return(false, nil)
-				guard.EXPECT().CanChangeUsername(cmd.Id, authUser).Return(nil)
+				guard.EXPECT().Can(mock.Anything, abac.Edit, abac.User(cmd.Id)).Return(nil)
 				userRepo.EXPECT().ChangeUsername(mock.Anything, cmd.Id, mock.AnythingOfType("func(*domain.User) error")).RunAndReturn(
 					func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
-						user, err := domain.NewUser("userId-123", "user@example.com", "username", rbac.Regular, time.Now(), time.Now(), nil, nil)
+						user, err := domain.NewUser("userId-123", "user@example.com", "username", []rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil, nil)
 						require.NoError(t, err)
 						err = updateFn(&user)
 						require.NoError(t, err)
@@ -597,7 +795,7 @@ func testChangeUsername(t *testing.T) {
 			name:        "user cannot change their username with existing username",
 			expectedErr: domain.ErrEmailOrUsernameAlreadyExists,
 			authUser: &auth.AuthenticatedUser{
-				Role:  rbac.Regular,
+				Roles: []rbac.UserRole{rbac.Regular},
 				Id:    "userId-123",
 				Email: "user@example.com",
 			},
@@ -630,7 +828,7 @@ func testBanUser(t *testing.T) {
 			authUser: &auth.AuthenticatedUser{
 				Id:    "userId-12345",
 				Email: "admin@example.com",
-				Role:  rbac.Admin,
+				Roles: []rbac.UserRole{rbac.Admin},
 			},
 			command: command.BanUser{
 				Id:             "userId-123",
@@ -638,10 +836,10 @@ func testBanUser(t *testing.T) {
 				IsIndefinitely: true,
 			},
 			setupMocks: func(t *testing.T, userRepo *domain_mocks.MockUserRepository, guard *guard_mocks.MockGuards, cmd *command.BanUser, authUser *auth.AuthenticatedUser) {
-				guard.EXPECT().Authorize(authUser.Role, rbac.BanUser).Return(nil)
+				guard.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.BanUser).Return(nil)
 				userRepo.EXPECT().BanUser(mock.Anything, cmd.Id, mock.AnythingOfType("func(*domain.User) error")).RunAndReturn(
 					func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
-						user, err := domain.NewUser(cmd.Id, "testuser@gmail.com", "testuser", rbac.Regular, time.Now(), time.Now(), nil, nil)
+						user, err := domain.NewUser(cmd.Id, "testuser@gmail.com", "testuser", []rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil, nil)
 						require.NoError(t, err, "Failed to create user")
 						err = updateFn(&user)
 						if err != nil {
@@ -658,13 +856,13 @@ func testBanUser(t *testing.T) {
 			authUser: &auth.AuthenticatedUser{
 				Id:    "userId-12345",
 				Email: "user@example.com",
-				Role:  rbac.Regular,
+				Roles: []rbac.UserRole{rbac.Regular},
 			},
 			command: command.BanUser{
 				Id: "userId-123",
 			},
 			setupMocks: func(t *testing.T, userRepo *domain_mocks.MockUserRepository, guard *guard_mocks.MockGuards, cmd *command.BanUser, authUser *auth.AuthenticatedUser) {
-				guard.EXPECT().Authorize(authUser.Role, rbac.BanUser).Return(rbac.ErrUnauthorized)
+				guard.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.BanUser).Return(rbac.ErrUnauthorized)
 			},
 		},
 	}
@@ -687,14 +885,15 @@ func testRegister(t *testing.T) {
 			authUser: &auth.AuthenticatedUser{
 				Id:    "",
 				Email: "",
-				Role:  rbac.Guest,
+				Roles: []rbac.UserRole{rbac.Guest},
 			},
 			command: command.RegisterUser{
 				Email:    "test@example.com",
 				Username: "testuser",
+				Password: "s3cret-password",
 			},
 			setupMocks: func(t *testing.T, userRepo *domain_mocks.MockUserRepository, guard *guard_mocks.MockGuards, cmd *command.RegisterUser, authUser *auth.AuthenticatedUser) {
-				guard.EXPECT().Authorize(authUser.Role, rbac.CreateAccount).Return(nil)
+				guard.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreateAccount).Return(nil)
 				userRepo.EXPECT().UserExists(mock.Anything, cmd.Email, cmd.Username).Return(false, nil)
 				userRepo.EXPECT().Register(mock.Anything, mock.AnythingOfType("domain.User")).Return(nil)
 			},
@@ -705,14 +904,15 @@ func testRegister(t *testing.T) {
 			authUser: &auth.AuthenticatedUser{
 				Id:    "",
 				Email: "",
-				Role:  rbac.Guest,
+				Roles: []rbac.UserRole{rbac.Guest},
 			},
 			command: command.RegisterUser{
 				Email:    "test@example.com",
 				Username: "testuser",
+				Password: "s3cret-password",
 			},
 			setupMocks: func(t *testing.T, userRepo *domain_mocks.MockUserRepository, guard *guard_mocks.MockGuards, cmd *command.RegisterUser, authUser *auth.AuthenticatedUser) {
-				guard.EXPECT().Authorize(authUser.Role, rbac.CreateAccount).Return(nil)
+				guard.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreateAccount).Return(nil)
 				userRepo.EXPECT().UserExists(mock.Anything, cmd.Email, cmd.Username).Return(true, nil)
 			},
 		},
@@ -722,17 +922,55 @@ func testRegister(t *testing.T) {
 			authUser: &auth.AuthenticatedUser{
 				Id:    "",
 				Email: "",
-				Role:  rbac.Guest,
+				Roles: []rbac.UserRole{rbac.Guest},
 			},
 			command: command.RegisterUser{
 				Email:    "test@example.com",
 				Username: "testuser",
+				Password: "s3cret-password",
 			},
 			setupMocks: func(t *testing.T, userRepo *domain_mocks.MockUserRepository, guard *guard_mocks.MockGuards, cmd *command.RegisterUser, authUser *auth.AuthenticatedUser) {
-				guard.EXPECT().Authorize(authUser.Role, rbac.CreateAccount).Return(nil)
+				guard.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreateAccount).Return(nil)
 				userRepo.EXPECT().UserExists(mock.Anything, cmd.Email, cmd.Username).Return(true, nil)
 			},
 		},
+		{
+			name:        "cannot create new account taken by a concurrent registration",
+			expectedErr: domain.ErrEmailOrUsernameAlreadyExists,
+			authUser: &auth.AuthenticatedUser{
+				Id:    "",
+				Email: "",
+				Roles: []rbac.UserRole{rbac.Guest},
+			},
+			command: command.RegisterUser{
+				Email:    "test@example.com",
+				Username: "testuser",
+				Password: "s3cret-password",
+			},
+			setupMocks: func(t *testing.T, userRepo *domain_mocks.MockUserRepository, guard *guard_mocks.MockGuards, cmd *command.RegisterUser, authUser *auth.AuthenticatedUser) {
+				guard.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreateAccount).Return(nil)
+				userRepo.EXPECT().UserExists(mock.Anything, cmd.Email, cmd.Username).Return(false, nil)
+				// The unique index catches what the check missed
+				userRepo.EXPECT().Register(mock.Anything, mock.AnythingOfType("domain.User")).Return(domain.ErrEmailOrUsernameAlreadyExists)
+			},
+		},
+		{
+			name:        "cannot create new account with a short password",
+			expectedErr: domain.ErrPasswordTooShort,
+			authUser: &auth.AuthenticatedUser{
+				Id:    "",
+				Email: "",
+				Roles: []rbac.UserRole{rbac.Guest},
+			},
+			command: command.RegisterUser{
+				Email:    "test@example.com",
+				Username: "testuser",
+				Password: "short",
+			},
+			setupMocks: func(t *testing.T, userRepo *domain_mocks.MockUserRepository, guard *guard_mocks.MockGuards, cmd *command.RegisterUser, authUser *auth.AuthenticatedUser) {
+				guard.EXPECT().Authorize(mock.Anything, authUser.Subject(), rbac.CreateAccount).Return(nil)
+			},
+		},
 	}
 	for _, tt := range testCases {
 		tt := tt
@@ -756,7 +994,7 @@ func setupCommandUserService[T any](t *testing.T, tt commandTestCase[T]) (contex
 
 	tt.setupMocks(t, userRepo, guard, &tt.command, tt.authUser)
 
-	userService := service.New(userRepo, userReadModelRepo, guard)
+	userService := service.New(userRepo, userReadModelRepo, notBanned, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), newAccountData(), deletionGracePeriod, newAuditLog(), guard)
 
 	return ctxWithAuthUser, userService
 }
@@ -770,7 +1008,7 @@ func setupQueryUserService[T any, D any](t *testing.T, tt queryTestCase[T, D]) (
 
 	tt.setupMocks(t, userReadModelRepo, guard, tt.query, tt.authUser)
 
-	userService := service.New(userRepo, userReadModelRepo, guard)
+	userService := service.New(userRepo, userReadModelRepo, notBanned, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), newAccountData(), deletionGracePeriod, newAuditLog(), guard)
 
 	return ctxWithAuthUser, userService
 }
@@ -783,3 +1021,868 @@ func assertError(t *testing.T, err error, expectedErr error) {
 		assert.NoError(t, err)
 	}
 }
+
+func TestEventRecording(t *testing.T) {
+	t.Parallel()
+	admin := &auth.AuthenticatedUser{
+		Id:    "userId-12345",
+		Email: "admin@example.com",
+		Roles: []rbac.UserRole{rbac.Admin},
+	}
+
+	t.Run("should leave raised events on the user for the repository to save", func(t *testing.T) {
+		t.Parallel()
+		userRepo := domain_mocks.NewMockUserRepository(t)
+		guard := guard_mocks.NewMockGuards(t)
+		guard.EXPECT().Authorize(mock.Anything, admin.Subject(), rbac.BanUser).Return(nil)
+		userService := service.New(userRepo, domain_mocks.NewMockUserReadModelRepository(t), notBanned, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), newAccountData(), deletionGracePeriod, newAuditLog(), guard)
+
+		var saved []events.Event
+		userRepo.EXPECT().BanUser(mock.Anything, "userId-123", mock.AnythingOfType("func(*domain.User) error")).RunAndReturn(
+			func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+				user := domain.MustNewUser(userId, "testuser@gmail.com", "testuser", []rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil, nil)
+				require.NoError(t, updateFn(&user))
+				saved = user.PullEvents()
+				return nil
+			})
+
+		err := userService.BanUser.Handle(auth.NewContextWithUser(context.Background(), admin), command.BanUser{
+			Id:             "userId-123",
+			Reason:         "abuse",
+			IsIndefinitely: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, saved, 1)
+		assert.Equal(t, "userId-123", saved[0].(domain.UserBanned).UserId)
+	})
+
+	t.Run("should leave the registration event on the new user", func(t *testing.T) {
+		t.Parallel()
+		userRepo := domain_mocks.NewMockUserRepository(t)
+		guard := guard_mocks.NewMockGuards(t)
+		guard.EXPECT().Authorize(mock.Anything, rbac.Subject{Roles: []rbac.UserRole{rbac.Guest}}, rbac.CreateAccount).Return(nil)
+		userService := service.New(userRepo, domain_mocks.NewMockUserReadModelRepository(t), notBanned, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), newAccountData(), deletionGracePeriod, newAuditLog(), guard)
+
+		userRepo.EXPECT().UserExists(mock.Anything, "testuser@gmail.com", "testuser").Return(false, nil)
+		userRepo.EXPECT().Register(mock.Anything, mock.AnythingOfType("domain.User")).RunAndReturn(
+			func(ctx context.Context, user domain.User) error {
+				assert.NoError(t, auth.CheckPassword(user.PasswordHash(), "s3cret-password"))
+				raised := user.PullEvents()
+				require.Len(t, raised, 1)
+				assert.Equal(t, "testuser", raised[0].(domain.UserRegistered).Username)
+				return nil
+			})
+
+		err := userService.RegisterUser.Handle(auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Roles: []rbac.UserRole{rbac.Guest}}), command.RegisterUser{
+			Email:    "testuser@gmail.com",
+			Username: "testuser",
+			Password: "s3cret-password",
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestRetryOnConcurrentModification(t *testing.T) {
+	t.Parallel()
+	admin := &auth.AuthenticatedUser{
+		Id:    "userId-12345",
+		Email: "admin@example.com",
+		Roles: []rbac.UserRole{rbac.Admin},
+	}
+	setup := func(t *testing.T) (*service.Application, *domain_mocks.MockUserRepository) {
+		userRepo := domain_mocks.NewMockUserRepository(t)
+		guard := guard_mocks.NewMockGuards(t)
+		guard.EXPECT().Authorize(mock.Anything, admin.Subject(), rbac.AwardBadge).Return(nil)
+		return service.New(userRepo, domain_mocks.NewMockUserReadModelRepository(t), notBanned, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), newAccountData(), deletionGracePeriod, newAuditLog(), guard), userRepo
+	}
+	ctx := auth.NewContextWithUser(context.Background(), admin)
+
+	t.Run("should retry the update when the user was modified concurrently", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo := setup(t)
+		userRepo.EXPECT().AwardBadge(mock.Anything, "userId-123", mock.AnythingOfType("func(*domain.User) error")).
+			Return(domain.ErrConcurrentModification).Once()
+		userRepo.EXPECT().AwardBadge(mock.Anything, "userId-123", mock.AnythingOfType("func(*domain.User) error")).
+			Return(nil).Once()
+
+		err := userService.AwardBadge.Handle(ctx, command.AwardBadge{Id: "userId-123", Badge: "helpful"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("should give up after a bounded number of attempts", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo := setup(t)
+		userRepo.EXPECT().AwardBadge(mock.Anything, "userId-123", mock.AnythingOfType("func(*domain.User) error")).
+			Return(domain.ErrConcurrentModification).Times(3)
+
+		err := userService.AwardBadge.Handle(ctx, command.AwardBadge{Id: "userId-123", Badge: "helpful"})
+		assert.ErrorIs(t, err, domain.ErrConcurrentModification)
+	})
+
+	t.Run("should not retry other errors", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo := setup(t)
+		userRepo.EXPECT().AwardBadge(mock.Anything, "userId-123", mock.AnythingOfType("func(*domain.User) error")).
+			Return(domain.ErrUserNotFound).Once()
+
+		err := userService.AwardBadge.Handle(ctx, command.AwardBadge{Id: "userId-123", Badge: "helpful"})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
+
+func TestLiftExpiredBans(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	expiredBan := func(userId string) domain.User {
+		return domain.MustNewUser(userId, userId+"@gmail.com", userId, []rbac.UserRole{rbac.Regular}, now, now, nil,
+			domain.NewBan(true, "spam", false, now.Add(-2*time.Hour), now.Add(-time.Hour), now.Add(-2*time.Hour)))
+	}
+	setup := func(t *testing.T) (*service.Application, *domain_mocks.MockUserRepository) {
+		userRepo := domain_mocks.NewMockUserRepository(t)
+		return service.New(userRepo, domain_mocks.NewMockUserReadModelRepository(t), notBanned, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), newAccountData(), deletionGracePeriod, newAuditLog(), guard_mocks.NewMockGuards(t)), userRepo
+	}
+
+	t.Run("should lift every expired ban of the batch", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo := setup(t)
+		userRepo.EXPECT().ExpiredBans(mock.Anything, now, 10).Return([]string{"user-1", "user-2"}, nil)
+		var lifted []string
+		userRepo.EXPECT().UnbanUser(mock.Anything, mock.Anything, mock.AnythingOfType("func(*domain.User) error")).RunAndReturn(
+			func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+				user := expiredBan(userId)
+				require.NoError(t, updateFn(&user))
+				assert.False(t, user.HasBan())
+				lifted = append(lifted, userId)
+				return nil
+			}).Times(2)
+
+		err := userService.LiftExpiredBans.Handle(context.Background(), command.LiftExpiredBans{Now: now, BatchSize: 10})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user-1", "user-2"}, lifted)
+	})
+
+	t.Run("should skip users whose ban changed since the lookup", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo := setup(t)
+		userRepo.EXPECT().ExpiredBans(mock.Anything, now, 10).Return([]string{"user-1"}, nil)
+		userRepo.EXPECT().UnbanUser(mock.Anything, "user-1", mock.AnythingOfType("func(*domain.User) error")).RunAndReturn(
+			func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+				// Banned again, indefinitely, in the meantime
+				user := domain.MustNewUser(userId, "user-1@gmail.com", userId, []rbac.UserRole{rbac.Regular}, now, now, nil, nil)
+				require.NoError(t, user.Ban("abuse", true, nil))
+				return updateFn(&user)
+			})
+
+		err := userService.LiftExpiredBans.Handle(context.Background(), command.LiftExpiredBans{Now: now, BatchSize: 10})
+		assert.NoError(t, err)
+	})
+
+	t.Run("should carry on with the batch and report failures", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo := setup(t)
+		userRepo.EXPECT().ExpiredBans(mock.Anything, now, 10).Return([]string{"user-1", "user-2"}, nil)
+		userRepo.EXPECT().UnbanUser(mock.Anything, "user-1", mock.AnythingOfType("func(*domain.User) error")).Return(assert.AnError)
+		userRepo.EXPECT().UnbanUser(mock.Anything, "user-2", mock.AnythingOfType("func(*domain.User) error")).Return(nil)
+
+		err := userService.LiftExpiredBans.Handle(context.Background(), command.LiftExpiredBans{Now: now, BatchSize: 10})
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestBanEnforcement(t *testing.T) {
+	t.Parallel()
+	userRepo := domain_mocks.NewMockUserRepository(t)
+	banned := bans.CheckerFunc(func(ctx context.Context, userId string) error {
+		return &bans.ErrUserBanned{Reason: "spam"}
+	})
+	userService := service.New(userRepo, domain_mocks.NewMockUserReadModelRepository(t), banned, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), newAccountData(), deletionGracePeriod, newAuditLog(), guard_mocks.NewMockGuards(t))
+	ctx := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Id: "userId-123", Roles: []rbac.UserRole{rbac.Regular}})
+
+	err := userService.ChangeUsername.Handle(ctx, command.ChangeUsername{Id: "userId-123", Username: "newname"})
+	var bannedErr *bans.ErrUserBanned
+	assert.ErrorAs(t, err, &bannedErr)
+	userRepo.AssertNotCalled(t, "ChangeUsername")
+}
+
+func TestLogin(t *testing.T) {
+	t.Parallel()
+	passwordHash, err := auth.HashPassword("s3cret-password")
+	require.NoError(t, err)
+	registered := func() *domain.User {
+		user, err := domain.RegisterUser("userId-123", "testuser@gmail.com", "testuser", passwordHash, time.Now())
+		require.NoError(t, err)
+		return &user
+	}
+	setup := func(t *testing.T) (*service.Application, *domain_mocks.MockUserRepository) {
+		userRepo := domain_mocks.NewMockUserRepository(t)
+		return service.New(userRepo, domain_mocks.NewMockUserReadModelRepository(t), notBanned, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), newAccountData(), deletionGracePeriod, newAuditLog(), guard_mocks.NewMockGuards(t)), userRepo
+	}
+	ctx := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Roles: []rbac.UserRole{rbac.Guest}})
+
+	t.Run("should issue an access token for the right password", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo := setup(t)
+		userRepo.EXPECT().GetUserBy(mock.Anything, "email", "testuser@gmail.com").Return(registered(), nil)
+
+		token, err := userService.Login.Handle(ctx, command.Login{Email: "testuser@gmail.com", Password: "s3cret-password"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, token.AccessToken)
+		assert.NotEmpty(t, token.RefreshToken)
+		assert.Equal(t, auth.BearerTokenType, token.TokenType)
+		assert.True(t, token.ExpiresAt.After(time.Now()))
+	})
+
+	t.Run("should find the user whatever the case of the email", func(t *testing.T) {
+		t.Parallel()
+		userRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
+		require.NoError(t, userRepo.Register(context.Background(), *registered()))
+		userService := service.New(userRepo, userRepo, notBanned, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), newAccountData(), deletionGracePeriod, newAuditLog(), guard_mocks.NewMockGuards(t))
+
+		token, err := userService.Login.Handle(ctx, command.Login{Email: "TestUser@Gmail.com", Password: "s3cret-password"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, token.AccessToken)
+	})
+
+	t.Run("should refuse a wrong password", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo := setup(t)
+		userRepo.EXPECT().GetUserBy(mock.Anything, "email", "testuser@gmail.com").Return(registered(), nil)
+
+		_, err := userService.Login.Handle(ctx, command.Login{Email: "testuser@gmail.com", Password: "wrong-password"})
+		assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	})
+
+	t.Run("should refuse an unknown email with the same error", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo := setup(t)
+		userRepo.EXPECT().GetUserBy(mock.Anything, "email", "nobody@gmail.com").Return(nil, domain.ErrUserNotFound)
+
+		_, err := userService.Login.Handle(ctx, command.Login{Email: "nobody@gmail.com", Password: "s3cret-password"})
+		assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	})
+
+	t.Run("should refuse users who never set a password", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo := setup(t)
+		user := domain.MustNewUser("userId-123", "testuser@gmail.com", "testuser", []rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil, nil)
+		userRepo.EXPECT().GetUserBy(mock.Anything, "email", "testuser@gmail.com").Return(&user, nil)
+
+		_, err := userService.Login.Handle(ctx, command.Login{Email: "testuser@gmail.com", Password: ""})
+		assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	})
+}
+
+func TestSessions(t *testing.T) {
+	t.Parallel()
+	passwordHash, err := auth.HashPassword("s3cret-password")
+	require.NoError(t, err)
+	setup := func(t *testing.T) (*service.Application, *domain_mocks.MockUserRepository) {
+		userRepo := domain_mocks.NewMockUserRepository(t)
+		user, err := domain.RegisterUser("userId-123", "testuser@gmail.com", "testuser", passwordHash, time.Now())
+		require.NoError(t, err)
+		userRepo.EXPECT().GetUserBy(mock.Anything, "email", "testuser@gmail.com").Return(&user, nil).Maybe()
+		return service.New(userRepo, domain_mocks.NewMockUserReadModelRepository(t), notBanned, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), newAccountData(), deletionGracePeriod, newAuditLog(), guard_mocks.NewMockGuards(t)), userRepo
+	}
+	login := func(t *testing.T, userService *service.Application) *auth.Token {
+		t.Helper()
+		ctx := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Roles: []rbac.UserRole{rbac.Guest}})
+		token, err := userService.Login.Handle(ctx, command.Login{Email: "testuser@gmail.com", Password: "s3cret-password", Device: "phone"})
+		require.NoError(t, err)
+		return token
+	}
+
+	t.Run("should refresh tokens with the user's current role", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo := setup(t)
+		token := login(t, userService)
+		moderator := domain.MustNewUser("userId-123", "testuser@gmail.com", "testuser", []rbac.UserRole{rbac.Moderator}, time.Now(), time.Now(), nil, nil)
+		userRepo.EXPECT().GetUserBy(mock.Anything, "id", "userId-123").Return(&moderator, nil)
+
+		refreshed, err := userService.RefreshToken.Handle(context.Background(), command.RefreshToken{RefreshToken: token.RefreshToken})
+		require.NoError(t, err)
+		assert.NotEqual(t, token.RefreshToken, refreshed.RefreshToken)
+	})
+
+	t.Run("should not refresh tokens of a user who no longer exists", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo := setup(t)
+		token := login(t, userService)
+		userRepo.EXPECT().GetUserBy(mock.Anything, "id", "userId-123").Return(nil, domain.ErrUserNotFound)
+
+		_, err := userService.RefreshToken.Handle(context.Background(), command.RefreshToken{RefreshToken: token.RefreshToken})
+		assert.ErrorIs(t, err, sessions.ErrInvalidRefreshToken)
+	})
+
+	t.Run("should let banned users log out", func(t *testing.T) {
+		t.Parallel()
+		banned := bans.CheckerFunc(func(ctx context.Context, userId string) error { return &bans.ErrUserBanned{Reason: "spam"} })
+		userRepo := domain_mocks.NewMockUserRepository(t)
+		userService := service.New(userRepo, domain_mocks.NewMockUserReadModelRepository(t), banned, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), newAccountData(), deletionGracePeriod, newAuditLog(), guard_mocks.NewMockGuards(t))
+		ctx := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Id: "userId-123", Email: "testuser@gmail.com", Roles: []rbac.UserRole{rbac.Regular}, SessionId: "session-1"})
+
+		assert.NoError(t, userService.Logout.Handle(ctx, command.Logout{}))
+		assert.NoError(t, userService.LogoutAllSessions.Handle(ctx, command.LogoutAllSessions{}))
+	})
+
+	t.Run("should not refresh tokens after logging out", func(t *testing.T) {
+		t.Parallel()
+		userService, _ := setup(t)
+		phone := login(t, userService)
+		laptop := login(t, userService)
+		ctx := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Id: "userId-123", Email: "testuser@gmail.com", Roles: []rbac.UserRole{rbac.Regular}})
+
+		require.NoError(t, userService.LogoutAllSessions.Handle(ctx, command.LogoutAllSessions{}))
+
+		for _, token := range []*auth.Token{phone, laptop} {
+			_, err := userService.RefreshToken.Handle(context.Background(), command.RefreshToken{RefreshToken: token.RefreshToken})
+			assert.ErrorIs(t, err, sessions.ErrInvalidRefreshToken)
+		}
+	})
+
+	t.Run("should refuse to log out guests", func(t *testing.T) {
+		t.Parallel()
+		userService, _ := setup(t)
+		ctx := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Roles: []rbac.UserRole{rbac.Guest}})
+
+		assert.ErrorIs(t, userService.Logout.Handle(ctx, command.Logout{}), rbac.ErrUnauthorized)
+		assert.ErrorIs(t, userService.LogoutAllSessions.Handle(ctx, command.LogoutAllSessions{}), rbac.ErrUnauthorized)
+	})
+}
+
+func TestAPIKeys(t *testing.T) {
+	t.Parallel()
+	owner := &auth.AuthenticatedUser{Id: "userId-123", Email: "testuser@gmail.com", Roles: []rbac.UserRole{rbac.Regular}}
+	setup := func(t *testing.T, checker bans.Checker) *service.Application {
+		return service.New(domain_mocks.NewMockUserRepository(t), domain_mocks.NewMockUserReadModelRepository(t), checker, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), newAccountData(), deletionGracePeriod, newAuditLog(), guards.New())
+	}
+	create := func(t *testing.T, userService *service.Application, user *auth.AuthenticatedUser, id string, scopes ...rbac.Permission) (string, error) {
+		t.Helper()
+		key, err := apikeys.NewKey()
+		require.NoError(t, err)
+		ctx := auth.NewContextWithUser(context.Background(), user)
+		return key, userService.CreateAPIKey.Handle(ctx, command.CreateAPIKey{Id: id, Key: key, Name: "deploy bot", Scopes: scopes})
+	}
+
+	t.Run("should create, list and revoke the user's keys", func(t *testing.T) {
+		t.Parallel()
+		userService := setup(t, notBanned)
+		ctx := auth.NewContextWithUser(context.Background(), owner)
+
+		_, err := create(t, userService, owner, "key-1", rbac.CreatePost, rbac.ViewPost)
+		require.NoError(t, err)
+		require.NoError(t, userService.RevokeAPIKey.Handle(ctx, command.RevokeAPIKey{Id: "key-1"}))
+
+		keys, err := userService.GetAPIKeys.Handle(ctx, query.GetAPIKeys{})
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, []rbac.Permission{rbac.CreatePost, rbac.ViewPost}, keys[0].Scopes)
+		assert.NotNil(t, keys[0].RevokedAt)
+
+		other := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Id: "userId-456", Email: "other@gmail.com", Roles: []rbac.UserRole{rbac.Regular}})
+		assert.ErrorIs(t, userService.RevokeAPIKey.Handle(other, command.RevokeAPIKey{Id: "key-1"}), apikeys.ErrAPIKeyNotFound)
+	})
+
+	t.Run("should not grant scopes the user doesn't have", func(t *testing.T) {
+		t.Parallel()
+		userService := setup(t, notBanned)
+
+		_, err := create(t, userService, owner, "key-1", rbac.CreatePost, rbac.BanUser)
+		assert.ErrorIs(t, err, rbac.ErrUnauthorized)
+	})
+
+	t.Run("should not let a key create keys with more scopes than its own", func(t *testing.T) {
+		t.Parallel()
+		userService := setup(t, notBanned)
+		scoped := *owner
+		scoped.APIKeyId = "key-1"
+		scoped.Scopes = []rbac.Permission{rbac.ManageAPIKeys, rbac.ViewPost}
+
+		_, err := create(t, userService, &scoped, "key-2", rbac.ViewPost)
+		assert.NoError(t, err)
+		_, err = create(t, userService, &scoped, "key-3", rbac.CreatePost)
+		assert.ErrorIs(t, err, rbac.ErrUnauthorized)
+	})
+
+	t.Run("should refuse guests", func(t *testing.T) {
+		t.Parallel()
+		userService := setup(t, notBanned)
+		guest := &auth.AuthenticatedUser{Roles: []rbac.UserRole{rbac.Guest}}
+
+		_, err := create(t, userService, guest, "key-1", rbac.ViewPost)
+		assert.ErrorIs(t, err, rbac.ErrUnauthorized)
+		_, err = userService.GetAPIKeys.Handle(auth.NewContextWithUser(context.Background(), guest), query.GetAPIKeys{})
+		assert.ErrorIs(t, err, rbac.ErrUnauthorized)
+	})
+
+	t.Run("should let banned users revoke but not create keys", func(t *testing.T) {
+		t.Parallel()
+		banned := bans.CheckerFunc(func(ctx context.Context, userId string) error { return &bans.ErrUserBanned{Reason: "spam"} })
+		userService := setup(t, banned)
+
+		_, err := create(t, userService, owner, "key-1", rbac.ViewPost)
+		var bannedErr *bans.ErrUserBanned
+		assert.ErrorAs(t, err, &bannedErr)
+		ctx := auth.NewContextWithUser(context.Background(), owner)
+		assert.ErrorIs(t, userService.RevokeAPIKey.Handle(ctx, command.RevokeAPIKey{Id: "key-1"}), apikeys.ErrAPIKeyNotFound)
+	})
+}
+
+func TestAccountMail(t *testing.T) {
+	t.Parallel()
+	passwordHash, err := auth.HashPassword("s3cret-password")
+	require.NoError(t, err)
+	registered := func(t *testing.T) *domain.User {
+		user, err := domain.RegisterUser("userId-123", "testuser@gmail.com", "testuser", passwordHash, time.Now())
+		require.NoError(t, err)
+		user.PullEvents()
+		return &user
+	}
+	setup := func(t *testing.T) (*service.Application, *domain_mocks.MockUserRepository, *accountmail.Mailer, *mail.MemoryMailer) {
+		userRepo := domain_mocks.NewMockUserRepository(t)
+		sent := mail.NewMemoryMailer()
+		accountMail := newAccountMailWith(sent)
+		return service.New(userRepo, domain_mocks.NewMockUserReadModelRepository(t), notBanned, newSessions(), newAPIKeys(), accountMail, newMFA(), newRoles(), newAccountData(), deletionGracePeriod, newAuditLog(), guards.New()), userRepo, accountMail, sent
+	}
+	// lastToken returns the token in the link of the last email sent to an address
+	lastToken := func(t *testing.T, sent *mail.MemoryMailer, to string) string {
+		t.Helper()
+		msg, ok := sent.Last(to)
+		require.True(t, ok)
+		for _, line := range strings.Split(msg.Body, "\n") {
+			if link, err := url.Parse(line); err == nil && link.Query().Has("token") {
+				return link.Query().Get("token")
+			}
+		}
+		t.Fatalf("no link in %q", msg.Body)
+		return ""
+	}
+	guest := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Roles: []rbac.UserRole{rbac.Guest}})
+
+	t.Run("should verify the email once", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo, accountMail, sent := setup(t)
+		user := registered(t)
+		userRepo.EXPECT().VerifyEmail(mock.Anything, "userId-123", mock.Anything).RunAndReturn(
+			func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+				return updateFn(user)
+			})
+		require.NoError(t, accountMail.SendVerificationEmail(context.Background(), "userId-123", "testuser@gmail.com"))
+		token := lastToken(t, sent, "testuser@gmail.com")
+
+		require.NoError(t, userService.VerifyEmail.Handle(guest, command.VerifyEmail{Token: token}))
+		assert.True(t, user.IsEmailVerified())
+		assert.Len(t, user.PullEvents(), 1)
+
+		err := userService.VerifyEmail.Handle(guest, command.VerifyEmail{Token: token})
+		assert.ErrorIs(t, err, onetime.ErrTokenUsed)
+	})
+
+	t.Run("should not verify an email the link wasn't sent to", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo, accountMail, sent := setup(t)
+		user := registered(t)
+		userRepo.EXPECT().VerifyEmail(mock.Anything, "userId-123", mock.Anything).RunAndReturn(
+			func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+				return updateFn(user)
+			})
+		require.NoError(t, accountMail.SendVerificationEmail(context.Background(), "userId-123", "another@gmail.com"))
+
+		err := userService.VerifyEmail.Handle(guest, command.VerifyEmail{Token: lastToken(t, sent, "another@gmail.com")})
+		assert.ErrorIs(t, err, onetime.ErrInvalidToken)
+		assert.False(t, user.IsEmailVerified())
+	})
+
+	t.Run("should resend the verification email to unverified users only", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo, _, sent := setup(t)
+		user := registered(t)
+		userRepo.EXPECT().GetUserBy(mock.Anything, "id", "userId-123").Return(user, nil)
+		ctx := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Id: "userId-123", Email: "testuser@gmail.com", Roles: []rbac.UserRole{rbac.Regular}, EmailUnverified: true})
+
+		require.NoError(t, userService.RequestEmailVerification.Handle(ctx, command.RequestEmailVerification{}))
+		assert.Len(t, sent.Sent(), 1)
+
+		require.NoError(t, user.VerifyEmail(time.Now()))
+		err := userService.RequestEmailVerification.Handle(ctx, command.RequestEmailVerification{})
+		assert.ErrorIs(t, err, domain.ErrEmailAlreadyVerified)
+		assert.Len(t, sent.Sent(), 1)
+
+		err = userService.RequestEmailVerification.Handle(guest, command.RequestEmailVerification{})
+		assert.ErrorIs(t, err, rbac.ErrUnauthorized)
+	})
+
+	t.Run("should reset the password once", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo, _, sent := setup(t)
+		user := registered(t)
+		userRepo.EXPECT().GetUserBy(mock.Anything, "email", "testuser@gmail.com").Return(user, nil)
+		userRepo.EXPECT().ResetPassword(mock.Anything, "userId-123", mock.Anything).RunAndReturn(
+			func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+				return updateFn(user)
+			})
+
+		require.NoError(t, userService.RequestPasswordReset.Handle(guest, command.RequestPasswordReset{Email: "testuser@gmail.com"}))
+		token := lastToken(t, sent, "testuser@gmail.com")
+
+		// A password that is too short doesn't use up the token
+		err := userService.ResetPassword.Handle(guest, command.ResetPassword{Token: token, NewPassword: "short"})
+		assert.ErrorIs(t, err, domain.ErrPasswordTooShort)
+
+		require.NoError(t, userService.ResetPassword.Handle(guest, command.ResetPassword{Token: token, NewPassword: "n3w-s3cret-password"}))
+		assert.NoError(t, auth.CheckPassword(user.PasswordHash(), "n3w-s3cret-password"))
+		raised := user.PullEvents()
+		require.Len(t, raised, 1)
+		assert.IsType(t, domain.UserPasswordReset{}, raised[0])
+
+		err = userService.ResetPassword.Handle(guest, command.ResetPassword{Token: token, NewPassword: "an0ther-password"})
+		assert.ErrorIs(t, err, onetime.ErrTokenUsed)
+	})
+
+	t.Run("should not reveal unknown emails", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo, _, sent := setup(t)
+		userRepo.EXPECT().GetUserBy(mock.Anything, "email", "nobody@gmail.com").Return(nil, domain.ErrUserNotFound)
+
+		assert.NoError(t, userService.RequestPasswordReset.Handle(guest, command.RequestPasswordReset{Email: "nobody@gmail.com"}))
+		assert.Empty(t, sent.Sent())
+	})
+}
+
+func TestMFA(t *testing.T) {
+	t.Parallel()
+	passwordHash, err := auth.HashPassword("s3cret-password")
+	require.NoError(t, err)
+	setup := func(t *testing.T) *service.Application {
+		userRepo := domain_mocks.NewMockUserRepository(t)
+		user, err := domain.RegisterUser("userId-123", "testuser@gmail.com", "testuser", passwordHash, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, user.VerifyEmail(time.Now()))
+		userRepo.EXPECT().GetUserBy(mock.Anything, "email", "testuser@gmail.com").Return(&user, nil).Maybe()
+		userRepo.EXPECT().GetUserBy(mock.Anything, "id", "userId-123").Return(&user, nil).Maybe()
+		return service.New(userRepo, domain_mocks.NewMockUserReadModelRepository(t), notBanned, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), newAccountData(), deletionGracePeriod, newAuditLog(), guards.New())
+	}
+	owner := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Id: "userId-123", Email: "testuser@gmail.com", Roles: []rbac.UserRole{rbac.Regular}})
+	guest := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Roles: []rbac.UserRole{rbac.Guest}})
+	// enable turns on two-factor authentication and returns the secret and recovery codes
+	enable := func(t *testing.T, userService *service.Application) (string, []string) {
+		t.Helper()
+		secret, err := totp.GenerateSecret()
+		require.NoError(t, err)
+		recoveryCodes, err := mfa.NewRecoveryCodes()
+		require.NoError(t, err)
+		require.NoError(t, userService.SetupMFA.Handle(owner, command.SetupMFA{Secret: secret}))
+		code, err := totp.Code(secret, time.Now().Add(-totp.Period))
+		require.NoError(t, err)
+		require.NoError(t, userService.EnableMFA.Handle(owner, command.EnableMFA{Code: code, RecoveryCodes: recoveryCodes}))
+		return secret, recoveryCodes
+	}
+	login := func(t *testing.T, userService *service.Application) *auth.Token {
+		t.Helper()
+		token, err := userService.Login.Handle(guest, command.Login{Email: "testuser@gmail.com", Password: "s3cret-password"})
+		require.NoError(t, err)
+		return token
+	}
+
+	t.Run("should log in with a password only until mfa is enabled", func(t *testing.T) {
+		t.Parallel()
+		userService := setup(t)
+		assert.NotEmpty(t, login(t, userService).AccessToken)
+
+		enable(t, userService)
+		enabled, err := userService.GetMFAStatus.Handle(owner, query.GetMFAStatus{})
+		require.NoError(t, err)
+		assert.True(t, enabled)
+
+		token := login(t, userService)
+		assert.Empty(t, token.AccessToken)
+		assert.Empty(t, token.RefreshToken)
+		assert.NotEmpty(t, token.MFAToken)
+	})
+
+	t.Run("should complete the login with a code", func(t *testing.T) {
+		t.Parallel()
+		userService := setup(t)
+		secret, _ := enable(t, userService)
+		code, err := totp.Code(secret, time.Now())
+		require.NoError(t, err)
+
+		token, err := userService.CompleteLogin.Handle(guest, command.CompleteLogin{MFAToken: login(t, userService).MFAToken, Code: code})
+		require.NoError(t, err)
+		claims, err := auth.ParseToken(auth.NewHMACKeySet([]byte("test-secret")), token.AccessToken)
+		require.NoError(t, err)
+		assert.True(t, claims.MFA)
+	})
+
+	t.Run("should complete the login with a recovery code once", func(t *testing.T) {
+		t.Parallel()
+		userService := setup(t)
+		_, recoveryCodes := enable(t, userService)
+
+		_, err := userService.CompleteLogin.Handle(guest, command.CompleteLogin{MFAToken: login(t, userService).MFAToken, Code: recoveryCodes[0]})
+		require.NoError(t, err)
+		_, err = userService.CompleteLogin.Handle(guest, command.CompleteLogin{MFAToken: login(t, userService).MFAToken, Code: recoveryCodes[0]})
+		assert.ErrorIs(t, err, mfa.ErrInvalidCode)
+	})
+
+	t.Run("should not complete the login with a wrong code", func(t *testing.T) {
+		t.Parallel()
+		userService := setup(t)
+		enable(t, userService)
+		mfaToken := login(t, userService).MFAToken
+
+		_, err := userService.CompleteLogin.Handle(guest, command.CompleteLogin{MFAToken: mfaToken, Code: "00000-00000"})
+		assert.ErrorIs(t, err, mfa.ErrInvalidCode)
+		_, err = userService.CompleteLogin.Handle(guest, command.CompleteLogin{MFAToken: "not-a-token", Code: "00000-00000"})
+		assert.ErrorIs(t, err, onetime.ErrInvalidToken)
+	})
+
+	t.Run("should disable mfa with a code", func(t *testing.T) {
+		t.Parallel()
+		userService := setup(t)
+		_, recoveryCodes := enable(t, userService)
+
+		require.NoError(t, userService.DisableMFA.Handle(owner, command.DisableMFA{Code: recoveryCodes[0]}))
+		assert.NotEmpty(t, login(t, userService).AccessToken)
+	})
+
+	t.Run("should refuse guests", func(t *testing.T) {
+		t.Parallel()
+		userService := setup(t)
+
+		err := userService.SetupMFA.Handle(guest, command.SetupMFA{Secret: "JBSWY3DPEHPK3PXP"})
+		assert.ErrorIs(t, err, rbac.ErrUnauthorized)
+	})
+}
+
+func TestRoles(t *testing.T) {
+	t.Parallel()
+	userService := service.New(domain_mocks.NewMockUserRepository(t), domain_mocks.NewMockUserReadModelRepository(t), notBanned, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), newAccountData(), deletionGracePeriod, newAuditLog(), guards.New())
+	admin := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Id: "userId-1", Roles: []rbac.UserRole{rbac.Admin}})
+	moderator := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Id: "userId-2", Roles: []rbac.UserRole{rbac.Moderator}})
+
+	t.Run("should list the effective permissions of the roles to admins", func(t *testing.T) {
+		t.Parallel()
+		roles, err := userService.GetRoles.Handle(admin, query.GetRoles{})
+		require.NoError(t, err)
+		assert.Len(t, roles, 4)
+
+		role, err := userService.GetRole.Handle(admin, query.GetRole{Role: rbac.Moderator})
+		require.NoError(t, err)
+		assert.Equal(t, []rbac.UserRole{rbac.Regular}, role.Inherits)
+		assert.Contains(t, role.Permissions, rbac.CreatePost)
+		assert.Contains(t, role.Permissions, rbac.BanUser)
+
+		_, err = userService.GetRole.Handle(admin, query.GetRole{Role: "SUPPORT"})
+		assert.ErrorIs(t, err, rbac.ErrUnknownRole)
+	})
+
+	t.Run("should refuse other roles", func(t *testing.T) {
+		t.Parallel()
+		_, err := userService.GetRoles.Handle(moderator, query.GetRoles{})
+		assert.ErrorIs(t, err, rbac.ErrUnauthorized)
+	})
+}
+
+func TestAuditLog(t *testing.T) {
+	t.Parallel()
+	admin := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Id: "userId-1", Roles: []rbac.UserRole{rbac.Admin}})
+	regular := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Id: "userId-2", Roles: []rbac.UserRole{rbac.Regular}})
+
+	setup := func(t *testing.T) (*service.Application, *domain_mocks.MockUserRepository) {
+		auditLog := newAuditLog()
+		userRepo := domain_mocks.NewMockUserRepository(t)
+		guard := guards.WithAudit(guards.New(), auditLog)
+		return service.New(userRepo, domain_mocks.NewMockUserReadModelRepository(t), notBanned, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), newAccountData(), deletionGracePeriod, auditLog, guard), userRepo
+	}
+
+	t.Run("should record who banned a user", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo := setup(t)
+		userRepo.EXPECT().BanUser(mock.Anything, "userId-3", mock.AnythingOfType("func(*domain.User) error")).RunAndReturn(
+			func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+				user := domain.MustNewUser(userId, "testuser@gmail.com", "testuser", []rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil, nil)
+				return updateFn(&user)
+			})
+
+		require.NoError(t, userService.BanUser.Handle(admin, command.BanUser{Id: "userId-3", Reason: "spam", IsIndefinitely: true}))
+
+		result, err := userService.GetAuditLog.Handle(admin, query.GetAuditLog{First: 10, Target: "userId-3"})
+		require.NoError(t, err)
+		require.Len(t, result.Data, 1)
+		entry := result.Data[0]
+		assert.Equal(t, audit.Command, entry.Kind)
+		assert.Equal(t, "userId-1", entry.ActorId)
+		assert.Equal(t, string(rbac.BanUser), entry.Permission)
+		assert.Equal(t, "spam", entry.Details)
+		assert.Equal(t, audit.Succeeded, entry.Outcome)
+	})
+
+	t.Run("should record denied requests", func(t *testing.T) {
+		t.Parallel()
+		userService, _ := setup(t)
+
+		err := userService.BanUser.Handle(regular, command.BanUser{Id: "userId-3", Reason: "spam", IsIndefinitely: true})
+		require.ErrorIs(t, err, rbac.ErrUnauthorized)
+
+		result, err := userService.GetAuditLog.Handle(admin, query.GetAuditLog{First: 10, ActorId: "userId-2"})
+		require.NoError(t, err)
+		require.Len(t, result.Data, 2)
+		assert.Equal(t, audit.Failed, result.Data[0].Outcome, "the command is recorded after the decision")
+		assert.Equal(t, audit.Authorization, result.Data[1].Kind)
+		assert.Equal(t, audit.Denied, result.Data[1].Outcome)
+	})
+
+	t.Run("should only show the log to admins", func(t *testing.T) {
+		t.Parallel()
+		userService, _ := setup(t)
+
+		_, err := userService.GetAuditLog.Handle(regular, query.GetAuditLog{First: 10})
+		assert.ErrorIs(t, err, rbac.ErrUnauthorized)
+	})
+}
+
+func TestAccountDeletion(t *testing.T) {
+	t.Parallel()
+	owner := &auth.AuthenticatedUser{Id: "userId-1", Email: "testuser@gmail.com", Roles: []rbac.UserRole{rbac.Regular}}
+	ownerCtx := auth.NewContextWithUser(context.Background(), owner)
+	setup := func(t *testing.T, accountData *accountDataStub) (*service.Application, *domain_mocks.MockUserRepository) {
+		userRepo := domain_mocks.NewMockUserRepository(t)
+		return service.New(userRepo, domain_mocks.NewMockUserReadModelRepository(t), notBanned, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), accountData, deletionGracePeriod, newAuditLog(), guards.New()), userRepo
+	}
+	// stored runs updateFn on user, standing in for the repository
+	stored := func(t *testing.T, user *domain.User) func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+		return func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+			require.Equal(t, user.Id(), userId)
+			return updateFn(user)
+		}
+	}
+	newUser := func(userId string) *domain.User {
+		user := domain.MustNewUser(userId, userId+"@gmail.com", userId, []rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil, nil)
+		return &user
+	}
+
+	t.Run("should let users delete their own account and change their mind", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo := setup(t, newAccountData())
+		user := newUser("userId-1")
+		userRepo.EXPECT().ScheduleDeletion(mock.Anything, "userId-1", mock.AnythingOfType("func(*domain.User) error")).RunAndReturn(stored(t, user))
+
+		require.NoError(t, userService.RequestAccountDeletion.Handle(ownerCtx, command.RequestAccountDeletion{Id: "userId-1"}))
+		assert.True(t, user.IsDeletionRequested())
+		assert.WithinDuration(t, time.Now().Add(deletionGracePeriod), user.DeletionDueAt(), time.Minute)
+
+		require.NoError(t, userService.CancelAccountDeletion.Handle(ownerCtx, command.CancelAccountDeletion{Id: "userId-1"}))
+		assert.False(t, user.IsDeletionRequested())
+	})
+
+	t.Run("should not let users delete someone else's account", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo := setup(t, newAccountData())
+
+		err := userService.RequestAccountDeletion.Handle(ownerCtx, command.RequestAccountDeletion{Id: "userId-2"})
+		assert.ErrorIs(t, err, rbac.ErrUnauthorized)
+		userRepo.AssertNotCalled(t, "ScheduleDeletion")
+	})
+
+	t.Run("should let admins delete any account", func(t *testing.T) {
+		t.Parallel()
+		userService, userRepo := setup(t, newAccountData())
+		user := newUser("userId-2")
+		userRepo.EXPECT().ScheduleDeletion(mock.Anything, "userId-2", mock.AnythingOfType("func(*domain.User) error")).RunAndReturn(stored(t, user))
+		admin := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Id: "userId-3", Roles: []rbac.UserRole{rbac.Admin}})
+
+		require.NoError(t, userService.RequestAccountDeletion.Handle(admin, command.RequestAccountDeletion{Id: "userId-2"}))
+		assert.True(t, user.IsDeletionRequested())
+	})
+
+	t.Run("should not let an API key delete the account of its owner without the permission", func(t *testing.T) {
+		t.Parallel()
+		userService, _ := setup(t, newAccountData())
+		scoped := *owner
+		scoped.APIKeyId = "key-1"
+		scoped.Scopes = []rbac.Permission{rbac.ViewUser}
+		ctx := auth.NewContextWithUser(context.Background(), &scoped)
+
+		err := userService.RequestAccountDeletion.Handle(ctx, command.RequestAccountDeletion{Id: "userId-1"})
+		assert.ErrorIs(t, err, rbac.ErrUnauthorized)
+	})
+
+	t.Run("should anonymize the data of due accounts before deleting them", func(t *testing.T) {
+		t.Parallel()
+		accountData := newAccountData()
+		userService, userRepo := setup(t, accountData)
+		now := time.Now()
+		user := newUser("userId-1")
+		user.SetDeletion(now.Add(-deletionGracePeriod), now.Add(-time.Second))
+		userRepo.EXPECT().DueDeletions(mock.Anything, now, 10).Return([]string{"userId-1"}, nil)
+		userRepo.EXPECT().DeleteUser(mock.Anything, "userId-1", mock.AnythingOfType("func(*domain.User) error")).RunAndReturn(
+			func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+				assert.Equal(t, []string{"userId-1"}, accountData.erased, "the data is anonymized first")
+				require.NoError(t, updateFn(user))
+				assert.Equal(t, []events.Event{domain.UserDeleted{UserId: "userId-1", DeletedAt: now}}, user.PullEvents())
+				return nil
+			})
+
+		require.NoError(t, userService.EraseDueAccounts.Handle(context.Background(), command.EraseDueAccounts{Now: now, BatchSize: 10}))
+	})
+
+	t.Run("should keep the user when their data can't be anonymized", func(t *testing.T) {
+		t.Parallel()
+		accountData := newAccountData()
+		accountData.err = assert.AnError
+		userService, userRepo := setup(t, accountData)
+		now := time.Now()
+		userRepo.EXPECT().DueDeletions(mock.Anything, now, 10).Return([]string{"userId-1"}, nil)
+
+		err := userService.EraseDueAccounts.Handle(context.Background(), command.EraseDueAccounts{Now: now, BatchSize: 10})
+		assert.ErrorIs(t, err, assert.AnError)
+		userRepo.AssertNotCalled(t, "DeleteUser")
+	})
+}
+
+func TestExportAccountData(t *testing.T) {
+	t.Parallel()
+	owner := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Id: "userId-1", Roles: []rbac.UserRole{rbac.Regular}})
+	admin := auth.NewContextWithUser(context.Background(), &auth.AuthenticatedUser{Id: "userId-2", Roles: []rbac.UserRole{rbac.Admin}})
+	setup := func(t *testing.T) (*service.Application, *domain_mocks.MockUserReadModelRepository, *audit.Log) {
+		userReadModelRepo := domain_mocks.NewMockUserReadModelRepository(t)
+		// Each entry is recorded a second after the previous one
+		clock := time.Now()
+		auditLog := audit.NewLog(audit.NewMemoryStore(), audit.WithClock(func() time.Time {
+			clock = clock.Add(time.Second)
+			return clock
+		}))
+		accountData := newAccountData()
+		accountData.posts = []*contentDomain.PostReadModel{{Id: "post-1", AuthorId: "userId-1"}}
+		return service.New(domain_mocks.NewMockUserRepository(t), userReadModelRepo, notBanned, newSessions(), newAPIKeys(), newAccountMail(), newMFA(), newRoles(), accountData, deletionGracePeriod, auditLog, guards.New()), userReadModelRepo, auditLog
+	}
+
+	t.Run("should export the profile, ban history and activity of the user", func(t *testing.T) {
+		t.Parallel()
+		userService, userReadModelRepo, auditLog := setup(t)
+		userReadModelRepo.EXPECT().GetUserById(mock.Anything, "userId-1").Return(&domain.UserReadModel{Id: "userId-1", Username: "testuser"}, nil)
+		auditLog.Record(admin, audit.Entry{Kind: audit.Command, Target: "userId-1", Permission: string(rbac.BanUser), Details: "spam", Outcome: audit.Succeeded})
+		auditLog.Record(admin, audit.Entry{Kind: audit.Command, Target: "userId-1", Permission: string(rbac.UnbanUser), Outcome: audit.Succeeded})
+		auditLog.Record(admin, audit.Entry{Kind: audit.Command, Target: "userId-1", Permission: string(rbac.BanUser), Outcome: audit.Failed})
+
+		export, err := userService.ExportAccountData.Handle(owner, query.ExportAccountData{Id: "userId-1"})
+		require.NoError(t, err)
+		assert.Equal(t, "testuser", export.Profile.Username)
+		require.Len(t, export.BanHistory, 2)
+		assert.Equal(t, query.BanRecord{Banned: true, Reason: "spam", At: export.BanHistory[0].At}, export.BanHistory[0])
+		assert.False(t, export.BanHistory[1].Banned)
+		require.Len(t, export.Posts, 1)
+		assert.Equal(t, "post-1", export.Posts[0].Id)
+	})
+
+	t.Run("should only export someone else's data with the permission to delete users", func(t *testing.T) {
+		t.Parallel()
+		userService, userReadModelRepo, _ := setup(t)
+		userReadModelRepo.EXPECT().GetUserById(mock.Anything, "userId-3").Return(&domain.UserReadModel{Id: "userId-3"}, nil)
+
+		_, err := userService.ExportAccountData.Handle(owner, query.ExportAccountData{Id: "userId-3"})
+		assert.ErrorIs(t, err, rbac.ErrUnauthorized)
+		_, err = userService.ExportAccountData.Handle(admin, query.ExportAccountData{Id: "userId-3"})
+		assert.NoError(t, err)
+	})
+}
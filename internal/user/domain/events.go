@@ -1 +1,126 @@
 package domain
+
+import (
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+)
+
+type UserRegistered struct {
+	UserId     string
+	Email      string
+	Username   string
+	Registered time.Time
+}
+
+func (e UserRegistered) EventName() string     { return "user.registered" }
+func (e UserRegistered) OccurredAt() time.Time { return e.Registered }
+
+type UserBanned struct {
+	UserId       string
+	Reason       string
+	IsIndefinite bool
+	From         time.Time
+	To           time.Time
+	BannedAt     time.Time
+}
+
+func (e UserBanned) EventName() string     { return "user.banned" }
+func (e UserBanned) OccurredAt() time.Time { return e.BannedAt }
+
+type UserUnbanned struct {
+	UserId     string
+	UnbannedAt time.Time
+}
+
+func (e UserUnbanned) EventName() string     { return "user.unbanned" }
+func (e UserUnbanned) OccurredAt() time.Time { return e.UnbannedAt }
+
+type BadgeAwarded struct {
+	UserId    string
+	Badge     string
+	AwardedAt time.Time
+}
+
+func (e BadgeAwarded) EventName() string     { return "user.badge_awarded" }
+func (e BadgeAwarded) OccurredAt() time.Time { return e.AwardedAt }
+
+type BadgeRevoked struct {
+	UserId    string
+	Badge     string
+	RevokedAt time.Time
+}
+
+func (e BadgeRevoked) EventName() string     { return "user.badge_revoked" }
+func (e BadgeRevoked) OccurredAt() time.Time { return e.RevokedAt }
+
+type UsernameChanged struct {
+	UserId      string
+	OldUsername string
+	NewUsername string
+	ChangedAt   time.Time
+}
+
+func (e UsernameChanged) EventName() string     { return "user.username_changed" }
+func (e UsernameChanged) OccurredAt() time.Time { return e.ChangedAt }
+
+type UserRoleAssigned struct {
+	UserId     string
+	Role       rbac.UserRole
+	AssignedAt time.Time
+}
+
+func (e UserRoleAssigned) EventName() string     { return "user.role_assigned" }
+func (e UserRoleAssigned) OccurredAt() time.Time { return e.AssignedAt }
+
+type UserRoleRemoved struct {
+	UserId    string
+	Role      rbac.UserRole
+	RemovedAt time.Time
+}
+
+func (e UserRoleRemoved) EventName() string     { return "user.role_removed" }
+func (e UserRoleRemoved) OccurredAt() time.Time { return e.RemovedAt }
+
+type UserEmailVerified struct {
+	UserId     string
+	Email      string
+	VerifiedAt time.Time
+}
+
+func (e UserEmailVerified) EventName() string     { return "user.email_verified" }
+func (e UserEmailVerified) OccurredAt() time.Time { return e.VerifiedAt }
+
+type UserPasswordReset struct {
+	UserId  string
+	ResetAt time.Time
+}
+
+func (e UserPasswordReset) EventName() string     { return "user.password_reset" }
+func (e UserPasswordReset) OccurredAt() time.Time { return e.ResetAt }
+
+type UserDeletionRequested struct {
+	UserId      string
+	DueAt       time.Time
+	RequestedAt time.Time
+}
+
+func (e UserDeletionRequested) EventName() string     { return "user.deletion_requested" }
+func (e UserDeletionRequested) OccurredAt() time.Time { return e.RequestedAt }
+
+type UserDeletionCancelled struct {
+	UserId      string
+	CancelledAt time.Time
+}
+
+func (e UserDeletionCancelled) EventName() string     { return "user.deletion_cancelled" }
+func (e UserDeletionCancelled) OccurredAt() time.Time { return e.CancelledAt }
+
+// UserDeleted is raised when a user is erased, after their content was anonymized
+type UserDeleted struct {
+	UserId    string
+	DeletedAt time.Time
+}
+
+func (e UserDeleted) EventName() string     { return "user.deleted" }
+func (e UserDeleted) OccurredAt() time.Time { return e.DeletedAt }
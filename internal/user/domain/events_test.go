@@ -0,0 +1,99 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/user/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserEvents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should raise UserRegistered when a user registers", func(t *testing.T) {
+		t.Parallel()
+		registeredAt := time.Now()
+		user, err := domain.RegisterUser("user-1", "johndoe@example.com", "johndoe", "password-hash", registeredAt)
+		require.Nil(t, err)
+		assert.Equal(t, []rbac.UserRole{rbac.Regular}, user.Roles())
+		assert.Equal(t, []events.Event{domain.UserRegistered{
+			UserId:     "user-1",
+			Email:      "johndoe@example.com",
+			Username:   "johndoe",
+			Registered: registeredAt,
+		}}, user.PullEvents())
+	})
+
+	t.Run("should not raise events when a user is loaded", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		assert.Empty(t, user.PullEvents())
+	})
+
+	t.Run("should raise UsernameChanged with the previous username", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		oldUsername := user.Username()
+		require.Nil(t, user.ChangeUsername("mikedoe"))
+		raised := user.PullEvents()
+		require.Len(t, raised, 1)
+		event := raised[0].(domain.UsernameChanged)
+		assert.Equal(t, oldUsername, event.OldUsername)
+		assert.Equal(t, "mikedoe", event.NewUsername)
+	})
+
+	t.Run("should raise UserBanned and UserUnbanned", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		require.Nil(t, user.Ban("abuse", true, nil))
+		require.Nil(t, user.UnBan())
+		raised := user.PullEvents()
+		require.Len(t, raised, 2)
+		banned := raised[0].(domain.UserBanned)
+		assert.Equal(t, "abuse", banned.Reason)
+		assert.True(t, banned.IsIndefinite)
+		assert.IsType(t, domain.UserUnbanned{}, raised[1])
+	})
+
+	t.Run("should raise BadgeAwarded and BadgeRevoked", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		require.Nil(t, user.AwardBadge("5 star"))
+		require.Nil(t, user.RevokeAwardedBadge("5 star"))
+		raised := user.PullEvents()
+		require.Len(t, raised, 2)
+		assert.Equal(t, "5 star", raised[0].(domain.BadgeAwarded).Badge)
+		assert.Equal(t, "5 star", raised[1].(domain.BadgeRevoked).Badge)
+	})
+
+	t.Run("should raise UserRoleAssigned and UserRoleRemoved when roles change", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		require.Nil(t, user.AssignRole(rbac.Moderator))
+		require.Nil(t, user.RemoveRole(rbac.Regular))
+		raised := user.PullEvents()
+		require.Len(t, raised, 2)
+		assert.Equal(t, rbac.Moderator, raised[0].(domain.UserRoleAssigned).Role)
+		assert.Equal(t, rbac.Regular, raised[1].(domain.UserRoleRemoved).Role)
+	})
+
+	t.Run("should not raise events when a command fails", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		assert.NotNil(t, user.Ban("", true, nil))
+		assert.NotNil(t, user.AwardBadge(""))
+		assert.Empty(t, user.PullEvents())
+	})
+
+	t.Run("should clear events once they are pulled", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		require.Nil(t, user.AwardBadge("5 star"))
+		assert.Len(t, user.PullEvents(), 1)
+		assert.Empty(t, user.PullEvents())
+	})
+}
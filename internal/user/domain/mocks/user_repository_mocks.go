@@ -6,6 +6,7 @@ package domain_mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/iammrsea/social-app/internal/user/domain"
 	mock "github.com/stretchr/testify/mock"
@@ -383,6 +384,169 @@ func (_c *MockUserRepository_ChangeUsername_Call) RunAndReturn(run func(ctx cont
 	return _c
 }
 
+// DeleteUser provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) DeleteUser(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	ret := _mock.Called(ctx, userId, updateFn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, func(user *domain.User) error) error); ok {
+		r0 = returnFunc(ctx, userId, updateFn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_DeleteUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUser'
+type MockUserRepository_DeleteUser_Call struct {
+	*mock.Call
+}
+
+// DeleteUser is a helper method to define mock.On call
+//   - ctx
+//   - userId
+//   - updateFn
+func (_e *MockUserRepository_Expecter) DeleteUser(ctx interface{}, userId interface{}, updateFn interface{}) *MockUserRepository_DeleteUser_Call {
+	return &MockUserRepository_DeleteUser_Call{Call: _e.mock.On("DeleteUser", ctx, userId, updateFn)}
+}
+
+func (_c *MockUserRepository_DeleteUser_Call) Run(run func(ctx context.Context, userId string, updateFn func(user *domain.User) error)) *MockUserRepository_DeleteUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(func(user *domain.User) error))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_DeleteUser_Call) Return(err error) *MockUserRepository_DeleteUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_DeleteUser_Call) RunAndReturn(run func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error) *MockUserRepository_DeleteUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DueDeletions provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) DueDeletions(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	ret := _mock.Called(ctx, now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DueDeletions")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]string, error)); ok {
+		return returnFunc(ctx, now, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) []string); ok {
+		r0 = returnFunc(ctx, now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = returnFunc(ctx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserRepository_DueDeletions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DueDeletions'
+type MockUserRepository_DueDeletions_Call struct {
+	*mock.Call
+}
+
+// DueDeletions is a helper method to define mock.On call
+//   - ctx
+//   - now
+//   - limit
+func (_e *MockUserRepository_Expecter) DueDeletions(ctx interface{}, now interface{}, limit interface{}) *MockUserRepository_DueDeletions_Call {
+	return &MockUserRepository_DueDeletions_Call{Call: _e.mock.On("DueDeletions", ctx, now, limit)}
+}
+
+func (_c *MockUserRepository_DueDeletions_Call) Run(run func(ctx context.Context, now time.Time, limit int)) *MockUserRepository_DueDeletions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_DueDeletions_Call) Return(vs []string, err error) *MockUserRepository_DueDeletions_Call {
+	_c.Call.Return(vs, err)
+	return _c
+}
+
+func (_c *MockUserRepository_DueDeletions_Call) RunAndReturn(run func(ctx context.Context, now time.Time, limit int) ([]string, error)) *MockUserRepository_DueDeletions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExpiredBans provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) ExpiredBans(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	ret := _mock.Called(ctx, now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpiredBans")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]string, error)); ok {
+		return returnFunc(ctx, now, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) []string); ok {
+		r0 = returnFunc(ctx, now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = returnFunc(ctx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserRepository_ExpiredBans_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExpiredBans'
+type MockUserRepository_ExpiredBans_Call struct {
+	*mock.Call
+}
+
+// ExpiredBans is a helper method to define mock.On call
+//   - ctx
+//   - now
+//   - limit
+func (_e *MockUserRepository_Expecter) ExpiredBans(ctx interface{}, now interface{}, limit interface{}) *MockUserRepository_ExpiredBans_Call {
+	return &MockUserRepository_ExpiredBans_Call{Call: _e.mock.On("ExpiredBans", ctx, now, limit)}
+}
+
+func (_c *MockUserRepository_ExpiredBans_Call) Run(run func(ctx context.Context, now time.Time, limit int)) *MockUserRepository_ExpiredBans_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ExpiredBans_Call) Return(vs []string, err error) *MockUserRepository_ExpiredBans_Call {
+	_c.Call.Return(vs, err)
+	return _c
+}
+
+func (_c *MockUserRepository_ExpiredBans_Call) RunAndReturn(run func(ctx context.Context, now time.Time, limit int) ([]string, error)) *MockUserRepository_ExpiredBans_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetUserBy provides a mock function for the type MockUserRepository
 func (_mock *MockUserRepository) GetUserBy(ctx context.Context, fieldName string, value any) (*domain.User, error) {
 	ret := _mock.Called(ctx, fieldName, value)
@@ -441,95 +605,95 @@ func (_c *MockUserRepository_GetUserBy_Call) RunAndReturn(run func(ctx context.C
 	return _c
 }
 
-// MakeModerator provides a mock function for the type MockUserRepository
-func (_mock *MockUserRepository) MakeModerator(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
-	ret := _mock.Called(ctx, userId, updateFn)
+// Register provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) Register(ctx context.Context, user domain.User) error {
+	ret := _mock.Called(ctx, user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for MakeModerator")
+		panic("no return value specified for Register")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, func(user *domain.User) error) error); ok {
-		r0 = returnFunc(ctx, userId, updateFn)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.User) error); ok {
+		r0 = returnFunc(ctx, user)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockUserRepository_MakeModerator_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MakeModerator'
-type MockUserRepository_MakeModerator_Call struct {
+// MockUserRepository_Register_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Register'
+type MockUserRepository_Register_Call struct {
 	*mock.Call
 }
 
-// MakeModerator is a helper method to define mock.On call
+// Register is a helper method to define mock.On call
 //   - ctx
-//   - userId
-//   - updateFn
-func (_e *MockUserRepository_Expecter) MakeModerator(ctx interface{}, userId interface{}, updateFn interface{}) *MockUserRepository_MakeModerator_Call {
-	return &MockUserRepository_MakeModerator_Call{Call: _e.mock.On("MakeModerator", ctx, userId, updateFn)}
+//   - user
+func (_e *MockUserRepository_Expecter) Register(ctx interface{}, user interface{}) *MockUserRepository_Register_Call {
+	return &MockUserRepository_Register_Call{Call: _e.mock.On("Register", ctx, user)}
 }
 
-func (_c *MockUserRepository_MakeModerator_Call) Run(run func(ctx context.Context, userId string, updateFn func(user *domain.User) error)) *MockUserRepository_MakeModerator_Call {
+func (_c *MockUserRepository_Register_Call) Run(run func(ctx context.Context, user domain.User)) *MockUserRepository_Register_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(func(user *domain.User) error))
+		run(args[0].(context.Context), args[1].(domain.User))
 	})
 	return _c
 }
 
-func (_c *MockUserRepository_MakeModerator_Call) Return(err error) *MockUserRepository_MakeModerator_Call {
+func (_c *MockUserRepository_Register_Call) Return(err error) *MockUserRepository_Register_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserRepository_MakeModerator_Call) RunAndReturn(run func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error) *MockUserRepository_MakeModerator_Call {
+func (_c *MockUserRepository_Register_Call) RunAndReturn(run func(ctx context.Context, user domain.User) error) *MockUserRepository_Register_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Register provides a mock function for the type MockUserRepository
-func (_mock *MockUserRepository) Register(ctx context.Context, user domain.User) error {
-	ret := _mock.Called(ctx, user)
+// ResetPassword provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) ResetPassword(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	ret := _mock.Called(ctx, userId, updateFn)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Register")
+		panic("no return value specified for ResetPassword")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.User) error); ok {
-		r0 = returnFunc(ctx, user)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, func(user *domain.User) error) error); ok {
+		r0 = returnFunc(ctx, userId, updateFn)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockUserRepository_Register_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Register'
-type MockUserRepository_Register_Call struct {
+// MockUserRepository_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
+type MockUserRepository_ResetPassword_Call struct {
 	*mock.Call
 }
 
-// Register is a helper method to define mock.On call
+// ResetPassword is a helper method to define mock.On call
 //   - ctx
-//   - user
-func (_e *MockUserRepository_Expecter) Register(ctx interface{}, user interface{}) *MockUserRepository_Register_Call {
-	return &MockUserRepository_Register_Call{Call: _e.mock.On("Register", ctx, user)}
+//   - userId
+//   - updateFn
+func (_e *MockUserRepository_Expecter) ResetPassword(ctx interface{}, userId interface{}, updateFn interface{}) *MockUserRepository_ResetPassword_Call {
+	return &MockUserRepository_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, userId, updateFn)}
 }
 
-func (_c *MockUserRepository_Register_Call) Run(run func(ctx context.Context, user domain.User)) *MockUserRepository_Register_Call {
+func (_c *MockUserRepository_ResetPassword_Call) Run(run func(ctx context.Context, userId string, updateFn func(user *domain.User) error)) *MockUserRepository_ResetPassword_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(domain.User))
+		run(args[0].(context.Context), args[1].(string), args[2].(func(user *domain.User) error))
 	})
 	return _c
 }
 
-func (_c *MockUserRepository_Register_Call) Return(err error) *MockUserRepository_Register_Call {
+func (_c *MockUserRepository_ResetPassword_Call) Return(err error) *MockUserRepository_ResetPassword_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockUserRepository_Register_Call) RunAndReturn(run func(ctx context.Context, user domain.User) error) *MockUserRepository_Register_Call {
+func (_c *MockUserRepository_ResetPassword_Call) RunAndReturn(run func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error) *MockUserRepository_ResetPassword_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -581,6 +745,53 @@ func (_c *MockUserRepository_RevokeAwardedBadge_Call) RunAndReturn(run func(ctx
 	return _c
 }
 
+// ScheduleDeletion provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) ScheduleDeletion(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	ret := _mock.Called(ctx, userId, updateFn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScheduleDeletion")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, func(user *domain.User) error) error); ok {
+		r0 = returnFunc(ctx, userId, updateFn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_ScheduleDeletion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ScheduleDeletion'
+type MockUserRepository_ScheduleDeletion_Call struct {
+	*mock.Call
+}
+
+// ScheduleDeletion is a helper method to define mock.On call
+//   - ctx
+//   - userId
+//   - updateFn
+func (_e *MockUserRepository_Expecter) ScheduleDeletion(ctx interface{}, userId interface{}, updateFn interface{}) *MockUserRepository_ScheduleDeletion_Call {
+	return &MockUserRepository_ScheduleDeletion_Call{Call: _e.mock.On("ScheduleDeletion", ctx, userId, updateFn)}
+}
+
+func (_c *MockUserRepository_ScheduleDeletion_Call) Run(run func(ctx context.Context, userId string, updateFn func(user *domain.User) error)) *MockUserRepository_ScheduleDeletion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(func(user *domain.User) error))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_ScheduleDeletion_Call) Return(err error) *MockUserRepository_ScheduleDeletion_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_ScheduleDeletion_Call) RunAndReturn(run func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error) *MockUserRepository_ScheduleDeletion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UnbanUser provides a mock function for the type MockUserRepository
 func (_mock *MockUserRepository) UnbanUser(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
 	ret := _mock.Called(ctx, userId, updateFn)
@@ -628,6 +839,53 @@ func (_c *MockUserRepository_UnbanUser_Call) RunAndReturn(run func(ctx context.C
 	return _c
 }
 
+// UpdateRoles provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) UpdateRoles(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	ret := _mock.Called(ctx, userId, updateFn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRoles")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, func(user *domain.User) error) error); ok {
+		r0 = returnFunc(ctx, userId, updateFn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_UpdateRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRoles'
+type MockUserRepository_UpdateRoles_Call struct {
+	*mock.Call
+}
+
+// UpdateRoles is a helper method to define mock.On call
+//   - ctx
+//   - userId
+//   - updateFn
+func (_e *MockUserRepository_Expecter) UpdateRoles(ctx interface{}, userId interface{}, updateFn interface{}) *MockUserRepository_UpdateRoles_Call {
+	return &MockUserRepository_UpdateRoles_Call{Call: _e.mock.On("UpdateRoles", ctx, userId, updateFn)}
+}
+
+func (_c *MockUserRepository_UpdateRoles_Call) Run(run func(ctx context.Context, userId string, updateFn func(user *domain.User) error)) *MockUserRepository_UpdateRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(func(user *domain.User) error))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateRoles_Call) Return(err error) *MockUserRepository_UpdateRoles_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_UpdateRoles_Call) RunAndReturn(run func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error) *MockUserRepository_UpdateRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UserExists provides a mock function for the type MockUserRepository
 func (_mock *MockUserRepository) UserExists(ctx context.Context, email string, username string) (bool, error) {
 	ret := _mock.Called(ctx, email, username)
@@ -683,3 +941,50 @@ func (_c *MockUserRepository_UserExists_Call) RunAndReturn(run func(ctx context.
 	_c.Call.Return(run)
 	return _c
 }
+
+// VerifyEmail provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) VerifyEmail(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	ret := _mock.Called(ctx, userId, updateFn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyEmail")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, func(user *domain.User) error) error); ok {
+		r0 = returnFunc(ctx, userId, updateFn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_VerifyEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyEmail'
+type MockUserRepository_VerifyEmail_Call struct {
+	*mock.Call
+}
+
+// VerifyEmail is a helper method to define mock.On call
+//   - ctx
+//   - userId
+//   - updateFn
+func (_e *MockUserRepository_Expecter) VerifyEmail(ctx interface{}, userId interface{}, updateFn interface{}) *MockUserRepository_VerifyEmail_Call {
+	return &MockUserRepository_VerifyEmail_Call{Call: _e.mock.On("VerifyEmail", ctx, userId, updateFn)}
+}
+
+func (_c *MockUserRepository_VerifyEmail_Call) Run(run func(ctx context.Context, userId string, updateFn func(user *domain.User) error)) *MockUserRepository_VerifyEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(func(user *domain.User) error))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_VerifyEmail_Call) Return(err error) *MockUserRepository_VerifyEmail_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_VerifyEmail_Call) RunAndReturn(run func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error) *MockUserRepository_VerifyEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
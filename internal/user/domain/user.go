@@ -7,18 +7,25 @@ import (
 	"strings"
 	"time"
 
+	"github.com/iammrsea/social-app/internal/shared/events"
 	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
 )
 
 type User struct {
-	id         string
-	email      string
-	username   string
-	reputation *userReputation
-	role       rbac.UserRole
-	banStatus  *ban
-	joinedAt   time.Time
-	updatedAt  time.Time
+	id           string
+	email        string
+	username     string
+	reputation   *userReputation
+	roles        []rbac.UserRole
+	banStatus    *ban
+	passwordHash string
+	// emailVerifiedAt is zero until the user proves the email is theirs
+	emailVerifiedAt time.Time
+	// deletion is nil unless the user asked for the account to be deleted
+	deletion  *deletion
+	joinedAt  time.Time
+	updatedAt time.Time
+	events    events.Recorder
 }
 
 type userReputation struct {
@@ -31,17 +38,23 @@ var (
 	ErrUserEmailRequired            = errors.New("user email cannot be empty")
 	ErrUsernameRequired             = errors.New("username cannot be empty")
 	ErrUserRoleRequired             = errors.New("user role cannot be empty")
+	ErrInvalidRole                  = errors.New("invalid user role")
+	ErrRoleAlreadyAssigned          = errors.New("role already assigned")
+	ErrRoleNotAssigned              = errors.New("role not assigned")
+	ErrLastRole                     = errors.New("a user must keep at least one role")
 	ErrBadgeRequired                = errors.New("badge cannot be empty")
 	ErrInvalidIncrementValue        = errors.New("you cannot increment user reputation by a value less than one")
 	ErrInvalidDecrementValue        = errors.New("you cannot decrement user reputation by a value less than one")
 	ErrInvalidRepScore              = errors.New("invalid reputation score")
-	ErrEmailAlreadyExists           = errors.New("email already exists")
 	ErrEmailOrUsernameAlreadyExists = errors.New("email or username already exists")
 	ErrUserNotFound                 = errors.New("user not found")
+	// ErrConcurrentModification is returned by repositories when the user changed between being
+	// read and being saved. The update can be retried on a fresh copy of the user.
+	ErrConcurrentModification = errors.New("user was modified concurrently")
 )
 
 func NewUser(
-	id, email, username string, role rbac.UserRole, joinedAt time.Time, updatedAt time.Time,
+	id, email, username string, roles []rbac.UserRole, joinedAt time.Time, updatedAt time.Time,
 	reputation *userReputation, banStatus *ban) (User, error) {
 	user := User{}
 	if strings.TrimSpace(id) == "" {
@@ -53,11 +66,13 @@ func NewUser(
 	if strings.TrimSpace(username) == "" {
 		return user, ErrUsernameRequired
 	}
-	if strings.TrimSpace(string(role)) == "" {
+	if len(roles) == 0 {
 		return user, ErrUserRoleRequired
 	}
-	if !isValidRole(role) {
-		return user, fmt.Errorf("invalid user role. Valid user roles are %s, %s and %s", rbac.Admin, rbac.Moderator, rbac.Regular)
+	for _, role := range roles {
+		if err := validateRole(role); err != nil {
+			return user, err
+		}
 	}
 
 	if reputation == nil {
@@ -73,19 +88,36 @@ func NewUser(
 		}
 	}
 
-	return User{id: id, email: email, joinedAt: joinedAt, updatedAt: updatedAt, username: username, role: role, reputation: reputation, banStatus: banStatus}, nil
+	return User{id: id, email: email, joinedAt: joinedAt, updatedAt: updatedAt, username: username, roles: uniqueRoles(roles), reputation: reputation, banStatus: banStatus}, nil
 }
 
 func MustNewUser(
-	id, email, username string, role rbac.UserRole, joinedAt time.Time, updatedAt time.Time,
+	id, email, username string, roles []rbac.UserRole, joinedAt time.Time, updatedAt time.Time,
 	reputation *userReputation, banStatus *ban) User {
-	user, err := NewUser(id, email, username, role, joinedAt, updatedAt, reputation, banStatus)
+	user, err := NewUser(id, email, username, roles, joinedAt, updatedAt, reputation, banStatus)
 	if err != nil {
 		panic(err.Error())
 	}
 	return user
 }
 
+// RegisterUser creates a new regular user who logs in with passwordHash and raises UserRegistered.
+// The email stays unverified until VerifyEmail.
+func RegisterUser(id, email, username, passwordHash string, registeredAt time.Time) (User, error) {
+	if err := ValidateEmail(email); err != nil {
+		return User{}, err
+	}
+	user, err := NewUser(id, email, username, []rbac.UserRole{rbac.Regular}, registeredAt, registeredAt, nil, nil)
+	if err != nil {
+		return user, err
+	}
+	if err := user.SetPasswordHash(passwordHash); err != nil {
+		return user, err
+	}
+	user.events.Record(UserRegistered{UserId: id, Email: email, Username: username, Registered: registeredAt})
+	return user, nil
+}
+
 func NewUserReputation(score int, badges []string) (*userReputation, error) {
 	if score < 0 {
 		return &userReputation{}, ErrInvalidRepScore
@@ -105,8 +137,10 @@ func (u *User) ChangeUsername(newUsername string) error {
 	if strings.TrimSpace(newUsername) == "" {
 		return ErrUsernameRequired
 	}
+	oldUsername := u.username
 	u.username = newUsername
 	u.updatedAt = time.Now()
+	u.events.Record(UsernameChanged{UserId: u.id, OldUsername: oldUsername, NewUsername: newUsername, ChangedAt: u.updatedAt})
 	return nil
 }
 
@@ -116,6 +150,7 @@ func (u *User) AwardBadge(badge string) error {
 	}
 	u.reputation.badges = append(u.reputation.badges, badge)
 	u.updatedAt = time.Now()
+	u.events.Record(BadgeAwarded{UserId: u.id, Badge: badge, AwardedAt: u.updatedAt})
 	return nil
 }
 
@@ -134,6 +169,7 @@ func (u *User) RevokeAwardedBadge(badge string) error {
 		return awardedBadge == badge
 	})
 	u.updatedAt = time.Now()
+	u.events.Record(BadgeRevoked{UserId: u.id, Badge: badge, RevokedAt: u.updatedAt})
 	return nil
 }
 
@@ -155,6 +191,11 @@ func (u *User) DecrementReputationScoreBy(v int) error {
 	return nil
 }
 
+// PullEvents returns the events raised since the user was loaded and clears them
+func (u *User) PullEvents() []events.Event {
+	return u.events.PullEvents()
+}
+
 func (u *User) Id() string {
 	return u.id
 }
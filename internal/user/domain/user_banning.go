@@ -12,6 +12,8 @@ var (
 	ErrUserAlreadyBanned   = errors.New("user is already banned")
 	ErrBanTimelineRequired = errors.New("you must pass correct ban timeline if user ban is not indefinitely")
 	ErrUserIsNotBanned     = errors.New("user you are trying to unban is not banned")
+	ErrInvalidBanTimeline  = errors.New("ban must end after it starts")
+	ErrBanNotExpired       = errors.New("ban has not expired yet")
 )
 
 type ban struct {
@@ -43,16 +45,33 @@ func NewBan(isBanned bool, reason string, isIndefinite bool, from, to, bannedAt
 	}
 }
 
+// IsBanActive tells whether a ban with the given state applies at t. A time-boxed ban applies from
+// its start date until its end date; an indefinite one until it is lifted.
+func IsBanActive(isBanned, isIndefinite bool, from, to, t time.Time) bool {
+	if !isBanned {
+		return false
+	}
+	if isIndefinite {
+		return true
+	}
+	return !t.Before(from) && t.Before(to)
+}
+
+// Ban bans the user, either indefinitely or for the given timeline. A timeline starting in the
+// future schedules the ban. A user whose previous ban has expired can be banned again.
 func (u *User) Ban(reason string, isIndefinite bool, timeline *BanTimeline) error {
 	if strings.TrimSpace(reason) == "" {
 		return ErrEmptyReason
 	}
-	if u.banStatus.isBanned {
+	if u.banStatus.isBanned && !u.IsBanExpired(time.Now()) {
 		return ErrUserAlreadyBanned
 	}
 	if !isIndefinite && timeline == nil {
 		return ErrBanTimelineRequired
 	}
+	if timeline != nil && !timeline.to.After(timeline.from) {
+		return ErrInvalidBanTimeline
+	}
 	u.banStatus.isBanned = true
 	u.banStatus.reason = reason
 	u.banStatus.isIndefinite = isIndefinite
@@ -62,6 +81,14 @@ func (u *User) Ban(reason string, isIndefinite bool, timeline *BanTimeline) erro
 		u.banStatus.to = timeline.to
 		u.banStatus.isIndefinite = false
 	}
+	u.events.Record(UserBanned{
+		UserId:       u.id,
+		Reason:       reason,
+		IsIndefinite: u.banStatus.isIndefinite,
+		From:         u.banStatus.from,
+		To:           u.banStatus.to,
+		BannedAt:     u.banStatus.bannedAt,
+	})
 
 	return nil
 }
@@ -76,11 +103,39 @@ func (u *User) UnBan() error {
 	u.banStatus.isIndefinite = false
 	u.banStatus.from = time.Time{}
 	u.banStatus.to = time.Time{}
+	u.events.Record(UserUnbanned{UserId: u.id, UnbannedAt: time.Now()})
 
 	return nil
 }
 
+// LiftExpiredBan unbans the user once a time-boxed ban is over
+func (u *User) LiftExpiredBan(now time.Time) error {
+	if !u.IsBanExpired(now) {
+		return ErrBanNotExpired
+	}
+	return u.UnBan()
+}
+
+// IsBanned tells whether the user is banned right now. Scheduled bans and bans that have
+// expired but were not lifted yet don't count.
 func (u *User) IsBanned() bool {
+	return u.IsBannedAt(time.Now())
+}
+
+func (u *User) IsBannedAt(t time.Time) bool {
+	b := u.banStatus
+	return IsBanActive(b.isBanned, b.isIndefinite, b.from, b.to, t)
+}
+
+// IsBanExpired tells whether the user still carries a time-boxed ban whose end date has passed
+func (u *User) IsBanExpired(now time.Time) bool {
+	b := u.banStatus
+	return b.isBanned && !b.isIndefinite && !now.Before(b.to)
+}
+
+// HasBan tells whether a ban is recorded on the user, whatever its timeline. Repositories persist
+// this rather than IsBanned.
+func (u *User) HasBan() bool {
 	return u.banStatus.isBanned
 }
 
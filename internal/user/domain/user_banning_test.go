@@ -103,6 +103,88 @@ func TestUnBan(t *testing.T) {
 
 }
 
+func TestBanTimeline(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should reject a timeline that ends before it starts", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		err := user.Ban("abuse", false, domain.NewBanTimeline(time.Now(), time.Now().Add(-time.Hour)))
+		assert.Equal(t, domain.ErrInvalidBanTimeline, err)
+	})
+
+	t.Run("should not take effect before the ban starts", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		from := time.Now().Add(time.Hour)
+		assert.Nil(t, user.Ban("abuse", false, domain.NewBanTimeline(from, from.Add(time.Hour))))
+
+		assert.False(t, user.IsBanned())
+		assert.True(t, user.HasBan())
+		assert.True(t, user.IsBannedAt(from))
+	})
+
+	t.Run("should read as not banned once the ban ends", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		from := time.Now()
+		to := from.Add(time.Hour)
+		assert.Nil(t, user.Ban("abuse", false, domain.NewBanTimeline(from, to)))
+
+		assert.True(t, user.IsBannedAt(to.Add(-time.Second)))
+		assert.False(t, user.IsBannedAt(to))
+		assert.True(t, user.IsBanExpired(to))
+		assert.False(t, user.IsBanExpired(to.Add(-time.Second)))
+	})
+
+	t.Run("should never expire an indefinite ban", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		mustBan(&user)
+		assert.True(t, user.IsBannedAt(time.Now().AddDate(100, 0, 0)))
+		assert.False(t, user.IsBanExpired(time.Now().AddDate(100, 0, 0)))
+	})
+
+	t.Run("should allow banning again after an expired ban", func(t *testing.T) {
+		t.Parallel()
+		user := domain.MustNewUser("user-id", "example@gmail.com", "john-doe", []rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil,
+			domain.NewBan(true, "spam", false, time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour), time.Now().Add(-2*time.Hour)))
+		assert.Nil(t, user.Ban("abuse", true, nil))
+		assert.True(t, user.IsBanned())
+	})
+}
+
+func TestLiftExpiredBan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should lift a ban that is over and raise UserUnbanned", func(t *testing.T) {
+		t.Parallel()
+		user := domain.MustNewUser("user-id", "example@gmail.com", "john-doe", []rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil,
+			domain.NewBan(true, "spam", false, time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour), time.Now().Add(-2*time.Hour)))
+
+		assert.Nil(t, user.LiftExpiredBan(time.Now()))
+		assert.False(t, user.HasBan())
+		raised := user.PullEvents()
+		assert.Len(t, raised, 1)
+		assert.IsType(t, domain.UserUnbanned{}, raised[0])
+	})
+
+	t.Run("should not lift a ban that is still running", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		assert.Nil(t, user.Ban("abuse", false, domain.NewBanTimeline(time.Now(), time.Now().Add(time.Hour))))
+		assert.Equal(t, domain.ErrBanNotExpired, user.LiftExpiredBan(time.Now()))
+		assert.True(t, user.IsBanned())
+	})
+
+	t.Run("should not lift an indefinite ban", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		mustBan(&user)
+		assert.Equal(t, domain.ErrBanNotExpired, user.LiftExpiredBan(time.Now()))
+	})
+}
+
 func mustBan(user *domain.User) {
 	err := user.Ban("abuse", true, nil)
 	if err != nil {
@@ -112,7 +194,7 @@ func mustBan(user *domain.User) {
 
 func createUser() domain.User {
 	return domain.MustNewUser("user-id", "example@gmail.com",
-		"john-doe", rbac.Regular,
+		"john-doe", []rbac.UserRole{rbac.Regular},
 		time.Now(),
 		time.Now(),
 		nil, nil)
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrDeletionAlreadyRequested = errors.New("account deletion is already requested")
+	ErrDeletionNotRequested     = errors.New("account deletion is not requested")
+	ErrDeletionNotDue           = errors.New("account deletion is not due yet")
+	ErrDeletionNoLongerUndoable = errors.New("account deletion can no longer be cancelled")
+	ErrInvalidGracePeriod       = errors.New("grace period before deleting an account cannot be negative")
+)
+
+// DeletedUserId stands in for deleted users. The posts, comments and reports of a deleted user
+// are handed over to it, so they stay in place without pointing at anyone.
+const DeletedUserId = "deleted-user"
+
+type deletion struct {
+	requestedAt time.Time
+	dueAt       time.Time
+}
+
+// RequestDeletion schedules the account to be deleted once gracePeriod has passed and raises
+// UserDeletionRequested. Until then the deletion can be cancelled.
+func (u *User) RequestDeletion(at time.Time, gracePeriod time.Duration) error {
+	if gracePeriod < 0 {
+		return ErrInvalidGracePeriod
+	}
+	if u.IsDeletionRequested() {
+		return ErrDeletionAlreadyRequested
+	}
+	u.deletion = &deletion{requestedAt: at, dueAt: at.Add(gracePeriod)}
+	u.updatedAt = at
+	u.events.Record(UserDeletionRequested{UserId: u.id, DueAt: u.deletion.dueAt, RequestedAt: at})
+	return nil
+}
+
+// CancelDeletion keeps an account whose deletion was requested and raises UserDeletionCancelled.
+// The deletion can only be cancelled during the grace period.
+func (u *User) CancelDeletion(at time.Time) error {
+	if !u.IsDeletionRequested() {
+		return ErrDeletionNotRequested
+	}
+	if !at.Before(u.deletion.dueAt) {
+		return ErrDeletionNoLongerUndoable
+	}
+	u.deletion = nil
+	u.updatedAt = at
+	u.events.Record(UserDeletionCancelled{UserId: u.id, CancelledAt: at})
+	return nil
+}
+
+// Erase raises UserDeleted for a user whose grace period is over. The repository deletes the
+// user along with their personal data.
+func (u *User) Erase(at time.Time) error {
+	if !u.IsDeletionRequested() {
+		return ErrDeletionNotRequested
+	}
+	if at.Before(u.deletion.dueAt) {
+		return ErrDeletionNotDue
+	}
+	u.events.Record(UserDeleted{UserId: u.id, DeletedAt: at})
+	return nil
+}
+
+// SetDeletion restores the pending deletion of a stored user
+func (u *User) SetDeletion(requestedAt, dueAt time.Time) {
+	u.deletion = &deletion{requestedAt: requestedAt, dueAt: dueAt}
+}
+
+func (u *User) IsDeletionRequested() bool {
+	return u.deletion != nil
+}
+
+// DeletionRequestedAt is zero unless the deletion of the account is requested
+func (u *User) DeletionRequestedAt() time.Time {
+	if u.deletion == nil {
+		return time.Time{}
+	}
+	return u.deletion.requestedAt
+}
+
+// DeletionDueAt is when the account gets deleted, zero unless the deletion is requested
+func (u *User) DeletionDueAt() time.Time {
+	if u.deletion == nil {
+		return time.Time{}
+	}
+	return u.deletion.dueAt
+}
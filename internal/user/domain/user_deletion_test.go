@@ -0,0 +1,98 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/events"
+	"github.com/iammrsea/social-app/internal/user/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountDeletion(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	grace := 30 * 24 * time.Hour
+
+	t.Run("should schedule the deletion after the grace period", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		require.NoError(t, user.RequestDeletion(now, grace))
+		assert.True(t, user.IsDeletionRequested())
+		assert.Equal(t, now, user.DeletionRequestedAt())
+		assert.Equal(t, now.Add(grace), user.DeletionDueAt())
+		assert.Equal(t, []events.Event{domain.UserDeletionRequested{
+			UserId:      user.Id(),
+			DueAt:       now.Add(grace),
+			RequestedAt: now,
+		}}, user.PullEvents())
+	})
+
+	t.Run("should not request the deletion twice", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		require.NoError(t, user.RequestDeletion(now, grace))
+		assert.ErrorIs(t, user.RequestDeletion(now.Add(time.Hour), grace), domain.ErrDeletionAlreadyRequested)
+		assert.Equal(t, now.Add(grace), user.DeletionDueAt())
+	})
+
+	t.Run("should reject a negative grace period", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		assert.ErrorIs(t, user.RequestDeletion(now, -time.Hour), domain.ErrInvalidGracePeriod)
+		assert.False(t, user.IsDeletionRequested())
+	})
+
+	t.Run("should cancel a requested deletion", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		require.NoError(t, user.RequestDeletion(now, grace))
+		require.NoError(t, user.CancelDeletion(now.Add(time.Hour)))
+		assert.False(t, user.IsDeletionRequested())
+		assert.True(t, user.DeletionDueAt().IsZero())
+		raised := user.PullEvents()
+		require.Len(t, raised, 2)
+		assert.Equal(t, domain.UserDeletionCancelled{UserId: user.Id(), CancelledAt: now.Add(time.Hour)}, raised[1])
+	})
+
+	t.Run("should not cancel the deletion once the grace period is over", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		require.NoError(t, user.RequestDeletion(now, grace))
+		assert.ErrorIs(t, user.CancelDeletion(now.Add(grace)), domain.ErrDeletionNoLongerUndoable)
+		assert.True(t, user.IsDeletionRequested())
+	})
+
+	t.Run("should not cancel a deletion that wasn't requested", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		assert.ErrorIs(t, user.CancelDeletion(now), domain.ErrDeletionNotRequested)
+	})
+
+	t.Run("should erase the user once the grace period is over", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		require.NoError(t, user.RequestDeletion(now, grace))
+		user.PullEvents()
+		assert.ErrorIs(t, user.Erase(now.Add(grace-time.Second)), domain.ErrDeletionNotDue)
+		require.NoError(t, user.Erase(now.Add(grace)))
+		assert.Equal(t, []events.Event{domain.UserDeleted{UserId: user.Id(), DeletedAt: now.Add(grace)}}, user.PullEvents())
+	})
+
+	t.Run("should not erase a user who didn't ask for it", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		assert.ErrorIs(t, user.Erase(now), domain.ErrDeletionNotRequested)
+		assert.Empty(t, user.PullEvents())
+	})
+
+	t.Run("should restore the pending deletion of a stored user", func(t *testing.T) {
+		t.Parallel()
+		user := createUser()
+		user.SetDeletion(now, now.Add(grace))
+		assert.True(t, user.IsDeletionRequested())
+		assert.Equal(t, now.Add(grace), user.DeletionDueAt())
+		assert.Empty(t, user.PullEvents())
+	})
+}
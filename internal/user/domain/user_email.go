@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"net/mail"
+	"time"
+)
+
+var (
+	ErrInvalidEmail         = errors.New("invalid email address")
+	ErrEmailAlreadyVerified = errors.New("email is already verified")
+)
+
+// ValidateEmail accepts a bare address such as johndoe@example.com, without a display name
+func ValidateEmail(email string) error {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return ErrInvalidEmail
+	}
+	return nil
+}
+
+// VerifyEmail marks the email of the user as verified and raises UserEmailVerified
+func (u *User) VerifyEmail(at time.Time) error {
+	if u.IsEmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+	u.emailVerifiedAt = at
+	u.events.Record(UserEmailVerified{UserId: u.id, Email: u.email, VerifiedAt: at})
+	return nil
+}
+
+// SetEmailVerifiedAt restores when the email of a stored user was verified
+func (u *User) SetEmailVerifiedAt(at time.Time) {
+	u.emailVerifiedAt = at
+}
+
+// EmailVerifiedAt is zero for users who haven't verified their email
+func (u *User) EmailVerifiedAt() time.Time {
+	return u.emailVerifiedAt
+}
+
+func (u *User) IsEmailVerified() bool {
+	return !u.emailVerifiedAt.IsZero()
+}
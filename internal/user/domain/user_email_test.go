@@ -0,0 +1,61 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/user/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEmail(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name    string
+		email   string
+		wantErr error
+	}{
+		{name: "should accept a bare address", email: "johndoe@example.com"},
+		{name: "should reject an address without a domain", email: "johndoe", wantErr: domain.ErrInvalidEmail},
+		{name: "should reject a display name", email: "John Doe <johndoe@example.com>", wantErr: domain.ErrInvalidEmail},
+		{name: "should reject surrounding spaces", email: " johndoe@example.com ", wantErr: domain.ErrInvalidEmail},
+		{name: "should reject an empty address", email: "", wantErr: domain.ErrInvalidEmail},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.wantErr, domain.ValidateEmail(tc.email))
+		})
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should register users with an unverified email", func(t *testing.T) {
+		t.Parallel()
+		user, err := domain.RegisterUser("user-1", "johndoe@example.com", "johndoe", "password-hash", time.Now())
+		require.NoError(t, err)
+		assert.False(t, user.IsEmailVerified())
+
+		_, err = domain.RegisterUser("user-2", "not-an-email", "janedoe", "password-hash", time.Now())
+		assert.ErrorIs(t, err, domain.ErrInvalidEmail)
+	})
+
+	t.Run("should verify the email once", func(t *testing.T) {
+		t.Parallel()
+		user, err := domain.RegisterUser("user-1", "johndoe@example.com", "johndoe", "password-hash", time.Now())
+		require.NoError(t, err)
+		user.PullEvents()
+		verifiedAt := time.Now()
+
+		require.NoError(t, user.VerifyEmail(verifiedAt))
+		assert.True(t, user.IsEmailVerified())
+		assert.Equal(t, verifiedAt, user.EmailVerifiedAt())
+		assert.Len(t, user.PullEvents(), 1)
+
+		assert.ErrorIs(t, user.VerifyEmail(time.Now()), domain.ErrEmailAlreadyVerified)
+		assert.Empty(t, user.PullEvents())
+	})
+}
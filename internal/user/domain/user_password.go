@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	MinPasswordLength = 8
+	// MaxPasswordLength is in bytes, the most bcrypt hashes
+	MaxPasswordLength = 72
+)
+
+var (
+	ErrPasswordTooShort     = fmt.Errorf("password must be at least %d characters long", MinPasswordLength)
+	ErrPasswordTooLong      = fmt.Errorf("password cannot be longer than %d bytes", MaxPasswordLength)
+	ErrPasswordHashRequired = errors.New("password hash cannot be empty")
+)
+
+// ValidatePassword checks a plain text password before it is hashed
+func ValidatePassword(password string) error {
+	if len([]rune(strings.TrimSpace(password))) < MinPasswordLength {
+		return ErrPasswordTooShort
+	}
+	if len(password) > MaxPasswordLength {
+		return ErrPasswordTooLong
+	}
+	return nil
+}
+
+// SetPasswordHash sets the hash of the password the user logs in with. The user never sees the
+// plain text password; hashing is done before it reaches the domain.
+func (u *User) SetPasswordHash(hash string) error {
+	if strings.TrimSpace(hash) == "" {
+		return ErrPasswordHashRequired
+	}
+	u.passwordHash = hash
+	return nil
+}
+
+func (u *User) PasswordHash() string {
+	return u.passwordHash
+}
+
+// HasPassword is false for users who registered before passwords were required
+func (u *User) HasPassword() bool {
+	return u.passwordHash != ""
+}
+
+// ResetPassword replaces the password of a user who proved they own the email, and raises
+// UserPasswordReset
+func (u *User) ResetPassword(hash string, at time.Time) error {
+	if err := u.SetPasswordHash(hash); err != nil {
+		return err
+	}
+	u.events.Record(UserPasswordReset{UserId: u.id, ResetAt: at})
+	return nil
+}
@@ -0,0 +1,49 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/user/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePassword(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		password string
+		wantErr  error
+	}{
+		{name: "should accept a long enough password", password: "s3cret-enough"},
+		{name: "should reject a short password", password: "short", wantErr: domain.ErrPasswordTooShort},
+		{name: "should not count surrounding spaces", password: "   short   ", wantErr: domain.ErrPasswordTooShort},
+		{name: "should reject passwords bcrypt cannot hash", password: strings.Repeat("a", domain.MaxPasswordLength+1), wantErr: domain.ErrPasswordTooLong},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.wantErr, domain.ValidatePassword(tc.password))
+		})
+	}
+}
+
+func TestRegisterUserWithPassword(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should keep the password hash of a registered user", func(t *testing.T) {
+		t.Parallel()
+		user, err := domain.RegisterUser("user-1", "johndoe@example.com", "johndoe", "password-hash", time.Now())
+		require.NoError(t, err)
+		assert.True(t, user.HasPassword())
+		assert.Equal(t, "password-hash", user.PasswordHash())
+	})
+
+	t.Run("should not register a user without a password hash", func(t *testing.T) {
+		t.Parallel()
+		_, err := domain.RegisterUser("user-1", "johndoe@example.com", "johndoe", " ", time.Now())
+		assert.Equal(t, domain.ErrPasswordHashRequired, err)
+	})
+}
@@ -7,14 +7,18 @@ import (
 )
 
 type UserReadModel struct {
-	Username   string         `json:"username"`
-	Email      string         `json:"email"`
-	Role       rbac.UserRole  `json:"role"`
-	Id         string         `json:"id"`
-	Reputation UserReputation `json:"reputation"`
-	CreatedAt  time.Time      `json:"createtAt"`
-	UpdatedAt  time.Time      `json:"createdAt"`
-	BanStatus  BanStatus      `json:"banStatus"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	// EmailVerified is false until the user proves the email is theirs
+	EmailVerified bool `json:"emailVerified"`
+	// DeletionDueAt is set while the account waits to be deleted, the deletion can be cancelled until then
+	DeletionDueAt *time.Time      `json:"deletionDueAt,omitempty"`
+	Roles         []rbac.UserRole `json:"roles"`
+	Id            string          `json:"id"`
+	Reputation    UserReputation  `json:"reputation"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	UpdatedAt     time.Time       `json:"updatedAt"`
+	BanStatus     BanStatus       `json:"banStatus"`
 }
 
 type UserReputation struct {
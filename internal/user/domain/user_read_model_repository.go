@@ -1,6 +1,12 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidPageSize is returned when users are listed without asking for at least one
+var ErrInvalidPageSize = errors.New("the number of users to list must be at least one")
 
 type GetUsersOptions struct {
 	First         int32
@@ -2,16 +2,28 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 type UserRepository interface {
 	Register(ctx context.Context, user User) error
-	MakeModerator(ctx context.Context, userId string, updateFn func(user *User) error) error
+	// UpdateRoles saves the roles assigned to or removed from the user by updateFn
+	UpdateRoles(ctx context.Context, userId string, updateFn func(user *User) error) error
 	AwardBadge(ctx context.Context, userId string, updateFn func(user *User) error) error
 	RevokeAwardedBadge(ctx context.Context, userId string, updateFn func(user *User) error) error
 	ChangeUsername(ctx context.Context, userId string, updateFn func(user *User) error) error
 	UnbanUser(ctx context.Context, userId string, updateFn func(user *User) error) error
 	BanUser(ctx context.Context, userId string, updateFn func(user *User) error) error
+	VerifyEmail(ctx context.Context, userId string, updateFn func(user *User) error) error
+	ResetPassword(ctx context.Context, userId string, updateFn func(user *User) error) error
 	GetUserBy(ctx context.Context, fieldName string, value any) (*User, error)
 	UserExists(ctx context.Context, email string, username string) (bool, error)
+	// ExpiredBans returns the ids of up to limit users whose time-boxed ban ended at or before now
+	ExpiredBans(ctx context.Context, now time.Time, limit int) ([]string, error)
+	// ScheduleDeletion saves the deletion of the account requested or cancelled by updateFn
+	ScheduleDeletion(ctx context.Context, userId string, updateFn func(user *User) error) error
+	// DeleteUser removes the user and their personal data once updateFn erased them
+	DeleteUser(ctx context.Context, userId string, updateFn func(user *User) error) error
+	// DueDeletions returns the ids of up to limit users whose account deletion was due at or before now
+	DueDeletions(ctx context.Context, now time.Time, limit int) ([]string, error)
 }
@@ -3,45 +3,75 @@ package domain
 import (
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
 )
 
 func (u *User) IsModerator() bool {
-	return u.role == rbac.Moderator
+	return u.HasRole(rbac.Moderator)
 }
 
 func (u *User) IsAdmin() bool {
-	return u.role == rbac.Admin
+	return u.HasRole(rbac.Admin)
 }
 
-func (u *User) MakeModerator() error {
-	if u.role == rbac.Moderator {
-		return fmt.Errorf("the user %s is already a moderator", u.username)
-	}
-	u.role = rbac.Moderator
-	return nil
+func (u *User) IsRegular() bool {
+	return u.HasRole(rbac.Regular)
 }
 
-func (u *User) IsRegular() bool {
-	return u.role == rbac.Regular
+// HasRole tells whether role is one of the user's roles
+func (u *User) HasRole(role rbac.UserRole) bool {
+	return slices.Contains(u.roles, role)
 }
 
-func (u *User) Role() rbac.UserRole {
-	return u.role
+// Roles are the roles of the user. The user has the permissions of all of them.
+func (u *User) Roles() []rbac.UserRole {
+	return slices.Clone(u.roles)
 }
 
-func (u *User) MakeRegular() error {
-	if u.role == rbac.Regular {
-		return fmt.Errorf("the user %s is already a regular", u.username)
+// AssignRole gives the user role on top of the roles they have. Whether the access policy defines
+// the role is for the caller to check.
+func (u *User) AssignRole(role rbac.UserRole) error {
+	if err := validateRole(role); err != nil {
+		return err
 	}
-	u.role = rbac.Regular
+	if u.HasRole(role) {
+		return fmt.Errorf("%w: the user %s is already %s", ErrRoleAlreadyAssigned, u.username, role)
+	}
+	u.roles = append(u.roles, role)
+	u.events.Record(UserRoleAssigned{UserId: u.id, Role: role, AssignedAt: time.Now()})
+	return nil
+}
 
+// RemoveRole takes role away from the user, who must keep at least one role
+func (u *User) RemoveRole(role rbac.UserRole) error {
+	if !u.HasRole(role) {
+		return fmt.Errorf("%w: the user %s is not %s", ErrRoleNotAssigned, u.username, role)
+	}
+	if len(u.roles) == 1 {
+		return ErrLastRole
+	}
+	u.roles = slices.DeleteFunc(u.roles, func(r rbac.UserRole) bool { return r == role })
+	u.events.Record(UserRoleRemoved{UserId: u.id, Role: role, RemovedAt: time.Now()})
 	return nil
 }
 
-func isValidRole(role rbac.UserRole) bool {
-	validRoles := []rbac.UserRole{rbac.Moderator, rbac.Admin, rbac.Regular}
+// validateRole accepts any well-formed role name but the guest role, which is for visitors who
+// haven't logged in
+func validateRole(role rbac.UserRole) error {
+	if !role.IsValid() || role == rbac.Guest {
+		return fmt.Errorf("%w: %q", ErrInvalidRole, role)
+	}
+	return nil
+}
 
-	return slices.Contains(validRoles, role)
+func uniqueRoles(roles []rbac.UserRole) []rbac.UserRole {
+	unique := make([]rbac.UserRole, 0, len(roles))
+	for _, role := range roles {
+		if !slices.Contains(unique, role) {
+			unique = append(unique, role)
+		}
+	}
+	return unique
 }
@@ -7,70 +7,111 @@ import (
 	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
 	"github.com/iammrsea/social-app/internal/user/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestMakeModerator(t *testing.T) {
+func TestAssignRole(t *testing.T) {
 	t.Parallel()
 
-	t.Run("should return error if user is already a moderator", func(t *testing.T) {
+	t.Run("should return error if user already has the role", func(t *testing.T) {
 		t.Parallel()
 		user := domain.MustNewUser("user-id", "johndoe@gmail.com",
-			"johndoe", rbac.Moderator,
+			"johndoe", []rbac.UserRole{rbac.Moderator},
 			time.Now(),
 			time.Now(),
 			nil,
 			nil)
-		err := user.MakeModerator()
-		assert.NotNil(t, err)
+		err := user.AssignRole(rbac.Moderator)
+		assert.ErrorIs(t, err, domain.ErrRoleAlreadyAssigned)
 	})
 
-	t.Run("should correctly change user role to moderator", func(t *testing.T) {
+	t.Run("should reject guest and invalid role names", func(t *testing.T) {
+		t.Parallel()
+		user := domain.MustNewUser("user-id", "johndoe@gmail.com",
+			"johndoe", []rbac.UserRole{rbac.Regular},
+			time.Now(),
+			time.Now(),
+			nil,
+			nil)
+		assert.ErrorIs(t, user.AssignRole(rbac.Guest), domain.ErrInvalidRole)
+		assert.ErrorIs(t, user.AssignRole("not a role"), domain.ErrInvalidRole)
+		assert.Equal(t, []rbac.UserRole{rbac.Regular}, user.Roles())
+	})
+
+	t.Run("should add the role to the roles of the user", func(t *testing.T) {
 		t.Parallel()
 		user := domain.MustNewUser(
 			"user-id",
 			"johndoe@gmail.com",
 			"johndoe",
-			rbac.Regular,
+			[]rbac.UserRole{rbac.Regular},
 			time.Now(),
 			time.Now(),
 			nil,
 			nil)
-		err := user.MakeModerator()
+		err := user.AssignRole("SUPPORT")
 		assert.Nil(t, err)
-		assert.Equal(t, user.Role(), rbac.Moderator)
-		assert.True(t, user.IsModerator())
+		assert.Equal(t, []rbac.UserRole{rbac.Regular, "SUPPORT"}, user.Roles())
+		assert.True(t, user.HasRole("SUPPORT"))
+		assert.True(t, user.IsRegular())
+
+		raised := user.PullEvents()
+		require.Len(t, raised, 1)
+		assigned := raised[0].(domain.UserRoleAssigned)
+		assert.Equal(t, "user-id", assigned.UserId)
+		assert.Equal(t, rbac.UserRole("SUPPORT"), assigned.Role)
 	})
 }
 
-func TestMakeRegular(t *testing.T) {
+func TestRemoveRole(t *testing.T) {
 	t.Parallel()
 
-	t.Run("should return error if user is already a regular", func(t *testing.T) {
+	t.Run("should return error if user doesn't have the role", func(t *testing.T) {
 		t.Parallel()
 		user := domain.MustNewUser(
 			"user-id",
 			"johndoe@gmail.com",
 			"johndoe",
-			rbac.Regular,
+			[]rbac.UserRole{rbac.Regular, rbac.Moderator},
 			time.Now(),
 			time.Now(),
 			nil,
 			nil)
-		err := user.MakeRegular()
-		assert.NotNil(t, err)
+		err := user.RemoveRole(rbac.Admin)
+		assert.ErrorIs(t, err, domain.ErrRoleNotAssigned)
 	})
 
-	t.Run("should correctly change user role to regular", func(t *testing.T) {
+	t.Run("should return error when removing the last role", func(t *testing.T) {
+		t.Parallel()
+		user := domain.MustNewUser(
+			"user-id",
+			"johndoe@gmail.com",
+			"johndoe",
+			[]rbac.UserRole{rbac.Moderator},
+			time.Now(),
+			time.Now(),
+			nil,
+			nil)
+		err := user.RemoveRole(rbac.Moderator)
+		assert.ErrorIs(t, err, domain.ErrLastRole)
+		assert.True(t, user.IsModerator())
+	})
+
+	t.Run("should take the role away from the user", func(t *testing.T) {
 		t.Parallel()
 		user := domain.MustNewUser(
 			"user-id", "johndoe@gmail.com",
-			"johndoe", rbac.Moderator,
+			"johndoe", []rbac.UserRole{rbac.Regular, rbac.Moderator},
 			time.Now(), time.Now(),
 			nil,
 			nil)
-		err := user.MakeRegular()
+		err := user.RemoveRole(rbac.Moderator)
 		assert.Nil(t, err)
-		assert.Equal(t, user.Role(), rbac.Regular)
-		assert.True(t, user.IsRegular())
+		assert.Equal(t, []rbac.UserRole{rbac.Regular}, user.Roles())
+		assert.False(t, user.IsModerator())
+
+		raised := user.PullEvents()
+		require.Len(t, raised, 1)
+		assert.Equal(t, rbac.Moderator, raised[0].(domain.UserRoleRemoved).Role)
 	})
 }
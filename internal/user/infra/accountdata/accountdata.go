@@ -0,0 +1,101 @@
+package accountdata
+
+import (
+	"context"
+	"time"
+
+	contentDomain "github.com/iammrsea/social-app/internal/content/domain"
+	interactionDomain "github.com/iammrsea/social-app/internal/interaction/domain"
+	moderationDomain "github.com/iammrsea/social-app/internal/moderation/domain"
+	"github.com/iammrsea/social-app/internal/user/app/query"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+const pageSize = 100
+
+// Content is where the posts, comments, votes and reports of users are kept
+type Content struct {
+	Posts          contentDomain.PostRepository
+	PostReadModels contentDomain.PostReadModelRepository
+	Comments       contentDomain.CommentRepository
+	Votes          interactionDomain.VoteRepository
+	VoteReadModels interactionDomain.VoteReadModelRepository
+	Reports        moderationDomain.ReportRepository
+	// ReportReadModels lists the reports a user filed
+	ReportReadModels moderationDomain.ReportReadModelRepository
+}
+
+// AccountData anonymizes and exports what the content, interaction and moderation parts of the
+// app keep about a user
+type AccountData struct {
+	content Content
+}
+
+func New(content Content) *AccountData {
+	if content.Posts == nil || content.PostReadModels == nil || content.Comments == nil || content.Votes == nil ||
+		content.VoteReadModels == nil || content.Reports == nil || content.ReportReadModels == nil {
+		panic("nil content repository")
+	}
+	return &AccountData{content: content}
+}
+
+// EraseAccountData hands the posts, comments and reports of the user over to domain.DeletedUserId
+// and retracts their votes, which can't be handed over as a user votes at most once on a post
+func (a *AccountData) EraseAccountData(ctx context.Context, userId string) error {
+	if err := a.content.Posts.ReassignAuthor(ctx, userId, domain.DeletedUserId); err != nil {
+		return err
+	}
+	if err := a.content.Comments.ReassignAuthor(ctx, userId, domain.DeletedUserId); err != nil {
+		return err
+	}
+	if err := a.content.Reports.ReassignUser(ctx, userId, domain.DeletedUserId); err != nil {
+		return err
+	}
+	return a.content.Votes.RetractAllVotes(ctx, userId)
+}
+
+// CollectAccountData lists every post, vote and report of the user, oldest first
+func (a *AccountData) CollectAccountData(ctx context.Context, userId string) (*query.AccountActivity, error) {
+	posts, err := collect(func(after string) ([]*contentDomain.PostReadModel, bool, error) {
+		return a.content.PostReadModels.GetPosts(ctx, contentDomain.GetPostsOptions{
+			First: pageSize, After: after, SortDirection: "ASC", AuthorId: userId, IncludeDrafts: true,
+		})
+	}, func(post *contentDomain.PostReadModel) time.Time { return post.CreatedAt })
+	if err != nil {
+		return nil, err
+	}
+	votes, err := collect(func(after string) ([]*interactionDomain.VoteReadModel, bool, error) {
+		return a.content.VoteReadModels.GetVotes(ctx, interactionDomain.GetVotesOptions{
+			First: pageSize, After: after, SortDirection: "ASC", UserId: userId,
+		})
+	}, func(vote *interactionDomain.VoteReadModel) time.Time { return vote.CreatedAt })
+	if err != nil {
+		return nil, err
+	}
+	reports, err := collect(func(after string) ([]*moderationDomain.ReportReadModel, bool, error) {
+		return a.content.ReportReadModels.GetReports(ctx, moderationDomain.GetReportsOptions{
+			First: pageSize, After: after, SortDirection: "ASC", ReporterId: userId,
+		})
+	}, func(report *moderationDomain.ReportReadModel) time.Time { return report.CreatedAt })
+	if err != nil {
+		return nil, err
+	}
+	return &query.AccountActivity{Posts: posts, Votes: votes, Reports: reports}, nil
+}
+
+// collect reads every page of a listing paginated by creation time
+func collect[T any](page func(after string) ([]T, bool, error), createdAt func(T) time.Time) ([]T, error) {
+	all := []T{}
+	after := ""
+	for {
+		items, hasNext, err := page(after)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if !hasNext || len(items) == 0 {
+			return all, nil
+		}
+		after = createdAt(items[len(items)-1]).Format(time.RFC3339Nano)
+	}
+}
@@ -0,0 +1,71 @@
+package accountdata_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	contentDomain "github.com/iammrsea/social-app/internal/content/domain"
+	contentMemory "github.com/iammrsea/social-app/internal/content/infra/db/memory"
+	interactionDomain "github.com/iammrsea/social-app/internal/interaction/domain"
+	interactionMemory "github.com/iammrsea/social-app/internal/interaction/infra/db/memory"
+	moderationDomain "github.com/iammrsea/social-app/internal/moderation/domain"
+	moderationMemory "github.com/iammrsea/social-app/internal/moderation/infra/db/memory"
+	"github.com/iammrsea/social-app/internal/user/domain"
+	"github.com/iammrsea/social-app/internal/user/infra/accountdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountData(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	now := time.Now()
+
+	posts := contentMemory.NewPostRepository()
+	comments := contentMemory.NewCommentRepository()
+	votes := interactionMemory.NewVoteRepository()
+	reports := moderationMemory.NewReportRepository()
+	accountData := accountdata.New(accountdata.Content{
+		Posts: posts, PostReadModels: posts, Comments: comments,
+		Votes: votes, VoteReadModels: votes,
+		Reports: reports, ReportReadModels: reports,
+	})
+
+	require.NoError(t, posts.CreatePost(ctx, contentDomain.MustNewPost("post-1", "user-1", "title", "body", contentDomain.Published, now, now)))
+	require.NoError(t, posts.CreatePost(ctx, contentDomain.MustNewPost("post-2", "user-1", "title", "body", contentDomain.Draft, now.Add(time.Minute), now)))
+	require.NoError(t, comments.CreateComment(ctx, contentDomain.MustNewComment("c1", "post-1", "user-1", "", "c1", 0, "first", false, now, now)))
+	require.NoError(t, votes.CastVote(ctx, "user-1", "post-1", func(vote *interactionDomain.Vote) (*interactionDomain.Vote, error) {
+		newVote := interactionDomain.MustNewVote("user-1", "post-1", interactionDomain.Upvote, now, now)
+		return &newVote, nil
+	}))
+	require.NoError(t, reports.CreateReport(ctx, moderationDomain.MustNewReport("report-1", "user-1", moderationDomain.PostTarget, "post-3", "user-2", moderationDomain.Spam, "", now, now, nil)))
+
+	activity, err := accountData.CollectAccountData(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Len(t, activity.Posts, 2, "drafts are exported too")
+	assert.Len(t, activity.Votes, 1)
+	assert.Len(t, activity.Reports, 1)
+
+	require.NoError(t, accountData.EraseAccountData(ctx, "user-1"))
+
+	post, err := posts.GetPostById(ctx, "post-1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeletedUserId, post.AuthorId)
+	comment, err := comments.GetCommentById(ctx, "c1")
+	require.NoError(t, err)
+	require.NotNil(t, comment.AuthorId)
+	assert.Equal(t, domain.DeletedUserId, *comment.AuthorId)
+	report, err := reports.GetReportById(ctx, "report-1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeletedUserId, report.ReporterId)
+	score, err := votes.GetPostScore(ctx, "post-1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), score.Upvotes)
+
+	activity, err = accountData.CollectAccountData(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Empty(t, activity.Posts)
+	assert.Empty(t, activity.Votes)
+	assert.Empty(t, activity.Reports)
+}
@@ -0,0 +1,93 @@
+package accountmail
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth/onetime"
+	"github.com/iammrsea/social-app/internal/shared/config"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/mail"
+	"github.com/iammrsea/social-app/internal/user/app/command"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+// Paths of the links in the emails, relative to the app URL
+const (
+	VerifyEmailPath   = "/auth/verify-email"
+	ResetPasswordPath = "/reset-password"
+)
+
+// Mailer emails users the single-use links that verify their email and reset their password.
+// It implements command.EmailVerifier and command.PasswordResetter.
+type Mailer struct {
+	tokens          *onetime.Tokens
+	mailer          mail.Mailer
+	appURL          string
+	verificationTTL time.Duration
+	resetTTL        time.Duration
+}
+
+func New(tokens *onetime.Tokens, mailer mail.Mailer, appURL string, verificationTTL, resetTTL time.Duration) *Mailer {
+	if tokens == nil || mailer == nil {
+		panic("nil single-use tokens or mailer")
+	}
+	return &Mailer{tokens: tokens, mailer: mailer, appURL: appURL, verificationTTL: verificationTTL, resetTTL: resetTTL}
+}
+
+// NewFromEnv links to APP_URL, with links that expire after EMAIL_VERIFICATION_TTL and PASSWORD_RESET_TTL
+func NewFromEnv(tokens *onetime.Tokens, mailer mail.Mailer) *Mailer {
+	env := config.NewEnv()
+	return New(tokens, mailer, env.AppURL(), env.EmailVerificationTTL(), env.PasswordResetTTL())
+}
+
+func (m *Mailer) SendVerificationEmail(ctx context.Context, userId, email string) error {
+	link, err := m.link(onetime.VerifyEmail, VerifyEmailPath, userId, email, m.verificationTTL)
+	if err != nil {
+		return err
+	}
+	return m.mailer.Send(ctx, mail.Message{
+		To:      email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Follow this link to verify your email:\n\n%s\n\nThe link expires in %s. "+
+			"If you didn't create an account, ignore this email.", link, m.verificationTTL),
+	})
+}
+
+func (m *Mailer) RedeemVerificationToken(ctx context.Context, token string) (onetime.Claims, error) {
+	return m.tokens.Use(ctx, onetime.VerifyEmail, token)
+}
+
+func (m *Mailer) SendPasswordResetEmail(ctx context.Context, userId, email string) error {
+	link, err := m.link(onetime.ResetPassword, ResetPasswordPath, userId, email, m.resetTTL)
+	if err != nil {
+		return err
+	}
+	return m.mailer.Send(ctx, mail.Message{
+		To:      email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Follow this link to choose a new password:\n\n%s\n\nThe link expires in %s. "+
+			"If you didn't ask to reset your password, ignore this email.", link, m.resetTTL),
+	})
+}
+
+func (m *Mailer) RedeemPasswordResetToken(ctx context.Context, token string) (onetime.Claims, error) {
+	return m.tokens.Use(ctx, onetime.ResetPassword, token)
+}
+
+func (m *Mailer) link(purpose onetime.Purpose, path, userId, email string, ttl time.Duration) (string, error) {
+	token, err := m.tokens.Issue(purpose, userId, email, ttl)
+	if err != nil {
+		return "", err
+	}
+	return m.appURL + path + "?" + url.Values{"token": {token}}.Encode(), nil
+}
+
+// OnRegistration sends new users the link that verifies their email
+func OnRegistration(subscriber eventbus.Subscriber, verifier command.EmailVerifier) {
+	eventbus.SubscribeTo(subscriber, func(ctx context.Context, event domain.UserRegistered) error {
+		return verifier.SendVerificationEmail(ctx, event.UserId, event.Email)
+	})
+}
@@ -0,0 +1,79 @@
+package accountmail_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/onetime"
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/mail"
+	"github.com/iammrsea/social-app/internal/user/domain"
+	"github.com/iammrsea/social-app/internal/user/infra/accountmail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMailer() (*accountmail.Mailer, *mail.MemoryMailer) {
+	tokens := onetime.NewTokens(auth.NewHMACKeySet([]byte("test-secret")), onetime.NewMemoryStore(), "social-app")
+	sent := mail.NewMemoryMailer()
+	return accountmail.New(tokens, sent, "https://example.com", time.Hour, time.Hour), sent
+}
+
+// tokenFromLink returns the token in the link of an email
+func tokenFromLink(t *testing.T, body, path string) string {
+	t.Helper()
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "https://example.com"+path+"?") {
+			link, err := url.Parse(line)
+			require.NoError(t, err)
+			return link.Query().Get("token")
+		}
+	}
+	t.Fatalf("no link to %s in %q", path, body)
+	return ""
+}
+
+func TestMailer(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should send a verification link on registration", func(t *testing.T) {
+		t.Parallel()
+		mailer, sent := newMailer()
+		bus := eventbus.New()
+		accountmail.OnRegistration(bus, mailer)
+
+		require.NoError(t, bus.Publish(ctx, domain.UserRegistered{UserId: "user-1", Email: "johndoe@example.com"}))
+
+		msg, ok := sent.Last("johndoe@example.com")
+		require.True(t, ok)
+		token := tokenFromLink(t, msg.Body, accountmail.VerifyEmailPath)
+		claims, err := mailer.RedeemVerificationToken(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", claims.Subject)
+		assert.Equal(t, "johndoe@example.com", claims.Email)
+
+		// A verification link can't reset the password
+		_, err = mailer.RedeemPasswordResetToken(ctx, token)
+		assert.ErrorIs(t, err, onetime.ErrInvalidToken)
+	})
+
+	t.Run("should send a password reset link", func(t *testing.T) {
+		t.Parallel()
+		mailer, sent := newMailer()
+
+		require.NoError(t, mailer.SendPasswordResetEmail(ctx, "user-1", "johndoe@example.com"))
+
+		msg, ok := sent.Last("johndoe@example.com")
+		require.True(t, ok)
+		token := tokenFromLink(t, msg.Body, accountmail.ResetPasswordPath)
+		_, err := mailer.RedeemPasswordResetToken(ctx, token)
+		require.NoError(t, err)
+		_, err = mailer.RedeemPasswordResetToken(ctx, token)
+		assert.ErrorIs(t, err, onetime.ErrTokenUsed)
+	})
+}
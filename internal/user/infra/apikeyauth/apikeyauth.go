@@ -0,0 +1,48 @@
+package apikeyauth
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/apikeys"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+// KeyVerifier finds the active API key matching a key
+type KeyVerifier interface {
+	Verify(ctx context.Context, key string) (apikeys.APIKey, error)
+}
+
+type authenticator struct {
+	keys              KeyVerifier
+	userReadModelRepo domain.UserReadModelRepository
+}
+
+// NewAuthenticator authenticates API keys as their owner. The owner's role is read on every
+// request, so a demoted user's keys lose the permissions the user lost.
+func NewAuthenticator(keys KeyVerifier, userReadModelRepo domain.UserReadModelRepository) auth.APIKeyAuthenticator {
+	if keys == nil || userReadModelRepo == nil {
+		panic("nil key verifier or user read model repository")
+	}
+	return &authenticator{keys: keys, userReadModelRepo: userReadModelRepo}
+}
+
+func (a *authenticator) AuthenticateAPIKey(ctx context.Context, key string) (*auth.AuthenticatedUser, error) {
+	apiKey, err := a.keys.Verify(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	user, err := a.userReadModelRepo.GetUserById(ctx, apiKey.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &auth.AuthenticatedUser{
+		Id:       user.Id,
+		Email:    user.Email,
+		Roles:    user.Roles,
+		APIKeyId: apiKey.Id,
+		Scopes:   apiKey.Scopes,
+		// Keys of users who haven't verified their email are as limited as the users
+		EmailUnverified: !user.EmailVerified,
+	}, nil
+}
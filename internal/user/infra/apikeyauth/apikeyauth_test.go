@@ -0,0 +1,65 @@
+package apikeyauth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/apikeys"
+	"github.com/iammrsea/social-app/internal/shared/guards"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/user/domain"
+	domain_mocks "github.com/iammrsea/social-app/internal/user/domain/mocks"
+	"github.com/iammrsea/social-app/internal/user/infra/apikeyauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticator(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manager := apikeys.NewManager(apikeys.NewMemoryStore())
+	key, err := apikeys.NewKey()
+	require.NoError(t, err)
+	_, err = manager.Create(ctx, apikeys.NewAPIKey{Id: "key-1", UserId: "user-1", Key: key, Name: "bot", Scopes: []rbac.Permission{rbac.CreatePost, rbac.BanUser}})
+	require.NoError(t, err)
+
+	repo := domain_mocks.NewMockUserReadModelRepository(t)
+	repo.EXPECT().GetUserById(mock.Anything, "user-1").Return(&domain.UserReadModel{Id: "user-1", Email: "bot@example.com", EmailVerified: true, Roles: []rbac.UserRole{rbac.Regular}}, nil).Maybe()
+
+	authenticate := func(header string) *auth.AuthenticatedUser {
+		var user *auth.AuthenticatedUser
+		handler := auth.Middleware(nil, apikeyauth.NewAuthenticator(manager, repo))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user = auth.GetUserFromCtx(r.Context())
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/some-url", nil)
+		req.Header.Set("Authorization", header)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return user
+	}
+
+	t.Run("should authenticate the owner of the key with the key's scopes", func(t *testing.T) {
+		user := authenticate(auth.APIKeyScheme + " " + key)
+		require.True(t, user.IsAuthenticated())
+		assert.Equal(t, "user-1", user.Id)
+		assert.Equal(t, "key-1", user.APIKeyId)
+		assert.Empty(t, user.TokenId)
+
+		guard := guards.New()
+		assert.NoError(t, guard.Authorize(context.Background(), user.Subject(), rbac.CreatePost))
+		// Regular users can delete posts, but the key isn't scoped to
+		assert.ErrorIs(t, guard.Authorize(context.Background(), user.Subject(), rbac.DeletePost), rbac.ErrUnauthorized)
+		// The key is scoped to ban users, but regular users can't
+		assert.ErrorIs(t, guard.Authorize(context.Background(), user.Subject(), rbac.BanUser), rbac.ErrUnauthorized)
+	})
+
+	t.Run("should leave requests with unknown or revoked keys unauthenticated", func(t *testing.T) {
+		assert.False(t, authenticate(auth.APIKeyScheme+" "+apikeys.KeyPrefix+"unknown").IsAuthenticated())
+
+		require.NoError(t, manager.Revoke(ctx, "user-1", "key-1"))
+		assert.False(t, authenticate(auth.APIKeyScheme+" "+key).IsAuthenticated())
+	})
+}
@@ -0,0 +1,52 @@
+package bancheck
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/guards/bans"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+type lookup struct {
+	userReadModelRepo domain.UserReadModelRepository
+}
+
+// NewLookup reads ban state from the user read model
+func NewLookup(userReadModelRepo domain.UserReadModelRepository) bans.Lookup {
+	if userReadModelRepo == nil {
+		panic("nil user read model repository")
+	}
+	return &lookup{userReadModelRepo: userReadModelRepo}
+}
+
+func (l *lookup) BanStatus(ctx context.Context, userId string) (bans.Status, error) {
+	user, err := l.userReadModelRepo.GetUserById(ctx, userId)
+	if err != nil {
+		return bans.Status{}, err
+	}
+	ban := user.BanStatus
+	// IsBanned only covers bans in effect right now. A ban always has a reason and lifting it
+	// clears the reason, so the reason also reveals bans that start later.
+	status := bans.Status{
+		Banned: ban.IsBanned || ban.ReasonForBan != "",
+		Reason: ban.ReasonForBan,
+		From:   ban.BanStartDate,
+	}
+	if !ban.IsBanIndefinite {
+		status.Until = ban.BanEndDate
+	}
+	return status, nil
+}
+
+// InvalidateOnBanChanges drops a user's cached ban as soon as the user is banned or unbanned
+func InvalidateOnBanChanges(subscriber eventbus.Subscriber, checker *bans.CachedChecker) {
+	eventbus.SubscribeTo(subscriber, func(ctx context.Context, event domain.UserBanned) error {
+		checker.Invalidate(event.UserId)
+		return nil
+	})
+	eventbus.SubscribeTo(subscriber, func(ctx context.Context, event domain.UserUnbanned) error {
+		checker.Invalidate(event.UserId)
+		return nil
+	})
+}
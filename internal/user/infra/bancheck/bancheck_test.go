@@ -0,0 +1,69 @@
+package bancheck_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/guards/bans"
+	"github.com/iammrsea/social-app/internal/user/domain"
+	domain_mocks "github.com/iammrsea/social-app/internal/user/domain/mocks"
+	"github.com/iammrsea/social-app/internal/user/infra/bancheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+
+	testCases := []struct {
+		name      string
+		banStatus domain.BanStatus
+		want      bans.Status
+	}{
+		{
+			name: "should report users without a ban as not banned",
+			want: bans.Status{},
+		},
+		{
+			name:      "should report an indefinite ban without an end",
+			banStatus: domain.BanStatus{IsBanned: true, ReasonForBan: "abuse", IsBanIndefinite: true},
+			want:      bans.Status{Banned: true, Reason: "abuse"},
+		},
+		{
+			name: "should report a ban that starts later",
+			banStatus: domain.BanStatus{
+				ReasonForBan: "spam", BanStartDate: now.Add(time.Hour), BanEndDate: now.Add(2 * time.Hour),
+			},
+			want: bans.Status{Banned: true, Reason: "spam", From: now.Add(time.Hour), Until: now.Add(2 * time.Hour)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo := domain_mocks.NewMockUserReadModelRepository(t)
+			repo.EXPECT().GetUserById(context.Background(), "user-1").Return(&domain.UserReadModel{Id: "user-1", BanStatus: tc.banStatus}, nil)
+
+			status, err := bancheck.NewLookup(repo).BanStatus(context.Background(), "user-1")
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, status)
+		})
+	}
+}
+
+func TestInvalidateOnBanChanges(t *testing.T) {
+	t.Parallel()
+	repo := domain_mocks.NewMockUserReadModelRepository(t)
+	repo.EXPECT().GetUserById(context.Background(), "user-1").Return(&domain.UserReadModel{Id: "user-1"}, nil).Once()
+	repo.EXPECT().GetUserById(context.Background(), "user-1").
+		Return(&domain.UserReadModel{Id: "user-1", BanStatus: domain.BanStatus{IsBanned: true, ReasonForBan: "spam", IsBanIndefinite: true}}, nil).Once()
+	checker := bans.NewCachedChecker(bancheck.NewLookup(repo), bans.WithTTL(time.Hour))
+	bus := eventbus.New()
+	bancheck.InvalidateOnBanChanges(bus, checker)
+
+	require.NoError(t, checker.CheckNotBanned(context.Background(), "user-1"))
+	require.NoError(t, bus.Publish(context.Background(), domain.UserBanned{UserId: "user-1"}))
+	assert.Error(t, checker.CheckNotBanned(context.Background(), "user-1"))
+}
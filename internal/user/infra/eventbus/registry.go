@@ -0,0 +1,23 @@
+package eventbus
+
+import (
+	"github.com/iammrsea/social-app/internal/shared/outbox"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+// RegisterEvents lets the outbox relay decode the events raised by the user aggregate
+func RegisterEvents(registry *outbox.Registry) {
+	outbox.Register[domain.UserRegistered](registry)
+	outbox.Register[domain.UserBanned](registry)
+	outbox.Register[domain.UserUnbanned](registry)
+	outbox.Register[domain.BadgeAwarded](registry)
+	outbox.Register[domain.BadgeRevoked](registry)
+	outbox.Register[domain.UsernameChanged](registry)
+	outbox.Register[domain.UserRoleAssigned](registry)
+	outbox.Register[domain.UserRoleRemoved](registry)
+	outbox.Register[domain.UserEmailVerified](registry)
+	outbox.Register[domain.UserPasswordReset](registry)
+	outbox.Register[domain.UserDeletionRequested](registry)
+	outbox.Register[domain.UserDeletionCancelled](registry)
+	outbox.Register[domain.UserDeleted](registry)
+}
@@ -5,21 +5,31 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/shared/outbox"
 	"github.com/iammrsea/social-app/internal/user/domain"
 )
 
 // simulate users table for a typical sql db
 type userModel struct {
-	id         string
-	email      string
-	username   string
-	role       string
-	reputation userReputationModel
-	createdAt  time.Time
-	updatedAt  time.Time
+	id              string
+	email           string
+	username        string
+	roles           []rbac.UserRole
+	reputation      userReputationModel
+	ban             userBanModel
+	passwordHash    string
+	emailVerifiedAt time.Time
+	// deletionDueAt is zero unless the deletion of the account was requested
+	deletionRequestedAt time.Time
+	deletionDueAt       time.Time
+	createdAt           time.Time
+	updatedAt           time.Time
+	version             int
 }
 
 // simulate user_reputations table for a typical sql db
@@ -28,182 +38,390 @@ type userReputationModel struct {
 	badges          []string
 }
 
-type memoryRepository struct {
-	users []*userModel
+type userBanModel struct {
+	isBanned        bool
+	bannedAt        time.Time
+	banStartDate    time.Time
+	banEndDate      time.Time
+	reasonForBan    string
+	isBanIndefinite bool
 }
 
-func NewUserRepository(ctx context.Context) *memoryRepository {
-	return &memoryRepository{}
+// UserRepository keeps users in memory. It implements both domain.UserRepository and
+// domain.UserReadModelRepository. The events raised by users are appended to the outbox when they
+// are saved, like the database repositories do. Updates only succeed if the user hasn't changed
+// since it was read, otherwise they fail with domain.ErrConcurrentModification. Emails and
+// usernames are unique regardless of case.
+type UserRepository struct {
+	mu     sync.Mutex
+	users  []*userModel
+	outbox *outbox.MemoryStore
 }
 
-func (m *memoryRepository) GetUserById(ctx context.Context, userId string) (*domain.UserReadModel, error) {
+func NewUserRepository(outbox *outbox.MemoryStore) *UserRepository {
+	if outbox == nil {
+		panic("nil outbox")
+	}
+	return &UserRepository{outbox: outbox}
+}
+
+func (m *UserRepository) GetUserById(ctx context.Context, userId string) (*domain.UserReadModel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	u, err := m.getUserModelById(userId)
 	if err != nil {
 		return nil, err
 	}
-	return &domain.UserReadModel{
-		Username: u.username,
-		Email:    u.email,
-		Role:     rbac.UserRole(u.role),
-		Id:       u.id,
-		Reputation: domain.UserReputation{
-			ReputationScore: u.reputation.reputationScore,
-			Badges:          u.reputation.badges,
-		},
-	}, nil
-}
-func (m *memoryRepository) BanUser(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
-	panic("not implemented")
+	return u.toReadModel(), nil
 }
 
-func (m *memoryRepository) GetUserByEmail(ctx context.Context, email string) (*domain.UserReadModel, error) {
+func (m *UserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.UserReadModel, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	i := slices.IndexFunc(m.users, func(u *userModel) bool {
-		return email == u.email
+		return strings.EqualFold(email, u.email)
 	})
 	if i < 0 {
-		return nil, fmt.Errorf("user with email %s does not exist", email)
+		return nil, domain.ErrUserNotFound
 	}
-	u := m.users[i]
-	return &domain.UserReadModel{
-		Username: u.username,
-		Email:    u.email,
-		Role:     rbac.UserRole(u.role),
-		Id:       u.id,
-		Reputation: domain.UserReputation{
-			ReputationScore: u.reputation.reputationScore,
-			Badges:          u.reputation.badges,
-		},
-	}, nil
+	return m.users[i].toReadModel(), nil
 }
 
-func (m *memoryRepository) GetUsers(ctx context.Context, opts domain.GetUsersOptions) ([]*domain.UserReadModel, bool, error) {
+// GetUsers pages through users by the time they joined, oldest first unless opts.SortDirection is DESC
+func (m *UserRepository) GetUsers(ctx context.Context, opts domain.GetUsersOptions) ([]*domain.UserReadModel, bool, error) {
+	if opts.First <= 0 {
+		return nil, false, domain.ErrInvalidPageSize
+	}
+	asc := true
+	switch opts.SortDirection {
+	case "", "ASC":
+	case "DESC":
+		asc = false
+	default:
+		return nil, false, errors.New("invalid sort direction, must be 'ASC' or 'DESC'")
+	}
+	var after time.Time
+	if opts.After != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, opts.After)
+		if err != nil {
+			return nil, false, errors.New("invalid after timestamp format")
+		}
+		after = parsed
+	}
+
+	m.mu.Lock()
 	users := []*domain.UserReadModel{}
+	for _, u := range m.users {
+		if !after.IsZero() {
+			if asc && !u.createdAt.After(after) {
+				continue
+			}
+			if !asc && !u.createdAt.Before(after) {
+				continue
+			}
+		}
+		users = append(users, u.toReadModel())
+	}
+	m.mu.Unlock()
 
-	for _, user := range m.users {
-		users = append(users, &domain.UserReadModel{
-			Username: user.username,
-			Email:    user.email,
-			Role:     rbac.UserRole(user.role),
-			Id:       user.id,
-			Reputation: domain.UserReputation{
-				ReputationScore: user.reputation.reputationScore,
-				Badges:          user.reputation.badges,
-			},
-		})
+	slices.SortFunc(users, func(a, b *domain.UserReadModel) int {
+		if asc {
+			return a.CreatedAt.Compare(b.CreatedAt)
+		}
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	hasNext := len(users) > int(opts.First)
+	if hasNext {
+		users = users[:opts.First]
 	}
-	hasNext := false // TODO: Calculate hasNext
 	return users, hasNext, nil
 }
 
-func (m *memoryRepository) Register(ctx context.Context, user domain.User) error {
-	userExists := slices.ContainsFunc(m.users, func(u *userModel) bool {
-		return user.Email() == u.email || user.Username() == u.username
-	})
-	if userExists {
-		return errors.New("user with email or username already exists")
+func (m *UserRepository) Register(ctx context.Context, user domain.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.taken(user.Email(), user.Username(), "") {
+		return domain.ErrEmailOrUsernameAlreadyExists
+	}
+	if _, err := m.getUserModelById(user.Id()); err == nil {
+		return fmt.Errorf("a user with id %s already exists", user.Id())
 	}
-	m.users = append(m.users, m.toUserModel(&user))
+	if err := m.appendToOutbox(ctx, &user); err != nil {
+		return err
+	}
+	u := &userModel{id: user.Id(), createdAt: user.JoinedAt(), version: 1}
+	u.save(&user)
+	u.updatedAt = user.UpdatedAt()
+	m.users = append(m.users, u)
 	return nil
 }
 
-func (m *memoryRepository) MakeModerator(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
-	u, err := m.getUserModelById(userId)
+func (m *UserRepository) UpdateRoles(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return m.updateUser(ctx, userId, updateFn)
+}
 
-	if err != nil {
-		return err
-	}
-	userDomain := m.toDomainUser(u)
-	err = updateFn(userDomain)
+func (m *UserRepository) AwardBadge(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return m.updateUser(ctx, userId, updateFn)
+}
 
-	if err != nil {
-		return err
-	}
+func (m *UserRepository) RevokeAwardedBadge(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return m.updateUser(ctx, userId, updateFn)
+}
 
-	u.role = string(userDomain.Role())
+func (m *UserRepository) ChangeUsername(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return m.updateUser(ctx, userId, updateFn)
+}
 
-	return nil
+func (m *UserRepository) BanUser(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return m.updateUser(ctx, userId, updateFn)
 }
-func (m *memoryRepository) AwardBadge(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
-	u, err := m.getUserModelById(userId)
 
-	if err != nil {
-		return err
+func (m *UserRepository) UnbanUser(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return m.updateUser(ctx, userId, updateFn)
+}
+
+func (m *UserRepository) VerifyEmail(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return m.updateUser(ctx, userId, updateFn)
+}
+
+func (m *UserRepository) ResetPassword(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return m.updateUser(ctx, userId, updateFn)
+}
+
+func (m *UserRepository) ScheduleDeletion(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return m.updateUser(ctx, userId, updateFn)
+}
+
+func (m *UserRepository) DeleteUser(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return m.writeUser(ctx, userId, updateFn, func(u *userModel, user *domain.User) error {
+		m.users = slices.DeleteFunc(m.users, func(stored *userModel) bool {
+			return stored == u
+		})
+		return nil
+	})
+}
+
+// GetUserBy finds a user by id, email or username. Emails and usernames match regardless of case.
+func (m *UserRepository) GetUserBy(ctx context.Context, fieldName string, value any) (*domain.User, error) {
+	text, _ := value.(string)
+	var matches func(u *userModel) bool
+	switch fieldName {
+	case "id":
+		matches = func(u *userModel) bool { return u.id == value }
+	case "email":
+		matches = func(u *userModel) bool { return strings.EqualFold(u.email, text) }
+	case "username":
+		matches = func(u *userModel) bool { return strings.EqualFold(u.username, text) }
+	default:
+		return nil, fmt.Errorf("users can't be looked up by %s", fieldName)
 	}
-	userDomain := m.toDomainUser(u)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := slices.IndexFunc(m.users, matches)
+	if i < 0 {
+		return nil, domain.ErrUserNotFound
+	}
+	return m.users[i].toDomain(), nil
+}
 
-	err = updateFn(userDomain)
+// UserExists tells whether a user has the email or the username, regardless of case
+func (m *UserRepository) UserExists(ctx context.Context, email string, username string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.taken(email, username, ""), nil
+}
 
-	if err != nil {
-		return err
+func (m *UserRepository) ExpiredBans(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	return m.findIds(limit, func(u *userModel) (time.Time, bool) {
+		expired := u.ban.isBanned && !u.ban.isBanIndefinite && !u.ban.banEndDate.After(now)
+		return u.ban.banEndDate, expired
+	}), nil
+}
+
+func (m *UserRepository) DueDeletions(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	return m.findIds(limit, func(u *userModel) (time.Time, bool) {
+		due := !u.deletionDueAt.IsZero() && !u.deletionDueAt.After(now)
+		return u.deletionDueAt, due
+	}), nil
+}
+
+// findIds returns the ids of up to limit users that match, ordered by the time match returns
+func (m *UserRepository) findIds(limit int, match func(u *userModel) (time.Time, bool)) []string {
+	type found struct {
+		id string
+		at time.Time
 	}
-	u.reputation.badges = userDomain.Badges()
-	return nil
+	m.mu.Lock()
+	matches := []found{}
+	for _, u := range m.users {
+		if at, ok := match(u); ok {
+			matches = append(matches, found{id: u.id, at: at})
+		}
+	}
+	m.mu.Unlock()
+
+	slices.SortFunc(matches, func(a, b found) int {
+		return a.at.Compare(b.at)
+	})
+	ids := []string{}
+	for _, match := range matches {
+		if len(ids) == limit {
+			break
+		}
+		ids = append(ids, match.id)
+	}
+	return ids
+}
+
+func (m *UserRepository) updateUser(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return m.writeUser(ctx, userId, updateFn, func(u *userModel, user *domain.User) error {
+		if m.taken(user.Email(), user.Username(), u.id) {
+			return domain.ErrEmailOrUsernameAlreadyExists
+		}
+		u.save(user)
+		u.updatedAt = time.Now()
+		u.version++
+		return nil
+	})
 }
-func (m *memoryRepository) RevokeAwardedBadge(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+
+// writeUser applies updateFn outside the lock, like a database read followed by a write, and
+// only saves the result with write if no other update was saved in between
+func (m *UserRepository) writeUser(ctx context.Context, userId string, updateFn func(user *domain.User) error, write func(u *userModel, user *domain.User) error) error {
+	m.mu.Lock()
 	u, err := m.getUserModelById(userId)
 	if err != nil {
+		m.mu.Unlock()
 		return err
 	}
-	userDomain := m.toDomainUser(u)
-	err = updateFn(userDomain)
-	if err != nil {
+	version := u.version
+	user := u.toDomain()
+	m.mu.Unlock()
+
+	if err := updateFn(user); err != nil {
 		return err
 	}
-	u.reputation.badges = userDomain.Badges()
 
-	return nil
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if u.version != version || !slices.Contains(m.users, u) {
+		return domain.ErrConcurrentModification
+	}
+	if err := write(u, user); err != nil {
+		return err
+	}
+	return m.appendToOutbox(ctx, user)
 }
-func (m *memoryRepository) ChangeUsername(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
-	u, err := m.getUserModelById(userId)
+
+func (m *UserRepository) appendToOutbox(ctx context.Context, user *domain.User) error {
+	evts := user.PullEvents()
+	if len(evts) == 0 {
+		return nil
+	}
+	messages, err := outbox.NewMessages(evts)
 	if err != nil {
 		return err
 	}
-	userDomain := m.toDomainUser(u)
+	return m.outbox.Append(ctx, messages...)
+}
 
-	err = updateFn(userDomain)
+// taken tells whether a user other than exceptId has the email or the username, regardless of case
+func (m *UserRepository) taken(email, username, exceptId string) bool {
+	return slices.ContainsFunc(m.users, func(u *userModel) bool {
+		if u.id == exceptId {
+			return false
+		}
+		return (email != "" && strings.EqualFold(u.email, email)) || (username != "" && strings.EqualFold(u.username, username))
+	})
+}
 
-	if err != nil {
-		return err
+func (m *UserRepository) getUserModelById(userId string) (*userModel, error) {
+	i := slices.IndexFunc(m.users, func(u *userModel) bool {
+		return userId == u.id
+	})
+	if i < 0 {
+		return nil, domain.ErrUserNotFound
 	}
-	u.username = userDomain.Username()
-	return nil
+	return m.users[i], nil
 }
 
-func (m *memoryRepository) toUserModel(user *domain.User) *userModel {
-	return &userModel{
-		id:        user.Id(),
-		email:     user.Email(),
-		username:  user.Username(),
-		role:      user.Role().String(),
-		createdAt: user.JoinedAt(),
-		reputation: userReputationModel{
-			reputationScore: user.ReputationScore(),
-			badges:          user.Badges(),
-		},
+// save copies what can change about user to the model
+func (u *userModel) save(user *domain.User) {
+	u.email = user.Email()
+	u.username = user.Username()
+	u.roles = slices.Clone(user.Roles())
+	u.reputation = userReputationModel{
+		reputationScore: user.ReputationScore(),
+		badges:          slices.Clone(user.Badges()),
+	}
+	u.ban = userBanModel{
+		isBanned:        user.HasBan(),
+		bannedAt:        user.BannedAt(),
+		banStartDate:    user.BanStartDate(),
+		banEndDate:      user.BanEndDate(),
+		reasonForBan:    user.ReasonForBan(),
+		isBanIndefinite: user.IsBanIndefinite(),
 	}
+	u.passwordHash = user.PasswordHash()
+	u.emailVerifiedAt = user.EmailVerifiedAt()
+	u.deletionRequestedAt = user.DeletionRequestedAt()
+	u.deletionDueAt = user.DeletionDueAt()
 }
 
-func (m *memoryRepository) toDomainUser(userModel *userModel) *domain.User {
+func (u *userModel) toDomain() *domain.User {
 	user := domain.MustNewUser(
-		userModel.id,
-		userModel.email,
-		userModel.username,
-		rbac.UserRole(userModel.role),
-		userModel.createdAt,
-		userModel.updatedAt,
-		domain.MustNewUserReputation(userModel.reputation.reputationScore, userModel.reputation.badges),
-		nil,
+		u.id,
+		u.email,
+		u.username,
+		slices.Clone(u.roles),
+		u.createdAt,
+		u.updatedAt,
+		domain.MustNewUserReputation(u.reputation.reputationScore, slices.Clone(u.reputation.badges)),
+		domain.NewBan(
+			u.ban.isBanned,
+			u.ban.reasonForBan,
+			u.ban.isBanIndefinite,
+			u.ban.banStartDate,
+			u.ban.banEndDate,
+			u.ban.bannedAt,
+		),
 	)
-
+	if u.passwordHash != "" {
+		_ = user.SetPasswordHash(u.passwordHash)
+	}
+	user.SetEmailVerifiedAt(u.emailVerifiedAt)
+	if !u.deletionDueAt.IsZero() {
+		user.SetDeletion(u.deletionRequestedAt, u.deletionDueAt)
+	}
 	return &user
 }
 
-func (m *memoryRepository) getUserModelById(userId string) (*userModel, error) {
-	i := slices.IndexFunc(m.users, func(u *userModel) bool {
-		return userId == u.id
-	})
-	if i < 0 {
-		return nil, fmt.Errorf("user with id %s does not exist", userId)
+func (u *userModel) toReadModel() *domain.UserReadModel {
+	var deletionDueAt *time.Time
+	if !u.deletionDueAt.IsZero() {
+		dueAt := u.deletionDueAt
+		deletionDueAt = &dueAt
+	}
+	return &domain.UserReadModel{
+		Id:            u.id,
+		Username:      u.username,
+		Email:         u.email,
+		EmailVerified: !u.emailVerifiedAt.IsZero(),
+		DeletionDueAt: deletionDueAt,
+		Roles:         slices.Clone(u.roles),
+		Reputation: domain.UserReputation{
+			ReputationScore: u.reputation.reputationScore,
+			Badges:          slices.Clone(u.reputation.badges),
+		},
+		CreatedAt: u.createdAt,
+		UpdatedAt: u.updatedAt,
+		BanStatus: domain.BanStatus{
+			IsBanned:        domain.IsBanActive(u.ban.isBanned, u.ban.isBanIndefinite, u.ban.banStartDate, u.ban.banEndDate, time.Now()),
+			BannedAt:        u.ban.bannedAt,
+			BanStartDate:    u.ban.banStartDate,
+			BanEndDate:      u.ban.banEndDate,
+			ReasonForBan:    u.ban.reasonForBan,
+			IsBanIndefinite: u.ban.isBanIndefinite,
+		},
 	}
-	return m.users[i], nil
 }
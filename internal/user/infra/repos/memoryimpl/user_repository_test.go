@@ -2,28 +2,41 @@ package memoryimpl_test
 
 import (
 	"context"
+	"fmt"
 	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/shared/outbox"
 	"github.com/iammrsea/social-app/internal/user/domain"
 	"github.com/iammrsea/social-app/internal/user/infra/repos/memoryimpl"
+	"github.com/iammrsea/social-app/internal/user/infra/repos/repotest"
 	"github.com/stretchr/testify/assert"
 )
 
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	repotest.Run(t, func(t *testing.T) repotest.Repos {
+		repo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
+		return repotest.Repos{Users: repo, ReadModels: repo}
+	})
+}
+
 func TestRegister(t *testing.T) {
 	t.Parallel()
 
 	t.Run("should be able to register user", func(t *testing.T) {
 		t.Parallel()
 		user := domain.MustNewUser("user-id", "johndoe@gmail.com",
-			"johndoe", rbac.Regular,
+			"johndoe", []rbac.UserRole{rbac.Regular},
 			time.Now(),
 			time.Now(),
 			nil, nil)
 
-		memRepo := memoryimpl.NewUserRepository(context.Background())
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
 
 		ctx := context.Background()
 
@@ -39,11 +52,11 @@ func TestRegister(t *testing.T) {
 	t.Run("should return correct error if user already exists", func(t *testing.T) {
 		t.Parallel()
 
-		user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", rbac.Regular,
+		user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", []rbac.UserRole{rbac.Regular},
 			time.Now(),
 			time.Now(), nil, nil)
 
-		memRepo := memoryimpl.NewUserRepository(context.Background())
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
 
 		ctx := context.Background()
 
@@ -53,49 +66,49 @@ func TestRegister(t *testing.T) {
 		// Register same user again
 		err = memRepo.Register(ctx, user)
 		assert.NotNil(t, err)
-		assert.Equal(t, err.Error(), "user with email or username already exists")
+		assert.ErrorIs(t, err, domain.ErrEmailOrUsernameAlreadyExists)
 	})
 }
 
-func TestMakeModerator(t *testing.T) {
+func TestUpdateRoles(t *testing.T) {
 	t.Parallel()
 
-	t.Run("should be able to make user moderator", func(t *testing.T) {
+	t.Run("should be able to assign user a role", func(t *testing.T) {
 		t.Parallel()
 
-		user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", rbac.Regular,
+		user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", []rbac.UserRole{rbac.Regular},
 			time.Now(),
 			time.Now(), nil, nil)
 
-		memRepo := memoryimpl.NewUserRepository(context.Background())
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
 
 		ctx := context.Background()
 
 		err := memRepo.Register(ctx, user)
 		assert.Nil(t, err)
 
-		err = memRepo.MakeModerator(ctx, user.Id(), func(user *domain.User) error {
-			return user.MakeModerator()
+		err = memRepo.UpdateRoles(ctx, user.Id(), func(user *domain.User) error {
+			return user.AssignRole(rbac.Moderator)
 		})
 		assert.Nil(t, err)
 
 		savedUser, err := memRepo.GetUserById(ctx, user.Id())
 		assert.Nil(t, err)
-		assert.Equal(t, savedUser.Role, rbac.Moderator)
+		assert.Equal(t, []rbac.UserRole{rbac.Regular, rbac.Moderator}, savedUser.Roles)
 	})
 
 	t.Run("should return correct error if user does not exist", func(t *testing.T) {
 		t.Parallel()
 
-		memRepo := memoryimpl.NewUserRepository(context.Background())
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
 
 		ctx := context.Background()
 
-		err := memRepo.MakeModerator(ctx, "user-id", func(user *domain.User) error {
-			return user.MakeModerator()
+		err := memRepo.UpdateRoles(ctx, "user-id", func(user *domain.User) error {
+			return user.AssignRole(rbac.Moderator)
 		})
 		assert.NotNil(t, err)
-		assert.Equal(t, err.Error(), "user with id user-id does not exist")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
 	})
 }
 
@@ -105,11 +118,11 @@ func TestAwardBadge(t *testing.T) {
 	t.Run("should be able to award badge to user", func(t *testing.T) {
 		t.Parallel()
 
-		user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", rbac.Regular,
+		user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", []rbac.UserRole{rbac.Regular},
 			time.Now(),
 			time.Now(), nil, nil)
 
-		memRepo := memoryimpl.NewUserRepository(context.Background())
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
 
 		ctx := context.Background()
 
@@ -129,7 +142,7 @@ func TestAwardBadge(t *testing.T) {
 	t.Run("should return correct error if user does not exist", func(t *testing.T) {
 		t.Parallel()
 
-		memRepo := memoryimpl.NewUserRepository(context.Background())
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
 
 		ctx := context.Background()
 
@@ -137,7 +150,7 @@ func TestAwardBadge(t *testing.T) {
 			return user.AwardBadge("5 start")
 		})
 		assert.NotNil(t, err)
-		assert.Equal(t, err.Error(), "user with id user-id does not exist")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
 	})
 }
 
@@ -147,11 +160,11 @@ func TestRevokeAwardedBadge(t *testing.T) {
 	t.Run("should be able to revoke badge from user", func(t *testing.T) {
 		t.Parallel()
 
-		user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", rbac.Regular,
+		user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", []rbac.UserRole{rbac.Regular},
 			time.Now(),
 			time.Now(), nil, nil)
 
-		memRepo := memoryimpl.NewUserRepository(context.Background())
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
 
 		ctx := context.Background()
 
@@ -176,7 +189,7 @@ func TestRevokeAwardedBadge(t *testing.T) {
 	t.Run("should return correct error if user does not exist", func(t *testing.T) {
 		t.Parallel()
 
-		memRepo := memoryimpl.NewUserRepository(context.Background())
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
 
 		ctx := context.Background()
 
@@ -184,7 +197,7 @@ func TestRevokeAwardedBadge(t *testing.T) {
 			return user.RevokeAwardedBadge("4 star")
 		})
 		assert.NotNil(t, err)
-		assert.Equal(t, err.Error(), "user with id user-id does not exist")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
 	})
 
 }
@@ -195,10 +208,10 @@ func TestChangeUsername(t *testing.T) {
 	t.Run("should be able to change username", func(t *testing.T) {
 		t.Parallel()
 
-		user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", rbac.Regular, time.Now(),
+		user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", []rbac.UserRole{rbac.Regular}, time.Now(),
 			time.Now(), nil, nil)
 
-		memRepo := memoryimpl.NewUserRepository(context.Background())
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
 
 		ctx := context.Background()
 
@@ -218,7 +231,7 @@ func TestChangeUsername(t *testing.T) {
 	t.Run("should return correct error if user does not exist", func(t *testing.T) {
 		t.Parallel()
 
-		memRepo := memoryimpl.NewUserRepository(context.Background())
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
 
 		ctx := context.Background()
 
@@ -226,7 +239,7 @@ func TestChangeUsername(t *testing.T) {
 			return user.ChangeUsername("johndoe2")
 		})
 		assert.NotNil(t, err)
-		assert.Equal(t, err.Error(), "user with id user-id does not exist")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
 	})
 }
 
@@ -236,11 +249,11 @@ func TestGetUserById(t *testing.T) {
 	t.Run("should be able to get user by id", func(t *testing.T) {
 		t.Parallel()
 
-		user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", rbac.Regular,
+		user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", []rbac.UserRole{rbac.Regular},
 			time.Now(),
 			time.Now(), nil, nil)
 
-		memRepo := memoryimpl.NewUserRepository(context.Background())
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
 
 		ctx := context.Background()
 
@@ -255,13 +268,13 @@ func TestGetUserById(t *testing.T) {
 	t.Run("should return correct error if user does not exist", func(t *testing.T) {
 		t.Parallel()
 
-		memRepo := memoryimpl.NewUserRepository(context.Background())
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
 
 		ctx := context.Background()
 
 		_, err := memRepo.GetUserById(ctx, "user-id")
 		assert.NotNil(t, err)
-		assert.Equal(t, err.Error(), "user with id user-id does not exist")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
 	})
 
 }
@@ -269,16 +282,16 @@ func TestGetUserById(t *testing.T) {
 func TestGetUsers(t *testing.T) {
 	t.Parallel()
 
-	memRepo := memoryimpl.NewUserRepository(context.Background())
+	memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
 
 	ctx := context.Background()
-	user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", rbac.Regular,
+	user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", []rbac.UserRole{rbac.Regular},
 		time.Now(),
 		time.Now(), nil, nil)
 	err := memRepo.Register(ctx, user)
 	assert.Nil(t, err)
 
-	users, hasNext, err := memRepo.GetUsers(ctx, domain.GetUsersOptions{})
+	users, hasNext, err := memRepo.GetUsers(ctx, domain.GetUsersOptions{First: 10})
 	assert.Nil(t, err)
 	assert.False(t, hasNext)
 	assert.Equal(t, len(users), 1)
@@ -291,11 +304,11 @@ func TestGetUserByEmail(t *testing.T) {
 	t.Run("should be able to get user by email", func(t *testing.T) {
 		t.Parallel()
 
-		memRepo := memoryimpl.NewUserRepository(context.Background())
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
 
 		ctx := context.Background()
 		user := domain.MustNewUser("user-id", "johndoe@gmail.com",
-			"johndoe", rbac.Regular,
+			"johndoe", []rbac.UserRole{rbac.Regular},
 			time.Now(),
 			time.Now(),
 			nil,
@@ -311,13 +324,13 @@ func TestGetUserByEmail(t *testing.T) {
 	t.Run("should correct error if user does not exist", func(t *testing.T) {
 		t.Parallel()
 
-		memRepo := memoryimpl.NewUserRepository(context.Background())
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
 
 		ctx := context.Background()
 
 		_, err := memRepo.GetUserByEmail(ctx, "johndoe@gmail.com")
 		assert.NotNil(t, err)
-		assert.Equal(t, err.Error(), "user with email johndoe@gmail.com does not exist")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
 	})
 }
 
@@ -325,7 +338,7 @@ func userDomainToUserReadModel(user domain.User) domain.UserReadModel {
 	return domain.UserReadModel{
 		Username: user.Username(),
 		Email:    user.Email(),
-		Role:     user.Role(),
+		Roles:    user.Roles(),
 		Id:       user.Id(),
 		Reputation: domain.UserReputation{
 			ReputationScore: user.ReputationScore(),
@@ -343,3 +356,167 @@ func userDomainToUserReadModel(user domain.User) domain.UserReadModel {
 		UpdatedAt: user.UpdatedAt(),
 	}
 }
+
+func TestConcurrentModification(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should reject an update when the user changed after being read", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", []rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil, nil)
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
+		assert.Nil(t, memRepo.Register(ctx, user))
+
+		err := memRepo.ChangeUsername(ctx, user.Id(), func(u *domain.User) error {
+			// Another writer saves first
+			if err := memRepo.AwardBadge(ctx, user.Id(), func(u *domain.User) error {
+				return u.AwardBadge("first")
+			}); err != nil {
+				return err
+			}
+			return u.ChangeUsername("janedoe")
+		})
+		assert.ErrorIs(t, err, domain.ErrConcurrentModification)
+
+		savedUser, _ := memRepo.GetUserById(ctx, user.Id())
+		assert.Equal(t, "johndoe", savedUser.Username)
+		assert.Equal(t, []string{"first"}, savedUser.Reputation.Badges)
+	})
+}
+
+func TestAccountDeletion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should erase the user once the deletion is due", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		now := time.Now()
+		user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", []rbac.UserRole{rbac.Regular}, now, now, nil, nil)
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
+		assert.Nil(t, memRepo.Register(ctx, user))
+
+		err := memRepo.ScheduleDeletion(ctx, user.Id(), func(u *domain.User) error {
+			return u.RequestDeletion(now, time.Hour)
+		})
+		assert.Nil(t, err)
+		savedUser, _ := memRepo.GetUserById(ctx, user.Id())
+		assert.NotNil(t, savedUser.DeletionDueAt)
+
+		due, err := memRepo.DueDeletions(ctx, now, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, due)
+		due, err = memRepo.DueDeletions(ctx, now.Add(time.Hour), 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{user.Id()}, due)
+
+		err = memRepo.DeleteUser(ctx, user.Id(), func(u *domain.User) error {
+			return u.Erase(now.Add(time.Hour))
+		})
+		assert.Nil(t, err)
+		_, err = memRepo.GetUserById(ctx, user.Id())
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("should keep the user if updateFn fails", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", []rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil, nil)
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
+		assert.Nil(t, memRepo.Register(ctx, user))
+
+		err := memRepo.DeleteUser(ctx, user.Id(), func(u *domain.User) error {
+			return u.Erase(time.Now())
+		})
+		assert.ErrorIs(t, err, domain.ErrDeletionNotRequested)
+		_, err = memRepo.GetUserById(ctx, user.Id())
+		assert.Nil(t, err)
+	})
+}
+
+func TestBans(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should save the ban and lift it once it expired", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		now := time.Now()
+		user := domain.MustNewUser("user-id", "johndoe@gmail.com", "johndoe", []rbac.UserRole{rbac.Regular}, now, now, nil, nil)
+		events := outbox.NewMemoryStore()
+		memRepo := memoryimpl.NewUserRepository(events)
+		assert.Nil(t, memRepo.Register(ctx, user))
+
+		err := memRepo.BanUser(ctx, user.Id(), func(u *domain.User) error {
+			return u.Ban("spam", false, domain.NewBanTimeline(now, now.Add(time.Hour)))
+		})
+		assert.Nil(t, err)
+		savedUser, _ := memRepo.GetUserById(ctx, user.Id())
+		assert.True(t, savedUser.BanStatus.IsBanned)
+		assert.Equal(t, "spam", savedUser.BanStatus.ReasonForBan)
+		assert.True(t, savedUser.UpdatedAt.After(now))
+
+		expired, err := memRepo.ExpiredBans(ctx, now, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, expired)
+		expired, err = memRepo.ExpiredBans(ctx, now.Add(time.Hour), 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{user.Id()}, expired)
+
+		err = memRepo.UnbanUser(ctx, user.Id(), func(u *domain.User) error {
+			return u.UnBan()
+		})
+		assert.Nil(t, err)
+		savedUser, _ = memRepo.GetUserById(ctx, user.Id())
+		assert.False(t, savedUser.BanStatus.IsBanned)
+
+		pending, err := events.Pending(ctx, 10)
+		assert.Nil(t, err)
+		names := []string{}
+		for _, msg := range pending {
+			names = append(names, msg.EventName)
+		}
+		assert.Equal(t, []string{domain.UserBanned{}.EventName(), domain.UserUnbanned{}.EventName()}, names)
+	})
+}
+
+func TestUniqueness(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should compare emails and usernames regardless of case", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
+		johndoe := domain.MustNewUser("user-1", "johndoe@gmail.com", "johndoe", []rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil, nil)
+		janedoe := domain.MustNewUser("user-2", "janedoe@gmail.com", "janedoe", []rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil, nil)
+		assert.Nil(t, memRepo.Register(ctx, johndoe))
+		assert.Nil(t, memRepo.Register(ctx, janedoe))
+
+		exists, err := memRepo.UserExists(ctx, "JohnDoe@gmail.com", "")
+		assert.Nil(t, err)
+		assert.True(t, exists)
+
+		err = memRepo.ChangeUsername(ctx, janedoe.Id(), func(u *domain.User) error {
+			return u.ChangeUsername("JOHNDOE")
+		})
+		assert.ErrorIs(t, err, domain.ErrEmailOrUsernameAlreadyExists)
+	})
+
+	t.Run("should let only one of concurrent registrations through", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		memRepo := memoryimpl.NewUserRepository(outbox.NewMemoryStore())
+		var wg sync.WaitGroup
+		var registered atomic.Int32
+		for i := range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				user := domain.MustNewUser(fmt.Sprintf("user-%d", i), "johndoe@gmail.com", fmt.Sprintf("johndoe%d", i), []rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil, nil)
+				if memRepo.Register(ctx, user) == nil {
+					registered.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), registered.Load())
+	})
+}
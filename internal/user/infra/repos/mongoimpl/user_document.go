@@ -9,14 +9,29 @@ import (
 
 // userDocument represents how a user is stored in MongoDB
 type userDocument struct {
-	ID        string         `bson:"_id"`
-	Email     string         `bson:"email"`
-	Username  string         `bson:"username"`
-	Role      string         `bson:"role"`
+	ID       string   `bson:"_id"`
+	Email    string   `bson:"email"`
+	Username string   `bson:"username"`
+	Roles    []string `bson:"roles"`
+	// Role is the single role of users saved before they could have several
+	Role      string         `bson:"role,omitempty"`
 	Reputaion userReputation `bson:"reputation"`
 	CreatedAt time.Time      `bson:"createdAt"`
 	UpdatedAt time.Time      `bson:"updatedAt"`
 	BanStatus userBanStatus  `bson:"banStatus"`
+	// PasswordHash is left out of the document for users registered before passwords
+	PasswordHash string `bson:"passwordHash,omitempty"`
+	// EmailVerifiedAt is left out until the user verifies the email
+	EmailVerifiedAt *time.Time `bson:"emailVerifiedAt,omitempty"`
+	// Version is incremented on every write and checked before replacing the document
+	Version int `bson:"version"`
+	// Deletion is left out unless the user asked for the account to be deleted
+	Deletion *userDeletion `bson:"deletion,omitempty"`
+}
+
+type userDeletion struct {
+	RequestedAt time.Time `bson:"requestedAt"`
+	DueAt       time.Time `bson:"dueAt"`
 }
 
 type userReputation struct {
@@ -35,11 +50,11 @@ type userBanStatus struct {
 
 // fromDomain converts a domain User to userDocument
 func fromDomain(user domain.User) userDocument {
-	return userDocument{
+	doc := userDocument{
 		ID:       user.Id(),
 		Email:    user.Email(),
 		Username: user.Username(),
-		Role:     user.Role().String(),
+		Roles:    roleNames(user.Roles()),
 		Reputaion: userReputation{
 			Badges:          user.Badges(),
 			ReputationScore: user.ReputationScore(),
@@ -47,45 +62,96 @@ func fromDomain(user domain.User) userDocument {
 		CreatedAt: user.JoinedAt(),
 		UpdatedAt: user.UpdatedAt(),
 		BanStatus: userBanStatus{
-			IsBanned:        user.IsBanned(),
+			IsBanned:        user.HasBan(),
 			BannedAt:        user.BannedAt(),
 			BanStartDate:    user.BanStartDate(),
 			BanEndDate:      user.BanEndDate(),
 			IsBanIndefinite: user.IsBanIndefinite(),
 			ReasonForBan:    user.ReasonForBan(),
 		},
+		PasswordHash: user.PasswordHash(),
+	}
+	if user.IsEmailVerified() {
+		verifiedAt := user.EmailVerifiedAt()
+		doc.EmailVerifiedAt = &verifiedAt
 	}
+	if user.IsDeletionRequested() {
+		doc.Deletion = &userDeletion{RequestedAt: user.DeletionRequestedAt(), DueAt: user.DeletionDueAt()}
+	}
+	return doc
 }
 
 // toDomain converts a userDocument to domain User
 func (u userDocument) toDomain() domain.User {
-	return domain.MustNewUser(
+	user := domain.MustNewUser(
 		u.ID,
 		u.Email,
 		u.Username,
-		rbac.UserRole(u.Role),
+		u.roles(),
 		u.CreatedAt,
 		u.UpdatedAt,
 		domain.MustNewUserReputation(u.Reputaion.ReputationScore, u.Reputaion.Badges),
 		domain.NewBan(u.BanStatus.IsBanned, u.BanStatus.ReasonForBan, u.BanStatus.IsBanIndefinite, u.BanStatus.BanStartDate, u.BanStatus.BanEndDate, u.BanStatus.BannedAt),
 	)
+	if u.PasswordHash != "" {
+		_ = user.SetPasswordHash(u.PasswordHash)
+	}
+	if u.EmailVerifiedAt != nil {
+		user.SetEmailVerifiedAt(*u.EmailVerifiedAt)
+	}
+	if u.Deletion != nil {
+		user.SetDeletion(u.Deletion.RequestedAt, u.Deletion.DueAt)
+	}
+	return user
+}
+
+// deletionDueAt is nil unless the user asked for the account to be deleted
+func (u userDocument) deletionDueAt() *time.Time {
+	if u.Deletion == nil {
+		return nil
+	}
+	dueAt := u.Deletion.DueAt
+	return &dueAt
+}
+
+// roles reads the roles of the user, falling back to the single role of older documents
+func (u userDocument) roles() []rbac.UserRole {
+	names := u.Roles
+	if len(names) == 0 && u.Role != "" {
+		names = []string{u.Role}
+	}
+	roles := make([]rbac.UserRole, len(names))
+	for i, name := range names {
+		roles[i] = rbac.UserRole(name)
+	}
+	return roles
+}
+
+func roleNames(roles []rbac.UserRole) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.String()
+	}
+	return names
 }
 
 // documentToReadModel converts userDocument to UserReadModel
 func documentToReadModel(doc userDocument) *domain.UserReadModel {
 	return &domain.UserReadModel{
-		Username:  doc.Username,
-		Email:     doc.Email,
-		Id:        doc.ID,
-		Role:      rbac.UserRole(doc.Role),
-		CreatedAt: doc.CreatedAt,
-		UpdatedAt: doc.UpdatedAt,
+		Username:      doc.Username,
+		Email:         doc.Email,
+		EmailVerified: doc.EmailVerifiedAt != nil,
+		DeletionDueAt: doc.deletionDueAt(),
+		Id:            doc.ID,
+		Roles:         doc.roles(),
+		CreatedAt:     doc.CreatedAt,
+		UpdatedAt:     doc.UpdatedAt,
 		Reputation: domain.UserReputation{
 			ReputationScore: doc.Reputaion.ReputationScore,
 			Badges:          doc.Reputaion.Badges,
 		},
 		BanStatus: domain.BanStatus{
-			IsBanned:        doc.BanStatus.IsBanned,
+			IsBanned:        domain.IsBanActive(doc.BanStatus.IsBanned, doc.BanStatus.IsBanIndefinite, doc.BanStatus.BanStartDate, doc.BanStatus.BanEndDate, time.Now()),
 			BannedAt:        doc.BanStatus.BannedAt,
 			BanStartDate:    doc.BanStatus.BanStartDate,
 			BanEndDate:      doc.BanStatus.BanEndDate,
@@ -23,9 +23,9 @@ func NewUserReadModelRepository(db *mongo.Database) *UserReadModelRepository {
 	}
 }
 
-// GetUserByEmail finds a user by their email
+// GetUserByEmail finds a user by their email, regardless of case
 func (r *UserReadModelRepository) GetUserByEmail(ctx context.Context, email string) (*domain.UserReadModel, error) {
-	doc, err := r.getUserBy(ctx, "email", email)
+	doc, err := r.getUserBy(ctx, "email", email, options.FindOne().SetCollation(caseInsensitive))
 	if err != nil {
 		return nil, err
 	}
@@ -41,20 +41,36 @@ func (r *UserReadModelRepository) GetUserById(ctx context.Context, id string) (*
 	return documentToReadModel(*doc), nil
 }
 
-// GetUsers retrieves paginated users sorted by createdAt
+// GetUsers retrieves paginated users sorted by createdAt, oldest first unless opts.SortDirection is DESC
 func (r *UserReadModelRepository) GetUsers(ctx context.Context, opts domain.GetUsersOptions) ([]*domain.UserReadModel, bool, error) {
+	if opts.First <= 0 {
+		return nil, false, domain.ErrInvalidPageSize
+	}
+	sortOrder := 1
+	switch opts.SortDirection {
+	case "", "ASC":
+	case "DESC":
+		sortOrder = -1
+	default:
+		return nil, false, errors.New("invalid sort direction, must be 'ASC' or 'DESC'")
+	}
+
 	findOptions := options.Find()
-	findOptions.SetLimit(int64(opts.First + 1))                //Fetch one more to determine if there are more results
-	findOptions.SetSort(bson.D{{Key: "createdAt", Value: -1}}) //Sort by createdAt descending (newest first)
+	findOptions.SetLimit(int64(opts.First + 1)) //Fetch one more to determine if there are more results
+	findOptions.SetSort(bson.D{{Key: "createdAt", Value: sortOrder}})
 
 	var filter bson.M = bson.M{}
 	if opts.After != "" {
 		// Parse the after cursor (which is a timestamp) for cursor-based pagination
 		createdAt, err := time.Parse(time.RFC3339Nano, opts.After)
 		if err != nil {
-			return nil, false, err
+			return nil, false, errors.New("invalid after timestamp format")
+		}
+		comparison := "$gt"
+		if sortOrder < 0 {
+			comparison = "$lt"
 		}
-		filter = bson.M{"createdAt": bson.M{"$lt": createdAt}}
+		filter = bson.M{"createdAt": bson.M{comparison: createdAt}}
 	}
 
 	cursor, err := r.collection.Find(ctx, filter, findOptions)
@@ -83,9 +99,9 @@ func (r *UserReadModelRepository) GetUsers(ctx context.Context, opts domain.GetU
 }
 
 // GetUserBy finds a user by the specified field and value
-func (r *UserReadModelRepository) getUserBy(ctx context.Context, fieldName string, value any) (*userDocument, error) {
+func (r *UserReadModelRepository) getUserBy(ctx context.Context, fieldName string, value any, opts ...*options.FindOneOptions) (*userDocument, error) {
 	var doc userDocument
-	err := r.collection.FindOne(ctx, bson.M{fmt.Sprintf("%s", fieldName): value}).Decode(&doc)
+	err := r.collection.FindOne(ctx, bson.M{fmt.Sprintf("%s", fieldName): value}, opts...).Decode(&doc)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, domain.ErrUserNotFound
@@ -3,13 +3,22 @@ package mongoimpl
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
+	"github.com/iammrsea/social-app/internal/shared/storage/mongodb"
 	"github.com/iammrsea/social-app/internal/user/domain"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// UserRepository implements the domain.UserRepository interface
+// UserRepository implements the domain.UserRepository interface. Users are saved together with
+// the events they raised, which go to the outbox collection in the same transaction, so the
+// database must run as a replica set. Updates only succeed if the user's version hasn't changed
+// since it was read, otherwise they fail with domain.ErrConcurrentModification. Emails and
+// usernames are unique regardless of case, through the indexes made by CreateIndexes; saving a user
+// who would take someone else's fails with domain.ErrEmailOrUsernameAlreadyExists.
 type UserRepository struct {
 	collection *mongo.Collection
 }
@@ -20,24 +29,24 @@ func NewUserRepository(db *mongo.Database) *UserRepository {
 	}
 }
 
+// caseInsensitive compares strings the way the unique indexes on emails and usernames do
+var caseInsensitive = &options.Collation{Locale: "en", Strength: 2}
+
 // Register adds a new user to the database
 func (r *UserRepository) Register(ctx context.Context, user domain.User) error {
-	doc := fromDomain(user)
-	// Check if user with the same email already exists
-	existingUser := userDocument{}
-	err := r.collection.FindOne(ctx, bson.M{"email": user.Email()}).Decode(&existingUser)
-	if err == nil {
-		return domain.ErrEmailAlreadyExists
-	} else if !errors.Is(err, mongo.ErrNoDocuments) {
-		return err
-	}
-	_, err = r.collection.InsertOne(ctx, doc)
-
-	return err
+	err := r.withTransaction(ctx, func(sessionCtx mongo.SessionContext) error {
+		doc := fromDomain(user)
+		doc.Version = 1
+		if _, err := r.collection.InsertOne(sessionCtx, doc); err != nil {
+			return err
+		}
+		return mongodb.AppendToOutbox(sessionCtx, r.collection.Database(), user.PullEvents())
+	})
+	return mapDuplicateKey(err)
 }
 
-// MakeModerator updates a user to have moderator role
-func (r *UserRepository) MakeModerator(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+// UpdateRoles saves the roles assigned to or removed from a user
+func (r *UserRepository) UpdateRoles(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
 	return r.getAndUpdateUser(ctx, userId, updateFn)
 }
 
@@ -66,10 +75,66 @@ func (r *UserRepository) BanUser(ctx context.Context, userId string, updateFn fu
 	return r.getAndUpdateUser(ctx, userId, updateFn)
 }
 
-// GetUserBy finds a user by a field name and value
+// VerifyEmail marks the email of a user as verified
+func (r *UserRepository) VerifyEmail(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return r.getAndUpdateUser(ctx, userId, updateFn)
+}
+
+// ResetPassword replaces the password of a user
+func (r *UserRepository) ResetPassword(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return r.getAndUpdateUser(ctx, userId, updateFn)
+}
+
+// ScheduleDeletion saves the requested or cancelled deletion of an account
+func (r *UserRepository) ScheduleDeletion(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return r.getAndUpdateUser(ctx, userId, updateFn)
+}
+
+// DeleteUser removes the document of a user once updateFn erased them
+func (r *UserRepository) DeleteUser(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return r.withTransaction(ctx, func(sessionCtx mongo.SessionContext) error {
+		var doc userDocument
+		err := r.collection.FindOne(sessionCtx, bson.M{"_id": userId}).Decode(&doc)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return domain.ErrUserNotFound
+			}
+			return err
+		}
+		user := doc.toDomain()
+		if err := updateFn(&user); err != nil {
+			return err
+		}
+		result, err := r.collection.DeleteOne(sessionCtx, bson.M{"_id": userId, "version": versionFilter(doc.Version)})
+		if err != nil {
+			return err
+		}
+		if result.DeletedCount == 0 {
+			return domain.ErrConcurrentModification
+		}
+		return mongodb.AppendToOutbox(sessionCtx, r.collection.Database(), user.PullEvents())
+	})
+}
+
+// lookupFields are the document fields users can be looked up by with GetUserBy
+var lookupFields = map[string]string{
+	"id":       "_id",
+	"email":    "email",
+	"username": "username",
+}
+
+// GetUserBy finds a user by id, email or username. Emails and usernames match regardless of case.
 func (r *UserRepository) GetUserBy(ctx context.Context, fieldName string, value any) (*domain.User, error) {
+	field, ok := lookupFields[fieldName]
+	if !ok {
+		return nil, fmt.Errorf("users can't be looked up by %s", fieldName)
+	}
+	opts := options.FindOne()
+	if field != "_id" {
+		opts.SetCollation(caseInsensitive)
+	}
 	var doc userDocument
-	err := r.collection.FindOne(ctx, bson.M{fieldName: value}).Decode(&doc)
+	err := r.collection.FindOne(ctx, bson.M{field: value}, opts).Decode(&doc)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, domain.ErrUserNotFound
@@ -80,7 +145,8 @@ func (r *UserRepository) GetUserBy(ctx context.Context, fieldName string, value
 	return &user, nil
 }
 
-// UserExists finds a user by a field name and value
+// UserExists tells whether a user has the email or the username, regardless of case. It is a
+// courtesy check: the unique indexes are what keep two users from taking the same email or username.
 func (r *UserRepository) UserExists(ctx context.Context, email string, username string) (bool, error) {
 	var doc userDocument
 	filter := bson.M{
@@ -90,7 +156,7 @@ func (r *UserRepository) UserExists(ctx context.Context, email string, username
 		},
 	}
 	//find user by email or username
-	err := r.collection.FindOne(ctx, filter).Decode(&doc)
+	err := r.collection.FindOne(ctx, filter, options.FindOne().SetCollation(caseInsensitive)).Decode(&doc)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return false, nil
@@ -100,57 +166,125 @@ func (r *UserRepository) UserExists(ctx context.Context, email string, username
 	return true, nil
 }
 
+// ExpiredBans finds users whose time-boxed ban is over
+func (r *UserRepository) ExpiredBans(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	filter := bson.M{
+		"banStatus.isBanned":        true,
+		"banStatus.isBanIndefinite": false,
+		"banStatus.banEndDate":      bson.M{"$lte": now},
+	}
+	opts := options.Find().
+		SetProjection(bson.M{"_id": 1}).
+		SetSort(bson.M{"banStatus.banEndDate": 1}).
+		SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	ids := []string{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID string `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		ids = append(ids, doc.ID)
+	}
+	return ids, cursor.Err()
+}
+
+// DueDeletions finds users whose grace period before the deletion of their account is over
+func (r *UserRepository) DueDeletions(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	filter := bson.M{"deletion.dueAt": bson.M{"$lte": now}}
+	opts := options.Find().
+		SetProjection(bson.M{"_id": 1}).
+		SetSort(bson.M{"deletion.dueAt": 1}).
+		SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	ids := []string{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID string `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		ids = append(ids, doc.ID)
+	}
+	return ids, cursor.Err()
+}
+
 // getAndUpdateUser is a helper function for updating user documents
 func (r *UserRepository) getAndUpdateUser(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
-	// Start a session and transaction
-	// session, err := r.collection.Database().Client().StartSession()
-	// if err != nil {
-	// 	return err
-	// }
-	// defer session.EndSession(ctx)
-
-	// callback := func(sessionCtx mongo.SessionContext) (any, error) {
-	// 	// Get the current user
-	// 	var doc userDocument
-	// 	err := r.collection.FindOne(sessionCtx, bson.M{"_id": userId}).Decode(&doc)
-	// 	if err != nil {
-	// 		if errors.Is(err, mongo.ErrNoDocuments) {
-	// 			return nil, domain.ErrUserNotFound
-	// 		}
-	// 		return nil, err
-	// 	}
-	// 	// Convert to domain model
-	// 	user := doc.toDomain()
-	// 	//Apply the update function
-	// 	err = updateFn(&user)
-	// 	if err != nil {
-	// 		return nil, err
-	// 	}
-	// 	//Convert back to document and update
-	// 	updatedDoc := fromDomain(user)
-	// 	_, err = r.collection.ReplaceOne(sessionCtx, bson.M{"_id": userId}, updatedDoc)
-	// 	return nil, err
-	// }
-	// _, err = session.WithTransaction(ctx, callback)
-
-	// Get the current user
-	var doc userDocument
-	err := r.collection.FindOne(ctx, bson.M{"_id": userId}).Decode(&doc)
-	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return domain.ErrUserNotFound
+	err := r.withTransaction(ctx, func(sessionCtx mongo.SessionContext) error {
+		// Get the current user
+		var doc userDocument
+		err := r.collection.FindOne(sessionCtx, bson.M{"_id": userId}).Decode(&doc)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return domain.ErrUserNotFound
+			}
+			return err
 		}
-		return err
+		// Convert to domain model
+		user := doc.toDomain()
+		//Apply the update function
+		if err := updateFn(&user); err != nil {
+			return err
+		}
+		//Convert back to document and update
+		updatedDoc := fromDomain(user)
+		updatedDoc.UpdatedAt = time.Now()
+		updatedDoc.Version = doc.Version + 1
+		result, err := r.collection.ReplaceOne(sessionCtx, bson.M{"_id": userId, "version": versionFilter(doc.Version)}, updatedDoc)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return domain.ErrConcurrentModification
+		}
+		return mongodb.AppendToOutbox(sessionCtx, r.collection.Database(), user.PullEvents())
+	})
+	return mapDuplicateKey(err)
+}
+
+// mapDuplicateKey turns the error of a write that would give two users the same email or username
+// into domain.ErrEmailOrUsernameAlreadyExists. Other duplicate keys, such as a taken id, are
+// returned as they are.
+func mapDuplicateKey(err error) error {
+	var serverErr mongo.ServerError
+	if mongo.IsDuplicateKeyError(err) && errors.As(err, &serverErr) &&
+		(serverErr.HasErrorMessage("index: "+emailIndex+" ") || serverErr.HasErrorMessage("index: "+usernameIndex+" ")) {
+		return domain.ErrEmailOrUsernameAlreadyExists
 	}
-	// Convert to domain model
-	user := doc.toDomain()
-	//Apply the update function
-	err = updateFn(&user)
+	return err
+}
+
+// withTransaction runs fn in a transaction, retrying it on transient errors
+func (r *UserRepository) withTransaction(ctx context.Context, fn func(sessionCtx mongo.SessionContext) error) error {
+	session, err := r.collection.Database().Client().StartSession()
 	if err != nil {
 		return err
 	}
-	//Convert back to document and update
-	updatedDoc := fromDomain(user)
-	_, err = r.collection.ReplaceOne(ctx, bson.M{"_id": userId}, updatedDoc)
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (any, error) {
+		return nil, fn(sessionCtx)
+	})
 	return err
 }
+
+// versionFilter matches the version a user was read at. Users saved before versioning have no
+// version field and are read as version 0.
+func versionFilter(version int) any {
+	if version == 0 {
+		return bson.M{"$exists": false}
+	}
+	return version
+}
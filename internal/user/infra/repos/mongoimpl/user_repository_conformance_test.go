@@ -0,0 +1,20 @@
+//go:build integration
+// +build integration
+
+package mongoimpl
+
+import (
+	"testing"
+
+	"github.com/iammrsea/social-app/internal/testutil"
+	"github.com/iammrsea/social-app/internal/user/infra/repos/repotest"
+)
+
+func TestConformance(t *testing.T) {
+	client := testutil.SetupTestMongoDb(t)
+	repotest.Run(t, func(t *testing.T) repotest.Repos {
+		db, cleanup := setUpDB(t, client)
+		t.Cleanup(cleanup)
+		return repotest.Repos{Users: getUserRepo(t, db), ReadModels: NewUserReadModelRepository(db)}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"testing"
 	"time"
 
@@ -47,9 +48,9 @@ func TestUserRepository(t *testing.T) {
 		testUnbanUser(t, client)
 	})
 
-	t.Run("MakeModerator", func(t *testing.T) {
+	t.Run("UpdateRoles", func(t *testing.T) {
 		t.Parallel()
-		testMakeModerator(t, client)
+		testUpdateRoles(t, client)
 	})
 
 	t.Run("RevokeAwardedBadge", func(t *testing.T) {
@@ -106,6 +107,17 @@ func testUserExists(t *testing.T, client *mongo.Client) {
 		require.NoError(t, err)
 		assert.True(t, exists)
 	})
+	t.Run("UserExistsRegardlessOfCase", func(t *testing.T) {
+		t.Parallel()
+		db, cleanup := setUpDB(t, client)
+		defer cleanup()
+		repo := getUserRepo(t, db)
+		user := addUserToDB(t, db, nil)
+
+		exists, err := repo.UserExists(context.Background(), strings.ToUpper(user.Email()), "")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
 	t.Run("UserDoesNotExist", func(t *testing.T) {
 		t.Parallel()
 		db, cleanup := setUpDB(t, client)
@@ -126,7 +138,7 @@ func testRevokeAwardedBadge(t *testing.T, client *mongo.Client) {
 		cuid.New(),
 		fmt.Sprintf("%s@example.com", cuid.New()),
 		fmt.Sprintf("username_%s", cuid.New()),
-		rbac.Regular,
+		[]rbac.UserRole{rbac.Regular},
 		time.Now(),
 		time.Now(),
 		domain.MustNewUserReputation(0, []string{badge}),
@@ -143,19 +155,19 @@ func testRevokeAwardedBadge(t *testing.T, client *mongo.Client) {
 	assert.NotContains(t, updatedUser.Reputaion.Badges, badge)
 }
 
-func testMakeModerator(t *testing.T, client *mongo.Client) {
+func testUpdateRoles(t *testing.T, client *mongo.Client) {
 	db, cleanup := setUpDB(t, client)
 	defer cleanup()
 
 	user := addUserToDB(t, db, nil)
 	repo := getUserRepo(t, db)
 
-	err := repo.MakeModerator(context.Background(), user.Id(), func(u *domain.User) error {
-		return u.MakeModerator()
+	err := repo.UpdateRoles(context.Background(), user.Id(), func(u *domain.User) error {
+		return u.AssignRole(rbac.Moderator)
 	})
 	require.NoError(t, err)
 	updatedUser := getUser(t, db, user.Id())
-	assert.Equal(t, rbac.Moderator.String(), updatedUser.Role)
+	assert.Equal(t, []string{rbac.Regular.String(), rbac.Moderator.String()}, updatedUser.Roles)
 }
 
 func testUnbanUser(t *testing.T, client *mongo.Client) {
@@ -166,7 +178,7 @@ func testUnbanUser(t *testing.T, client *mongo.Client) {
 		cuid.New(),
 		fmt.Sprintf("%s@example.com", cuid.New()),
 		fmt.Sprintf("username_%s", cuid.New()),
-		rbac.Regular,
+		[]rbac.UserRole{rbac.Regular},
 		time.Now(),
 		time.Now(),
 		nil,
@@ -194,7 +206,7 @@ func testRegisterUser(t *testing.T, client *mongo.Client) {
 		cuid.New(),
 		fmt.Sprintf("%s@example.com", cuid.New()),
 		fmt.Sprintf("username_%s", cuid.New()),
-		rbac.Regular,
+		[]rbac.UserRole{rbac.Regular},
 		time.Now(),
 		time.Now(),
 		nil,
@@ -202,11 +214,17 @@ func testRegisterUser(t *testing.T, client *mongo.Client) {
 
 	require.NoError(t, err)
 
-	repo := NewUserRepository(db)
-	require.NotNil(t, repo)
+	repo := getUserRepo(t, db)
 	err = repo.Register(context.Background(), user)
 	require.NoError(t, err)
 	assertUserInDB(t, db, user)
+
+	// The same email in other letter case is taken
+	duplicate, err := domain.NewUser(cuid.New(), strings.ToUpper(user.Email()), fmt.Sprintf("username_%s", cuid.New()),
+		[]rbac.UserRole{rbac.Regular}, time.Now(), time.Now(), nil, nil)
+	require.NoError(t, err)
+	err = repo.Register(context.Background(), duplicate)
+	assert.ErrorIs(t, err, domain.ErrEmailOrUsernameAlreadyExists)
 }
 func testChangeUsername(t *testing.T, client *mongo.Client) {
 	db, cleanup := setUpDB(t, client)
@@ -284,7 +302,7 @@ func addUserToDB(t *testing.T, db *mongo.Database, user *domain.User) domain.Use
 			cuid.New(),
 			fmt.Sprintf("%s@example.com", cuid.New()),
 			fmt.Sprintf("username_%s", cuid.New()),
-			rbac.Regular,
+			[]rbac.UserRole{rbac.Regular},
 			time.Now(),
 			time.Now(),
 			nil,
@@ -300,6 +318,7 @@ func addUserToDB(t *testing.T, db *mongo.Database, user *domain.User) domain.Use
 
 func getUserRepo(t *testing.T, db *mongo.Database) *UserRepository {
 	t.Helper()
+	require.NoError(t, CreateIndexes(context.Background(), db))
 	repo := NewUserRepository(db)
 	require.NotNil(t, repo)
 	return repo
@@ -328,7 +347,7 @@ func assertUserInDB(t *testing.T, db *mongo.Database, user domain.User) {
 	assert.Equal(t, user.Id(), foundUser.ID)
 	assert.Equal(t, user.Email(), foundUser.Email)
 	assert.Equal(t, user.Username(), foundUser.Username)
-	assert.Equal(t, user.Role().String(), foundUser.Role)
+	assert.Equal(t, user.Roles(), foundUser.roles())
 	assert.Equal(t, user.ReputationScore(), foundUser.Reputaion.ReputationScore)
 	assert.Equal(t, user.JoinedAt().UTC().Format(time.RFC3339), foundUser.CreatedAt.UTC().Format(time.RFC3339))
 	assert.Equal(t, user.UpdatedAt().UTC().Format(time.RFC3339), foundUser.UpdatedAt.UTC().Format(time.RFC3339))
@@ -0,0 +1,82 @@
+package mongoimpl
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// userValidator rejects users saved without the fields every user has. Documents saved before the
+// validator are only checked once they are valid, so older users can still be updated.
+var userValidator = bson.M{"$jsonSchema": bson.M{
+	"bsonType": "object",
+	"required": []string{"_id", "email", "username", "roles", "createdAt"},
+	"properties": bson.M{
+		"_id":       bson.M{"bsonType": "string"},
+		"email":     bson.M{"bsonType": "string", "minLength": 1},
+		"username":  bson.M{"bsonType": "string", "minLength": 1},
+		"roles":     bson.M{"bsonType": "array", "minItems": 1, "items": bson.M{"bsonType": "string"}},
+		"createdAt": bson.M{"bsonType": "date"},
+	},
+}}
+
+// The unique indexes on the emails and usernames of users, regardless of case
+const (
+	emailIndex    = "email_unique"
+	usernameIndex = "username_unique"
+)
+
+// CreateIndexes makes the indexes the users collection relies on. Existing indexes are left as
+// they are, so it can be run again.
+func CreateIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("users").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetName(emailIndex).SetUnique(true).SetCollation(caseInsensitive),
+		},
+		{
+			Keys:    bson.D{{Key: "username", Value: 1}},
+			Options: options.Index().SetName(usernameIndex).SetUnique(true).SetCollation(caseInsensitive),
+		},
+	})
+	return err
+}
+
+// DropIndexes drops the indexes made by CreateIndexes
+func DropIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := db.Collection("users").Indexes()
+	for _, name := range []string{emailIndex, usernameIndex} {
+		if _, err := indexes.DropOne(ctx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetValidator makes MongoDB check users before saving them, creating the users collection if
+// there is none yet
+func SetValidator(ctx context.Context, db *mongo.Database) error {
+	names, err := db.ListCollectionNames(ctx, bson.M{"name": "users"})
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		opts := options.CreateCollection().SetValidator(userValidator).SetValidationLevel("moderate")
+		return db.CreateCollection(ctx, "users", opts)
+	}
+	return db.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: "users"},
+		{Key: "validator", Value: userValidator},
+		{Key: "validationLevel", Value: "moderate"},
+	}).Err()
+}
+
+// RemoveValidator stops MongoDB from checking users
+func RemoveValidator(ctx context.Context, db *mongo.Database) error {
+	return db.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: "users"},
+		{Key: "validator", Value: bson.M{}},
+	}).Err()
+}
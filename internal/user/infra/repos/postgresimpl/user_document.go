@@ -14,7 +14,7 @@ type userDocument struct {
 	ID              string    `db:"id"`
 	Email           string    `db:"email"`
 	Username        string    `db:"username"`
-	Role            string    `db:"role"`
+	Roles           []string  `db:"roles"`
 	ReputationScore int       `db:"reputation_score"`
 	Badges          []string  `db:"badges"`
 	IsBanned        bool      `db:"is_banned"`
@@ -23,8 +23,16 @@ type userDocument struct {
 	BanEndDate      time.Time `db:"ban_end_date"`
 	ReasonForBan    string    `db:"reason_for_ban"`
 	IsBanIndefinite bool      `db:"is_ban_indefinite"`
+	PasswordHash    string    `db:"password_hash"`
 	CreatedAt       time.Time `db:"created_at"`
 	UpdatedAt       time.Time `db:"updated_at"`
+	// EmailVerifiedAt is NULL until the user verifies the email
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
+	// Version is incremented on every write and checked before updating
+	Version int `db:"version"`
+	// DeletionDueAt is NULL unless the user asked for the account to be deleted
+	DeletionRequestedAt *time.Time `db:"deletion_requested_at"`
+	DeletionDueAt       *time.Time `db:"deletion_due_at"`
 }
 
 // Helper function to determine the comparison operator based on sort direction
@@ -37,11 +45,11 @@ func getComparisonOperator(sortDirection string) string {
 
 // toDomain converts a userDocument to a domain.User
 func (u *userDocument) toDomain() domain.User {
-	return domain.MustNewUser(
+	user := domain.MustNewUser(
 		u.ID,
 		u.Email,
 		u.Username,
-		rbac.UserRole(u.Role),
+		toRoles(u.Roles),
 		u.CreatedAt,
 		u.UpdatedAt,
 		domain.MustNewUserReputation(u.ReputationScore, u.Badges),
@@ -54,23 +62,68 @@ func (u *userDocument) toDomain() domain.User {
 			u.BannedAt,
 		),
 	)
+	if u.PasswordHash != "" {
+		_ = user.SetPasswordHash(u.PasswordHash)
+	}
+	if u.EmailVerifiedAt != nil {
+		user.SetEmailVerifiedAt(*u.EmailVerifiedAt)
+	}
+	if u.DeletionRequestedAt != nil && u.DeletionDueAt != nil {
+		user.SetDeletion(*u.DeletionRequestedAt, *u.DeletionDueAt)
+	}
+	return user
+}
+
+func toRoles(names []string) []rbac.UserRole {
+	roles := make([]rbac.UserRole, len(names))
+	for i, name := range names {
+		roles[i] = rbac.UserRole(name)
+	}
+	return roles
+}
+
+func roleNames(roles []rbac.UserRole) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.String()
+	}
+	return names
+}
+
+// emailVerifiedAt stores unverified emails as NULL
+func emailVerifiedAt(user domain.User) *time.Time {
+	if !user.IsEmailVerified() {
+		return nil
+	}
+	verifiedAt := user.EmailVerifiedAt()
+	return &verifiedAt
+}
+
+// optionalTime stores the zero time as NULL
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
 }
 
 // documentToReadModel converts userDocument to UserReadModel
 func documentToReadModel(doc userDocument) *domain.UserReadModel {
 	return &domain.UserReadModel{
-		Username:  doc.Username,
-		Email:     doc.Email,
-		Id:        doc.ID,
-		Role:      rbac.UserRole(doc.Role),
-		CreatedAt: doc.CreatedAt,
-		UpdatedAt: doc.UpdatedAt,
+		Username:      doc.Username,
+		Email:         doc.Email,
+		EmailVerified: doc.EmailVerifiedAt != nil,
+		DeletionDueAt: doc.DeletionDueAt,
+		Id:            doc.ID,
+		Roles:         toRoles(doc.Roles),
+		CreatedAt:     doc.CreatedAt,
+		UpdatedAt:     doc.UpdatedAt,
 		Reputation: domain.UserReputation{
 			ReputationScore: doc.ReputationScore,
 			Badges:          doc.Badges,
 		},
 		BanStatus: domain.BanStatus{
-			IsBanned:        doc.IsBanned,
+			IsBanned:        domain.IsBanActive(doc.IsBanned, doc.IsBanIndefinite, doc.BanStartDate, doc.BanEndDate, time.Now()),
 			BannedAt:        doc.BannedAt,
 			BanStartDate:    doc.BanStartDate,
 			BanEndDate:      doc.BanEndDate,
@@ -81,11 +134,30 @@ func documentToReadModel(doc userDocument) *domain.UserReadModel {
 }
 
 func scanUserRow(row pgx.Row, doc *userDocument) error {
-	err := row.Scan(
+	return scanUserRowInto(row, doc.scanTargets()...)
+}
+
+// scanStoredUserRow scans a row selected with storedUserColumns
+func scanStoredUserRow(row pgx.Row, doc *userDocument) error {
+	return scanUserRowInto(row, append(doc.scanTargets(), &doc.PasswordHash, &doc.Version, &doc.DeletionRequestedAt)...)
+}
+
+func scanUserRowInto(row pgx.Row, dest ...any) error {
+	if err := row.Scan(dest...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (doc *userDocument) scanTargets() []any {
+	return []any{
 		&doc.ID,
 		&doc.Username,
 		&doc.Email,
-		&doc.Role,
+		&doc.Roles,
 		&doc.ReputationScore,
 		&doc.Badges,
 		&doc.IsBanned,
@@ -96,12 +168,7 @@ func scanUserRow(row pgx.Row, doc *userDocument) error {
 		&doc.IsBanIndefinite,
 		&doc.CreatedAt,
 		&doc.UpdatedAt,
-	)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.ErrUserNotFound
-		}
-		return err
+		&doc.EmailVerifiedAt,
+		&doc.DeletionDueAt,
 	}
-	return nil
 }
@@ -20,6 +20,9 @@ func NewUserReadModelRepository(db *pgxpool.Pool) *UserReadModelRepository {
 }
 
 func (r *UserReadModelRepository) GetUsers(ctx context.Context, opts domain.GetUsersOptions) (users []*domain.UserReadModel, hasNext bool, err error) {
+	if opts.First <= 0 {
+		return nil, false, domain.ErrInvalidPageSize
+	}
 	// Validate sort direction
 	sortDirection := "ASC" // Default to ascending
 	if opts.SortDirection != "" {
@@ -29,11 +32,11 @@ func (r *UserReadModelRepository) GetUsers(ctx context.Context, opts domain.GetU
 		sortDirection = opts.SortDirection
 	}
 
-	// Base query with dynamic ORDER BY
+	// Base query with dynamic ORDER BY. The user standing in for deleted users isn't listed.
 	query := fmt.Sprintf(`
-        SELECT id, username, email, role, reputation_score, badges, is_banned, created_at, updated_at
+        SELECT `+userColumns+`
         FROM users
-        WHERE ($1::TIMESTAMP IS NULL OR created_at %s $1)
+        WHERE id <> $3 AND ($1::TIMESTAMP IS NULL OR created_at %s $1)
         ORDER BY created_at %s
         LIMIT $2
     `, getComparisonOperator(sortDirection), sortDirection)
@@ -45,11 +48,13 @@ func (r *UserReadModelRepository) GetUsers(ctx context.Context, opts domain.GetU
 		if err != nil {
 			return nil, false, errors.New("invalid after timestamp format")
 		}
+		// created_at holds UTC times without a zone, pgx would compare the cursor's wall clock instead
+		parsedTime = parsedTime.UTC()
 		afterTimestamp = &parsedTime
 	}
 
 	// Execute query
-	rows, err := r.db.Query(ctx, query, afterTimestamp, opts.First+1) // Fetch one extra row to check for "hasNext"
+	rows, err := r.db.Query(ctx, query, afterTimestamp, opts.First+1, domain.DeletedUserId) // Fetch one extra row to check for "hasNext"
 	if err != nil {
 		return nil, false, err
 	}
@@ -58,22 +63,14 @@ func (r *UserReadModelRepository) GetUsers(ctx context.Context, opts domain.GetU
 	// Parse results
 	for rows.Next() {
 		var user userDocument
-		err := rows.Scan(
-			&user.ID,
-			&user.Username,
-			&user.Email,
-			&user.Role,
-			&user.ReputationScore,
-			&user.Badges,
-			&user.IsBanned,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
-		if err != nil {
+		if err := scanUserRow(rows, &user); err != nil {
 			return nil, false, err
 		}
 		users = append(users, documentToReadModel(user))
 	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
 
 	// Determine if there is a next page
 	hasNext = len(users) > int(opts.First)
@@ -86,8 +83,7 @@ func (r *UserReadModelRepository) GetUsers(ctx context.Context, opts domain.GetU
 
 func (r *UserReadModelRepository) GetUserById(ctx context.Context, id string) (*domain.UserReadModel, error) {
 	query := `
-        SELECT id, username, email, role, reputation_score, badges, is_banned, banned_at,
-            ban_start_date, ban_end_date, reason_for_ban, is_ban_indefinite, created_at, updated_at
+        SELECT ` + userColumns + `
         FROM users WHERE id = $1
     `
 	row := r.db.QueryRow(ctx, query, id)
@@ -107,9 +103,8 @@ func (r *UserReadModelRepository) GetUserById(ctx context.Context, id string) (*
 
 func (r *UserReadModelRepository) GetUserByEmail(ctx context.Context, email string) (*domain.UserReadModel, error) {
 	query := `
-        SELECT id, username, email, role, reputation_score, badges, is_banned, banned_at,
-            ban_start_date, ban_end_date, reason_for_ban, is_ban_indefinite, created_at, updated_at
-        FROM users WHERE email = $1
+        SELECT ` + userColumns + `
+        FROM users WHERE lower(email) = lower($1)
     `
 	row := r.db.QueryRow(ctx, query, email)
 	var doc userDocument
@@ -0,0 +1,166 @@
+//go:build integration
+// +build integration
+
+package postgresimpl
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/testutil"
+	"github.com/iammrsea/social-app/internal/user/domain"
+	"github.com/lucsky/cuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserReadModelRepository(t *testing.T) {
+	t.Parallel()
+	db := testutil.SetupTestPostgres(t)
+	repo := NewUserRepository(db)
+	readModels := NewUserReadModelRepository(db)
+
+	t.Run("GetUserById", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		user := registerUser(t, repo)
+
+		found, err := readModels.GetUserById(ctx, user.Id())
+		require.NoError(t, err)
+		assert.Equal(t, user.Id(), found.Id)
+		assert.Equal(t, user.Email(), found.Email)
+		assert.Equal(t, user.Username(), found.Username)
+		assert.Equal(t, []rbac.UserRole{rbac.Regular}, found.Roles)
+		assert.Equal(t, user.JoinedAt(), found.CreatedAt)
+		assert.False(t, found.EmailVerified)
+		assert.False(t, found.BanStatus.IsBanned)
+		assert.Nil(t, found.DeletionDueAt)
+
+		_, err = readModels.GetUserById(ctx, cuid.New())
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("GetUserByEmail", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		user := registerUser(t, repo)
+
+		found, err := readModels.GetUserByEmail(ctx, user.Email())
+		require.NoError(t, err)
+		assert.Equal(t, user.Id(), found.Id)
+
+		_, err = readModels.GetUserByEmail(ctx, "nobody@example.com")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("the user standing in for deleted users", func(t *testing.T) {
+		t.Parallel()
+		found, err := readModels.GetUserById(context.Background(), domain.DeletedUserId)
+		require.NoError(t, err)
+		assert.Equal(t, []rbac.UserRole{rbac.Guest}, found.Roles)
+		assert.False(t, found.BanStatus.IsBanned)
+		assert.True(t, found.BanStatus.BannedAt.IsZero())
+		assert.Empty(t, found.BanStatus.ReasonForBan)
+	})
+
+	t.Run("GetUsers", func(t *testing.T) {
+		t.Parallel()
+		testGetUsers(t)
+	})
+}
+
+func testGetUsers(t *testing.T) {
+	ctx := context.Background()
+	// Listings see every user, so they get a database of their own
+	db := testutil.SetupTestPostgres(t)
+	repo := NewUserRepository(db)
+	readModels := NewUserReadModelRepository(db)
+
+	users, hasNext, err := readModels.GetUsers(ctx, domain.GetUsersOptions{First: 10})
+	require.NoError(t, err)
+	assert.Empty(t, users, "the user standing in for deleted users isn't listed")
+	assert.False(t, hasNext)
+
+	// Users joined a microsecond apart, the precision Postgres keeps
+	start := now().Add(-time.Hour)
+	ids := make([]string, 5)
+	for i := range ids {
+		id := cuid.New()
+		user, err := domain.NewUser(id, id+"@example.com", "user_"+id, []rbac.UserRole{rbac.Regular},
+			start.Add(time.Duration(i)*time.Microsecond), start, nil, nil)
+		require.NoError(t, err)
+		require.NoError(t, repo.Register(ctx, user))
+		ids[i] = id
+	}
+	reversed := []string{ids[4], ids[3], ids[2], ids[1], ids[0]}
+
+	for _, direction := range []string{"ASC", "DESC"} {
+		t.Run("walks every page "+direction, func(t *testing.T) {
+			want := ids
+			if direction == "DESC" {
+				want = reversed
+			}
+			var seen []string
+			after := ""
+			for page := 0; ; page++ {
+				require.Less(t, page, len(ids), "the listing ends")
+				users, hasNext, err := readModels.GetUsers(ctx, domain.GetUsersOptions{First: 2, After: after, SortDirection: direction})
+				require.NoError(t, err)
+				for _, user := range users {
+					seen = append(seen, user.Id)
+				}
+				if !hasNext {
+					break
+				}
+				after = users[len(users)-1].CreatedAt.Format(time.RFC3339Nano)
+			}
+			assert.Equal(t, want, seen, "every user is listed once, in order")
+		})
+	}
+
+	cursor := func(t time.Time) string {
+		return t.Format(time.RFC3339Nano)
+	}
+	tests := []struct {
+		name    string
+		opts    domain.GetUsersOptions
+		want    []string
+		hasNext bool
+	}{
+		{"empty page", domain.GetUsersOptions{First: 0}, nil, true},
+		{"cursor between two users", domain.GetUsersOptions{First: 2, After: cursor(start.Add(1500 * time.Nanosecond))}, ids[2:4], true},
+		{"cursor before every user", domain.GetUsersOptions{First: 10, After: cursor(start.Add(-time.Hour))}, ids, false},
+		{"cursor after every user", domain.GetUsersOptions{First: 10, After: cursor(start.Add(time.Hour))}, nil, false},
+		{"cursor before every user newest first", domain.GetUsersOptions{First: 10, After: cursor(start.Add(-time.Hour)), SortDirection: "DESC"}, nil, false},
+		{"cursor in another time zone", domain.GetUsersOptions{First: 2, After: cursor(start.Add(2 * time.Microsecond).In(time.FixedZone("UTC+2", 2*60*60)))}, ids[3:5], false},
+		{"cursor without fractional seconds", domain.GetUsersOptions{First: 10, After: start.Truncate(time.Second).Add(-time.Second).Format(time.RFC3339)}, ids, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, hasNext, err := readModels.GetUsers(ctx, tt.opts)
+			require.NoError(t, err)
+			got := make([]string, len(users))
+			for i, user := range users {
+				got[i] = user.Id
+			}
+			if tt.want == nil {
+				assert.Empty(t, got)
+			} else {
+				assert.Equal(t, tt.want, got)
+			}
+			assert.Equal(t, tt.hasNext, hasNext)
+		})
+	}
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, _, err := readModels.GetUsers(ctx, domain.GetUsersOptions{First: 2, SortDirection: "asc"})
+		assert.ErrorContains(t, err, "invalid sort direction")
+		_, _, err = readModels.GetUsers(ctx, domain.GetUsersOptions{First: 2, After: "yesterday"})
+		assert.ErrorContains(t, err, "invalid after timestamp format")
+		_, _, err = readModels.GetUsers(ctx, domain.GetUsersOptions{First: 2, After: strings.Repeat("9", 40)})
+		assert.ErrorContains(t, err, "invalid after timestamp format")
+	})
+}
@@ -2,13 +2,40 @@ package postgresimpl
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/iammrsea/social-app/internal/shared/storage/postgres"
 	"github.com/iammrsea/social-app/internal/user/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// uniqueViolation is the SQLSTATE of an insert or update that breaks a unique index
+const uniqueViolation = "23505"
+
+// The unique indexes on the emails and usernames of users, regardless of case
+const (
+	emailIndex    = "idx_users_email_lower"
+	usernameIndex = "idx_users_username_lower"
+)
+
+// userColumns are what is shown to readers. Users who were never banned may have NULL ban columns,
+// like the user standing in for deleted users, which are read as the zero time and the empty reason.
+const userColumns = `id, username, email, roles, reputation_score, badges, is_banned,
+            COALESCE(banned_at, '0001-01-01'), COALESCE(ban_start_date, '0001-01-01'), COALESCE(ban_end_date, '0001-01-01'),
+            COALESCE(reason_for_ban, ''), is_ban_indefinite, created_at, updated_at, email_verified_at, deletion_due_at`
+
+// storedUserColumns are the columns the write side needs on top of what is shown to readers
+const storedUserColumns = userColumns + `, password_hash, version, deletion_requested_at`
+
+// UserRepository saves users together with the events they raised, which go to the outbox table
+// in the same transaction. Updates only succeed if the user's version hasn't changed since it was
+// read, otherwise they fail with domain.ErrConcurrentModification. Emails and usernames are unique
+// regardless of case; saving a user who would take someone else's fails with
+// domain.ErrEmailOrUsernameAlreadyExists.
 type UserRepository struct {
 	db *pgxpool.Pool
 }
@@ -20,30 +47,39 @@ func NewUserRepository(db *pgxpool.Pool) *UserRepository {
 func (r *UserRepository) Register(ctx context.Context, user domain.User) error {
 	query := `
         INSERT INTO users (
-            id, username, email, role, reputation_score, badges, is_banned, banned_at,
-            ban_start_date, ban_end_date, reason_for_ban, is_ban_indefinite, created_at, updated_at
-        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+            id, username, email, roles, reputation_score, badges, is_banned, banned_at,
+            ban_start_date, ban_end_date, reason_for_ban, is_ban_indefinite, password_hash, created_at, updated_at,
+            email_verified_at
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
     `
-	_, err := r.db.Exec(ctx, query,
-		user.Id(),
-		user.Username(),
-		user.Email(),
-		user.Role().String(),
-		user.ReputationScore(),
-		user.Badges(),
-		user.IsBanned(),
-		user.BannedAt(),
-		user.BanStartDate(),
-		user.BanEndDate(),
-		user.ReasonForBan(),
-		user.IsBanIndefinite(),
-		user.JoinedAt(),
-		user.UpdatedAt(),
-	)
-	return err
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, query,
+			user.Id(),
+			user.Username(),
+			user.Email(),
+			roleNames(user.Roles()),
+			user.ReputationScore(),
+			user.Badges(),
+			user.HasBan(),
+			user.BannedAt(),
+			user.BanStartDate(),
+			user.BanEndDate(),
+			user.ReasonForBan(),
+			user.IsBanIndefinite(),
+			user.PasswordHash(),
+			user.JoinedAt(),
+			user.UpdatedAt(),
+			emailVerifiedAt(user),
+		)
+		if err != nil {
+			return err
+		}
+		return postgres.AppendToOutbox(ctx, tx, user.PullEvents())
+	})
+	return mapUniqueViolation(err)
 }
 
-func (r *UserRepository) MakeModerator(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+func (r *UserRepository) UpdateRoles(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
 	return r.updateUser(ctx, userId, updateFn)
 }
 
@@ -63,26 +99,125 @@ func (r *UserRepository) BanUser(ctx context.Context, userId string, updateFn fu
 	return r.updateUser(ctx, userId, updateFn)
 }
 
+func (r *UserRepository) VerifyEmail(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return r.updateUser(ctx, userId, updateFn)
+}
+
+func (r *UserRepository) ResetPassword(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return r.updateUser(ctx, userId, updateFn)
+}
+
 func (r *UserRepository) UnbanUser(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
 	return r.updateUser(ctx, userId, updateFn)
 }
 
-// UserExists finds a user by a field name and value
+func (r *UserRepository) ScheduleDeletion(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return r.updateUser(ctx, userId, updateFn)
+}
+
+// DeleteUser deletes the row of the user. Their sessions, API keys and two-factor secrets go with
+// it, the rest of what references the user must have been handed over to domain.DeletedUserId.
+func (r *UserRepository) DeleteUser(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var doc userDocument
+		if err := scanStoredUserRow(tx.QueryRow(ctx, `SELECT `+storedUserColumns+` FROM users WHERE id = $1`, userId), &doc); err != nil {
+			return err
+		}
+		user := doc.toDomain()
+		if err := updateFn(&user); err != nil {
+			return err
+		}
+		tag, err := tx.Exec(ctx, `DELETE FROM users WHERE id = $1 AND version = $2`, user.Id(), doc.Version)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrConcurrentModification
+		}
+		return postgres.AppendToOutbox(ctx, tx, user.PullEvents())
+	})
+}
+
+func (r *UserRepository) DueDeletions(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	query := `
+        SELECT id FROM users
+        WHERE deletion_due_at <= $1
+        ORDER BY deletion_due_at
+        LIMIT $2
+    `
+	rows, err := r.db.Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// UserExists tells whether a user has the email or the username, regardless of case. It is a
+// courtesy check: the unique indexes are what keep two users from taking the same email or username.
 func (r *UserRepository) UserExists(ctx context.Context, email string, username string) (bool, error) {
-	panic("implement me")
+	query := `
+        SELECT EXISTS (
+            SELECT 1 FROM users WHERE lower(email) = lower($1) OR lower(username) = lower($2)
+        )
+    `
+	var exists bool
+	if err := r.db.QueryRow(ctx, query, email, username).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func (r *UserRepository) ExpiredBans(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	query := `
+        SELECT id FROM users
+        WHERE is_banned AND NOT is_ban_indefinite AND ban_end_date <= $1
+        ORDER BY ban_end_date
+        LIMIT $2
+    `
+	rows, err := r.db.Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// lookupConditions match users by what GetUserBy can look them up by. Emails and usernames are
+// compared the way the unique indexes on them do, regardless of case.
+var lookupConditions = map[string]string{
+	"id":       "id = $1",
+	"email":    "lower(email) = lower($1)",
+	"username": "lower(username) = lower($1)",
 }
 
+// GetUserBy finds a user by id, email or username
 func (r *UserRepository) GetUserBy(ctx context.Context, fieldName string, value any) (*domain.User, error) {
-	query := fmt.Sprintf(`
-        SELECT id, username, email, role, reputation_score, badges, is_banned, banned_at, ban_start_date, ban_end_date,
-            reason_for_ban, is_ban_indefinite, updated_at, created_at
-        FROM users
-        WHERE %s = $1
-    `, fieldName)
+	condition, ok := lookupConditions[fieldName]
+	if !ok {
+		return nil, fmt.Errorf("users can't be looked up by %s", fieldName)
+	}
+	query := `SELECT ` + storedUserColumns + ` FROM users WHERE ` + condition
 
 	var doc userDocument
 	row := r.db.QueryRow(ctx, query, value)
-	err := scanUserRow(row, &doc)
+	err := scanStoredUserRow(row, &doc)
 	if err != nil {
 		return nil, err
 	}
@@ -91,34 +226,62 @@ func (r *UserRepository) GetUserBy(ctx context.Context, fieldName string, value
 }
 
 func (r *UserRepository) updateUser(ctx context.Context, userId string, updateFn func(user *domain.User) error) error {
-	user, err := r.GetUserBy(ctx, "id", userId)
-	if err != nil {
-		return err
-	}
-	if err := updateFn(user); err != nil {
-		return err
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var doc userDocument
+		if err := scanStoredUserRow(tx.QueryRow(ctx, `SELECT `+storedUserColumns+` FROM users WHERE id = $1`, userId), &doc); err != nil {
+			return err
+		}
+		user := doc.toDomain()
+		if err := updateFn(&user); err != nil {
+			return err
+		}
+		query := `
+            UPDATE users
+            SET username = $1, email = $2, roles = $3, reputation_score = $4, badges = $5,
+                is_banned = $6, banned_at = $7, ban_start_date = $8, ban_end_date = $9,
+                reason_for_ban = $10, is_ban_indefinite = $11, password_hash = $12, updated_at = $13,
+                email_verified_at = $14, deletion_requested_at = $15, deletion_due_at = $16, version = version + 1
+            WHERE id = $17 AND version = $18
+        `
+		tag, err := tx.Exec(ctx, query,
+			user.Username(),
+			user.Email(),
+			roleNames(user.Roles()),
+			user.ReputationScore(),
+			user.Badges(),
+			user.HasBan(),
+			user.BannedAt(),
+			user.BanStartDate(),
+			user.BanEndDate(),
+			user.ReasonForBan(),
+			user.IsBanIndefinite(),
+			user.PasswordHash(),
+			time.Now(),
+			emailVerifiedAt(user),
+			optionalTime(user.DeletionRequestedAt()),
+			optionalTime(user.DeletionDueAt()),
+			user.Id(),
+			doc.Version,
+		)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrConcurrentModification
+		}
+		return postgres.AppendToOutbox(ctx, tx, user.PullEvents())
+	})
+	return mapUniqueViolation(err)
+}
+
+// mapUniqueViolation turns the error of a write that would give two users the same email or
+// username into domain.ErrEmailOrUsernameAlreadyExists. Other unique violations, such as a taken
+// id, are returned as they are.
+func mapUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation &&
+		(pgErr.ConstraintName == emailIndex || pgErr.ConstraintName == usernameIndex) {
+		return domain.ErrEmailOrUsernameAlreadyExists
 	}
-	query := `
-        UPDATE users
-        SET username = $1, email = $2, role = $3, reputation_score = $4, badges = $5,
-            is_banned = $6, banned_at = $7, ban_start_date = $8, ban_end_date = $9,
-            reason_for_ban = $10, is_ban_indefinite = $11, updated_at = $12
-        WHERE id = $13
-    `
-	_, err = r.db.Exec(ctx, query,
-		user.Username(),
-		user.Email(),
-		user.Role().String(),
-		user.ReputationScore(),
-		user.Badges(),
-		user.IsBanned(),
-		user.BannedAt(),
-		user.BanStartDate(),
-		user.BanEndDate(),
-		user.ReasonForBan(),
-		user.IsBanIndefinite(),
-		time.Now(),
-		user.Id(),
-	)
 	return err
 }
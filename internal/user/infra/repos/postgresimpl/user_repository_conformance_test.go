@@ -0,0 +1,18 @@
+//go:build integration
+// +build integration
+
+package postgresimpl
+
+import (
+	"testing"
+
+	"github.com/iammrsea/social-app/internal/testutil"
+	"github.com/iammrsea/social-app/internal/user/infra/repos/repotest"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repos {
+		db := testutil.SetupTestPostgres(t)
+		return repotest.Repos{Users: NewUserRepository(db), ReadModels: NewUserReadModelRepository(db)}
+	})
+}
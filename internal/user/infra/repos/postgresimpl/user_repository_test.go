@@ -0,0 +1,505 @@
+//go:build integration
+// +build integration
+
+package postgresimpl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth/sessions"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/shared/storage/postgres"
+	"github.com/iammrsea/social-app/internal/testutil"
+	"github.com/iammrsea/social-app/internal/user/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lucsky/cuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository(t *testing.T) {
+	t.Parallel()
+	db := testutil.SetupTestPostgres(t)
+	repo := NewUserRepository(db)
+
+	t.Run("Register", func(t *testing.T) {
+		t.Parallel()
+		testRegister(t, db, repo)
+	})
+
+	t.Run("Register rolls back when the email is taken", func(t *testing.T) {
+		t.Parallel()
+		testRegisterTaken(t, db, repo)
+	})
+
+	t.Run("Updates", func(t *testing.T) {
+		t.Parallel()
+		testUpdates(t, db, repo)
+	})
+
+	t.Run("Updates roll back when updateFn fails", func(t *testing.T) {
+		t.Parallel()
+		testUpdateRollback(t, db, repo)
+	})
+
+	t.Run("Updates of a stale version", func(t *testing.T) {
+		t.Parallel()
+		testStaleVersion(t, db, repo)
+	})
+
+	t.Run("ChangeUsername to a taken username", func(t *testing.T) {
+		t.Parallel()
+		testChangeUsernameTaken(t, db, repo)
+	})
+
+	t.Run("DeleteUser", func(t *testing.T) {
+		t.Parallel()
+		testDeleteUser(t, db, repo)
+	})
+
+	t.Run("UserExists", func(t *testing.T) {
+		t.Parallel()
+		testUserExists(t, repo)
+	})
+
+	t.Run("GetUserBy", func(t *testing.T) {
+		t.Parallel()
+		testGetUserBy(t, repo)
+	})
+
+	// The schedulers look at every user, so they get a database of their own
+	t.Run("ExpiredBans", func(t *testing.T) {
+		t.Parallel()
+		testExpiredBans(t)
+	})
+
+	t.Run("DueDeletions", func(t *testing.T) {
+		t.Parallel()
+		testDueDeletions(t)
+	})
+}
+
+func testRegister(t *testing.T, db *pgxpool.Pool, repo *UserRepository) {
+	ctx := context.Background()
+	user := registerUser(t, repo)
+
+	stored, err := repo.GetUserBy(ctx, "id", user.Id())
+	require.NoError(t, err)
+	assert.Equal(t, user.Email(), stored.Email())
+	assert.Equal(t, user.Username(), stored.Username())
+	assert.Equal(t, []rbac.UserRole{rbac.Regular}, stored.Roles())
+	assert.Equal(t, "password-hash", stored.PasswordHash())
+	assert.Equal(t, user.JoinedAt(), stored.JoinedAt())
+	assert.False(t, stored.HasBan())
+	assert.False(t, stored.IsEmailVerified())
+	assert.False(t, stored.IsDeletionRequested())
+
+	assert.Equal(t, 1, userVersion(t, db, user.Id()))
+	assert.Equal(t, []string{"user.registered"}, outboxEvents(t, db, user.Id()))
+}
+
+func testRegisterTaken(t *testing.T, db *pgxpool.Pool, repo *UserRepository) {
+	ctx := context.Background()
+	user := registerUser(t, repo)
+
+	id := cuid.New()
+	other, err := domain.RegisterUser(id, strings.ToUpper(user.Email()), "user_"+id, "password-hash", now())
+	require.NoError(t, err)
+	err = repo.Register(ctx, other)
+	require.ErrorIs(t, err, domain.ErrEmailOrUsernameAlreadyExists)
+
+	_, err = repo.GetUserBy(ctx, "id", id)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	assert.Empty(t, outboxEvents(t, db, id), "the events of a user who wasn't saved are dropped")
+}
+
+func testUpdates(t *testing.T, db *pgxpool.Pool, repo *UserRepository) {
+	type update func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error
+
+	tests := []struct {
+		name      string
+		prepare   func(user *domain.User) error
+		update    update
+		updateFn  func(user *domain.User) error
+		wantEvent string
+		check     func(t *testing.T, stored *domain.User)
+	}{
+		{
+			name:      "UpdateRoles",
+			update:    repo.UpdateRoles,
+			updateFn:  func(user *domain.User) error { return user.AssignRole(rbac.Moderator) },
+			wantEvent: "user.role_assigned",
+			check: func(t *testing.T, stored *domain.User) {
+				assert.True(t, stored.IsModerator())
+			},
+		},
+		{
+			name:      "AwardBadge",
+			update:    repo.AwardBadge,
+			updateFn:  func(user *domain.User) error { return user.AwardBadge("helpful") },
+			wantEvent: "user.badge_awarded",
+			check: func(t *testing.T, stored *domain.User) {
+				assert.Equal(t, []string{"helpful"}, stored.Badges())
+			},
+		},
+		{
+			name:      "RevokeAwardedBadge",
+			prepare:   func(user *domain.User) error { return user.AwardBadge("helpful") },
+			update:    repo.RevokeAwardedBadge,
+			updateFn:  func(user *domain.User) error { return user.RevokeAwardedBadge("helpful") },
+			wantEvent: "user.badge_revoked",
+			check: func(t *testing.T, stored *domain.User) {
+				assert.Empty(t, stored.Badges())
+			},
+		},
+		{
+			name:      "ChangeUsername",
+			update:    repo.ChangeUsername,
+			updateFn:  func(user *domain.User) error { return user.ChangeUsername(user.Username() + "_renamed") },
+			wantEvent: "user.username_changed",
+			check: func(t *testing.T, stored *domain.User) {
+				assert.True(t, strings.HasSuffix(stored.Username(), "_renamed"))
+			},
+		},
+		{
+			name:   "BanUser",
+			update: repo.BanUser,
+			updateFn: func(user *domain.User) error {
+				return user.Ban("spamming", false, domain.NewBanTimeline(now().Add(-time.Hour), now().Add(time.Hour)))
+			},
+			wantEvent: "user.banned",
+			check: func(t *testing.T, stored *domain.User) {
+				assert.True(t, stored.IsBanned())
+				assert.Equal(t, "spamming", stored.ReasonForBan())
+			},
+		},
+		{
+			name:      "UnbanUser",
+			prepare:   func(user *domain.User) error { return user.Ban("spamming", true, nil) },
+			update:    repo.UnbanUser,
+			updateFn:  func(user *domain.User) error { return user.UnBan() },
+			wantEvent: "user.unbanned",
+			check: func(t *testing.T, stored *domain.User) {
+				assert.False(t, stored.HasBan())
+			},
+		},
+		{
+			name:      "VerifyEmail",
+			update:    repo.VerifyEmail,
+			updateFn:  func(user *domain.User) error { return user.VerifyEmail(now()) },
+			wantEvent: "user.email_verified",
+			check: func(t *testing.T, stored *domain.User) {
+				assert.True(t, stored.IsEmailVerified())
+			},
+		},
+		{
+			name:      "ResetPassword",
+			update:    repo.ResetPassword,
+			updateFn:  func(user *domain.User) error { return user.ResetPassword("new-password-hash", now()) },
+			wantEvent: "user.password_reset",
+			check: func(t *testing.T, stored *domain.User) {
+				assert.Equal(t, "new-password-hash", stored.PasswordHash())
+			},
+		},
+		{
+			name:      "ScheduleDeletion",
+			update:    repo.ScheduleDeletion,
+			updateFn:  func(user *domain.User) error { return user.RequestDeletion(now(), 24*time.Hour) },
+			wantEvent: "user.deletion_requested",
+			check: func(t *testing.T, stored *domain.User) {
+				assert.True(t, stored.IsDeletionRequested())
+				assert.Equal(t, stored.DeletionRequestedAt().Add(24*time.Hour), stored.DeletionDueAt())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			user := registerUser(t, repo)
+			if tt.prepare != nil {
+				require.NoError(t, repo.updateUser(ctx, user.Id(), tt.prepare))
+			}
+			version := userVersion(t, db, user.Id())
+			events := outboxEvents(t, db, user.Id())
+
+			require.NoError(t, tt.update(ctx, user.Id(), tt.updateFn))
+
+			stored, err := repo.GetUserBy(ctx, "id", user.Id())
+			require.NoError(t, err)
+			tt.check(t, stored)
+			assert.Equal(t, version+1, userVersion(t, db, user.Id()))
+			assert.Equal(t, append(events, tt.wantEvent), outboxEvents(t, db, user.Id()), "the events are saved with the user")
+		})
+	}
+}
+
+func testUpdateRollback(t *testing.T, db *pgxpool.Pool, repo *UserRepository) {
+	ctx := context.Background()
+	user := registerUser(t, repo)
+	errFailed := errors.New("failed")
+
+	err := repo.AwardBadge(ctx, user.Id(), func(u *domain.User) error {
+		require.NoError(t, u.AwardBadge("helpful"))
+		return errFailed
+	})
+	require.ErrorIs(t, err, errFailed)
+
+	stored, err := repo.GetUserBy(ctx, "id", user.Id())
+	require.NoError(t, err)
+	assert.Empty(t, stored.Badges())
+	assert.Equal(t, 1, userVersion(t, db, user.Id()))
+	assert.Equal(t, []string{"user.registered"}, outboxEvents(t, db, user.Id()))
+}
+
+func testStaleVersion(t *testing.T, db *pgxpool.Pool, repo *UserRepository) {
+	// bumpVersion stands for another instance saving the user after it was read
+	bumpVersion := func(t *testing.T, userId string) {
+		_, err := db.Exec(context.Background(), `UPDATE users SET version = version + 1 WHERE id = $1`, userId)
+		require.NoError(t, err)
+	}
+
+	t.Run("update", func(t *testing.T) {
+		ctx := context.Background()
+		user := registerUser(t, repo)
+
+		err := repo.AwardBadge(ctx, user.Id(), func(u *domain.User) error {
+			bumpVersion(t, u.Id())
+			return u.AwardBadge("helpful")
+		})
+		require.ErrorIs(t, err, domain.ErrConcurrentModification)
+
+		stored, err := repo.GetUserBy(ctx, "id", user.Id())
+		require.NoError(t, err)
+		assert.Empty(t, stored.Badges())
+		assert.Equal(t, []string{"user.registered"}, outboxEvents(t, db, user.Id()))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ctx := context.Background()
+		user := registerUser(t, repo)
+
+		err := repo.DeleteUser(ctx, user.Id(), func(u *domain.User) error {
+			bumpVersion(t, u.Id())
+			return nil
+		})
+		require.ErrorIs(t, err, domain.ErrConcurrentModification)
+
+		_, err = repo.GetUserBy(ctx, "id", user.Id())
+		assert.NoError(t, err, "the user is still there")
+	})
+}
+
+func testChangeUsernameTaken(t *testing.T, db *pgxpool.Pool, repo *UserRepository) {
+	ctx := context.Background()
+	taken := registerUser(t, repo)
+	user := registerUser(t, repo)
+
+	err := repo.ChangeUsername(ctx, user.Id(), func(u *domain.User) error {
+		return u.ChangeUsername(strings.ToUpper(taken.Username()))
+	})
+	require.ErrorIs(t, err, domain.ErrEmailOrUsernameAlreadyExists)
+
+	stored, err := repo.GetUserBy(ctx, "id", user.Id())
+	require.NoError(t, err)
+	assert.Equal(t, user.Username(), stored.Username())
+	assert.Equal(t, []string{"user.registered"}, outboxEvents(t, db, user.Id()))
+}
+
+func testDeleteUser(t *testing.T, db *pgxpool.Pool, repo *UserRepository) {
+	ctx := context.Background()
+	user := registerUser(t, repo)
+	require.NoError(t, repo.ScheduleDeletion(ctx, user.Id(), func(u *domain.User) error {
+		return u.RequestDeletion(now().Add(-48*time.Hour), 24*time.Hour)
+	}))
+	sessionStore := postgres.NewSessionStore(db)
+	require.NoError(t, sessionStore.Save(ctx, sessions.RefreshToken{
+		Id:          cuid.New(),
+		SessionId:   cuid.New(),
+		UserId:      user.Id(),
+		TokenHash:   cuid.New(),
+		AccessToken: sessions.AccessToken{Id: cuid.New(), ExpiresAt: now().Add(time.Hour)},
+		CreatedAt:   now(),
+		ExpiresAt:   now().Add(time.Hour),
+	}))
+
+	t.Run("keeps the user when updateFn fails", func(t *testing.T) {
+		err := repo.DeleteUser(ctx, user.Id(), func(u *domain.User) error {
+			return u.Erase(now().Add(-72 * time.Hour))
+		})
+		require.ErrorIs(t, err, domain.ErrDeletionNotDue)
+		_, err = repo.GetUserBy(ctx, "id", user.Id())
+		assert.NoError(t, err)
+	})
+
+	t.Run("deletes the user with their sessions", func(t *testing.T) {
+		err := repo.DeleteUser(ctx, user.Id(), func(u *domain.User) error {
+			return u.Erase(now())
+		})
+		require.NoError(t, err)
+
+		_, err = repo.GetUserBy(ctx, "id", user.Id())
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		var sessionCount int
+		require.NoError(t, db.QueryRow(ctx, `SELECT count(*) FROM refresh_tokens WHERE user_id = $1`, user.Id()).Scan(&sessionCount))
+		assert.Zero(t, sessionCount)
+		events := outboxEvents(t, db, user.Id())
+		assert.Equal(t, "user.deleted", events[len(events)-1], "the event outlives the user")
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		err := repo.DeleteUser(ctx, cuid.New(), func(u *domain.User) error { return nil })
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
+
+func testUserExists(t *testing.T, repo *UserRepository) {
+	ctx := context.Background()
+	user := registerUser(t, repo)
+
+	tests := []struct {
+		name     string
+		email    string
+		username string
+		want     bool
+	}{
+		{"same email", user.Email(), "someone_else", true},
+		{"same email in another case", strings.ToUpper(user.Email()), "someone_else", true},
+		{"same username", "someone@else.com", user.Username(), true},
+		{"same username in another case", "someone@else.com", strings.ToUpper(user.Username()), true},
+		{"neither", "someone@else.com", "someone_else", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exists, err := repo.UserExists(ctx, tt.email, tt.username)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, exists)
+		})
+	}
+}
+
+func testGetUserBy(t *testing.T, repo *UserRepository) {
+	ctx := context.Background()
+	user := registerUser(t, repo)
+
+	for field, value := range map[string]string{"id": user.Id(), "email": user.Email(), "username": user.Username()} {
+		t.Run(field, func(t *testing.T) {
+			stored, err := repo.GetUserBy(ctx, field, value)
+			require.NoError(t, err)
+			assert.Equal(t, user.Id(), stored.Id())
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repo.GetUserBy(ctx, "email", "nobody@example.com")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("only known columns", func(t *testing.T) {
+		_, err := repo.GetUserBy(ctx, "password_hash", "password-hash")
+		assert.ErrorContains(t, err, "users can't be looked up by password_hash")
+		_, err = repo.GetUserBy(ctx, "id = id OR TRUE; --", user.Id())
+		assert.Error(t, err)
+	})
+}
+
+func testExpiredBans(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(testutil.SetupTestPostgres(t))
+	ban := func(from, to time.Time, indefinite bool) string {
+		user := registerUser(t, repo)
+		require.NoError(t, repo.BanUser(ctx, user.Id(), func(u *domain.User) error {
+			if indefinite {
+				return u.Ban("spamming", true, nil)
+			}
+			return u.Ban("spamming", false, domain.NewBanTimeline(from, to))
+		}))
+		return user.Id()
+	}
+	registerUser(t, repo)
+	expiredLast := ban(now().Add(-3*time.Hour), now().Add(-time.Hour), false)
+	expiredFirst := ban(now().Add(-3*time.Hour), now().Add(-2*time.Hour), false)
+	ban(now().Add(-time.Hour), now().Add(time.Hour), false)
+	ban(time.Time{}, time.Time{}, true)
+
+	ids, err := repo.ExpiredBans(ctx, now(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{expiredFirst, expiredLast}, ids, "ordered by the end of the ban")
+
+	ids, err = repo.ExpiredBans(ctx, now(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{expiredFirst}, ids)
+
+	ids, err = repo.ExpiredBans(ctx, now().Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func testDueDeletions(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(testutil.SetupTestPostgres(t))
+	requestDeletion := func(dueAt time.Time) string {
+		user := registerUser(t, repo)
+		require.NoError(t, repo.ScheduleDeletion(ctx, user.Id(), func(u *domain.User) error {
+			return u.RequestDeletion(dueAt.Add(-24*time.Hour), 24*time.Hour)
+		}))
+		return user.Id()
+	}
+	registerUser(t, repo)
+	dueLast := requestDeletion(now().Add(-time.Hour))
+	dueFirst := requestDeletion(now().Add(-2 * time.Hour))
+	requestDeletion(now().Add(time.Hour))
+
+	ids, err := repo.DueDeletions(ctx, now(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{dueFirst, dueLast}, ids, "ordered by when the deletion is due")
+
+	ids, err = repo.DueDeletions(ctx, now(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{dueFirst}, ids)
+
+	ids, err = repo.DueDeletions(ctx, now().Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+// now is the current time at the precision Postgres keeps
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// registerUser registers a regular user with an email and a username no other test uses
+func registerUser(t *testing.T, repo *UserRepository) domain.User {
+	t.Helper()
+	id := cuid.New()
+	user, err := domain.RegisterUser(id, id+"@example.com", "user_"+id, "password-hash", now())
+	require.NoError(t, err)
+	require.NoError(t, repo.Register(context.Background(), user))
+	return user
+}
+
+func userVersion(t *testing.T, db *pgxpool.Pool, userId string) int {
+	t.Helper()
+	var version int
+	require.NoError(t, db.QueryRow(context.Background(), `SELECT version FROM users WHERE id = $1`, userId).Scan(&version))
+	return version
+}
+
+// outboxEvents lists the names of the events written to the outbox about the user, oldest first
+func outboxEvents(t *testing.T, db *pgxpool.Pool, userId string) []string {
+	t.Helper()
+	rows, err := db.Query(context.Background(), `SELECT event_name FROM outbox WHERE payload->>'UserId' = $1 ORDER BY sequence`, userId)
+	require.NoError(t, err)
+	defer rows.Close()
+	names := []string{}
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		names = append(names, name)
+	}
+	require.NoError(t, rows.Err())
+	return names
+}
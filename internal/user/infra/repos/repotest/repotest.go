@@ -0,0 +1,586 @@
+// Package repotest checks that a user repository behaves the way the app expects from every
+// storage engine: the same errors, the same pagination and sorting, and the same updates.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/user/domain"
+	"github.com/lucsky/cuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Repos are the repositories under test. Both must be backed by the same store.
+type Repos struct {
+	Users      domain.UserRepository
+	ReadModels domain.UserReadModelRepository
+}
+
+// Factory makes repositories backed by a store of their own, holding no users but
+// domain.DeletedUserId at most. It is called once per test, possibly from parallel tests.
+type Factory func(t *testing.T) Repos
+
+// Run runs the conformance tests against the repositories made by newRepos
+func Run(t *testing.T, newRepos Factory) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repos Repos)
+	}{
+		{"Register", testRegister},
+		{"RegisterTakenEmailOrUsername", testRegisterTakenEmailOrUsername},
+		{"RegisterTakenId", testRegisterTakenId},
+		{"UserExists", testUserExists},
+		{"LookupInOtherCase", testLookupInOtherCase},
+		{"UserNotFound", testUserNotFound},
+		{"GetUserByUnknownField", testGetUserByUnknownField},
+		{"Updates", testUpdates},
+		{"UpdateFnError", testUpdateFnError},
+		{"ChangeUsernameTaken", testChangeUsernameTaken},
+		{"ConcurrentUpdate", testConcurrentUpdate},
+		{"GetUsers", testGetUsers},
+		{"BanStatus", testBanStatus},
+		{"ExpiredBans", testExpiredBans},
+		{"DueDeletions", testDueDeletions},
+		{"DeleteUser", testDeleteUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tt.test(t, newRepos(t))
+		})
+	}
+}
+
+func testRegister(t *testing.T, repos Repos) {
+	ctx := context.Background()
+	joinedAt := now().Add(-time.Hour)
+	id := cuid.New()
+	user, err := domain.RegisterUser(id, id+"@example.com", "user_"+id, "password-hash", joinedAt)
+	require.NoError(t, err)
+	require.NoError(t, repos.Users.Register(ctx, user))
+
+	byId, err := repos.ReadModels.GetUserById(ctx, id)
+	require.NoError(t, err)
+	assertReadModel(t, user, byId)
+	byEmail, err := repos.ReadModels.GetUserByEmail(ctx, user.Email())
+	require.NoError(t, err)
+	assertReadModel(t, user, byEmail)
+
+	for field, value := range map[string]string{"id": id, "email": user.Email(), "username": user.Username()} {
+		stored, err := repos.Users.GetUserBy(ctx, field, value)
+		require.NoError(t, err, field)
+		assert.Equal(t, id, stored.Id(), field)
+		assert.Equal(t, "password-hash", stored.PasswordHash(), field)
+		assert.Equal(t, []rbac.UserRole{rbac.Regular}, stored.Roles(), field)
+		assert.WithinDuration(t, joinedAt, stored.JoinedAt(), time.Millisecond, field)
+		assert.False(t, stored.IsEmailVerified(), field)
+		assert.False(t, stored.IsDeletionRequested(), field)
+	}
+}
+
+func testRegisterTakenEmailOrUsername(t *testing.T, repos Repos) {
+	ctx := context.Background()
+	taken := register(t, repos, newUser(t, now()))
+
+	tests := []struct {
+		name     string
+		email    string
+		username string
+	}{
+		{"same email", taken.Email(), "user_" + cuid.New()},
+		{"email in other case", strings.ToUpper(taken.Email()), "user_" + cuid.New()},
+		{"same username", cuid.New() + "@example.com", taken.Username()},
+		{"username in other case", cuid.New() + "@example.com", strings.ToUpper(taken.Username())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := domain.NewUser(cuid.New(), tt.email, tt.username, []rbac.UserRole{rbac.Regular}, now(), now(), nil, nil)
+			require.NoError(t, err)
+			err = repos.Users.Register(ctx, user)
+			assert.ErrorIs(t, err, domain.ErrEmailOrUsernameAlreadyExists)
+			_, err = repos.ReadModels.GetUserById(ctx, user.Id())
+			assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		})
+	}
+}
+
+// testRegisterTakenId checks that only taken emails and usernames are reported as such
+func testRegisterTakenId(t *testing.T, repos Repos) {
+	taken := register(t, repos, newUser(t, now()))
+	id := cuid.New()
+	user, err := domain.NewUser(taken.Id(), id+"@example.com", "user_"+id, []rbac.UserRole{rbac.Regular}, now(), now(), nil, nil)
+	require.NoError(t, err)
+	err = repos.Users.Register(context.Background(), user)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, domain.ErrEmailOrUsernameAlreadyExists)
+}
+
+func testUserExists(t *testing.T, repos Repos) {
+	user := register(t, repos, newUser(t, now()))
+
+	tests := []struct {
+		name     string
+		email    string
+		username string
+		exists   bool
+	}{
+		{"email", user.Email(), "", true},
+		{"email in other case", strings.ToUpper(user.Email()), "", true},
+		{"username", "", user.Username(), true},
+		{"username in other case", "", strings.ToUpper(user.Username()), true},
+		{"neither", "nobody@example.com", "nobody", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exists, err := repos.Users.UserExists(context.Background(), tt.email, tt.username)
+			require.NoError(t, err)
+			assert.Equal(t, tt.exists, exists)
+		})
+	}
+}
+
+// testLookupInOtherCase checks that emails and usernames are found the way the unique indexes
+// compare them, regardless of case
+func testLookupInOtherCase(t *testing.T, repos Repos) {
+	ctx := context.Background()
+	user := register(t, repos, newUser(t, now()))
+
+	byEmail, err := repos.ReadModels.GetUserByEmail(ctx, strings.ToUpper(user.Email()))
+	require.NoError(t, err)
+	assert.Equal(t, user.Id(), byEmail.Id)
+
+	for field, value := range map[string]string{"email": user.Email(), "username": user.Username()} {
+		stored, err := repos.Users.GetUserBy(ctx, field, strings.ToUpper(value))
+		require.NoError(t, err, field)
+		assert.Equal(t, user.Id(), stored.Id(), field)
+	}
+}
+
+func testUserNotFound(t *testing.T, repos Repos) {
+	ctx := context.Background()
+	missing := cuid.New()
+	noop := func(user *domain.User) error { return nil }
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"GetUserById", func() error { _, err := repos.ReadModels.GetUserById(ctx, missing); return err }},
+		{"GetUserByEmail", func() error { _, err := repos.ReadModels.GetUserByEmail(ctx, missing+"@example.com"); return err }},
+		{"GetUserBy", func() error { _, err := repos.Users.GetUserBy(ctx, "id", missing); return err }},
+		{"UpdateRoles", func() error { return repos.Users.UpdateRoles(ctx, missing, noop) }},
+		{"AwardBadge", func() error { return repos.Users.AwardBadge(ctx, missing, noop) }},
+		{"RevokeAwardedBadge", func() error { return repos.Users.RevokeAwardedBadge(ctx, missing, noop) }},
+		{"ChangeUsername", func() error { return repos.Users.ChangeUsername(ctx, missing, noop) }},
+		{"BanUser", func() error { return repos.Users.BanUser(ctx, missing, noop) }},
+		{"UnbanUser", func() error { return repos.Users.UnbanUser(ctx, missing, noop) }},
+		{"VerifyEmail", func() error { return repos.Users.VerifyEmail(ctx, missing, noop) }},
+		{"ResetPassword", func() error { return repos.Users.ResetPassword(ctx, missing, noop) }},
+		{"ScheduleDeletion", func() error { return repos.Users.ScheduleDeletion(ctx, missing, noop) }},
+		{"DeleteUser", func() error { return repos.Users.DeleteUser(ctx, missing, noop) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), domain.ErrUserNotFound)
+		})
+	}
+}
+
+func testGetUserByUnknownField(t *testing.T, repos Repos) {
+	user := register(t, repos, newUser(t, now()))
+	_, err := repos.Users.GetUserBy(context.Background(), "password_hash", user.PasswordHash())
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, domain.ErrUserNotFound)
+}
+
+func testUpdates(t *testing.T, repos Repos) {
+	type update func(ctx context.Context, userId string, updateFn func(user *domain.User) error) error
+	banStart := now().Add(-time.Hour)
+	banEnd := now().Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		user     func(t *testing.T) domain.User
+		update   update
+		updateFn func(user *domain.User) error
+		check    func(t *testing.T, user *domain.UserReadModel, stored *domain.User)
+	}{
+		{
+			name:     "UpdateRoles",
+			update:   repos.Users.UpdateRoles,
+			updateFn: func(user *domain.User) error { return user.AssignRole(rbac.Moderator) },
+			check: func(t *testing.T, user *domain.UserReadModel, stored *domain.User) {
+				assert.Equal(t, []rbac.UserRole{rbac.Regular, rbac.Moderator}, user.Roles)
+			},
+		},
+		{
+			name:     "AwardBadge",
+			update:   repos.Users.AwardBadge,
+			updateFn: func(user *domain.User) error { return user.AwardBadge("helpful") },
+			check: func(t *testing.T, user *domain.UserReadModel, stored *domain.User) {
+				assert.Equal(t, []string{"helpful"}, user.Reputation.Badges)
+			},
+		},
+		{
+			name: "RevokeAwardedBadge",
+			user: func(t *testing.T) domain.User {
+				id := cuid.New()
+				user, err := domain.NewUser(id, id+"@example.com", "user_"+id, []rbac.UserRole{rbac.Regular}, now(), now(),
+					domain.MustNewUserReputation(10, []string{"helpful", "curious"}), nil)
+				require.NoError(t, err)
+				return user
+			},
+			update:   repos.Users.RevokeAwardedBadge,
+			updateFn: func(user *domain.User) error { return user.RevokeAwardedBadge("helpful") },
+			check: func(t *testing.T, user *domain.UserReadModel, stored *domain.User) {
+				assert.Equal(t, []string{"curious"}, user.Reputation.Badges)
+				assert.Equal(t, 10, user.Reputation.ReputationScore)
+			},
+		},
+		{
+			name:     "ChangeUsername",
+			update:   repos.Users.ChangeUsername,
+			updateFn: func(user *domain.User) error { return user.ChangeUsername(user.Username() + "_renamed") },
+			check: func(t *testing.T, user *domain.UserReadModel, stored *domain.User) {
+				assert.True(t, strings.HasSuffix(user.Username, "_renamed"))
+			},
+		},
+		{
+			name:   "BanUser",
+			update: repos.Users.BanUser,
+			updateFn: func(user *domain.User) error {
+				return user.Ban("spamming", false, domain.NewBanTimeline(banStart, banEnd))
+			},
+			check: func(t *testing.T, user *domain.UserReadModel, stored *domain.User) {
+				assert.True(t, user.BanStatus.IsBanned)
+				assert.False(t, user.BanStatus.IsBanIndefinite)
+				assert.Equal(t, "spamming", user.BanStatus.ReasonForBan)
+				assert.WithinDuration(t, banStart, user.BanStatus.BanStartDate, time.Millisecond)
+				assert.WithinDuration(t, banEnd, user.BanStatus.BanEndDate, time.Millisecond)
+				assert.False(t, user.BanStatus.BannedAt.IsZero())
+				assert.True(t, stored.IsBanned())
+			},
+		},
+		{
+			name: "UnbanUser",
+			user: func(t *testing.T) domain.User {
+				id := cuid.New()
+				user, err := domain.NewUser(id, id+"@example.com", "user_"+id, []rbac.UserRole{rbac.Regular}, now(), now(),
+					nil, domain.NewBan(true, "spamming", true, time.Time{}, time.Time{}, now()))
+				require.NoError(t, err)
+				return user
+			},
+			update:   repos.Users.UnbanUser,
+			updateFn: func(user *domain.User) error { return user.UnBan() },
+			check: func(t *testing.T, user *domain.UserReadModel, stored *domain.User) {
+				assert.False(t, user.BanStatus.IsBanned)
+				assert.False(t, user.BanStatus.IsBanIndefinite)
+				assert.Empty(t, user.BanStatus.ReasonForBan)
+				assert.False(t, stored.HasBan())
+			},
+		},
+		{
+			name:     "VerifyEmail",
+			update:   repos.Users.VerifyEmail,
+			updateFn: func(user *domain.User) error { return user.VerifyEmail(now()) },
+			check: func(t *testing.T, user *domain.UserReadModel, stored *domain.User) {
+				assert.True(t, user.EmailVerified)
+				assert.True(t, stored.IsEmailVerified())
+			},
+		},
+		{
+			name:     "ResetPassword",
+			update:   repos.Users.ResetPassword,
+			updateFn: func(user *domain.User) error { return user.ResetPassword("new-password-hash", now()) },
+			check: func(t *testing.T, user *domain.UserReadModel, stored *domain.User) {
+				assert.Equal(t, "new-password-hash", stored.PasswordHash())
+			},
+		},
+		{
+			name:     "ScheduleDeletion",
+			update:   repos.Users.ScheduleDeletion,
+			updateFn: func(user *domain.User) error { return user.RequestDeletion(now(), 24*time.Hour) },
+			check: func(t *testing.T, user *domain.UserReadModel, stored *domain.User) {
+				require.NotNil(t, user.DeletionDueAt)
+				assert.WithinDuration(t, now().Add(24*time.Hour), *user.DeletionDueAt, time.Minute)
+				assert.True(t, stored.IsDeletionRequested())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			joinedAt := now().Add(-time.Hour)
+			user := newUser(t, joinedAt)
+			if tt.user != nil {
+				user = tt.user(t)
+			}
+			register(t, repos, user)
+
+			before := now()
+			require.NoError(t, tt.update(ctx, user.Id(), tt.updateFn))
+
+			updated, err := repos.ReadModels.GetUserById(ctx, user.Id())
+			require.NoError(t, err)
+			stored, err := repos.Users.GetUserBy(ctx, "id", user.Id())
+			require.NoError(t, err)
+			tt.check(t, updated, stored)
+			assert.False(t, updated.UpdatedAt.Before(before), "the update time is saved")
+		})
+	}
+}
+
+func testUpdateFnError(t *testing.T, repos Repos) {
+	ctx := context.Background()
+	user := register(t, repos, newUser(t, now()))
+	errRejected := errors.New("rejected")
+
+	err := repos.Users.ChangeUsername(ctx, user.Id(), func(u *domain.User) error {
+		if err := u.ChangeUsername(u.Username() + "_renamed"); err != nil {
+			return err
+		}
+		return errRejected
+	})
+	assert.ErrorIs(t, err, errRejected)
+
+	stored, err := repos.ReadModels.GetUserById(ctx, user.Id())
+	require.NoError(t, err)
+	assert.Equal(t, user.Username(), stored.Username)
+}
+
+func testChangeUsernameTaken(t *testing.T, repos Repos) {
+	ctx := context.Background()
+	taken := register(t, repos, newUser(t, now()))
+	user := register(t, repos, newUser(t, now()))
+
+	err := repos.Users.ChangeUsername(ctx, user.Id(), func(u *domain.User) error {
+		return u.ChangeUsername(strings.ToUpper(taken.Username()))
+	})
+	assert.ErrorIs(t, err, domain.ErrEmailOrUsernameAlreadyExists)
+
+	stored, err := repos.ReadModels.GetUserById(ctx, user.Id())
+	require.NoError(t, err)
+	assert.Equal(t, user.Username(), stored.Username)
+}
+
+// testConcurrentUpdate saves an update while another one is in flight. The update in flight must
+// either fail with domain.ErrConcurrentModification or be applied on top of the other one, so
+// that no update is lost.
+func testConcurrentUpdate(t *testing.T, repos Repos) {
+	ctx := context.Background()
+	user := register(t, repos, newUser(t, now()))
+	newUsername := user.Username() + "_renamed"
+
+	var once sync.Once
+	err := repos.Users.AwardBadge(ctx, user.Id(), func(u *domain.User) error {
+		once.Do(func() {
+			require.NoError(t, repos.Users.ChangeUsername(ctx, user.Id(), func(u *domain.User) error {
+				return u.ChangeUsername(newUsername)
+			}))
+		})
+		return u.AwardBadge("helpful")
+	})
+
+	stored, getErr := repos.ReadModels.GetUserById(ctx, user.Id())
+	require.NoError(t, getErr)
+	assert.Equal(t, newUsername, stored.Username)
+	if err != nil {
+		assert.ErrorIs(t, err, domain.ErrConcurrentModification)
+		assert.Empty(t, stored.Reputation.Badges)
+	} else {
+		assert.Equal(t, []string{"helpful"}, stored.Reputation.Badges)
+	}
+}
+
+func testGetUsers(t *testing.T, repos Repos) {
+	ctx := context.Background()
+	start := now().Add(-time.Hour)
+	ids := make([]string, 5)
+	for i := range ids {
+		user := register(t, repos, newUser(t, start.Add(time.Duration(i)*time.Minute)))
+		ids[i] = user.Id()
+	}
+	cursor := func(i int) string {
+		return start.Add(time.Duration(i) * time.Minute).Format(time.RFC3339Nano)
+	}
+
+	tests := []struct {
+		name    string
+		opts    domain.GetUsersOptions
+		want    []string
+		hasNext bool
+	}{
+		{"oldest first by default", domain.GetUsersOptions{First: 2}, ids[:2], true},
+		{"oldest first", domain.GetUsersOptions{First: 2, SortDirection: "ASC"}, ids[:2], true},
+		{"oldest first after a cursor", domain.GetUsersOptions{First: 2, After: cursor(1), SortDirection: "ASC"}, ids[2:4], true},
+		{"last page oldest first", domain.GetUsersOptions{First: 2, After: cursor(3), SortDirection: "ASC"}, ids[4:], false},
+		{"newest first", domain.GetUsersOptions{First: 2, SortDirection: "DESC"}, []string{ids[4], ids[3]}, true},
+		{"newest first after a cursor", domain.GetUsersOptions{First: 2, After: cursor(3), SortDirection: "DESC"}, []string{ids[2], ids[1]}, true},
+		{"last page newest first", domain.GetUsersOptions{First: 2, After: cursor(1), SortDirection: "DESC"}, ids[:1], false},
+		{"page exactly as large as what is left", domain.GetUsersOptions{First: 5}, ids, false},
+		{"page larger than what is left", domain.GetUsersOptions{First: 10}, ids, false},
+		{"nothing after the last user", domain.GetUsersOptions{First: 2, After: cursor(4)}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, hasNext, err := repos.ReadModels.GetUsers(ctx, tt.opts)
+			require.NoError(t, err)
+			got := make([]string, len(users))
+			for i, user := range users {
+				got[i] = user.Id
+			}
+			if tt.want == nil {
+				assert.Empty(t, got)
+			} else {
+				assert.Equal(t, tt.want, got, "sorted by the time users joined")
+			}
+			assert.Equal(t, tt.hasNext, hasNext)
+		})
+	}
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, _, err := repos.ReadModels.GetUsers(ctx, domain.GetUsersOptions{First: 2, SortDirection: "SIDEWAYS"})
+		assert.Error(t, err)
+		_, _, err = repos.ReadModels.GetUsers(ctx, domain.GetUsersOptions{First: 2, After: "yesterday"})
+		assert.Error(t, err)
+		for _, first := range []int32{0, -1} {
+			_, _, err = repos.ReadModels.GetUsers(ctx, domain.GetUsersOptions{First: first})
+			assert.ErrorIs(t, err, domain.ErrInvalidPageSize, "first: %d", first)
+		}
+	})
+}
+
+func testBanStatus(t *testing.T, repos Repos) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		from, to time.Time
+		banned   bool
+	}{
+		{"ban in force", now().Add(-time.Hour), now().Add(time.Hour), true},
+		{"expired ban", now().Add(-2 * time.Hour), now().Add(-time.Hour), false},
+		{"scheduled ban", now().Add(time.Hour), now().Add(2 * time.Hour), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := register(t, repos, newBannedUser(t, tt.from, tt.to))
+			stored, err := repos.ReadModels.GetUserById(ctx, user.Id())
+			require.NoError(t, err)
+			assert.Equal(t, tt.banned, stored.BanStatus.IsBanned)
+			assert.Equal(t, "spamming", stored.BanStatus.ReasonForBan)
+		})
+	}
+}
+
+func testExpiredBans(t *testing.T, repos Repos) {
+	ctx := context.Background()
+	expiredLast := register(t, repos, newBannedUser(t, now().Add(-2*time.Hour), now().Add(-time.Hour)))
+	expiredFirst := register(t, repos, newBannedUser(t, now().Add(-3*time.Hour), now().Add(-2*time.Hour)))
+	register(t, repos, newBannedUser(t, now().Add(-time.Hour), now().Add(time.Hour)))
+	register(t, repos, newUser(t, now()))
+
+	ids, err := repos.Users.ExpiredBans(ctx, now(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{expiredFirst.Id(), expiredLast.Id()}, ids)
+
+	ids, err = repos.Users.ExpiredBans(ctx, now(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{expiredFirst.Id()}, ids)
+}
+
+func testDueDeletions(t *testing.T, repos Repos) {
+	ctx := context.Background()
+	dueLast := requestDeletion(t, repos, now().Add(-time.Hour))
+	dueFirst := requestDeletion(t, repos, now().Add(-2*time.Hour))
+	requestDeletion(t, repos, now().Add(time.Hour))
+	register(t, repos, newUser(t, now()))
+
+	ids, err := repos.Users.DueDeletions(ctx, now(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{dueFirst.Id(), dueLast.Id()}, ids)
+
+	ids, err = repos.Users.DueDeletions(ctx, now(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{dueFirst.Id()}, ids)
+}
+
+func testDeleteUser(t *testing.T, repos Repos) {
+	ctx := context.Background()
+	due := requestDeletion(t, repos, now().Add(-time.Hour))
+	notDue := requestDeletion(t, repos, now().Add(time.Hour))
+	erase := func(user *domain.User) error { return user.Erase(now()) }
+
+	err := repos.Users.DeleteUser(ctx, notDue.Id(), erase)
+	assert.ErrorIs(t, err, domain.ErrDeletionNotDue)
+	_, err = repos.ReadModels.GetUserById(ctx, notDue.Id())
+	assert.NoError(t, err, "a failed deletion keeps the user")
+
+	require.NoError(t, repos.Users.DeleteUser(ctx, due.Id(), erase))
+	_, err = repos.ReadModels.GetUserById(ctx, due.Id())
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	exists, err := repos.Users.UserExists(ctx, due.Email(), due.Username())
+	require.NoError(t, err)
+	assert.False(t, exists, "the email and username of a deleted user are free again")
+}
+
+// now is the current time at the precision every storage engine keeps
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// newUser makes a regular user with an email and a username no other test uses
+func newUser(t *testing.T, joinedAt time.Time) domain.User {
+	t.Helper()
+	id := cuid.New()
+	user, err := domain.NewUser(id, id+"@example.com", "user_"+id, []rbac.UserRole{rbac.Regular}, joinedAt, joinedAt, nil, nil)
+	require.NoError(t, err)
+	return user
+}
+
+func newBannedUser(t *testing.T, from, to time.Time) domain.User {
+	t.Helper()
+	id := cuid.New()
+	user, err := domain.NewUser(id, id+"@example.com", "user_"+id, []rbac.UserRole{rbac.Regular}, now(), now(),
+		nil, domain.NewBan(true, "spamming", false, from, to, from))
+	require.NoError(t, err)
+	return user
+}
+
+func register(t *testing.T, repos Repos, user domain.User) domain.User {
+	t.Helper()
+	require.NoError(t, repos.Users.Register(context.Background(), user))
+	return user
+}
+
+// requestDeletion registers a user whose account deletion is due at dueAt
+func requestDeletion(t *testing.T, repos Repos, dueAt time.Time) domain.User {
+	t.Helper()
+	user := register(t, repos, newUser(t, now()))
+	err := repos.Users.ScheduleDeletion(context.Background(), user.Id(), func(u *domain.User) error {
+		return u.RequestDeletion(dueAt.Add(-24*time.Hour), 24*time.Hour)
+	})
+	require.NoError(t, err)
+	return user
+}
+
+func assertReadModel(t *testing.T, want domain.User, got *domain.UserReadModel) {
+	t.Helper()
+	assert.Equal(t, want.Id(), got.Id)
+	assert.Equal(t, want.Email(), got.Email)
+	assert.Equal(t, want.Username(), got.Username)
+	assert.Equal(t, want.Roles(), got.Roles)
+	assert.Equal(t, want.ReputationScore(), got.Reputation.ReputationScore)
+	assert.Empty(t, got.Reputation.Badges)
+	assert.WithinDuration(t, want.JoinedAt(), got.CreatedAt, time.Millisecond)
+	assert.WithinDuration(t, want.UpdatedAt(), got.UpdatedAt, time.Millisecond)
+	assert.False(t, got.EmailVerified)
+	assert.Nil(t, got.DeletionDueAt)
+	assert.False(t, got.BanStatus.IsBanned)
+}
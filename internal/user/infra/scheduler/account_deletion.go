@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/user/app/command"
+)
+
+// AccountDeletionScheduler periodically erases the accounts whose deletion grace period is over.
+// An erasure that fails is picked up again by the next sweep.
+type AccountDeletionScheduler struct {
+	schedule
+	eraseDueAccounts command.EraseDueAccountsHandler
+}
+
+func NewAccountDeletionScheduler(eraseDueAccounts command.EraseDueAccountsHandler, interval time.Duration, opts ...Option) *AccountDeletionScheduler {
+	if eraseDueAccounts == nil {
+		panic("nil erase due accounts handler")
+	}
+	return &AccountDeletionScheduler{
+		schedule:         newSchedule(interval, opts),
+		eraseDueAccounts: eraseDueAccounts,
+	}
+}
+
+// Run sweeps due account deletions until ctx is cancelled
+func (s *AccountDeletionScheduler) Run(ctx context.Context) {
+	s.run(ctx, "account deletion", s.Sweep)
+}
+
+// Sweep erases one batch of due accounts
+func (s *AccountDeletionScheduler) Sweep(ctx context.Context) error {
+	return s.eraseDueAccounts.Handle(ctx, command.EraseDueAccounts{Now: s.now(), BatchSize: s.batchSize})
+}
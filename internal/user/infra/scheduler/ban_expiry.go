@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/user/app/command"
+)
+
+// BanExpiryScheduler periodically lifts the time-boxed bans that are over. The unban events are
+// saved to the outbox by the repository, like those of any other unban.
+type BanExpiryScheduler struct {
+	schedule
+	liftExpiredBans command.LiftExpiredBansHandler
+}
+
+func NewBanExpiryScheduler(liftExpiredBans command.LiftExpiredBansHandler, interval time.Duration, opts ...Option) *BanExpiryScheduler {
+	if liftExpiredBans == nil {
+		panic("nil lift expired bans handler")
+	}
+	return &BanExpiryScheduler{
+		schedule:        newSchedule(interval, opts),
+		liftExpiredBans: liftExpiredBans,
+	}
+}
+
+// Run sweeps expired bans until ctx is cancelled
+func (s *BanExpiryScheduler) Run(ctx context.Context) {
+	s.run(ctx, "ban expiry", s.Sweep)
+}
+
+// Sweep lifts one batch of expired bans
+func (s *BanExpiryScheduler) Sweep(ctx context.Context) error {
+	return s.liftExpiredBans.Handle(ctx, command.LiftExpiredBans{Now: s.now(), BatchSize: s.batchSize})
+}
@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const defaultBatchSize = 100
+
+type Option func(*schedule)
+
+// WithClock replaces time.Now as the source of the current time
+func WithClock(now func() time.Time) Option {
+	return func(s *schedule) {
+		s.now = now
+	}
+}
+
+func WithBatchSize(size int) Option {
+	return func(s *schedule) {
+		s.batchSize = size
+	}
+}
+
+// schedule is what the schedulers share: how often they sweep and how much at once
+type schedule struct {
+	interval  time.Duration
+	batchSize int
+	now       func() time.Time
+}
+
+func newSchedule(interval time.Duration, opts []Option) schedule {
+	s := schedule{
+		interval:  interval,
+		batchSize: defaultBatchSize,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s
+}
+
+// run calls sweep once and then every interval until ctx is cancelled, logging its errors
+func (s *schedule) run(ctx context.Context, name string, sweep func(context.Context) error) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if err := sweep(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("%s: %v", name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package signout
+
+import (
+	"context"
+
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/user/app/command"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+// OnAccessChanges ends every session of a user whose roles change, who is banned or whose password
+// is reset, so that access tokens carrying the old roles or stolen along with the old password stop
+// working before they expire
+func OnAccessChanges(subscriber eventbus.Subscriber, sessions command.SessionRevoker) {
+	eventbus.SubscribeTo(subscriber, func(ctx context.Context, event domain.UserRoleAssigned) error {
+		return sessions.LogoutAll(ctx, event.UserId)
+	})
+	eventbus.SubscribeTo(subscriber, func(ctx context.Context, event domain.UserRoleRemoved) error {
+		return sessions.LogoutAll(ctx, event.UserId)
+	})
+	eventbus.SubscribeTo(subscriber, func(ctx context.Context, event domain.UserBanned) error {
+		return sessions.LogoutAll(ctx, event.UserId)
+	})
+	eventbus.SubscribeTo(subscriber, func(ctx context.Context, event domain.UserPasswordReset) error {
+		return sessions.LogoutAll(ctx, event.UserId)
+	})
+}
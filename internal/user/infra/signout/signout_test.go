@@ -0,0 +1,42 @@
+package signout_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iammrsea/social-app/internal/shared/eventbus"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	"github.com/iammrsea/social-app/internal/user/domain"
+	"github.com/iammrsea/social-app/internal/user/infra/signout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type revokerStub struct {
+	loggedOut []string
+}
+
+func (r *revokerStub) Logout(ctx context.Context, sessionId string) error {
+	return nil
+}
+
+func (r *revokerStub) LogoutAll(ctx context.Context, userId string) error {
+	r.loggedOut = append(r.loggedOut, userId)
+	return nil
+}
+
+func TestOnAccessChanges(t *testing.T) {
+	t.Parallel()
+	revoker := &revokerStub{}
+	bus := eventbus.New()
+	signout.OnAccessChanges(bus, revoker)
+	ctx := context.Background()
+
+	require.NoError(t, bus.Publish(ctx, domain.UserRoleAssigned{UserId: "user-1", Role: rbac.Moderator}))
+	require.NoError(t, bus.Publish(ctx, domain.UserBanned{UserId: "user-2"}))
+	require.NoError(t, bus.Publish(ctx, domain.UserUnbanned{UserId: "user-3"}))
+	require.NoError(t, bus.Publish(ctx, domain.UserPasswordReset{UserId: "user-4"}))
+	require.NoError(t, bus.Publish(ctx, domain.UserRoleRemoved{UserId: "user-5", Role: rbac.Moderator}))
+
+	assert.Equal(t, []string{"user-1", "user-2", "user-4", "user-5"}, revoker.loggedOut)
+}
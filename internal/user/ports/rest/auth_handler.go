@@ -0,0 +1,229 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/mfa"
+	"github.com/iammrsea/social-app/internal/shared/auth/onetime"
+	"github.com/iammrsea/social-app/internal/shared/auth/sessions"
+	"github.com/iammrsea/social-app/internal/shared/custom_errors"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	service "github.com/iammrsea/social-app/internal/user/app"
+	"github.com/iammrsea/social-app/internal/user/app/command"
+	"github.com/iammrsea/social-app/internal/user/domain"
+)
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Device   string `json:"device"`
+}
+
+type completeLoginRequest struct {
+	MFAToken string `json:"mfaToken"`
+	Code     string `json:"code"`
+	Device   string `json:"device"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+type passwordResetRequest struct {
+	Email string `json:"email"`
+}
+
+type resetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"newPassword"`
+}
+
+type tokenResponse struct {
+	AccessToken           string    `json:"accessToken"`
+	TokenType             string    `json:"tokenType"`
+	ExpiresAt             time.Time `json:"expiresAt"`
+	RefreshToken          string    `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time `json:"refreshTokenExpiresAt"`
+}
+
+// mfaRequiredResponse answers a login that has to be completed with a two-factor code
+type mfaRequiredResponse struct {
+	MFARequired bool      `json:"mfaRequired"`
+	MFAToken    string    `json:"mfaToken"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type AuthHandler struct {
+	users *service.Application
+}
+
+func NewAuthHandler(users *service.Application) *AuthHandler {
+	if users == nil {
+		panic("nil user application")
+	}
+	return &AuthHandler{users: users}
+}
+
+// Routes are meant to be mounted under /auth
+func (h *AuthHandler) Routes() chi.Router {
+	router := chi.NewRouter()
+	router.Post("/login", h.Login)
+	router.Post("/login/mfa", h.CompleteLogin)
+	router.Post("/refresh", h.Refresh)
+	router.Post("/logout", h.Logout)
+	router.Post("/logout-all", h.LogoutAll)
+	router.Get("/verify-email", h.VerifyEmail)
+	router.Post("/verify-email/resend", h.ResendVerificationEmail)
+	router.Post("/password-reset", h.RequestPasswordReset)
+	router.Post("/password-reset/confirm", h.ResetPassword)
+	return router
+}
+
+// Login exchanges an email and password for an access token and a refresh token. Users with
+// two-factor authentication get an MFA token instead, for CompleteLogin.
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	token, err := h.users.Login.Handle(r.Context(), command.Login{Email: req.Email, Password: req.Password, Device: device(r, req.Device)})
+	writeToken(w, token, err)
+}
+
+// CompleteLogin exchanges the MFA token from Login and a two-factor code for an access token and
+// a refresh token
+func (h *AuthHandler) CompleteLogin(w http.ResponseWriter, r *http.Request) {
+	var req completeLoginRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	token, err := h.users.CompleteLogin.Handle(r.Context(), command.CompleteLogin{MFAToken: req.MFAToken, Code: req.Code, Device: device(r, req.Device)})
+	writeToken(w, token, err)
+}
+
+// Refresh exchanges a refresh token for new tokens. The refresh token can't be used again.
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req refreshRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	token, err := h.users.RefreshToken.Handle(r.Context(), command.RefreshToken{RefreshToken: req.RefreshToken})
+	writeToken(w, token, err)
+}
+
+// Logout ends the session of the access token the request is authenticated with
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	writeNoContent(w, h.users.Logout.Handle(r.Context(), command.Logout{}))
+}
+
+// LogoutAll ends every session of the authenticated user
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	writeNoContent(w, h.users.LogoutAllSessions.Handle(r.Context(), command.LogoutAllSessions{}))
+}
+
+// VerifyEmail is the link sent to verify the email of a user; the token is in the query string
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	writeNoContent(w, h.users.VerifyEmail.Handle(r.Context(), command.VerifyEmail{Token: token}))
+}
+
+// ResendVerificationEmail sends the authenticated user a new email verification link
+func (h *AuthHandler) ResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	writeNoContent(w, h.users.RequestEmailVerification.Handle(r.Context(), command.RequestEmailVerification{}))
+}
+
+// RequestPasswordReset emails a password reset link. It succeeds whether or not the email is known.
+func (h *AuthHandler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req passwordResetRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	writeNoContent(w, h.users.RequestPasswordReset.Handle(r.Context(), command.RequestPasswordReset{Email: req.Email}))
+}
+
+// ResetPassword sets a new password with the token from a password reset link
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req resetPasswordRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	writeNoContent(w, h.users.ResetPassword.Handle(r.Context(), command.ResetPassword{Token: req.Token, NewPassword: req.NewPassword}))
+}
+
+// device names the device a user logs in on, the user agent unless the client names it
+func device(r *http.Request, name string) string {
+	if name == "" {
+		return r.UserAgent()
+	}
+	return name
+}
+
+func decode(w http.ResponseWriter, r *http.Request, dest any) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(dest); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: custom_errors.ErrInvalidInput.Error()})
+		return false
+	}
+	return true
+}
+
+func writeToken(w http.ResponseWriter, token *auth.Token, err error) {
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	if token.MFAToken != "" {
+		writeJSON(w, http.StatusOK, mfaRequiredResponse{MFARequired: true, MFAToken: token.MFAToken, ExpiresAt: token.ExpiresAt})
+		return
+	}
+	writeJSON(w, http.StatusOK, tokenResponse{
+		AccessToken:           token.AccessToken,
+		TokenType:             token.TokenType,
+		ExpiresAt:             token.ExpiresAt,
+		RefreshToken:          token.RefreshToken,
+		RefreshTokenExpiresAt: token.RefreshTokenExpiresAt,
+	})
+}
+
+func writeNoContent(w http.ResponseWriter, err error) {
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, auth.ErrInvalidCredentials),
+		errors.Is(err, sessions.ErrInvalidRefreshToken),
+		errors.Is(err, sessions.ErrRefreshTokenReused),
+		errors.Is(err, mfa.ErrInvalidCode),
+		errors.Is(err, rbac.ErrUnauthorized):
+		writeJSON(w, http.StatusUnauthorized, errorResponse{Error: err.Error()})
+	case errors.Is(err, onetime.ErrInvalidToken),
+		errors.Is(err, onetime.ErrTokenUsed),
+		errors.Is(err, domain.ErrPasswordTooShort),
+		errors.Is(err, domain.ErrPasswordTooLong):
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+	case errors.Is(err, domain.ErrEmailAlreadyVerified):
+		writeJSON(w, http.StatusConflict, errorResponse{Error: err.Error()})
+	default:
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: custom_errors.ErrInternalServerError.Error()})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,264 @@
+package rest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iammrsea/social-app/internal/shared/auth"
+	"github.com/iammrsea/social-app/internal/shared/auth/mfa"
+	"github.com/iammrsea/social-app/internal/shared/auth/onetime"
+	"github.com/iammrsea/social-app/internal/shared/guards/rbac"
+	service "github.com/iammrsea/social-app/internal/user/app"
+	"github.com/iammrsea/social-app/internal/user/app/command"
+	"github.com/iammrsea/social-app/internal/user/domain"
+	"github.com/iammrsea/social-app/internal/user/ports/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type loginStub struct {
+	token *auth.Token
+	err   error
+	got   command.Login
+}
+
+func (l *loginStub) Handle(ctx context.Context, cmd command.Login) (*auth.Token, error) {
+	l.got = cmd
+	return l.token, l.err
+}
+
+func TestLogin(t *testing.T) {
+	t.Parallel()
+	expiresAt := time.Date(2025, 1, 1, 0, 15, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		body       string
+		login      *loginStub
+		wantStatus int
+	}{
+		{
+			name:       "should return an access token for valid credentials",
+			body:       `{"email":"johndoe@example.com","password":"s3cret-password"}`,
+			login:      &loginStub{token: &auth.Token{AccessToken: "token", TokenType: auth.BearerTokenType, ExpiresAt: expiresAt}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "should return unauthorized for invalid credentials",
+			body:       `{"email":"johndoe@example.com","password":"wrong"}`,
+			login:      &loginStub{err: auth.ErrInvalidCredentials},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "should return bad request for a malformed body",
+			body:       `{"email":`,
+			login:      &loginStub{},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "should hide other errors",
+			body:       `{"email":"johndoe@example.com","password":"s3cret-password"}`,
+			login:      &loginStub{err: assert.AnError},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(tc.body))
+			req.Header.Set("User-Agent", "test-agent")
+			rec := httptest.NewRecorder()
+
+			users := &service.Application{CommandHandler: service.CommandHandler{Login: tc.login}}
+			rest.NewAuthHandler(users).Routes().ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			if tc.wantStatus == http.StatusOK {
+				assert.Equal(t, command.Login{Email: "johndoe@example.com", Password: "s3cret-password", Device: "test-agent"}, tc.login.got)
+				assert.Equal(t, "token", body["accessToken"])
+				assert.Equal(t, "Bearer", body["tokenType"])
+				assert.Equal(t, expiresAt.Format(time.RFC3339), body["expiresAt"])
+			} else {
+				assert.NotEmpty(t, body["error"])
+				assert.NotContains(t, body["error"], assert.AnError.Error())
+			}
+		})
+	}
+}
+
+func TestLoginWithMFA(t *testing.T) {
+	t.Parallel()
+	expiresAt := time.Date(2025, 1, 1, 0, 5, 0, 0, time.UTC)
+	login := &loginStub{token: &auth.Token{MFAToken: "mfa-token", ExpiresAt: expiresAt}}
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email":"johndoe@example.com","password":"s3cret-password"}`))
+	rec := httptest.NewRecorder()
+
+	users := &service.Application{CommandHandler: service.CommandHandler{Login: login}}
+	rest.NewAuthHandler(users).Routes().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, true, body["mfaRequired"])
+	assert.Equal(t, "mfa-token", body["mfaToken"])
+	assert.NotContains(t, body, "accessToken")
+}
+
+type completeLoginStub struct {
+	token *auth.Token
+	err   error
+	got   command.CompleteLogin
+}
+
+func (c *completeLoginStub) Handle(ctx context.Context, cmd command.CompleteLogin) (*auth.Token, error) {
+	c.got = cmd
+	return c.token, c.err
+}
+
+func TestCompleteLogin(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name       string
+		stub       *completeLoginStub
+		wantStatus int
+	}{
+		{
+			name:       "should return an access token for a valid code",
+			stub:       &completeLoginStub{token: &auth.Token{AccessToken: "token", TokenType: auth.BearerTokenType}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "should return unauthorized for a wrong code",
+			stub:       &completeLoginStub{err: mfa.ErrInvalidCode},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodPost, "/login/mfa", strings.NewReader(`{"mfaToken":"mfa-token","code":"123456"}`))
+			req.Header.Set("User-Agent", "test-agent")
+			rec := httptest.NewRecorder()
+
+			users := &service.Application{CommandHandler: service.CommandHandler{CompleteLogin: tc.stub}}
+			rest.NewAuthHandler(users).Routes().ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			assert.Equal(t, command.CompleteLogin{MFAToken: "mfa-token", Code: "123456", Device: "test-agent"}, tc.stub.got)
+		})
+	}
+}
+
+type logoutStub struct {
+	err error
+}
+
+func (l *logoutStub) Handle(ctx context.Context, cmd command.Logout) error {
+	return l.err
+}
+
+func TestLogout(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "should end the session", wantStatus: http.StatusNoContent},
+		{name: "should refuse requests without a session", err: rbac.ErrUnauthorized, wantStatus: http.StatusUnauthorized},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+			rec := httptest.NewRecorder()
+
+			users := &service.Application{CommandHandler: service.CommandHandler{Logout: &logoutStub{err: tc.err}}}
+			rest.NewAuthHandler(users).Routes().ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+		})
+	}
+}
+
+type verifyEmailStub struct {
+	err error
+	got command.VerifyEmail
+}
+
+func (v *verifyEmailStub) Handle(ctx context.Context, cmd command.VerifyEmail) error {
+	v.got = cmd
+	return v.err
+}
+
+func TestVerifyEmail(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "should verify the email", wantStatus: http.StatusNoContent},
+		{name: "should refuse an invalid token", err: onetime.ErrInvalidToken, wantStatus: http.StatusBadRequest},
+		{name: "should refuse a used token", err: onetime.ErrTokenUsed, wantStatus: http.StatusBadRequest},
+		{name: "should report an email that is already verified", err: domain.ErrEmailAlreadyVerified, wantStatus: http.StatusConflict},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodGet, "/verify-email?token=abc", nil)
+			rec := httptest.NewRecorder()
+
+			stub := &verifyEmailStub{err: tc.err}
+			users := &service.Application{CommandHandler: service.CommandHandler{VerifyEmail: stub}}
+			rest.NewAuthHandler(users).Routes().ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			assert.Equal(t, "abc", stub.got.Token)
+		})
+	}
+}
+
+type resetPasswordStub struct {
+	err error
+	got command.ResetPassword
+}
+
+func (r *resetPasswordStub) Handle(ctx context.Context, cmd command.ResetPassword) error {
+	r.got = cmd
+	return r.err
+}
+
+func TestResetPassword(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{name: "should reset the password", body: `{"token":"abc","newPassword":"n3w-password"}`, wantStatus: http.StatusNoContent},
+		{name: "should refuse a short password", body: `{"token":"abc","newPassword":"short"}`, err: domain.ErrPasswordTooShort, wantStatus: http.StatusBadRequest},
+		{name: "should refuse an invalid token", body: `{"token":"abc","newPassword":"n3w-password"}`, err: onetime.ErrInvalidToken, wantStatus: http.StatusBadRequest},
+		{name: "should return bad request for a malformed body", body: `{"token":`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodPost, "/password-reset/confirm", strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+
+			users := &service.Application{CommandHandler: service.CommandHandler{ResetPassword: &resetPasswordStub{err: tc.err}}}
+			rest.NewAuthHandler(users).Routes().ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+		})
+	}
+}